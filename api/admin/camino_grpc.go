@@ -0,0 +1,463 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	adminv1 "github.com/ava-labs/avalanchego/proto/pb/admin/v1"
+)
+
+var _ adminv1.AdminServer = (*grpcService)(nil)
+
+// grpcService serves the admin API over gRPC
+type grpcService struct {
+	adminv1.UnsafeAdminServer
+	admin *Admin
+}
+
+// NewGRPCService returns a handler that serves the admin API as the gRPC
+// service admin.v1.Admin. Like the JSON-RPC API, calls are refused unless the
+// request carries the secret of [config].
+// All of the fields in [config] must be set.
+func NewGRPCService(config Config) *common.HTTPHandler {
+	service := &grpcService{
+		admin: newAdmin(config),
+	}
+	server := grpc.NewServer(grpc.UnaryInterceptor(service.validateRequest))
+	adminv1.RegisterAdminServer(server, service)
+	return &common.HTTPHandler{
+		LockOptions: common.WriteLock,
+		Handler:     server,
+	}
+}
+
+// validateRequest refuses calls whose request doesn't carry the admin secret,
+// the same way as the JSON-RPC API does.
+func (s *grpcService) validateRequest(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := s.admin.ValidateRequest(nil, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *grpcService) StartCPUProfiler(_ context.Context, req *adminv1.StartCPUProfilerRequest) (*adminv1.StartCPUProfilerResponse, error) {
+	if err := s.admin.StartCPUProfiler(nil, &Secret{Secret: req.Secret}, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.StartCPUProfilerResponse{}, nil
+}
+
+func (s *grpcService) StopCPUProfiler(_ context.Context, req *adminv1.StopCPUProfilerRequest) (*adminv1.StopCPUProfilerResponse, error) {
+	if err := s.admin.StopCPUProfiler(nil, &Secret{Secret: req.Secret}, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.StopCPUProfilerResponse{}, nil
+}
+
+func (s *grpcService) MemoryProfile(_ context.Context, req *adminv1.MemoryProfileRequest) (*adminv1.MemoryProfileResponse, error) {
+	if err := s.admin.MemoryProfile(nil, &Secret{Secret: req.Secret}, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.MemoryProfileResponse{}, nil
+}
+
+func (s *grpcService) LockProfile(_ context.Context, req *adminv1.LockProfileRequest) (*adminv1.LockProfileResponse, error) {
+	if err := s.admin.LockProfile(nil, &Secret{Secret: req.Secret}, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.LockProfileResponse{}, nil
+}
+
+func (s *grpcService) Alias(_ context.Context, req *adminv1.AliasRequest) (*adminv1.AliasResponse, error) {
+	args := AliasArgs{
+		Secret:   Secret{Secret: req.Secret},
+		Endpoint: req.Endpoint,
+		Alias:    req.Alias,
+	}
+	if err := s.admin.Alias(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.AliasResponse{}, nil
+}
+
+func (s *grpcService) AliasChain(_ context.Context, req *adminv1.AliasChainRequest) (*adminv1.AliasChainResponse, error) {
+	args := AliasChainArgs{
+		Secret: Secret{Secret: req.Secret},
+		Chain:  req.Chain,
+		Alias:  req.Alias,
+	}
+	if err := s.admin.AliasChain(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.AliasChainResponse{}, nil
+}
+
+func (s *grpcService) GetChainAliases(_ context.Context, req *adminv1.GetChainAliasesRequest) (*adminv1.GetChainAliasesResponse, error) {
+	args := GetChainAliasesArgs{
+		Secret: Secret{Secret: req.Secret},
+		Chain:  req.Chain,
+	}
+	reply := GetChainAliasesReply{}
+	if err := s.admin.GetChainAliases(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	return &adminv1.GetChainAliasesResponse{
+		Aliases: reply.Aliases,
+	}, nil
+}
+
+func (s *grpcService) Stacktrace(_ context.Context, req *adminv1.StacktraceRequest) (*adminv1.StacktraceResponse, error) {
+	if err := s.admin.Stacktrace(nil, &Secret{Secret: req.Secret}, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.StacktraceResponse{}, nil
+}
+
+func (s *grpcService) SetLoggerLevel(_ context.Context, req *adminv1.SetLoggerLevelRequest) (*adminv1.SetLoggerLevelResponse, error) {
+	args := SetLoggerLevelArgs{
+		Secret:     Secret{Secret: req.Secret},
+		LoggerName: req.LoggerName,
+	}
+	if req.LogLevel != "" {
+		logLevel, err := logging.ToLevel(req.LogLevel)
+		if err != nil {
+			return nil, err
+		}
+		args.LogLevel = &logLevel
+	}
+	if req.DisplayLevel != "" {
+		displayLevel, err := logging.ToLevel(req.DisplayLevel)
+		if err != nil {
+			return nil, err
+		}
+		args.DisplayLevel = &displayLevel
+	}
+	if err := s.admin.SetLoggerLevel(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.SetLoggerLevelResponse{}, nil
+}
+
+func (s *grpcService) GetLoggerLevel(_ context.Context, req *adminv1.GetLoggerLevelRequest) (*adminv1.GetLoggerLevelResponse, error) {
+	args := GetLoggerLevelArgs{
+		Secret:     Secret{Secret: req.Secret},
+		LoggerName: req.LoggerName,
+	}
+	reply := GetLoggerLevelReply{}
+	if err := s.admin.GetLoggerLevel(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	loggerLevels := make(map[string]*adminv1.LoggerLevels, len(reply.LoggerLevels))
+	for name, levels := range reply.LoggerLevels {
+		loggerLevels[name] = &adminv1.LoggerLevels{
+			LogLevel:     levels.LogLevel.String(),
+			DisplayLevel: levels.DisplayLevel.String(),
+		}
+	}
+	return &adminv1.GetLoggerLevelResponse{
+		LoggerLevels: loggerLevels,
+	}, nil
+}
+
+func (s *grpcService) GetConfig(_ context.Context, req *adminv1.GetConfigRequest) (*adminv1.GetConfigResponse, error) {
+	var reply interface{}
+	if err := s.admin.GetConfig(nil, &Secret{Secret: req.Secret}, &reply); err != nil {
+		return nil, err
+	}
+	config, err := stdjson.Marshal(reply)
+	if err != nil {
+		return nil, err
+	}
+	return &adminv1.GetConfigResponse{
+		Config: config,
+	}, nil
+}
+
+func (s *grpcService) LoadVMs(ctx context.Context, req *adminv1.LoadVMsRequest) (*adminv1.LoadVMsResponse, error) {
+	reply := LoadVMsReply{}
+	if err := s.admin.LoadVMs(requestWithContext(ctx), &Secret{Secret: req.Secret}, &reply); err != nil {
+		return nil, err
+	}
+	response := &adminv1.LoadVMsResponse{
+		NewVms:    make(map[string]*adminv1.VMAliases, len(reply.NewVMs)),
+		FailedVms: make(map[string]string, len(reply.FailedVMs)),
+	}
+	for vmID, aliases := range reply.NewVMs {
+		response.NewVms[vmID.String()] = &adminv1.VMAliases{
+			Aliases: aliases,
+		}
+	}
+	for vmID, err := range reply.FailedVMs {
+		response.FailedVms[vmID.String()] = err
+	}
+	return response, nil
+}
+
+func (s *grpcService) ExportSnapshot(ctx context.Context, req *adminv1.ExportSnapshotRequest) (*adminv1.ExportSnapshotResponse, error) {
+	args := ExportSnapshotArgs{
+		Secret: Secret{Secret: req.Secret},
+		Path:   req.Path,
+		Chain:  req.Chain,
+		Height: json.Uint64(req.Height),
+	}
+	reply := ExportSnapshotReply{}
+	if err := s.admin.ExportSnapshot(requestWithContext(ctx), &args, &reply); err != nil {
+		return nil, err
+	}
+	response := &adminv1.ExportSnapshotResponse{
+		Path:      reply.Path,
+		NumKeys:   uint64(reply.NumKeys),
+		Checksum:  reply.Checksum.String(),
+		Timestamp: timestamppb.New(time.Unix(int64(reply.Timestamp), 0)),
+		Chains:    make([]*adminv1.ExportedChain, len(reply.Chains)),
+	}
+	for i, chain := range reply.Chains {
+		response.Chains[i] = &adminv1.ExportedChain{
+			ChainId:      chain.ChainID.String(),
+			LastAccepted: chain.LastAccepted.String(),
+			Height:       uint64(chain.Height),
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcService) BanPeer(_ context.Context, req *adminv1.BanPeerRequest) (*adminv1.BanPeerResponse, error) {
+	args := BanPeerArgs{
+		Secret:   Secret{Secret: req.Secret},
+		IP:       req.Ip,
+		Reason:   req.Reason,
+		Duration: json.Uint64(req.Duration.AsDuration() / time.Second),
+	}
+	if req.NodeId != "" {
+		nodeID, err := ids.NodeIDFromString(req.NodeId)
+		if err != nil {
+			return nil, err
+		}
+		args.NodeID = &nodeID
+	}
+	if err := s.admin.BanPeer(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.BanPeerResponse{}, nil
+}
+
+func (s *grpcService) UnbanPeer(_ context.Context, req *adminv1.UnbanPeerRequest) (*adminv1.UnbanPeerResponse, error) {
+	args := UnbanPeerArgs{
+		Secret: Secret{Secret: req.Secret},
+		IP:     req.Ip,
+	}
+	if req.NodeId != "" {
+		nodeID, err := ids.NodeIDFromString(req.NodeId)
+		if err != nil {
+			return nil, err
+		}
+		args.NodeID = &nodeID
+	}
+	if err := s.admin.UnbanPeer(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.UnbanPeerResponse{}, nil
+}
+
+func (s *grpcService) DisconnectPeer(_ context.Context, req *adminv1.DisconnectPeerRequest) (*adminv1.DisconnectPeerResponse, error) {
+	nodeID, err := ids.NodeIDFromString(req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+	args := DisconnectPeerArgs{
+		Secret: Secret{Secret: req.Secret},
+		NodeID: nodeID,
+	}
+	if err := s.admin.DisconnectPeer(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.DisconnectPeerResponse{}, nil
+}
+
+func (s *grpcService) AddPersistentPeer(_ context.Context, req *adminv1.AddPersistentPeerRequest) (*adminv1.AddPersistentPeerResponse, error) {
+	nodeID, err := ids.NodeIDFromString(req.NodeId)
+	if err != nil {
+		return nil, err
+	}
+	args := AddPersistentPeerArgs{
+		Secret: Secret{Secret: req.Secret},
+		NodeID: nodeID,
+		IP:     req.Ip,
+	}
+	if err := s.admin.AddPersistentPeer(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.AddPersistentPeerResponse{}, nil
+}
+
+func (s *grpcService) GetConsensusState(_ context.Context, req *adminv1.GetConsensusStateRequest) (*adminv1.GetConsensusStateResponse, error) {
+	args := GetConsensusStateArgs{
+		Secret: Secret{Secret: req.Secret},
+		Chain:  req.Chain,
+	}
+	reply := smeng.ConsensusState{}
+	if err := s.admin.GetConsensusState(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	response := &adminv1.GetConsensusStateResponse{
+		LastAccepted: reply.LastAccepted.String(),
+		Preference:   reply.Preference.String(),
+		Blocks:       make([]*adminv1.BlockState, len(reply.Blocks)),
+		Polls:        make([]*adminv1.PollInfo, len(reply.Polls)),
+		NumPending:   int64(reply.NumPending),
+	}
+	for i, blk := range reply.Blocks {
+		response.Blocks[i] = &adminv1.BlockState{
+			Id:         blk.ID.String(),
+			ParentId:   blk.ParentID.String(),
+			Height:     uint64(blk.Height),
+			Accepted:   blk.Accepted,
+			Preferred:  blk.Preferred,
+			Confidence: int64(blk.Confidence),
+			Snowball:   blk.Snowball,
+		}
+	}
+	for i, poll := range reply.Polls {
+		polled := make(map[string]int64, len(poll.Polled))
+		for nodeID, count := range poll.Polled {
+			polled[nodeID.String()] = int64(count)
+		}
+		votes := make(map[string]string, len(poll.Votes))
+		for nodeID, blkID := range poll.Votes {
+			votes[nodeID.String()] = blkID.String()
+		}
+		dropped := make([]string, len(poll.Dropped))
+		for j, nodeID := range poll.Dropped {
+			dropped[j] = nodeID.String()
+		}
+		response.Polls[i] = &adminv1.PollInfo{
+			RequestId: poll.RequestID,
+			Start:     timestamppb.New(poll.Start),
+			Polled:    polled,
+			Votes:     votes,
+			Dropped:   dropped,
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetPreferenceChanges(_ context.Context, req *adminv1.GetPreferenceChangesRequest) (*adminv1.GetPreferenceChangesResponse, error) {
+	args := GetPreferenceChangesArgs{
+		Secret: Secret{Secret: req.Secret},
+		Chain:  req.Chain,
+		After:  json.Uint64(req.After),
+	}
+	reply := GetPreferenceChangesReply{}
+	if err := s.admin.GetPreferenceChanges(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	response := &adminv1.GetPreferenceChangesResponse{
+		Changes: make([]*adminv1.PreferenceChange, len(reply.Changes)),
+	}
+	for i, change := range reply.Changes {
+		response.Changes[i] = &adminv1.PreferenceChange{
+			Sequence:     uint64(change.Sequence),
+			Time:         timestamppb.New(change.Time),
+			Previous:     change.Previous.String(),
+			Preference:   change.Preference.String(),
+			LastAccepted: change.LastAccepted.String(),
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcService) ProposeConsensusParameters(_ context.Context, req *adminv1.ProposeConsensusParametersRequest) (*adminv1.ProposeConsensusParametersResponse, error) {
+	subnetID, err := ids.FromString(req.SubnetId)
+	if err != nil {
+		return nil, err
+	}
+	params := req.Parameters
+	args := ProposeConsensusParametersArgs{
+		Secret:   Secret{Secret: req.Secret},
+		SubnetID: subnetID,
+		Parameters: snowball.Parameters{
+			K:                       int(params.GetK()),
+			Alpha:                   int(params.GetAlpha()),
+			BetaVirtuous:            int(params.GetBetaVirtuous()),
+			BetaRogue:               int(params.GetBetaRogue()),
+			ConcurrentRepolls:       int(params.GetConcurrentRepolls()),
+			OptimalProcessing:       int(params.GetOptimalProcessing()),
+			MaxOutstandingItems:     int(params.GetMaxOutstandingItems()),
+			MaxItemProcessingTime:   params.GetMaxItemProcessingTime().AsDuration(),
+			MixedQueryNumPushVdr:    int(params.GetMixedQueryNumPushVdr()),
+			MixedQueryNumPushNonVdr: int(params.GetMixedQueryNumPushNonVdr()),
+		},
+		DryRun: req.DryRun,
+	}
+	reply := ProposeConsensusParametersReply{}
+	if err := s.admin.ProposeConsensusParameters(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	response := &adminv1.ProposeConsensusParametersResponse{}
+	if estimate := reply.Estimate; estimate != nil {
+		response.Estimate = &adminv1.FinalityEstimate{
+			NumResponses:      int64(estimate.NumResponses),
+			DropRate:          estimate.DropRate,
+			ExpectedResponses: estimate.ExpectedResponses,
+			Finalizes:         estimate.Finalizes,
+			PollLatency:       durationpb.New(estimate.PollLatency),
+			FinalityLatency:   durationpb.New(estimate.FinalityLatency),
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcService) ClearConsensusParameters(_ context.Context, req *adminv1.ClearConsensusParametersRequest) (*adminv1.ClearConsensusParametersResponse, error) {
+	subnetID, err := ids.FromString(req.SubnetId)
+	if err != nil {
+		return nil, err
+	}
+	args := ClearConsensusParametersArgs{
+		Secret:   Secret{Secret: req.Secret},
+		SubnetID: subnetID,
+	}
+	if err := s.admin.ClearConsensusParameters(nil, &args, &api.EmptyReply{}); err != nil {
+		return nil, err
+	}
+	return &adminv1.ClearConsensusParametersResponse{}, nil
+}
+
+func (s *grpcService) GetNodeSigner(_ context.Context, req *adminv1.GetNodeSignerRequest) (*adminv1.GetNodeSignerResponse, error) {
+	reply := GetNodeSignerReply{}
+	if err := s.admin.GetNodeSigner(nil, &Secret{Secret: req.Secret}, &reply); err != nil {
+		return nil, err
+	}
+	return &adminv1.GetNodeSignerResponse{
+		PrivateKey: reply.PrivateKey,
+		PublicKey:  reply.PublicKey,
+	}, nil
+}
+
+// requestWithContext returns the request passed to the JSON-RPC methods that
+// use the context of the call
+func requestWithContext(ctx context.Context) *http.Request {
+	return (&http.Request{}).WithContext(ctx)
+}
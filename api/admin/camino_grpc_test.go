@@ -0,0 +1,51 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	adminv1 "github.com/ava-labs/avalanchego/proto/pb/admin/v1"
+)
+
+func TestGRPCServiceRequiresSecret(t *testing.T) {
+	require := require.New(t)
+
+	handler := NewGRPCService(Config{
+		Secret:     "secret",
+		Log:        logging.NoLog{},
+		NodeConfig: map[string]int{"networkID": 1},
+	})
+	server, ok := handler.Handler.(*grpc.Server)
+	require.True(ok)
+	defer server.Stop()
+
+	listener, err := grpcutils.NewListener()
+	require.NoError(err)
+	go grpcutils.Serve(listener, server)
+
+	conn, err := grpcutils.Dial(listener.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+	client := adminv1.NewAdminClient(conn)
+
+	ctx := context.Background()
+	response, err := client.GetConfig(ctx, &adminv1.GetConfigRequest{Secret: "secret"})
+	require.NoError(err)
+	require.JSONEq(`{"networkID":1}`, string(response.Config))
+
+	_, err = client.GetConfig(ctx, &adminv1.GetConfigRequest{Secret: "wrong"})
+	require.ErrorContains(err, errWrongSecret.Error())
+
+	_, err = client.GetConfig(ctx, &adminv1.GetConfigRequest{})
+	require.ErrorContains(err, errWrongSecret.Error())
+}
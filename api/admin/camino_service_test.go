@@ -0,0 +1,89 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openrpc"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestDiscoverRequiresSecret(t *testing.T) {
+	handler, err := NewService(Config{Secret: "secret"})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		params         string
+		expectedResult bool
+	}{
+		"Right secret": {
+			params:         `{"secret":"secret"}`,
+			expectedResult: true,
+		},
+		"Wrong secret": {
+			params: `{"secret":"wrong"}`,
+		},
+		"No secret": {
+			params: `{}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			request := httptest.NewRequest(
+				http.MethodPost,
+				"/",
+				strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"`+openrpc.DiscoverMethod+`","params":`+tt.params+`}`),
+			)
+			request.Header.Set("Content-Type", "application/json")
+			recorder := httptest.NewRecorder()
+			handler.Handler.ServeHTTP(recorder, request)
+
+			body := recorder.Body.String()
+			require.Equal(tt.expectedResult, strings.Contains(body, `"admin.loadVMs"`), body)
+		})
+	}
+}
+
+func TestBanPeerArgs(t *testing.T) {
+	nodeID := ids.GenerateTestNodeID()
+	tests := map[string]struct {
+		args        BanPeerArgs
+		expectedErr error
+	}{
+		"No target": {
+			args:        BanPeerArgs{},
+			expectedErr: errNoBanTarget,
+		},
+		"Node ID and IP": {
+			args: BanPeerArgs{
+				NodeID: &nodeID,
+				IP:     "127.0.0.1",
+			},
+			expectedErr: errNoBanTarget,
+		},
+		"Invalid IP": {
+			args: BanPeerArgs{
+				IP: "127.0.0.1:9651",
+			},
+			expectedErr: errInvalidIP,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			admin := &Admin{Config: Config{Log: logging.NoLog{}}}
+			err := admin.BanPeer(nil, &tt.args, &api.EmptyReply{})
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
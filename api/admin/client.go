@@ -16,9 +16,13 @@ package admin
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
@@ -40,6 +44,15 @@ type Client interface {
 	GetLoggerLevel(ctx context.Context, loggerName string, options ...rpc.Option) (map[string]LogAndDisplayLevels, error)
 	GetConfig(ctx context.Context, options ...rpc.Option) (interface{}, error)
 	GetNodeSigner(ctx context.Context, _ string, options ...rpc.Option) (*GetNodeSignerReply, error)
+	ExportSnapshot(ctx context.Context, path string, chain string, height uint64, options ...rpc.Option) (*ExportSnapshotReply, error)
+	BanPeer(ctx context.Context, nodeID *ids.NodeID, ip string, reason string, duration time.Duration, options ...rpc.Option) error
+	UnbanPeer(ctx context.Context, nodeID *ids.NodeID, ip string, options ...rpc.Option) error
+	DisconnectPeer(ctx context.Context, nodeID ids.NodeID, options ...rpc.Option) error
+	AddPersistentPeer(ctx context.Context, nodeID ids.NodeID, ip string, options ...rpc.Option) error
+	GetConsensusState(ctx context.Context, chain string, options ...rpc.Option) (*smeng.ConsensusState, error)
+	GetPreferenceChanges(ctx context.Context, chain string, after uint64, options ...rpc.Option) ([]smeng.PreferenceChange, error)
+	ProposeConsensusParameters(ctx context.Context, subnetID ids.ID, params snowball.Parameters, dryRun bool, options ...rpc.Option) (*smeng.FinalityEstimate, error)
+	ClearConsensusParameters(ctx context.Context, subnetID ids.ID, options ...rpc.Option) error
 }
 
 // Client implementation for the Avalanche Platform Info API Endpoint
@@ -162,3 +175,97 @@ func (c *client) GetNodeSigner(ctx context.Context, _ string, options ...rpc.Opt
 	err := c.requester.SendRequest(ctx, "getNodeSigner", Secret{c.secret}, res, options...)
 	return res, err
 }
+
+func (c *client) ExportSnapshot(ctx context.Context, path string, chain string, height uint64, options ...rpc.Option) (*ExportSnapshotReply, error) {
+	res := &ExportSnapshotReply{}
+	err := c.requester.SendRequest(ctx, "admin.exportSnapshot", &ExportSnapshotArgs{
+		Secret: Secret{c.secret},
+		Path:   path,
+		Chain:  chain,
+		Height: json.Uint64(height),
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) BanPeer(
+	ctx context.Context,
+	nodeID *ids.NodeID,
+	ip string,
+	reason string,
+	duration time.Duration,
+	options ...rpc.Option,
+) error {
+	return c.requester.SendRequest(ctx, "admin.banPeer", &BanPeerArgs{
+		Secret:   Secret{c.secret},
+		NodeID:   nodeID,
+		IP:       ip,
+		Reason:   reason,
+		Duration: json.Uint64(duration / time.Second),
+	}, &api.EmptyReply{}, options...)
+}
+
+func (c *client) UnbanPeer(ctx context.Context, nodeID *ids.NodeID, ip string, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.unbanPeer", &UnbanPeerArgs{
+		Secret: Secret{c.secret},
+		NodeID: nodeID,
+		IP:     ip,
+	}, &api.EmptyReply{}, options...)
+}
+
+func (c *client) DisconnectPeer(ctx context.Context, nodeID ids.NodeID, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.disconnectPeer", &DisconnectPeerArgs{
+		Secret: Secret{c.secret},
+		NodeID: nodeID,
+	}, &api.EmptyReply{}, options...)
+}
+
+func (c *client) AddPersistentPeer(ctx context.Context, nodeID ids.NodeID, ip string, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.addPersistentPeer", &AddPersistentPeerArgs{
+		Secret: Secret{c.secret},
+		NodeID: nodeID,
+		IP:     ip,
+	}, &api.EmptyReply{}, options...)
+}
+
+func (c *client) GetConsensusState(ctx context.Context, chain string, options ...rpc.Option) (*smeng.ConsensusState, error) {
+	res := &smeng.ConsensusState{}
+	err := c.requester.SendRequest(ctx, "admin.getConsensusState", &GetConsensusStateArgs{
+		Secret: Secret{c.secret},
+		Chain:  chain,
+	}, res, options...)
+	return res, err
+}
+
+func (c *client) GetPreferenceChanges(ctx context.Context, chain string, after uint64, options ...rpc.Option) ([]smeng.PreferenceChange, error) {
+	res := &GetPreferenceChangesReply{}
+	err := c.requester.SendRequest(ctx, "admin.getPreferenceChanges", &GetPreferenceChangesArgs{
+		Secret: Secret{c.secret},
+		Chain:  chain,
+		After:  json.Uint64(after),
+	}, res, options...)
+	return res.Changes, err
+}
+
+func (c *client) ProposeConsensusParameters(
+	ctx context.Context,
+	subnetID ids.ID,
+	params snowball.Parameters,
+	dryRun bool,
+	options ...rpc.Option,
+) (*smeng.FinalityEstimate, error) {
+	res := &ProposeConsensusParametersReply{}
+	err := c.requester.SendRequest(ctx, "admin.proposeConsensusParameters", &ProposeConsensusParametersArgs{
+		Secret:     Secret{c.secret},
+		SubnetID:   subnetID,
+		Parameters: params,
+		DryRun:     dryRun,
+	}, res, options...)
+	return res.Estimate, err
+}
+
+func (c *client) ClearConsensusParameters(ctx context.Context, subnetID ids.ID, options ...rpc.Option) error {
+	return c.requester.SendRequest(ctx, "admin.clearConsensusParameters", &ClearConsensusParametersArgs{
+		Secret:   Secret{c.secret},
+		SubnetID: subnetID,
+	}, &api.EmptyReply{}, options...)
+}
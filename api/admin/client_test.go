@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -7,11 +17,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
@@ -66,6 +79,18 @@ func (mc *mockClient) SendRequest(_ context.Context, _ string, _ interface{}, re
 	case *GetLoggerLevelReply:
 		response := mc.response.(*GetLoggerLevelReply)
 		*p = *response
+	case *ExportSnapshotReply:
+		response := mc.response.(*ExportSnapshotReply)
+		*p = *response
+	case *smeng.ConsensusState:
+		response := mc.response.(*smeng.ConsensusState)
+		*p = *response
+	case *GetPreferenceChangesReply:
+		response := mc.response.(*GetPreferenceChangesReply)
+		*p = *response
+	case *ProposeConsensusParametersReply:
+		response := mc.response.(*ProposeConsensusParametersReply)
+		*p = *response
 	case *interface{}:
 		response := mc.response.(*interface{})
 		*p = *response
@@ -387,3 +412,119 @@ func TestGetConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestExportSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	expectedReply := &ExportSnapshotReply{
+		Path:     "snapshot.bin",
+		NumKeys:  3,
+		Checksum: ids.GenerateTestID(),
+		Chains: []ExportedChainReply{
+			{
+				ChainID:      ids.GenerateTestID(),
+				LastAccepted: ids.GenerateTestID(),
+				Height:       5,
+			},
+		},
+	}
+	mockClient := client{requester: NewMockClient(expectedReply, nil)}
+	reply, err := mockClient.ExportSnapshot(context.Background(), "snapshot.bin", "P", 5)
+	require.NoError(err)
+	require.Equal(expectedReply, reply)
+
+	mockClient = client{requester: NewMockClient(nil, errTest)}
+	_, err = mockClient.ExportSnapshot(context.Background(), "snapshot.bin", "", 0)
+	require.ErrorIs(err, errTest)
+}
+
+func TestBanPeer(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	mockClient := client{requester: NewMockClient(&api.EmptyReply{}, nil)}
+	require.NoError(mockClient.BanPeer(context.Background(), &nodeID, "", "spam", time.Hour))
+
+	mockClient = client{requester: NewMockClient(nil, errTest)}
+	err := mockClient.BanPeer(context.Background(), &nodeID, "", "spam", time.Hour)
+	require.ErrorIs(err, errTest)
+}
+
+func TestGetConsensusState(t *testing.T) {
+	require := require.New(t)
+
+	expectedState := &smeng.ConsensusState{
+		LastAccepted: ids.GenerateTestID(),
+		Preference:   ids.GenerateTestID(),
+	}
+	mockClient := client{requester: NewMockClient(expectedState, nil)}
+	state, err := mockClient.GetConsensusState(context.Background(), "C")
+	require.NoError(err)
+	require.Equal(expectedState, state)
+
+	mockClient = client{requester: NewMockClient(nil, errTest)}
+	_, err = mockClient.GetConsensusState(context.Background(), "C")
+	require.ErrorIs(err, errTest)
+}
+
+func TestGetPreferenceChanges(t *testing.T) {
+	require := require.New(t)
+
+	expectedChanges := []smeng.PreferenceChange{{
+		Sequence:   2,
+		Previous:   ids.GenerateTestID(),
+		Preference: ids.GenerateTestID(),
+	}}
+	mockClient := client{requester: NewMockClient(&GetPreferenceChangesReply{
+		Changes: expectedChanges,
+	}, nil)}
+	changes, err := mockClient.GetPreferenceChanges(context.Background(), "C", 1)
+	require.NoError(err)
+	require.Equal(expectedChanges, changes)
+
+	mockClient = client{requester: NewMockClient(nil, errTest)}
+	_, err = mockClient.GetPreferenceChanges(context.Background(), "C", 1)
+	require.ErrorIs(err, errTest)
+}
+
+func TestProposeConsensusParameters(t *testing.T) {
+	require := require.New(t)
+
+	expectedEstimate := &smeng.FinalityEstimate{
+		NumResponses:    10,
+		Finalizes:       true,
+		PollLatency:     time.Second,
+		FinalityLatency: 2 * time.Second,
+	}
+	params := snowball.Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: time.Second,
+	}
+	mockClient := client{requester: NewMockClient(&ProposeConsensusParametersReply{
+		Estimate: expectedEstimate,
+	}, nil)}
+	estimate, err := mockClient.ProposeConsensusParameters(context.Background(), ids.GenerateTestID(), params, true)
+	require.NoError(err)
+	require.Equal(expectedEstimate, estimate)
+
+	mockClient = client{requester: NewMockClient(nil, errTest)}
+	_, err = mockClient.ProposeConsensusParameters(context.Background(), ids.GenerateTestID(), params, false)
+	require.ErrorIs(err, errTest)
+}
+
+func TestClearConsensusParameters(t *testing.T) {
+	require := require.New(t)
+
+	mockClient := client{requester: NewMockClient(&api.EmptyReply{}, nil)}
+	require.NoError(mockClient.ClearConsensusParameters(context.Background(), ids.GenerateTestID()))
+
+	mockClient = client{requester: NewMockClient(nil, errTest)}
+	err := mockClient.ClearConsensusParameters(context.Background(), ids.GenerateTestID())
+	require.ErrorIs(err, errTest)
+}
@@ -17,22 +17,31 @@ import (
 	"crypto/rsa"
 	"crypto/tls"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"path"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snapshot"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/cb58"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/crypto/secp256k1"
 	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/ips"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/perms"
@@ -49,8 +58,12 @@ const (
 )
 
 var (
-	errAliasTooLong = errors.New("alias length is too long")
-	errNoLogLevel   = errors.New("need to specify either displayLevel or logLevel")
+	errAliasTooLong   = errors.New("alias length is too long")
+	errNoLogLevel     = errors.New("need to specify either displayLevel or logLevel")
+	errNoSnapshotPath = errors.New("need to specify a snapshot path")
+	errNoBanTarget    = errors.New("need to specify either nodeID or ip")
+	errInvalidIP      = errors.New("invalid IP")
+	errWrongSecret    = errors.New("secret arg missing or wrong")
 )
 
 type Config struct {
@@ -63,6 +76,8 @@ type Config struct {
 	HTTPServer   server.PathAdderWithReadLock
 	VMRegistry   registry.VMRegistry
 	VMManager    vms.Manager
+	Snapshotter  snapshot.Snapshotter
+	Network      network.Network
 
 	StakingTLSCert tls.Certificate
 }
@@ -88,14 +103,11 @@ type ISecret interface {
 // NewService returns a new admin API service.
 // All of the fields in [config] must be set.
 func NewService(config Config) (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := openrpc.NewServer()
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	admin := &Admin{
-		Config:   config,
-		profiler: profiler.New(config.ProfileDir),
-	}
+	admin := newAdmin(config)
 	if err := newServer.RegisterService(admin, "admin"); err != nil {
 		return nil, err
 	}
@@ -103,9 +115,16 @@ func NewService(config Config) (*common.HTTPHandler, error) {
 	return &common.HTTPHandler{Handler: newServer}, nil
 }
 
+func newAdmin(config Config) *Admin {
+	return &Admin{
+		Config:   config,
+		profiler: profiler.New(config.ProfileDir),
+	}
+}
+
 func (a *Admin) ValidateRequest(_ *rpc.RequestInfo, i interface{}) error {
 	if secret, ok := i.(ISecret); !ok || secret.GetSecret() != a.Secret {
-		return errors.New("secret arg missing or wrong")
+		return errWrongSecret
 	}
 	return nil
 }
@@ -389,6 +408,323 @@ func (a *Admin) LoadVMs(r *http.Request, args *Secret, reply *LoadVMsReply) erro
 	return err
 }
 
+// ExportSnapshotArgs are the arguments for calling ExportSnapshot
+type ExportSnapshotArgs struct {
+	Secret
+	// Path of the snapshot file to create
+	Path string `json:"path"`
+	// Alias or ID of the linear chain, whose accepted [Height] the snapshot
+	// is taken at. If empty, the snapshot is taken at the current state.
+	Chain  string      `json:"chain"`
+	Height json.Uint64 `json:"height"`
+}
+
+// ExportSnapshotReply describes the exported snapshot
+type ExportSnapshotReply struct {
+	Path      string               `json:"path"`
+	NumKeys   json.Uint64          `json:"numKeys"`
+	Checksum  ids.ID               `json:"checksum"`
+	Timestamp json.Uint64          `json:"timestamp"`
+	Chains    []ExportedChainReply `json:"chains"`
+}
+
+// ExportedChainReply is the last accepted block of a chain in a snapshot
+type ExportedChainReply struct {
+	ChainID      ids.ID      `json:"chainID"`
+	LastAccepted ids.ID      `json:"lastAccepted"`
+	Height       json.Uint64 `json:"height"`
+}
+
+// ExportSnapshot writes a checksummed snapshot of the node's database to
+// [args.Path] while the node keeps running. If [args.Chain] is given, the
+// snapshot is taken once the chain accepted the block at [args.Height].
+// There is no API to restore a snapshot, as the database of a running node
+// can't be replaced. Snapshots are restored into the empty database of a
+// stopped node with tools/snapshot.
+func (a *Admin) ExportSnapshot(r *http.Request, args *ExportSnapshotArgs, reply *ExportSnapshotReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "exportSnapshot"),
+		logging.UserString("path", args.Path),
+		logging.UserString("chain", args.Chain),
+		zap.Uint64("height", uint64(args.Height)),
+	)
+
+	if args.Path == "" {
+		return errNoSnapshotPath
+	}
+
+	chainID := ids.Empty
+	if args.Chain != "" {
+		var err error
+		chainID, err = a.ChainManager.Lookup(args.Chain)
+		if err != nil {
+			return err
+		}
+	}
+
+	summary, err := a.Snapshotter.Export(r.Context(), args.Path, chainID, uint64(args.Height))
+	if err != nil {
+		return err
+	}
+
+	reply.Path = args.Path
+	reply.NumKeys = json.Uint64(summary.NumKeys)
+	reply.Checksum = summary.Checksum
+	reply.Timestamp = json.Uint64(summary.Timestamp.Unix())
+	reply.Chains = make([]ExportedChainReply, len(summary.Chains))
+	for i, head := range summary.Chains {
+		reply.Chains[i] = ExportedChainReply{
+			ChainID:      head.ChainID,
+			LastAccepted: head.LastAccepted,
+			Height:       json.Uint64(head.Height),
+		}
+	}
+	return nil
+}
+
+// BanPeerArgs are the arguments for calling BanPeer
+type BanPeerArgs struct {
+	Secret
+	// Exactly one of NodeID and IP must be specified
+	NodeID *ids.NodeID `json:"nodeID"`
+	IP     string      `json:"ip"`
+	Reason string      `json:"reason"`
+	// Duration of the ban in seconds. If 0, the ban never expires.
+	Duration json.Uint64 `json:"duration"`
+}
+
+// BanPeer disconnects the peers with the given node ID or IP and refuses
+// connections to them until the ban expires or is lifted by UnbanPeer.
+func (a *Admin) BanPeer(_ *http.Request, args *BanPeerArgs, _ *api.EmptyReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "banPeer"),
+		zap.Stringer("nodeID", args.NodeID),
+		logging.UserString("ip", args.IP),
+		logging.UserString("reason", args.Reason),
+		zap.Uint64("duration", uint64(args.Duration)),
+	)
+
+	var expiry *time.Time
+	if args.Duration != 0 {
+		banExpiry := time.Now().Add(time.Duration(args.Duration) * time.Second)
+		expiry = &banExpiry
+	}
+
+	switch {
+	case args.NodeID != nil && args.IP == "":
+		return a.Network.BanNode(*args.NodeID, args.Reason, expiry)
+	case args.NodeID == nil && args.IP != "":
+		ip, err := parseIP(args.IP)
+		if err != nil {
+			return err
+		}
+		return a.Network.BanIP(ip, args.Reason, expiry)
+	default:
+		return errNoBanTarget
+	}
+}
+
+// UnbanPeerArgs are the arguments for calling UnbanPeer
+type UnbanPeerArgs struct {
+	Secret
+	// Exactly one of NodeID and IP must be specified
+	NodeID *ids.NodeID `json:"nodeID"`
+	IP     string      `json:"ip"`
+}
+
+// UnbanPeer lifts the ban of the given node ID or IP
+func (a *Admin) UnbanPeer(_ *http.Request, args *UnbanPeerArgs, _ *api.EmptyReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "unbanPeer"),
+		zap.Stringer("nodeID", args.NodeID),
+		logging.UserString("ip", args.IP),
+	)
+
+	switch {
+	case args.NodeID != nil && args.IP == "":
+		return a.Network.UnbanNode(*args.NodeID)
+	case args.NodeID == nil && args.IP != "":
+		ip, err := parseIP(args.IP)
+		if err != nil {
+			return err
+		}
+		return a.Network.UnbanIP(ip)
+	default:
+		return errNoBanTarget
+	}
+}
+
+// DisconnectPeerArgs are the arguments for calling DisconnectPeer
+type DisconnectPeerArgs struct {
+	Secret
+	NodeID ids.NodeID `json:"nodeID"`
+}
+
+// DisconnectPeer closes the connection to the given peer. The node reconnects
+// to the peer if it is a validator or manually tracked.
+func (a *Admin) DisconnectPeer(_ *http.Request, args *DisconnectPeerArgs, _ *api.EmptyReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "disconnectPeer"),
+		zap.Stringer("nodeID", args.NodeID),
+	)
+
+	return a.Network.DisconnectPeer(args.NodeID)
+}
+
+// AddPersistentPeerArgs are the arguments for calling AddPersistentPeer
+type AddPersistentPeerArgs struct {
+	Secret
+	NodeID ids.NodeID `json:"nodeID"`
+	// IP and port the peer is reachable at, for example 127.0.0.1:9651
+	IP string `json:"ip"`
+}
+
+// AddPersistentPeer makes the node connect to the given peer and reconnect to
+// it whenever the connection is lost, including after a restart.
+func (a *Admin) AddPersistentPeer(_ *http.Request, args *AddPersistentPeerArgs, _ *api.EmptyReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "addPersistentPeer"),
+		zap.Stringer("nodeID", args.NodeID),
+		logging.UserString("ip", args.IP),
+	)
+
+	ip, err := ips.ToIPPort(args.IP)
+	if err != nil {
+		return fmt.Errorf("%w %q: %s", errInvalidIP, args.IP, err)
+	}
+	return a.Network.AddPersistentPeer(args.NodeID, ip)
+}
+
+func parseIP(ipStr string) (net.IP, error) {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return nil, fmt.Errorf("%w %q", errInvalidIP, ipStr)
+	}
+	return ip, nil
+}
+
+// GetConsensusStateArgs are the arguments for calling GetConsensusState
+type GetConsensusStateArgs struct {
+	Secret
+	Chain string `json:"chain"`
+}
+
+// GetConsensusState returns the processing block tree of a snowman chain with
+// the confidence of every block, and the outstanding polls with the responses
+// of the polled validators
+func (a *Admin) GetConsensusState(_ *http.Request, args *GetConsensusStateArgs, reply *smeng.ConsensusState) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "getConsensusState"),
+		logging.UserString("chain", args.Chain),
+	)
+
+	chainID, err := a.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	state, err := a.ChainManager.SnowmanConsensusState(chainID)
+	if err != nil {
+		return err
+	}
+	if state != nil {
+		*reply = *state
+	}
+	return nil
+}
+
+// GetPreferenceChangesArgs are the arguments for calling GetPreferenceChanges
+type GetPreferenceChangesArgs struct {
+	Secret
+	Chain string `json:"chain"`
+	// Only preference changes with a greater sequence number are returned
+	After json.Uint64 `json:"after"`
+}
+
+// GetPreferenceChangesReply are the preference changes of a snowman chain
+type GetPreferenceChangesReply struct {
+	Changes []smeng.PreferenceChange `json:"changes"`
+}
+
+// GetPreferenceChanges returns the last preference changes of a snowman
+// chain. Callers follow the log by passing the sequence number of the last
+// change they received as [After].
+func (a *Admin) GetPreferenceChanges(_ *http.Request, args *GetPreferenceChangesArgs, reply *GetPreferenceChangesReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "getPreferenceChanges"),
+		logging.UserString("chain", args.Chain),
+		zap.Uint64("after", uint64(args.After)),
+	)
+
+	chainID, err := a.ChainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	reply.Changes, err = a.ChainManager.SnowmanPreferenceChanges(chainID, uint64(args.After))
+	return err
+}
+
+// ProposeConsensusParametersArgs are the arguments for calling
+// ProposeConsensusParameters
+type ProposeConsensusParametersArgs struct {
+	Secret
+	SubnetID   ids.ID              `json:"subnetID"`
+	Parameters snowball.Parameters `json:"parameters"`
+	// If true, the parameters are only verified and the finality latency is
+	// estimated
+	DryRun bool `json:"dryRun"`
+}
+
+// ProposeConsensusParametersReply is the result of a parameters proposal
+type ProposeConsensusParametersReply struct {
+	// Estimate is the finality latency expected with the proposed parameters.
+	// Nil if no poll responses were recorded yet.
+	Estimate *smeng.FinalityEstimate `json:"estimate"`
+}
+
+// ProposeConsensusParameters verifies the snowball parameters for a subnet
+// and, unless in dry-run mode, persists them. The running chains of the subnet
+// switch to them once no blocks are processing.
+func (a *Admin) ProposeConsensusParameters(_ *http.Request, args *ProposeConsensusParametersArgs, reply *ProposeConsensusParametersReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "proposeConsensusParameters"),
+		zap.Stringer("subnetID", args.SubnetID),
+		zap.Reflect("parameters", args.Parameters),
+		zap.Bool("dryRun", args.DryRun),
+	)
+
+	var err error
+	reply.Estimate, err = a.ChainManager.ProposeConsensusParameters(args.SubnetID, args.Parameters, args.DryRun)
+	return err
+}
+
+// ClearConsensusParametersArgs are the arguments for calling
+// ClearConsensusParameters
+type ClearConsensusParametersArgs struct {
+	Secret
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// ClearConsensusParameters removes the proposed snowball parameters of a
+// subnet. The chains of the subnet switch back to the configured parameters
+// once no blocks are processing.
+func (a *Admin) ClearConsensusParameters(_ *http.Request, args *ClearConsensusParametersArgs, _ *api.EmptyReply) error {
+	a.Log.Debug("API called",
+		zap.String("service", "admin"),
+		zap.String("method", "clearConsensusParameters"),
+		zap.Stringer("subnetID", args.SubnetID),
+	)
+
+	return a.ChainManager.ClearConsensusParameters(args.SubnetID)
+}
+
 // See GetNodeSigner
 type GetNodeSignerReply struct {
 	PrivateKey string `json:"privateKey"`
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -16,12 +26,11 @@ import (
 
 	jwt "github.com/golang-jwt/jwt/v4"
 
-	"github.com/gorilla/rpc/v2"
-
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/utils/json"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/password"
-	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 )
 
@@ -46,6 +55,7 @@ var (
 		headerValStart,
 	)
 	errInvalidSigningMethod        = errors.New("auth token didn't specify the HS256 signing method correctly")
+	errUnknownPrincipal            = errors.New("auth token was issued to an unknown principal")
 	errTokenRevoked                = errors.New("the provided auth token was revoked")
 	errTokenInsufficientPermission = errors.New("the provided auth token does not allow access to this endpoint")
 	errWrongPassword               = errors.New("incorrect password")
@@ -63,15 +73,25 @@ type Auth interface {
 	// If one of the elements of [endpoints] is "*", all APIs are accessible.
 	NewToken(pw string, duration time.Duration, endpoints []string) (string, error)
 
+	// Create and return a new token of [principal] that is restricted to
+	// [scope] for [duration]. If [principal] is empty or [AdminPrincipal], [pw]
+	// is the API auth password and [scope] isn't restricted. Otherwise, [pw] is
+	// the password of the principal and [scope] must be within the scope of the
+	// principal. If [duration] is 0, the default token lifespan is used.
+	NewScopedToken(principal, pw string, duration time.Duration, scope Scope) (string, error)
+
 	// Revokes [token]; it will not be accepted as authorization for future API
 	// calls. If the token is invalid, this is a no-op.  If a token is revoked
 	// and then the password is changed, and then changed back to the current
 	// password, the token will be un-revoked. Therefore, passwords shouldn't be
-	// re-used before previously revoked tokens have expired.
+	// re-used before previously revoked tokens have expired. [pw] is either the
+	// API auth password or the password of the principal of the token.
 	RevokeToken(pw, token string) error
 
-	// Authenticates [token] for access to [url].
-	AuthenticateToken(token, url string) error
+	// Authenticates [token] for calling the JSON-RPC [method] at [url].
+	// [method] is empty if the call isn't a JSON-RPC call. Each successful
+	// authentication counts towards the rate limit of the token.
+	AuthenticateToken(token, url, method string) error
 
 	// Change the password required to create and revoke tokens.
 	// [oldPW] is the current password.
@@ -96,53 +116,114 @@ type auth struct {
 	log      logging.Logger
 	endpoint string
 
+	// Logs every API call that requires a token.
+	auditLog logging.Logger
+
 	lock sync.RWMutex
 	// Can be changed via API call.
 	password password.Hash
-	// Set of token IDs that have been revoked
-	revoked set.Set[string]
+	// Principals other than the admin, by name. Their passwords can be changed
+	// via API call.
+	principals map[string]*principal
+	// Token IDs that have been revoked, mapped to the principal of the token
+	revoked map[string]string
+
+	limitersLock sync.Mutex
+	// Rate limiters of the tokens with a rate limit, by token ID
+	limiters map[string]*tokenLimiter
 }
 
-func New(log logging.Logger, endpoint, pw string) (Auth, error) {
+// New returns a new Auth. [pw] is the API auth password of the admin and
+// [principals] are the additional principals that can create tokens. Calls
+// that require a token are logged to [auditLog].
+func New(log, auditLog logging.Logger, endpoint, pw string, principals []Principal) (Auth, error) {
+	hashes, err := newPrincipals(principals)
+	if err != nil {
+		return nil, err
+	}
 	a := &auth{
-		log:      log,
-		endpoint: endpoint,
+		log:        log,
+		endpoint:   endpoint,
+		auditLog:   auditLog,
+		principals: hashes,
+		revoked:    make(map[string]string),
+		limiters:   make(map[string]*tokenLimiter),
 	}
 	return a, a.password.Set(pw)
 }
 
 func NewFromHash(log logging.Logger, endpoint string, pw password.Hash) Auth {
 	return &auth{
-		log:      log,
-		endpoint: endpoint,
-		password: pw,
+		log:        log,
+		endpoint:   endpoint,
+		auditLog:   logging.NoLog{},
+		password:   pw,
+		principals: make(map[string]*principal),
+		revoked:    make(map[string]string),
+		limiters:   make(map[string]*tokenLimiter),
 	}
 }
 
 func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (string, error) {
+	return a.NewScopedToken(AdminPrincipal, pw, duration, Scope{Endpoints: endpoints})
+}
+
+func (a *auth) NewScopedToken(principalName, pw string, duration time.Duration, scope Scope) (string, error) {
 	if pw == "" {
 		return "", errNoPassword
 	}
-	if l := len(endpoints); l == 0 {
+	if l := len(scope.Endpoints); l == 0 {
 		return "", errNoEndpoints
 	} else if l > maxEndpoints {
 		return "", errTooManyEndpoints
 	}
+	if len(scope.Methods) > maxMethods {
+		return "", errTooManyMethods
+	}
 
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
-	if !a.password.Check(pw) {
-		return "", errWrongPassword
+	key := a.password.Password[:]
+	if principalName == "" || principalName == AdminPrincipal {
+		principalName = ""
+		if !a.password.Check(pw) {
+			return "", errWrongPassword
+		}
+		if duration == 0 {
+			duration = defaultTokenLifespan
+		}
+	} else {
+		// Unknown principals are reported as a wrong password to not reveal
+		// which principals exist.
+		p, ok := a.principals[principalName]
+		if !ok || !p.password.Check(pw) {
+			return "", errWrongPassword
+		}
+		var err error
+		if scope, err = p.narrow(scope); err != nil {
+			return "", err
+		}
+		if duration, err = p.lifespan(duration); err != nil {
+			return "", err
+		}
+		key = p.password.Password[:]
 	}
 
 	canAccessAll := false
-	for _, endpoint := range endpoints {
+	for _, endpoint := range scope.Endpoints {
 		if endpoint == "*" {
 			canAccessAll = true
 			break
 		}
 	}
+	canCallAll := false
+	for _, method := range scope.Methods {
+		if method == "*" {
+			canCallAll = true
+			break
+		}
+	}
 
 	idBytes := [tokenIDByteLen]byte{}
 	if _, err := rand.Read(idBytes[:]); err != nil {
@@ -152,17 +233,22 @@ func (a *auth) NewToken(pw string, duration time.Duration, endpoints []string) (
 
 	claims := endpointClaims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   principalName,
 			ExpiresAt: jwt.NewNumericDate(a.clock.Time().Add(duration)),
 			ID:        id,
 		},
+		RateLimit: scope.RateLimit,
 	}
 	if canAccessAll {
 		claims.Endpoints = []string{"*"}
 	} else {
-		claims.Endpoints = endpoints
+		claims.Endpoints = scope.Endpoints
+	}
+	if !canCallAll {
+		claims.Methods = scope.Methods
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
-	return token.SignedString(a.password.Password[:]) // Sign the token and return its string repr.
+	return token.SignedString(key) // Sign the token and return its string repr.
 }
 
 func (a *auth) RevokeToken(tokenStr, pw string) error {
@@ -176,36 +262,52 @@ func (a *auth) RevokeToken(tokenStr, pw string) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 
-	if !a.password.Check(pw) {
-		return errWrongPassword
-	}
+	isAdmin := a.password.Check(pw)
 
 	// See if token is well-formed and signature is right
 	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
 	if err != nil {
+		if !isAdmin {
+			return errWrongPassword
+		}
 		return err
 	}
 
+	claims, ok := token.Claims.(*endpointClaims)
+	if !ok {
+		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+	}
+	if !isAdmin {
+		p, ok := a.principals[claims.Subject]
+		if !ok || !p.password.Check(pw) {
+			return errWrongPassword
+		}
+	}
+
 	// If the token isn't valid, it has essentially already been revoked.
 	if !token.Valid {
 		return nil
 	}
+	a.revoked[claims.ID] = claims.Subject
+	return nil
+}
 
-	claims, ok := token.Claims.(*endpointClaims)
-	if !ok {
-		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+func (a *auth) AuthenticateToken(tokenStr, url, method string) error {
+	claims, err := a.authenticate(tokenStr, url)
+	if err != nil {
+		return err
 	}
-	a.revoked.Add(claims.ID)
-	return nil
+	return a.authorize(claims, []string{method})
 }
 
-func (a *auth) AuthenticateToken(tokenStr, url string) error {
+// authenticate returns the claims of [tokenStr] if it gives access to [url].
+func (a *auth) authenticate(tokenStr, url string) (*endpointClaims, error) {
 	a.lock.RLock()
 	defer a.lock.RUnlock()
 
 	token, err := jwt.ParseWithClaims(tokenStr, &endpointClaims{}, a.getTokenKey)
 	if err != nil { // Probably because signature wrong
-		return err
+		return nil, err
 	}
 
 	// Make sure this token gives access to the requested endpoint
@@ -213,23 +315,46 @@ func (a *auth) AuthenticateToken(tokenStr, url string) error {
 	if !ok {
 		// Error is intentionally dropped here as there is nothing left to do
 		// with it.
-		return fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
+		return nil, fmt.Errorf("expected auth token's claims to be type endpointClaims but is %T", token.Claims)
 	}
 
 	_, revoked := a.revoked[claims.ID]
 	if revoked {
-		return errTokenRevoked
+		return nil, errTokenRevoked
 	}
 
 	for _, endpoint := range claims.Endpoints {
 		if endpoint == "*" || strings.HasSuffix(url, endpoint) {
-			return nil
+			return claims, nil
 		}
 	}
-	return errTokenInsufficientPermission
+	return nil, errTokenInsufficientPermission
+}
+
+// authorize returns nil if [claims] allow calling all of [methods] and the
+// rate limit of the token isn't exhausted.
+func (a *auth) authorize(claims *endpointClaims, methods []string) error {
+	if len(claims.Methods) != 0 {
+		if len(methods) == 0 {
+			return errTokenMethodNotAllowed
+		}
+		for _, method := range methods {
+			if !methodAllowed(claims.Methods, method) {
+				return fmt.Errorf("%w: %q", errTokenMethodNotAllowed, method)
+			}
+		}
+	}
+	if claims.RateLimit != 0 && !a.allow(claims) {
+		return errTokenRateLimitExhausted
+	}
+	return nil
 }
 
 func (a *auth) ChangePassword(oldPW, newPW string) error {
+	return a.changePassword(AdminPrincipal, oldPW, newPW)
+}
+
+func (a *auth) changePassword(principalName, oldPW, newPW string) error {
 	if oldPW == newPW {
 		return errSamePassword
 	}
@@ -237,24 +362,39 @@ func (a *auth) ChangePassword(oldPW, newPW string) error {
 	a.lock.Lock()
 	defer a.lock.Unlock()
 
-	if !a.password.Check(oldPW) {
+	hash := &a.password
+	if principalName == "" || principalName == AdminPrincipal {
+		principalName = ""
+	} else {
+		p, ok := a.principals[principalName]
+		if !ok {
+			return errWrongPassword
+		}
+		hash = &p.password
+	}
+
+	if !hash.Check(oldPW) {
 		return errWrongPassword
 	}
 	if err := password.IsValid(newPW, password.OK); err != nil {
 		return err
 	}
-	if err := a.password.Set(newPW); err != nil {
+	if err := hash.Set(newPW); err != nil {
 		return err
 	}
 
-	// All the revoked tokens are now invalid; no need to mark specifically as
-	// revoked.
-	a.revoked.Clear()
+	// All the revoked tokens of the principal are now invalid; no need to mark
+	// specifically as revoked.
+	for id, principal := range a.revoked {
+		if principal == principalName {
+			delete(a.revoked, id)
+		}
+	}
 	return nil
 }
 
 func (a *auth) CreateHandler() (http.Handler, error) {
-	server := rpc.NewServer()
+	server := openrpc.NewServer()
 	codec := json.NewCodec()
 	server.RegisterCodec(codec, "application/json")
 	server.RegisterCodec(codec, "application/json;charset=UTF-8")
@@ -287,12 +427,40 @@ func (a *auth) WrapHandler(h http.Handler) http.Handler {
 		// Returns actual auth token. Slice guaranteed to not go OOB
 		tokenStr := rawHeader[len(headerValStart):]
 
-		if err := a.AuthenticateToken(tokenStr, r.URL.Path); err != nil {
+		// The body is only read once the token is known to be valid, so that
+		// unauthenticated callers can't make us buffer it.
+		// gRPC calls are scoped by the endpoint and the methods of the
+		// JSON-RPC API their service mirrors.
+		endpoint := r.URL.Path
+		grpcEndpoint, grpcMethod, isGRPC := grpcScope(r)
+		if isGRPC {
+			endpoint = grpcEndpoint
+		}
+		claims, err := a.authenticate(tokenStr, endpoint)
+		var methods []string
+		if err == nil && isGRPC {
+			methods = []string{grpcMethod}
+		} else if err == nil {
+			methods, err = readRPCMethods(w, r)
+		}
+		if err == nil {
+			err = a.authorize(claims, methods)
+		}
+		a.audit(r, claims, methods, err)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, errTokenRateLimitExhausted):
+			writeErrorResponse(w, http.StatusTooManyRequests, err)
+			return
+		case errors.As(err, &maxBytesErr):
+			writeErrorResponse(w, http.StatusRequestEntityTooLarge, err)
+			return
+		case err != nil:
 			writeUnauthorizedResponse(w, err)
 			return
 		}
 
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(w, r.WithContext(api.WithAuthToken(r.Context(), claims.ID)))
 	})
 }
 
@@ -301,5 +469,13 @@ func (a *auth) getTokenKey(t *jwt.Token) (interface{}, error) {
 	if t.Method != jwt.SigningMethodHS256 {
 		return nil, errInvalidSigningMethod
 	}
-	return a.password.Password[:], nil
+	claims, ok := t.Claims.(*endpointClaims)
+	if !ok || claims.Subject == "" {
+		return a.password.Password[:], nil
+	}
+	p, ok := a.principals[claims.Subject]
+	if !ok {
+		return nil, errUnknownPrincipal
+	}
+	return p.password.Password[:], nil
 }
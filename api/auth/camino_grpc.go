@@ -0,0 +1,53 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const grpcContentType = "application/grpc"
+
+// grpcAPI is the JSON-RPC API that is mirrored by a gRPC service
+type grpcAPI struct {
+	// Endpoint the JSON-RPC API is served at
+	endpoint string
+	// Name of the JSON-RPC service, as used in method names
+	service string
+}
+
+// grpcAPIs maps the gRPC services served on the HTTP port to the JSON-RPC API
+// they mirror. Calls to these services are scoped the same way as the calls
+// to the JSON-RPC API.
+var grpcAPIs = map[string]grpcAPI{
+	"info.v1.Info":         {endpoint: "/ext/info", service: "info"},
+	"health.v1.Health":     {endpoint: "/ext/health", service: "health"},
+	"platform.v1.Platform": {endpoint: "/ext/bc/P", service: "platform"},
+	"avm.v1.AVM":           {endpoint: "/ext/bc/X", service: "avm"},
+	"admin.v1.Admin":       {endpoint: "/ext/admin", service: "admin"},
+}
+
+// grpcScope returns the endpoint and the JSON-RPC method that scope the gRPC
+// call [r]. The gRPC method GetNodeID of info.v1.Info is scoped by the endpoint
+// /ext/info and the method info.getNodeID. Returns false if [r] isn't a gRPC
+// call to a known service.
+func grpcScope(r *http.Request) (string, string, bool) {
+	if r.ProtoMajor != 2 || !strings.HasPrefix(r.Header.Get("Content-Type"), grpcContentType) {
+		return "", "", false
+	}
+	// The path of a gRPC call is /<service>/<method>
+	service, method, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if !ok || method == "" || strings.Contains(method, "/") {
+		return "", "", false
+	}
+	api, ok := grpcAPIs[service]
+	if !ok {
+		return "", "", false
+	}
+	first, size := utf8.DecodeRuneInString(method)
+	return api.endpoint, api.service + "." + string(unicode.ToLower(first)) + method[size:], true
+}
@@ -0,0 +1,273 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/time/rate"
+
+	"github.com/ava-labs/avalanchego/utils/password"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// AdminPrincipal is the name of the principal authenticated by the node's
+	// API auth password. Tokens of this principal aren't restricted in scope.
+	AdminPrincipal = "admin"
+
+	maxMethods = 128
+
+	// maxRPCBodySize is the maximum size of the body of a call with a token
+	maxRPCBodySize = 16 * units.MiB
+)
+
+var (
+	errNoPrincipalName         = errors.New("principal must have a name")
+	errDuplicatePrincipal      = errors.New("duplicate principal")
+	errReservedPrincipal       = fmt.Errorf("principal name %q is reserved", AdminPrincipal)
+	errNoPrincipalMethods      = errors.New("principal must name at least one method")
+	errTooManyMethods          = fmt.Errorf("can only name at most %d methods", maxMethods)
+	errEndpointNotPermitted    = errors.New("principal isn't permitted to access endpoint")
+	errMethodNotPermitted      = errors.New("principal isn't permitted to call method")
+	errRateLimitNotPermitted   = errors.New("rate limit exceeds the principal's rate limit")
+	errLifespanNotPermitted    = errors.New("token lifespan exceeds the principal's maximum token lifespan")
+	errTokenMethodNotAllowed   = errors.New("the provided auth token does not allow calling this method")
+	errTokenRateLimitExhausted = errors.New("the provided auth token exceeded its rate limit")
+)
+
+// Scope restricts what a token can be used for.
+type Scope struct {
+	// Each element is an endpoint that may be accessed, see [Auth.NewToken].
+	Endpoints []string `json:"endpoints"`
+	// Each element is a JSON-RPC method that may be called, e.g.
+	// "platform.getBalance". An element "platform.*" allows all methods of the
+	// platform service and an element "*" allows all methods. If empty, the
+	// methods aren't restricted.
+	Methods []string `json:"methods"`
+	// Maximum number of requests per minute. If 0, requests aren't limited.
+	RateLimit uint32 `json:"rateLimit"`
+}
+
+// Principal is a named identity, e.g. a partner company, that creates its own
+// tokens with its own password. The tokens of a principal can't exceed the
+// scope of the principal.
+type Principal struct {
+	Name     string
+	Password string
+	// Scope bounds the scope of every token created by the principal.
+	// [Scope.Methods] must not be empty.
+	Scope Scope
+	// Maximum lifespan of the principal's tokens. If 0, the lifespan isn't
+	// bounded.
+	MaxTokenLifespan time.Duration
+}
+
+type principal struct {
+	password         password.Hash
+	scope            Scope
+	maxTokenLifespan time.Duration
+}
+
+type tokenLimiter struct {
+	limiter   *rate.Limiter
+	expiresAt time.Time
+}
+
+func newPrincipals(principals []Principal) (map[string]*principal, error) {
+	hashes := make(map[string]*principal, len(principals))
+	for _, p := range principals {
+		switch {
+		case p.Name == "":
+			return nil, errNoPrincipalName
+		case p.Name == AdminPrincipal:
+			return nil, errReservedPrincipal
+		case len(p.Scope.Endpoints) == 0:
+			return nil, fmt.Errorf("%w: %s", errNoEndpoints, p.Name)
+		case len(p.Scope.Methods) == 0:
+			return nil, fmt.Errorf("%w: %s", errNoPrincipalMethods, p.Name)
+		}
+		if _, ok := hashes[p.Name]; ok {
+			return nil, fmt.Errorf("%w: %s", errDuplicatePrincipal, p.Name)
+		}
+		if err := password.IsValid(p.Password, password.OK); err != nil {
+			return nil, fmt.Errorf("invalid password of principal %s: %w", p.Name, err)
+		}
+		hashed := &principal{
+			scope:            p.Scope,
+			maxTokenLifespan: p.MaxTokenLifespan,
+		}
+		if err := hashed.password.Set(p.Password); err != nil {
+			return nil, err
+		}
+		hashes[p.Name] = hashed
+	}
+	return hashes, nil
+}
+
+// narrow returns [scope] restricted to the scope of the principal. Unset
+// methods and rate limit are inherited from the principal.
+func (p *principal) narrow(scope Scope) (Scope, error) {
+	for _, endpoint := range scope.Endpoints {
+		if !endpointAllowed(p.scope.Endpoints, endpoint) {
+			return Scope{}, fmt.Errorf("%w: %s", errEndpointNotPermitted, endpoint)
+		}
+	}
+
+	if len(scope.Methods) == 0 {
+		scope.Methods = p.scope.Methods
+	}
+	for _, method := range scope.Methods {
+		if !methodAllowed(p.scope.Methods, method) {
+			return Scope{}, fmt.Errorf("%w: %s", errMethodNotPermitted, method)
+		}
+	}
+
+	switch {
+	case p.scope.RateLimit == 0:
+	case scope.RateLimit == 0:
+		scope.RateLimit = p.scope.RateLimit
+	case scope.RateLimit > p.scope.RateLimit:
+		return Scope{}, errRateLimitNotPermitted
+	}
+	return scope, nil
+}
+
+// lifespan returns the lifespan of a token of the principal, given the
+// requested [duration]. If [duration] is 0, the default lifespan is used.
+func (p *principal) lifespan(duration time.Duration) (time.Duration, error) {
+	switch {
+	case duration == 0 && p.maxTokenLifespan != 0 && p.maxTokenLifespan < defaultTokenLifespan:
+		return p.maxTokenLifespan, nil
+	case duration == 0:
+		return defaultTokenLifespan, nil
+	case p.maxTokenLifespan != 0 && duration > p.maxTokenLifespan:
+		return 0, errLifespanNotPermitted
+	}
+	return duration, nil
+}
+
+// endpointAllowed returns true if every URL that can be accessed with
+// [endpoint] can be accessed with one of [endpoints].
+func endpointAllowed(endpoints []string, endpoint string) bool {
+	for _, e := range endpoints {
+		if e == "*" || (endpoint != "*" && strings.HasSuffix(endpoint, e)) {
+			return true
+		}
+	}
+	return false
+}
+
+// methodAllowed returns true if [method] is matched by one of [methods]. As
+// [method] is matched literally, this also returns true if a method pattern is
+// covered by one of [methods].
+func methodAllowed(methods []string, method string) bool {
+	for _, m := range methods {
+		switch {
+		case m == "*", m == method:
+			return true
+		case strings.HasSuffix(m, ".*") && strings.HasPrefix(method, m[:len(m)-1]):
+			return true
+		}
+	}
+	return false
+}
+
+// allow consumes one request of the rate limit of the token described by
+// [claims]. Returns false if the rate limit is exhausted.
+func (a *auth) allow(claims *endpointClaims) bool {
+	a.limitersLock.Lock()
+	defer a.limitersLock.Unlock()
+
+	now := a.clock.Time()
+	l, ok := a.limiters[claims.ID]
+	if !ok {
+		// Drop the limiters of expired tokens, so they don't accumulate.
+		for id, l := range a.limiters {
+			if !now.Before(l.expiresAt) {
+				delete(a.limiters, id)
+			}
+		}
+		l = &tokenLimiter{
+			limiter: rate.NewLimiter(rate.Limit(float64(claims.RateLimit)/time.Minute.Seconds()), int(claims.RateLimit)),
+		}
+		if claims.ExpiresAt != nil {
+			l.expiresAt = claims.ExpiresAt.Time
+		}
+		a.limiters[claims.ID] = l
+	}
+	return l.limiter.AllowN(now, 1)
+}
+
+// audit logs an API call authenticated with [claims]. [claims] is nil if the
+// token couldn't be authenticated.
+func (a *auth) audit(r *http.Request, claims *endpointClaims, methods []string, err error) {
+	fields := []zap.Field{
+		zap.String("endpoint", r.URL.Path),
+		zap.Strings("methods", methods),
+		zap.String("remoteAddr", r.RemoteAddr),
+	}
+	if claims != nil {
+		principal := claims.Subject
+		if principal == "" {
+			principal = AdminPrincipal
+		}
+		fields = append(fields,
+			zap.String("principal", principal),
+			zap.String("tokenID", claims.ID),
+		)
+	}
+	if err != nil {
+		a.auditLog.Info("API call rejected", append(fields, zap.Error(err))...)
+		return
+	}
+	a.auditLog.Info("API call authorized", fields...)
+}
+
+// readRPCMethods returns the JSON-RPC methods called by [r]. A batch request
+// calls one method per element. If [r] isn't a JSON-RPC call, no methods are
+// returned. At most [maxRPCBodySize] bytes are read and the body of [r] is
+// replaced, so that it can be read again.
+func readRPCMethods(w http.ResponseWriter, r *http.Request) ([]string, error) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRPCBodySize))
+	_ = r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	type rpcRequest struct {
+		Method string `json:"method"`
+	}
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	if len(trimmed) == 0 || trimmed[0] != '[' {
+		request := rpcRequest{}
+		if json.Unmarshal(body, &request) != nil {
+			return nil, nil
+		}
+		return []string{request.Method}, nil
+	}
+
+	batch := []rpcRequest{}
+	if json.Unmarshal(body, &batch) != nil {
+		return nil, nil
+	}
+	methods := make([]string, len(batch))
+	for i, request := range batch {
+		methods[i] = request.Method
+	}
+	return methods, nil
+}
@@ -0,0 +1,414 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const testPrincipalPassword = "Zb4!q8#Lw2@xR9$tPm" // #nosec G101
+
+var testPrincipal = Principal{
+	Name:     "partner",
+	Password: testPrincipalPassword,
+	Scope: Scope{
+		Endpoints: []string{"/ext/bc/P", "/ext/info"},
+		Methods:   []string{"platform.getBalance", "info.*"},
+		RateLimit: 10,
+	},
+	MaxTokenLifespan: time.Hour,
+}
+
+func newTestPrincipalAuth(t *testing.T) *auth {
+	a, err := New(logging.NoLog{}, logging.NoLog{}, "auth", testPassword, []Principal{testPrincipal})
+	require.NoError(t, err)
+	return a.(*auth)
+}
+
+func serveRPC(h http.Handler, token, endpoint, method string) *httptest.ResponseRecorder {
+	body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `","params":{}}`
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650"+endpoint, strings.NewReader(body))
+	req.Header.Add("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestNewPrincipalsInvalid(t *testing.T) {
+	tests := map[string]struct {
+		principals  []Principal
+		expectedErr error
+	}{
+		"no name": {
+			principals:  []Principal{{Password: testPrincipalPassword, Scope: testPrincipal.Scope}},
+			expectedErr: errNoPrincipalName,
+		},
+		"reserved name": {
+			principals:  []Principal{{Name: AdminPrincipal, Password: testPrincipalPassword, Scope: testPrincipal.Scope}},
+			expectedErr: errReservedPrincipal,
+		},
+		"no methods": {
+			principals:  []Principal{{Name: "a", Password: testPrincipalPassword, Scope: Scope{Endpoints: []string{"*"}}}},
+			expectedErr: errNoPrincipalMethods,
+		},
+		"duplicate": {
+			principals:  []Principal{testPrincipal, testPrincipal},
+			expectedErr: errDuplicatePrincipal,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(logging.NoLog{}, logging.NoLog{}, "auth", testPassword, test.principals)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestNewScopedTokenPrincipalScope(t *testing.T) {
+	a := newTestPrincipalAuth(t)
+
+	tests := map[string]struct {
+		principal   string
+		password    string
+		duration    time.Duration
+		scope       Scope
+		expectedErr error
+	}{
+		"unknown principal": {
+			principal:   "unknown",
+			password:    testPrincipalPassword,
+			scope:       Scope{Endpoints: []string{"/ext/info"}},
+			expectedErr: errWrongPassword,
+		},
+		"admin password for principal": {
+			principal:   testPrincipal.Name,
+			password:    testPassword,
+			scope:       Scope{Endpoints: []string{"/ext/info"}},
+			expectedErr: errWrongPassword,
+		},
+		"endpoint outside of scope": {
+			principal:   testPrincipal.Name,
+			password:    testPrincipalPassword,
+			scope:       Scope{Endpoints: []string{"*"}},
+			expectedErr: errEndpointNotPermitted,
+		},
+		"method outside of scope": {
+			principal:   testPrincipal.Name,
+			password:    testPrincipalPassword,
+			scope:       Scope{Endpoints: []string{"/ext/bc/P"}, Methods: []string{"platform.*"}},
+			expectedErr: errMethodNotPermitted,
+		},
+		"rate limit outside of scope": {
+			principal:   testPrincipal.Name,
+			password:    testPrincipalPassword,
+			scope:       Scope{Endpoints: []string{"/ext/bc/P"}, RateLimit: 11},
+			expectedErr: errRateLimitNotPermitted,
+		},
+		"lifespan outside of scope": {
+			principal:   testPrincipal.Name,
+			password:    testPrincipalPassword,
+			duration:    2 * time.Hour,
+			scope:       Scope{Endpoints: []string{"/ext/bc/P"}},
+			expectedErr: errLifespanNotPermitted,
+		},
+		"narrower scope": {
+			principal: testPrincipal.Name,
+			password:  testPrincipalPassword,
+			duration:  time.Minute,
+			scope:     Scope{Endpoints: []string{"/ext/info"}, Methods: []string{"info.getNodeID"}, RateLimit: 1},
+		},
+		"admin": {
+			principal: AdminPrincipal,
+			password:  testPassword,
+			scope:     Scope{Endpoints: []string{"*"}, Methods: []string{"*"}},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.NewScopedToken(test.principal, test.password, test.duration, test.scope)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestWrapHandlerMethodScope(t *testing.T) {
+	require := require.New(t)
+	a := newTestPrincipalAuth(t)
+
+	var calledMethod string
+	wrappedHandler := a.WrapHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		methods, err := readRPCMethods(nil, r)
+		require.NoError(err)
+		require.Len(methods, 1)
+		calledMethod = methods[0]
+	}))
+
+	// The token inherits the methods of the principal.
+	tokenStr, err := a.NewScopedToken(testPrincipal.Name, testPrincipalPassword, 0, Scope{Endpoints: []string{"/ext/bc/P", "/ext/info"}})
+	require.NoError(err)
+
+	rr := serveRPC(wrappedHandler, tokenStr, "/ext/bc/P", "platform.getBalance")
+	require.Equal(http.StatusOK, rr.Code)
+	require.Equal("platform.getBalance", calledMethod)
+
+	rr = serveRPC(wrappedHandler, tokenStr, "/ext/info", "info.getNodeID")
+	require.Equal(http.StatusOK, rr.Code)
+
+	rr = serveRPC(wrappedHandler, tokenStr, "/ext/bc/P", "platform.addValidator")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+	require.Contains(rr.Body.String(), errTokenMethodNotAllowed.Error())
+
+	// Requests that aren't JSON-RPC calls don't call an allowed method.
+	req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9650/ext/info", nil)
+	req.Header.Add("Authorization", "Bearer "+tokenStr)
+	rr = httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	require.Equal(http.StatusUnauthorized, rr.Code)
+
+	// Admin tokens without methods can call all methods.
+	tokenStr, err = a.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	require.NoError(err)
+	rr = serveRPC(wrappedHandler, tokenStr, "/ext/bc/P", "platform.addValidator")
+	require.Equal(http.StatusOK, rr.Code)
+}
+
+func TestWrapHandlerRateLimit(t *testing.T) {
+	require := require.New(t)
+	a := newTestPrincipalAuth(t)
+	now := time.Now()
+	a.clock.Set(now)
+
+	tokenStr, err := a.NewScopedToken(testPrincipal.Name, testPrincipalPassword, 0, Scope{
+		Endpoints: []string{"/ext/info"},
+		RateLimit: 2,
+	})
+	require.NoError(err)
+	wrappedHandler := a.WrapHandler(dummyHandler)
+
+	for i := 0; i < 2; i++ {
+		rr := serveRPC(wrappedHandler, tokenStr, "/ext/info", "info.getNodeID")
+		require.Equal(http.StatusOK, rr.Code)
+	}
+	rr := serveRPC(wrappedHandler, tokenStr, "/ext/info", "info.getNodeID")
+	require.Equal(http.StatusTooManyRequests, rr.Code)
+	require.Contains(rr.Body.String(), errTokenRateLimitExhausted.Error())
+
+	// The rate limit is replenished over a minute.
+	a.clock.Set(now.Add(30 * time.Second))
+	rr = serveRPC(wrappedHandler, tokenStr, "/ext/info", "info.getNodeID")
+	require.Equal(http.StatusOK, rr.Code)
+}
+
+func TestPrincipalRevokeAndChangePassword(t *testing.T) {
+	require := require.New(t)
+	a := newTestPrincipalAuth(t)
+	wrappedHandler := a.WrapHandler(dummyHandler)
+
+	adminToken, err := a.NewToken(testPassword, defaultTokenLifespan, []string{"*"})
+	require.NoError(err)
+	principalToken, err := a.NewScopedToken(testPrincipal.Name, testPrincipalPassword, 0, Scope{Endpoints: []string{"/ext/info"}})
+	require.NoError(err)
+
+	// Principals can only revoke their own tokens.
+	require.ErrorIs(a.RevokeToken(adminToken, testPrincipalPassword), errWrongPassword)
+	require.NoError(a.RevokeToken(adminToken, testPassword))
+	rr := serveRPC(wrappedHandler, adminToken, "/ext/info", "info.getNodeID")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+
+	// Changing the password of the principal invalidates its tokens, but
+	// doesn't un-revoke the tokens of the admin.
+	rr = serveRPC(wrappedHandler, principalToken, "/ext/info", "info.getNodeID")
+	require.Equal(http.StatusOK, rr.Code)
+	require.NoError(a.changePassword(testPrincipal.Name, testPrincipalPassword, "Kd7%vN3^hY6&jQ1*s"))
+	rr = serveRPC(wrappedHandler, principalToken, "/ext/info", "info.getNodeID")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+	rr = serveRPC(wrappedHandler, adminToken, "/ext/info", "info.getNodeID")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+	require.Contains(rr.Body.String(), errTokenRevoked.Error())
+}
+
+func TestReadRPCMethods(t *testing.T) {
+	require := require.New(t)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"platform.getBalance"}`
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/P", strings.NewReader(body))
+	methods, err := readRPCMethods(httptest.NewRecorder(), req)
+	require.NoError(err)
+	require.Equal([]string{"platform.getBalance"}, methods)
+
+	// The body can be read again.
+	bodyBytes, err := io.ReadAll(req.Body)
+	require.NoError(err)
+	require.Equal(body, string(bodyBytes))
+
+	// Every call of a batch is returned.
+	body = ` [{"jsonrpc":"2.0","id":1,"method":"platform.getBalance"},{"jsonrpc":"2.0","id":2,"method":"platform.addValidator"}]`
+	req = httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/P", strings.NewReader(body))
+	methods, err = readRPCMethods(httptest.NewRecorder(), req)
+	require.NoError(err)
+	require.Equal([]string{"platform.getBalance", "platform.addValidator"}, methods)
+
+	req = httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/P", strings.NewReader("not json"))
+	methods, err = readRPCMethods(httptest.NewRecorder(), req)
+	require.NoError(err)
+	require.Empty(methods)
+
+	req = httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/P", bytes.NewReader(make([]byte, maxRPCBodySize+1)))
+	_, err = readRPCMethods(httptest.NewRecorder(), req)
+	var maxBytesErr *http.MaxBytesError
+	require.ErrorAs(err, &maxBytesErr)
+}
+
+func TestWrapHandlerBatch(t *testing.T) {
+	require := require.New(t)
+	a := newTestPrincipalAuth(t)
+	wrappedHandler := a.WrapHandler(dummyHandler)
+
+	tokenStr, err := a.NewScopedToken(testPrincipal.Name, testPrincipalPassword, 0, Scope{Endpoints: []string{"/ext/bc/P"}})
+	require.NoError(err)
+
+	serveBatch := func(methods ...string) *httptest.ResponseRecorder {
+		calls := make([]string, len(methods))
+		for i, method := range methods {
+			calls[i] = fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":%q}`, i, method)
+		}
+		body := "[" + strings.Join(calls, ",") + "]"
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/P", strings.NewReader(body))
+		req.Header.Add("Authorization", "Bearer "+tokenStr)
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serveBatch("platform.getBalance", "info.getNodeID")
+	require.Equal(http.StatusOK, rr.Code)
+
+	// A single call that isn't allowed rejects the whole batch.
+	rr = serveBatch("platform.getBalance", "platform.addValidator")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+	require.Contains(rr.Body.String(), errTokenMethodNotAllowed.Error())
+
+	rr = serveBatch()
+	require.Equal(http.StatusUnauthorized, rr.Code)
+}
+
+func TestWrapHandlerUnauthenticatedBodyNotRead(t *testing.T) {
+	require := require.New(t)
+	a := newTestPrincipalAuth(t)
+	wrappedHandler := a.WrapHandler(dummyHandler)
+
+	body := &countingReader{r: bytes.NewReader(make([]byte, units.KiB))}
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/bc/P", body)
+	req.Header.Add("Authorization", "Bearer invalid.token.here")
+	rr := httptest.NewRecorder()
+	wrappedHandler.ServeHTTP(rr, req)
+	require.Equal(http.StatusUnauthorized, rr.Code)
+	require.Zero(body.n)
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestMethodAllowed(t *testing.T) {
+	require := require.New(t)
+
+	methods := []string{"platform.getBalance", "info.*"}
+	require.True(methodAllowed(methods, "platform.getBalance"))
+	require.True(methodAllowed(methods, "info.getNodeID"))
+	require.True(methodAllowed(methods, "info.*"))
+	require.False(methodAllowed(methods, "platform.getBalances"))
+	require.False(methodAllowed(methods, "platform.*"))
+	require.False(methodAllowed(methods, "infos.getNodeID"))
+	require.False(methodAllowed(methods, "*"))
+	require.False(methodAllowed(methods, ""))
+	require.True(methodAllowed([]string{"*"}, "keystore.exportUser"))
+}
+
+func TestWrapHandlerGRPCScope(t *testing.T) {
+	require := require.New(t)
+	a := newTestPrincipalAuth(t)
+	wrappedHandler := a.WrapHandler(dummyHandler)
+
+	tokenStr, err := a.NewScopedToken(testPrincipal.Name, testPrincipalPassword, 0, Scope{Endpoints: []string{"/ext/bc/P", "/ext/info"}})
+	require.NoError(err)
+
+	serveGRPC := func(token, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650"+path, bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+		req.ProtoMajor = 2
+		req.Header.Set("Content-Type", "application/grpc+proto")
+		req.Header.Add("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		wrappedHandler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// gRPC methods are scoped by the endpoint and the methods of the JSON-RPC
+	// API their service mirrors.
+	rr := serveGRPC(tokenStr, "/platform.v1.Platform/GetBalance")
+	require.Equal(http.StatusOK, rr.Code)
+	rr = serveGRPC(tokenStr, "/info.v1.Info/GetNodeID")
+	require.Equal(http.StatusOK, rr.Code)
+
+	rr = serveGRPC(tokenStr, "/platform.v1.Platform/GetTx")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+	require.Contains(rr.Body.String(), errTokenMethodNotAllowed.Error())
+
+	rr = serveGRPC(tokenStr, "/avm.v1.AVM/GetBalance")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+	require.Contains(rr.Body.String(), errTokenInsufficientPermission.Error())
+
+	rr = serveGRPC(tokenStr, "/unknown.v1.Unknown/GetBalance")
+	require.Equal(http.StatusUnauthorized, rr.Code)
+
+	// Admin tokens without methods can call all gRPC methods.
+	tokenStr, err = a.NewToken(testPassword, defaultTokenLifespan, []string{"/ext/bc/X"})
+	require.NoError(err)
+	rr = serveGRPC(tokenStr, "/avm.v1.AVM/GetBalance")
+	require.Equal(http.StatusOK, rr.Code)
+}
+
+func TestGRPCScope(t *testing.T) {
+	require := require.New(t)
+
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/avm.v1.AVM/GetUTXOs", nil)
+	req.Header.Set("Content-Type", "application/grpc")
+	_, _, ok := grpcScope(req)
+	require.False(ok)
+
+	req.ProtoMajor = 2
+	endpoint, method, ok := grpcScope(req)
+	require.True(ok)
+	require.Equal("/ext/bc/X", endpoint)
+	require.Equal("avm.getUTXOs", method)
+
+	req.URL.Path = "/admin.v1.Admin/BanPeer"
+	endpoint, method, ok = grpcScope(req)
+	require.True(ok)
+	require.Equal("/ext/admin", endpoint)
+	require.Equal("admin.banPeer", method)
+
+	req.URL.Path = "/avm.v1.AVM"
+	_, _, ok = grpcScope(req)
+	require.False(ok)
+}
@@ -7,7 +7,8 @@ import (
 	jwt "github.com/golang-jwt/jwt/v4"
 )
 
-// Custom claim type used for API access token
+// Custom claim type used for API access token. The subject is the name of the
+// principal that created the token, or empty for the admin.
 type endpointClaims struct {
 	jwt.RegisteredClaims
 
@@ -15,4 +16,11 @@ type endpointClaims struct {
 	// If endpoints has an element "*", allows access to all API endpoints
 	// In this case, "*" should be the only element of [endpoints]
 	Endpoints []string `json:"endpoints,omitempty"`
+
+	// Each element is a JSON-RPC method, or pattern of methods, that the token
+	// allows calling. If empty, all methods may be called.
+	Methods []string `json:"methods,omitempty"`
+
+	// Maximum number of requests per minute. If 0, requests aren't limited.
+	RateLimit uint32 `json:"rateLimit,omitempty"`
 }
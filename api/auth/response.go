@@ -25,8 +25,15 @@ type responseBody struct {
 // The response has header http.StatusUnauthorized.
 // Errors while writing are ignored.
 func writeUnauthorizedResponse(w http.ResponseWriter, err error) {
+	writeErrorResponse(w, http.StatusUnauthorized, err)
+}
+
+// Write a JSON-RPC formatted response saying that the API call was rejected
+// with [err]. The response has header [statusCode].
+// Errors while writing are ignored.
+func writeErrorResponse(w http.ResponseWriter, statusCode int, err error) {
 	w.Header().Add("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
+	w.WriteHeader(statusCode)
 
 	// There isn't anything to do with the returned error, so it is dropped.
 	_ = json.NewEncoder(w).Encode(responseBody{
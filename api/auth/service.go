@@ -5,10 +5,12 @@ package auth
 
 import (
 	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/utils/json"
 )
 
 // Service that serves the Auth API functionality.
@@ -22,12 +24,25 @@ type Password struct {
 
 type NewTokenArgs struct {
 	Password
+	// Principal that creates the token. If empty, [Password] is the API auth
+	// password and the token may be of any scope.
+	Principal string `json:"principal"`
 	// Endpoints that may be accessed with this token e.g. if endpoints is
 	// ["/ext/bc/X", "/ext/admin"] then the token holder can hit the X-Chain API
 	// and the admin API. If [Endpoints] contains an element "*" then the token
 	// allows access to all API endpoints. [Endpoints] must have between 1 and
 	// [maxEndpoints] elements
 	Endpoints []string `json:"endpoints"`
+	// JSON-RPC methods that may be called with this token e.g. if methods is
+	// ["platform.getBalance", "info.*"] then the token holder can call
+	// platform.getBalance and all methods of the info API. If empty, the
+	// methods of the principal are allowed.
+	Methods []string `json:"methods"`
+	// Maximum number of requests per minute. If 0, the rate limit of the
+	// principal is used.
+	RateLimit json.Uint32 `json:"rateLimit"`
+	// Lifespan of the token in seconds. If 0, the default lifespan is used.
+	Lifespan json.Uint64 `json:"lifespan"`
 }
 
 type Token struct {
@@ -41,7 +56,16 @@ func (s *Service) NewToken(_ *http.Request, args *NewTokenArgs, reply *Token) er
 	)
 
 	var err error
-	reply.Token, err = s.auth.NewToken(args.Password.Password, defaultTokenLifespan, args.Endpoints)
+	reply.Token, err = s.auth.NewScopedToken(
+		args.Principal,
+		args.Password.Password,
+		time.Duration(args.Lifespan)*time.Second,
+		Scope{
+			Endpoints: args.Endpoints,
+			Methods:   args.Methods,
+			RateLimit: uint32(args.RateLimit),
+		},
+	)
 	return err
 }
 
@@ -60,6 +84,9 @@ func (s *Service) RevokeToken(_ *http.Request, args *RevokeTokenArgs, _ *api.Emp
 }
 
 type ChangePasswordArgs struct {
+	// Principal whose password is changed. If empty, the API auth password is
+	// changed.
+	Principal   string `json:"principal"`
 	OldPassword string `json:"oldPassword"` // Current authorization password
 	NewPassword string `json:"newPassword"` // New authorization password
 }
@@ -70,5 +97,5 @@ func (s *Service) ChangePassword(_ *http.Request, args *ChangePasswordArgs, _ *a
 		zap.String("method", "changePassword"),
 	)
 
-	return s.auth.ChangePassword(args.OldPassword, args.NewPassword)
+	return s.auth.changePassword(args.Principal, args.OldPassword, args.NewPassword)
 }
@@ -0,0 +1,23 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import "context"
+
+type contextKey int
+
+const authTokenKey contextKey = iota
+
+// WithAuthToken returns [ctx] annotated with the ID of the auth token that
+// authenticated the request of [ctx].
+func WithAuthToken(ctx context.Context, tokenID string) context.Context {
+	return context.WithValue(ctx, authTokenKey, tokenID)
+}
+
+// AuthToken returns the ID of the auth token that authenticated the request of
+// [ctx]. Returns false if the request wasn't authenticated by an auth token.
+func AuthToken(ctx context.Context) (string, bool) {
+	tokenID, ok := ctx.Value(authTokenKey).(string)
+	return tokenID, ok
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"strconv"
+
+	"github.com/graphql-go/graphql/language/ast"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+// builder converts the types of a schema to the types of the executor.
+type builder struct {
+	objects map[*Object]*gql.Object
+	scalars map[*Scalar]*gql.Scalar
+}
+
+func newBuilder() *builder {
+	return &builder{
+		objects: map[*Object]*gql.Object{},
+		scalars: map[*Scalar]*gql.Scalar{
+			String:  gql.String,
+			Int:     gql.Int,
+			Float:   gql.Float,
+			Boolean: gql.Boolean,
+			ID:      gql.ID,
+		},
+	}
+}
+
+func (b *builder) outputType(t Type) gql.Output {
+	switch t := t.(type) {
+	case *Scalar:
+		return b.scalar(t)
+	case *Object:
+		return b.object(t)
+	case *List:
+		return gql.NewList(b.outputType(t.OfType))
+	case *NonNull:
+		return gql.NewNonNull(b.outputType(t.OfType))
+	default:
+		return nil
+	}
+}
+
+func (b *builder) inputType(t Type) gql.Input {
+	switch t := t.(type) {
+	case *Scalar:
+		return b.scalar(t)
+	case *List:
+		return gql.NewList(b.inputType(t.OfType))
+	case *NonNull:
+		return gql.NewNonNull(b.inputType(t.OfType))
+	default:
+		return nil
+	}
+}
+
+func (b *builder) object(o *Object) *gql.Object {
+	if object, ok := b.objects[o]; ok {
+		return object
+	}
+	object := gql.NewObject(gql.ObjectConfig{
+		Name:        o.Name,
+		Description: o.Description,
+		// The fields are converted lazily, so that objects can reference each
+		// other.
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			fields := make(gql.Fields, len(o.Fields))
+			for name, f := range o.Fields {
+				fields[name] = b.field(name, f)
+			}
+			return fields
+		}),
+	})
+	b.objects[o] = object
+	return object
+}
+
+func (b *builder) field(name string, f *Field) *gql.Field {
+	args := make(gql.FieldConfigArgument, len(f.Args))
+	for argName, arg := range f.Args {
+		args[argName] = &gql.ArgumentConfig{
+			Type:         b.inputType(arg.Type),
+			Description:  arg.Description,
+			DefaultValue: coerceDefaultValue(arg),
+		}
+	}
+
+	resolve := f.Resolve
+	if resolve == nil {
+		resolve = func(p ResolveParams) (interface{}, error) {
+			return DefaultResolve(p.Source, name), nil
+		}
+	}
+	return &gql.Field{
+		Type:        b.outputType(f.Type),
+		Description: f.Description,
+		Args:        args,
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return resolve(ResolveParams{
+				Context: p.Context,
+				Source:  p.Source,
+				Args:    p.Args,
+			})
+		},
+	}
+}
+
+// coerceDefaultValue returns the default value of [arg] as it's passed to
+// resolvers.
+func coerceDefaultValue(arg *Argument) interface{} {
+	if arg.DefaultValue == nil {
+		return nil
+	}
+	value, err := coerceInput(arg.Type, normalizeInput(arg.DefaultValue))
+	if err != nil {
+		// The default value is passed unchanged, so that invalid default
+		// values are noticed.
+		return arg.DefaultValue
+	}
+	return value
+}
+
+func (b *builder) scalar(s *Scalar) *gql.Scalar {
+	if scalar, ok := b.scalars[s]; ok {
+		return scalar
+	}
+	// The executor treats nil as an invalid value and reports its own error.
+	parse := func(value interface{}) interface{} {
+		if s.Parse == nil {
+			return value
+		}
+		parsed, err := s.Parse(value)
+		if err != nil {
+			return nil
+		}
+		return parsed
+	}
+	scalar := gql.NewScalar(gql.ScalarConfig{
+		Name:        s.Name,
+		Description: s.Description,
+		Serialize: func(value interface{}) interface{} {
+			if s.Serialize == nil {
+				return value
+			}
+			serialized, err := s.Serialize(value)
+			if err != nil {
+				return nil
+			}
+			return serialized
+		},
+		ParseValue: func(value interface{}) interface{} {
+			return parse(normalizeInput(value))
+		},
+		ParseLiteral: func(value ast.Value) interface{} {
+			return parse(valueFromLiteral(value))
+		},
+	})
+	b.scalars[s] = scalar
+	return scalar
+}
+
+// valueFromLiteral returns the input value of the literal [v]. Variables are
+// replaced with their values by the executor, before literals are parsed.
+func valueFromLiteral(v ast.Value) interface{} {
+	switch v := v.(type) {
+	case *ast.IntValue:
+		if i, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.Value, 10, 64); err == nil {
+			return u
+		}
+		// Out of range integers can still be floats.
+		f, _ := strconv.ParseFloat(v.Value, 64)
+		return f
+	case *ast.FloatValue:
+		f, _ := strconv.ParseFloat(v.Value, 64)
+		return f
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.EnumValue:
+		return v.Value
+	case *ast.ListValue:
+		list := make([]interface{}, len(v.Values))
+		for i, item := range v.Values {
+			list[i] = valueFromLiteral(item)
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			object[f.Name.Value] = valueFromLiteral(f.Value)
+		}
+		return object
+	default:
+		return nil
+	}
+}
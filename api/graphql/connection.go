@@ -0,0 +1,148 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultPageSize is the number of nodes of a connection, that are
+	// returned, if the page size isn't given.
+	DefaultPageSize = 25
+	// MaxPageSize is the maximum number of nodes of a connection, that are
+	// returned at once.
+	MaxPageSize = 1024
+)
+
+var (
+	errInvalidPageSize = errors.New("invalid page size")
+
+	pageInfoType = &Object{
+		Name:        "PageInfo",
+		Description: "Information about a page of a connection",
+		Fields: Fields{
+			"hasNextPage": {
+				Type:        NewNonNull(Boolean),
+				Description: "True, if there are more nodes after the end cursor",
+			},
+			"endCursor": {
+				Type:        String,
+				Description: "Cursor of the last node of the page. Pass it as the after argument to get the next page",
+			},
+		},
+	}
+)
+
+// Connection is a page of a list of nodes. See
+// https://relay.dev/graphql/connections.htm.
+type Connection struct {
+	Edges    []Edge   `json:"edges"`
+	PageInfo PageInfo `json:"pageInfo"`
+}
+
+type Edge struct {
+	Cursor string      `json:"cursor"`
+	Node   interface{} `json:"node"`
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor"`
+}
+
+// NewConnection returns the connection of [edges]. [hasNextPage] is true, if
+// there are nodes after the last edge.
+func NewConnection(edges []Edge, hasNextPage bool) *Connection {
+	c := &Connection{
+		Edges: edges,
+		PageInfo: PageInfo{
+			HasNextPage: hasNextPage,
+		},
+	}
+	if len(edges) > 0 {
+		c.PageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+	if c.Edges == nil {
+		c.Edges = []Edge{}
+	}
+	return c
+}
+
+// NewConnectionType returns the connection type of [node]. The connection
+// type is named after [node] and must be used with a *Connection.
+func NewConnectionType(node *Object) *Object {
+	edgeType := &Object{
+		Name:        node.Name + "Edge",
+		Description: fmt.Sprintf("%s of a %sConnection", node.Name, node.Name),
+		Fields: Fields{
+			"cursor": {
+				Type: NewNonNull(String),
+			},
+			"node": {
+				Type: NewNonNull(node),
+			},
+		},
+	}
+	return &Object{
+		Name:        node.Name + "Connection",
+		Description: fmt.Sprintf("Page of a list of %s nodes", node.Name),
+		Fields: Fields{
+			"edges": {
+				Type: NewNonNull(NewList(NewNonNull(edgeType))),
+			},
+			"nodes": {
+				Type:        NewNonNull(NewList(NewNonNull(node))),
+				Description: "Nodes of the edges",
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					c := p.Source.(*Connection)
+					nodes := make([]interface{}, len(c.Edges))
+					for i, edge := range c.Edges {
+						nodes[i] = edge.Node
+					}
+					return nodes, nil
+				},
+			},
+			"pageInfo": {
+				Type: NewNonNull(pageInfoType),
+			},
+		},
+	}
+}
+
+// ConnectionArgs returns the arguments of a connection field merged with
+// [args].
+func ConnectionArgs(args Args) Args {
+	connectionArgs := Args{
+		"first": {
+			Type:         Int,
+			Description:  fmt.Sprintf("Maximum number of nodes to return. At most %d", MaxPageSize),
+			DefaultValue: DefaultPageSize,
+		},
+		"after": {
+			Type:        String,
+			Description: "Only nodes after this cursor are returned",
+		},
+	}
+	for name, arg := range args {
+		connectionArgs[name] = arg
+	}
+	return connectionArgs
+}
+
+// PageArgs returns the page size and the cursor of a connection field, whose
+// arguments were created with ConnectionArgs. The cursor is empty, if the
+// first page is requested.
+func PageArgs(p ResolveParams) (int, string, error) {
+	first, ok := p.Args["first"].(int)
+	if !ok {
+		first = DefaultPageSize
+	}
+	if first < 0 || first > MaxPageSize {
+		return 0, "", fmt.Errorf("%w: %d isn't in [0, %d]", errInvalidPageSize, first, MaxPageSize)
+	}
+	after, _ := p.Args["after"].(string)
+	return first, after, nil
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// Maximum depth of nested selection sets of a query
+	maxSelectionDepth = 16
+	// Maximum cost of a query. See queryCost.
+	maxQueryCost = 1 << 16
+)
+
+// costCalculator calculates the cost of an operation before it's executed.
+//
+// Every selected field costs 1. The selections of a connection field are
+// counted once per node of the requested page, so that nested connections
+// multiply their costs. Other list fields are counted once, as the number of
+// their items is bounded by the state, rather than the query.
+type costCalculator struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	// Default values of the variables of the operation
+	defaultValues map[string]ast.Value
+}
+
+// queryCost returns the cost of [op]. Returns an error if the depth of [op]
+// exceeds maxSelectionDepth or if its cost exceeds maxQueryCost.
+func queryCost(
+	doc *ast.Document,
+	op *ast.OperationDefinition,
+	query *Object,
+	variables map[string]interface{},
+) (uint64, error) {
+	c := &costCalculator{
+		fragments:     map[string]*ast.FragmentDefinition{},
+		variables:     variables,
+		defaultValues: map[string]ast.Value{},
+	}
+	for _, def := range op.VariableDefinitions {
+		if def.DefaultValue != nil {
+			c.defaultValues[def.Variable.Name.Value] = def.DefaultValue
+		}
+	}
+	for _, def := range doc.Definitions {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok && fragment.Name != nil {
+			c.fragments[fragment.Name.Value] = fragment
+		}
+	}
+	return c.selectionSetCost(query, op.SelectionSet, 1)
+}
+
+func (c *costCalculator) selectionSetCost(object *Object, selectionSet *ast.SelectionSet, depth int) (uint64, error) {
+	if selectionSet == nil || len(selectionSet.Selections) == 0 {
+		return 0, nil
+	}
+	if depth > maxSelectionDepth {
+		return 0, newError(selectionLoc(selectionSet.Selections[0]), "query exceeds the maximum depth of %d", maxSelectionDepth)
+	}
+
+	var cost uint64
+	for _, selection := range selectionSet.Selections {
+		var (
+			selectionCost uint64
+			err           error
+		)
+		switch selection := selection.(type) {
+		case *ast.Field:
+			selectionCost, err = c.fieldCost(object, selection, depth)
+		case *ast.InlineFragment:
+			selectionCost, err = c.selectionSetCost(object, selection.SelectionSet, depth)
+		case *ast.FragmentSpread:
+			// Fragment cycles are rejected by the validation.
+			fragment, ok := c.fragments[selection.Name.Value]
+			if !ok {
+				continue
+			}
+			selectionCost, err = c.selectionSetCost(object, fragment.SelectionSet, depth)
+		}
+		if err != nil {
+			return 0, err
+		}
+		cost += selectionCost
+		if cost > maxQueryCost {
+			return 0, newError(selectionLoc(selection), "query exceeds the maximum cost of %d", maxQueryCost)
+		}
+	}
+	return cost, nil
+}
+
+func (c *costCalculator) fieldCost(object *Object, f *ast.Field, depth int) (uint64, error) {
+	var (
+		def         *Field
+		fieldObject *Object
+	)
+	if object != nil {
+		def = object.Fields[f.Name.Value]
+	}
+	if def != nil {
+		fieldObject = namedObject(def.Type)
+	}
+	// Introspection fields aren't defined by the schema, so their selections
+	// are counted without the definitions of their fields.
+	selectionsCost, err := c.selectionSetCost(fieldObject, f.SelectionSet, depth+1)
+	if err != nil {
+		return 0, err
+	}
+	if def != nil && isConnectionField(def) {
+		selectionsCost *= c.pageSize(f)
+	}
+	return 1 + selectionsCost, nil
+}
+
+// pageSize returns the number of nodes that the connection field [f] returns
+// at most. Invalid page sizes are counted as one more than MaxPageSize, as
+// they fail once the field is resolved.
+func (c *costCalculator) pageSize(f *ast.Field) uint64 {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		var value interface{}
+		switch v := arg.Value.(type) {
+		case *ast.Variable:
+			var ok bool
+			if value, ok = c.variables[v.Name.Value]; ok {
+				value = normalizeInput(value)
+			} else if defaultValue, ok := c.defaultValues[v.Name.Value]; ok {
+				value = valueFromLiteral(defaultValue)
+			}
+		default:
+			value = valueFromLiteral(v)
+		}
+		if value == nil {
+			return DefaultPageSize
+		}
+		first, ok := value.(int64)
+		if !ok || first < 0 || first > MaxPageSize {
+			return MaxPageSize + 1
+		}
+		return uint64(first)
+	}
+	return DefaultPageSize
+}
+
+func selectionLoc(selection ast.Selection) *ast.Location {
+	if node, ok := selection.(ast.Node); ok {
+		return node.GetLoc()
+	}
+	return nil
+}
+
+// isConnectionField returns true if [f] returns a page of a connection.
+func isConnectionField(f *Field) bool {
+	_, ok := f.Args["first"]
+	return ok
+}
+
+// namedObject returns the object that [t] wraps, or nil if [t] doesn't wrap an
+// object.
+func namedObject(t Type) *Object {
+	for {
+		switch typ := t.(type) {
+		case *List:
+			t = typ.OfType
+		case *NonNull:
+			t = typ.OfType
+		case *Object:
+			return typ
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"bytes"
+	"context"
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+var (
+	_ error               = (*Error)(nil)
+	_ stdjson.Unmarshaler = (*Request)(nil)
+	_ context.Context     = detachedContext{}
+
+	nullData = stdjson.RawMessage("null")
+)
+
+// Request is a GraphQL request. See
+// https://github.com/graphql/graphql-over-http/blob/main/spec/GraphQLOverHTTP.md#request-parameters.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// UnmarshalJSON unmarshals the request and keeps the precision of numbers in
+// the variables.
+func (r *Request) UnmarshalJSON(b []byte) error {
+	type request Request
+	decoder := stdjson.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	return decoder.Decode((*request)(r))
+}
+
+// Response is the result of a request. Data is missing, if the request
+// couldn't be executed.
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is an error, that occurred while parsing or executing a request.
+type Error struct {
+	Message   string     `json:"message"`
+	Locations []Location `json:"locations,omitempty"`
+	// Response keys and list indices of the field, that caused the error
+	Path []interface{} `json:"path,omitempty"`
+}
+
+// Location is a position in a query. Lines and columns start at 1.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// newError returns the error with the message [format] at [loc].
+func newError(loc *ast.Location, format string, args ...interface{}) *Error {
+	err := &Error{Message: fmt.Sprintf(format, args...)}
+	if loc != nil && loc.Source != nil {
+		sourceLoc := location.GetLocation(loc.Source, loc.Start)
+		err.Locations = []Location{{Line: sourceLoc.Line, Column: sourceLoc.Column}}
+	}
+	return err
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Message)
+	for _, loc := range e.Locations {
+		fmt.Fprintf(&sb, " (line %d, column %d)", loc.Line, loc.Column)
+	}
+	return sb.String()
+}
+
+// Execute executes [request]. The query is parsed and validated, and its
+// depth and cost are checked, before any resolver is called.
+func (s *Schema) Execute(ctx context.Context, request *Request) *Response {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(request.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return &Response{Errors: convertErrors(gqlerrors.FormatErrors(err))}
+	}
+	if result := gql.ValidateDocument(&s.schema, doc, nil); !result.IsValid {
+		return &Response{Errors: convertErrors(result.Errors)}
+	}
+	op, err := selectOperation(doc, request.OperationName)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if op.Operation != ast.OperationTypeQuery {
+		return errorResponse(newError(op.Loc, "%s operations aren't supported", op.Operation))
+	}
+
+	variables := make(map[string]interface{}, len(request.Variables))
+	for name, value := range request.Variables {
+		variables[name] = normalizeInput(value)
+	}
+	if _, err := queryCost(doc, op, s.query, variables); err != nil {
+		return errorResponse(err)
+	}
+
+	result := gql.Execute(gql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          variables,
+		// The executor stops waiting for the resolvers once the context is
+		// done, but doesn't stop them. As the resolvers must only be called
+		// while the caller holds the chain lock, the context isn't cancelled.
+		Context: detachedContext{ctx},
+	})
+	response := &Response{
+		Data:   result.Data,
+		Errors: convertErrors(result.Errors),
+	}
+	if result.Data == nil && len(response.Errors) > 0 && len(response.Errors[0].Path) > 0 {
+		// A non-null field of the query type is null.
+		response.Data = nullData
+	}
+	return response
+}
+
+func selectOperation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var selected *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if selected != nil {
+				return nil, &Error{Message: "operation name is required, if the document contains multiple operations"}
+			}
+			selected = op
+			continue
+		}
+		if op.Name != nil && op.Name.Value == name {
+			return op, nil
+		}
+	}
+	if selected == nil {
+		return nil, &Error{Message: fmt.Sprintf("unknown operation %q", name)}
+	}
+	return selected, nil
+}
+
+func errorResponse(err error) *Response {
+	gqlErr, ok := err.(*Error)
+	if !ok {
+		gqlErr = &Error{Message: err.Error()}
+	}
+	return &Response{Errors: []*Error{gqlErr}}
+}
+
+func convertErrors(errs []gqlerrors.FormattedError) []*Error {
+	if len(errs) == 0 {
+		return nil
+	}
+	converted := make([]*Error, len(errs))
+	for i, err := range errs {
+		converted[i] = &Error{
+			Message: err.Message,
+			Path:    err.Path,
+		}
+		for _, loc := range err.Locations {
+			converted[i].Locations = append(converted[i].Locations, Location{
+				Line:   loc.Line,
+				Column: loc.Column,
+			})
+		}
+	}
+	return converted
+}
+
+// detachedContext passes the values of its parent context, but is never
+// cancelled.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// DefaultResolve returns the field [name] of [source]. [source] can be a map
+// with string keys or a struct. Struct fields are matched by the name in their
+// json tag or by their name. The fields of embedded structs without json tag
+// are matched too.
+func DefaultResolve(source interface{}, name string) interface{} {
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !value.IsValid() {
+			return nil
+		}
+		return value.Interface()
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			structField := t.Field(i)
+			if !structField.IsExported() {
+				continue
+			}
+			tagName, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+			if tagName == name || (tagName == "" && strings.EqualFold(structField.Name, name)) {
+				return v.Field(i).Interface()
+			}
+		}
+		for i := 0; i < t.NumField(); i++ {
+			structField := t.Field(i)
+			if structField.Anonymous && structField.IsExported() && structField.Tag.Get("json") == "" {
+				if value := DefaultResolve(v.Field(i).Interface(), name); value != nil {
+					return value
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeInput converts the numbers of the input value [value] to int64 or,
+// if they are out of its range, to uint64 or float64.
+func normalizeInput(value interface{}) interface{} {
+	switch v := value.(type) {
+	case stdjson.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(string(v), 10, 64); err == nil {
+			return u
+		}
+		f, _ := v.Float64()
+		return f
+	case uint64:
+		if v > math.MaxInt64 {
+			return v
+		}
+		return int64(v)
+	case uint:
+		return normalizeInput(uint64(v))
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+		return v
+	case float32:
+		return normalizeInput(float64(v))
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = normalizeInput(item)
+		}
+		return list
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = normalizeInput(item)
+		}
+		return object
+	}
+	if i, ok := toInt64(value); ok {
+		return i
+	}
+	return value
+}
+
+// coerceInput returns the value of type [t] from the input value [value].
+func coerceInput(t Type, value interface{}) (interface{}, error) {
+	switch t := t.(type) {
+	case *NonNull:
+		if value == nil {
+			return nil, fmt.Errorf("expected non-null value of type %s", t)
+		}
+		return coerceInput(t.OfType, value)
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items, ok := value.([]interface{})
+		if !ok {
+			// A single value is coerced to a list with one item.
+			item, err := coerceInput(t.OfType, value)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		coerced := make([]interface{}, len(items))
+		for i, item := range items {
+			var err error
+			if coerced[i], err = coerceInput(t.OfType, item); err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		return coerced, nil
+	case *Scalar:
+		if t.Parse == nil {
+			return value, nil
+		}
+		parsed, err := t.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of type %s: %w", t, err)
+		}
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("invalid input type %s", t)
+	}
+}
@@ -0,0 +1,404 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errTestResolve = errors.New("resolve failed")
+
+type testCharacter struct {
+	ID        string `json:"id"`
+	Name      string
+	FriendIDs []string `json:"-"`
+}
+
+var testCharacters = []*testCharacter{
+	{ID: "1", Name: "Alice", FriendIDs: []string{"2", "3"}},
+	{ID: "2", Name: "Bob", FriendIDs: []string{"1"}},
+	{ID: "3", Name: "Carol"},
+}
+
+func getTestCharacter(id string) *testCharacter {
+	for _, c := range testCharacters {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func newTestSchema(t *testing.T) *Schema {
+	characterType := &Object{
+		Name:        "Character",
+		Description: "A character",
+	}
+	characterType.Fields = Fields{
+		"id": {
+			Type: NewNonNull(ID),
+		},
+		"name": {
+			Type: NewNonNull(String),
+		},
+		"friends": {
+			Type: NewNonNull(NewList(NewNonNull(characterType))),
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				var friends []*testCharacter
+				for _, id := range p.Source.(*testCharacter).FriendIDs {
+					friends = append(friends, getTestCharacter(id))
+				}
+				return friends, nil
+			},
+		},
+		"friendsPage": {
+			Type: NewNonNull(NewConnectionType(characterType)),
+			Args: ConnectionArgs(nil),
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				first, _, err := PageArgs(p)
+				if err != nil {
+					return nil, err
+				}
+				var edges []Edge
+				for _, id := range p.Source.(*testCharacter).FriendIDs {
+					if len(edges) == first {
+						break
+					}
+					edges = append(edges, Edge{Cursor: id, Node: getTestCharacter(id)})
+				}
+				return NewConnection(edges, false), nil
+			},
+		},
+		"friendsWithMissing": {
+			Type: NewList(NewNonNull(characterType)),
+			Resolve: func(p ResolveParams) (interface{}, error) {
+				return []*testCharacter{getTestCharacter("1"), nil}, nil
+			},
+		},
+	}
+
+	schema, err := NewSchema(&Object{
+		Name: "Query",
+		Fields: Fields{
+			"character": {
+				Type: characterType,
+				Args: Args{
+					"id": {Type: NewNonNull(ID)},
+				},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return getTestCharacter(p.Args["id"].(string)), nil
+				},
+			},
+			"characters": {
+				Type: NewNonNull(characterType.Fields["friendsPage"].Type.(*NonNull).OfType),
+				Args: ConnectionArgs(nil),
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					first, after, err := PageArgs(p)
+					if err != nil {
+						return nil, err
+					}
+					start := 0
+					if after != "" {
+						if start, err = strconv.Atoi(after); err != nil {
+							return nil, err
+						}
+					}
+					var edges []Edge
+					for i := start; i < len(testCharacters) && len(edges) < first; i++ {
+						edges = append(edges, Edge{
+							Cursor: strconv.Itoa(i + 1),
+							Node:   testCharacters[i],
+						})
+					}
+					return NewConnection(edges, start+len(edges) < len(testCharacters)), nil
+				},
+			},
+			"failing": {
+				Type: String,
+				Resolve: func(ResolveParams) (interface{}, error) {
+					return nil, errTestResolve
+				},
+			},
+			"failingNonNull": {
+				Type: NewNonNull(String),
+				Resolve: func(ResolveParams) (interface{}, error) {
+					return nil, errTestResolve
+				},
+			},
+			"echo": {
+				Type: NewList(Uint64),
+				Args: Args{
+					"values": {
+						Type:         NewList(NewNonNull(Uint64)),
+						DefaultValue: []interface{}{1},
+					},
+				},
+				Resolve: func(p ResolveParams) (interface{}, error) {
+					return p.Args["values"], nil
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	return schema
+}
+
+func TestExecute(t *testing.T) {
+	tests := map[string]struct {
+		request  *Request
+		expected string
+	}{
+		"fields, aliases and __typename": {
+			request: &Request{Query: `{
+				character(id: "1") { __typename id name }
+				other: character(id: 2) { name }
+				missing: character(id: "4") { name }
+			}`},
+			expected: `{"data":{"character":{"__typename":"Character","id":"1","name":"Alice"},"other":{"name":"Bob"},"missing":null}}`,
+		},
+		"nested lists": {
+			request:  &Request{Query: `{ character(id: "1") { friends { name friends { id } } } }`},
+			expected: `{"data":{"character":{"friends":[{"name":"Bob","friends":[{"id":"1"}]},{"name":"Carol","friends":[]}]}}}`,
+		},
+		"fragments and directives": {
+			request: &Request{
+				Query: `query Q($withName: Boolean!) {
+					character(id: "2") { ...Fields ... on Character { id } ... @skip(if: true) { friends { id } } }
+				}
+				fragment Fields on Character { name @include(if: $withName) id }`,
+				Variables: map[string]interface{}{"withName": true},
+			},
+			expected: `{"data":{"character":{"name":"Bob","id":"2"}}}`,
+		},
+		"variables and defaults": {
+			request: &Request{
+				Query: `query ($values: [Uint64!], $first: Int = 2) {
+					a: echo(values: $values)
+					b: echo
+					c: echo(values: 5)
+					characters(first: $first) { nodes { id } }
+				}`,
+				Variables: map[string]interface{}{"values": []interface{}{stdjson.Number("18446744073709551615"), stdjson.Number("2")}},
+			},
+			expected: `{"data":{"a":["18446744073709551615","2"],"b":["1"],"c":["5"],"characters":{"nodes":[{"id":"1"},{"id":"2"}]}}}`,
+		},
+		"pagination": {
+			request:  &Request{Query: `{ characters(first: 2, after: "1") { edges { cursor node { name } } pageInfo { hasNextPage endCursor } } }`},
+			expected: `{"data":{"characters":{"edges":[{"cursor":"2","node":{"name":"Bob"}},{"cursor":"3","node":{"name":"Carol"}}],"pageInfo":{"hasNextPage":false,"endCursor":"3"}}}}`,
+		},
+		"empty page": {
+			request:  &Request{Query: `{ characters(first: 0) { nodes { id } pageInfo { hasNextPage endCursor } } }`},
+			expected: `{"data":{"characters":{"nodes":[],"pageInfo":{"hasNextPage":true,"endCursor":null}}}}`,
+		},
+		"nullable field error": {
+			request:  &Request{Query: `{ failing character(id: "3") { id } }`},
+			expected: `{"data":{"failing":null,"character":{"id":"3"}},"errors":[{"message":"resolve failed","locations":[{"line":1,"column":3}],"path":["failing"]}]}`,
+		},
+		"non-null field error propagates": {
+			request:  &Request{Query: `{ failingNonNull }`},
+			expected: `{"data":null,"errors":[{"message":"resolve failed","locations":[{"line":1,"column":3}],"path":["failingNonNull"]}]}`,
+		},
+		"null list item propagates": {
+			request:  &Request{Query: `{ character(id: "3") { friendsWithMissing { id } } }`},
+			expected: `{"data":{"character":{"friendsWithMissing":null}},"errors":[{"message":"Cannot return null for non-nullable field Character.friendsWithMissing.","locations":[{"line":1,"column":24}],"path":["character","friendsWithMissing",1]}]}`,
+		},
+		"unknown field": {
+			request:  &Request{Query: `{ character(id: "1") { age } }`},
+			expected: `{"errors":[{"message":"Cannot query field \"age\" on type \"Character\". Did you mean \"name\"?","locations":[{"line":1,"column":24}]}]}`,
+		},
+		"missing required argument": {
+			request:  &Request{Query: `{ character { id } }`},
+			expected: `{"errors":[{"message":"Field \"character\" argument \"id\" of type \"ID!\" is required but not provided.","locations":[{"line":1,"column":3}]}]}`,
+		},
+		"invalid page size": {
+			request:  &Request{Query: `{ characters(first: 1025) { nodes { id } } }`},
+			expected: `{"data":null,"errors":[{"message":"invalid page size: 1025 isn't in [0, 1024]","locations":[{"line":1,"column":3}],"path":["characters"]}]}`,
+		},
+		"missing variable": {
+			request:  &Request{Query: `query ($id: ID!) { character(id: $id) { id } }`},
+			expected: `{"errors":[{"message":"Variable \"$id\" of required type \"ID!\" was not provided.","locations":[{"line":1,"column":8}]}]}`,
+		},
+		"syntax error": {
+			request:  &Request{Query: `{ character(id: "1") { id }`},
+			expected: `{"errors":[{"message":"Syntax Error GraphQL request (1:28) Expected Name, found EOF\n\n1: { character(id: \"1\") { id }\n                              ^\n","locations":[{"line":1,"column":28}]}]}`,
+		},
+		"mutation": {
+			request:  &Request{Query: `mutation { character(id: "1") { id } }`},
+			expected: `{"errors":[{"message":"mutation operations aren't supported","locations":[{"line":1,"column":1}]}]}`,
+		},
+		"introspection": {
+			request:  &Request{Query: `{ __type(name: "Character") { name description } }`},
+			expected: `{"data":{"__type":{"name":"Character","description":"A character"}}}`,
+		},
+		"operation name": {
+			request: &Request{
+				Query:         `query A { character(id: "1") { id } } query B { character(id: "2") { id } }`,
+				OperationName: "B",
+			},
+			expected: `{"data":{"character":{"id":"2"}}}`,
+		},
+		"depth limit": {
+			request:  &Request{Query: `{ character(id: "1") { ` + strings.Repeat("friends { ", 15) + "id" + strings.Repeat(" }", 16) + ` }`},
+			expected: `{"errors":[{"message":"query exceeds the maximum depth of 16","locations":[{"line":1,"column":174}]}]}`,
+		},
+		"max depth": {
+			request:  &Request{Query: `{ character(id: "3") { ` + strings.Repeat("friends { ", 14) + "id" + strings.Repeat(" }", 15) + ` }`},
+			expected: `{"data":{"character":{"friends":[]}}}`,
+		},
+		"cost limit": {
+			request:  &Request{Query: `{ characters(first: 1024) { nodes { friendsPage(first: 64) { nodes { id } } } } }`},
+			expected: `{"errors":[{"message":"query exceeds the maximum cost of 65536","locations":[{"line":1,"column":3}]}]}`,
+		},
+		"cost limit with variables": {
+			request: &Request{
+				Query:     `query ($first: Int) { characters(first: $first) { nodes { friendsPage(first: 1024) { nodes { id } } } } }`,
+				Variables: map[string]interface{}{"first": stdjson.Number("64")},
+			},
+			expected: `{"errors":[{"message":"query exceeds the maximum cost of 65536","locations":[{"line":1,"column":23}]}]}`,
+		},
+		"nested connections within the cost limit": {
+			request:  &Request{Query: `{ characters(first: 1) { nodes { friendsPage(first: 1) { nodes { name } } } } }`},
+			expected: `{"data":{"characters":{"nodes":[{"friendsPage":{"nodes":[{"name":"Bob"}]}}]}}}`,
+		},
+	}
+
+	schema := newTestSchema(t)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := schema.Execute(context.Background(), test.request)
+			responseBytes, err := stdjson.Marshal(response)
+			require.NoError(t, err)
+			require.JSONEq(t, test.expected, string(responseBytes))
+		})
+	}
+}
+
+func TestDefaultResolve(t *testing.T) {
+	type Embedded struct {
+		A int
+		B int `json:"c"`
+	}
+	type source struct {
+		Embedded
+		A int `json:"-"`
+		D string
+	}
+	tests := map[string]struct {
+		source   interface{}
+		name     string
+		expected interface{}
+	}{
+		"map": {
+			source:   map[string]int{"a": 1},
+			name:     "a",
+			expected: 1,
+		},
+		"field by name": {
+			source:   &source{D: "d"},
+			name:     "d",
+			expected: "d",
+		},
+		"embedded field by json tag": {
+			source:   source{Embedded: Embedded{B: 2}},
+			name:     "c",
+			expected: 2,
+		},
+		"ignored field": {
+			source:   source{A: 1, Embedded: Embedded{A: 2}},
+			name:     "A",
+			expected: 2,
+		},
+		"nil": {
+			source: (*source)(nil),
+			name:   "d",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, test.expected, DefaultResolve(test.source, test.name))
+		})
+	}
+}
+
+func TestNewSchemaInvalid(t *testing.T) {
+	tests := map[string]struct {
+		query       *Object
+		expectedErr error
+	}{
+		"no query": {
+			expectedErr: errNoQueryType,
+		},
+		"invalid field name": {
+			query: &Object{
+				Name:   "Query",
+				Fields: Fields{"__a": {Type: String}},
+			},
+			expectedErr: errInvalidName,
+		},
+		"duplicate type": {
+			query: &Object{
+				Name: "Query",
+				Fields: Fields{
+					"a": {Type: &Object{Name: "A", Fields: Fields{"b": {Type: String}}}},
+					"b": {Type: &Object{Name: "A", Fields: Fields{"b": {Type: String}}}},
+				},
+			},
+			expectedErr: errDuplicateType,
+		},
+		"object argument": {
+			query: &Object{
+				Name: "Query",
+				Fields: Fields{
+					"a": {
+						Type: String,
+						Args: Args{"b": {Type: &Object{Name: "B"}}},
+					},
+				},
+			},
+			expectedErr: errInvalidArgType,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewSchema(test.query)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestSchemaString(t *testing.T) {
+	schema, err := NewSchema(&Object{
+		Name: "Query",
+		Fields: Fields{
+			"amount": {
+				Type:        NewNonNull(Uint64),
+				Description: "An amount",
+				Args: Args{
+					"id":    {Type: NewNonNull(ID)},
+					"after": {Type: String, DefaultValue: "a"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `schema {
+  query: Query
+}
+
+type Query {
+  "An amount"
+  amount(after: String = "a", id: ID!): Uint64!
+}
+
+"Unsigned 64-bit integer, that is serialized as a string"
+scalar Uint64
+`, schema.String())
+}
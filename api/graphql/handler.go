@@ -0,0 +1,83 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// Maximum size of the body of a request
+const maxRequestSize = units.MiB
+
+var _ http.Handler = (*handler)(nil)
+
+type handler struct {
+	schema *Schema
+}
+
+// NewHandler returns a handler, that executes requests to [schema]. Requests
+// are either POST requests with a JSON encoded Request as body or GET
+// requests with the query, operationName and variables URL parameters. GET
+// requests without a query return the schema in the schema definition
+// language.
+func NewHandler(schema *Schema) http.Handler {
+	return &handler{schema: schema}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request := &Request{}
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		if !query.Has("query") {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = io.WriteString(w, h.schema.String())
+			return
+		}
+		request.Query = query.Get("query")
+		request.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			decoder := stdjson.NewDecoder(strings.NewReader(variables))
+			decoder.UseNumber()
+			if err := decoder.Decode(&request.Variables); err != nil {
+				writeResponse(w, http.StatusBadRequest, errorResponse(fmt.Errorf("invalid variables: %w", err)))
+				return
+			}
+		}
+	case http.MethodPost:
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+		if err != nil {
+			writeResponse(w, http.StatusRequestEntityTooLarge, errorResponse(fmt.Errorf("couldn't read request: %w", err)))
+			return
+		}
+		if err := stdjson.Unmarshal(body, request); err != nil {
+			writeResponse(w, http.StatusBadRequest, errorResponse(fmt.Errorf("invalid request: %w", err)))
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := h.schema.Execute(r.Context(), request)
+	status := http.StatusOK
+	if response.Data == nil {
+		// The request couldn't be executed.
+		status = http.StatusBadRequest
+	}
+	writeResponse(w, status, response)
+}
+
+func writeResponse(w http.ResponseWriter, status int, response *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = stdjson.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	tests := map[string]struct {
+		request            *http.Request
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		"get schema": {
+			request:            httptest.NewRequest(http.MethodGet, "/", nil),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       newTestSchema(t).String(),
+		},
+		"get query": {
+			request: httptest.NewRequest(http.MethodGet, "/?"+url.Values{
+				"query":     {`query ($id: ID!) { character(id: $id) { name } }`},
+				"variables": {`{"id": 2}`},
+			}.Encode(), nil),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":{"character":{"name":"Bob"}}}` + "\n",
+		},
+		"post query": {
+			request:            httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query": "{ character(id: \"1\") { name } }"}`)),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"data":{"character":{"name":"Alice"}}}` + "\n",
+		},
+		"post invalid request": {
+			request:            httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{`)),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"errors":[{"message":"invalid request: unexpected end of JSON input"}]}` + "\n",
+		},
+		"query error": {
+			request:            httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"query": "{"}`)),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"errors":[{"message":"Syntax Error GraphQL request (1:2) Expected Name, found EOF\n\n1: {\n    ^\n","locations":[{"line":1,"column":2}]}]}` + "\n",
+		},
+		"unsupported method": {
+			request:            httptest.NewRequest(http.MethodPut, "/", nil),
+			expectedStatusCode: http.StatusMethodNotAllowed,
+		},
+	}
+	handler := NewHandler(newTestSchema(t))
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, test.request)
+			require.Equal(t, test.expectedStatusCode, w.Code)
+			require.Equal(t, test.expectedBody, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+var (
+	errNotString  = errors.New("not a string")
+	errNotInt     = errors.New("not an int")
+	errNotFloat   = errors.New("not a float")
+	errNotBoolean = errors.New("not a boolean")
+	errNotID      = errors.New("not an ID")
+	errNotUint64  = errors.New("not an unsigned 64-bit integer")
+
+	// The built-in scalars. See
+	// https://spec.graphql.org/October2021/#sec-Scalars.Built-in-Scalars.
+
+	String = &Scalar{
+		Name: "String",
+		Parse: func(value interface{}) (interface{}, error) {
+			s, ok := value.(string)
+			if !ok {
+				return nil, errNotString
+			}
+			return s, nil
+		},
+	}
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(value interface{}) (interface{}, error) {
+			i, ok := toInt64(value)
+			if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+				return nil, fmt.Errorf("%w: %v", errNotInt, value)
+			}
+			return i, nil
+		},
+		Parse: func(value interface{}) (interface{}, error) {
+			i, ok := value.(int64)
+			if !ok || i < math.MinInt32 || i > math.MaxInt32 {
+				return nil, errNotInt
+			}
+			return int(i), nil
+		},
+	}
+	Float = &Scalar{
+		Name: "Float",
+		Parse: func(value interface{}) (interface{}, error) {
+			switch value := value.(type) {
+			case int64:
+				return float64(value), nil
+			case uint64:
+				return float64(value), nil
+			case float64:
+				return value, nil
+			default:
+				return nil, errNotFloat
+			}
+		},
+	}
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Parse: func(value interface{}) (interface{}, error) {
+			b, ok := value.(bool)
+			if !ok {
+				return nil, errNotBoolean
+			}
+			return b, nil
+		},
+	}
+	ID = &Scalar{
+		Name: "ID",
+		Serialize: func(value interface{}) (interface{}, error) {
+			if s, ok := value.(fmt.Stringer); ok {
+				return s.String(), nil
+			}
+			return value, nil
+		},
+		Parse: func(value interface{}) (interface{}, error) {
+			switch value := value.(type) {
+			case string:
+				return value, nil
+			case int64:
+				return strconv.FormatInt(value, 10), nil
+			default:
+				return nil, errNotID
+			}
+		},
+	}
+
+	// Uint64 is an unsigned 64-bit integer. It's serialized as a string,
+	// because JSON numbers can't represent all of its values exactly.
+	Uint64 = &Scalar{
+		Name:        "Uint64",
+		Description: "Unsigned 64-bit integer, that is serialized as a string",
+		Serialize: func(value interface{}) (interface{}, error) {
+			switch value := value.(type) {
+			case uint64:
+				return json.Uint64(value), nil
+			case json.Uint64:
+				return value, nil
+			}
+			i, ok := toInt64(value)
+			if !ok || i < 0 {
+				return nil, fmt.Errorf("%w: %v", errNotUint64, value)
+			}
+			return json.Uint64(i), nil
+		},
+		Parse: func(value interface{}) (interface{}, error) {
+			switch value := value.(type) {
+			case string:
+				u, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					return nil, errNotUint64
+				}
+				return u, nil
+			case int64:
+				if value < 0 {
+					return nil, errNotUint64
+				}
+				return uint64(value), nil
+			case uint64:
+				return value, nil
+			default:
+				return nil, errNotUint64
+			}
+		},
+	}
+	// JSON is an arbitrary JSON value. The values returned by resolvers are
+	// marshalled as they are.
+	JSON = &Scalar{
+		Name:        "JSON",
+		Description: "Arbitrary JSON value",
+		Parse: func(value interface{}) (interface{}, error) {
+			return value, nil
+		},
+	}
+)
+
+func isBuiltinScalar(s *Scalar) bool {
+	return s == String || s == Int || s == Float || s == Boolean || s == ID
+}
+
+// toInt64 converts the signed or unsigned integer [value] to an int64.
+func toInt64(value interface{}) (int64, bool) {
+	switch value := value.(type) {
+	case int:
+		return int64(value), true
+	case int8:
+		return int64(value), true
+	case int16:
+		return int64(value), true
+	case int32:
+		return int64(value), true
+	case int64:
+		return value, true
+	case uint:
+		return int64(value), uint64(value) <= math.MaxInt64
+	case uint8:
+		return int64(value), true
+	case uint16:
+		return int64(value), true
+	case uint32:
+		return int64(value), true
+	case uint64:
+		return int64(value), value <= math.MaxInt64
+	default:
+		return 0, false
+	}
+}
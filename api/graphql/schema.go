@@ -0,0 +1,356 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/maps"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+var (
+	_ Type = (*Scalar)(nil)
+	_ Type = (*Object)(nil)
+	_ Type = (*List)(nil)
+	_ Type = (*NonNull)(nil)
+
+	namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+	errNoQueryType      = errors.New("schema has no query type")
+	errInvalidName      = errors.New("invalid name")
+	errDuplicateType    = errors.New("duplicate type name")
+	errInvalidFieldType = errors.New("invalid field type")
+	errInvalidArgType   = errors.New("invalid argument type")
+)
+
+// Type is a type of a schema. It's a *Scalar, *Object, *List or *NonNull.
+type Type interface {
+	// String returns the reference to the type, as it is written in queries
+	// (e.g. "[ID!]").
+	String() string
+}
+
+// Scalar is a leaf type.
+type Scalar struct {
+	Name        string
+	Description string
+	// Serialize returns the value of the scalar, that is marshalled into the
+	// response, from the value returned by a resolver. If nil, the value
+	// returned by the resolver is marshalled.
+	Serialize func(value interface{}) (interface{}, error)
+	// Parse returns the value of the scalar, that is passed to resolvers,
+	// from an input value. The input value is an int64, float64, string,
+	// bool, []interface{} or map[string]interface{}. Integers, that don't fit
+	// into an int64, are passed as uint64, if possible. Enum values are
+	// passed as strings.
+	Parse func(value interface{}) (interface{}, error)
+}
+
+func (s *Scalar) String() string {
+	return s.Name
+}
+
+// Object is an output type with fields.
+type Object struct {
+	Name        string
+	Description string
+	// Fields can be set after the object was created, so that objects can
+	// reference each other.
+	Fields Fields
+}
+
+func (o *Object) String() string {
+	return o.Name
+}
+
+// Fields of an object by their name.
+type Fields map[string]*Field
+
+type Field struct {
+	Type        Type
+	Description string
+	Args        Args
+	// Resolve returns the value of the field. If nil, the field is read from
+	// the source value. See DefaultResolve.
+	Resolve ResolveFunc
+}
+
+// Args are the arguments of a field by their name.
+type Args map[string]*Argument
+
+type Argument struct {
+	Type        Type
+	Description string
+	// Value used, if the argument isn't given. If nil, the argument has no
+	// default value.
+	DefaultValue interface{}
+}
+
+// ResolveParams are passed to resolvers.
+type ResolveParams struct {
+	Context context.Context
+	// Value of the object, whose field is resolved
+	Source interface{}
+	// Values of the arguments of the field. Arguments, that weren't given and
+	// don't have a default value, are missing.
+	Args map[string]interface{}
+}
+
+type ResolveFunc func(p ResolveParams) (interface{}, error)
+
+// List is a list of values of another type.
+type List struct {
+	OfType Type
+}
+
+func NewList(ofType Type) *List {
+	return &List{OfType: ofType}
+}
+
+func (l *List) String() string {
+	return "[" + l.OfType.String() + "]"
+}
+
+// NonNull is a type, whose values can't be null.
+type NonNull struct {
+	OfType Type
+}
+
+func NewNonNull(ofType Type) *NonNull {
+	return &NonNull{OfType: ofType}
+}
+
+func (n *NonNull) String() string {
+	return n.OfType.String() + "!"
+}
+
+// Schema executes queries. See https://spec.graphql.org/October2021.
+//
+// Only queries are supported. The schema can be introspected or printed as
+// SDL with String.
+type Schema struct {
+	query *Object
+	// Named types by their name
+	types map[string]Type
+	// The schema of the executor
+	schema gql.Schema
+}
+
+// NewSchema returns the schema, whose queries start at [query]. Returns an
+// error, if the schema is invalid.
+func NewSchema(query *Object) (*Schema, error) {
+	if query == nil {
+		return nil, errNoQueryType
+	}
+	s := &Schema{
+		query: query,
+		types: map[string]Type{
+			String.Name:  String,
+			Int.Name:     Int,
+			Float.Name:   Float,
+			Boolean.Name: Boolean,
+			ID.Name:      ID,
+		},
+	}
+	if err := s.addType(query); err != nil {
+		return nil, err
+	}
+
+	var err error
+	s.schema, err = gql.NewSchema(gql.SchemaConfig{
+		Query: newBuilder().object(query),
+	})
+	return s, err
+}
+
+// addType adds the named type of [t] and the types, that are referenced by
+// it, to the schema.
+func (s *Schema) addType(t Type) error {
+	switch t := t.(type) {
+	case *List:
+		return s.addType(t.OfType)
+	case *NonNull:
+		return s.addType(t.OfType)
+	}
+
+	name := t.String()
+	if !namePattern.MatchString(name) || strings.HasPrefix(name, "__") {
+		return fmt.Errorf("%w: type %q", errInvalidName, name)
+	}
+	if existing, ok := s.types[name]; ok {
+		if existing != t {
+			return fmt.Errorf("%w: %q", errDuplicateType, name)
+		}
+		return nil
+	}
+	s.types[name] = t
+
+	object, ok := t.(*Object)
+	if !ok {
+		return nil
+	}
+	for fieldName, f := range object.Fields {
+		if !namePattern.MatchString(fieldName) || strings.HasPrefix(fieldName, "__") {
+			return fmt.Errorf("%w: field %s.%s", errInvalidName, name, fieldName)
+		}
+		if !isOutputType(f.Type) {
+			return fmt.Errorf("%w: %s.%s", errInvalidFieldType, name, fieldName)
+		}
+		if err := s.addType(f.Type); err != nil {
+			return err
+		}
+		for argName, arg := range f.Args {
+			if !namePattern.MatchString(argName) || strings.HasPrefix(argName, "__") {
+				return fmt.Errorf("%w: argument %s.%s(%s)", errInvalidName, name, fieldName, argName)
+			}
+			if !isInputType(arg.Type) {
+				return fmt.Errorf("%w: %s.%s(%s)", errInvalidArgType, name, fieldName, argName)
+			}
+			if err := s.addType(arg.Type); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isOutputType(t Type) bool {
+	switch t := t.(type) {
+	case *Scalar, *Object:
+		return true
+	case *List:
+		return isOutputType(t.OfType)
+	case *NonNull:
+		_, isNonNull := t.OfType.(*NonNull)
+		return !isNonNull && isOutputType(t.OfType)
+	default:
+		return false
+	}
+}
+
+func isInputType(t Type) bool {
+	switch t := t.(type) {
+	case *Scalar:
+		return true
+	case *List:
+		return isInputType(t.OfType)
+	case *NonNull:
+		_, isNonNull := t.OfType.(*NonNull)
+		return !isNonNull && isInputType(t.OfType)
+	default:
+		return false
+	}
+}
+
+// String returns the schema in the schema definition language.
+func (s *Schema) String() string {
+	sb := &strings.Builder{}
+	sb.WriteString("schema {\n  query: ")
+	sb.WriteString(s.query.Name)
+	sb.WriteString("\n}\n")
+
+	names := maps.Keys(s.types)
+	sort.Strings(names)
+	for _, name := range names {
+		switch t := s.types[name].(type) {
+		case *Scalar:
+			if isBuiltinScalar(t) {
+				continue
+			}
+			sb.WriteString("\n")
+			writeDescription(sb, "", t.Description)
+			sb.WriteString("scalar ")
+			sb.WriteString(t.Name)
+			sb.WriteString("\n")
+		case *Object:
+			sb.WriteString("\n")
+			writeDescription(sb, "", t.Description)
+			sb.WriteString("type ")
+			sb.WriteString(t.Name)
+			sb.WriteString(" {\n")
+			fieldNames := maps.Keys(t.Fields)
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				f := t.Fields[fieldName]
+				writeDescription(sb, "  ", f.Description)
+				sb.WriteString("  ")
+				sb.WriteString(fieldName)
+				writeArgs(sb, f.Args)
+				sb.WriteString(": ")
+				sb.WriteString(f.Type.String())
+				sb.WriteString("\n")
+			}
+			sb.WriteString("}\n")
+		}
+	}
+	return sb.String()
+}
+
+func writeDescription(sb *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	sb.WriteString(indent)
+	if !strings.Contains(description, "\n") {
+		sb.WriteString(strconv.Quote(description))
+		sb.WriteString("\n")
+		return
+	}
+	sb.WriteString(`"""`)
+	sb.WriteString("\n")
+	for _, line := range strings.Split(description, "\n") {
+		sb.WriteString(indent)
+		sb.WriteString(strings.ReplaceAll(line, `"""`, `\"""`))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(indent)
+	sb.WriteString(`"""`)
+	sb.WriteString("\n")
+}
+
+func writeArgs(sb *strings.Builder, args Args) {
+	if len(args) == 0 {
+		return
+	}
+	argNames := maps.Keys(args)
+	sort.Strings(argNames)
+	sb.WriteString("(")
+	for i, argName := range argNames {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		arg := args[argName]
+		sb.WriteString(argName)
+		sb.WriteString(": ")
+		sb.WriteString(arg.Type.String())
+		if arg.DefaultValue != nil {
+			sb.WriteString(" = ")
+			sb.WriteString(formatDefaultValue(arg.DefaultValue))
+		}
+	}
+	sb.WriteString(")")
+}
+
+func formatDefaultValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatDefaultValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
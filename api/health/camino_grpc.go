@@ -0,0 +1,93 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	healthv1 "github.com/ava-labs/avalanchego/proto/pb/health/v1"
+)
+
+var _ healthv1.HealthServer = (*grpcService)(nil)
+
+// grpcService serves the health API over gRPC
+type grpcService struct {
+	healthv1.UnsafeHealthServer
+	health Reporter
+}
+
+// NewGRPCService returns a server that serves the health reported by
+// [reporter] as the gRPC service health.v1.Health.
+func NewGRPCService(reporter Reporter) *grpc.Server {
+	server := grpc.NewServer()
+	healthv1.RegisterHealthServer(server, &grpcService{
+		health: reporter,
+	})
+	return server
+}
+
+func (s *grpcService) Readiness(_ context.Context, req *healthv1.ReadinessRequest) (*healthv1.ReadinessResponse, error) {
+	checks, healthy, err := newResults(s.health.Readiness(req.Tags...))
+	if err != nil {
+		return nil, err
+	}
+	return &healthv1.ReadinessResponse{
+		Checks:  checks,
+		Healthy: healthy,
+	}, nil
+}
+
+func (s *grpcService) Health(_ context.Context, req *healthv1.HealthRequest) (*healthv1.HealthResponse, error) {
+	checks, healthy, err := newResults(s.health.Health(req.Tags...))
+	if err != nil {
+		return nil, err
+	}
+	return &healthv1.HealthResponse{
+		Checks:  checks,
+		Healthy: healthy,
+	}, nil
+}
+
+func (s *grpcService) Liveness(_ context.Context, req *healthv1.LivenessRequest) (*healthv1.LivenessResponse, error) {
+	checks, healthy, err := newResults(s.health.Liveness(req.Tags...))
+	if err != nil {
+		return nil, err
+	}
+	return &healthv1.LivenessResponse{
+		Checks:  checks,
+		Healthy: healthy,
+	}, nil
+}
+
+func newResults(results map[string]Result, healthy bool) (map[string]*healthv1.Result, bool, error) {
+	pbResults := make(map[string]*healthv1.Result, len(results))
+	for name, result := range results {
+		pbResult := &healthv1.Result{
+			Timestamp:          timestamppb.New(result.Timestamp),
+			Duration:           durationpb.New(result.Duration),
+			ContiguousFailures: result.ContiguousFailures,
+		}
+		if result.Details != nil {
+			details, err := json.Marshal(result.Details)
+			if err != nil {
+				return nil, false, err
+			}
+			pbResult.Details = string(details)
+		}
+		if result.Error != nil {
+			pbResult.Error = *result.Error
+		}
+		if result.TimeOfFirstFailure != nil {
+			pbResult.TimeOfFirstFailure = timestamppb.New(*result.TimeOfFirstFailure)
+		}
+		pbResults[name] = pbResult
+	}
+	return pbResults, healthy, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+
+	healthv1 "github.com/ava-labs/avalanchego/proto/pb/health/v1"
+)
+
+var _ Client = (*grpcClient)(nil)
+
+// grpcClient implements Client with the generated health.v1.Health client
+type grpcClient struct {
+	client healthv1.HealthClient
+}
+
+// NewGRPCClient returns a new Health API Client that calls the gRPC service
+// health.v1.Health over [conn]. Only the headers of the options are sent, as
+// gRPC metadata.
+func NewGRPCClient(conn grpc.ClientConnInterface) Client {
+	return &grpcClient{client: healthv1.NewHealthClient(conn)}
+}
+
+func (c *grpcClient) Readiness(ctx context.Context, tags []string, options ...rpc.Option) (*APIReply, error) {
+	res, err := c.client.Readiness(rpc.NewGRPCContext(ctx, options), &healthv1.ReadinessRequest{
+		Tags: tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newAPIReply(res.Checks, res.Healthy)
+}
+
+func (c *grpcClient) Health(ctx context.Context, tags []string, options ...rpc.Option) (*APIReply, error) {
+	res, err := c.client.Health(rpc.NewGRPCContext(ctx, options), &healthv1.HealthRequest{
+		Tags: tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newAPIReply(res.Checks, res.Healthy)
+}
+
+func (c *grpcClient) Liveness(ctx context.Context, tags []string, options ...rpc.Option) (*APIReply, error) {
+	res, err := c.client.Liveness(rpc.NewGRPCContext(ctx, options), &healthv1.LivenessRequest{
+		Tags: tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newAPIReply(res.Checks, res.Healthy)
+}
+
+func newAPIReply(pbResults map[string]*healthv1.Result, healthy bool) (*APIReply, error) {
+	reply := &APIReply{
+		Checks:  make(map[string]Result, len(pbResults)),
+		Healthy: healthy,
+	}
+	for name, pbResult := range pbResults {
+		result := Result{
+			Timestamp:          pbResult.Timestamp.AsTime(),
+			Duration:           pbResult.Duration.AsDuration(),
+			ContiguousFailures: pbResult.ContiguousFailures,
+		}
+		if pbResult.Details != "" {
+			if err := json.Unmarshal([]byte(pbResult.Details), &result.Details); err != nil {
+				return nil, err
+			}
+		}
+		if pbResult.Error != "" {
+			result.Error = &pbResult.Error
+		}
+		if pbResult.TimeOfFirstFailure != nil {
+			timeOfFirstFailure := pbResult.TimeOfFirstFailure.AsTime()
+			result.TimeOfFirstFailure = &timeOfFirstFailure
+		}
+		reply.Checks[name] = result
+	}
+	return reply, nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	healthv1 "github.com/ava-labs/avalanchego/proto/pb/health/v1"
+)
+
+func TestGRPCService(t *testing.T) {
+	require := require.New(t)
+
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	errFailed := errors.New("failed")
+	require.NoError(h.RegisterHealthCheck("passing", CheckerFunc(func(context.Context) (interface{}, error) {
+		return map[string]int{"a": 1}, nil
+	}), "tag"))
+	require.NoError(h.RegisterHealthCheck("failing", CheckerFunc(func(context.Context) (interface{}, error) {
+		return nil, errFailed
+	})))
+	h.Start(context.Background(), time.Millisecond)
+	defer h.Stop()
+
+	service := &grpcService{health: h}
+	require.Eventually(func() bool {
+		response, err := service.Health(context.Background(), &healthv1.HealthRequest{})
+		return err == nil && response.Checks["failing"].Error == errFailed.Error()
+	}, time.Second, time.Millisecond)
+
+	response, err := service.Health(context.Background(), &healthv1.HealthRequest{Tags: []string{"tag"}})
+	require.NoError(err)
+	require.True(response.Healthy)
+	require.Len(response.Checks, 1)
+	require.Equal(`{"a":1}`, response.Checks["passing"].Details)
+	require.Empty(response.Checks["passing"].Error)
+	require.Nil(response.Checks["passing"].TimeOfFirstFailure)
+
+	response, err = service.Health(context.Background(), &healthv1.HealthRequest{})
+	require.NoError(err)
+	require.False(response.Healthy)
+	require.NotNil(response.Checks["failing"].TimeOfFirstFailure)
+	require.Positive(response.Checks["failing"].ContiguousFailures)
+}
+
+func TestGRPCClient(t *testing.T) {
+	require := require.New(t)
+
+	h, err := New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+
+	errFailed := errors.New("failed")
+	require.NoError(h.RegisterHealthCheck("passing", CheckerFunc(func(context.Context) (interface{}, error) {
+		return map[string]interface{}{"a": 1.0}, nil
+	}), "tag"))
+	require.NoError(h.RegisterHealthCheck("failing", CheckerFunc(func(context.Context) (interface{}, error) {
+		return nil, errFailed
+	})))
+	h.Start(context.Background(), time.Millisecond)
+	defer h.Stop()
+
+	listener, err := grpcutils.NewListener()
+	require.NoError(err)
+	server := grpc.NewServer()
+	defer server.Stop()
+	healthv1.RegisterHealthServer(server, &grpcService{health: h})
+	go grpcutils.Serve(listener, server)
+
+	conn, err := grpcutils.Dial(listener.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+	client := NewGRPCClient(conn)
+
+	require.Eventually(func() bool {
+		reply, err := client.Health(context.Background(), nil)
+		return err == nil && reply.Checks["failing"].Error != nil
+	}, time.Second, time.Millisecond)
+
+	reply, err := client.Health(context.Background(), []string{"tag"})
+	require.NoError(err)
+	require.True(reply.Healthy)
+	require.Len(reply.Checks, 1)
+	require.Equal(map[string]interface{}{"a": 1.0}, reply.Checks["passing"].Details)
+	require.Nil(reply.Checks["passing"].Error)
+	require.Nil(reply.Checks["passing"].TimeOfFirstFailure)
+
+	reply, err = client.Health(context.Background(), nil)
+	require.NoError(err)
+	require.False(reply.Healthy)
+	require.Equal(errFailed.Error(), *reply.Checks["failing"].Error)
+	require.NotNil(reply.Checks["failing"].TimeOfFirstFailure)
+}
@@ -6,6 +6,7 @@ package info
 import (
 	"context"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/rpc"
 )
 
@@ -14,3 +15,11 @@ func (c *client) GetGenesisBytes(ctx context.Context, options ...rpc.Option) ([]
 	err := c.requester.SendRequest(ctx, "info.getGenesisBytes", struct{}{}, res, options...)
 	return res.GenesisBytes, err
 }
+
+func (c *client) GetBandwidth(ctx context.Context, nodeIDs []ids.NodeID, options ...rpc.Option) (*GetBandwidthReply, error) {
+	res := &GetBandwidthReply{}
+	err := c.requester.SendRequest(ctx, "info.getBandwidth", &GetBandwidthArgs{
+		NodeIDs: nodeIDs,
+	}, res, options...)
+	return res, err
+}
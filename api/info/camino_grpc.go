@@ -0,0 +1,230 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms"
+
+	infov1 "github.com/ava-labs/avalanchego/proto/pb/info/v1"
+)
+
+var _ infov1.InfoServer = (*grpcService)(nil)
+
+// grpcService serves the info API over gRPC
+type grpcService struct {
+	infov1.UnsafeInfoServer
+	info *Info
+}
+
+// NewGRPCService returns a handler that serves the info API as the gRPC
+// service info.v1.Info.
+func NewGRPCService(
+	parameters Parameters,
+	log logging.Logger,
+	chainManager chains.Manager,
+	vmManager vms.Manager,
+	myIP ips.DynamicIPPort,
+	network network.Network,
+	validators validators.Set,
+	benchlist benchlist.Manager,
+) *common.HTTPHandler {
+	server := grpc.NewServer()
+	infov1.RegisterInfoServer(server, &grpcService{
+		info: newInfo(
+			parameters,
+			log,
+			chainManager,
+			vmManager,
+			myIP,
+			network,
+			validators,
+			benchlist,
+		),
+	})
+	return &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     server,
+	}
+}
+
+func (s *grpcService) GetNodeVersion(context.Context, *infov1.GetNodeVersionRequest) (*infov1.GetNodeVersionResponse, error) {
+	reply := GetNodeVersionReply{}
+	if err := s.info.GetNodeVersion(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.GetNodeVersionResponse{
+		Version:            reply.Version,
+		DatabaseVersion:    reply.DatabaseVersion,
+		RpcProtocolVersion: uint32(reply.RPCProtocolVersion),
+		GitCommit:          reply.GitCommit,
+		GitVersion:         reply.GitVersion,
+		VmVersions:         reply.VMVersions,
+	}, nil
+}
+
+func (s *grpcService) GetNodeID(context.Context, *infov1.GetNodeIDRequest) (*infov1.GetNodeIDResponse, error) {
+	reply := GetNodeIDReply{}
+	if err := s.info.GetNodeID(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	response := &infov1.GetNodeIDResponse{
+		NodeId: reply.NodeID.String(),
+	}
+	if reply.NodePOP != nil {
+		response.PublicKey = reply.NodePOP.PublicKey[:]
+		response.ProofOfPossession = reply.NodePOP.ProofOfPossession[:]
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetNodeIP(context.Context, *infov1.GetNodeIPRequest) (*infov1.GetNodeIPResponse, error) {
+	reply := GetNodeIPReply{}
+	if err := s.info.GetNodeIP(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.GetNodeIPResponse{
+		Ip: reply.IP,
+	}, nil
+}
+
+func (s *grpcService) GetNetworkID(context.Context, *infov1.GetNetworkIDRequest) (*infov1.GetNetworkIDResponse, error) {
+	reply := GetNetworkIDReply{}
+	if err := s.info.GetNetworkID(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.GetNetworkIDResponse{
+		NetworkId: uint32(reply.NetworkID),
+	}, nil
+}
+
+func (s *grpcService) GetNetworkName(context.Context, *infov1.GetNetworkNameRequest) (*infov1.GetNetworkNameResponse, error) {
+	reply := GetNetworkNameReply{}
+	if err := s.info.GetNetworkName(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.GetNetworkNameResponse{
+		NetworkName: reply.NetworkName,
+	}, nil
+}
+
+func (s *grpcService) GetBlockchainID(_ context.Context, req *infov1.GetBlockchainIDRequest) (*infov1.GetBlockchainIDResponse, error) {
+	reply := GetBlockchainIDReply{}
+	if err := s.info.GetBlockchainID(nil, &GetBlockchainIDArgs{Alias: req.Alias}, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.GetBlockchainIDResponse{
+		BlockchainId: reply.BlockchainID.String(),
+	}, nil
+}
+
+func (s *grpcService) IsBootstrapped(_ context.Context, req *infov1.IsBootstrappedRequest) (*infov1.IsBootstrappedResponse, error) {
+	reply := IsBootstrappedResponse{}
+	if err := s.info.IsBootstrapped(nil, &IsBootstrappedArgs{Chain: req.Chain}, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.IsBootstrappedResponse{
+		IsBootstrapped: reply.IsBootstrapped,
+	}, nil
+}
+
+func (s *grpcService) Peers(_ context.Context, req *infov1.PeersRequest) (*infov1.PeersResponse, error) {
+	args := PeersArgs{
+		NodeIDs: make([]ids.NodeID, len(req.NodeIds)),
+	}
+	for i, nodeIDStr := range req.NodeIds {
+		nodeID, err := ids.NodeIDFromString(nodeIDStr)
+		if err != nil {
+			return nil, err
+		}
+		args.NodeIDs[i] = nodeID
+	}
+
+	reply := PeersReply{}
+	if err := s.info.Peers(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+
+	response := &infov1.PeersResponse{
+		Peers: make([]*infov1.Peer, len(reply.Peers)),
+	}
+	for i, peer := range reply.Peers {
+		observedSubnetUptimes := make(map[string]uint32, len(peer.ObservedSubnetUptimes))
+		for subnetID, uptime := range peer.ObservedSubnetUptimes {
+			observedSubnetUptimes[subnetID.String()] = uint32(uptime)
+		}
+		response.Peers[i] = &infov1.Peer{
+			Ip:                    peer.IP,
+			PublicIp:              peer.PublicIP,
+			NodeId:                peer.ID.String(),
+			Version:               peer.Version,
+			LastSent:              timestamppb.New(peer.LastSent),
+			LastReceived:          timestamppb.New(peer.LastReceived),
+			ObservedUptime:        uint32(peer.ObservedUptime),
+			ObservedSubnetUptimes: observedSubnetUptimes,
+			TrackedSubnets:        idsToStrings(peer.TrackedSubnets),
+			Benched:               idsToStrings(peer.Benched),
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcService) Uptime(_ context.Context, req *infov1.UptimeRequest) (*infov1.UptimeResponse, error) {
+	args := UptimeRequest{}
+	if req.SubnetId != "" {
+		subnetID, err := ids.FromString(req.SubnetId)
+		if err != nil {
+			return nil, err
+		}
+		args.SubnetID = subnetID
+	}
+
+	reply := UptimeResponse{}
+	if err := s.info.Uptime(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.UptimeResponse{
+		RewardingStakePercentage:  float64(reply.RewardingStakePercentage),
+		WeightedAveragePercentage: float64(reply.WeightedAveragePercentage),
+	}, nil
+}
+
+func (s *grpcService) GetTxFee(context.Context, *infov1.GetTxFeeRequest) (*infov1.GetTxFeeResponse, error) {
+	reply := GetTxFeeResponse{}
+	if err := s.info.GetTxFee(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &infov1.GetTxFeeResponse{
+		TxFee:                         uint64(reply.TxFee),
+		CreateAssetTxFee:              uint64(reply.CreateAssetTxFee),
+		CreateSubnetTxFee:             uint64(reply.CreateSubnetTxFee),
+		TransformSubnetTxFee:          uint64(reply.TransformSubnetTxFee),
+		CreateBlockchainTxFee:         uint64(reply.CreateBlockchainTxFee),
+		AddPrimaryNetworkValidatorFee: uint64(reply.AddPrimaryNetworkValidatorFee),
+		AddPrimaryNetworkDelegatorFee: uint64(reply.AddPrimaryNetworkDelegatorFee),
+		AddSubnetValidatorFee:         uint64(reply.AddSubnetValidatorFee),
+		AddSubnetDelegatorFee:         uint64(reply.AddSubnetDelegatorFee),
+	}, nil
+}
+
+func idsToStrings(idSlice []ids.ID) []string {
+	strs := make([]string, len(idSlice))
+	for i, id := range idSlice {
+		strs[i] = id.String()
+	}
+	return strs
+}
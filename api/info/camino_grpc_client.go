@@ -0,0 +1,214 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
+
+	infov1 "github.com/ava-labs/avalanchego/proto/pb/info/v1"
+)
+
+var (
+	_ GRPCClient = Client(nil)
+	_ GRPCClient = (*grpcClient)(nil)
+)
+
+// GRPCClient is the part of the Info API Client that is served by the gRPC
+// service info.v1.Info. Only the headers of the options are sent, as gRPC
+// metadata.
+type GRPCClient interface {
+	GetNodeVersion(context.Context, ...rpc.Option) (*GetNodeVersionReply, error)
+	GetNodeID(context.Context, ...rpc.Option) (ids.NodeID, *signer.ProofOfPossession, error)
+	GetNodeIP(context.Context, ...rpc.Option) (string, error)
+	GetNetworkID(context.Context, ...rpc.Option) (uint32, error)
+	GetNetworkName(context.Context, ...rpc.Option) (string, error)
+	GetBlockchainID(context.Context, string, ...rpc.Option) (ids.ID, error)
+	Peers(context.Context, ...rpc.Option) ([]Peer, error)
+	IsBootstrapped(context.Context, string, ...rpc.Option) (bool, error)
+	GetTxFee(context.Context, ...rpc.Option) (*GetTxFeeResponse, error)
+	Uptime(context.Context, ids.ID, ...rpc.Option) (*UptimeResponse, error)
+}
+
+// grpcClient implements GRPCClient with the generated info.v1.Info client
+type grpcClient struct {
+	client infov1.InfoClient
+}
+
+// NewGRPCClient returns a new Info API Client that calls the gRPC service
+// info.v1.Info over [conn]
+func NewGRPCClient(conn grpc.ClientConnInterface) GRPCClient {
+	return &grpcClient{client: infov1.NewInfoClient(conn)}
+}
+
+func (c *grpcClient) GetNodeVersion(ctx context.Context, options ...rpc.Option) (*GetNodeVersionReply, error) {
+	res, err := c.client.GetNodeVersion(rpc.NewGRPCContext(ctx, options), &infov1.GetNodeVersionRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &GetNodeVersionReply{
+		Version:            res.Version,
+		DatabaseVersion:    res.DatabaseVersion,
+		RPCProtocolVersion: json.Uint32(res.RpcProtocolVersion),
+		GitCommit:          res.GitCommit,
+		GitVersion:         res.GitVersion,
+		VMVersions:         res.VmVersions,
+	}, nil
+}
+
+func (c *grpcClient) GetNodeID(ctx context.Context, options ...rpc.Option) (ids.NodeID, *signer.ProofOfPossession, error) {
+	res, err := c.client.GetNodeID(rpc.NewGRPCContext(ctx, options), &infov1.GetNodeIDRequest{})
+	if err != nil {
+		return ids.EmptyNodeID, nil, err
+	}
+	nodeID, err := ids.NodeIDFromString(res.NodeId)
+	if err != nil || len(res.PublicKey) == 0 {
+		return nodeID, nil, err
+	}
+
+	pop := &signer.ProofOfPossession{}
+	copy(pop.PublicKey[:], res.PublicKey)
+	copy(pop.ProofOfPossession[:], res.ProofOfPossession)
+	return nodeID, pop, pop.Verify()
+}
+
+func (c *grpcClient) GetNodeIP(ctx context.Context, options ...rpc.Option) (string, error) {
+	res, err := c.client.GetNodeIP(rpc.NewGRPCContext(ctx, options), &infov1.GetNodeIPRequest{})
+	if err != nil {
+		return "", err
+	}
+	return res.Ip, nil
+}
+
+func (c *grpcClient) GetNetworkID(ctx context.Context, options ...rpc.Option) (uint32, error) {
+	res, err := c.client.GetNetworkID(rpc.NewGRPCContext(ctx, options), &infov1.GetNetworkIDRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return res.NetworkId, nil
+}
+
+func (c *grpcClient) GetNetworkName(ctx context.Context, options ...rpc.Option) (string, error) {
+	res, err := c.client.GetNetworkName(rpc.NewGRPCContext(ctx, options), &infov1.GetNetworkNameRequest{})
+	if err != nil {
+		return "", err
+	}
+	return res.NetworkName, nil
+}
+
+func (c *grpcClient) GetBlockchainID(ctx context.Context, alias string, options ...rpc.Option) (ids.ID, error) {
+	res, err := c.client.GetBlockchainID(rpc.NewGRPCContext(ctx, options), &infov1.GetBlockchainIDRequest{
+		Alias: alias,
+	})
+	if err != nil {
+		return ids.Empty, err
+	}
+	return ids.FromString(res.BlockchainId)
+}
+
+func (c *grpcClient) Peers(ctx context.Context, options ...rpc.Option) ([]Peer, error) {
+	res, err := c.client.Peers(rpc.NewGRPCContext(ctx, options), &infov1.PeersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	peers := make([]Peer, len(res.Peers))
+	for i, p := range res.Peers {
+		nodeID, err := ids.NodeIDFromString(p.NodeId)
+		if err != nil {
+			return nil, err
+		}
+		observedSubnetUptimes := make(map[ids.ID]json.Uint32, len(p.ObservedSubnetUptimes))
+		for subnetIDStr, uptime := range p.ObservedSubnetUptimes {
+			subnetID, err := ids.FromString(subnetIDStr)
+			if err != nil {
+				return nil, err
+			}
+			observedSubnetUptimes[subnetID] = json.Uint32(uptime)
+		}
+		trackedSubnets, err := stringsToIDs(p.TrackedSubnets)
+		if err != nil {
+			return nil, err
+		}
+		benched, err := stringsToIDs(p.Benched)
+		if err != nil {
+			return nil, err
+		}
+		peers[i] = Peer{
+			Info: peer.Info{
+				IP:                    p.Ip,
+				PublicIP:              p.PublicIp,
+				ID:                    nodeID,
+				Version:               p.Version,
+				LastSent:              p.LastSent.AsTime(),
+				LastReceived:          p.LastReceived.AsTime(),
+				ObservedUptime:        json.Uint32(p.ObservedUptime),
+				ObservedSubnetUptimes: observedSubnetUptimes,
+				TrackedSubnets:        trackedSubnets,
+			},
+			Benched: benched,
+		}
+	}
+	return peers, nil
+}
+
+func (c *grpcClient) IsBootstrapped(ctx context.Context, chainID string, options ...rpc.Option) (bool, error) {
+	res, err := c.client.IsBootstrapped(rpc.NewGRPCContext(ctx, options), &infov1.IsBootstrappedRequest{
+		Chain: chainID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.IsBootstrapped, nil
+}
+
+func (c *grpcClient) GetTxFee(ctx context.Context, options ...rpc.Option) (*GetTxFeeResponse, error) {
+	res, err := c.client.GetTxFee(rpc.NewGRPCContext(ctx, options), &infov1.GetTxFeeRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return &GetTxFeeResponse{
+		TxFee:                         json.Uint64(res.TxFee),
+		CreateAssetTxFee:              json.Uint64(res.CreateAssetTxFee),
+		CreateSubnetTxFee:             json.Uint64(res.CreateSubnetTxFee),
+		TransformSubnetTxFee:          json.Uint64(res.TransformSubnetTxFee),
+		CreateBlockchainTxFee:         json.Uint64(res.CreateBlockchainTxFee),
+		AddPrimaryNetworkValidatorFee: json.Uint64(res.AddPrimaryNetworkValidatorFee),
+		AddPrimaryNetworkDelegatorFee: json.Uint64(res.AddPrimaryNetworkDelegatorFee),
+		AddSubnetValidatorFee:         json.Uint64(res.AddSubnetValidatorFee),
+		AddSubnetDelegatorFee:         json.Uint64(res.AddSubnetDelegatorFee),
+	}, nil
+}
+
+func (c *grpcClient) Uptime(ctx context.Context, subnetID ids.ID, options ...rpc.Option) (*UptimeResponse, error) {
+	res, err := c.client.Uptime(rpc.NewGRPCContext(ctx, options), &infov1.UptimeRequest{
+		SubnetId: subnetID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UptimeResponse{
+		RewardingStakePercentage:  json.Float64(res.RewardingStakePercentage),
+		WeightedAveragePercentage: json.Float64(res.WeightedAveragePercentage),
+	}, nil
+}
+
+func stringsToIDs(strs []string) ([]ids.ID, error) {
+	idSlice := make([]ids.ID, len(strs))
+	for i, str := range strs {
+		id, err := ids.FromString(str)
+		if err != nil {
+			return nil, err
+		}
+		idSlice[i] = id
+	}
+	return idSlice, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package info
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/crypto/bls"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/platformvm/signer"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	infov1 "github.com/ava-labs/avalanchego/proto/pb/info/v1"
+)
+
+func TestGRPCService(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	nodeID := ids.GenerateTestNodeID()
+	pop := signer.NewProofOfPossession(sk)
+
+	service := &grpcService{
+		info: &Info{
+			Parameters: Parameters{
+				NodeID:    nodeID,
+				NodePOP:   pop,
+				NetworkID: constants.KopernikusID,
+				TxFee:     1,
+			},
+			log: logging.NoLog{},
+		},
+	}
+
+	nodeIDResponse, err := service.GetNodeID(context.Background(), &infov1.GetNodeIDRequest{})
+	require.NoError(err)
+	require.Equal(nodeID.String(), nodeIDResponse.NodeId)
+	require.Equal(pop.PublicKey[:], nodeIDResponse.PublicKey)
+	require.Equal(pop.ProofOfPossession[:], nodeIDResponse.ProofOfPossession)
+
+	networkName, err := service.GetNetworkName(context.Background(), &infov1.GetNetworkNameRequest{})
+	require.NoError(err)
+	require.Equal(constants.KopernikusName, networkName.NetworkName)
+
+	txFee, err := service.GetTxFee(context.Background(), &infov1.GetTxFeeRequest{})
+	require.NoError(err)
+	require.Equal(uint64(1), txFee.TxFee)
+}
+
+func TestGRPCClient(t *testing.T) {
+	require := require.New(t)
+
+	sk, err := bls.NewSecretKey()
+	require.NoError(err)
+	nodeID := ids.GenerateTestNodeID()
+	pop := signer.NewProofOfPossession(sk)
+
+	listener, err := grpcutils.NewListener()
+	require.NoError(err)
+	server := grpc.NewServer()
+	defer server.Stop()
+	infov1.RegisterInfoServer(server, &grpcService{
+		info: &Info{
+			Parameters: Parameters{
+				NodeID:    nodeID,
+				NodePOP:   pop,
+				NetworkID: constants.KopernikusID,
+				TxFee:     1,
+			},
+			log: logging.NoLog{},
+		},
+	})
+	go grpcutils.Serve(listener, server)
+
+	conn, err := grpcutils.Dial(listener.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+	client := NewGRPCClient(conn)
+
+	ctx := context.Background()
+	option := rpc.WithHeader("Authorization", "Bearer token")
+
+	gotNodeID, gotPOP, err := client.GetNodeID(ctx, option)
+	require.NoError(err)
+	require.Equal(nodeID, gotNodeID)
+	require.Equal(pop.PublicKey, gotPOP.PublicKey)
+	require.Equal(pop.ProofOfPossession, gotPOP.ProofOfPossession)
+
+	networkID, err := client.GetNetworkID(ctx)
+	require.NoError(err)
+	require.Equal(constants.KopernikusID, networkID)
+
+	txFee, err := client.GetTxFee(ctx)
+	require.NoError(err)
+	require.Equal(GetTxFeeResponse{TxFee: 1}, *txFee)
+}
@@ -3,7 +3,15 @@
 
 package info
 
-import "net/http"
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
 
 // GetGenesisBytesReply contains the response metadata for GetGenesisBytes
 type GetGenesisBytesReply struct {
@@ -16,3 +24,44 @@ func (i *Info) GetGenesisBytes(_ *http.Request, _ *struct{}, reply *GetGenesisBy
 	reply.GenesisBytes = i.GenesisBytes
 	return nil
 }
+
+// GetBandwidthArgs are the arguments for calling GetBandwidth
+type GetBandwidthArgs struct {
+	// Peers to report the traffic of. All peers are reported if empty.
+	NodeIDs []ids.NodeID `json:"nodeIDs"`
+}
+
+// GetBandwidthReply are the results from calling GetBandwidth
+type GetBandwidthReply struct {
+	// Traffic of the chains, keyed by their primary alias
+	Chains map[string]bandwidth.Usage `json:"chains"`
+	// Traffic with the peers
+	Peers map[ids.NodeID]bandwidth.Usage `json:"peers"`
+}
+
+// GetBandwidth returns the bytes this node exchanged with peers per chain and
+// per peer, and the bytes dropped because of subnet bandwidth quotas
+func (i *Info) GetBandwidth(_ *http.Request, args *GetBandwidthArgs, reply *GetBandwidthReply) error {
+	i.log.Debug("API called",
+		zap.String("service", "info"),
+		zap.String("method", "getBandwidth"),
+	)
+
+	chains := i.Bandwidth.Chains()
+	reply.Chains = make(map[string]bandwidth.Usage, len(chains))
+	for chainID, usage := range chains {
+		reply.Chains[i.chainManager.PrimaryAliasOrDefault(chainID)] = usage
+	}
+
+	reply.Peers = i.Bandwidth.Peers()
+	if len(args.NodeIDs) > 0 {
+		nodeIDs := set.NewSet[ids.NodeID](len(args.NodeIDs))
+		nodeIDs.Add(args.NodeIDs...)
+		for nodeID := range reply.Peers {
+			if !nodeIDs.Contains(nodeID) {
+				delete(reply.Peers, nodeID)
+			}
+		}
+	}
+	return nil
+}
@@ -6,11 +6,28 @@ package info
 import (
 	"testing"
 
-	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/golang/mock/gomock"
+
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
 )
 
+type aliasingManager struct {
+	chains.Manager
+	aliases map[ids.ID]string
+}
+
+func (m aliasingManager) PrimaryAliasOrDefault(chainID ids.ID) string {
+	return m.aliases[chainID]
+}
+
 func TestGetGenesisBytes(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockLog := logging.NewMockLogger(ctrl)
@@ -25,3 +42,46 @@ func TestGetGenesisBytes(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, GetGenesisBytesReply{GenesisBytes: service.GenesisBytes}, reply)
 }
+
+func TestGetBandwidth(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := bandwidth.NewTracker("", prometheus.NewRegistry())
+	require.NoError(err)
+
+	chainID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+	tracker.Received(chainID, nodeID1, 100)
+	tracker.Sent(ids.Empty, chainID, set.Set[ids.NodeID]{nodeID2: {}}, 50)
+
+	service := Info{
+		Parameters: Parameters{Bandwidth: tracker},
+		log:        logging.NoLog{},
+		chainManager: aliasingManager{
+			Manager: chains.TestManager,
+			aliases: map[ids.ID]string{chainID: "X"},
+		},
+	}
+
+	reply := GetBandwidthReply{}
+	require.NoError(service.GetBandwidth(nil, &GetBandwidthArgs{}, &reply))
+	require.Equal(map[string]bandwidth.Usage{
+		"X": {
+			InboundBytes:     100,
+			InboundMessages:  1,
+			OutboundBytes:    50,
+			OutboundMessages: 1,
+		},
+	}, reply.Chains)
+	require.Len(reply.Peers, 2)
+
+	reply = GetBandwidthReply{}
+	require.NoError(service.GetBandwidth(nil, &GetBandwidthArgs{NodeIDs: []ids.NodeID{nodeID2}}, &reply))
+	require.Equal(map[ids.NodeID]bandwidth.Usage{
+		nodeID2: {
+			OutboundBytes:    50,
+			OutboundMessages: 1,
+		},
+	}, reply.Peers)
+}
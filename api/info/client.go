@@ -39,6 +39,7 @@ type Client interface {
 	Uptime(context.Context, ids.ID, ...rpc.Option) (*UptimeResponse, error)
 	GetVMs(context.Context, ...rpc.Option) (map[ids.ID][]string, error)
 	GetGenesisBytes(context.Context, ...rpc.Option) ([]byte, error)
+	GetBandwidth(context.Context, []ids.NodeID, ...rpc.Option) (*GetBandwidthReply, error)
 }
 
 // Client implementation for an Info API Client
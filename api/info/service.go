@@ -18,15 +18,15 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/gorilla/rpc/v2"
-
 	"go.uber.org/zap"
 
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -70,6 +70,7 @@ type Parameters struct {
 	AddSubnetDelegatorFee         uint64
 	VMManager                     vms.Manager
 	GenesisBytes                  []byte
+	Bandwidth                     bandwidth.Tracker
 }
 
 // NewService returns a new admin API service
@@ -83,11 +84,39 @@ func NewService(
 	validators validators.Set,
 	benchlist benchlist.Manager,
 ) (*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := openrpc.NewServer()
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := newServer.RegisterService(&Info{
+	if err := newServer.RegisterService(newInfo(
+		parameters,
+		log,
+		chainManager,
+		vmManager,
+		myIP,
+		network,
+		validators,
+		benchlist,
+	), "info"); err != nil {
+		return nil, err
+	}
+	return &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     newServer,
+	}, nil
+}
+
+func newInfo(
+	parameters Parameters,
+	log logging.Logger,
+	chainManager chains.Manager,
+	vmManager vms.Manager,
+	myIP ips.DynamicIPPort,
+	network network.Network,
+	validators validators.Set,
+	benchlist benchlist.Manager,
+) *Info {
+	return &Info{
 		Parameters:   parameters,
 		log:          log,
 		chainManager: chainManager,
@@ -96,13 +125,7 @@ func NewService(
 		networking:   network,
 		validators:   validators,
 		benchlist:    benchlist,
-	}, "info"); err != nil {
-		return nil, err
 	}
-	return &common.HTTPHandler{
-		LockOptions: common.NoLock,
-		Handler:     newServer,
-	}, nil
 }
 
 // GetNodeVersionReply are the results from calling GetNodeVersion
@@ -241,6 +264,8 @@ type PeersReply struct {
 	NumPeers json.Uint64 `json:"numPeers"`
 	// Each element is a peer
 	Peers []Peer `json:"peers"`
+	// Bans of node IDs and IPs this node refuses to connect to
+	Bans []network.Ban `json:"bans"`
 }
 
 // Peers returns the list of current validators
@@ -259,8 +284,14 @@ func (i *Info) Peers(_ *http.Request, args *PeersArgs, reply *PeersReply) error
 		}
 	}
 
+	bans, err := i.networking.Bans()
+	if err != nil {
+		return err
+	}
+
 	reply.Peers = peerInfo
 	reply.NumPeers = json.Uint64(len(reply.Peers))
+	reply.Bans = bans
 	return nil
 }
 
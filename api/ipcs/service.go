@@ -6,11 +6,10 @@ package ipcs
 import (
 	"net/http"
 
-	"github.com/gorilla/rpc/v2"
-
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
@@ -38,7 +37,7 @@ func NewService(log logging.Logger, chainManager chains.Manager, httpServer serv
 		ipcs: ipcs,
 	}
 
-	newServer := rpc.NewServer()
+	newServer := openrpc.NewServer()
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
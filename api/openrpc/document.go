@@ -0,0 +1,54 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+// Version is the version of the OpenRPC specification the documents follow
+const Version = "1.2.6"
+
+// ParamStructureByName signals that the params of a method are passed as the
+// fields of a single JSON object, as all gorilla rpc services expect them.
+const ParamStructureByName = "by-name"
+
+// Document describes the methods of a JSON-RPC endpoint
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []*Method  `json:"methods"`
+	Components Components `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Method struct {
+	Name           string               `json:"name"`
+	ParamStructure string               `json:"paramStructure"`
+	Params         []*ContentDescriptor `json:"params"`
+	Result         *ContentDescriptor   `json:"result"`
+}
+
+type ContentDescriptor struct {
+	Name   string  `json:"name"`
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas of the named types referenced by the methods
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is the subset of JSON schema needed to describe the JSON encoding of
+// Go types
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
@@ -0,0 +1,395 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	stdjson "encoding/json"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+const (
+	typeString  = "string"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+	typeArray   = "array"
+	typeObject  = "object"
+
+	schemaRefPrefix = "#/components/schemas/"
+)
+
+var (
+	typeOfError         = reflect.TypeOf((*error)(nil)).Elem()
+	typeOfRequest       = reflect.TypeOf((*http.Request)(nil)).Elem()
+	typeOfMarshaler     = reflect.TypeOf((*stdjson.Marshaler)(nil)).Elem()
+	typeOfTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	invalidSchemaNameChars = regexp.MustCompile(`[^a-zA-Z0-9.\-_]`)
+
+	uintPattern  = `^[0-9]+$`
+	floatPattern = `^-?[0-9]+(\.[0-9]+)?$`
+
+	// knownSchemas describes the types whose JSON encoding can't be derived
+	// from their kind.
+	knownSchemas = map[reflect.Type]Schema{
+		reflect.TypeOf(json.Uint8(0)):   {Type: typeString, Format: "uint8", Pattern: uintPattern},
+		reflect.TypeOf(json.Uint16(0)):  {Type: typeString, Format: "uint16", Pattern: uintPattern},
+		reflect.TypeOf(json.Uint32(0)):  {Type: typeString, Format: "uint32", Pattern: uintPattern},
+		reflect.TypeOf(json.Uint64(0)):  {Type: typeString, Format: "uint64", Pattern: uintPattern},
+		reflect.TypeOf(json.Float32(0)): {Type: typeString, Format: "float", Pattern: floatPattern},
+		reflect.TypeOf(json.Float64(0)): {Type: typeString, Format: "double", Pattern: floatPattern},
+		reflect.TypeOf(formatting.Hex): {
+			Type: typeString,
+			Enum: []string{
+				formatting.Hex.String(),
+				formatting.HexNC.String(),
+				formatting.HexC.String(),
+				formatting.JSON.String(),
+			},
+		},
+		reflect.TypeOf(ids.ID{}):      {Type: typeString, Format: "id"},
+		reflect.TypeOf(ids.ShortID{}): {Type: typeString, Format: "shortID"},
+		reflect.TypeOf(ids.NodeID{}):  {Type: typeString, Format: "nodeID"},
+		reflect.TypeOf(time.Time{}):   {Type: typeString, Format: "date-time"},
+	}
+)
+
+// generator derives the schemas of Go types from the rules of encoding/json
+type generator struct {
+	// schemas of the named struct types, keyed by their schema name
+	schemas map[string]*Schema
+	// names of the schemas of the named struct types seen so far
+	names map[reflect.Type]string
+}
+
+func newGenerator() *generator {
+	return &generator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// methods returns the gorilla rpc methods of [receiver] registered as
+// [service], sorted by name. A method is exposed by gorilla rpc if it's
+// exported and has the signature:
+//
+//	func (receiver) Method(*http.Request, *Args, *Reply) error
+func (g *generator) methods(receiver interface{}, service string) []*Method {
+	receiverType := reflect.TypeOf(receiver)
+	methods := make([]*Method, 0, receiverType.NumMethod())
+	for i := 0; i < receiverType.NumMethod(); i++ {
+		method := receiverType.Method(i)
+		methodType := method.Type
+		if method.PkgPath != "" ||
+			methodType.NumIn() != 4 ||
+			methodType.In(1) != reflect.PointerTo(typeOfRequest) ||
+			methodType.In(2).Kind() != reflect.Pointer ||
+			!isExportedOrBuiltin(methodType.In(2)) ||
+			methodType.In(3).Kind() != reflect.Pointer ||
+			!isExportedOrBuiltin(methodType.In(3)) ||
+			methodType.NumOut() != 1 ||
+			methodType.Out(0) != typeOfError {
+			continue
+		}
+
+		methods = append(methods, &Method{
+			Name:           service + "." + lowercaseFirst(method.Name),
+			ParamStructure: ParamStructureByName,
+			Params:         g.params(methodType.In(2).Elem()),
+			Result: &ContentDescriptor{
+				Name:   "result",
+				Schema: g.schema(methodType.In(3).Elem()),
+			},
+		})
+	}
+	return methods
+}
+
+// params returns a content descriptor for every field of the JSON object
+// [argsType] is decoded from.
+func (g *generator) params(argsType reflect.Type) []*ContentDescriptor {
+	for argsType.Kind() == reflect.Pointer {
+		argsType = argsType.Elem()
+	}
+	if argsType.Kind() != reflect.Struct || implementsMarshaler(argsType) {
+		return []*ContentDescriptor{{
+			Name:   "args",
+			Schema: g.schema(argsType),
+		}}
+	}
+
+	fields := structFields(argsType)
+	params := make([]*ContentDescriptor, len(fields))
+	for i, field := range fields {
+		params[i] = &ContentDescriptor{
+			Name:   field.name,
+			Schema: g.fieldSchema(field),
+		}
+	}
+	return params
+}
+
+func (g *generator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if schema, ok := knownSchemas[t]; ok {
+		return &schema
+	}
+	switch {
+	case implementsTextMarshaler(t):
+		// encoding/json encodes the text as JSON string, unless the type
+		// is also a json.Marshaler
+		if !implementsMarshaler(t) {
+			return &Schema{Type: typeString}
+		}
+		return marshalerSchema(t)
+	case implementsMarshaler(t):
+		return marshalerSchema(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: typeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: typeInteger}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: typeNumber}
+	case reflect.String:
+		return &Schema{Type: typeString}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && !implementsMarshaler(t.Elem()) && !implementsTextMarshaler(t.Elem()) {
+			return &Schema{Type: typeString, Format: "byte"}
+		}
+		return &Schema{Type: typeArray, Items: g.schema(t.Elem())}
+	case reflect.Array:
+		return &Schema{Type: typeArray, Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: typeObject, AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	default:
+		// Interfaces can hold any value
+		return &Schema{}
+	}
+}
+
+// structSchema returns a reference to the schema of [t] if it's a named type,
+// or the schema itself otherwise.
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.objectSchema(t)
+	}
+
+	name, ok := g.names[t]
+	if !ok {
+		name = g.schemaName(t)
+		g.names[t] = name
+		// The schema is registered before its fields are derived to support
+		// recursive types.
+		schema := &Schema{}
+		g.schemas[name] = schema
+		*schema = *g.objectSchema(t)
+	}
+	return &Schema{Ref: schemaRefPrefix + name}
+}
+
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:       typeObject,
+		Properties: make(map[string]*Schema),
+	}
+	for _, field := range structFields(t) {
+		schema.Properties[field.name] = g.fieldSchema(field)
+	}
+	return schema
+}
+
+func (g *generator) fieldSchema(field structField) *Schema {
+	if field.quoted {
+		return &Schema{Type: typeString}
+	}
+	return g.schema(field.typ)
+}
+
+// schemaName returns a unique name for the schema of [t], made of its package
+// and type name.
+func (g *generator) schemaName(t reflect.Type) string {
+	baseName := invalidSchemaNameChars.ReplaceAllString(path.Base(t.PkgPath())+"."+t.Name(), "_")
+	name := baseName
+	for i := 2; ; i++ {
+		if _, ok := g.schemas[name]; !ok {
+			return name
+		}
+		name = fmt.Sprintf("%s_%d", baseName, i)
+	}
+}
+
+// marshalerSchema derives the JSON type of a json.Marshaler from the encoding
+// of its zero value.
+func marshalerSchema(t reflect.Type) (schema *Schema) {
+	defer func() {
+		// Types that can't encode their zero value can hold any value
+		if recover() != nil {
+			schema = &Schema{}
+		}
+	}()
+
+	encoded, err := stdjson.Marshal(reflect.New(t).Interface())
+	if err != nil || len(encoded) == 0 {
+		return &Schema{}
+	}
+	switch encoded[0] {
+	case '"':
+		return &Schema{Type: typeString}
+	case '{':
+		return &Schema{Type: typeObject}
+	case '[':
+		return &Schema{Type: typeArray}
+	case 't', 'f':
+		return &Schema{Type: typeBoolean}
+	case 'n':
+		return &Schema{}
+	default:
+		return &Schema{Type: typeNumber}
+	}
+}
+
+func implementsMarshaler(t reflect.Type) bool {
+	return t.Implements(typeOfMarshaler) || reflect.PointerTo(t).Implements(typeOfMarshaler)
+}
+
+func implementsTextMarshaler(t reflect.Type) bool {
+	return t.Implements(typeOfTextMarshaler) || reflect.PointerTo(t).Implements(typeOfTextMarshaler)
+}
+
+type structField struct {
+	name   string
+	typ    reflect.Type
+	quoted bool
+	depth  int
+}
+
+// structFields returns the fields encoding/json encodes for the struct [t], in
+// declaration order. Fields of embedded structs are promoted, unless a field
+// of the same name is less deeply nested.
+func structFields(t reflect.Type) []structField {
+	fields := collectStructFields(t, 0, map[reflect.Type]bool{})
+
+	dominant := make(map[string]int, len(fields))
+	for i, field := range fields {
+		if j, ok := dominant[field.name]; !ok || field.depth < fields[j].depth {
+			dominant[field.name] = i
+		}
+	}
+	dominantFields := make([]structField, 0, len(dominant))
+	for i, field := range fields {
+		if dominant[field.name] == i {
+			dominantFields = append(dominantFields, field)
+		}
+	}
+	return dominantFields
+}
+
+func collectStructFields(t reflect.Type, depth int, visited map[reflect.Type]bool) []structField {
+	if visited[t] {
+		return nil
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			fields = append(fields, collectStructFields(fieldType, depth+1, visited)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, structField{
+			name:   name,
+			typ:    field.Type,
+			quoted: hasOption(opts, "string") && isQuotable(fieldType),
+			depth:  depth,
+		})
+	}
+	return fields
+}
+
+func hasOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isQuotable returns true if the ",string" option of encoding/json applies to
+// values of kind [t].
+func isQuotable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// isExportedOrBuiltin returns true if gorilla rpc accepts [t] as type of the
+// args or reply of a method.
+func isExportedOrBuiltin(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	firstRune, _ := utf8.DecodeRuneInString(t.Name())
+	return unicode.IsUpper(firstRune) || t.PkgPath() == ""
+}
+
+// lowercaseFirst returns [name] as it's called through the codec of
+// utils/json, which expects method names to start with a lowercase letter.
+func lowercaseFirst(name string) string {
+	firstRune, runeLen := utf8.DecodeRuneInString(name)
+	if firstRune == utf8.RuneError {
+		return name
+	}
+	return string(unicode.ToLower(firstRune)) + name[runeLen:]
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+type testEmbedded struct {
+	Address string `json:"address"`
+	Limit   int    `json:"limit"`
+}
+
+type ThingArgs struct {
+	testEmbedded
+	Limit    json.Uint32         `json:"limit"`
+	TxID     ids.ID              `json:"txID"`
+	Encoding formatting.Encoding `json:"encoding"`
+	Bytes    []byte              `json:"bytes"`
+	Count    int                 `json:"count,string"`
+	Ignored  string              `json:"-"`
+	Untagged bool
+	ignored  bool
+}
+
+type ThingReply struct {
+	Amounts  map[ids.ID]json.Uint64 `json:"amounts"`
+	Children []*ThingReply          `json:"children"`
+	Any      interface{}            `json:"any"`
+}
+
+type testService struct{}
+
+func (*testService) GetThing(*http.Request, *ThingArgs, *ThingReply) error {
+	return nil
+}
+
+func (*testService) NotAnRPCMethod(*ThingArgs) error {
+	return nil
+}
+
+func (*testService) UnexportedArgs(*http.Request, *testEmbedded, *ThingReply) error {
+	return nil
+}
+
+func TestGeneratorMethods(t *testing.T) {
+	require := require.New(t)
+
+	g := newGenerator()
+	methods := g.methods(&testService{}, "test")
+	require.Len(methods, 1)
+
+	method := methods[0]
+	require.Equal("test.getThing", method.Name)
+	require.Equal(ParamStructureByName, method.ParamStructure)
+	require.Equal([]*ContentDescriptor{
+		{Name: "address", Schema: &Schema{Type: typeString}},
+		{Name: "limit", Schema: &Schema{Type: typeString, Format: "uint32", Pattern: uintPattern}},
+		{Name: "txID", Schema: &Schema{Type: typeString, Format: "id"}},
+		{Name: "encoding", Schema: &Schema{Type: typeString, Enum: []string{"hex", "hexnc", "hexc", "json"}}},
+		{Name: "bytes", Schema: &Schema{Type: typeString, Format: "byte"}},
+		{Name: "count", Schema: &Schema{Type: typeString}},
+		{Name: "Untagged", Schema: &Schema{Type: typeBoolean}},
+	}, method.Params)
+
+	replyRef := &Schema{Ref: schemaRefPrefix + "openrpc.ThingReply"}
+	require.Equal(&ContentDescriptor{Name: "result", Schema: replyRef}, method.Result)
+	require.Equal(map[string]*Schema{
+		"openrpc.ThingReply": {
+			Type: typeObject,
+			Properties: map[string]*Schema{
+				"amounts": {
+					Type:                 typeObject,
+					AdditionalProperties: &Schema{Type: typeString, Format: "uint64", Pattern: uintPattern},
+				},
+				"children": {
+					Type:  typeArray,
+					Items: replyRef,
+				},
+				"any": {},
+			},
+		},
+	}, g.schemas)
+}
+
+type testStringMarshaler uint8
+
+func (testStringMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"value"`), nil
+}
+
+type testObjectMarshaler struct{}
+
+func (*testObjectMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{}`), nil
+}
+
+func TestGeneratorMarshalers(t *testing.T) {
+	require := require.New(t)
+
+	g := newGenerator()
+	require.Equal(&Schema{Type: typeString}, g.schema(reflect.TypeOf(testStringMarshaler(0))))
+	require.Equal(&Schema{Type: typeObject}, g.schema(reflect.TypeOf(&testObjectMarshaler{})))
+	require.Equal(&Schema{Type: typeString, Format: "nodeID"}, g.schema(reflect.TypeOf(&ids.NodeID{})))
+	require.Empty(g.schemas)
+}
+
+func TestSchemaNamesAreUnique(t *testing.T) {
+	require := require.New(t)
+
+	g := newGenerator()
+	g.schemas["openrpc.ThingReply"] = &Schema{}
+	require.Equal("openrpc.ThingReply_2", g.schemaName(reflect.TypeOf(ThingReply{})))
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/rpc/v2"
+
+	"github.com/ava-labs/avalanchego/version"
+)
+
+const (
+	// DiscoverMethod is the method that returns the OpenRPC document of an
+	// endpoint, as defined by the OpenRPC specification.
+	DiscoverMethod = discoverServiceName + ".discover"
+
+	discoverServiceName = "rpc"
+)
+
+// Server is a gorilla rpc server that serves the OpenRPC document of the
+// services registered on it as the rpc.discover method.
+type Server struct {
+	*rpc.Server
+
+	lock         sync.RWMutex
+	generator    *generator
+	serviceNames []string
+	methods      []*Method
+}
+
+func NewServer() *Server {
+	s := &Server{
+		Server:    rpc.NewServer(),
+		generator: newGenerator(),
+	}
+	// Registering the service can't fail, as it has a method of suitable type
+	// and is the first service registered.
+	_ = s.Server.RegisterService(&discoverService{server: s}, discoverServiceName)
+	return s
+}
+
+// RegisterService registers [receiver] as [name] and adds its methods to the
+// OpenRPC document of the server.
+func (s *Server) RegisterService(receiver interface{}, name string) error {
+	if err := s.Server.RegisterService(receiver, name); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.serviceNames = append(s.serviceNames, name)
+	s.methods = append(s.methods, s.generator.methods(receiver, name)...)
+	sort.Slice(s.methods, func(i, j int) bool {
+		return s.methods[i].Name < s.methods[j].Name
+	})
+	return nil
+}
+
+// Document returns the OpenRPC document of the services registered so far
+func (s *Server) Document() *Document {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	schemas := make(map[string]*Schema, len(s.generator.schemas))
+	for name, schema := range s.generator.schemas {
+		schemas[name] = schema
+	}
+	return &Document{
+		OpenRPC: Version,
+		Info: Info{
+			Title:   strings.Join(s.serviceNames, ", "),
+			Version: version.Current.String(),
+		},
+		Methods: append([]*Method(nil), s.methods...),
+		Components: Components{
+			Schemas: schemas,
+		},
+	}
+}
+
+// DiscoverArgs are the arguments of rpc.discover. The secret is only required
+// by services that authenticate every request, like the admin API.
+type DiscoverArgs struct {
+	Secret string `json:"secret,omitempty"`
+}
+
+func (a *DiscoverArgs) GetSecret() string {
+	return a.Secret
+}
+
+type discoverService struct {
+	server *Server
+}
+
+func (s *discoverService) Discover(_ *http.Request, _ *DiscoverArgs, reply *Document) error {
+	*reply = *s.server.Document()
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package openrpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	stdjson "encoding/json"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/version"
+)
+
+func TestServerDiscover(t *testing.T) {
+	require := require.New(t)
+
+	server := NewServer()
+	server.RegisterCodec(json.NewCodec(), "application/json")
+	require.NoError(server.RegisterService(&testService{}, "test"))
+
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/",
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"`+DiscoverMethod+`","params":{}}`),
+	)
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	require.Equal(http.StatusOK, recorder.Code)
+
+	response := struct {
+		Result *Document `json:"result"`
+	}{}
+	require.NoError(stdjson.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(server.Document(), response.Result)
+
+	document := response.Result
+	require.Equal(Version, document.OpenRPC)
+	require.Equal(Info{Title: "test", Version: version.Current.String()}, document.Info)
+	require.Len(document.Methods, 1)
+	require.Equal("test.getThing", document.Methods[0].Name)
+	require.Contains(document.Components.Schemas, "openrpc.ThingReply")
+}
+
+func TestServerRegisterServiceFails(t *testing.T) {
+	require := require.New(t)
+
+	server := NewServer()
+	require.NoError(server.RegisterService(&testService{}, "test"))
+	require.Error(server.RegisterService(&testService{}, "test"))
+	require.Error(server.RegisterService(&testService{}, discoverServiceName))
+	require.Len(server.Document().Methods, 1)
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/exp/maps"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+const grpcContentType = "application/grpc"
+
+var (
+	errNotGRPCServer     = errors.New("handler doesn't serve gRPC services")
+	errNoGRPCServices    = errors.New("handler doesn't have any gRPC services registered")
+	errDuplicateServices = errors.New("gRPC service is already served")
+)
+
+// grpcServer is implemented by *grpc.Server
+type grpcServer interface {
+	http.Handler
+	GetServiceInfo() map[string]grpc.ServiceInfo
+}
+
+// grpcRouter routes gRPC calls to the handler of the called service.
+type grpcRouter struct {
+	lock     sync.RWMutex
+	services map[string]http.Handler
+}
+
+func newGRPCRouter() *grpcRouter {
+	return &grpcRouter{
+		services: make(map[string]http.Handler),
+	}
+}
+
+// isGRPCRequest returns true if [r] is a gRPC call. gRPC calls are only made
+// over HTTP/2.
+func isGRPCRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), grpcContentType)
+}
+
+// addServices routes the calls of each service to its handler. If a service
+// is already routed, no service is added.
+func (r *grpcRouter) addServices(handlers map[string]http.Handler) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for service := range handlers {
+		if _, ok := r.services[service]; ok {
+			return fmt.Errorf("%w: %s", errDuplicateServices, service)
+		}
+	}
+	maps.Copy(r.services, handlers)
+	return nil
+}
+
+func (r *grpcRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// The path of a gRPC call is /<service>/<method>
+	service, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+
+	r.lock.RLock()
+	handler, ok := r.services[service]
+	r.lock.RUnlock()
+
+	if !ok {
+		writeGRPCError(w, codes.Unimplemented, fmt.Sprintf("unknown service %s", service))
+		return
+	}
+	handler.ServeHTTP(w, req)
+}
+
+// writeGRPCError writes a gRPC response without a body that fails with [code].
+func writeGRPCError(w http.ResponseWriter, code codes.Code, msg string) {
+	w.Header().Set("Content-Type", grpcContentType)
+	w.Header().Set("Grpc-Status", strconv.Itoa(int(code)))
+	w.Header().Set("Grpc-Message", msg)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *server) AddGRPCRoute(handler *common.HTTPHandler, lock *sync.RWMutex) error {
+	return s.addGRPCRoute(handler, lock, nil)
+}
+
+func (s *server) addGRPCRoute(handler *common.HTTPHandler, lock *sync.RWMutex, ctx *snow.ConsensusContext) error {
+	srv, ok := handler.Handler.(grpcServer)
+	if !ok {
+		return errNotGRPCServer
+	}
+	services := maps.Keys(srv.GetServiceInfo())
+	if len(services) == 0 {
+		return errNoGRPCServices
+	}
+	s.log.Info("adding gRPC services",
+		zap.Strings("services", services),
+	)
+
+	var h http.Handler = srv
+	if s.tracingEnabled {
+		h = api.TraceHandler(h, strings.Join(services, ","), s.tracer)
+	}
+	// Apply middleware to grab/release chain's lock before/after calling API method
+	h, err := lockMiddleware(
+		h,
+		handler.LockOptions,
+		s.tracingEnabled,
+		s.tracer,
+		lock,
+	)
+	if err != nil {
+		return err
+	}
+	if ctx != nil {
+		// Apply middleware to reject calls to the handler before the chain
+		// finishes bootstrapping
+		h = rejectMiddleware(h, ctx)
+	}
+	h = throttleMiddleware(h, s.throttlingConfig, s.metrics)
+
+	handlers := make(map[string]http.Handler, len(services))
+	for _, service := range services {
+		handlers[service] = s.metrics.wrapHandler(service, h)
+	}
+	return s.grpcRouter.addServices(handlers)
+}
+
+// addChainGRPCRoute routes the gRPC services of the chain described by [ctx].
+// As gRPC services aren't served under the chain's endpoint, only the chains
+// of the primary network can serve them.
+func (s *server) addChainGRPCRoute(chainName string, handler *common.HTTPHandler, ctx *snow.ConsensusContext) error {
+	if ctx.SubnetID != constants.PrimaryNetworkID {
+		s.log.Debug("not adding gRPC services of chain",
+			zap.String("chainName", chainName),
+			zap.String("reason", "chain isn't validated by the primary network"),
+		)
+		return nil
+	}
+	return s.addGRPCRoute(handler, &ctx.Lock, ctx)
+}
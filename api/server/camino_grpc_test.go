@@ -0,0 +1,143 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newTestServer(t *testing.T) (*server, *grpc.ClientConn) {
+	s, err := New(
+		logging.NoLog{},
+		logging.NewFactory(logging.Config{}),
+		"127.0.0.1",
+		0,
+		nil,
+		time.Second,
+		ids.EmptyNodeID,
+		false,
+		nil,
+		"",
+		prometheus.NewRegistry(),
+		HTTPConfig{},
+	)
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(s.(*server).srv.Handler)
+	t.Cleanup(httpServer.Close)
+
+	conn, err := grpc.Dial(
+		strings.TrimPrefix(httpServer.URL, "http://"),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return s.(*server), conn
+}
+
+func newTestGRPCHandler() *common.HTTPHandler {
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	return &common.HTTPHandler{
+		LockOptions: common.NoLock,
+		Handler:     server,
+	}
+}
+
+func TestAddGRPCRoute(t *testing.T) {
+	require := require.New(t)
+	s, conn := newTestServer(t)
+
+	handler := &testHandler{}
+	require.NoError(s.AddRoute(&common.HTTPHandler{LockOptions: common.NoLock, Handler: handler}, &sync.RWMutex{}, "test", ""))
+	require.NoError(s.AddGRPCRoute(newTestGRPCHandler(), &sync.RWMutex{}))
+
+	client := healthpb.NewHealthClient(conn)
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+	require.Equal(healthpb.HealthCheckResponse_SERVING, response.Status)
+
+	// Calls of services that aren't served fail
+	err = conn.Invoke(context.Background(), "/unknown.v1.Unknown/Get", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	require.Equal(codes.Unimplemented, status.Code(err))
+
+	// Services can only be served once
+	err = s.AddGRPCRoute(newTestGRPCHandler(), &sync.RWMutex{})
+	require.ErrorIs(err, errDuplicateServices)
+
+	// Handlers that aren't gRPC servers are rejected
+	err = s.AddGRPCRoute(&common.HTTPHandler{LockOptions: common.NoLock, Handler: handler}, &sync.RWMutex{})
+	require.ErrorIs(err, errNotGRPCServer)
+
+	// Other calls are still routed by the HTTP router
+	httpServer := httptest.NewServer(s.srv.Handler)
+	defer httpServer.Close()
+	httpResponse, err := http.Get(httpServer.URL + "/ext/test")
+	require.NoError(err)
+	require.NoError(httpResponse.Body.Close())
+	require.Equal(http.StatusOK, httpResponse.StatusCode)
+	require.True(handler.called)
+}
+
+func TestRegisterChainGRPC(t *testing.T) {
+	require := require.New(t)
+	s, conn := newTestServer(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vm := &common.TestVM{
+		T: t,
+		CreateHandlersF: func(context.Context) (map[string]*common.HTTPHandler, error) {
+			return map[string]*common.HTTPHandler{
+				common.GRPCExtension: newTestGRPCHandler(),
+			}, nil
+		},
+	}
+	s.RegisterChain("test", ctx, vm)
+
+	// Calls are rejected until the chain is bootstrapped
+	client := healthpb.NewHealthClient(conn)
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(codes.Unavailable, status.Code(err))
+
+	ctx.State.Set(snow.EngineState{State: snow.NormalOp})
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(err)
+	require.Equal(healthpb.HealthCheckResponse_SERVING, response.Status)
+
+	// The services of chains that aren't validated by the primary network
+	// aren't served
+	s, conn = newTestServer(t)
+	ctx = snow.DefaultConsensusContextTest()
+	ctx.SubnetID = ids.GenerateTestID()
+	ctx.State.Set(snow.EngineState{State: snow.NormalOp})
+	s.RegisterChain("test", ctx, vm)
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(codes.Unimplemented, status.Code(err))
+}
@@ -0,0 +1,304 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/cache"
+)
+
+const (
+	// Maximum number of clients whose rate limits are tracked at once. If
+	// exceeded, the rate limit of the least recently seen client is reset.
+	maxThrottledClients = 4096
+
+	rejectReasonIPRateLimit     = "ip_rate_limit"
+	rejectReasonTokenRateLimit  = "token_rate_limit"
+	rejectReasonConcurrency     = "concurrency_limit"
+	rejectReasonMaxResponseSize = "response_size_limit"
+)
+
+var (
+	errResponseTooLarge     = errors.New("API response exceeds the maximum response size")
+	errHijackNotSupported   = errors.New("response writer doesn't support hijacking")
+	errNegativeRateLimit    = errors.New("rate limit must be non-negative")
+	errNegativeThrottleSize = errors.New("throttling sizes must be non-negative")
+)
+
+// ThrottlingConfig limits how much of the node's resources the API clients
+// can use. Zero values disable the respective limit.
+type ThrottlingConfig struct {
+	// Number of requests per second a single IP can make
+	IPRateLimit float64 `json:"ipRateLimit"`
+	// Number of requests a single IP can make at once, before being limited by
+	// [IPRateLimit]
+	IPRateLimitBurst int `json:"ipRateLimitBurst"`
+	// Number of requests per second that can be made with a single auth
+	// token. Only requests that were authenticated by a token are limited.
+	TokenRateLimit float64 `json:"tokenRateLimit"`
+	// Number of requests that can be made at once with a single auth token,
+	// before being limited by [TokenRateLimit]
+	TokenRateLimitBurst int `json:"tokenRateLimitBurst"`
+	// Number of requests each route processes concurrently, including the
+	// requests waiting for the chain's lock
+	MaxConcurrentRequestsPerRoute int `json:"maxConcurrentRequestsPerRoute"`
+	// Maximum size, in bytes, of the response body of a request
+	MaxResponseSize int `json:"maxResponseSize"`
+}
+
+func (c *ThrottlingConfig) Verify() error {
+	switch {
+	case c.IPRateLimit < 0, c.TokenRateLimit < 0:
+		return errNegativeRateLimit
+	case c.IPRateLimitBurst < 0, c.TokenRateLimitBurst < 0,
+		c.MaxConcurrentRequestsPerRoute < 0, c.MaxResponseSize < 0:
+		return errNegativeThrottleSize
+	}
+	return nil
+}
+
+// clientLimiter rate limits requests by client key.
+type clientLimiter struct {
+	limit    rate.Limit
+	burst    int
+	lock     sync.Mutex
+	limiters cache.LRU[string, *rate.Limiter]
+}
+
+func newClientLimiter(limit float64, burst int) *clientLimiter {
+	if burst == 0 {
+		burst = int(math.Max(1, math.Ceil(limit)))
+	}
+	return &clientLimiter{
+		limit:    rate.Limit(limit),
+		burst:    burst,
+		limiters: cache.LRU[string, *rate.Limiter]{Size: maxThrottledClients},
+	}
+}
+
+func (c *clientLimiter) allow(key string, now time.Time) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	limiter, ok := c.limiters.Get(key)
+	if !ok {
+		limiter = rate.NewLimiter(c.limit, c.burst)
+		c.limiters.Put(key, limiter)
+	}
+	return limiter.AllowN(now, 1)
+}
+
+// rateLimitHandler rejects requests of clients that exceed their rate limit.
+type rateLimitHandler struct {
+	handler http.Handler
+	metrics *metrics
+	limiter *clientLimiter
+
+	// clientKey returns the key of the client of a request. Returns false if
+	// the request isn't limited.
+	clientKey    func(*http.Request) (string, bool)
+	rejectReason string
+	rejectMsg    string
+}
+
+// newIPRateLimitHandler returns [handler] wrapped by the IP rate limit of
+// [config]. If [config] doesn't limit IPs, [handler] is returned.
+func newIPRateLimitHandler(handler http.Handler, config ThrottlingConfig, m *metrics) http.Handler {
+	if config.IPRateLimit == 0 {
+		return handler
+	}
+	return &rateLimitHandler{
+		handler:      handler,
+		metrics:      m,
+		limiter:      newClientLimiter(config.IPRateLimit, config.IPRateLimitBurst),
+		clientKey:    remoteIP,
+		rejectReason: rejectReasonIPRateLimit,
+		rejectMsg:    "API call rejected because the IP exceeded its rate limit",
+	}
+}
+
+// newTokenRateLimitHandler returns [handler] wrapped by the auth token rate
+// limit of [config]. Only requests that were authenticated by an auth token
+// are limited, so that unauthenticated callers can't evict the limiters of
+// valid tokens. If [config] doesn't limit tokens, [handler] is returned.
+func newTokenRateLimitHandler(handler http.Handler, config ThrottlingConfig, m *metrics) http.Handler {
+	if config.TokenRateLimit == 0 {
+		return handler
+	}
+	return &rateLimitHandler{
+		handler: handler,
+		metrics: m,
+		limiter: newClientLimiter(config.TokenRateLimit, config.TokenRateLimitBurst),
+		clientKey: func(r *http.Request) (string, bool) {
+			return api.AuthToken(r.Context())
+		},
+		rejectReason: rejectReasonTokenRateLimit,
+		rejectMsg:    "API call rejected because the auth token exceeded its rate limit",
+	}
+}
+
+func (h *rateLimitHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if key, ok := h.clientKey(r); ok && !h.limiter.allow(key, time.Now()) {
+		h.metrics.numRejected.WithLabelValues(h.rejectReason).Inc()
+		writeRejectedResponse(w, http.StatusTooManyRequests, h.rejectMsg)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+func remoteIP(r *http.Request) (string, bool) {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return ip, true
+}
+
+// throttleMiddleware wraps the handler of a route. Requests exceeding the
+// route's concurrency cap are rejected before they wait for the chain's lock
+// and response bodies are limited in size. Upgraded connections, e.g.
+// websockets, are long-lived and hijack the connection, so they aren't
+// throttled.
+func throttleMiddleware(handler http.Handler, config ThrottlingConfig, m *metrics) http.Handler {
+	if config.MaxResponseSize == 0 && config.MaxConcurrentRequestsPerRoute == 0 {
+		return handler
+	}
+	throttled := handler
+	if config.MaxResponseSize != 0 {
+		throttled = maxResponseSizeMiddleware(throttled, config.MaxResponseSize, m)
+	}
+	if config.MaxConcurrentRequestsPerRoute != 0 {
+		throttled = concurrencyMiddleware(throttled, config.MaxConcurrentRequestsPerRoute, m)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		throttled.ServeHTTP(w, r)
+	})
+}
+
+func concurrencyMiddleware(handler http.Handler, maxConcurrentRequests int, m *metrics) http.Handler {
+	slots := make(chan struct{}, maxConcurrentRequests)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			m.numRejected.WithLabelValues(rejectReasonConcurrency).Inc()
+			writeRejectedResponse(w, http.StatusServiceUnavailable, "API call rejected because the API is processing too many calls")
+			return
+		}
+		defer func() {
+			<-slots
+		}()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func maxResponseSizeMiddleware(handler http.Handler, maxResponseSize int, m *metrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lw := &limitedResponseWriter{
+			ResponseWriter: w,
+			maxSize:        maxResponseSize,
+		}
+		handler.ServeHTTP(lw, r)
+		switch {
+		case lw.hijacked:
+			return
+		case lw.exceeded:
+			m.numRejected.WithLabelValues(rejectReasonMaxResponseSize).Inc()
+			header := w.Header()
+			for key := range header {
+				if key == "Content-Length" || strings.HasPrefix(key, http.TrailerPrefix) {
+					delete(header, key)
+				}
+			}
+			msg := fmt.Sprintf("API call failed because the response exceeds the maximum size of %d bytes", maxResponseSize)
+			if isGRPCRequest(r) {
+				writeGRPCError(w, codes.ResourceExhausted, msg)
+				return
+			}
+			writeRejectedResponse(w, http.StatusInternalServerError, msg)
+		default:
+			lw.writeResponse()
+		}
+	})
+}
+
+// limitedResponseWriter buffers the response until the handler returns and
+// fails writes that exceed the maximum response size. This way, a response
+// that is too large is replaced by an error response instead of being
+// truncated.
+type limitedResponseWriter struct {
+	http.ResponseWriter
+	maxSize int
+
+	statusCode int
+	body       bytes.Buffer
+	exceeded   bool
+	hijacked   bool
+}
+
+func (w *limitedResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+}
+
+func (w *limitedResponseWriter) Write(b []byte) (int, error) {
+	if w.exceeded || w.body.Len()+len(b) > w.maxSize {
+		w.exceeded = true
+		return 0, errResponseTooLarge
+	}
+	return w.body.Write(b)
+}
+
+// Flush is a no-op, as the response is only written once the handler returns.
+func (*limitedResponseWriter) Flush() {}
+
+// Hijack hands the connection over to the handler. Writes to the hijacked
+// connection aren't limited.
+func (w *limitedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackNotSupported
+	}
+	w.hijacked = true
+	return h.Hijack()
+}
+
+func (w *limitedResponseWriter) writeResponse() {
+	if w.statusCode != 0 {
+		w.ResponseWriter.WriteHeader(w.statusCode)
+	}
+	if w.body.Len() != 0 {
+		// Doesn't matter if there's an error while writing. The client has
+		// disconnected.
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
+
+func writeRejectedResponse(w http.ResponseWriter, statusCode int, msg string) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(statusCode)
+	// Doesn't matter if there's an error while writing. They'll get the status
+	// code.
+	_, _ = w.Write([]byte(msg))
+}
@@ -0,0 +1,224 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func newTestMetrics(t *testing.T) *metrics {
+	m, err := newMetrics("", prometheus.NewRegistry())
+	require.NoError(t, err)
+	return m
+}
+
+func numRejected(t *testing.T, m *metrics, reason string) float64 {
+	metric := &dto.Metric{}
+	require.NoError(t, m.numRejected.WithLabelValues(reason).Write(metric))
+	return metric.Counter.GetValue()
+}
+
+func serve(h http.Handler, remoteAddr, tokenID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/info", strings.NewReader(""))
+	req.RemoteAddr = remoteAddr
+	if tokenID != "" {
+		req = req.WithContext(api.WithAuthToken(req.Context(), tokenID))
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	require := require.New(t)
+	m := newTestMetrics(t)
+
+	config := ThrottlingConfig{
+		IPRateLimit:         0.001,
+		IPRateLimitBurst:    2,
+		TokenRateLimit:      0.001,
+		TokenRateLimitBurst: 1,
+	}
+	h := newIPRateLimitHandler(newTokenRateLimitHandler(&testHandler{}, config, m), config, m)
+
+	// The IP limit is tracked per IP, independent of the port.
+	require.Equal(http.StatusOK, serve(h, "1.2.3.4:1", "").Code)
+	require.Equal(http.StatusOK, serve(h, "1.2.3.4:2", "").Code)
+	require.Equal(http.StatusTooManyRequests, serve(h, "1.2.3.4:3", "").Code)
+	require.Equal(http.StatusOK, serve(h, "5.6.7.8:1", "").Code)
+	require.Equal(1.0, numRejected(t, m, rejectReasonIPRateLimit))
+
+	// The token limit is tracked per authenticated token, independent of the
+	// IP.
+	require.Equal(http.StatusOK, serve(h, "10.0.0.1:1", "a").Code)
+	require.Equal(http.StatusTooManyRequests, serve(h, "10.0.0.2:1", "a").Code)
+	require.Equal(http.StatusOK, serve(h, "10.0.0.3:1", "b").Code)
+	require.Equal(1.0, numRejected(t, m, rejectReasonTokenRateLimit))
+
+	// Tokens that weren't authenticated aren't tracked.
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "http://127.0.0.1:9650/ext/info", strings.NewReader(""))
+		req.RemoteAddr = "10.0.0.4:1"
+		req.Header.Set("Authorization", "Bearer a")
+		rr := httptest.NewRecorder()
+		newTokenRateLimitHandler(&testHandler{}, config, m).ServeHTTP(rr, req)
+		require.Equal(http.StatusOK, rr.Code)
+	}
+}
+
+func TestRateLimitHandlerDisabled(t *testing.T) {
+	require := require.New(t)
+	handler := &testHandler{}
+	require.Equal(http.Handler(handler), newIPRateLimitHandler(handler, ThrottlingConfig{}, newTestMetrics(t)))
+	require.Equal(http.Handler(handler), newTokenRateLimitHandler(handler, ThrottlingConfig{}, newTestMetrics(t)))
+	require.Equal(http.Handler(handler), throttleMiddleware(handler, ThrottlingConfig{}, newTestMetrics(t)))
+}
+
+func TestConcurrencyMiddleware(t *testing.T) {
+	require := require.New(t)
+	m := newTestMetrics(t)
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	h := throttleMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		started <- struct{}{}
+		<-release
+	}), ThrottlingConfig{MaxConcurrentRequestsPerRoute: 1}, m)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serve(h, "1.2.3.4:1", "")
+	}()
+	<-started
+
+	require.Equal(http.StatusServiceUnavailable, serve(h, "1.2.3.4:1", "").Code)
+	require.Equal(1.0, numRejected(t, m, rejectReasonConcurrency))
+
+	close(release)
+	wg.Wait()
+
+	go func() {
+		<-started
+	}()
+	require.Equal(http.StatusOK, serve(h, "1.2.3.4:1", "").Code)
+}
+
+func TestConcurrencyMiddlewareUpgrade(t *testing.T) {
+	require := require.New(t)
+	m := newTestMetrics(t)
+
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	h := throttleMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			started <- struct{}{}
+			<-release
+		}
+	}), ThrottlingConfig{MaxConcurrentRequestsPerRoute: 1}, m)
+
+	// Upgraded connections don't hold a slot for their whole life.
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		req := httptest.NewRequest(http.MethodGet, "http://127.0.0.1:9650/ext/info/events", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	<-started
+
+	require.Equal(http.StatusOK, serve(h, "1.2.3.4:1", "").Code)
+	require.Zero(numRejected(t, m, rejectReasonConcurrency))
+
+	close(release)
+	wg.Wait()
+}
+
+func TestMaxResponseSizeMiddleware(t *testing.T) {
+	tests := map[string]struct {
+		writes       []string
+		statusCode   int
+		expectedCode int
+		expectedBody string
+		rejected     bool
+	}{
+		"within limit": {
+			writes:       []string{"abc", "de"},
+			expectedCode: http.StatusOK,
+			expectedBody: "abcde",
+		},
+		"status code within limit": {
+			writes:       []string{"abc"},
+			statusCode:   http.StatusBadRequest,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: "abc",
+		},
+		"no body": {
+			statusCode:   http.StatusNoContent,
+			expectedCode: http.StatusNoContent,
+		},
+		"exceeds limit": {
+			writes:       []string{"abcdef"},
+			statusCode:   http.StatusOK,
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "API call failed because the response exceeds the maximum size of 5 bytes",
+			rejected:     true,
+		},
+		"exceeds limit after write": {
+			writes:       []string{"abc", "def"},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "API call failed because the response exceeds the maximum size of 5 bytes",
+			rejected:     true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			m := newTestMetrics(t)
+
+			h := throttleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if test.statusCode != 0 {
+					w.WriteHeader(test.statusCode)
+				}
+				for _, write := range test.writes {
+					_, _ = w.Write([]byte(write))
+				}
+			}), ThrottlingConfig{MaxResponseSize: 5}, m)
+
+			rr := serve(h, "1.2.3.4:1", "")
+			require.Equal(test.expectedCode, rr.Code)
+			require.Equal(test.expectedBody, rr.Body.String())
+			if test.rejected {
+				require.Equal(1.0, numRejected(t, m, rejectReasonMaxResponseSize))
+			}
+		})
+	}
+}
+
+func TestThrottlingConfigVerify(t *testing.T) {
+	require := require.New(t)
+
+	require.NoError((&ThrottlingConfig{IPRateLimit: 1, MaxResponseSize: 1}).Verify())
+	require.ErrorIs((&ThrottlingConfig{TokenRateLimit: -1}).Verify(), errNegativeRateLimit)
+	require.ErrorIs((&ThrottlingConfig{MaxConcurrentRequestsPerRoute: -1}).Verify(), errNegativeThrottleSize)
+}
@@ -16,6 +16,7 @@ type metrics struct {
 	numProcessing *prometheus.GaugeVec
 	numCalls      *prometheus.CounterVec
 	totalDuration *prometheus.GaugeVec
+	numRejected   *prometheus.CounterVec
 }
 
 func newMetrics(namespace string, registerer prometheus.Registerer) (*metrics, error) {
@@ -44,6 +45,14 @@ func newMetrics(namespace string, registerer prometheus.Registerer) (*metrics, e
 			},
 			[]string{"base"},
 		),
+		numRejected: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "calls_rejected",
+				Help:      "The number of calls this API has rejected because a throttling limit was exceeded",
+			},
+			[]string{"reason"},
+		),
 	}
 
 	errs := wrappers.Errs{}
@@ -51,6 +60,7 @@ func newMetrics(namespace string, registerer prometheus.Registerer) (*metrics, e
 		registerer.Register(m.numProcessing),
 		registerer.Register(m.numCalls),
 		registerer.Register(m.totalDuration),
+		registerer.Register(m.numRejected),
 	)
 	return m, errs.Err
 }
@@ -77,6 +77,20 @@ func (mr *MockServerMockRecorder) AddAliasesWithReadLock(arg0 interface{}, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAliasesWithReadLock", reflect.TypeOf((*MockServer)(nil).AddAliasesWithReadLock), varargs...)
 }
 
+// AddGRPCRoute mocks base method.
+func (m *MockServer) AddGRPCRoute(arg0 *common.HTTPHandler, arg1 *sync.RWMutex) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddGRPCRoute", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddGRPCRoute indicates an expected call of AddGRPCRoute.
+func (mr *MockServerMockRecorder) AddGRPCRoute(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddGRPCRoute", reflect.TypeOf((*MockServer)(nil).AddGRPCRoute), arg0, arg1)
+}
+
 // AddRoute mocks base method.
 func (m *MockServer) AddRoute(arg0 *common.HTTPHandler, arg1 *sync.RWMutex, arg2, arg3 string) error {
 	m.ctrl.T.Helper()
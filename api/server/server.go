@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -23,6 +33,9 @@ import (
 
 	"go.uber.org/zap"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
 	"github.com/ava-labs/avalanchego/api"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -72,6 +85,9 @@ type Server interface {
 	// That is, add <route, handler> pairs to server so that API calls can be
 	// made to the VM.
 	RegisterChain(chainName string, ctx *snow.ConsensusContext, vm common.VM)
+	// AddGRPCRoute serves the gRPC services of [handler], which must be a
+	// *grpc.Server, on the API server's root.
+	AddGRPCRoute(handler *common.HTTPHandler, lock *sync.RWMutex) error
 	// Shutdown this server
 	Shutdown() error
 }
@@ -81,6 +97,8 @@ type HTTPConfig struct {
 	ReadHeaderTimeout time.Duration `json:"readHeaderTimeout"`
 	WriteTimeout      time.Duration `json:"writeHeaderTimeout"`
 	IdleTimeout       time.Duration `json:"idleTimeout"`
+
+	ThrottlingConfig `json:"throttlingConfig"`
 }
 
 type server struct {
@@ -99,8 +117,12 @@ type server struct {
 
 	metrics *metrics
 
+	throttlingConfig ThrottlingConfig
+
 	// Maps endpoints to handlers
 	router *router
+	// Maps gRPC services to handlers
+	grpcRouter *grpcRouter
 
 	srv *http.Server
 }
@@ -127,6 +149,7 @@ func New(
 	}
 
 	router := newRouter()
+	grpcRouter := newGRPCRouter()
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
 		AllowCredentials: true,
@@ -136,30 +159,44 @@ func New(
 		func(w http.ResponseWriter, r *http.Request) {
 			// Attach this node's ID as a header
 			w.Header().Set("node-id", nodeID.String())
+			if isGRPCRequest(r) {
+				grpcRouter.ServeHTTP(w, r)
+				return
+			}
 			gzipHandler.ServeHTTP(w, r)
 		},
 	)
 
+	// Auth tokens are rate limited once they are authenticated by the
+	// wrappers.
+	handler = newTokenRateLimitHandler(handler, httpConfig.ThrottlingConfig, m)
 	for _, wrapper := range wrappers {
 		handler = wrapper.WrapHandler(handler)
 	}
+	// IP rate limits are applied first, so that throttled requests are
+	// rejected as cheaply as possible.
+	handler = newIPRateLimitHandler(handler, httpConfig.ThrottlingConfig, m)
 
 	log.Info("API created",
 		zap.Strings("allowedOrigins", allowedOrigins),
 	)
 
 	return &server{
-		log:             log,
-		factory:         factory,
-		listenHost:      host,
-		listenPort:      port,
-		shutdownTimeout: shutdownTimeout,
-		tracingEnabled:  tracingEnabled,
-		tracer:          tracer,
-		metrics:         m,
-		router:          router,
+		log:              log,
+		factory:          factory,
+		listenHost:       host,
+		listenPort:       port,
+		shutdownTimeout:  shutdownTimeout,
+		tracingEnabled:   tracingEnabled,
+		tracer:           tracer,
+		metrics:          m,
+		throttlingConfig: httpConfig.ThrottlingConfig,
+		router:           router,
+		grpcRouter:       grpcRouter,
 		srv: &http.Server{
-			Handler:           handler,
+			// gRPC calls require HTTP/2, which is served in cleartext if TLS
+			// isn't enabled.
+			Handler:           h2c.NewHandler(handler, &http2.Server{}),
 			ReadTimeout:       httpConfig.ReadTimeout,
 			ReadHeaderTimeout: httpConfig.ReadHeaderTimeout,
 			WriteTimeout:      httpConfig.WriteTimeout,
@@ -199,6 +236,7 @@ func (s *server) DispatchTLS(certBytes, keyBytes []byte) error {
 	config := &tls.Config{
 		MinVersion:   tls.VersionTLS12,
 		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{http2.NextProtoTLS, "http/1.1"},
 	}
 
 	listener, err := tls.Listen("tcp", listenAddress, config)
@@ -256,6 +294,15 @@ func (s *server) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 			)
 			continue
 		}
+		if extension == common.GRPCExtension {
+			if err := s.addChainGRPCRoute(chainName, handler, ctx); err != nil {
+				s.log.Error("error adding gRPC services",
+					zap.String("chainName", chainName),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
 		if err := s.addChainRoute(chainName, handler, ctx, defaultEndpoint, extension); err != nil {
 			s.log.Error("error adding route",
 				zap.Error(err),
@@ -289,6 +336,9 @@ func (s *server) addChainRoute(chainName string, handler *common.HTTPHandler, ct
 	}
 	// Apply middleware to reject calls to the handler before the chain finishes bootstrapping
 	h = rejectMiddleware(h, ctx)
+	// Apply middleware to reject calls exceeding the route's limits before
+	// they wait for the chain's lock
+	h = throttleMiddleware(h, s.throttlingConfig, s.metrics)
 	h = s.metrics.wrapHandler(chainName, h)
 	return s.router.AddRouter(url, endpoint, h)
 }
@@ -328,6 +378,7 @@ func (s *server) addRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
+	h = throttleMiddleware(h, s.throttlingConfig, s.metrics)
 	h = s.metrics.wrapHandler(base, h)
 	return s.router.AddRouter(url, endpoint, h)
 }
@@ -228,6 +228,8 @@ func (a *app) Start() error {
 	// Note that if the node config said to not dynamically resolve and
 	// update our public IP, [p.config.IPUdater] is a no-op implementation.
 	go a.config.IPUpdater.Dispatch(log)
+	// Regularly update the endpoints advertised in addition to our public IP.
+	go a.config.ExtraIPsUpdater.Dispatch(log)
 
 	if err := a.node.Initialize(&a.config, log, logFactory); err != nil {
 		log.Fatal("error initializing node",
@@ -235,6 +237,7 @@ func (a *app) Start() error {
 		)
 		mapper.UnmapAllPorts()
 		a.config.IPUpdater.Stop()
+		a.config.ExtraIPsUpdater.Stop()
 		log.Stop()
 		logFactory.Close()
 		return err
@@ -254,6 +257,7 @@ func (a *app) Start() error {
 		defer func() {
 			mapper.UnmapAllPorts()
 			a.config.IPUpdater.Stop()
+			a.config.ExtraIPsUpdater.Stop()
 
 			// If [p.node.Dispatch()] panics, then we should log the panic and
 			// then re-raise the panic. This is why the above defer is broken
@@ -0,0 +1,46 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+)
+
+var _ DAGChainVM = (*dagChainVM)(nil)
+
+// DAGChainVM is the VM of a chain, that runs the Avalanche consensus engine
+// until it is linearized. It is passed to the registrants of such chains.
+type DAGChainVM interface {
+	vertex.LinearizableVMWithEngine
+	common.WrappedVM
+
+	// VertexStorage returns the storage of the vertices of the chain
+	VertexStorage() vertex.Storage
+
+	// LinearVM returns the VM, that is used by the Snowman engines of the
+	// chain. Returns false, if the chain isn't linearized yet.
+	// Assumes [ctx.Lock] is held.
+	LinearVM() (block.ChainVM, bool)
+}
+
+type dagChainVM struct {
+	vertex.LinearizableVMWithEngine
+	vtxStorage     vertex.Storage
+	linearVM       block.ChainVM
+	linearizableVM *initializeOnLinearizeVM
+}
+
+func (vm *dagChainVM) Unwrap() common.VM {
+	return vm.LinearizableVMWithEngine
+}
+
+func (vm *dagChainVM) VertexStorage() vertex.Storage {
+	return vm.vtxStorage
+}
+
+func (vm *dagChainVM) LinearVM() (block.ChainVM, bool) {
+	return vm.linearVM, vm.linearizableVM.linearized
+}
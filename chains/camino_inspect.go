@@ -0,0 +1,68 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+var (
+	errUnknownChain      = errors.New("unknown chain")
+	errNotSnowmanRunning = errors.New("chain isn't running the snowman consensus engine")
+	errNotInspectable    = errors.New("snowman engine can't be inspected")
+)
+
+func (m *manager) SnowmanConsensusState(chainID ids.ID) (*smeng.ConsensusState, error) {
+	var state *smeng.ConsensusState
+	err := m.inspectSnowman(chainID, func(inspector smeng.Inspector) {
+		state = inspector.ConsensusState()
+	})
+	return state, err
+}
+
+func (m *manager) SnowmanPreferenceChanges(chainID ids.ID, after uint64) ([]smeng.PreferenceChange, error) {
+	var changes []smeng.PreferenceChange
+	err := m.inspectSnowman(chainID, func(inspector smeng.Inspector) {
+		changes = inspector.PreferenceChanges(after)
+	})
+	return changes, err
+}
+
+// inspectSnowman calls [f] with the snowman engine of the chain while holding
+// the context lock of the chain
+func (m *manager) inspectSnowman(chainID ids.ID, f func(smeng.Inspector)) error {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[chainID]
+	m.chainsLock.Unlock()
+	if !exists {
+		return fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+
+	ctx := chain.Context()
+	ctx.Lock.RLock()
+	defer ctx.Lock.RUnlock()
+
+	engineState := ctx.State.Get()
+	if engineState.Type != p2p.EngineType_ENGINE_TYPE_SNOWMAN || engineState.State != snow.NormalOp {
+		return fmt.Errorf("%w: chain is in state %s", errNotSnowmanRunning, engineState.State)
+	}
+
+	engine := snowmanEngine(chain)
+	if engine == nil {
+		return errNotSnowmanRunning
+	}
+	inspector, ok := engine.(smeng.Inspector)
+	if !ok {
+		return errNotInspectable
+	}
+	f(inspector)
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/handler"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/constants"
+
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+)
+
+var (
+	errUnknownSubnet    = errors.New("unknown subnet")
+	errTooFewValidators = errors.New("sample size exceeds the number of validators")
+)
+
+func (m *manager) ProposeConsensusParameters(subnetID ids.ID, params snowball.Parameters, dryRun bool) (*smeng.FinalityEstimate, error) {
+	if err := params.Verify(); err != nil {
+		return nil, err
+	}
+
+	if _, err := m.subnet(subnetID); err != nil {
+		return nil, err
+	}
+
+	vdrsSubnetID := subnetID
+	if !m.StakingEnabled {
+		// Staking is disabled. Every peer validates every subnet.
+		vdrsSubnetID = constants.PrimaryNetworkID
+	}
+	vdrs, ok := m.Validators.Get(vdrsSubnetID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownSubnet, subnetID)
+	}
+	if numVdrs := vdrs.Len(); params.K > numVdrs {
+		return nil, fmt.Errorf("%w: %d > %d", errTooFewValidators, params.K, numVdrs)
+	}
+
+	engines := m.subnetSnowmanEngines(subnetID)
+	var responses []poll.Response
+	for _, engine := range engines {
+		recorder, ok := engine.Engine.(smeng.ResponseRecorder)
+		if !ok {
+			continue
+		}
+		engine.lock.RLock()
+		responses = append(responses, recorder.Responses()...)
+		engine.lock.RUnlock()
+	}
+	var estimate *smeng.FinalityEstimate
+	if len(responses) > 0 {
+		var err error
+		estimate, err = smeng.EstimateFinality(params, responses)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dryRun {
+		return estimate, nil
+	}
+
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ConsensusParamsDB.Put(subnetID[:], paramsBytes); err != nil {
+		return nil, err
+	}
+
+	m.Log.Info("proposed consensus parameters",
+		zap.Stringer("subnetID", subnetID),
+		zap.Reflect("parameters", params),
+	)
+	return estimate, setParameters(engines, params)
+}
+
+func (m *manager) ClearConsensusParameters(subnetID ids.ID) error {
+	sb, err := m.subnet(subnetID)
+	if err != nil {
+		return err
+	}
+	if err := m.ConsensusParamsDB.Delete(subnetID[:]); err != nil {
+		return err
+	}
+
+	params := sb.Config().ConsensusParameters.Parameters
+	m.Log.Info("cleared proposed consensus parameters",
+		zap.Stringer("subnetID", subnetID),
+		zap.Reflect("parameters", params),
+	)
+	return setParameters(m.subnetSnowmanEngines(subnetID), params)
+}
+
+// subnet returns the subnet [subnetID], if the node tracks it
+func (m *manager) subnet(subnetID ids.ID) (subnets.Subnet, error) {
+	m.subnetsLock.Lock()
+	defer m.subnetsLock.Unlock()
+
+	sb, exists := m.subnets[subnetID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", errUnknownSubnet, subnetID)
+	}
+	return sb, nil
+}
+
+// consensusParameters returns the consensus parameters of the chains of
+// [subnetID], with the last proposed snowball parameters if any
+func (m *manager) consensusParameters(sb subnets.Subnet, subnetID ids.ID) (avalanche.Parameters, error) {
+	params := sb.Config().ConsensusParameters
+
+	paramsBytes, err := m.ConsensusParamsDB.Get(subnetID[:])
+	if err == database.ErrNotFound {
+		return params, nil
+	}
+	if err != nil {
+		return params, err
+	}
+	if err := json.Unmarshal(paramsBytes, &params.Parameters); err != nil {
+		return params, fmt.Errorf("couldn't parse proposed consensus parameters of subnet %s: %w", subnetID, err)
+	}
+	return params, nil
+}
+
+// setParameters passes [params] to the running snowman [engines], which
+// switch to them once no blocks are processing
+func setParameters(engines []lockedEngine, params snowball.Parameters) error {
+	for _, engine := range engines {
+		setter, ok := engine.Engine.(smeng.ParametersSetter)
+		if !ok {
+			continue
+		}
+		engine.lock.Lock()
+		err := setter.SetParameters(params)
+		engine.lock.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockedEngine is the snowman engine of a chain with the context lock of the
+// chain that must be held while calling the engine
+type lockedEngine struct {
+	common.Engine
+	lock *sync.RWMutex
+}
+
+// subnetSnowmanEngines returns the snowman engines of the chains of [subnetID]
+func (m *manager) subnetSnowmanEngines(subnetID ids.ID) []lockedEngine {
+	m.chainsLock.Lock()
+	defer m.chainsLock.Unlock()
+
+	var engines []lockedEngine
+	for _, chain := range m.chains {
+		ctx := chain.Context()
+		if ctx.SubnetID != subnetID {
+			continue
+		}
+		engine := snowmanEngine(chain)
+		if engine == nil {
+			continue
+		}
+		engines = append(engines, lockedEngine{
+			Engine: engine,
+			lock:   &ctx.Lock,
+		})
+	}
+	return engines
+}
+
+// snowmanEngine returns the snowman consensus engine of [chain], or nil if the
+// chain doesn't run the snowman consensus engine
+func snowmanEngine(chain handler.Handler) common.Engine {
+	engine, _ := chain.GetEngineManager().Get(p2p.EngineType_ENGINE_TYPE_SNOWMAN).Get(snow.NormalOp)
+	return engine
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestProposeConsensusParameters(t *testing.T) {
+	require := require.New(t)
+
+	subnetID := ids.GenerateTestID()
+	vdrs := validators.NewSet()
+	require.NoError(vdrs.Add(ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	require.NoError(vdrs.Add(ids.GenerateTestNodeID(), nil, ids.Empty, 1))
+	vdrsManager := validators.NewManager()
+	require.True(vdrsManager.Add(subnetID, vdrs))
+
+	config := subnets.Config{
+		ConsensusParameters: avalanche.Parameters{
+			Parameters: snowball.Parameters{
+				K:                     1,
+				Alpha:                 1,
+				BetaVirtuous:          1,
+				BetaRogue:             1,
+				ConcurrentRepolls:     1,
+				OptimalProcessing:     1,
+				MaxOutstandingItems:   1,
+				MaxItemProcessingTime: time.Minute,
+			},
+			Parents:   2,
+			BatchSize: 1,
+		},
+	}
+	sb := subnets.New(ids.EmptyNodeID, config)
+
+	db := memdb.New()
+	newManager := func() *manager {
+		m := New(&ManagerConfig{
+			StakingEnabled:    true,
+			Log:               logging.NoLog{},
+			Validators:        vdrsManager,
+			ConsensusParamsDB: db,
+		}).(*manager)
+		m.subnets[subnetID] = sb
+		return m
+	}
+	m := newManager()
+
+	_, err := m.ProposeConsensusParameters(ids.GenerateTestID(), config.ConsensusParameters.Parameters, false)
+	require.ErrorIs(err, errUnknownSubnet)
+
+	params := config.ConsensusParameters.Parameters
+	params.K = 3
+	params.Alpha = 2
+	_, err = m.ProposeConsensusParameters(subnetID, params, false)
+	require.ErrorIs(err, errTooFewValidators)
+
+	params.K = 2
+	estimate, err := m.ProposeConsensusParameters(subnetID, params, true)
+	require.NoError(err)
+	require.Nil(estimate)
+
+	// A dry run doesn't store the parameters
+	consensusParams, err := m.consensusParameters(sb, subnetID)
+	require.NoError(err)
+	require.Equal(config.ConsensusParameters, consensusParams)
+
+	_, err = m.ProposeConsensusParameters(subnetID, params, false)
+	require.NoError(err)
+
+	// The persisted parameters are also used by chains created after a restart
+	m = newManager()
+	consensusParams, err = m.consensusParameters(sb, subnetID)
+	require.NoError(err)
+	require.Equal(params, consensusParams.Parameters)
+	require.Equal(config.ConsensusParameters.Parents, consensusParams.Parents)
+
+	require.ErrorIs(m.ClearConsensusParameters(ids.GenerateTestID()), errUnknownSubnet)
+
+	// Clearing the proposal restores the configured parameters
+	require.NoError(m.ClearConsensusParameters(subnetID))
+	consensusParams, err = m.consensusParameters(sb, subnetID)
+	require.NoError(err)
+	require.Equal(config.ConsensusParameters, consensusParams)
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+// VerifyingRegistrant is a Registrant, that verifies chains before they are
+// created. Chains, that fail the verification, aren't created.
+type VerifyingRegistrant interface {
+	Registrant
+
+	// Called when a chain was built, before it is registered
+	// [vm] should be a vertex.DAGVM or block.ChainVM
+	VerifyChain(chainName string, ctx *snow.ConsensusContext, vm common.VM) error
+}
+
+// verifyRegistrants lets the registrants, that verify chains, verify [chain]
+func (m *manager) verifyRegistrants(chain *chain) error {
+	for _, registrant := range m.registrants {
+		verifier, ok := registrant.(VerifyingRegistrant)
+		if !ok {
+			continue
+		}
+		if err := verifier.VerifyChain(chain.Name, chain.Context, chain.VM); err != nil {
+			return fmt.Errorf("couldn't verify chain: %w", err)
+		}
+	}
+	return nil
+}
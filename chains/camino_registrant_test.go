@@ -0,0 +1,49 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chains
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+)
+
+var errTestVerify = errors.New("test verify")
+
+type testRegistrant struct {
+	verifyErr error
+	verified  []string
+}
+
+func (*testRegistrant) RegisterChain(string, *snow.ConsensusContext, common.VM) {}
+
+func (r *testRegistrant) VerifyChain(chainName string, _ *snow.ConsensusContext, _ common.VM) error {
+	r.verified = append(r.verified, chainName)
+	return r.verifyErr
+}
+
+func TestVerifyRegistrants(t *testing.T) {
+	require := require.New(t)
+
+	verifier := &testRegistrant{}
+	m := &manager{}
+	m.AddRegistrant(verifier)
+	m.AddRegistrant(&nonVerifyingRegistrant{})
+
+	chain := &chain{Name: "test", Context: snow.DefaultConsensusContextTest()}
+	require.NoError(m.verifyRegistrants(chain))
+	require.Equal([]string{"test"}, verifier.verified)
+
+	verifier.verifyErr = errTestVerify
+	err := m.verifyRegistrants(chain)
+	require.ErrorIs(err, errTestVerify)
+}
+
+type nonVerifyingRegistrant struct{}
+
+func (*nonVerifyingRegistrant) RegisterChain(string, *snow.ConsensusContext, common.VM) {}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -29,6 +39,8 @@ type initializeOnLinearizeVM struct {
 	vertex.DAGVM
 	vmToInitialize common.VM
 	vmToLinearize  *linearizeOnInitializeVM
+	// Set, once [vmToInitialize] was initialized. Assumes [ctx.Lock] is held.
+	linearized bool
 
 	registerer   metrics.OptionalGatherer
 	ctx          *snow.Context
@@ -44,7 +56,7 @@ type initializeOnLinearizeVM struct {
 func (vm *initializeOnLinearizeVM) Linearize(ctx context.Context, stopVertexID ids.ID) error {
 	vm.vmToLinearize.stopVertexID = stopVertexID
 	vm.ctx.Metrics = vm.registerer
-	return vm.vmToInitialize.Initialize(
+	err := vm.vmToInitialize.Initialize(
 		ctx,
 		vm.ctx,
 		vm.dbManager,
@@ -55,6 +67,8 @@ func (vm *initializeOnLinearizeVM) Linearize(ctx context.Context, stopVertexID i
 		vm.fxs,
 		vm.appSender,
 	)
+	vm.linearized = err == nil
+	return err
 }
 
 // linearizeOnInitializeVM transforms the proposervm's call to Initialize into a
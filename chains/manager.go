@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -23,12 +33,14 @@ import (
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
 	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/state"
 	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -36,7 +48,9 @@ import (
 	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/syncer"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
 	"github.com/ava-labs/avalanchego/snow/networking/handler"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/sender"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
@@ -127,6 +141,28 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns the processing blocks and outstanding polls of the snowman
+	// engine of the chain with the given ID
+	SnowmanConsensusState(ids.ID) (*smeng.ConsensusState, error)
+
+	// Returns the preference changes of the snowman engine of the chain with
+	// the given ID with a sequence number greater than the given one
+	SnowmanPreferenceChanges(chainID ids.ID, after uint64) ([]smeng.PreferenceChange, error)
+
+	// Verifies the snowball parameters for the subnet with the given ID and,
+	// unless [dryRun], persists them. The running snowman engines of the
+	// subnet's chains switch to the parameters once no blocks are processing,
+	// engines created later use them from the start.
+	// Returns the finality latency expected with the parameters, based on the
+	// recent poll responses of the subnet's chains. The estimate is nil if no
+	// responses were recorded.
+	ProposeConsensusParameters(subnetID ids.ID, params snowball.Parameters, dryRun bool) (*smeng.FinalityEstimate, error)
+
+	// Removes the persisted snowball parameters for the subnet with the given
+	// ID. The snowman engines of the subnet's chains switch back to the
+	// configured parameters of the subnet once no blocks are processing.
+	ClearConsensusParameters(subnetID ids.ID) error
+
 	// Starts the chain creator with the initial platform chain parameters, must
 	// be called once.
 	StartChainCreator(platformChain ChainParameters) error
@@ -190,10 +226,12 @@ type ManagerConfig struct {
 	Keystore                    keystore.Keystore
 	AtomicMemory                *atomic.Memory
 	AVAXAssetID                 ids.ID
-	XChainID                    ids.ID          // ID of the X-Chain,
-	CChainID                    ids.ID          // ID of the C-Chain,
-	CriticalChains              set.Set[ids.ID] // Chains that can't exit gracefully
-	TimeoutManager              timeout.Manager // Manages request timeouts when sending messages to other validators
+	XChainID                    ids.ID             // ID of the X-Chain,
+	CChainID                    ids.ID             // ID of the C-Chain,
+	CriticalChains              set.Set[ids.ID]    // Chains that can't exit gracefully
+	TimeoutManager              timeout.Manager    // Manages request timeouts when sending messages to other validators
+	Reputation                  reputation.Tracker // Tracks the behaviour of peers
+	Bandwidth                   bandwidth.Tracker  // Accounts for the traffic of chains
 	Health                      health.Registerer
 	RetryBootstrap              bool                      // Should Bootstrap be retried
 	RetryBootstrapWarnFrequency int                       // Max number of times to retry bootstrap before warning the node operator
@@ -225,6 +263,9 @@ type ManagerConfig struct {
 	StateSyncBeacons []ids.NodeID
 
 	ChainDataDir string
+
+	// Stores the last proposed snowball parameters of each subnet
+	ConsensusParamsDB database.Database
 }
 
 type manager struct {
@@ -288,6 +329,7 @@ func (m *manager) QueueChainCreation(chainParams ChainParameters) {
 		}
 		sb = subnets.New(m.NodeID, sbConfig)
 		m.subnets[chainParams.SubnetID] = sb
+		m.Bandwidth.SetQuota(subnetID, sbConfig.BandwidthQuota)
 	}
 	addedChain := sb.AddChain(chainParams.ID)
 	m.subnetsLock.Unlock()
@@ -484,6 +526,8 @@ func (m *manager) buildChain(chainParams ChainParameters, sb subnets.Subnet) (*c
 		BlockAcceptor:       m.BlockAcceptorGroup,
 		TxAcceptor:          m.TxAcceptorGroup,
 		VertexAcceptor:      m.VertexAcceptorGroup,
+		Reputation:          m.Reputation,
+		Bandwidth:           m.Bandwidth,
 		Registerer:          consensusMetrics,
 		AvalancheRegisterer: avalancheConsensusMetrics,
 	}
@@ -573,6 +617,10 @@ func (m *manager) buildChain(chainParams ChainParameters, sb subnets.Subnet) (*c
 		return nil, errUnknownVMType
 	}
 
+	if err := m.verifyRegistrants(chain); err != nil {
+		return nil, err
+	}
+
 	// Register the chain with the timeout manager
 	if err := m.TimeoutManager.RegisterChain(ctx); err != nil {
 		return nil, err
@@ -638,7 +686,7 @@ func (m *manager) createAvalancheChain(
 	avalancheMessageSender, err := sender.New(
 		ctx,
 		m.MsgCreator,
-		m.Net,
+		sender.NewMeteredSender(ctx, m.Net),
 		m.ManagerConfig.Router,
 		m.TimeoutManager,
 		p2p.EngineType_ENGINE_TYPE_AVALANCHE,
@@ -666,7 +714,7 @@ func (m *manager) createAvalancheChain(
 	snowmanMessageSender, err := sender.New(
 		ctx,
 		m.MsgCreator,
-		m.Net,
+		sender.NewMeteredSender(ctx, m.Net),
 		m.ManagerConfig.Router,
 		m.TimeoutManager,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
@@ -807,7 +855,10 @@ func (m *manager) createAvalancheChain(
 		appSender:    snowmanMessageSender,
 	}
 
-	consensusParams := sb.Config().ConsensusParameters
+	consensusParams, err := m.consensusParameters(sb, ctx.SubnetID)
+	if err != nil {
+		return nil, err
+	}
 	sampleK := consensusParams.K
 	if uint64(sampleK) > bootstrapWeight {
 		sampleK = int(bootstrapWeight)
@@ -993,7 +1044,12 @@ func (m *manager) createAvalancheChain(
 	return &chain{
 		Name:    chainAlias,
 		Context: ctx,
-		VM:      vm,
+		VM: &dagChainVM{
+			LinearizableVMWithEngine: vm,
+			vtxStorage:               vtxManager,
+			linearVM:                 vmWrappingProposerVM,
+			linearizableVM:           linearizableVM,
+		},
 		Handler: h,
 	}, nil
 }
@@ -1040,7 +1096,7 @@ func (m *manager) createSnowmanChain(
 	messageSender, err := sender.New(
 		ctx,
 		m.MsgCreator,
-		m.Net,
+		sender.NewMeteredSender(ctx, m.Net),
 		m.ManagerConfig.Router,
 		m.TimeoutManager,
 		p2p.EngineType_ENGINE_TYPE_SNOWMAN,
@@ -1163,7 +1219,10 @@ func (m *manager) createSnowmanChain(
 		return nil, err
 	}
 
-	consensusParams := sb.Config().ConsensusParameters
+	consensusParams, err := m.consensusParameters(sb, ctx.SubnetID)
+	if err != nil {
+		return nil, err
+	}
 	sampleK := consensusParams.K
 	if uint64(sampleK) > bootstrapWeight {
 		sampleK = int(bootstrapWeight)
@@ -1350,6 +1409,7 @@ func (m *manager) StartChainCreator(platformParams ChainParameters) error {
 	m.subnetsLock.Lock()
 	sb := subnets.New(m.NodeID, sbConfig)
 	m.subnets[platformParams.SubnetID] = sb
+	m.Bandwidth.SetQuota(platformParams.SubnetID, sbConfig.BandwidthQuota)
 	sb.AddChain(platformParams.ID)
 	m.subnetsLock.Unlock()
 
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -5,6 +15,8 @@ package chains
 
 import (
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 )
 
@@ -56,6 +68,22 @@ func (testManager) IsBootstrapped(ids.ID) bool {
 	return false
 }
 
+func (testManager) SnowmanConsensusState(ids.ID) (*snowman.ConsensusState, error) {
+	return nil, nil
+}
+
+func (testManager) SnowmanPreferenceChanges(ids.ID, uint64) ([]snowman.PreferenceChange, error) {
+	return nil, nil
+}
+
+func (testManager) ProposeConsensusParameters(ids.ID, snowball.Parameters, bool) (*snowman.FinalityEstimate, error) {
+	return nil, nil
+}
+
+func (testManager) ClearConsensusParameters(ids.ID) error {
+	return nil
+}
+
 func (testManager) Lookup(s string) (ids.ID, error) {
 	return ids.FromString(s)
 }
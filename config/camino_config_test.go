@@ -0,0 +1,132 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api/auth"
+	"github.com/ava-labs/avalanchego/ipcs"
+	"github.com/ava-labs/avalanchego/ipcs/eventsink"
+	"github.com/ava-labs/avalanchego/node"
+)
+
+func TestGetIPCSinkConfig(t *testing.T) {
+	tests := map[string]struct {
+		flags       map[string]interface{}
+		expected    node.IPCConfig
+		expectedErr error
+	}{
+		"no sink": {
+			flags: map[string]interface{}{},
+			expected: node.IPCConfig{
+				IPCPath: ipcs.DefaultBaseURL,
+			},
+		},
+		"file sink": {
+			flags: map[string]interface{}{
+				IpcsSinkTypeKey:         node.IPCSinkTypeFile,
+				IpcsSinkChainIDsKey:     "11111111111111111111111111111111LpoYY",
+				IpcsSinkFileDirKey:      "/events",
+				IpcsSinkFileMaxSizeKey:  1024,
+				IpcsSinkFileMaxFilesKey: 2,
+			},
+			expected: node.IPCConfig{
+				IPCPath:             ipcs.DefaultBaseURL,
+				IPCSinkType:         node.IPCSinkTypeFile,
+				IPCSinkChainIDs:     []string{"11111111111111111111111111111111LpoYY"},
+				IPCSinkFileDir:      "/events",
+				IPCSinkFileMaxSize:  1024,
+				IPCSinkFileMaxFiles: 2,
+			},
+		},
+		"kafka sink": {
+			flags: map[string]interface{}{
+				IpcsSinkTypeKey:              node.IPCSinkTypeKafka,
+				IpcsSinkKafkaBrokersKey:      "127.0.0.1:9092,127.0.0.1:9093",
+				IpcsSinkKafkaRequiredAcksKey: 1,
+				IpcsSinkKafkaTimeoutKey:      time.Second,
+			},
+			expected: node.IPCConfig{
+				IPCPath:     ipcs.DefaultBaseURL,
+				IPCSinkType: node.IPCSinkTypeKafka,
+				IPCSinkKafkaConfig: eventsink.KafkaConfig{
+					Brokers:      []string{"127.0.0.1:9092", "127.0.0.1:9093"},
+					RequiredAcks: 1,
+					Timeout:      time.Second,
+				},
+			},
+		},
+		"kafka sink without brokers": {
+			flags: map[string]interface{}{
+				IpcsSinkTypeKey: node.IPCSinkTypeKafka,
+			},
+			expectedErr: errMissingIPCSinkKafkaBrokers,
+		},
+		"unknown sink": {
+			flags: map[string]interface{}{
+				IpcsSinkTypeKey: "socket",
+			},
+			expectedErr: errInvalidIPCSinkType,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			v := setupViperFlags()
+			for key, value := range test.flags {
+				v.Set(key, value)
+			}
+
+			config, err := getIPCConfig(v)
+			require.ErrorIs(err, test.expectedErr)
+			require.Equal(test.expected, config)
+		})
+	}
+}
+
+func TestParseAPIAuthPrincipals(t *testing.T) {
+	tests := map[string]struct {
+		principals  string
+		expected    []auth.Principal
+		expectedErr error
+	}{
+		"principal": {
+			principals: `[{
+				"name": "partner",
+				"password": "Zb4!q8#Lw2@xR9$tPm",
+				"endpoints": ["/ext/bc/P"],
+				"methods": ["platform.getBalance"],
+				"rateLimit": 60,
+				"maxTokenLifespan": "24h"
+			}]`,
+			expected: []auth.Principal{{
+				Name:     "partner",
+				Password: "Zb4!q8#Lw2@xR9$tPm",
+				Scope: auth.Scope{
+					Endpoints: []string{"/ext/bc/P"},
+					Methods:   []string{"platform.getBalance"},
+					RateLimit: 60,
+				},
+				MaxTokenLifespan: 24 * time.Hour,
+			}},
+		},
+		"weak password": {
+			principals:  `[{"name": "partner", "password": "password"}]`,
+			expectedErr: errAuthPasswordTooWeak,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			principals, err := parseAPIAuthPrincipals([]byte(test.principals))
+			require.ErrorIs(t, err, test.expectedErr)
+			require.Equal(t, test.expected, principals)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/utils/dynamicip"
+	"github.com/ava-labs/avalanchego/utils/ips"
+)
+
+var (
+	errMissingSTUNServers    = errors.New("missing STUN servers")
+	errTooManyExtraEndpoints = errors.New("too many extra endpoints")
+)
+
+// newIPResolver returns the resolver for the primary public IP. STUN
+// resolution needs the configured servers, so it's handled here.
+func newIPResolver(v *viper.Viper, ipResolutionService string) (dynamicip.Resolver, error) {
+	if strings.ToLower(ipResolutionService) != dynamicip.STUNName {
+		return dynamicip.NewResolver(ipResolutionService)
+	}
+	servers := getSTUNServers(v)
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("%w: --%s=%s requires --%s", errMissingSTUNServers, PublicIPResolutionServiceKey, dynamicip.STUNName, PublicIPSTUNServersKey)
+	}
+	return dynamicip.NewSTUNResolver(servers, "udp4")
+}
+
+// getExtraIPConfig returns the endpoints advertised in addition to [ipPort]
+// and the updater that keeps them current.
+func getExtraIPConfig(v *viper.Viper, ipPort ips.DynamicIPPort) (ips.DynamicIPPorts, dynamicip.Updater, error) {
+	updateFreq := v.GetDuration(PublicIPEndpointsUpdateFreqKey)
+	if updateFreq <= 0 {
+		return nil, nil, fmt.Errorf("%q must be > 0", PublicIPEndpointsUpdateFreqKey)
+	}
+
+	endpointsConfig := dynamicip.EndpointsConfig{
+		Interfaces:   v.GetBool(PublicIPInterfaceEndpointsKey),
+		MaxEndpoints: peer.MaxExtraIPPorts,
+	}
+	for _, endpoint := range strings.Split(v.GetString(PublicIPExtraEndpointsKey), ",") {
+		if endpoint == "" {
+			continue
+		}
+		extraIPPort, err := ips.ToIPPort(endpoint)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid endpoint %q in --%s: %w", endpoint, PublicIPExtraEndpointsKey, err)
+		}
+		endpointsConfig.Static = append(endpointsConfig.Static, extraIPPort)
+	}
+	if len(endpointsConfig.Static) > peer.MaxExtraIPPorts {
+		return nil, nil, fmt.Errorf("%w: --%s can't contain more than %d endpoints", errTooManyExtraEndpoints, PublicIPExtraEndpointsKey, peer.MaxExtraIPPorts)
+	}
+	if servers := getSTUNServers(v); len(servers) > 0 {
+		for _, network := range []string{"udp4", "udp6"} {
+			resolver, err := dynamicip.NewSTUNResolver(servers, network)
+			if err != nil {
+				return nil, nil, err
+			}
+			endpointsConfig.Resolvers = append(endpointsConfig.Resolvers, resolver)
+		}
+	}
+
+	extraIPPorts := ips.NewDynamicIPPorts()
+	if len(endpointsConfig.Resolvers) == 0 && !endpointsConfig.Interfaces {
+		// Nothing can change, so there's no need to poll
+		extraIPPorts.SetIPPorts(endpointsConfig.Static)
+		return extraIPPorts, dynamicip.NewNoUpdater(), nil
+	}
+	return extraIPPorts, dynamicip.NewEndpointsUpdater(ipPort, extraIPPorts, endpointsConfig, updateFreq), nil
+}
+
+func getSTUNServers(v *viper.Viper) []string {
+	var servers []string
+	for _, server := range strings.Split(v.GetString(PublicIPSTUNServersKey), ",") {
+		if server != "" {
+			servers = append(servers, server)
+		}
+	}
+	return servers
+}
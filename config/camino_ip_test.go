@@ -0,0 +1,96 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/dynamicip"
+	"github.com/ava-labs/avalanchego/utils/ips"
+)
+
+func TestGetExtraIPConfig(t *testing.T) {
+	primary := ips.NewDynamicIPPort(net.IPv4(1, 2, 3, 4), 9651)
+
+	tests := map[string]struct {
+		flags           map[string]interface{}
+		expectedIPPorts []ips.IPPort
+		expectNoUpdater bool
+		expectedErr     error
+	}{
+		"no extra endpoints": {
+			flags:           map[string]interface{}{},
+			expectedIPPorts: []ips.IPPort{},
+			expectNoUpdater: true,
+		},
+		"static endpoints": {
+			flags: map[string]interface{}{
+				PublicIPExtraEndpointsKey: "[2001:db8::1]:9651,10.0.0.1:9000",
+			},
+			expectedIPPorts: []ips.IPPort{
+				{IP: net.ParseIP("2001:db8::1"), Port: 9651},
+				{IP: net.IPv4(10, 0, 0, 1), Port: 9000},
+			},
+			expectNoUpdater: true,
+		},
+		"stun servers": {
+			flags: map[string]interface{}{
+				PublicIPSTUNServersKey: "127.0.0.1:3478",
+			},
+			expectedIPPorts: []ips.IPPort{},
+		},
+		"interface endpoints": {
+			flags: map[string]interface{}{
+				PublicIPInterfaceEndpointsKey: true,
+			},
+			expectedIPPorts: []ips.IPPort{},
+		},
+		"invalid endpoint port": {
+			flags: map[string]interface{}{
+				PublicIPExtraEndpointsKey: "10.0.0.1:99999",
+			},
+			expectedErr: strconv.ErrRange,
+		},
+		"too many endpoints": {
+			flags: map[string]interface{}{
+				PublicIPExtraEndpointsKey: "10.0.0.1:1,10.0.0.1:2,10.0.0.1:3,10.0.0.1:4,10.0.0.1:5",
+			},
+			expectedErr: errTooManyExtraEndpoints,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			v := setupViperFlags()
+			for key, value := range test.flags {
+				v.Set(key, value)
+			}
+
+			extraIPPorts, updater, err := getExtraIPConfig(v, primary)
+			require.ErrorIs(err, test.expectedErr)
+			if test.expectedErr != nil {
+				return
+			}
+			require.True(ips.EqualIPPorts(test.expectedIPPorts, extraIPPorts.IPPorts()))
+			require.Equal(test.expectNoUpdater, updater == dynamicip.NewNoUpdater())
+		})
+	}
+}
+
+func TestNewIPResolverSTUN(t *testing.T) {
+	require := require.New(t)
+
+	v := setupViperFlags()
+	_, err := newIPResolver(v, "STUN")
+	require.ErrorIs(err, errMissingSTUNServers)
+
+	v.Set(PublicIPSTUNServersKey, "127.0.0.1:3478")
+	_, err = newIPResolver(v, "stun")
+	require.NoError(err)
+}
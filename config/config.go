@@ -30,19 +30,23 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ava-labs/avalanchego/api/auth"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/ipcs"
+	"github.com/ava-labs/avalanchego/ipcs/eventsink"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/staking"
@@ -96,6 +100,8 @@ var (
 	errTracingEndpointEmpty          = fmt.Errorf("%s cannot be empty", TracingEndpointKey)
 	errPluginDirNotADirectory        = errors.New("plugin dir is not a directory")
 	errZstdNotSupported              = errors.New("zstd compression not supported until v1.10")
+	errInvalidIPCSinkType            = errors.New("invalid ipcs sink type")
+	errMissingIPCSinkKafkaBrokers    = errors.New("missing kafka brokers of the ipcs sink")
 )
 
 func getConsensusConfig(v *viper.Viper) avalanche.Parameters {
@@ -171,13 +177,68 @@ func getAPIAuthConfig(v *viper.Viper) (node.APIAuthConfig, error) {
 	if !password.SufficientlyStrong(config.APIAuthPassword, password.OK) {
 		return node.APIAuthConfig{}, errAuthPasswordTooWeak
 	}
+
+	if !v.IsSet(APIAuthPrincipalsFileKey) {
+		return config, nil
+	}
+	principalsBytes, err := os.ReadFile(filepath.Clean(GetExpandedArg(v, APIAuthPrincipalsFileKey)))
+	if err != nil {
+		return node.APIAuthConfig{}, fmt.Errorf("failed to read API auth principals file: %w", err)
+	}
+	config.APIAuthPrincipals, err = parseAPIAuthPrincipals(principalsBytes)
+	if err != nil {
+		return node.APIAuthConfig{}, fmt.Errorf("failed to parse API auth principals file: %w", err)
+	}
 	return config, nil
 }
 
-func getIPCConfig(v *viper.Viper) node.IPCConfig {
+// apiAuthPrincipal is the format of a principal in the API auth principals
+// file
+type apiAuthPrincipal struct {
+	Name             string   `json:"name"`
+	Password         string   `json:"password"`
+	Endpoints        []string `json:"endpoints"`
+	Methods          []string `json:"methods"`
+	RateLimit        uint32   `json:"rateLimit"`
+	MaxTokenLifespan string   `json:"maxTokenLifespan"`
+}
+
+func parseAPIAuthPrincipals(principalsBytes []byte) ([]auth.Principal, error) {
+	var principalConfigs []apiAuthPrincipal
+	if err := json.Unmarshal(principalsBytes, &principalConfigs); err != nil {
+		return nil, err
+	}
+	principals := make([]auth.Principal, len(principalConfigs))
+	for i, p := range principalConfigs {
+		if !password.SufficientlyStrong(p.Password, password.OK) {
+			return nil, fmt.Errorf("%w: principal %s", errAuthPasswordTooWeak, p.Name)
+		}
+		principals[i] = auth.Principal{
+			Name:     p.Name,
+			Password: p.Password,
+			Scope: auth.Scope{
+				Endpoints: p.Endpoints,
+				Methods:   p.Methods,
+				RateLimit: p.RateLimit,
+			},
+		}
+		if p.MaxTokenLifespan == "" {
+			continue
+		}
+		lifespan, err := time.ParseDuration(p.MaxTokenLifespan)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max token lifespan of principal %s: %w", p.Name, err)
+		}
+		principals[i].MaxTokenLifespan = lifespan
+	}
+	return principals, nil
+}
+
+func getIPCConfig(v *viper.Viper) (node.IPCConfig, error) {
 	config := node.IPCConfig{
 		IPCAPIEnabled: v.GetBool(IpcAPIEnabledKey),
 		IPCPath:       ipcs.DefaultBaseURL,
+		IPCSinkType:   v.GetString(IpcsSinkTypeKey),
 	}
 	if v.IsSet(IpcsChainIDsKey) {
 		config.IPCDefaultChainIDs = strings.Split(v.GetString(IpcsChainIDsKey), ",")
@@ -185,7 +246,37 @@ func getIPCConfig(v *viper.Viper) node.IPCConfig {
 	if v.IsSet(IpcsPathKey) {
 		config.IPCPath = GetExpandedArg(v, IpcsPathKey)
 	}
-	return config
+	if chainIDs := v.GetString(IpcsSinkChainIDsKey); chainIDs != "" {
+		config.IPCSinkChainIDs = strings.Split(chainIDs, ",")
+	}
+
+	switch config.IPCSinkType {
+	case "":
+	case node.IPCSinkTypeFile:
+		config.IPCSinkFileDir = GetExpandedArg(v, IpcsSinkFileDirKey)
+		config.IPCSinkFileMaxSize = v.GetUint64(IpcsSinkFileMaxSizeKey)
+		config.IPCSinkFileMaxFiles = v.GetInt(IpcsSinkFileMaxFilesKey)
+		if config.IPCSinkFileMaxSize == 0 {
+			return node.IPCConfig{}, fmt.Errorf("%q must be > 0", IpcsSinkFileMaxSizeKey)
+		}
+		if config.IPCSinkFileMaxFiles < 0 {
+			return node.IPCConfig{}, fmt.Errorf("%q must be >= 0", IpcsSinkFileMaxFilesKey)
+		}
+	case node.IPCSinkTypeKafka:
+		config.IPCSinkKafkaConfig = eventsink.KafkaConfig{
+			RequiredAcks: int16(v.GetInt(IpcsSinkKafkaRequiredAcksKey)),
+			Timeout:      v.GetDuration(IpcsSinkKafkaTimeoutKey),
+		}
+		if brokers := v.GetString(IpcsSinkKafkaBrokersKey); brokers != "" {
+			config.IPCSinkKafkaConfig.Brokers = strings.Split(brokers, ",")
+		}
+		if len(config.IPCSinkKafkaConfig.Brokers) == 0 {
+			return node.IPCConfig{}, errMissingIPCSinkKafkaBrokers
+		}
+	default:
+		return node.IPCConfig{}, fmt.Errorf("%w: %q", errInvalidIPCSinkType, config.IPCSinkType)
+	}
+	return config, nil
 }
 
 func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
@@ -230,11 +321,20 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 			ReadHeaderTimeout: v.GetDuration(HTTPReadHeaderTimeoutKey),
 			WriteTimeout:      v.GetDuration(HTTPWriteTimeoutKey),
 			IdleTimeout:       v.GetDuration(HTTPIdleTimeoutKey),
+			ThrottlingConfig: server.ThrottlingConfig{
+				IPRateLimit:                   v.GetFloat64(HTTPIPRateLimitKey),
+				IPRateLimitBurst:              int(v.GetUint(HTTPIPRateLimitBurstKey)),
+				TokenRateLimit:                v.GetFloat64(HTTPTokenRateLimitKey),
+				TokenRateLimitBurst:           int(v.GetUint(HTTPTokenRateLimitBurstKey)),
+				MaxConcurrentRequestsPerRoute: int(v.GetUint(HTTPMaxConcurrentRequestsPerRouteKey)),
+				MaxResponseSize:               int(v.GetUint(HTTPMaxResponseSizeKey)),
+			},
 		},
 		APIConfig: node.APIConfig{
 			APIIndexerConfig: node.APIIndexerConfig{
-				IndexAPIEnabled:      v.GetBool(IndexEnabledKey),
-				IndexAllowIncomplete: v.GetBool(IndexAllowIncompleteKey),
+				IndexAPIEnabled:       v.GetBool(IndexEnabledKey),
+				IndexAllowIncomplete:  v.GetBool(IndexAllowIncompleteKey),
+				IndexAddressesEnabled: v.GetBool(IndexAddressesEnabledKey),
 			},
 			AdminAPIEnabledSecret: v.GetString(AdminAPIEnabledKey),
 			InfoAPIEnabled:        v.GetBool(InfoAPIEnabledKey),
@@ -252,12 +352,16 @@ func getHTTPConfig(v *viper.Viper) (node.HTTPConfig, error) {
 		ShutdownWait:      v.GetDuration(HTTPShutdownWaitKey),
 	}
 
+	if err := config.ThrottlingConfig.Verify(); err != nil {
+		return node.HTTPConfig{}, fmt.Errorf("invalid HTTP throttling config: %w", err)
+	}
+
 	config.APIAuthConfig, err = getAPIAuthConfig(v)
 	if err != nil {
 		return node.HTTPConfig{}, err
 	}
-	config.IPCConfig = getIPCConfig(v)
-	return config, nil
+	config.IPCConfig, err = getIPCConfig(v)
+	return config, err
 }
 
 func getRouterHealthConfig(v *viper.Viper, halflife time.Duration) (router.HealthConfig, error) {
@@ -389,6 +493,16 @@ func getNetworkConfig(
 				VdrAllocSize:        v.GetUint64(OutboundThrottlerVdrAllocSizeKey),
 				NodeMaxAtLargeBytes: v.GetUint64(OutboundThrottlerNodeMaxAtLargeBytesKey),
 			},
+
+			OutboundMsgQueueConfig: peer.MessageQueueConfig{
+				MaxQueuedBytes: [peer.NumPriorities]uint64{
+					peer.ConsensusPriority:  v.GetUint64(OutboundQueueConsensusMaxBytesKey),
+					peer.AcceptancePriority: v.GetUint64(OutboundQueueAcceptanceMaxBytesKey),
+					peer.AppPriority:        v.GetUint64(OutboundQueueAppMaxBytesKey),
+					peer.BulkPriority:       v.GetUint64(OutboundQueueBulkMaxBytesKey),
+				},
+				MaxStarvation: v.GetDuration(OutboundQueueMaxStarvationKey),
+			},
 		},
 
 		HealthConfig: network.HealthConfig{
@@ -439,6 +553,22 @@ func getNetworkConfig(
 		RequireValidatorToConnect: v.GetBool(NetworkRequireValidatorToConnectKey),
 		PeerReadBufferSize:        int(v.GetUint(NetworkPeerReadBufferSizeKey)),
 		PeerWriteBufferSize:       int(v.GetUint(NetworkPeerWriteBufferSizeKey)),
+
+		ConsortiumOnly: v.GetBool(NetworkConsortiumOnlyKey),
+
+		QUICEnabled: v.GetBool(NetworkQUICEnabledKey),
+		QUICPort:    uint16(v.GetUint(NetworkQUICPortKey)),
+	}
+
+	for _, id := range strings.Split(v.GetString(NetworkAllowedNodeIDsKey), ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.NodeIDFromString(id)
+		if err != nil {
+			return network.Config{}, fmt.Errorf("couldn't parse allowed node id %s: %w", id, err)
+		}
+		config.AllowedNodeIDs.Add(nodeID)
 	}
 
 	switch {
@@ -470,6 +600,8 @@ func getNetworkConfig(
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkReadHandshakeTimeoutKey)
 	case config.MaxClockDifference < 0:
 		return network.Config{}, fmt.Errorf("%s must be >= 0", NetworkMaxClockDifferenceKey)
+	case config.ThrottlerConfig.OutboundMsgQueueConfig.MaxStarvation < 0:
+		return network.Config{}, fmt.Errorf("%s must be >= 0", OutboundQueueMaxStarvationKey)
 	}
 	return config, nil
 }
@@ -492,6 +624,40 @@ func getBenchlistConfig(v *viper.Viper, consensusParameters avalanche.Parameters
 	return config, nil
 }
 
+func getReputationConfig(v *viper.Viper) (reputation.Config, error) {
+	config := reputation.Config{
+		Enabled:    v.GetBool(ReputationEnabledKey),
+		HalfLife:   v.GetDuration(ReputationHalfLifeKey),
+		BenchScore: v.GetFloat64(ReputationBenchScoreKey),
+		MaxScore:   v.GetFloat64(ReputationMaxScoreKey),
+	}
+	switch {
+	case config.HalfLife <= 0:
+		return reputation.Config{}, fmt.Errorf("%q must be > 0", ReputationHalfLifeKey)
+	case config.BenchScore >= 0:
+		return reputation.Config{}, fmt.Errorf("%q must be < 0", ReputationBenchScoreKey)
+	case config.MaxScore < 0:
+		return reputation.Config{}, fmt.Errorf("%q must be >= 0", ReputationMaxScoreKey)
+	}
+	return config, nil
+}
+
+func getNetworkCaptureConfig(v *viper.Viper) (peer.CaptureConfig, error) {
+	config := peer.CaptureConfig{
+		Enabled:     v.GetBool(NetworkCaptureEnabledKey),
+		Dir:         GetExpandedArg(v, NetworkCaptureDirKey),
+		MaxFileSize: v.GetUint64(NetworkCaptureMaxFileSizeKey),
+		MaxFiles:    v.GetInt(NetworkCaptureMaxFilesKey),
+	}
+	switch {
+	case config.MaxFileSize == 0:
+		return peer.CaptureConfig{}, fmt.Errorf("%q must be > 0", NetworkCaptureMaxFileSizeKey)
+	case config.MaxFiles <= 0:
+		return peer.CaptureConfig{}, fmt.Errorf("%q must be > 0", NetworkCaptureMaxFilesKey)
+	}
+	return config, nil
+}
+
 func getStateSyncConfig(v *viper.Viper) (node.StateSyncConfig, error) {
 	var (
 		config       = node.StateSyncConfig{}
@@ -615,7 +781,7 @@ func getIPConfig(v *viper.Viper) (node.IPConfig, error) {
 	}
 	if ipResolutionService != "" {
 		// User specified to use dynamic IP resolution.
-		resolver, err := dynamicip.NewResolver(ipResolutionService)
+		resolver, err := newIPResolver(v, ipResolutionService)
 		if err != nil {
 			return node.IPConfig{}, fmt.Errorf("couldn't create IP resolver: %w", err)
 		}
@@ -947,7 +1113,8 @@ func getDatabaseConfig(v *viper.Viper, networkID uint32) (node.DatabaseConfig, e
 			GetExpandedArg(v, DBPathKey),
 			constants.NetworkName(networkID),
 		),
-		Config: configBytes,
+		Config:             configBytes,
+		MigrateFromLevelDB: v.GetBool(DBMigrateFromLevelDBKey),
 	}, nil
 }
 
@@ -1358,6 +1525,10 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 	if err != nil {
 		return node.Config{}, err
 	}
+	nodeConfig.ExtraIPPorts, nodeConfig.ExtraIPsUpdater, err = getExtraIPConfig(v, nodeConfig.IPPort)
+	if err != nil {
+		return node.Config{}, err
+	}
 
 	// Staking
 	nodeConfig.StakingConfig, err = getStakingConfig(v, nodeConfig.NetworkID)
@@ -1430,6 +1601,18 @@ func GetNodeConfig(v *viper.Viper) (node.Config, error) {
 		return node.Config{}, err
 	}
 
+	// Reputation
+	nodeConfig.ReputationConfig, err = getReputationConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
+	// Network Capture
+	nodeConfig.NetworkCaptureConfig, err = getNetworkCaptureConfig(v)
+	if err != nil {
+		return node.Config{}, err
+	}
+
 	// File Descriptor Limit
 	nodeConfig.FdLimit = v.GetUint64(FdLimitKey)
 
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -460,6 +470,18 @@ func TestGetSubnetConfigsFromFile(t *testing.T) {
 			},
 			errMessage: "",
 		},
+		"bandwidth quota": {
+			fileName:  "2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i.json",
+			givenJSON: `{"bandwidthQuota": {"inboundBytesPerSecond": 1048576, "outboundBytesPerSecond": 524288}}`,
+			testF: func(require *require.Assertions, given map[ids.ID]subnets.Config) {
+				id, _ := ids.FromString("2Ctt6eGAeo4MLqTmGa7AdRecuVMPGWEX9wSsCLBYrLhX4a394i")
+				config, ok := given[id]
+				require.True(ok)
+				require.Equal(uint64(1048576), config.BandwidthQuota.InboundBytesPerSecond)
+				require.Equal(uint64(524288), config.BandwidthQuota.OutboundBytesPerSecond)
+			},
+			errMessage: "",
+		},
 	}
 
 	for name, test := range tests {
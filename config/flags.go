@@ -25,7 +25,9 @@ import (
 
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/pebble"
 	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/node"
 	"github.com/ava-labs/avalanchego/trace"
 	"github.com/ava-labs/avalanchego/utils/compression"
 	"github.com/ava-labs/avalanchego/utils/constants"
@@ -48,6 +50,7 @@ var (
 	defaultDBDir                = filepath.Join(defaultUnexpandedDataDir, "db")
 	defaultLogDir               = filepath.Join(defaultUnexpandedDataDir, "logs")
 	defaultProfileDir           = filepath.Join(defaultUnexpandedDataDir, "profiles")
+	defaultNetworkCaptureDir    = filepath.Join(defaultUnexpandedDataDir, "capture")
 	defaultStakingPath          = filepath.Join(defaultUnexpandedDataDir, "staking")
 	defaultStakingTLSKeyPath    = filepath.Join(defaultStakingPath, "staker.key")
 	defaultStakingCertPath      = filepath.Join(defaultStakingPath, "staker.crt")
@@ -60,6 +63,7 @@ var (
 	defaultSubnetConfigDir      = filepath.Join(defaultConfigDir, "subnets")
 	defaultPluginDir            = filepath.Join(defaultUnexpandedDataDir, "plugins")
 	defaultChainDataDir         = filepath.Join(defaultUnexpandedDataDir, "chainData")
+	defaultIPCSinkFileDir       = filepath.Join(defaultUnexpandedDataDir, "events")
 )
 
 func deprecateFlags(fs *pflag.FlagSet) error {
@@ -110,10 +114,11 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Uint64(AddSubnetDelegatorFeeKey, genesis.LocalParams.AddSubnetDelegatorFee, "Transaction fee, in nAVAX, for transactions that add new subnet delegators")
 
 	// Database
-	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s}", leveldb.Name, memdb.Name))
+	fs.String(DBTypeKey, leveldb.Name, fmt.Sprintf("Database type to use. Should be one of {%s, %s, %s}", leveldb.Name, memdb.Name, pebble.Name))
 	fs.String(DBPathKey, defaultDBDir, "Path to database directory")
 	fs.String(DBConfigFileKey, "", fmt.Sprintf("Path to database config file. Ignored if %s is specified", DBConfigContentKey))
 	fs.String(DBConfigContentKey, "", "Specifies base64 encoded database config content")
+	fs.Bool(DBMigrateFromLevelDBKey, false, fmt.Sprintf("If true and %s is %s, copies an existing leveldb database into a new pebble database on startup. The leveldb database is left untouched", DBTypeKey, pebble.Name))
 
 	// Logging
 	fs.String(LogsDirKey, defaultLogDir, "Logging directory for Avalanche")
@@ -144,7 +149,11 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	// Public IP Resolution
 	fs.String(PublicIPKey, "", "Public IP of this node for P2P communication. If empty, try to discover with NAT")
 	fs.Duration(PublicIPResolutionFreqKey, 5*time.Minute, "Frequency at which this node resolves/updates its public IP and renew NAT mappings, if applicable")
-	fs.String(PublicIPResolutionServiceKey, "", fmt.Sprintf("Only acceptable values are 'ifconfigco', 'opendns' or 'ifconfigme'. When provided, the node will use that service to periodically resolve/update its public IP. Ignored if %s is set", PublicIPKey))
+	fs.String(PublicIPResolutionServiceKey, "", fmt.Sprintf("Only acceptable values are 'ifconfigco', 'opendns', 'ifconfigme' or 'stun'. When provided, the node will use that service to periodically resolve/update its public IP. 'stun' queries the servers given by %s. Ignored if %s is set", PublicIPSTUNServersKey, PublicIPKey))
+	fs.String(PublicIPSTUNServersKey, "", "Comma separated list of STUN servers used to resolve the public IPv4 and IPv6 endpoints of this node. They are tried in order. Example: stun.example.com:3478,203.0.113.1:3478")
+	fs.String(PublicIPExtraEndpointsKey, "", "Comma separated list of endpoints to advertise to peers in addition to the public IP. Peers try them in order when the public IP can't be dialed. Example: [2001:db8::1]:9651,10.0.0.1:9651")
+	fs.Bool(PublicIPInterfaceEndpointsKey, false, "If true, the public addresses of the local network interfaces are advertised as additional endpoints with the staking port")
+	fs.Duration(PublicIPEndpointsUpdateFreqKey, constants.DefaultPublicIPEndpointsUpdateFreq, "Frequency at which this node re-resolves its additional endpoints and re-signs them when they change")
 
 	// Inbound Connection Throttling
 	fs.Duration(InboundConnUpgradeThrottlerCooldownKey, constants.DefaultInboundConnUpgradeThrottlerCooldown, "Upgrade an inbound connection from a given IP at most once per this duration. If 0, don't rate-limit inbound connection upgrades")
@@ -169,6 +178,10 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(NetworkMaxClockDifferenceKey, constants.DefaultNetworkMaxClockDifference, "Max allowed clock difference value between this node and peers")
 	fs.Bool(NetworkAllowPrivateIPsKey, constants.DefaultNetworkAllowPrivateIPs, "Allows the node to initiate outbound connection attempts to peers with private IPs")
 	fs.Bool(NetworkRequireValidatorToConnectKey, constants.DefaultNetworkRequireValidatorToConnect, "If true, this node will only maintain a connection with another node if this node is a validator, the other node is a validator, or the other node is a beacon")
+	fs.Bool(NetworkConsortiumOnlyKey, false, fmt.Sprintf("If true, this node will only connect to and accept connections from current and pending validators of the primary network, the bootstrappers and the nodes of %s. IPs of other nodes received by peer-list gossip are ignored", NetworkAllowedNodeIDsKey))
+	fs.String(NetworkAllowedNodeIDsKey, "", fmt.Sprintf("Comma separated list of node IDs, like API nodes, that are allowed to connect without validating if %s is set", NetworkConsortiumOnlyKey))
+	fs.Bool(NetworkQUICEnabledKey, false, "If true, this node accepts QUIC connections and connects to peers that advertise a QUIC port over QUIC, falling back to TCP. QUIC connections use separate streams for handshake, consensus and bulk messages. The consensus and bulk streams share the budgets of the inbound message throttler")
+	fs.Uint(NetworkQUICPortKey, 0, fmt.Sprintf("UDP port this node accepts QUIC connections on if %s is set. If 0, the staking port is used", NetworkQUICEnabledKey))
 	fs.Uint(NetworkPeerReadBufferSizeKey, constants.DefaultNetworkPeerReadBufferSize, "Size, in bytes, of the buffer that we read peer messages into (there is one buffer per peer)")
 	fs.Uint(NetworkPeerWriteBufferSizeKey, constants.DefaultNetworkPeerWriteBufferSize, "Size, in bytes, of the buffer that we write peer messages into (there is one buffer per peer)")
 
@@ -187,6 +200,18 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(BenchlistDurationKey, constants.DefaultBenchlistDuration, "Max amount of time a peer is benchlisted after surpassing the threshold")
 	fs.Duration(BenchlistMinFailingDurationKey, constants.DefaultBenchlistMinFailingDuration, "Minimum amount of time messages to a peer must be failing before the peer is benched")
 
+	// Reputation
+	fs.Bool(ReputationEnabledKey, true, "If true, timeouts, invalid messages, invalid containers and throttling violations of peers lower their reputation score, which biases the choice of peers containers are fetched from against them. Validators sampled for consensus queries stay weighted by stake only")
+	fs.Duration(ReputationHalfLifeKey, constants.DefaultReputationHalfLife, "Time it takes for the reputation score of a peer to decay to half its value")
+	fs.Float64(ReputationBenchScoreKey, constants.DefaultReputationBenchScore, "Negative reputation score at or below which a peer is benched. Requests to benched validators immediately fail, as long as the benched stake doesn't exceed the benchlist max portion, and benched peers aren't gossiped to")
+	fs.Float64(ReputationMaxScoreKey, constants.DefaultReputationMaxScore, "Max reputation score a peer can build up by answering requests in time")
+
+	// Network Capture
+	fs.Bool(NetworkCaptureEnabledKey, false, "If true, all messages exchanged with peers are written to capture files, which can be replayed offline to debug consensus stalls")
+	fs.String(NetworkCaptureDirKey, defaultNetworkCaptureDir, "Path to the directory the network capture files are written to")
+	fs.Uint64(NetworkCaptureMaxFileSizeKey, constants.DefaultNetworkCaptureMaxFileSize, "Size in bytes after which a new network capture file is started")
+	fs.Int(NetworkCaptureMaxFilesKey, constants.DefaultNetworkCaptureMaxFiles, "Number of network capture files that are kept. The oldest file is removed when this number is exceeded")
+
 	// Router
 	fs.Duration(ConsensusGossipFrequencyKey, constants.DefaultConsensusGossipFrequency, "Frequency of gossiping accepted frontiers")
 	fs.Uint(ConsensusAppConcurrencyKey, constants.DefaultConsensusAppConcurrency, "Maximum number of goroutines to use when handling App messages on a chain")
@@ -215,6 +240,11 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Uint64(OutboundThrottlerAtLargeAllocSizeKey, constants.DefaultOutboundThrottlerAtLargeAllocSize, "Size, in bytes, of at-large byte allocation in outbound message throttler")
 	fs.Uint64(OutboundThrottlerVdrAllocSizeKey, constants.DefaultOutboundThrottlerVdrAllocSize, "Size, in bytes, of validator byte allocation in outbound message throttler")
 	fs.Uint64(OutboundThrottlerNodeMaxAtLargeBytesKey, constants.DefaultOutboundThrottlerNodeMaxAtLargeBytes, "Max number of bytes a node can take from the outbound message throttler's at-large allocation. Must be at least the max message size")
+	fs.Uint64(OutboundQueueConsensusMaxBytesKey, constants.DefaultOutboundQueueConsensusMaxBytes, "Max number of bytes of handshake, query and chits messages queued for a peer. If 0, only the outbound message throttler applies")
+	fs.Uint64(OutboundQueueAcceptanceMaxBytesKey, constants.DefaultOutboundQueueAcceptanceMaxBytes, "Max number of bytes of accepted container gossip and request messages queued for a peer. If 0, only the outbound message throttler applies")
+	fs.Uint64(OutboundQueueAppMaxBytesKey, constants.DefaultOutboundQueueAppMaxBytes, "Max number of bytes of app messages queued for a peer. If 0, only the outbound message throttler applies")
+	fs.Uint64(OutboundQueueBulkMaxBytesKey, constants.DefaultOutboundQueueBulkMaxBytes, "Max number of bytes of bootstrapping and state sync messages queued for a peer. If 0, only the outbound message throttler applies")
+	fs.Duration(OutboundQueueMaxStarvationKey, constants.DefaultOutboundQueueMaxStarvation, "Max time a queued outbound message waits while messages of a higher priority are sent to the same peer. If 0, lower priority messages wait until no higher priority messages are queued")
 
 	// HTTP APIs
 	fs.String(HTTPHostKey, "127.0.0.1", "Address of the HTTP server")
@@ -231,11 +261,18 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	fs.Duration(HTTPReadHeaderTimeoutKey, 30*time.Second, fmt.Sprintf("Maximum duration to read request headers. The connection's read deadline is reset after reading the headers. If %s is zero, the value of %s is used. If both are zero, there is no timeout.", HTTPReadHeaderTimeoutKey, HTTPReadTimeoutKey))
 	fs.Duration(HTTPWriteTimeoutKey, 30*time.Second, "Maximum duration before timing out writes of the response. It is reset whenever a new request's header is read. A zero or negative value means there will be no timeout.")
 	fs.Duration(HTTPIdleTimeoutKey, 120*time.Second, fmt.Sprintf("Maximum duration to wait for the next request when keep-alives are enabled. If %s is zero, the value of %s is used. If both are zero, there is no timeout.", HTTPIdleTimeoutKey, HTTPReadTimeoutKey))
+	fs.Float64(HTTPIPRateLimitKey, 0, "Maximum number of HTTP API requests per second from a single IP. If 0, requests aren't limited")
+	fs.Uint(HTTPIPRateLimitBurstKey, 0, fmt.Sprintf("Maximum number of HTTP API requests a single IP can make at once before being limited by %s. If 0, the rate limit rounded up is used", HTTPIPRateLimitKey))
+	fs.Float64(HTTPTokenRateLimitKey, 0, fmt.Sprintf("Maximum number of HTTP API requests per second with a single auth token. Only applies if %s is set. If 0, requests aren't limited", APIAuthRequiredKey))
+	fs.Uint(HTTPTokenRateLimitBurstKey, 0, fmt.Sprintf("Maximum number of HTTP API requests that can be made at once with a single auth token before being limited by %s. If 0, the rate limit rounded up is used", HTTPTokenRateLimitKey))
+	fs.Uint(HTTPMaxConcurrentRequestsPerRouteKey, 0, "Maximum number of requests each HTTP API route processes concurrently. Additional requests are rejected instead of waiting for the chain's lock. Websocket connections aren't limited. If 0, requests aren't limited")
+	fs.Uint(HTTPMaxResponseSizeKey, 0, "Maximum size, in bytes, of an HTTP API response body. Responses are buffered up to this size and replaced by an error if they exceed it. If 0, responses aren't limited")
 	fs.Bool(APIAuthRequiredKey, false, "Require authorization token to call HTTP APIs")
 	fs.String(APIAuthPasswordFileKey, "",
 		fmt.Sprintf("Password file used to initially create/validate API authorization tokens. Ignored if %s is specified. Leading and trailing whitespace is removed from the password. Can be changed via API call",
 			APIAuthPasswordKey))
 	fs.String(APIAuthPasswordKey, "", "Specifies password for API authorization tokens")
+	fs.String(APIAuthPrincipalsFileKey, "", "JSON file of named principals that create API authorization tokens with their own password. Each principal is an object with a name, password, endpoints, methods, rateLimit and maxTokenLifespan. The tokens of a principal are restricted to the endpoints, JSON-RPC methods and rate limit (requests per minute) of the principal")
 
 	// Enable/Disable APIs
 	fs.String(AdminAPIEnabledKey, "", "If not empty, this node exposes the Admin API. The secret must be passed for every call")
@@ -333,10 +370,19 @@ func addNodeFlags(fs *pflag.FlagSet) {
 	// IPC
 	fs.String(IpcsChainIDsKey, "", "Comma separated list of chain ids to add to the IPC engine. Example: 11111111111111111111111111111111LpoYY,4R5p2RXDGLqaifZE4hHWH9owe34pfoBULn1DrQTWivjg8o4aH")
 	fs.String(IpcsPathKey, "", "The directory (Unix) or named pipe name prefix (Windows) for IPC sockets")
+	fs.String(IpcsSinkTypeKey, "", fmt.Sprintf("Type of the sink accepted txs are published to as JSON events. If empty, no events are published. One of {%q, %q}", node.IPCSinkTypeFile, node.IPCSinkTypeKafka))
+	fs.String(IpcsSinkChainIDsKey, "", "Comma separated list of chain ids, whose accepted txs are published to the sink. If empty, the txs of the P-chain and X-chain are published")
+	fs.String(IpcsSinkFileDirKey, defaultIPCSinkFileDir, "Directory of the newline-delimited JSON files events are written to")
+	fs.Uint64(IpcsSinkFileMaxSizeKey, 64*units.MiB, "Size in bytes after which an event file is rotated")
+	fs.Int(IpcsSinkFileMaxFilesKey, 16, "Maximum number of rotated event files kept per chain. If 0, rotated files are never deleted")
+	fs.String(IpcsSinkKafkaBrokersKey, "", "Comma separated list of Kafka bootstrap brokers, events are produced to. Example: 127.0.0.1:9092")
+	fs.Int(IpcsSinkKafkaRequiredAcksKey, -1, "Number of Kafka replica acknowledgements required for produced events. Either -1 (all in-sync replicas) or 1 (leader only)")
+	fs.Duration(IpcsSinkKafkaTimeoutKey, 10*time.Second, "Timeout of requests to Kafka brokers")
 
 	// Indexer
 	fs.Bool(IndexEnabledKey, false, "If true, index all accepted containers and transactions and expose them via an API")
 	fs.Bool(IndexAllowIncompleteKey, false, "If true, allow running the node in such a way that could cause an index to miss transactions. Ignored if index is disabled")
+	fs.Bool(IndexAddressesEnabledKey, false, "If true, index the P-chain and X-chain txs by the addresses they touched. Txs accepted before it was enabled are indexed in the background. Ignored if index is disabled")
 
 	// Config Directories
 	fs.String(ChainConfigDirKey, defaultChainConfigDir, fmt.Sprintf("Chain specific configurations parent directory. Ignored if %s is specified", ChainConfigContentKey))
@@ -47,9 +47,14 @@ const (
 	DBPathKey                                          = "db-dir"
 	DBConfigFileKey                                    = "db-config-file"
 	DBConfigContentKey                                 = "db-config-file-content"
+	DBMigrateFromLevelDBKey                            = "db-migrate-from-leveldb"
 	PublicIPKey                                        = "public-ip"
 	PublicIPResolutionFreqKey                          = "public-ip-resolution-frequency"
 	PublicIPResolutionServiceKey                       = "public-ip-resolution-service"
+	PublicIPSTUNServersKey                             = "public-ip-stun-servers"
+	PublicIPExtraEndpointsKey                          = "public-ip-extra-endpoints"
+	PublicIPInterfaceEndpointsKey                      = "public-ip-interface-endpoints"
+	PublicIPEndpointsUpdateFreqKey                     = "public-ip-endpoints-update-frequency"
 	InboundConnUpgradeThrottlerCooldownKey             = "inbound-connection-throttling-cooldown"
 	InboundThrottlerMaxConnsPerSecKey                  = "inbound-connection-throttling-max-conns-per-sec"
 	OutboundConnectionThrottlingRpsKey                 = "outbound-connection-throttling-rps"
@@ -68,9 +73,16 @@ const (
 	HTTPReadHeaderTimeoutKey                           = "http-read-header-timeout"
 	HTTPWriteTimeoutKey                                = "http-write-timeout"
 	HTTPIdleTimeoutKey                                 = "http-idle-timeout"
+	HTTPIPRateLimitKey                                 = "http-ip-rate-limit"
+	HTTPIPRateLimitBurstKey                            = "http-ip-rate-limit-burst"
+	HTTPTokenRateLimitKey                              = "http-token-rate-limit"
+	HTTPTokenRateLimitBurstKey                         = "http-token-rate-limit-burst"
+	HTTPMaxConcurrentRequestsPerRouteKey               = "http-max-concurrent-requests-per-route"
+	HTTPMaxResponseSizeKey                             = "http-max-response-size"
 	APIAuthRequiredKey                                 = "api-auth-required"
 	APIAuthPasswordKey                                 = "api-auth-password"
 	APIAuthPasswordFileKey                             = "api-auth-password-file"
+	APIAuthPrincipalsFileKey                           = "api-auth-principals-file"
 	StateSyncIPsKey                                    = "state-sync-ips"
 	StateSyncIDsKey                                    = "state-sync-ids"
 	BootstrapIPsKey                                    = "bootstrap-ips"
@@ -113,6 +125,10 @@ const (
 	NetworkMaxClockDifferenceKey                       = "network-max-clock-difference"
 	NetworkAllowPrivateIPsKey                          = "network-allow-private-ips"
 	NetworkRequireValidatorToConnectKey                = "network-require-validator-to-connect"
+	NetworkConsortiumOnlyKey                           = "network-consortium-only"
+	NetworkAllowedNodeIDsKey                           = "network-allowed-node-ids"
+	NetworkQUICEnabledKey                              = "network-quic-enabled"
+	NetworkQUICPortKey                                 = "network-quic-port"
 	NetworkPeerReadBufferSizeKey                       = "network-peer-read-buffer-size"
 	NetworkPeerWriteBufferSizeKey                      = "network-peer-write-buffer-size"
 	NetworkTCPProxyEnabledKey                          = "network-tcp-proxy-enabled"
@@ -121,6 +137,14 @@ const (
 	BenchlistFailThresholdKey                          = "benchlist-fail-threshold"
 	BenchlistDurationKey                               = "benchlist-duration"
 	BenchlistMinFailingDurationKey                     = "benchlist-min-failing-duration"
+	ReputationEnabledKey                               = "reputation-enabled"
+	ReputationHalfLifeKey                              = "reputation-half-life"
+	ReputationBenchScoreKey                            = "reputation-bench-score"
+	ReputationMaxScoreKey                              = "reputation-max-score"
+	NetworkCaptureEnabledKey                           = "network-capture-enabled"
+	NetworkCaptureDirKey                               = "network-capture-dir"
+	NetworkCaptureMaxFileSizeKey                       = "network-capture-max-file-size"
+	NetworkCaptureMaxFilesKey                          = "network-capture-max-files"
 	LogsDirKey                                         = "log-dir"
 	LogLevelKey                                        = "log-level"
 	LogDisplayLevelKey                                 = "log-display-level"
@@ -151,6 +175,14 @@ const (
 	IpcAPIEnabledKey                                   = "api-ipcs-enabled"
 	IpcsChainIDsKey                                    = "ipcs-chain-ids"
 	IpcsPathKey                                        = "ipcs-path"
+	IpcsSinkTypeKey                                    = "ipcs-sink-type"
+	IpcsSinkChainIDsKey                                = "ipcs-sink-chain-ids"
+	IpcsSinkFileDirKey                                 = "ipcs-sink-file-dir"
+	IpcsSinkFileMaxSizeKey                             = "ipcs-sink-file-max-size"
+	IpcsSinkFileMaxFilesKey                            = "ipcs-sink-file-max-files"
+	IpcsSinkKafkaBrokersKey                            = "ipcs-sink-kafka-brokers"
+	IpcsSinkKafkaRequiredAcksKey                       = "ipcs-sink-kafka-required-acks"
+	IpcsSinkKafkaTimeoutKey                            = "ipcs-sink-kafka-timeout"
 	MeterVMsEnabledKey                                 = "meter-vms-enabled"
 	ConsensusGossipFrequencyKey                        = "consensus-gossip-frequency"
 	ConsensusAppConcurrencyKey                         = "consensus-app-concurrency"
@@ -168,6 +200,7 @@ const (
 	FdLimitKey                                         = "fd-limit"
 	IndexEnabledKey                                    = "index-enabled"
 	IndexAllowIncompleteKey                            = "index-allow-incomplete"
+	IndexAddressesEnabledKey                           = "index-addresses-enabled"
 	RouterHealthMaxDropRateKey                         = "router-health-max-drop-rate"
 	RouterHealthMaxOutstandingRequestsKey              = "router-health-max-outstanding-requests"
 	HealthCheckFreqKey                                 = "health-check-frequency"
@@ -211,6 +244,11 @@ const (
 	OutboundThrottlerAtLargeAllocSizeKey               = "throttler-outbound-at-large-alloc-size"
 	OutboundThrottlerVdrAllocSizeKey                   = "throttler-outbound-validator-alloc-size"
 	OutboundThrottlerNodeMaxAtLargeBytesKey            = "throttler-outbound-node-max-at-large-bytes"
+	OutboundQueueConsensusMaxBytesKey                  = "throttler-outbound-queue-consensus-max-bytes"
+	OutboundQueueAcceptanceMaxBytesKey                 = "throttler-outbound-queue-acceptance-max-bytes"
+	OutboundQueueAppMaxBytesKey                        = "throttler-outbound-queue-app-max-bytes"
+	OutboundQueueBulkMaxBytesKey                       = "throttler-outbound-queue-bulk-max-bytes"
+	OutboundQueueMaxStarvationKey                      = "throttler-outbound-queue-max-starvation"
 	UptimeMetricFreqKey                                = "uptime-metric-freq"
 	VMAliasesFileKey                                   = "vm-aliases-file"
 	VMAliasesContentKey                                = "vm-aliases-file-content"
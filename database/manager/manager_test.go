@@ -15,6 +15,7 @@ import (
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/database/meterdb"
+	"github.com/ava-labs/avalanchego/database/pebble"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/version"
@@ -50,6 +51,40 @@ func TestNewSingleLevelDB(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestNewPebbleDBWithPreviousVersion(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	v1 := version.Semantic1_0_0
+	v2 := &version.Semantic{
+		Major: 1,
+		Minor: 1,
+		Patch: 0,
+	}
+
+	dbPath := filepath.Join(dir, v1.String())
+	db, err := pebble.New(dbPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(err)
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+	require.NoError(db.Close())
+
+	manager, err := NewPebbleDB(dir, nil, logging.NoLog{}, v2, "", prometheus.NewRegistry())
+	require.NoError(err)
+
+	semDB := manager.Current()
+	require.Zero(semDB.Version.Compare(v2), "incorrect version on current database")
+
+	prev, exists := manager.Previous()
+	require.True(exists, "there should be a previous database")
+	require.Zero(prev.Version.Compare(v1), "incorrect version on previous database")
+
+	value, err := prev.Database.Get([]byte("key"))
+	require.NoError(err)
+	require.Equal([]byte("value"), value)
+
+	require.NoError(manager.Close())
+}
+
 func TestNewCreatesSingleDB(t *testing.T) {
 	require := require.New(t)
 
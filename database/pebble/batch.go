@@ -0,0 +1,100 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebble
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var _ database.Batch = (*batch)(nil)
+
+// batch is a wrapper around a pebble batch to contain sizes.
+type batch struct {
+	batch *pebble.Batch
+	db    *Database
+	size  int
+
+	// written is true if this batch was already committed to the database.
+	// Pebble doesn't allow a batch to be committed twice.
+	written bool
+}
+
+// Put the value into the batch for later writing
+func (b *batch) Put(key, value []byte) error {
+	b.size += len(key) + len(value) + pebbleByteOverhead
+	return b.batch.Set(key, value, nil)
+}
+
+// Delete the key during writing
+func (b *batch) Delete(key []byte) error {
+	b.size += len(key) + pebbleByteOverhead
+	return b.batch.Delete(key, nil)
+}
+
+// Size retrieves the amount of data queued up for writing.
+func (b *batch) Size() int {
+	return b.size
+}
+
+// Write flushes any accumulated data to disk.
+func (b *batch) Write() error {
+	b.db.lock.RLock()
+	defer b.db.lock.RUnlock()
+
+	if b.db.closed {
+		return database.ErrClosed
+	}
+
+	if !b.written {
+		b.written = true
+		return updateError(b.batch.Commit(b.db.writeOptions))
+	}
+
+	// The batch was already committed, so its contents are copied into a new
+	// batch that can be committed.
+	newBatch := b.db.pebbleDB.NewBatch()
+	if err := newBatch.Apply(b.batch, nil); err != nil {
+		return err
+	}
+	return updateError(newBatch.Commit(b.db.writeOptions))
+}
+
+// Reset resets the batch for reuse.
+func (b *batch) Reset() {
+	b.batch.Reset()
+	b.written = false
+	b.size = 0
+}
+
+// Replay the batch contents.
+func (b *batch) Replay(w database.KeyValueWriterDeleter) error {
+	reader := b.batch.Reader()
+	for {
+		kind, k, v, ok := reader.Next()
+		if !ok {
+			return nil
+		}
+		switch kind {
+		case pebble.InternalKeyKindSet:
+			if err := w.Put(k, v); err != nil {
+				return err
+			}
+		case pebble.InternalKeyKindDelete:
+			if err := w.Delete(k); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: unexpected operation kind %s", errInvalidOperation, kind)
+		}
+	}
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch {
+	return b
+}
@@ -0,0 +1,488 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebble
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	// Name is the name of this database for database switches
+	Name = "pebble"
+
+	// DefaultCacheSize is the number of bytes to use for block caching in
+	// pebble.
+	DefaultCacheSize = 512 * 1024 * 1024
+
+	// DefaultBytesPerSync is the number of bytes of sstable data written
+	// before the data is synced to disk.
+	DefaultBytesPerSync = 512 * 1024
+
+	// DefaultWALBytesPerSync is the number of bytes of WAL data written before
+	// the data is synced to disk.
+	DefaultWALBytesPerSync = 0
+
+	// DefaultMemTableSize is the number of bytes a memtable may grow to
+	// before it is flushed to L0.
+	DefaultMemTableSize = 64 * 1024 * 1024
+
+	// DefaultMemTableStopWritesThreshold is the number of queued memtables
+	// that will cause writes to stall.
+	DefaultMemTableStopWritesThreshold = 8
+
+	// DefaultMaxOpenFiles is the number of files descriptors to cap pebble to
+	// use.
+	DefaultMaxOpenFiles = 4096
+
+	// DefaultL0CompactionThreshold is the number of L0 read-amplification
+	// that triggers an L0 compaction.
+	DefaultL0CompactionThreshold = 4
+
+	// DefaultL0StopWritesThreshold is the number of L0 read-amplification
+	// that causes writes to stall.
+	DefaultL0StopWritesThreshold = 64
+
+	// DefaultMetricUpdateFrequency is the frequency to poll the pebble
+	// metrics.
+	DefaultMetricUpdateFrequency = 10 * time.Second
+
+	// pebbleByteOverhead is the number of bytes of constant overhead that
+	// should be added to a batch size per operation.
+	pebbleByteOverhead = 8
+)
+
+var (
+	_ database.Database = (*Database)(nil)
+
+	errInvalidOperation = errors.New("invalid operation")
+)
+
+// Database is a persistent key-value store backed by pebble. Apart from basic
+// data storage functionality it also supports batch writes and iterating over
+// the keyspace in binary-alphabetical order.
+type Database struct {
+	lock     sync.RWMutex
+	pebbleDB *pebble.DB
+	closed   bool
+	// writeOptions are used for all writes to the database.
+	writeOptions *pebble.WriteOptions
+	// openIterators is the set of iterators that have not been released yet.
+	// Pebble panics if the database is closed while iterators are still open,
+	// so they are closed together with the database.
+	openIterators set.Set[*iter]
+
+	// metrics is only initialized and used when [MetricUpdateFrequency] is > 0
+	// in the config
+	metrics *metrics
+	closeOnce sync.Once
+	// closeCh is closed when Close() is called.
+	closeCh chan struct{}
+	// closeWg is used to wait for all goroutines created by New() to exit.
+	closeWg sync.WaitGroup
+}
+
+type config struct {
+	// CacheSize is the number of bytes used to cache uncompressed blocks.
+	//
+	// The default value is 512MiB.
+	CacheSize int64 `json:"cacheSize"`
+	// BytesPerSync is the number of bytes of sstable data written before it
+	// is synced to disk. This smooths out writes to disk.
+	//
+	// The default value is 512KiB.
+	BytesPerSync int `json:"bytesPerSync"`
+	// WALBytesPerSync is the number of bytes of WAL data written before it is
+	// synced to disk. Zero disables periodic WAL syncing.
+	//
+	// The default value is 0.
+	WALBytesPerSync int `json:"walBytesPerSync"`
+	// MemTableSize is the size in bytes of a memtable before it is flushed.
+	//
+	// The default value is 64MiB.
+	MemTableSize int `json:"memTableSize"`
+	// MemTableStopWritesThreshold is the number of queued memtables that
+	// causes writes to be stopped until a flush completes.
+	//
+	// The default value is 8.
+	MemTableStopWritesThreshold int `json:"memTableStopWritesThreshold"`
+	// MaxOpenFiles is the maximum number of open files pebble may use.
+	//
+	// The default value is 4096.
+	MaxOpenFiles int `json:"maxOpenFiles"`
+	// MaxConcurrentCompactions is the number of compactions that may run
+	// concurrently.
+	//
+	// The default value is the number of CPUs.
+	MaxConcurrentCompactions int `json:"maxConcurrentCompactions"`
+	// L0CompactionThreshold is the amount of L0 read-amplification that
+	// triggers an L0 compaction.
+	//
+	// The default value is 4.
+	L0CompactionThreshold int `json:"l0CompactionThreshold"`
+	// L0StopWritesThreshold is the amount of L0 read-amplification that
+	// causes writes to be stopped until compactions catch up.
+	//
+	// The default value is 64.
+	L0StopWritesThreshold int `json:"l0StopWritesThreshold"`
+	// Sync specifies if writes should be synced to disk before returning.
+	//
+	// The default value is true.
+	Sync bool `json:"sync"`
+
+	// MetricUpdateFrequency is the frequency to poll pebble metrics.
+	// If <= 0, pebble metrics aren't polled.
+	MetricUpdateFrequency time.Duration `json:"metricUpdateFrequency"`
+}
+
+// New returns a wrapped pebble database.
+func New(file string, configBytes []byte, log logging.Logger, namespace string, reg prometheus.Registerer) (database.Database, error) {
+	parsedConfig := config{
+		CacheSize:                   DefaultCacheSize,
+		BytesPerSync:                DefaultBytesPerSync,
+		WALBytesPerSync:             DefaultWALBytesPerSync,
+		MemTableSize:                DefaultMemTableSize,
+		MemTableStopWritesThreshold: DefaultMemTableStopWritesThreshold,
+		MaxOpenFiles:                DefaultMaxOpenFiles,
+		MaxConcurrentCompactions:    runtime.NumCPU(),
+		L0CompactionThreshold:       DefaultL0CompactionThreshold,
+		L0StopWritesThreshold:       DefaultL0StopWritesThreshold,
+		Sync:                        true,
+		MetricUpdateFrequency:       DefaultMetricUpdateFrequency,
+	}
+	if len(configBytes) > 0 {
+		if err := json.Unmarshal(configBytes, &parsedConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse db config: %w", err)
+		}
+	}
+
+	log.Info("creating pebble",
+		zap.Reflect("config", parsedConfig),
+	)
+
+	db := &Database{
+		writeOptions:  pebble.Sync,
+		openIterators: set.Set[*iter]{},
+		closeCh:       make(chan struct{}),
+	}
+	if !parsedConfig.Sync {
+		db.writeOptions = pebble.NoSync
+	}
+	if parsedConfig.MetricUpdateFrequency > 0 {
+		metrics, err := newMetrics(namespace, reg)
+		if err != nil {
+			return nil, err
+		}
+		db.metrics = metrics
+	}
+
+	cache := pebble.NewCache(parsedConfig.CacheSize)
+	defer cache.Unref()
+
+	opts := &pebble.Options{
+		Cache:                       cache,
+		BytesPerSync:                parsedConfig.BytesPerSync,
+		WALBytesPerSync:             parsedConfig.WALBytesPerSync,
+		MemTableSize:                parsedConfig.MemTableSize,
+		MemTableStopWritesThreshold: parsedConfig.MemTableStopWritesThreshold,
+		MaxOpenFiles:                parsedConfig.MaxOpenFiles,
+		MaxConcurrentCompactions:    func() int { return parsedConfig.MaxConcurrentCompactions },
+		L0CompactionThreshold:       parsedConfig.L0CompactionThreshold,
+		L0StopWritesThreshold:       parsedConfig.L0StopWritesThreshold,
+		Logger:                      &logger{log: log},
+		EventListener:               db.eventListener(),
+	}
+	opts.Experimental.ReadSamplingMultiplier = -1 // Disable seek compaction
+
+	pebbleDB, err := pebble.Open(file, opts)
+	if err != nil {
+		return nil, err
+	}
+	db.pebbleDB = pebbleDB
+
+	if db.metrics != nil {
+		db.closeWg.Add(1)
+		go func() {
+			t := time.NewTicker(parsedConfig.MetricUpdateFrequency)
+			defer func() {
+				t.Stop()
+				db.closeWg.Done()
+			}()
+
+			for {
+				db.updateMetrics()
+
+				select {
+				case <-t.C:
+				case <-db.closeCh:
+					return
+				}
+			}
+		}()
+	}
+	return db, nil
+}
+
+// Has returns if the key is set in the database
+func (db *Database) Has(key []byte) (bool, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return false, database.ErrClosed
+	}
+
+	_, closer, err := db.pebbleDB.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, updateError(err)
+	}
+	return true, closer.Close()
+}
+
+// Get returns the value the key maps to in the database
+func (db *Database) Get(key []byte) ([]byte, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+
+	value, closer, err := db.pebbleDB.Get(key)
+	if err != nil {
+		return nil, updateError(err)
+	}
+	// The returned value is only valid until closer is closed, so it must be
+	// copied.
+	value = slices.Clone(value)
+	return value, closer.Close()
+}
+
+// Put sets the value of the provided key to the provided value
+func (db *Database) Put(key []byte, value []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.pebbleDB.Set(key, value, db.writeOptions))
+}
+
+// Delete removes the key from the database
+func (db *Database) Delete(key []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+	return updateError(db.pebbleDB.Delete(key, db.writeOptions))
+}
+
+// Compact the underlying DB for the given key range.
+//
+// A nil start is treated as a key before all keys in the DB.
+// And a nil limit is treated as a key after all keys in the DB.
+// Therefore if both are nil then it will compact entire DB.
+func (db *Database) Compact(start []byte, limit []byte) error {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	if limit == nil {
+		// Pebble treats a nil [limit] as a key before all keys, so the last
+		// key in the database is used as the inclusive limit instead.
+		it := db.pebbleDB.NewIter(&pebble.IterOptions{})
+		if !it.Last() {
+			// The database is empty.
+			return updateError(it.Close())
+		}
+		limit = slices.Clone(it.Key())
+		if err := it.Close(); err != nil {
+			return updateError(err)
+		}
+	}
+
+	if pebble.DefaultComparer.Compare(start, limit) >= 0 {
+		// Pebble requires [start] < [limit]. There is nothing to compact.
+		return nil
+	}
+	return updateError(db.pebbleDB.Compact(start, limit, true /* parallelize */))
+}
+
+// Close the database. Any iterators that have not been released are released
+// before the underlying database is closed.
+func (db *Database) Close() error {
+	// The metrics goroutine grabs the read lock, so it must exit before the
+	// write lock is acquired.
+	db.closeOnce.Do(func() {
+		close(db.closeCh)
+	})
+	db.closeWg.Wait()
+
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return database.ErrClosed
+	}
+
+	db.closed = true
+
+	var errs []error
+	for it := range db.openIterators {
+		if err := it.release(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	db.openIterators.Clear()
+
+	if err := db.pebbleDB.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return updateError(errs[0])
+	}
+	return nil
+}
+
+func (db *Database) HealthCheck(context.Context) (interface{}, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return nil, database.ErrClosed
+	}
+	return nil, nil
+}
+
+// NewBatch creates a write/delete-only buffer that is atomically committed to
+// the database when write is called
+func (db *Database) NewBatch() database.Batch {
+	return &batch{
+		db:    db,
+		batch: db.pebbleDB.NewBatch(),
+	}
+}
+
+// NewIterator creates a lexicographically ordered iterator over the database
+func (db *Database) NewIterator() database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+// NewIteratorWithStart creates a lexicographically ordered iterator over the
+// database starting at the provided key
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix creates a lexicographically ordered iterator over the
+// database ignoring keys that do not start with the provided prefix
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix creates a lexicographically ordered iterator
+// over the database starting at start and ignoring keys that do not start with
+// the provided prefix
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if db.closed {
+		return &database.IteratorError{
+			Err: database.ErrClosed,
+		}
+	}
+
+	it := &iter{
+		db:   db,
+		iter: db.pebbleDB.NewIter(keyRange(start, prefix)),
+	}
+	db.openIterators.Add(it)
+	return it
+}
+
+// keyRange returns the iterator bounds that cover all keys that are >= [start]
+// and that start with [prefix].
+func keyRange(start, prefix []byte) *pebble.IterOptions {
+	opts := &pebble.IterOptions{
+		LowerBound: prefix,
+		UpperBound: prefixToUpperBound(prefix),
+	}
+	if pebble.DefaultComparer.Compare(start, prefix) == 1 {
+		opts.LowerBound = start
+	}
+	return opts
+}
+
+// prefixToUpperBound returns the smallest key that is larger than all keys
+// with the given [prefix], or nil if no such key exists.
+func prefixToUpperBound(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xFF {
+			upperBound := slices.Clone(prefix[:i+1])
+			upperBound[i]++
+			return upperBound
+		}
+	}
+	return nil
+}
+
+func updateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, pebble.ErrClosed):
+		return database.ErrClosed
+	case errors.Is(err, pebble.ErrNotFound):
+		return database.ErrNotFound
+	default:
+		return err
+	}
+}
+
+// logger forwards pebble's internal log messages to the node logger.
+type logger struct {
+	log logging.Logger
+}
+
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.log.Debug("pebble",
+		zap.String("msg", fmt.Sprintf(format, args...)),
+	)
+}
+
+func (l *logger) Fatalf(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	l.log.Fatal("pebble",
+		zap.String("msg", msg),
+	)
+	panic(msg)
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebble
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newDB(t testing.TB) database.Database {
+	folder := t.TempDir()
+	db, err := New(folder, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	return db
+}
+
+func TestInterface(t *testing.T) {
+	for _, test := range database.Tests {
+		db := newDB(t)
+
+		test(t, db)
+
+		// The database may have been closed by the test, so we don't care if it
+		// errors here.
+		_ = db.Close()
+	}
+}
+
+func FuzzInterface(f *testing.F) {
+	for _, test := range database.FuzzTests {
+		db := newDB(f)
+
+		test(f, db)
+
+		// The database may have been closed by the test, so we don't care if it
+		// errors here.
+		_ = db.Close()
+	}
+}
+
+func TestPrefixToUpperBound(t *testing.T) {
+	tests := []struct {
+		prefix   []byte
+		expected []byte
+	}{
+		{
+			prefix:   nil,
+			expected: nil,
+		},
+		{
+			prefix:   []byte{0x01},
+			expected: []byte{0x02},
+		},
+		{
+			prefix:   []byte{0x01, 0xFF},
+			expected: []byte{0x02},
+		},
+		{
+			prefix:   []byte{0xFF, 0xFF},
+			expected: nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%x", test.prefix), func(t *testing.T) {
+			require.Equal(t, test.expected, prefixToUpperBound(test.prefix))
+		})
+	}
+}
+
+func TestMigrateFromLevelDB(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	levelDBPath := filepath.Join(dir, leveldb.Name)
+	pebbleDBPath := filepath.Join(dir, Name)
+
+	src, err := leveldb.New(levelDBPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(err)
+	for i := 0; i < 1000; i++ {
+		require.NoError(src.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	require.NoError(src.Close())
+
+	require.NoError(MigrateFromLevelDB(levelDBPath, pebbleDBPath, nil, logging.NoLog{}))
+
+	// Migrating a second time must not overwrite the migrated database.
+	err = MigrateFromLevelDB(levelDBPath, pebbleDBPath, nil, logging.NoLog{})
+	require.ErrorIs(err, errAlreadyExists)
+
+	dst, err := New(pebbleDBPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	require.NoError(err)
+	defer dst.Close()
+
+	count, err := database.Count(dst)
+	require.NoError(err)
+	require.Equal(1000, count)
+	for i := 0; i < 1000; i++ {
+		value, err := dst.Get([]byte(fmt.Sprintf("key%d", i)))
+		require.NoError(err)
+		require.Equal([]byte(fmt.Sprintf("value%d", i)), value)
+	}
+}
+
+func BenchmarkInterface(b *testing.B) {
+	for _, size := range database.BenchmarkSizes {
+		keys, values := database.SetupBenchmark(b, size[0], size[1], size[2])
+		for _, bench := range database.Benchmarks {
+			db := newDB(b)
+
+			bench(b, db, "pebble", keys, values)
+
+			// The database may have been closed by the test, so we don't care if it
+			// errors here.
+			_ = db.Close()
+		}
+	}
+}
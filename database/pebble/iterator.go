@@ -0,0 +1,109 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebble
+
+import (
+	"github.com/cockroachdb/pebble"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var _ database.Iterator = (*iter)(nil)
+
+type iter struct {
+	// [db] is used to check if the database was closed and to remove the
+	// iterator from the set of open iterators when it is released.
+	db   *Database
+	iter *pebble.Iterator
+
+	initialized bool
+	// released is true once the underlying pebble iterator was closed.
+	released bool
+	err      error
+
+	hasNext  bool
+	key, val []byte
+}
+
+func (it *iter) Next() bool {
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	switch {
+	case it.db.closed:
+		// Short-circuit and set an error if the underlying database has been
+		// closed.
+		it.hasNext = false
+		it.err = database.ErrClosed
+	case it.released:
+		it.hasNext = false
+	case !it.initialized:
+		it.initialized = true
+		it.hasNext = it.iter.First()
+	default:
+		it.hasNext = it.iter.Next()
+	}
+
+	if !it.hasNext {
+		it.key = nil
+		it.val = nil
+		return false
+	}
+
+	// The pebble iterator only guarantees the key and value to be valid until
+	// the next positioning call, so they are copied.
+	it.key = slices.Clone(it.iter.Key())
+	it.val = slices.Clone(it.iter.Value())
+	return true
+}
+
+func (it *iter) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+
+	it.db.lock.RLock()
+	defer it.db.lock.RUnlock()
+
+	if it.released {
+		return nil
+	}
+	return updateError(it.iter.Error())
+}
+
+func (it *iter) Key() []byte {
+	return it.key
+}
+
+func (it *iter) Value() []byte {
+	return it.val
+}
+
+func (it *iter) Release() {
+	it.db.lock.Lock()
+	defer it.db.lock.Unlock()
+
+	if it.released || it.db.closed {
+		// The database closes all open iterators when it is closed.
+		return
+	}
+
+	if err := it.release(); err != nil {
+		it.err = updateError(err)
+	}
+	it.db.openIterators.Remove(it)
+}
+
+// release closes the underlying pebble iterator.
+//
+// Assumes the database lock is held.
+func (it *iter) release() error {
+	if it.released {
+		return nil
+	}
+	it.released = true
+	return it.iter.Close()
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebble
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var levelLabels = []string{"level"}
+
+type metrics struct {
+	// total number of writes that have been stalled due to compaction or
+	// flushing falling behind
+	writeStallCount prometheus.Counter
+	// total amount of time (in ns) that writes have been stalled
+	writeStallDuration prometheus.Counter
+	// set to 1 if writes are currently stalled
+	writeIsStalled prometheus.Gauge
+
+	// total number of compactions performed
+	compactions prometheus.Counter
+	// estimated number of bytes that need to be compacted for the LSM to
+	// reach a stable state
+	compactionDebt prometheus.Gauge
+	// number of bytes present in sstables being written by in-progress
+	// compactions
+	compactionInProgressBytes prometheus.Gauge
+	// total number of memtable flushes performed
+	flushes prometheus.Counter
+
+	// size of the memtables in bytes
+	memTableSize prometheus.Gauge
+	// size of the live WAL data in bytes
+	walSize prometheus.Gauge
+	// total number of bytes of cached data blocks
+	blockCacheSize prometheus.Gauge
+
+	// number of sstables per level
+	levelTableCount *prometheus.GaugeVec
+	// size of each level
+	levelSize *prometheus.GaugeVec
+
+	stallStart  time.Time
+	priorCounts *pebble.Metrics
+}
+
+func newMetrics(namespace string, reg prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		writeStallCount: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "write_stalls",
+			Help:      "number of cumulative writes that have been stalled due to compaction",
+		}),
+		writeStallDuration: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "write_stall_duration",
+			Help:      "amount of time (in ns) that writes have been stalled due to compaction",
+		}),
+		writeIsStalled: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "write_stalled",
+			Help:      "1 if writes are currently stalled due to compaction",
+		}),
+
+		compactions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "comps",
+			Help:      "total number of compactions performed",
+		}),
+		compactionDebt: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "comp_debt",
+			Help:      "estimated number of bytes that need to be compacted",
+		}),
+		compactionInProgressBytes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "comp_in_progress_bytes",
+			Help:      "number of bytes being written by in-progress compactions",
+		}),
+		flushes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "flushes",
+			Help:      "total number of memtable flushes performed",
+		}),
+
+		memTableSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mem_table_size",
+			Help:      "size of the memtables in bytes",
+		}),
+		walSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "wal_size",
+			Help:      "size of the live WAL data in bytes",
+		}),
+		blockCacheSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "block_cache_size",
+			Help:      "total size of cached data blocks",
+		}),
+
+		levelTableCount: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "tables",
+				Help:      "number of sstables by level",
+			},
+			levelLabels,
+		),
+		levelSize: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "size",
+				Help:      "size of each level",
+			},
+			levelLabels,
+		),
+
+		priorCounts: &pebble.Metrics{},
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		reg.Register(m.writeStallCount),
+		reg.Register(m.writeStallDuration),
+		reg.Register(m.writeIsStalled),
+
+		reg.Register(m.compactions),
+		reg.Register(m.compactionDebt),
+		reg.Register(m.compactionInProgressBytes),
+		reg.Register(m.flushes),
+
+		reg.Register(m.memTableSize),
+		reg.Register(m.walSize),
+		reg.Register(m.blockCacheSize),
+
+		reg.Register(m.levelTableCount),
+		reg.Register(m.levelSize),
+	)
+	return m, errs.Err
+}
+
+// eventListener returns the pebble event listener that records write stalls.
+func (db *Database) eventListener() *pebble.EventListener {
+	if db.metrics == nil {
+		return &pebble.EventListener{}
+	}
+	metrics := db.metrics
+	return &pebble.EventListener{
+		// Pebble serializes stall begin and end events.
+		WriteStallBegin: func(pebble.WriteStallBeginInfo) {
+			metrics.stallStart = time.Now()
+			metrics.writeStallCount.Inc()
+			metrics.writeIsStalled.Set(1)
+		},
+		WriteStallEnd: func() {
+			metrics.writeStallDuration.Add(float64(time.Since(metrics.stallStart)))
+			metrics.writeIsStalled.Set(0)
+		},
+	}
+}
+
+func (db *Database) updateMetrics() {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.closed {
+		return
+	}
+
+	metrics := db.metrics
+	current := db.pebbleDB.Metrics()
+	prior := metrics.priorCounts
+
+	metrics.compactions.Add(float64(current.Compact.Count - prior.Compact.Count))
+	metrics.compactionDebt.Set(float64(current.Compact.EstimatedDebt))
+	metrics.compactionInProgressBytes.Set(float64(current.Compact.InProgressBytes))
+	metrics.flushes.Add(float64(current.Flush.Count - prior.Flush.Count))
+
+	metrics.memTableSize.Set(float64(current.MemTable.Size))
+	metrics.walSize.Set(float64(current.WAL.Size))
+	metrics.blockCacheSize.Set(float64(current.BlockCache.Size))
+
+	for level, levelMetrics := range current.Levels {
+		levelStr := strconv.Itoa(level)
+		metrics.levelTableCount.WithLabelValues(levelStr).Set(float64(levelMetrics.NumFiles))
+		metrics.levelSize.WithLabelValues(levelStr).Set(float64(levelMetrics.Size))
+	}
+
+	metrics.priorCounts = current
+}
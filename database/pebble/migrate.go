@@ -0,0 +1,127 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pebble
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// migrationBatchSize is the number of bytes that are accumulated before
+	// a batch is written during a migration.
+	migrationBatchSize = 4 * units.MiB
+
+	// migrationLogFrequency is the number of keys copied between progress
+	// logs.
+	migrationLogFrequency = 1_000_000
+
+	migratingSuffix = ".migrating"
+)
+
+var errAlreadyExists = errors.New("database already exists")
+
+// MigrateFromLevelDB copies all key-value pairs of the leveldb database at
+// [levelDBPath] into a new pebble database at [pebbleDBPath].
+//
+// The data is copied into a temporary directory that is only moved to
+// [pebbleDBPath] once the copy has completed, so an interrupted migration is
+// restarted from scratch. The leveldb database isn't modified.
+func MigrateFromLevelDB(
+	levelDBPath string,
+	pebbleDBPath string,
+	pebbleConfigBytes []byte,
+	log logging.Logger,
+) error {
+	if _, err := os.Stat(pebbleDBPath); err == nil {
+		return fmt.Errorf("%w at %s", errAlreadyExists, pebbleDBPath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	tmpPath := pebbleDBPath + migratingSuffix
+	if err := os.RemoveAll(tmpPath); err != nil {
+		return fmt.Errorf("couldn't remove stale migration at %s: %w", tmpPath, err)
+	}
+
+	log.Info("migrating leveldb to pebble",
+		zap.String("source", levelDBPath),
+		zap.String("destination", pebbleDBPath),
+	)
+
+	// Metrics of both databases are discarded during the migration.
+	src, err := leveldb.New(levelDBPath, nil, log, "", prometheus.NewRegistry())
+	if err != nil {
+		return fmt.Errorf("couldn't open leveldb at %s: %w", levelDBPath, err)
+	}
+	dst, err := New(tmpPath, pebbleConfigBytes, log, "", prometheus.NewRegistry())
+	if err != nil {
+		_ = src.Close()
+		return fmt.Errorf("couldn't create pebble at %s: %w", tmpPath, err)
+	}
+
+	numKeys, err := Copy(dst, src, log)
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		err,
+		src.Close(),
+		dst.Close(),
+	)
+	if errs.Errored() {
+		return fmt.Errorf("failed to migrate leveldb to pebble: %w", errs.Err)
+	}
+
+	if err := os.Rename(tmpPath, pebbleDBPath); err != nil {
+		return err
+	}
+
+	log.Info("finished migrating leveldb to pebble",
+		zap.Int("numKeys", numKeys),
+	)
+	return nil
+}
+
+// Copy writes every key-value pair of [src] into [dst] and returns the number
+// of copied keys.
+func Copy(dst database.Batcher, src database.Iteratee, log logging.Logger) (int, error) {
+	it := src.NewIterator()
+	defer it.Release()
+
+	batch := dst.NewBatch()
+	numKeys := 0
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return numKeys, err
+		}
+		numKeys++
+
+		if batch.Size() >= migrationBatchSize {
+			if err := batch.Write(); err != nil {
+				return numKeys, err
+			}
+			batch.Reset()
+		}
+		if numKeys%migrationLogFrequency == 0 {
+			log.Info("migrating database",
+				zap.Int("numKeys", numKeys),
+			)
+		}
+	}
+	if err := it.Error(); err != nil {
+		return numKeys, err
+	}
+	return numKeys, batch.Write()
+}
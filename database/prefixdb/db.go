@@ -54,7 +54,7 @@ func New(prefix []byte, db database.Database) *Database {
 // prefixes.
 func NewNested(prefix []byte, db database.Database) *Database {
 	return &Database{
-		dbPrefix: hashing.ComputeHash256(prefix),
+		dbPrefix: MakePrefix(prefix),
 		db:       db,
 		bufferPool: sync.Pool{
 			New: func() interface{} {
@@ -64,6 +64,12 @@ func NewNested(prefix []byte, db database.Database) *Database {
 	}
 }
 
+// MakePrefix returns the prefix that is prepended to all keys of a database
+// created by NewNested with [prefix].
+func MakePrefix(prefix []byte) []byte {
+	return hashing.ComputeHash256(prefix)
+}
+
 // Assumes that it is OK for the argument to db.db.Has
 // to be modified after db.db.Has returns
 // [key] may be modified after this method returns.
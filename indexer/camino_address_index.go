@@ -0,0 +1,460 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+// Maximum number of address txs, that are read in a call to getAddressTxs.
+// Bounds the work of a call, if only few txs match the filters.
+const maxAddressTxsScanned = 16 * MaxFetchedByRange
+
+var (
+	// Source prefix --> Index of the next container of the source to index
+	addressIndexProgressPrefix = []byte{0x00}
+	// Address --> Number of indexed txs of the address
+	addressTxCountPrefix = []byte{0x01}
+	// Address + Index --> Indexed tx of the address
+	addressTxPrefix = []byte{0x02}
+	// Tx ID --> Owners of the UTXOs consumed by the tx, until the tx is indexed
+	addressInputOwnersPrefix = []byte{0x03}
+)
+
+// addressTx is a tx of an accepted container and the addresses it touched.
+type addressTx struct {
+	txID     ids.ID
+	txType   string
+	assetIDs set.Set[ids.ID]
+	addrs    set.Set[ids.ShortID]
+
+	// IDs of the UTXOs, that the tx consumes from the chain state
+	inputIDs []ids.ID
+	// Chain, the tx imports UTXOs from
+	sourceChain ids.ID
+	// IDs of the UTXOs, that the tx imports from [sourceChain]
+	importedInputIDs []ids.ID
+	// UTXO ID --> Addresses of the UTXO, for the UTXOs the tx produces
+	producedAddrs map[ids.ID][]ids.ShortID
+}
+
+// addressTxEntry is the persisted form of an addressTx.
+type addressTxEntry struct {
+	TxID     ids.ID   `serialize:"true"`
+	Type     string   `serialize:"true"`
+	AssetIDs []ids.ID `serialize:"true"`
+}
+
+// addressTxsParser returns the txs of the accepted containers of a chain.
+type addressTxsParser interface {
+	parseBlock(blkBytes []byte) ([]*addressTx, error)
+	parseTx(txBytes []byte) ([]*addressTx, error)
+	parseUTXO(utxoBytes []byte) (*avax.UTXO, error)
+}
+
+// utxoGetter returns the UTXOs of the last accepted state of a chain.
+type utxoGetter interface {
+	GetUTXO(utxoID ids.ID) (*avax.UTXO, error)
+}
+
+// addressIndexSource is a container index, whose containers are indexed by
+// the addresses their txs touched.
+type addressIndexSource struct {
+	prefix byte
+	index  Index
+	parse  func([]byte) ([]*addressTx, error)
+}
+
+// addressIndex indexes the txs of a chain by the addresses they touched.
+// The txs are read from the container indices of the chain in the background,
+// so txs, that were accepted before the address index was enabled, are
+// indexed as well. Progress is tracked per container index, so indexing
+// resumes where it stopped after restarts.
+//
+// The UTXOs consumed by a tx are gone from the chain state, once the tx was
+// accepted. So their owners are looked up when the container of the tx is
+// accepted and kept until the tx is indexed. The consumed UTXOs of txs, that
+// were accepted before the address index was enabled, aren't indexed.
+type addressIndex struct {
+	codec codec.Manager
+	log   logging.Logger
+	// Parses the UTXOs imported from shared memory
+	parser addressTxsParser
+	// Returns the UTXOs consumed from the chain state. Can be nil.
+	utxos utxoGetter
+	// When [baseDB] is committed, writes to [baseDB]
+	vDB        *versiondb.Database
+	baseDB     database.Database
+	progressDB database.Database
+	countDB    database.Database
+	txDB       database.Database
+	// Read and deleted through [vDB], when the txs are indexed
+	inputOwnersDB database.Database
+	// Written directly to [baseDB], when the containers are accepted
+	acceptedInputOwnersDB database.Database
+
+	sources []*addressIndexSource
+	// Signaled, when a container was accepted by a source
+	accepted chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Closes [baseDB] on close.
+func newAddressIndex(
+	baseDB database.Database,
+	log logging.Logger,
+	codec codec.Manager,
+	parser addressTxsParser,
+	utxos utxoGetter,
+) *addressIndex {
+	vDB := versiondb.New(baseDB)
+	ctx, cancel := context.WithCancel(context.Background())
+	return &addressIndex{
+		codec:         codec,
+		log:           log,
+		parser:        parser,
+		utxos:         utxos,
+		vDB:           vDB,
+		baseDB:        baseDB,
+		progressDB:    prefixdb.New(addressIndexProgressPrefix, vDB),
+		countDB:       prefixdb.New(addressTxCountPrefix, vDB),
+		txDB:          prefixdb.New(addressTxPrefix, vDB),
+		inputOwnersDB: prefixdb.New(addressInputOwnersPrefix, vDB),
+		// Not compressed with the prefix of [baseDB], so that the keys are
+		// the same as the keys of [inputOwnersDB]
+		acceptedInputOwnersDB: prefixdb.NewNested(addressInputOwnersPrefix, baseDB),
+		accepted:              make(chan struct{}, 1),
+		ctx:                   ctx,
+		cancel:                cancel,
+	}
+}
+
+// addSource adds a container index to the address index. Sources are indexed
+// in the order they were added. Must be called before start.
+func (a *addressIndex) addSource(prefix byte, index Index, parse func([]byte) ([]*addressTx, error)) {
+	a.sources = append(a.sources, &addressIndexSource{
+		prefix: prefix,
+		index:  index,
+		parse:  parse,
+	})
+}
+
+// start indexes the containers of the sources in the background, until the
+// address index is closed.
+func (a *addressIndex) start() {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+
+		for {
+			for _, source := range a.sources {
+				if err := a.indexSource(source); err != nil {
+					a.log.Error("failed to index txs by address",
+						zap.Uint8("source", source.prefix),
+						zap.Error(err),
+					)
+				}
+			}
+
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-a.accepted:
+			}
+		}
+	}()
+}
+
+// onAccept must be called after a source accepted a container.
+func (a *addressIndex) onAccept() {
+	select {
+	case a.accepted <- struct{}{}:
+	default:
+	}
+}
+
+// putInputOwners looks up the owners of the UTXOs, that are consumed by the
+// txs of the accepted [container], and keeps them until the txs are indexed.
+// Must be called before the container is passed to its source and before the
+// VM accepted it, so the consumed UTXOs are still in the chain state and in
+// shared memory. UTXOs, that are produced by an earlier tx of the container,
+// are resolved from the container.
+// Assumes [ctx.Lock] is held.
+func (a *addressIndex) putInputOwners(
+	ctx *snow.ConsensusContext,
+	container []byte,
+	parse func([]byte) ([]*addressTx, error),
+) error {
+	addrTxs, err := parse(container)
+	if err != nil {
+		// The container is skipped by indexContainer as well
+		return nil
+	}
+
+	produced := map[ids.ID][]ids.ShortID{}
+	for _, addrTx := range addrTxs {
+		owners := set.Set[ids.ShortID]{}
+		for _, utxoID := range addrTx.inputIDs {
+			if addrs, ok := produced[utxoID]; ok {
+				owners.Add(addrs...)
+				continue
+			}
+			if a.utxos == nil {
+				continue
+			}
+			utxo, err := a.utxos.GetUTXO(utxoID)
+			if err == database.ErrNotFound {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			owners.Add(outputAddresses(utxo.Out)...)
+		}
+		if len(addrTx.importedInputIDs) > 0 {
+			importedOwners, err := a.getImportedOwners(ctx, addrTx)
+			if err != nil {
+				a.log.Warn("couldn't look up owners of imported UTXOs",
+					zap.Stringer("txID", addrTx.txID),
+					zap.Error(err),
+				)
+			}
+			owners.Add(importedOwners...)
+		}
+		for utxoID, addrs := range addrTx.producedAddrs {
+			produced[utxoID] = addrs
+		}
+
+		if owners.Len() == 0 {
+			continue
+		}
+		ownersBytes, err := a.codec.Marshal(codecVersion, owners.List())
+		if err != nil {
+			return err
+		}
+		if err := a.acceptedInputOwnersDB.Put(addrTx.txID[:], ownersBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getImportedOwners returns the owners of the UTXOs, that [addrTx] imports
+// from shared memory.
+func (a *addressIndex) getImportedOwners(ctx *snow.ConsensusContext, addrTx *addressTx) ([]ids.ShortID, error) {
+	utxoIDs := make([][]byte, len(addrTx.importedInputIDs))
+	for i, utxoID := range addrTx.importedInputIDs {
+		utxoID := utxoID
+		utxoIDs[i] = utxoID[:]
+	}
+	utxosBytes, err := ctx.SharedMemory.Get(addrTx.sourceChain, utxoIDs)
+	if err != nil {
+		return nil, err
+	}
+	var owners []ids.ShortID
+	for _, utxoBytes := range utxosBytes {
+		utxo, err := a.parser.parseUTXO(utxoBytes)
+		if err != nil {
+			return nil, err
+		}
+		owners = append(owners, outputAddresses(utxo.Out)...)
+	}
+	return owners, nil
+}
+
+func (a *addressIndex) Close() error {
+	a.cancel()
+	a.wg.Wait()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		a.progressDB.Close(),
+		a.countDB.Close(),
+		a.txDB.Close(),
+		a.inputOwnersDB.Close(),
+		a.acceptedInputOwnersDB.Close(),
+		a.vDB.Close(),
+		a.baseDB.Close(),
+	)
+	return errs.Err
+}
+
+// indexSource indexes the containers of [source], that weren't indexed yet.
+func (a *addressIndex) indexSource(source *addressIndexSource) error {
+	lastAccepted, err := source.index.GetLastAccepted()
+	if err == errNoneAccepted {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	lastAcceptedIndex, err := source.index.GetIndex(lastAccepted.ID)
+	if err != nil {
+		return err
+	}
+
+	nextIndex, err := a.getProgress(source.prefix)
+	if err != nil {
+		return err
+	}
+	if nextIndex <= lastAcceptedIndex {
+		a.log.Debug("indexing txs by address",
+			zap.Uint8("source", source.prefix),
+			zap.Uint64("startIndex", nextIndex),
+			zap.Uint64("lastAcceptedIndex", lastAcceptedIndex),
+		)
+	}
+
+	for nextIndex <= lastAcceptedIndex {
+		numToFetch := math.Min(lastAcceptedIndex-nextIndex+1, MaxFetchedByRange)
+		containers, err := source.index.GetContainerRange(nextIndex, numToFetch)
+		if err != nil {
+			return err
+		}
+		for _, container := range containers {
+			if a.ctx.Err() != nil {
+				return nil
+			}
+			if err := a.indexContainer(source, container, nextIndex); err != nil {
+				return fmt.Errorf("couldn't index container %s: %w", container.ID, err)
+			}
+			nextIndex++
+		}
+	}
+	return nil
+}
+
+// indexContainer indexes the txs of [container], which is at [index] of
+// [source], and commits them together with the progress of [source].
+func (a *addressIndex) indexContainer(source *addressIndexSource, container Container, index uint64) error {
+	addrTxs, err := source.parse(container.Bytes)
+	if err != nil {
+		// Unparsable containers can't be indexed later either, so they are
+		// skipped to not block the index.
+		a.log.Warn("skipping unparsable container",
+			zap.Uint8("source", source.prefix),
+			zap.Stringer("containerID", container.ID),
+			zap.Error(err),
+		)
+		addrTxs = nil
+	}
+
+	for _, addrTx := range addrTxs {
+		if err := a.addInputOwners(addrTx); err != nil {
+			return err
+		}
+		entryBytes, err := a.codec.Marshal(codecVersion, &addressTxEntry{
+			TxID:     addrTx.txID,
+			Type:     addrTx.txType,
+			AssetIDs: addrTx.assetIDs.List(),
+		})
+		if err != nil {
+			return err
+		}
+		for addr := range addrTx.addrs {
+			count, err := database.GetUInt64(a.countDB, addr[:])
+			switch {
+			case err == database.ErrNotFound:
+				count = 0
+			case err != nil:
+				return err
+			}
+			if err := a.txDB.Put(addressTxKey(addr, count), entryBytes); err != nil {
+				return err
+			}
+			if err := database.PutUInt64(a.countDB, addr[:], count+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := database.PutUInt64(a.progressDB, []byte{source.prefix}, index+1); err != nil {
+		return err
+	}
+	return a.vDB.Commit()
+}
+
+// addInputOwners adds the owners of the UTXOs consumed by [addrTx], that were
+// looked up when its container was accepted, to the addresses of [addrTx].
+func (a *addressIndex) addInputOwners(addrTx *addressTx) error {
+	ownersBytes, err := a.inputOwnersDB.Get(addrTx.txID[:])
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var owners []ids.ShortID
+	if _, err := a.codec.Unmarshal(ownersBytes, &owners); err != nil {
+		return err
+	}
+	addrTx.addrs.Add(owners...)
+	return a.inputOwnersDB.Delete(addrTx.txID[:])
+}
+
+func (a *addressIndex) getProgress(sourcePrefix byte) (uint64, error) {
+	nextIndex, err := database.GetUInt64(a.progressDB, []byte{sourcePrefix})
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return nextIndex, err
+}
+
+// read returns the IDs of the txs of [addr], that are of type [txType] and
+// use [assetID], in the order they were indexed. Empty [txType] and [assetID]
+// match all txs. Reading starts at the [cursor]th tx of [addr]. At most
+// [pageSize] tx IDs are returned. Also returns the cursor to continue
+// reading from.
+func (a *addressIndex) read(addr ids.ShortID, assetID ids.ID, txType string, cursor, pageSize uint64) ([]ids.ID, uint64, error) {
+	it := a.txDB.NewIteratorWithStartAndPrefix(addressTxKey(addr, cursor), addr[:])
+	defer it.Release()
+
+	var (
+		txIDs   []ids.ID
+		scanned uint64
+	)
+	for uint64(len(txIDs)) < pageSize && scanned < maxAddressTxsScanned && it.Next() {
+		scanned++
+
+		entry := addressTxEntry{}
+		if _, err := a.codec.Unmarshal(it.Value(), &entry); err != nil {
+			return nil, 0, err
+		}
+		if txType != "" && entry.Type != txType {
+			continue
+		}
+		if assetID != ids.Empty && !slices.Contains(entry.AssetIDs, assetID) {
+			continue
+		}
+		txIDs = append(txIDs, entry.TxID)
+	}
+	return txIDs, cursor + scanned, it.Error()
+}
+
+func addressTxKey(addr ids.ShortID, index uint64) []byte {
+	key := make([]byte, 0, len(addr)+wrappers.LongLen)
+	key = append(key, addr[:]...)
+	return append(key, database.PackUInt64(index)...)
+}
@@ -0,0 +1,335 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/multisig"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/locked"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var errUnparsable = errors.New("unparsable")
+
+func TestAddressIndex(t *testing.T) {
+	require := require.New(t)
+
+	codec := codec.NewDefaultManager()
+	require.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	db := memdb.New()
+	ctx := snow.DefaultConsensusContextTest()
+
+	var (
+		addr1   = ids.GenerateTestShortID()
+		addr2   = ids.GenerateTestShortID()
+		assetID = ids.GenerateTestID()
+		// Container bytes --> txs of the container
+		containerTxs = map[string][]*addressTx{}
+		txIDs        []ids.ID
+	)
+	parse := func(containerBytes []byte) ([]*addressTx, error) {
+		addrTxs, ok := containerTxs[string(containerBytes)]
+		if !ok {
+			return nil, errUnparsable
+		}
+		return addrTxs, nil
+	}
+
+	containerIndex, err := newIndex(prefixdb.New([]byte{blockPrefix}, db), logging.NoLog{}, codec, mockable.Clock{})
+	require.NoError(err)
+	newAddrIndex := func() *addressIndex {
+		addrIndex := newAddressIndex(prefixdb.New([]byte{addressPrefix}, db), logging.NoLog{}, codec, nil, nil)
+		addrIndex.addSource(blockPrefix, containerIndex, parse)
+		return addrIndex
+	}
+	acceptContainer := func(onAccept func(), addrs ...ids.ShortID) {
+		txID := ids.GenerateTestID()
+		txIDs = append(txIDs, txID)
+		txType := "BaseTx"
+		if len(txIDs)%2 == 0 {
+			txType = "DepositTx"
+		}
+		txAddrs := set.NewSet[ids.ShortID](len(addrs))
+		txAddrs.Add(addrs...)
+		containerID := ids.GenerateTestID()
+		containerTxs[string(containerID[:])] = []*addressTx{{
+			txID:     txID,
+			txType:   txType,
+			assetIDs: set.Set[ids.ID]{assetID: struct{}{}},
+			addrs:    txAddrs,
+		}}
+		require.NoError(containerIndex.Accept(ctx, containerID, containerID[:]))
+		if onAccept != nil {
+			onAccept()
+		}
+	}
+	requireIndexed := func(addrIndex *addressIndex) {
+		lastAccepted, err := containerIndex.GetLastAccepted()
+		require.NoError(err)
+		lastAcceptedIndex, err := containerIndex.GetIndex(lastAccepted.ID)
+		require.NoError(err)
+		require.Eventually(func() bool {
+			nextIndex, err := addrIndex.getProgress(blockPrefix)
+			require.NoError(err)
+			return nextIndex == lastAcceptedIndex+1
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Containers accepted before the address index was created are
+	// backfilled.
+	acceptContainer(nil, addr1)
+	acceptContainer(nil, addr1, addr2)
+	require.NoError(containerIndex.Accept(ctx, ids.GenerateTestID(), []byte("unparsable")))
+	addrIndex := newAddrIndex()
+	addrIndex.start()
+	requireIndexed(addrIndex)
+
+	// Containers accepted afterwards are indexed as well.
+	acceptContainer(addrIndex.onAccept, addr2)
+	acceptContainer(addrIndex.onAccept, addr1)
+	requireIndexed(addrIndex)
+
+	readTxs := func(addr ids.ShortID, assetID ids.ID, txType string, cursor, pageSize uint64) ([]ids.ID, uint64) {
+		txIDs, nextCursor, err := addrIndex.read(addr, assetID, txType, cursor, pageSize)
+		require.NoError(err)
+		return txIDs, nextCursor
+	}
+	readAllTxs := func(addr ids.ShortID) []ids.ID {
+		txIDs, _ := readTxs(addr, ids.Empty, "", 0, MaxFetchedByRange)
+		return txIDs
+	}
+
+	require.Equal([]ids.ID{txIDs[0], txIDs[1], txIDs[3]}, readAllTxs(addr1))
+	require.Equal([]ids.ID{txIDs[1], txIDs[2]}, readAllTxs(addr2))
+
+	// Pagination
+	page, cursor := readTxs(addr1, ids.Empty, "", 0, 2)
+	require.Equal([]ids.ID{txIDs[0], txIDs[1]}, page)
+	require.EqualValues(2, cursor)
+	page, cursor = readTxs(addr1, ids.Empty, "", cursor, 2)
+	require.Equal([]ids.ID{txIDs[3]}, page)
+	require.EqualValues(3, cursor)
+	page, cursor = readTxs(addr1, ids.Empty, "", cursor, 2)
+	require.Empty(page)
+	require.EqualValues(3, cursor)
+
+	// Filters
+	page, _ = readTxs(addr1, ids.Empty, "DepositTx", 0, MaxFetchedByRange)
+	require.Equal([]ids.ID{txIDs[1], txIDs[3]}, page)
+	page, _ = readTxs(addr1, assetID, "BaseTx", 0, MaxFetchedByRange)
+	require.Equal([]ids.ID{txIDs[0]}, page)
+	page, _ = readTxs(addr1, ids.GenerateTestID(), "", 0, MaxFetchedByRange)
+	require.Empty(page)
+
+	// Indexing resumes after restarts without indexing containers twice.
+	require.NoError(addrIndex.Close())
+	acceptContainer(nil, addr2)
+	addrIndex = newAddrIndex()
+	addrIndex.start()
+	requireIndexed(addrIndex)
+	require.Equal([]ids.ID{txIDs[1], txIDs[2], txIDs[4]}, readAllTxs(addr2))
+	require.NoError(addrIndex.Close())
+}
+
+func TestPlatformAddressTxsParser(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		outAddr       = ids.GenerateTestShortID()
+		lockedOutAddr = ids.GenerateTestShortID()
+		ownerAddr     = ids.GenerateTestShortID()
+		aliasOwner    = ids.GenerateTestShortID()
+		voterAddr     = ids.GenerateTestShortID()
+		assetID       = ids.GenerateTestID()
+		in            = &avax.TransferableInput{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: assetID},
+			In:     &secp256k1fx.TransferInput{},
+		}
+		baseTx = txs.BaseTx{BaseTx: avax.BaseTx{Ins: []*avax.TransferableInput{in}, Outs: []*avax.TransferableOutput{
+			{
+				Asset: avax.Asset{ID: assetID},
+				Out:   &secp256k1fx.TransferOutput{OutputOwners: secp256k1fx.OutputOwners{Addrs: []ids.ShortID{outAddr}}},
+			},
+			{
+				Asset: avax.Asset{ID: assetID},
+				Out: &locked.Out{TransferableOut: &secp256k1fx.TransferOutput{
+					OutputOwners: secp256k1fx.OutputOwners{Addrs: []ids.ShortID{lockedOutAddr}},
+				}},
+			},
+		}}}
+	)
+
+	newTx := func(utx txs.UnsignedTx) *txs.Tx {
+		tx := &txs.Tx{Unsigned: utx}
+		require.NoError(tx.Initialize(txs.Codec))
+		return tx
+	}
+	depositTx := newTx(&txs.DepositTx{
+		BaseTx:       baseTx,
+		RewardsOwner: &secp256k1fx.OutputOwners{Addrs: []ids.ShortID{ownerAddr}},
+	})
+	multisigAliasTx := newTx(&txs.MultisigAliasTx{
+		BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{}},
+		MultisigAlias: multisig.Alias{
+			Owners: &secp256k1fx.OutputOwners{Addrs: []ids.ShortID{aliasOwner}},
+		},
+		Auth: &secp256k1fx.Input{},
+	})
+	voteTx := newTx(&txs.AddVoteTx{
+		BaseTx:       txs.BaseTx{BaseTx: avax.BaseTx{}},
+		VoterAddress: voterAddr,
+		VoterAuth:    &secp256k1fx.Input{},
+	})
+
+	blk, err := blocks.NewBanffStandardBlock(time.Unix(0, 0), ids.GenerateTestID(), 1, []*txs.Tx{depositTx, multisigAliasTx, voteTx})
+	require.NoError(err)
+
+	addrTxs, err := (&platformAddressTxsParser{}).parseBlock(blk.Bytes())
+	require.NoError(err)
+	outUTXOID := avax.UTXOID{TxID: depositTx.ID(), OutputIndex: 0}
+	lockedOutUTXOID := avax.UTXOID{TxID: depositTx.ID(), OutputIndex: 1}
+	require.Equal([]*addressTx{
+		{
+			txID:     depositTx.ID(),
+			txType:   "DepositTx",
+			assetIDs: set.Set[ids.ID]{assetID: struct{}{}},
+			addrs:    set.Set[ids.ShortID]{outAddr: struct{}{}, lockedOutAddr: struct{}{}, ownerAddr: struct{}{}},
+			inputIDs: []ids.ID{in.InputID()},
+			producedAddrs: map[ids.ID][]ids.ShortID{
+				outUTXOID.InputID():       {outAddr},
+				lockedOutUTXOID.InputID(): {lockedOutAddr},
+			},
+		},
+		{
+			txID:          multisigAliasTx.ID(),
+			txType:        "MultisigAliasTx",
+			assetIDs:      set.Set[ids.ID]{},
+			addrs:         set.Set[ids.ShortID]{multisig.ComputeAliasID(multisigAliasTx.ID()): struct{}{}, aliasOwner: struct{}{}},
+			inputIDs:      []ids.ID{},
+			producedAddrs: map[ids.ID][]ids.ShortID{},
+		},
+		{
+			txID:          voteTx.ID(),
+			txType:        "AddVoteTx",
+			assetIDs:      set.Set[ids.ID]{},
+			addrs:         set.Set[ids.ShortID]{voterAddr: struct{}{}},
+			inputIDs:      []ids.ID{},
+			producedAddrs: map[ids.ID][]ids.ShortID{},
+		},
+	}, addrTxs)
+}
+
+type testUTXOGetter map[ids.ID]*avax.UTXO
+
+func (g testUTXOGetter) GetUTXO(utxoID ids.ID) (*avax.UTXO, error) {
+	utxo, ok := g[utxoID]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return utxo, nil
+}
+
+func TestAddressIndexInputOwners(t *testing.T) {
+	require := require.New(t)
+
+	codec := codec.NewDefaultManager()
+	require.NoError(codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	db := memdb.New()
+	ctx := snow.DefaultConsensusContextTest()
+	sourceChainID := ids.GenerateTestID()
+	sharedMemory := atomic.NewMemory(prefixdb.New([]byte{0}, db))
+	ctx.SharedMemory = sharedMemory.NewSharedMemory(ctx.ChainID)
+
+	newUTXO := func(addr ids.ShortID) *avax.UTXO {
+		return &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Out:    &secp256k1fx.TransferOutput{OutputOwners: secp256k1fx.OutputOwners{Addrs: []ids.ShortID{addr}}},
+		}
+	}
+	var (
+		stateOwner    = ids.GenerateTestShortID()
+		importedOwner = ids.GenerateTestShortID()
+		producedOwner = ids.GenerateTestShortID()
+		stateUTXO     = newUTXO(stateOwner)
+		importedUTXO  = newUTXO(importedOwner)
+		producedUTXO  = newUTXO(producedOwner)
+	)
+	importedUTXOBytes, err := txs.Codec.Marshal(txs.Version, importedUTXO)
+	require.NoError(err)
+	importedUTXOID := importedUTXO.InputID()
+	require.NoError(sharedMemory.NewSharedMemory(sourceChainID).Apply(map[ids.ID]*atomic.Requests{
+		ctx.ChainID: {PutRequests: []*atomic.Element{{Key: importedUTXOID[:], Value: importedUTXOBytes}}},
+	}))
+
+	// The first tx consumes a UTXO of the chain state and imports a UTXO. The
+	// second tx consumes a UTXO produced by the first tx of the same container
+	// and a UTXO, that isn't known.
+	tx1 := &addressTx{
+		txID:             ids.GenerateTestID(),
+		addrs:            set.Set[ids.ShortID]{},
+		inputIDs:         []ids.ID{stateUTXO.InputID()},
+		sourceChain:      sourceChainID,
+		importedInputIDs: []ids.ID{importedUTXOID},
+		producedAddrs:    map[ids.ID][]ids.ShortID{producedUTXO.InputID(): {producedOwner}},
+	}
+	tx2 := &addressTx{
+		txID:     ids.GenerateTestID(),
+		addrs:    set.Set[ids.ShortID]{},
+		inputIDs: []ids.ID{producedUTXO.InputID(), ids.GenerateTestID()},
+	}
+	parse := func([]byte) ([]*addressTx, error) {
+		return []*addressTx{tx1, tx2}, nil
+	}
+
+	containerIndex, err := newIndex(prefixdb.New([]byte{blockPrefix}, db), logging.NoLog{}, codec, mockable.Clock{})
+	require.NoError(err)
+	addrIndex := newAddressIndex(
+		prefixdb.New([]byte{addressPrefix}, db),
+		logging.NoLog{},
+		codec,
+		&platformAddressTxsParser{},
+		testUTXOGetter{stateUTXO.InputID(): stateUTXO},
+	)
+	addrIndex.addSource(blockPrefix, containerIndex, parse)
+
+	containerID := ids.GenerateTestID()
+	require.NoError(addrIndex.putInputOwners(ctx, containerID[:], parse))
+	require.NoError(containerIndex.Accept(ctx, containerID, containerID[:]))
+	require.NoError(addrIndex.indexSource(addrIndex.sources[0]))
+
+	readAllTxs := func(addr ids.ShortID) []ids.ID {
+		txIDs, _, err := addrIndex.read(addr, ids.Empty, "", 0, MaxFetchedByRange)
+		require.NoError(err)
+		return txIDs
+	}
+	require.Equal([]ids.ID{tx1.txID}, readAllTxs(stateOwner))
+	require.Equal([]ids.ID{tx1.txID}, readAllTxs(importedOwner))
+	require.Equal([]ids.ID{tx2.txID}, readAllTxs(producedOwner))
+
+	// The looked up owners are dropped, once the txs were indexed.
+	has, err := addrIndex.acceptedInputOwnersDB.Has(tx1.txID[:])
+	require.NoError(err)
+	require.False(has)
+	require.NoError(addrIndex.Close())
+}
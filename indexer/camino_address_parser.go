@@ -0,0 +1,260 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"reflect"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/avm/fxs"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/multisig"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/platformvm/locked"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	xchainblocks "github.com/ava-labs/avalanchego/vms/avm/blocks"
+	xchaintxs "github.com/ava-labs/avalanchego/vms/avm/txs"
+	pchainblocks "github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	pchaintxs "github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	proposerblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+var (
+	_ addressTxsParser = (*platformAddressTxsParser)(nil)
+	_ addressTxsParser = (*avmAddressTxsParser)(nil)
+)
+
+// newAddressTxsParser returns the parser of the txs of the chain [ctx]. Returns
+// nil, if the txs of the chain can't be indexed by address.
+func newAddressTxsParser(ctx *snow.ConsensusContext) (addressTxsParser, error) {
+	switch ctx.ChainID {
+	case constants.PlatformChainID:
+		return &platformAddressTxsParser{}, nil
+	case ctx.XChainID:
+		parser, err := xchainblocks.NewParser([]fxs.Fx{
+			&secp256k1fx.Fx{},
+			&nftfx.Fx{},
+			&propertyfx.Fx{},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &avmAddressTxsParser{parser: parser}, nil
+	default:
+		return nil, nil
+	}
+}
+
+type platformAddressTxsParser struct{}
+
+func (*platformAddressTxsParser) parseBlock(blkBytes []byte) ([]*addressTx, error) {
+	blk, err := parseInnerBlock(blkBytes, func(blkBytes []byte) (pchainblocks.Block, error) {
+		return pchainblocks.Parse(pchainblocks.Codec, blkBytes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	blkTxs := blk.Txs()
+	addrTxs := make([]*addressTx, len(blkTxs))
+	for i, tx := range blkTxs {
+		addrTxs[i] = newPlatformAddressTx(tx)
+	}
+	return addrTxs, nil
+}
+
+func (*platformAddressTxsParser) parseTx(txBytes []byte) ([]*addressTx, error) {
+	tx, err := pchaintxs.Parse(pchaintxs.Codec, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	return []*addressTx{newPlatformAddressTx(tx)}, nil
+}
+
+func (*platformAddressTxsParser) parseUTXO(utxoBytes []byte) (*avax.UTXO, error) {
+	utxo := &avax.UTXO{}
+	if _, err := pchaintxs.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
+
+// newPlatformAddressTx returns [tx] with the addresses of its outputs and the
+// addresses, that it assigns a role to, like reward owners, multisig alias
+// owners, proposers and voters. The owners of the UTXOs it consumes are looked
+// up by the address index, when the tx is accepted.
+func newPlatformAddressTx(tx *pchaintxs.Tx) *addressTx {
+	txID := tx.ID()
+	addrTx := newAddressTx(txID, tx.Unsigned)
+	addrTx.addOutputs(tx.Unsigned.Outputs())
+	addrTx.inputIDs = tx.Unsigned.InputIDs().List()
+	for i, out := range tx.Unsigned.Outputs() {
+		utxoID := avax.UTXOID{TxID: txID, OutputIndex: uint32(i)}
+		addrTx.producedAddrs[utxoID.InputID()] = outputAddresses(out.Out)
+	}
+
+	switch utx := tx.Unsigned.(type) {
+	case *pchaintxs.ImportTx:
+		addrTx.inputIDs = utx.BaseTx.InputIDs().List()
+		addrTx.sourceChain = utx.SourceChain
+		addrTx.importedInputIDs = utx.InputUTXOs().List()
+	case *pchaintxs.ExportTx:
+		addrTx.addOutputs(utx.ExportedOutputs)
+	case *pchaintxs.CreateSubnetTx:
+		addrTx.addOwner(utx.Owner)
+	case *pchaintxs.DepositTx:
+		addrTx.addOwner(utx.RewardsOwner)
+		addrTx.addAddress(utx.DepositCreatorAddress)
+	case *pchaintxs.AddProposalTx:
+		addrTx.addAddress(utx.ProposerAddress)
+	case *pchaintxs.AddVoteTx:
+		addrTx.addAddress(utx.VoterAddress)
+	case *pchaintxs.AddressStateTx:
+		addrTx.addAddress(utx.Address)
+		addrTx.addAddress(utx.Executor)
+	case *pchaintxs.RegisterNodeTx:
+		addrTx.addAddress(utx.NodeOwnerAddress)
+	case *pchaintxs.MultisigAliasTx:
+		aliasID := utx.MultisigAlias.ID
+		if aliasID == ids.ShortEmpty {
+			aliasID = multisig.ComputeAliasID(txID)
+		}
+		addrTx.addAddress(aliasID)
+		addrTx.addOwner(utx.MultisigAlias.Owners)
+	case pchaintxs.ValidatorTx:
+		addrTx.addOutputs(utx.Stake())
+		addrTx.addOwner(utx.ValidationRewardsOwner())
+		addrTx.addOwner(utx.DelegationRewardsOwner())
+	case pchaintxs.DelegatorTx:
+		addrTx.addOutputs(utx.Stake())
+		addrTx.addOwner(utx.RewardsOwner())
+	}
+	return addrTx
+}
+
+type avmAddressTxsParser struct {
+	parser xchainblocks.Parser
+}
+
+func (p *avmAddressTxsParser) parseBlock(blkBytes []byte) ([]*addressTx, error) {
+	blk, err := parseInnerBlock(blkBytes, p.parser.ParseBlock)
+	if err != nil {
+		return nil, err
+	}
+	blkTxs := blk.Txs()
+	addrTxs := make([]*addressTx, len(blkTxs))
+	for i, tx := range blkTxs {
+		addrTxs[i] = newAVMAddressTx(tx)
+	}
+	return addrTxs, nil
+}
+
+func (p *avmAddressTxsParser) parseTx(txBytes []byte) ([]*addressTx, error) {
+	tx, err := p.parser.ParseTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	return []*addressTx{newAVMAddressTx(tx)}, nil
+}
+
+func (p *avmAddressTxsParser) parseUTXO(utxoBytes []byte) (*avax.UTXO, error) {
+	utxo := &avax.UTXO{}
+	if _, err := p.parser.Codec().Unmarshal(utxoBytes, utxo); err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
+
+// newAVMAddressTx returns [tx] with the addresses of the UTXOs it produces and
+// exports. The owners of the UTXOs it consumes are looked up by the address
+// index, when the tx is accepted.
+func newAVMAddressTx(tx *xchaintxs.Tx) *addressTx {
+	addrTx := newAddressTx(tx.ID(), tx.Unsigned)
+	addrTx.assetIDs.Union(tx.Unsigned.AssetIDs())
+	addrTx.inputIDs = tx.Unsigned.InputIDs().List()
+	for _, utxo := range tx.UTXOs() {
+		addrTx.addOutput(utxo.Out)
+		addrTx.producedAddrs[utxo.InputID()] = outputAddresses(utxo.Out)
+	}
+
+	switch utx := tx.Unsigned.(type) {
+	case *xchaintxs.ImportTx:
+		addrTx.inputIDs = utx.BaseTx.InputIDs().List()
+		addrTx.sourceChain = utx.SourceChain
+		for _, in := range utx.ImportedIns {
+			addrTx.importedInputIDs = append(addrTx.importedInputIDs, in.InputID())
+		}
+	case *xchaintxs.ExportTx:
+		addrTx.addOutputs(utx.ExportedOuts)
+	}
+	return addrTx
+}
+
+// parseInnerBlock parses the block, that is wrapped by the proposervm block
+// [blkBytes]. Blocks, that were accepted before the proposervm was activated,
+// aren't wrapped and are parsed as they are.
+func parseInnerBlock[T any](blkBytes []byte, parse func([]byte) (T, error)) (T, error) {
+	if proposerBlk, err := proposerblock.Parse(blkBytes); err == nil {
+		if blk, err := parse(proposerBlk.Block()); err == nil {
+			return blk, nil
+		}
+	}
+	return parse(blkBytes)
+}
+
+func newAddressTx(txID ids.ID, utx interface{}) *addressTx {
+	return &addressTx{
+		txID:          txID,
+		txType:        reflect.TypeOf(utx).Elem().Name(),
+		assetIDs:      set.Set[ids.ID]{},
+		addrs:         set.Set[ids.ShortID]{},
+		producedAddrs: map[ids.ID][]ids.ShortID{},
+	}
+}
+
+func (a *addressTx) addOutputs(outs []*avax.TransferableOutput) {
+	for _, out := range outs {
+		a.assetIDs.Add(out.AssetID())
+		a.addOutput(out.Out)
+	}
+}
+
+func (a *addressTx) addOutput(out interface{}) {
+	a.addrs.Add(outputAddresses(out)...)
+}
+
+func (a *addressTx) addOwner(owner interface{}) {
+	if owners, ok := owner.(*secp256k1fx.OutputOwners); ok {
+		a.addrs.Add(owners.Addrs...)
+	}
+}
+
+// outputAddresses returns the addresses, that own [out]. Returns nil, if [out]
+// isn't owned by addresses.
+func outputAddresses(out interface{}) []ids.ShortID {
+	if lockedOut, ok := out.(*locked.Out); ok {
+		out = lockedOut.TransferableOut
+	}
+	addressable, ok := out.(avax.Addressable)
+	if !ok {
+		return nil
+	}
+	var addrs []ids.ShortID
+	for _, addrBytes := range addressable.Addresses() {
+		if addr, err := ids.ToShortID(addrBytes); err == nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func (a *addressTx) addAddress(addr ids.ShortID) {
+	if addr != ids.ShortEmpty {
+		a.addrs.Add(addr)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+type addressService struct {
+	index          *addressIndex
+	addressManager avax.AddressManager
+}
+
+type GetAddressTxsArgs struct {
+	Address string `json:"address"`
+	// Only txs, that use this asset, are returned, if not empty
+	AssetID string `json:"assetID"`
+	// Only txs of this type (e.g. "BaseTx" or "DepositTx") are returned, if
+	// not empty
+	TxType string `json:"txType"`
+	// Number of txs of the address to skip
+	Cursor json.Uint64 `json:"cursor"`
+	// Maximum number of tx IDs to return
+	PageSize json.Uint64 `json:"pageSize"`
+}
+
+type GetAddressTxsResponse struct {
+	TxIDs []ids.ID `json:"txIDs"`
+	// Cursor to continue reading from. If it didn't advance, all indexed
+	// txs of the address were read.
+	Cursor json.Uint64 `json:"cursor"`
+}
+
+// GetAddressTxs returns the IDs of the accepted txs, that touched the address,
+// in the order they were indexed.
+func (s *addressService) GetAddressTxs(_ *http.Request, args *GetAddressTxsArgs, reply *GetAddressTxsResponse) error {
+	pageSize := uint64(args.PageSize)
+	if pageSize > MaxFetchedByRange {
+		return fmt.Errorf("pageSize > maximum allowed (%d)", MaxFetchedByRange)
+	} else if pageSize == 0 {
+		pageSize = MaxFetchedByRange
+	}
+
+	addr, err := avax.ParseServiceAddress(s.addressManager, args.Address)
+	if err != nil {
+		return err
+	}
+
+	assetID := ids.Empty
+	if args.AssetID != "" {
+		assetID, err = ids.FromString(args.AssetID)
+		if err != nil {
+			return fmt.Errorf("couldn't parse assetID %q: %w", args.AssetID, err)
+		}
+	}
+
+	txIDs, cursor, err := s.index.read(addr, assetID, args.TxType, uint64(args.Cursor), pageSize)
+	if err != nil {
+		return err
+	}
+	reply.TxIDs = txIDs
+	reply.Cursor = json.Uint64(cursor)
+	return nil
+}
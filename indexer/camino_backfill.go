@@ -0,0 +1,473 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	// Maximum number of blocks, that are backfilled while the chain's lock is
+	// held
+	backfillBatchSize = 256
+	// Time to wait before retrying a failed backfill
+	backfillRetryDelay = 10 * time.Second
+)
+
+var (
+	_ indexBackfiller = (*backfiller)(nil)
+	_ snow.Acceptor   = (*backfiller)(nil)
+	_ backfillableVM  = (*linearizedVM)(nil)
+
+	// Returned while the chain of a backfill isn't linearized yet. The
+	// backfill waits for the linearization, which isn't a failure.
+	errNotLinearized = errors.New("chain isn't linearized")
+)
+
+// indexBackfiller adds the containers of a chain, that were accepted while
+// the chain wasn't indexed, to indices of the chain.
+type indexBackfiller interface {
+	// acceptor returns the acceptor of the containers accepted by consensus,
+	// which are passed to [index], once the backfill is done. [prefixEnd]
+	// identifies the index.
+	acceptor(prefixEnd byte, index Index) snow.Acceptor
+	getName() string
+	getProgress() BackfillProgress
+	start()
+	Close()
+}
+
+// backfillableVM is a VM, whose accepted blocks can be read by their height.
+type backfillableVM interface {
+	block.HeightIndexedChainVM
+	block.Getter
+
+	LastAccepted(context.Context) (ids.ID, error)
+}
+
+// linearizedVM is the backfillableVM of a DAG chain, whose blocks can be read,
+// once the chain was linearized.
+type linearizedVM struct {
+	vm chains.DAGChainVM
+}
+
+func (vm *linearizedVM) linearVM() (backfillableVM, error) {
+	linearVM, linearized := vm.vm.LinearVM()
+	if !linearized {
+		return nil, errNotLinearized
+	}
+	backfillVM, ok := linearVM.(backfillableVM)
+	if !ok {
+		return nil, block.ErrHeightIndexedVMNotImplemented
+	}
+	return backfillVM, nil
+}
+
+func (vm *linearizedVM) VerifyHeightIndex(ctx context.Context) error {
+	linearVM, err := vm.linearVM()
+	if err != nil {
+		return err
+	}
+	return linearVM.VerifyHeightIndex(ctx)
+}
+
+func (vm *linearizedVM) GetBlockIDAtHeight(ctx context.Context, height uint64) (ids.ID, error) {
+	linearVM, err := vm.linearVM()
+	if err != nil {
+		return ids.Empty, err
+	}
+	return linearVM.GetBlockIDAtHeight(ctx, height)
+}
+
+func (vm *linearizedVM) GetBlock(ctx context.Context, blkID ids.ID) (snowman.Block, error) {
+	linearVM, err := vm.linearVM()
+	if err != nil {
+		return nil, err
+	}
+	return linearVM.GetBlock(ctx, blkID)
+}
+
+func (vm *linearizedVM) LastAccepted(ctx context.Context) (ids.ID, error) {
+	linearVM, err := vm.linearVM()
+	if err != nil {
+		return ids.Empty, err
+	}
+	return linearVM.LastAccepted(ctx)
+}
+
+// BackfillProgress is the progress of adding the containers of a chain, that
+// were accepted while the chain wasn't indexed, to its indices. For the vertex
+// and tx indices of DAG chains, heights are the positions of the vertices
+// ordered by their height.
+type BackfillProgress struct {
+	Backfilling bool `json:"backfilling"`
+	// Height of the first backfilled block
+	StartHeight json.Uint64 `json:"startHeight"`
+	// Height of the next block to backfill
+	NextHeight json.Uint64 `json:"nextHeight"`
+	// Height of the last accepted block of the chain, when progress was made
+	// the last time
+	LastAcceptedHeight json.Uint64 `json:"lastAcceptedHeight"`
+	// Number of blocks or vertices, that couldn't be backfilled, because the
+	// chain doesn't store them (e.g. because they were pruned or state synced)
+	SkippedContainers json.Uint64 `json:"skippedContainers"`
+	// Error of the last backfill attempt, if it failed
+	Error string `json:"error,omitempty"`
+}
+
+// backfillState is the persisted state of an ongoing backfill.
+type backfillState struct {
+	StartHeight   uint64 `serialize:"true"`
+	NextHeight    uint64 `serialize:"true"`
+	SkippedBlocks uint64 `serialize:"true"`
+}
+
+// backfiller adds the blocks of a chain, that were accepted while the chain
+// wasn't indexed, to the block index of the chain. The blocks are read from
+// the VM by their height in the background. Until the backfiller caught up
+// with the last accepted block of the VM, blocks accepted by consensus aren't
+// passed to the index, because they will be backfilled as well. That keeps
+// the index in the order of acceptance.
+type backfiller struct {
+	backfillWorker
+
+	codec    codec.Manager
+	chainCtx *snow.ConsensusContext
+	vm       backfillableVM
+	// Set when the index of the chain is created
+	index Index
+	// Persists the state of an ongoing backfill
+	db  database.Database
+	key []byte
+	// Called after blocks were backfilled, if not nil
+	onAccept func()
+	// Marks the index of the chain as (in)complete
+	setIncomplete func(bool) error
+
+	loaded bool
+	state  backfillState
+}
+
+func newBackfiller(
+	name string,
+	codec codec.Manager,
+	log logging.Logger,
+	chainCtx *snow.ConsensusContext,
+	vm backfillableVM,
+	db database.Database,
+	key []byte,
+	onAccept func(),
+	setIncomplete func(bool) error,
+) *backfiller {
+	return &backfiller{
+		backfillWorker: newBackfillWorker(name, log),
+		codec:          codec,
+		chainCtx:       chainCtx,
+		vm:             vm,
+		db:             db,
+		key:            key,
+		onAccept:       onAccept,
+		setIncomplete:  setIncomplete,
+	}
+}
+
+func (b *backfiller) acceptor(_ byte, index Index) snow.Acceptor {
+	b.index = index
+	return b
+}
+
+// Accept passes [containerID] to the index, once the backfill is done.
+// Assumes [ctx.Lock] is held.
+func (b *backfiller) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	if !b.isDone() {
+		// The container will be backfilled.
+		return nil
+	}
+	return b.index.Accept(ctx, containerID, container)
+}
+
+// start backfills the blocks in the background, until the backfill is done or
+// the backfiller is closed. Failed attempts are retried.
+func (b *backfiller) start() {
+	b.run(b.backfillBatch)
+}
+
+// backfillBatch backfills up to [backfillBatchSize] blocks and returns true,
+// if the backfill caught up with the last accepted block of the VM.
+func (b *backfiller) backfillBatch() (bool, error) {
+	b.chainCtx.Lock.Lock()
+	defer b.chainCtx.Lock.Unlock()
+
+	if err := b.vm.VerifyHeightIndex(b.ctx); err != nil {
+		return false, err
+	}
+	lastAcceptedID, err := b.vm.LastAccepted(b.ctx)
+	if err != nil {
+		return false, err
+	}
+	lastAccepted, err := b.vm.GetBlock(b.ctx, lastAcceptedID)
+	if err != nil {
+		return false, err
+	}
+	lastAcceptedHeight := lastAccepted.Height()
+
+	if !b.loaded {
+		if err := b.loadState(lastAcceptedHeight); err != nil {
+			return false, err
+		}
+	}
+
+	state := b.state
+	numBackfilled := 0
+	for i := 0; i < backfillBatchSize && state.NextHeight <= lastAcceptedHeight; i++ {
+		blkID, err := b.vm.GetBlockIDAtHeight(b.ctx, state.NextHeight)
+		if err == database.ErrNotFound {
+			state.SkippedBlocks++
+			state.NextHeight++
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		blk, err := b.vm.GetBlock(b.ctx, blkID)
+		if err == database.ErrNotFound {
+			state.SkippedBlocks++
+			state.NextHeight++
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if err := b.index.Accept(b.chainCtx, blkID, blk.Bytes()); err != nil {
+			return false, err
+		}
+		state.NextHeight++
+		numBackfilled++
+	}
+
+	done := state.NextHeight > lastAcceptedHeight
+	if done {
+		if err := b.db.Delete(b.key); err != nil {
+			return false, err
+		}
+		switch {
+		case state.SkippedBlocks > 0:
+			// The skipped blocks can't be added to the index later, without
+			// breaking its order.
+			if err := b.setIncomplete(true); err != nil {
+				return false, err
+			}
+			b.log.Warn("index is incomplete, because blocks couldn't be backfilled",
+				zap.String("chainName", b.name),
+				zap.Uint64("skippedBlocks", state.SkippedBlocks),
+			)
+		case state.StartHeight <= 1:
+			// All blocks after genesis are indexed.
+			if err := b.setIncomplete(false); err != nil {
+				return false, err
+			}
+		}
+		if state.NextHeight > state.StartHeight {
+			b.log.Info("finished backfilling index",
+				zap.String("chainName", b.name),
+				zap.Uint64("startHeight", state.StartHeight),
+				zap.Uint64("lastAcceptedHeight", lastAcceptedHeight),
+				zap.Uint64("skippedBlocks", state.SkippedBlocks),
+			)
+		}
+	} else {
+		stateBytes, err := b.codec.Marshal(codecVersion, &state)
+		if err != nil {
+			return false, err
+		}
+		if err := b.db.Put(b.key, stateBytes); err != nil {
+			return false, err
+		}
+	}
+
+	b.lock.Lock()
+	b.state = state
+	b.done = done
+	b.progress = BackfillProgress{
+		Backfilling:        !done,
+		StartHeight:        json.Uint64(state.StartHeight),
+		NextHeight:         json.Uint64(state.NextHeight),
+		LastAcceptedHeight: json.Uint64(lastAcceptedHeight),
+		SkippedContainers:  json.Uint64(state.SkippedBlocks),
+	}
+	b.lock.Unlock()
+
+	if numBackfilled > 0 && b.onAccept != nil {
+		b.onAccept()
+	}
+	return done, nil
+}
+
+// loadState loads the state of the backfill, that was interrupted by a
+// restart. If there is none, the backfill starts after the last indexed
+// block.
+// Assumes [ctx.Lock] is held.
+func (b *backfiller) loadState(lastAcceptedHeight uint64) error {
+	stateBytes, err := b.db.Get(b.key)
+	switch {
+	case err == nil:
+		if _, err := b.codec.Unmarshal(stateBytes, &b.state); err != nil {
+			return err
+		}
+		b.log.Info("resuming index backfill",
+			zap.String("chainName", b.name),
+			zap.Uint64("nextHeight", b.state.NextHeight),
+			zap.Uint64("lastAcceptedHeight", lastAcceptedHeight),
+		)
+	case err != database.ErrNotFound:
+		return err
+	default:
+		startHeight, err := b.getStartHeight(lastAcceptedHeight)
+		if err != nil {
+			return err
+		}
+		b.state = backfillState{
+			StartHeight: startHeight,
+			NextHeight:  startHeight,
+		}
+		if startHeight <= lastAcceptedHeight {
+			b.log.Info("backfilling index",
+				zap.String("chainName", b.name),
+				zap.Uint64("startHeight", startHeight),
+				zap.Uint64("lastAcceptedHeight", lastAcceptedHeight),
+			)
+		}
+	}
+	b.loaded = true
+	return nil
+}
+
+// getStartHeight returns the height of the first block, that isn't indexed.
+// Assumes [ctx.Lock] is held.
+func (b *backfiller) getStartHeight(lastAcceptedHeight uint64) (uint64, error) {
+	lastIndexed, err := b.index.GetLastAccepted()
+	if err == errNoneAccepted {
+		// The genesis block isn't indexed.
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	lastIndexedBlk, err := b.vm.GetBlock(b.ctx, lastIndexed.ID)
+	if err == database.ErrNotFound {
+		// The index accepts blocks before the VM does. So, the last indexed
+		// block may not be accepted by the VM yet, if the node stopped in
+		// between. It will be accepted again.
+		return lastAcceptedHeight + 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return lastIndexedBlk.Height() + 1, nil
+}
+
+// backfillWorker runs a backfill in the background and tracks its progress.
+type backfillWorker struct {
+	name string
+	log  logging.Logger
+
+	lock     sync.RWMutex
+	done     bool
+	progress BackfillProgress
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newBackfillWorker(name string, log logging.Logger) backfillWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return backfillWorker{
+		name:     name,
+		log:      log,
+		progress: BackfillProgress{Backfilling: true},
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// run calls [backfillBatch] in the background, until it returns true or the
+// worker is closed. Failed attempts and attempts before the chain was
+// linearized are retried after [backfillRetryDelay].
+func (w *backfillWorker) run(backfillBatch func() (bool, error)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		for {
+			done, err := backfillBatch()
+			w.lock.Lock()
+			w.progress.Error = ""
+			if err != nil && err != errNotLinearized {
+				w.progress.Error = err.Error()
+			}
+			w.lock.Unlock()
+			if done {
+				return
+			}
+
+			var delay time.Duration
+			switch {
+			case err == errNotLinearized:
+				delay = backfillRetryDelay
+			case err != nil:
+				w.log.Warn("failed to backfill index",
+					zap.String("chainName", w.name),
+					zap.Duration("retryDelay", backfillRetryDelay),
+					zap.Error(err),
+				)
+				delay = backfillRetryDelay
+			}
+
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+		}
+	}()
+}
+
+func (w *backfillWorker) Close() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *backfillWorker) getName() string {
+	return w.name
+}
+
+func (w *backfillWorker) isDone() bool {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.done
+}
+
+func (w *backfillWorker) getProgress() BackfillProgress {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.progress
+}
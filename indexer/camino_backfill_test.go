@@ -0,0 +1,273 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+type testBackfillableVM struct {
+	*block.TestVM
+	*block.TestHeightIndexedVM
+}
+
+// newTestBackfillableVM returns a VM, whose chain consists of the genesis
+// block and [numBlocks] accepted blocks. The blocks at the [missingHeights]
+// aren't stored.
+func newTestBackfillableVM(numBlocks int, missingHeights ...uint64) (*testBackfillableVM, []*snowman.TestBlock) {
+	blks := make([]*snowman.TestBlock, numBlocks+1)
+	for height := range blks {
+		blkID := ids.GenerateTestID()
+		blks[height] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     blkID,
+				StatusV: choices.Accepted,
+			},
+			HeightV: uint64(height),
+			BytesV:  blkID[:],
+		}
+	}
+	isMissing := func(height uint64) bool {
+		for _, missingHeight := range missingHeights {
+			if height == missingHeight {
+				return true
+			}
+		}
+		return false
+	}
+
+	vm := &testBackfillableVM{
+		TestVM:              &block.TestVM{},
+		TestHeightIndexedVM: &block.TestHeightIndexedVM{},
+	}
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return blks[len(blks)-1].ID(), nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID() == blkID && !isMissing(blk.Height()) {
+				return blk, nil
+			}
+		}
+		return nil, database.ErrNotFound
+	}
+	vm.VerifyHeightIndexF = func(context.Context) error {
+		return nil
+	}
+	vm.GetBlockIDAtHeightF = func(_ context.Context, height uint64) (ids.ID, error) {
+		if height >= uint64(len(blks)) || isMissing(height) {
+			return ids.Empty, database.ErrNotFound
+		}
+		return blks[height].ID(), nil
+	}
+	return vm, blks
+}
+
+func TestBackfiller(t *testing.T) {
+	codec := codec.NewDefaultManager()
+	require.NoError(t, codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	ctx := snow.DefaultConsensusContextTest()
+
+	type test struct {
+		name string
+		// Heights of the blocks, that are in the index before the backfill
+		indexedHeights []uint64
+		// Persisted state of an interrupted backfill
+		state              *backfillState
+		missingHeights     []uint64
+		expectedHeights    []uint64
+		expectedIncomplete *bool
+	}
+	incomplete, complete := true, false
+	tests := []test{
+		{
+			name:               "empty index",
+			expectedHeights:    []uint64{1, 2, 3, 4, 5},
+			expectedIncomplete: &complete,
+		},
+		{
+			name:            "gap after indexed blocks",
+			indexedHeights:  []uint64{3},
+			expectedHeights: []uint64{3, 4, 5},
+		},
+		{
+			name:            "interrupted backfill",
+			indexedHeights:  []uint64{1, 2},
+			state:           &backfillState{StartHeight: 1, NextHeight: 3},
+			expectedHeights: []uint64{1, 2, 3, 4, 5},
+			// The state of the backfill isn't lost.
+			expectedIncomplete: &complete,
+		},
+		{
+			name:               "missing blocks",
+			missingHeights:     []uint64{2},
+			expectedHeights:    []uint64{1, 3, 4, 5},
+			expectedIncomplete: &incomplete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vm, blks := newTestBackfillableVM(5, tt.missingHeights...)
+			db := memdb.New()
+			index, err := newIndex(prefixdb.New([]byte{blockPrefix}, db), logging.NoLog{}, codec, mockable.Clock{})
+			require.NoError(err)
+			for _, height := range tt.indexedHeights {
+				require.NoError(index.Accept(ctx, blks[height].ID(), blks[height].Bytes()))
+			}
+			key := []byte{backfillPrefix}
+			if tt.state != nil {
+				stateBytes, err := codec.Marshal(codecVersion, tt.state)
+				require.NoError(err)
+				require.NoError(db.Put(key, stateBytes))
+			}
+
+			var gotIncomplete *bool
+			bf := newBackfiller("chain", codec, logging.NoLog{}, ctx, vm, db, key, nil, func(incomplete bool) error {
+				gotIncomplete = &incomplete
+				return nil
+			})
+			bf.index = index
+
+			// Blocks accepted by consensus are backfilled, until the
+			// backfill is done.
+			require.NoError(bf.Accept(ctx, blks[5].ID(), blks[5].Bytes()))
+			lastAccepted, err := index.GetLastAccepted()
+			if len(tt.indexedHeights) == 0 {
+				require.ErrorIs(err, errNoneAccepted)
+			} else {
+				require.NoError(err)
+				require.Equal(blks[tt.indexedHeights[len(tt.indexedHeights)-1]].ID(), lastAccepted.ID)
+			}
+
+			// Failed attempts don't make progress.
+			vm.VerifyHeightIndexF = func(context.Context) error {
+				return block.ErrIndexIncomplete
+			}
+			done, err := bf.backfillBatch()
+			require.ErrorIs(err, block.ErrIndexIncomplete)
+			require.False(done)
+			require.True(bf.getProgress().Backfilling)
+
+			vm.VerifyHeightIndexF = func(context.Context) error {
+				return nil
+			}
+			done, err = bf.backfillBatch()
+			require.NoError(err)
+			require.True(done)
+			require.Equal(tt.expectedIncomplete, gotIncomplete)
+			has, err := db.Has(key)
+			require.NoError(err)
+			require.False(has)
+
+			progress := bf.getProgress()
+			require.False(progress.Backfilling)
+			require.EqualValues(6, progress.NextHeight)
+			require.EqualValues(5, progress.LastAcceptedHeight)
+			require.EqualValues(len(tt.missingHeights), progress.SkippedContainers)
+
+			// Blocks accepted by consensus are indexed after the backfill.
+			blkID := ids.GenerateTestID()
+			require.NoError(bf.Accept(ctx, blkID, blkID[:]))
+
+			containers, err := index.GetContainerRange(0, MaxFetchedByRange)
+			require.NoError(err)
+			require.Len(containers, len(tt.expectedHeights)+1)
+			for i, height := range tt.expectedHeights {
+				require.Equal(blks[height].ID(), containers[i].ID)
+			}
+			require.Equal(blkID, containers[len(containers)-1].ID)
+		})
+	}
+}
+
+func TestIndexerBackfill(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	config := Config{
+		IndexingEnabled:      false,
+		AllowIncompleteIndex: false,
+		Log:                  logging.NoLog{},
+		DB:                   versiondb.New(baseDB),
+		BlockAcceptorGroup:   snow.NewAcceptorGroup(logging.NoLog{}),
+		TxAcceptorGroup:      snow.NewAcceptorGroup(logging.NoLog{}),
+		VertexAcceptorGroup:  snow.NewAcceptorGroup(logging.NoLog{}),
+		APIServer:            &apiServerMock{},
+		ShutdownF:            func() {},
+	}
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	vm, blks := newTestBackfillableVM(3)
+
+	// The node runs without indexing, which doesn't mark the index as
+	// incomplete, because it can be backfilled.
+	idxrIntf, err := NewIndexer(config)
+	require.NoError(err)
+	idxrIntf.RegisterChain("chain", ctx, vm)
+	require.NoError(idxrIntf.Close())
+
+	// Indexing is enabled and the blocks are backfilled.
+	config.DB = versiondb.New(baseDB)
+	config.IndexingEnabled = true
+	idxrIntf, err = NewIndexer(config)
+	require.NoError(err)
+	idxr, ok := idxrIntf.(*indexer)
+	require.True(ok)
+	isIncomplete, err := idxr.isIncomplete(ctx.ChainID)
+	require.NoError(err)
+	require.False(isIncomplete)
+	idxr.RegisterChain("chain", ctx, vm)
+	require.False(idxr.closed)
+	require.Len(idxr.backfillers, 1)
+
+	bf := idxr.backfillers[ctx.ChainID][0]
+	require.Eventually(func() bool {
+		return !bf.getProgress().Backfilling
+	}, 5*time.Second, 10*time.Millisecond)
+	blkIndex := idxr.blockIndices[ctx.ChainID]
+	lastAccepted, err := blkIndex.GetLastAccepted()
+	require.NoError(err)
+	require.Equal(blks[3].ID(), lastAccepted.ID)
+	lastAcceptedIndex, err := blkIndex.GetIndex(lastAccepted.ID)
+	require.NoError(err)
+	require.EqualValues(2, lastAcceptedIndex)
+
+	details, err := idxr.HealthCheck(context.Background())
+	require.NoError(err)
+	require.Equal(map[string]BackfillProgress{
+		"chain": {
+			StartHeight:        1,
+			NextHeight:         4,
+			LastAcceptedHeight: 3,
+		},
+	}, details)
+
+	// Blocks accepted by consensus are indexed after the backfill.
+	blkID := ids.GenerateTestID()
+	require.NoError(config.BlockAcceptorGroup.Accept(ctx, blkID, blkID[:]))
+	lastAccepted, err = blkIndex.GetLastAccepted()
+	require.NoError(err)
+	require.Equal(blkID, lastAccepted.ID)
+	require.NoError(idxr.Close())
+}
@@ -0,0 +1,361 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ indexBackfiller = (*dagBackfiller)(nil)
+	_ snow.Acceptor   = (*dagAcceptor)(nil)
+)
+
+// dagBackfillState is the persisted state of the backfill of a DAG chain.
+// It is kept after the backfill is done, because the DAG doesn't change
+// anymore.
+type dagBackfillState struct {
+	StartVertex     uint64 `serialize:"true"`
+	NextVertex      uint64 `serialize:"true"`
+	NumVertices     uint64 `serialize:"true"`
+	SkippedVertices uint64 `serialize:"true"`
+	Done            bool   `serialize:"true"`
+}
+
+// dagBackfiller adds the vertices of a DAG chain and their txs, that were
+// accepted while the chain wasn't indexed, to the vertex and tx indices of the
+// chain. The vertices are backfilled, once the DAG was finalized by the
+// linearization of the chain. They are backfilled in the order of their
+// height, which is an order, in which they could have been accepted, with the
+// txs of each vertex before the vertex. Until the backfill is done, vertices
+// and txs accepted by consensus aren't passed to the indices, because they
+// will be backfilled as well.
+type dagBackfiller struct {
+	backfillWorker
+
+	codec    codec.Manager
+	chainCtx *snow.ConsensusContext
+	storage  vertex.Storage
+	// Set when the indices of the chain are created
+	vtxIndex Index
+	txIndex  Index
+	// Persists the state of the backfill
+	db  database.Database
+	key []byte
+	// Called after txs were backfilled, if not nil
+	onAccept func()
+	// Marks the indices of the chain as (in)complete
+	setIncomplete func(bool) error
+
+	loaded bool
+	state  dagBackfillState
+	// IDs of the accepted vertices ordered by their height. Only loaded, if
+	// the backfill isn't done.
+	vtxIDs []ids.ID
+}
+
+func newDAGBackfiller(
+	name string,
+	codec codec.Manager,
+	log logging.Logger,
+	chainCtx *snow.ConsensusContext,
+	storage vertex.Storage,
+	db database.Database,
+	key []byte,
+	onAccept func(),
+	setIncomplete func(bool) error,
+) *dagBackfiller {
+	return &dagBackfiller{
+		backfillWorker: newBackfillWorker(name, log),
+		codec:          codec,
+		chainCtx:       chainCtx,
+		storage:        storage,
+		db:             db,
+		key:            key,
+		onAccept:       onAccept,
+		setIncomplete:  setIncomplete,
+	}
+}
+
+func (b *dagBackfiller) acceptor(prefixEnd byte, index Index) snow.Acceptor {
+	if prefixEnd == vtxPrefix {
+		b.vtxIndex = index
+	} else {
+		b.txIndex = index
+	}
+	return &dagAcceptor{backfiller: b, index: index}
+}
+
+// start backfills the vertices and txs in the background, until the backfill
+// is done or the backfiller is closed. Failed attempts are retried.
+func (b *dagBackfiller) start() {
+	b.run(b.backfillBatch)
+}
+
+// backfillBatch backfills up to [backfillBatchSize] vertices and their txs
+// and returns true, if all accepted vertices were backfilled.
+func (b *dagBackfiller) backfillBatch() (bool, error) {
+	b.chainCtx.Lock.Lock()
+	defer b.chainCtx.Lock.Unlock()
+
+	if !b.loaded {
+		if err := b.loadState(); err != nil {
+			return false, err
+		}
+	}
+
+	state := b.state
+	numBackfilled := 0
+	for ; numBackfilled < backfillBatchSize && state.NextVertex < state.NumVertices; state.NextVertex++ {
+		vtxID := b.vtxIDs[state.NextVertex]
+		vtx, err := b.storage.GetVtx(b.ctx, vtxID)
+		if err != nil {
+			return false, err
+		}
+		txs, err := vtx.Txs(b.ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, tx := range txs {
+			if err := b.txIndex.Accept(b.chainCtx, tx.ID(), tx.Bytes()); err != nil {
+				return false, err
+			}
+		}
+		if err := b.vtxIndex.Accept(b.chainCtx, vtxID, vtx.Bytes()); err != nil {
+			return false, err
+		}
+		numBackfilled++
+	}
+
+	finished := !state.Done && state.NextVertex >= state.NumVertices
+	if finished {
+		state.Done = true
+		switch {
+		case state.SkippedVertices > 0:
+			// The skipped vertices can't be added to the indices later,
+			// without breaking their order.
+			if err := b.setIncomplete(true); err != nil {
+				return false, err
+			}
+			b.log.Warn("index is incomplete, because vertices couldn't be backfilled",
+				zap.String("chainName", b.name),
+				zap.Uint64("skippedVertices", state.SkippedVertices),
+			)
+		case state.StartVertex == 0:
+			// All vertices are indexed.
+			if err := b.setIncomplete(false); err != nil {
+				return false, err
+			}
+		}
+		b.log.Info("finished backfilling index",
+			zap.String("chainName", b.name),
+			zap.Uint64("startVertex", state.StartVertex),
+			zap.Uint64("numVertices", state.NumVertices),
+			zap.Uint64("skippedVertices", state.SkippedVertices),
+		)
+		b.vtxIDs = nil
+	}
+	if numBackfilled > 0 || finished {
+		stateBytes, err := b.codec.Marshal(codecVersion, &state)
+		if err != nil {
+			return false, err
+		}
+		if err := b.db.Put(b.key, stateBytes); err != nil {
+			return false, err
+		}
+	}
+
+	lastVertex := state.NumVertices
+	if lastVertex > 0 {
+		lastVertex--
+	}
+	b.lock.Lock()
+	b.state = state
+	b.done = state.Done
+	b.progress = BackfillProgress{
+		Backfilling:        !state.Done,
+		StartHeight:        json.Uint64(state.StartVertex),
+		NextHeight:         json.Uint64(state.NextVertex),
+		LastAcceptedHeight: json.Uint64(lastVertex),
+		SkippedContainers:  json.Uint64(state.SkippedVertices),
+	}
+	b.lock.Unlock()
+
+	if numBackfilled > 0 && b.onAccept != nil {
+		b.onAccept()
+	}
+	return state.Done, nil
+}
+
+// loadState loads the state of the backfill. Unless the backfill is done, the
+// accepted vertices are loaded, which requires the DAG to be finalized.
+// Assumes [ctx.Lock] is held.
+func (b *dagBackfiller) loadState() error {
+	stateBytes, err := b.db.Get(b.key)
+	switch {
+	case err == nil:
+		if _, err := b.codec.Unmarshal(stateBytes, &b.state); err != nil {
+			return err
+		}
+		if b.state.Done {
+			b.loaded = true
+			return nil
+		}
+	case err != database.ErrNotFound:
+		return err
+	}
+	resuming := err == nil
+
+	stopVertexAccepted, err := b.storage.StopVertexAccepted(b.ctx)
+	if err != nil {
+		return err
+	}
+	if !stopVertexAccepted {
+		return errNotLinearized
+	}
+	vtxIDs, skippedVertices, err := b.getAcceptedVertices()
+	if err != nil {
+		return err
+	}
+	b.vtxIDs = vtxIDs
+
+	if resuming {
+		b.log.Info("resuming index backfill",
+			zap.String("chainName", b.name),
+			zap.Uint64("nextVertex", b.state.NextVertex),
+			zap.Uint64("numVertices", b.state.NumVertices),
+		)
+	} else {
+		startVertex, err := b.getStartVertex()
+		if err != nil {
+			return err
+		}
+		b.state = dagBackfillState{
+			StartVertex:     startVertex,
+			NextVertex:      startVertex,
+			NumVertices:     uint64(len(vtxIDs)),
+			SkippedVertices: skippedVertices,
+		}
+		b.log.Info("backfilling index",
+			zap.String("chainName", b.name),
+			zap.Uint64("startVertex", startVertex),
+			zap.Int("numVertices", len(vtxIDs)),
+		)
+	}
+	b.loaded = true
+	return nil
+}
+
+// getAcceptedVertices returns the IDs of the accepted vertices ordered by
+// their height and ID, and the number of vertices, that are referenced by
+// accepted vertices, but aren't stored.
+// Assumes [ctx.Lock] is held.
+func (b *dagBackfiller) getAcceptedVertices() ([]ids.ID, uint64, error) {
+	type heightVertex struct {
+		height uint64
+		vtxID  ids.ID
+	}
+	var (
+		vertices        []heightVertex
+		skippedVertices uint64
+		visited         set.Set[ids.ID]
+		toVisit         = b.storage.Edge(b.ctx)
+	)
+	for len(toVisit) > 0 {
+		vtxID := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if visited.Contains(vtxID) {
+			continue
+		}
+		visited.Add(vtxID)
+
+		vtx, err := b.storage.GetVtx(b.ctx, vtxID)
+		if err != nil {
+			return nil, 0, err
+		}
+		height, err := vtx.Height()
+		if err != nil {
+			return nil, 0, err
+		}
+		vertices = append(vertices, heightVertex{height: height, vtxID: vtxID})
+
+		parents, err := vtx.Parents()
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, parent := range parents {
+			parentID := parent.ID()
+			if visited.Contains(parentID) {
+				continue
+			}
+			if parent.Status() != choices.Accepted {
+				visited.Add(parentID)
+				skippedVertices++
+				continue
+			}
+			toVisit = append(toVisit, parentID)
+		}
+	}
+
+	// The parents of a vertex are lower than the vertex.
+	sort.Slice(vertices, func(i, j int) bool {
+		if vertices[i].height != vertices[j].height {
+			return vertices[i].height < vertices[j].height
+		}
+		return vertices[i].vtxID.Less(vertices[j].vtxID)
+	})
+	vtxIDs := make([]ids.ID, len(vertices))
+	for i, vtx := range vertices {
+		vtxIDs[i] = vtx.vtxID
+	}
+	return vtxIDs, skippedVertices, nil
+}
+
+// getStartVertex returns the position in [b.vtxIDs] of the first vertex after
+// the last indexed vertex.
+func (b *dagBackfiller) getStartVertex() (uint64, error) {
+	lastIndexed, err := b.vtxIndex.GetLastAccepted()
+	if err == errNoneAccepted {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	for i, vtxID := range b.vtxIDs {
+		if vtxID == lastIndexed.ID {
+			return uint64(i) + 1, nil
+		}
+	}
+	// The last indexed vertex isn't accepted, so no vertex can be added after
+	// it without breaking the order of the index.
+	return uint64(len(b.vtxIDs)), nil
+}
+
+// dagAcceptor passes the vertices or txs accepted by consensus to [index],
+// once the backfill of [backfiller] is done.
+type dagAcceptor struct {
+	backfiller *dagBackfiller
+	index      Index
+}
+
+// Assumes [ctx.Lock] is held.
+func (a *dagAcceptor) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	if !a.backfiller.isDone() {
+		// The container will be backfilled.
+		return nil
+	}
+	return a.index.Accept(ctx, containerID, container)
+}
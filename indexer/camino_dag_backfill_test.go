@@ -0,0 +1,205 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package indexer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowstorm"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+func newTestVertex(height uint64, txs []snowstorm.Tx, parents ...avalanche.Vertex) *avalanche.TestVertex {
+	vtxID := ids.GenerateTestID()
+	return &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     vtxID,
+			StatusV: choices.Accepted,
+		},
+		ParentsV: parents,
+		HeightV:  height,
+		TxsV:     txs,
+		BytesV:   vtxID[:],
+	}
+}
+
+func newTestTx() *snowstorm.TestTx {
+	txID := ids.GenerateTestID()
+	return &snowstorm.TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     txID,
+			StatusV: choices.Accepted,
+		},
+		BytesV: txID[:],
+	}
+}
+
+func TestDAGBackfiller(t *testing.T) {
+	codec := codec.NewDefaultManager()
+	require.NoError(t, codec.RegisterCodec(codecVersion, linearcodec.NewDefault()))
+	ctx := snow.DefaultConsensusContextTest()
+
+	// The DAG consists of the vertices 0a and 0b, the vertex 1 on top of
+	// them and the stop vertex.
+	var (
+		tx0a, tx1a, tx1b = newTestTx(), newTestTx(), newTestTx()
+		vtx0a            = newTestVertex(0, []snowstorm.Tx{tx0a})
+		vtx0b            = newTestVertex(0, nil)
+		vtx1             = newTestVertex(1, []snowstorm.Tx{tx1a, tx1b}, vtx0a, vtx0b)
+		stopVtx          = newTestVertex(2, nil, vtx1)
+		vertices         = []*avalanche.TestVertex{vtx0a, vtx0b, vtx1, stopVtx}
+	)
+	// Vertices of the same height are ordered by their ID.
+	if vtx0b.ID().Less(vtx0a.ID()) {
+		vertices[0], vertices[1] = vtx0b, vtx0a
+	}
+
+	type test struct {
+		name string
+		// Vertices, that are in the vertex index before the backfill
+		indexedVertices []*avalanche.TestVertex
+		// Vertex, that isn't stored
+		missingVertex      *avalanche.TestVertex
+		expectedVertices   []*avalanche.TestVertex
+		expectedTxs        []*snowstorm.TestTx
+		expectedIncomplete *bool
+	}
+	incomplete, complete := true, false
+	tests := []test{
+		{
+			name:               "empty index",
+			expectedVertices:   vertices,
+			expectedTxs:        []*snowstorm.TestTx{tx0a, tx1a, tx1b},
+			expectedIncomplete: &complete,
+		},
+		{
+			name:             "gap after indexed vertices",
+			indexedVertices:  []*avalanche.TestVertex{vtx1},
+			expectedVertices: []*avalanche.TestVertex{vtx1, stopVtx},
+		},
+		{
+			name:               "missing vertex",
+			missingVertex:      vtx0b,
+			expectedVertices:   []*avalanche.TestVertex{vtx0a, vtx1, stopVtx},
+			expectedTxs:        []*snowstorm.TestTx{tx0a, tx1a, tx1b},
+			expectedIncomplete: &incomplete,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			vtx0b.StatusV = choices.Accepted
+			if tt.missingVertex != nil {
+				tt.missingVertex.StatusV = choices.Unknown
+			}
+			stopVertexAccepted := false
+			storage := &vertex.TestStorage{
+				EdgeF: func(context.Context) []ids.ID {
+					return []ids.ID{stopVtx.ID()}
+				},
+				GetVtxF: func(_ context.Context, vtxID ids.ID) (avalanche.Vertex, error) {
+					for _, vtx := range vertices {
+						if vtx.ID() == vtxID && vtx.Status() == choices.Accepted {
+							return vtx, nil
+						}
+					}
+					return nil, database.ErrNotFound
+				},
+				StopVertexAcceptedF: func(context.Context) (bool, error) {
+					return stopVertexAccepted, nil
+				},
+			}
+
+			db := memdb.New()
+			vtxIndex, err := newIndex(prefixdb.New([]byte{vtxPrefix}, db), logging.NoLog{}, codec, mockable.Clock{})
+			require.NoError(err)
+			txIndex, err := newIndex(prefixdb.New([]byte{txPrefix}, db), logging.NoLog{}, codec, mockable.Clock{})
+			require.NoError(err)
+			for _, vtx := range tt.indexedVertices {
+				require.NoError(vtxIndex.Accept(ctx, vtx.ID(), vtx.Bytes()))
+			}
+
+			var gotIncomplete *bool
+			key := []byte{dagBackfillPrefix}
+			bf := newDAGBackfiller("chain", codec, logging.NoLog{}, ctx, storage, db, key, nil, func(incomplete bool) error {
+				gotIncomplete = &incomplete
+				return nil
+			})
+			vtxAcceptor := bf.acceptor(vtxPrefix, vtxIndex)
+			txAcceptor := bf.acceptor(txPrefix, txIndex)
+
+			// Vertices are backfilled, once the DAG was finalized.
+			done, err := bf.backfillBatch()
+			require.ErrorIs(err, errNotLinearized)
+			require.False(done)
+			require.True(bf.getProgress().Backfilling)
+
+			// Vertices and txs accepted by consensus are backfilled, until
+			// the backfill is done.
+			require.NoError(vtxAcceptor.Accept(ctx, stopVtx.ID(), stopVtx.Bytes()))
+			_, err = vtxIndex.GetIndex(stopVtx.ID())
+			require.ErrorIs(err, database.ErrNotFound)
+
+			stopVertexAccepted = true
+			done, err = bf.backfillBatch()
+			require.NoError(err)
+			require.True(done)
+			require.Equal(tt.expectedIncomplete, gotIncomplete)
+
+			progress := bf.getProgress()
+			require.False(progress.Backfilling)
+			require.EqualValues(len(tt.expectedVertices)-len(tt.indexedVertices), progress.NextHeight-progress.StartHeight)
+			if tt.missingVertex != nil {
+				require.EqualValues(1, progress.SkippedContainers)
+			}
+
+			containers, err := vtxIndex.GetContainerRange(0, MaxFetchedByRange)
+			require.NoError(err)
+			require.Len(containers, len(tt.expectedVertices))
+			for i, vtx := range tt.expectedVertices {
+				require.Equal(vtx.ID(), containers[i].ID)
+			}
+			if len(tt.expectedTxs) > 0 {
+				containers, err = txIndex.GetContainerRange(0, MaxFetchedByRange)
+				require.NoError(err)
+				require.Len(containers, len(tt.expectedTxs))
+				for i, tx := range tt.expectedTxs {
+					require.Equal(tx.ID(), containers[i].ID)
+				}
+			}
+
+			// Txs accepted by consensus are indexed after the backfill.
+			txID := ids.GenerateTestID()
+			require.NoError(txAcceptor.Accept(ctx, txID, txID[:]))
+			lastAccepted, err := txIndex.GetLastAccepted()
+			require.NoError(err)
+			require.Equal(txID, lastAccepted.ID)
+
+			// The finished backfill isn't repeated after a restart.
+			storage.EdgeF = nil
+			bf = newDAGBackfiller("chain", codec, logging.NoLog{}, ctx, storage, db, key, nil, nil)
+			bf.acceptor(vtxPrefix, vtxIndex)
+			bf.acceptor(txPrefix, txIndex)
+			done, err = bf.backfillBatch()
+			require.NoError(err)
+			require.True(done)
+			require.Equal(progress, bf.getProgress())
+		})
+	}
+}
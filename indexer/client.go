@@ -32,6 +32,8 @@ type Client interface {
 	IsAccepted(ctx context.Context, containerID ids.ID, options ...rpc.Option) (bool, error)
 	// Get a container and its index by its ID
 	GetContainerByID(ctx context.Context, containerID ids.ID, options ...rpc.Option) (Container, uint64, error)
+	// Get the progress of backfilling the index
+	GetBackfillProgress(context.Context, ...rpc.Option) (BackfillProgress, error)
 }
 
 // Client implementation for Avalanche Indexer API Endpoint
@@ -154,3 +156,9 @@ func (c *client) GetContainerByID(ctx context.Context, id ids.ID, options ...rpc
 		Bytes:     containerBytes,
 	}, uint64(fc.Index), nil
 }
+
+func (c *client) GetBackfillProgress(ctx context.Context, options ...rpc.Option) (BackfillProgress, error) {
+	var res BackfillProgress
+	err := c.requester.SendRequest(ctx, "index.getBackfillProgress", struct{}{}, &res, options...)
+	return res, err
+}
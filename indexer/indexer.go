@@ -1,18 +1,30 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
 package indexer
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sync"
 
-	"github.com/gorilla/rpc/v2"
-
 	"go.uber.org/zap"
 
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/codec"
@@ -30,6 +42,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/logging"
 	"github.com/ava-labs/avalanchego/utils/timer/mockable"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
 )
 
 const (
@@ -50,9 +63,14 @@ var (
 	blockPrefix             = byte(0x03)
 	isIncompletePrefix      = byte(0x04)
 	previouslyIndexedPrefix = byte(0x05)
+	addressPrefix           = byte(0x06)
 	hasRunKey               = []byte{0x07}
+	backfillPrefix          = byte(0x08)
+	dagBackfillPrefix       = byte(0x09)
 
 	_ Indexer = (*indexer)(nil)
+
+	errBackfillFailed = errors.New("backfilling failed")
 )
 
 // Config for an indexer
@@ -61,6 +79,7 @@ type Config struct {
 	Log                  logging.Logger
 	IndexingEnabled      bool
 	AllowIncompleteIndex bool
+	AddressIndexEnabled  bool
 	BlockAcceptorGroup   snow.AcceptorGroup
 	TxAcceptorGroup      snow.AcceptorGroup
 	VertexAcceptorGroup  snow.AcceptorGroup
@@ -74,6 +93,8 @@ type Config struct {
 // Indexer is threadsafe.
 type Indexer interface {
 	chains.Registrant
+	// Reports the progress of backfilling the indices
+	health.Checker
 	// Close will do nothing and return nil after the first call
 	io.Closer
 }
@@ -86,12 +107,16 @@ func NewIndexer(config Config) (Indexer, error) {
 		db:                   config.DB,
 		allowIncompleteIndex: config.AllowIncompleteIndex,
 		indexingEnabled:      config.IndexingEnabled,
+		addressIndexEnabled:  config.AddressIndexEnabled,
+		newAddressTxsParser:  newAddressTxsParser,
 		blockAcceptorGroup:   config.BlockAcceptorGroup,
 		txAcceptorGroup:      config.TxAcceptorGroup,
 		vertexAcceptorGroup:  config.VertexAcceptorGroup,
 		txIndices:            map[ids.ID]Index{},
 		vtxIndices:           map[ids.ID]Index{},
 		blockIndices:         map[ids.ID]Index{},
+		addressIndices:       map[ids.ID]*addressIndex{},
+		backfillers:          map[ids.ID][]indexBackfiller{},
 		pathAdder:            config.APIServer,
 		shutdownF:            config.ShutdownF,
 	}
@@ -134,12 +159,23 @@ type indexer struct {
 	// If false, don't create index for a chain when RegisterChain is called
 	indexingEnabled bool
 
+	// If true, index the txs of the chains, that support it, by the addresses
+	// they touched
+	addressIndexEnabled bool
+	// Returns the parser of the txs of a chain for the address index, or nil
+	// if the chain doesn't support it
+	newAddressTxsParser func(*snow.ConsensusContext) (addressTxsParser, error)
+
 	// Chain ID --> index of blocks of that chain (if applicable)
 	blockIndices map[ids.ID]Index
 	// Chain ID --> index of vertices of that chain (if applicable)
 	vtxIndices map[ids.ID]Index
 	// Chain ID --> index of txs of that chain (if applicable)
 	txIndices map[ids.ID]Index
+	// Chain ID --> index of txs of that chain by address (if applicable)
+	addressIndices map[ids.ID]*addressIndex
+	// Chain ID --> backfillers of the indices of that chain (if applicable)
+	backfillers map[ids.ID][]indexBackfiller
 
 	// Notifies of newly accepted blocks
 	blockAcceptorGroup snow.AcceptorGroup
@@ -176,6 +212,10 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 		return
 	}
 
+	// If the blocks of the chain can be read by their height, blocks, that
+	// weren't indexed, are backfilled.
+	backfillVM, backfillable := i.getBackfillableVM(ctx, vm)
+
 	// If the index is incomplete, make sure that's OK. Otherwise, cause node to die.
 	isIncomplete, err := i.isIncomplete(chainID)
 	if err != nil {
@@ -207,6 +247,11 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 	}
 
 	if !i.indexingEnabled { // Indexing is disabled
+		if backfillable {
+			// The blocks accepted in this run will be backfilled, once
+			// indexing is enabled again.
+			return
+		}
 		if previouslyIndexed && !i.allowIncompleteIndex {
 			// We indexed this chain in a previous run but not in this run.
 			// This would create an incomplete index, which is not allowed, so exit.
@@ -238,7 +283,10 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 		return
 	}
 
-	if !i.allowIncompleteIndex && isIncomplete && (previouslyIndexed || i.hasRunBefore) {
+	// If the chain wasn't indexed before, its index is empty and all blocks
+	// can be backfilled.
+	canBackfill := backfillable && !previouslyIndexed
+	if !i.allowIncompleteIndex && isIncomplete && (previouslyIndexed || i.hasRunBefore) && !canBackfill {
 		i.log.Fatal("index is incomplete but incomplete indices are disabled. Shutting down",
 			zap.String("chainName", chainName),
 		)
@@ -264,7 +312,48 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 		return
 	}
 
-	index, err := i.registerChainHelper(chainID, blockPrefix, chainName, "block", i.blockAcceptorGroup)
+	addrIndex, addrTxsParser, err := i.registerAddressIndex(chainName, ctx, vm)
+	if err != nil {
+		i.log.Fatal("failed to create address index",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+		if err := i.close(); err != nil {
+			i.log.Error("failed to close indexer",
+				zap.Error(err),
+			)
+		}
+		return
+	}
+	var (
+		onAccept                        func()
+		onBlockAccepting, onTxAccepting func(*snow.ConsensusContext, []byte) error
+	)
+	if addrIndex != nil {
+		onAccept = addrIndex.onAccept
+		onBlockAccepting = func(ctx *snow.ConsensusContext, container []byte) error {
+			return addrIndex.putInputOwners(ctx, container, addrTxsParser.parseBlock)
+		}
+		onTxAccepting = func(ctx *snow.ConsensusContext, container []byte) error {
+			return addrIndex.putInputOwners(ctx, container, addrTxsParser.parseTx)
+		}
+	}
+
+	blockBackfill, dagBackfill, err := i.newBackfillers(chainName, ctx, vm, backfillVM, backfillable, previouslyIndexed, onAccept)
+	if err != nil {
+		i.log.Fatal("failed to create backfillers",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+		if err := i.close(); err != nil {
+			i.log.Error("failed to close indexer",
+				zap.Error(err),
+			)
+		}
+		return
+	}
+
+	index, err := i.registerChainHelper(chainID, blockPrefix, chainName, "block", i.blockAcceptorGroup, blockBackfill, onBlockAccepting, onAccept)
 	if err != nil {
 		i.log.Fatal("failed to create index",
 			zap.String("chainName", chainName),
@@ -282,7 +371,7 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 
 	switch vm.(type) {
 	case vertex.DAGVM:
-		vtxIndex, err := i.registerChainHelper(chainID, vtxPrefix, chainName, "vtx", i.vertexAcceptorGroup)
+		vtxIndex, err := i.registerChainHelper(chainID, vtxPrefix, chainName, "vtx", i.vertexAcceptorGroup, dagBackfill, nil, nil)
 		if err != nil {
 			i.log.Fatal("couldn't create index",
 				zap.String("chainName", chainName),
@@ -298,7 +387,7 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 		}
 		i.vtxIndices[chainID] = vtxIndex
 
-		txIndex, err := i.registerChainHelper(chainID, txPrefix, chainName, "tx", i.txAcceptorGroup)
+		txIndex, err := i.registerChainHelper(chainID, txPrefix, chainName, "tx", i.txAcceptorGroup, dagBackfill, onTxAccepting, onAccept)
 		if err != nil {
 			i.log.Fatal("couldn't create index",
 				zap.String("chainName", chainName),
@@ -313,6 +402,10 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 			return
 		}
 		i.txIndices[chainID] = txIndex
+
+		if addrIndex != nil {
+			addrIndex.addSource(txPrefix, txIndex, addrTxsParser.parseTx)
+		}
 	case block.ChainVM:
 	default:
 		vmType := fmt.Sprintf("%T", vm)
@@ -324,7 +417,121 @@ func (i *indexer) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm
 				zap.Error(err),
 			)
 		}
+		return
+	}
+
+	if addrIndex != nil {
+		addrIndex.addSource(blockPrefix, index, addrTxsParser.parseBlock)
+		addrIndex.start()
+	}
+	for _, bf := range i.backfillers[chainID] {
+		bf.start()
+	}
+}
+
+// newBackfillers returns the backfillers of the block index and, for DAG
+// chains, of the vertex and tx indices of the chain [ctx], if they can be
+// backfilled. The vertices and txs of DAG chains are only backfilled, if the
+// chain wasn't indexed in a previous run of the node or their backfill
+// didn't finish. Otherwise, they are indexed as they are accepted.
+func (i *indexer) newBackfillers(
+	chainName string,
+	ctx *snow.ConsensusContext,
+	vm common.VM,
+	backfillVM backfillableVM,
+	backfillable bool,
+	previouslyIndexed bool,
+	onAccept func(),
+) (indexBackfiller, indexBackfiller, error) {
+	if !backfillable {
+		return nil, nil, nil
+	}
+
+	chainID := ctx.ChainID
+	var (
+		numBackfillers int
+		numCompleted   int
+	)
+	// The index of the chain is complete, once all of its backfillers
+	// completed their indices.
+	// Assumes [ctx.Lock] is held.
+	setIncomplete := func(incomplete bool) error {
+		if incomplete {
+			return i.markIncomplete(chainID)
+		}
+		numCompleted++
+		if numCompleted < numBackfillers {
+			return nil
+		}
+		return i.markComplete(chainID)
+	}
+
+	key := make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(key, chainID[:])
+	key[hashing.HashLen] = backfillPrefix
+	blockBackfill := newBackfiller(
+		chainName,
+		i.codec,
+		i.log,
+		ctx,
+		backfillVM,
+		i.db,
+		key,
+		onAccept,
+		setIncomplete,
+	)
+	i.backfillers[chainID] = []indexBackfiller{blockBackfill}
+	numBackfillers++
+
+	dagVM, ok := vm.(chains.DAGChainVM)
+	if !ok {
+		return blockBackfill, nil, nil
+	}
+	key = make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(key, chainID[:])
+	key[hashing.HashLen] = dagBackfillPrefix
+	hasState, err := i.db.Has(key)
+	if err != nil {
+		return nil, nil, err
 	}
+	if !hasState && (previouslyIndexed || !i.hasRunBefore) {
+		return blockBackfill, nil, nil
+	}
+	dagBackfill := newDAGBackfiller(
+		chainName+"/vtx",
+		i.codec,
+		i.log,
+		ctx,
+		dagVM.VertexStorage(),
+		i.db,
+		key,
+		onAccept,
+		setIncomplete,
+	)
+	i.backfillers[chainID] = append(i.backfillers[chainID], dagBackfill)
+	numBackfillers++
+	return blockBackfill, dagBackfill, nil
+}
+
+// getBackfillableVM returns the VM, whose accepted blocks can be read by their
+// height, which is [vm] or, for DAG chains, the VM of the linearized chain.
+// Assumes [ctx.Lock] is not held.
+func (i *indexer) getBackfillableVM(ctx *snow.ConsensusContext, vm common.VM) (backfillableVM, bool) {
+	var backfillVM backfillableVM
+	if dagVM, ok := vm.(chains.DAGChainVM); ok {
+		// The blocks are backfilled, once the chain was linearized.
+		backfillVM = &linearizedVM{vm: dagVM}
+	} else if backfillVM, ok = vm.(backfillableVM); !ok {
+		return nil, false
+	}
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	// The height index may still be repaired, which is waited for by the
+	// backfiller.
+	err := backfillVM.VerifyHeightIndex(context.TODO())
+	return backfillVM, err != block.ErrHeightIndexedVMNotImplemented
 }
 
 func (i *indexer) registerChainHelper(
@@ -332,6 +539,9 @@ func (i *indexer) registerChainHelper(
 	prefixEnd byte,
 	name, endpoint string,
 	acceptorGroup snow.AcceptorGroup,
+	backfill indexBackfiller,
+	onAccepting func(*snow.ConsensusContext, []byte) error,
+	onAccept func(),
 ) (Index, error) {
 	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
 	copy(prefix, chainID[:])
@@ -344,26 +554,73 @@ func (i *indexer) registerChainHelper(
 	}
 
 	// Register index to learn about new accepted vertices
-	if err := acceptorGroup.RegisterAcceptor(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID), index, true); err != nil {
+	var acceptor snow.Acceptor = index
+	if backfill != nil {
+		// Containers are passed to the index by the backfiller, until it
+		// caught up.
+		acceptor = backfill.acceptor(prefixEnd, index)
+	}
+	if onAccept != nil {
+		acceptor = &notifyingAcceptor{
+			Acceptor:    acceptor,
+			onAccepting: onAccepting,
+			onAccept:    onAccept,
+		}
+	}
+	if err := acceptorGroup.RegisterAcceptor(chainID, fmt.Sprintf("%s%s", indexNamePrefix, chainID), acceptor, true); err != nil {
+		_ = index.Close()
+		return nil, err
+	}
+
+	// Create an API endpoint for this index
+	if err := i.addRoute(&service{Index: index, backfiller: backfill}, name, endpoint); err != nil {
 		_ = index.Close()
 		return nil, err
 	}
+	return index, nil
+}
+
+// registerAddressIndex creates the address index of the chain [ctx] and its
+// API endpoint. Returns nil, if the txs of the chain aren't indexed by
+// address. The address index must be started after its sources were added.
+// The consumed UTXOs are looked up in the state of [vm], if it returns UTXOs.
+func (i *indexer) registerAddressIndex(name string, ctx *snow.ConsensusContext, vm common.VM) (*addressIndex, addressTxsParser, error) {
+	if !i.addressIndexEnabled {
+		return nil, nil, nil
+	}
+	parser, err := i.newAddressTxsParser(ctx)
+	if err != nil || parser == nil {
+		return nil, nil, err
+	}
+
+	prefix := make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(prefix, ctx.ChainID[:])
+	prefix[hashing.HashLen] = addressPrefix
+	utxos, _ := common.UnwrapVM(vm).(utxoGetter)
+	addrIndex := newAddressIndex(prefixdb.New(prefix, i.db), i.log, i.codec, parser, utxos)
+	i.addressIndices[ctx.ChainID] = addrIndex
 
 	// Create an API endpoint for this index
-	apiServer := rpc.NewServer()
+	return addrIndex, parser, i.addRoute(
+		&addressService{
+			index:          addrIndex,
+			addressManager: avax.NewAddressManager(ctx.Context),
+		},
+		name,
+		"address",
+	)
+}
+
+func (i *indexer) addRoute(service interface{}, name, endpoint string) error {
+	apiServer := openrpc.NewServer()
 	codec := json.NewCodec()
 	apiServer.RegisterCodec(codec, "application/json")
 	apiServer.RegisterCodec(codec, "application/json;charset=UTF-8")
-	if err := apiServer.RegisterService(&service{Index: index}, "index"); err != nil {
-		_ = index.Close()
-		return nil, err
+	if err := apiServer.RegisterService(service, "index"); err != nil {
+		return err
 	}
 	handler := &common.HTTPHandler{LockOptions: common.NoLock, Handler: apiServer}
-	if err := i.pathAdder.AddRoute(handler, &sync.RWMutex{}, "index/"+name, "/"+endpoint); err != nil {
-		_ = index.Close()
-		return nil, err
-	}
-	return index, nil
+	return i.pathAdder.AddRoute(handler, &sync.RWMutex{}, "index/"+name, "/"+endpoint)
 }
 
 // Close this indexer. Stops indexing all chains.
@@ -384,6 +641,16 @@ func (i *indexer) close() error {
 	i.closed = true
 
 	errs := &wrappers.Errs{}
+	// The backfillers and the address indices read from and write to the other
+	// indices, so they are closed first.
+	for _, chainBackfillers := range i.backfillers {
+		for _, bf := range chainBackfillers {
+			bf.Close()
+		}
+	}
+	for _, addrIndex := range i.addressIndices {
+		errs.Add(addrIndex.Close())
+	}
 	for chainID, txIndex := range i.txIndices {
 		errs.Add(
 			txIndex.Close(),
@@ -408,6 +675,31 @@ func (i *indexer) close() error {
 	return errs.Err
 }
 
+// HealthCheck returns the progress of backfilling the block indices by chain
+// name and the vertex and tx indices by chain name followed by "/vtx".
+// Returns an error, if the last attempt of a backfill failed.
+func (i *indexer) HealthCheck(context.Context) (interface{}, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	details := make(map[string]BackfillProgress, len(i.backfillers))
+	var failed []string
+	for _, chainBackfillers := range i.backfillers {
+		for _, bf := range chainBackfillers {
+			name := bf.getName()
+			progress := bf.getProgress()
+			details[name] = progress
+			if progress.Error != "" {
+				failed = append(failed, name)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return details, fmt.Errorf("%w for chains %v", errBackfillFailed, failed)
+	}
+	return details, nil
+}
+
 func (i *indexer) markIncomplete(chainID ids.ID) error {
 	key := make([]byte, hashing.HashLen+wrappers.ByteLen)
 	copy(key, chainID[:])
@@ -415,6 +707,13 @@ func (i *indexer) markIncomplete(chainID ids.ID) error {
 	return i.db.Put(key, nil)
 }
 
+func (i *indexer) markComplete(chainID ids.ID) error {
+	key := make([]byte, hashing.HashLen+wrappers.ByteLen)
+	copy(key, chainID[:])
+	key[hashing.HashLen] = isIncompletePrefix
+	return i.db.Delete(key)
+}
+
 // Returns true if this chain is incomplete
 func (i *indexer) isIncomplete(chainID ids.ID) (bool, error) {
 	key := make([]byte, hashing.HashLen+wrappers.ByteLen)
@@ -447,3 +746,25 @@ func (i *indexer) markHasRun() error {
 func (i *indexer) hasRun() (bool, error) {
 	return i.db.Has(hasRunKey)
 }
+
+// notifyingAcceptor calls [onAccepting] before and [onAccept] after
+// [Acceptor] accepted a container.
+type notifyingAcceptor struct {
+	snow.Acceptor
+	// Can be nil
+	onAccepting func(*snow.ConsensusContext, []byte) error
+	onAccept    func()
+}
+
+func (a *notifyingAcceptor) Accept(ctx *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	if a.onAccepting != nil {
+		if err := a.onAccepting(ctx, container); err != nil {
+			return err
+		}
+	}
+	if err := a.Acceptor.Accept(ctx, containerID, container); err != nil {
+		return err
+	}
+	a.onAccept()
+	return nil
+}
@@ -1,9 +1,20 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
 package indexer
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -16,8 +27,12 @@ import (
 
 type service struct {
 	Index
+	// Backfills the index, if not nil
+	backfiller indexBackfiller
 }
 
+var errBackfillNotSupported = errors.New("backfilling isn't supported by this index")
+
 type FormattedContainer struct {
 	ID        ids.ID              `json:"id"`
 	Bytes     string              `json:"bytes"`
@@ -163,3 +178,13 @@ func (s *service) GetContainerByID(_ *http.Request, args *GetContainerByIDArgs,
 	*reply, err = newFormattedContainer(container, index, args.Encoding)
 	return err
 }
+
+// GetBackfillProgress returns the progress of adding the containers, that
+// were accepted while the chain wasn't indexed, to the index.
+func (s *service) GetBackfillProgress(_ *http.Request, _ *struct{}, reply *BackfillProgress) error {
+	if s.backfiller == nil {
+		return errBackfillNotSupported
+	}
+	*reply = s.backfiller.getProgress()
+	return nil
+}
@@ -0,0 +1,423 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	stdcontext "context"
+	stdjson "encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/ipcs/eventsink"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	ipcSinkIdentifier   = ipcIdentifierPrefix + "-sink"
+	ipcEventsIdentifier = "events"
+
+	// Maximum number of events, that are published at once
+	maxPublishedEvents = 256
+	// Time to wait before retrying to publish events
+	publishRetryDelay = 5 * time.Second
+)
+
+var (
+	_ chains.Registrant = (*ChainSinks)(nil)
+	_ snow.Acceptor     = (*chainSink)(nil)
+
+	// Sequence number --> Event, that wasn't published yet
+	eventPrefix = []byte{0x00}
+	// Sequence number of the next accepted event
+	nextSequenceKey = []byte{0x01}
+	// Sequence number of the next event to publish
+	cursorKey = []byte{0x02}
+)
+
+// ChainSinks publishes the txs accepted by a set of chains to an event sink.
+// Accepted txs are persisted, before they are published in the background, so
+// they are published at least once, even if the sink is unavailable or the
+// node restarts.
+type ChainSinks struct {
+	context
+	db                 database.Database
+	sink               eventsink.Sink
+	blockAcceptorGroup snow.AcceptorGroup
+	txAcceptorGroup    snow.AcceptorGroup
+	// Chains, whose txs are published. If empty, the txs of all supported
+	// chains are published.
+	chainIDs set.Set[ids.ID]
+
+	lock   sync.Mutex
+	closed bool
+	chains map[ids.ID]*chainSink
+}
+
+// NewChainSinks creates a new *ChainSinks that publishes the txs accepted by
+// the chains [chainIDs] to [sink]. [db] keeps the txs, that weren't
+// published yet. [sink] is closed on shutdown.
+func NewChainSinks(
+	log logging.Logger,
+	networkID uint32,
+	db database.Database,
+	sink eventsink.Sink,
+	blockAcceptorGroup snow.AcceptorGroup,
+	txAcceptorGroup snow.AcceptorGroup,
+	chainIDs []ids.ID,
+) *ChainSinks {
+	chainIDsSet := set.NewSet[ids.ID](len(chainIDs))
+	chainIDsSet.Add(chainIDs...)
+	return &ChainSinks{
+		context: context{
+			log:       log,
+			networkID: networkID,
+		},
+		db:                 db,
+		sink:               sink,
+		blockAcceptorGroup: blockAcceptorGroup,
+		txAcceptorGroup:    txAcceptorGroup,
+		chainIDs:           chainIDsSet,
+		chains:             make(map[ids.ID]*chainSink),
+	}
+}
+
+// RegisterChain starts publishing the txs accepted by the chain [ctx], if
+// they should be published.
+// Assumes [ctx.Lock] is not held.
+func (cs *ChainSinks) RegisterChain(chainName string, ctx *snow.ConsensusContext, _ common.VM) {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	chainID := ctx.ChainID
+	if cs.closed || cs.chains[chainID] != nil {
+		return
+	}
+	if cs.chainIDs.Len() > 0 && !cs.chainIDs.Contains(chainID) {
+		return
+	}
+
+	decoder, err := newTxDecoder(ctx)
+	if err != nil {
+		cs.log.Error("couldn't create tx decoder",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+		return
+	}
+	if decoder == nil {
+		if cs.chainIDs.Len() > 0 {
+			cs.log.Warn("not publishing txs to event sink",
+				zap.String("reason", "txs of the chain can't be decoded"),
+				zap.String("chainName", chainName),
+			)
+		}
+		return
+	}
+
+	sink, err := newChainSink(
+		cs.context,
+		chainID,
+		prefixdb.New(chainID[:], cs.db),
+		cs.sink,
+		decoder,
+	)
+	if err != nil {
+		cs.log.Error("couldn't create chain sink",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+		return
+	}
+	if err := cs.registerAcceptors(chainID, sink); err != nil {
+		cs.log.Error("couldn't register chain sink",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+		_ = sink.db.Close()
+		return
+	}
+	sink.start()
+
+	cs.chains[chainID] = sink
+	cs.log.Info("publishing txs to event sink",
+		zap.String("chainName", chainName),
+		zap.Stringer("blockchainID", chainID),
+		zap.String("topic", sink.topic),
+	)
+}
+
+func (cs *ChainSinks) registerAcceptors(chainID ids.ID, sink *chainSink) error {
+	if err := cs.blockAcceptorGroup.RegisterAcceptor(chainID, ipcSinkIdentifier, sink, false); err != nil {
+		return err
+	}
+	// Txs are only accepted individually, before the chain was linearized.
+	if err := cs.txAcceptorGroup.RegisterAcceptor(chainID, ipcSinkIdentifier, &txAcceptor{sink}, false); err != nil {
+		_ = cs.blockAcceptorGroup.DeregisterAcceptor(chainID, ipcSinkIdentifier)
+		return err
+	}
+	return nil
+}
+
+// GetPublishedBlockchains returns the chains, whose txs are published
+func (cs *ChainSinks) GetPublishedBlockchains() []ids.ID {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	return maps.Keys(cs.chains)
+}
+
+// Shutdown stops publishing txs and closes the sink. Txs, that weren't
+// published yet, are published after the next start.
+func (cs *ChainSinks) Shutdown() error {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	if cs.closed {
+		return nil
+	}
+	cs.closed = true
+	cs.log.Info("shutting down chain sinks")
+
+	errs := wrappers.Errs{}
+	for chainID, sink := range cs.chains {
+		errs.Add(
+			cs.blockAcceptorGroup.DeregisterAcceptor(chainID, ipcSinkIdentifier),
+			cs.txAcceptorGroup.DeregisterAcceptor(chainID, ipcSinkIdentifier),
+			sink.stop(),
+		)
+	}
+	errs.Add(cs.sink.Close())
+	return errs.Err
+}
+
+// chainSink persists the txs accepted by a single chain and publishes them to
+// the topic of the chain.
+type chainSink struct {
+	log     logging.Logger
+	chainID ids.ID
+	topic   string
+	db      database.Database
+	sink    eventsink.Sink
+	decoder txDecoder
+	clock   mockable.Clock
+
+	// Sequence number of the next accepted event. Only accessed by Accept,
+	// which is called with the chain's lock held.
+	nextSequence uint64
+	// Signaled, when events were accepted
+	accepted chan struct{}
+
+	ctx    stdcontext.Context
+	cancel stdcontext.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newChainSink(
+	ctx context,
+	chainID ids.ID,
+	db database.Database,
+	sink eventsink.Sink,
+	decoder txDecoder,
+) (*chainSink, error) {
+	nextSequence, err := getUInt64(db, nextSequenceKey)
+	if err != nil {
+		return nil, err
+	}
+	sinkCtx, cancel := stdcontext.WithCancel(stdcontext.Background())
+	return &chainSink{
+		log:          ctx.log,
+		chainID:      chainID,
+		topic:        fmt.Sprintf("%d-%s-%s", ctx.networkID, chainID, ipcEventsIdentifier),
+		db:           db,
+		sink:         sink,
+		decoder:      decoder,
+		nextSequence: nextSequence,
+		accepted:     make(chan struct{}, 1),
+		ctx:          sinkCtx,
+		cancel:       cancel,
+	}, nil
+}
+
+// Accept persists the txs of the accepted block [containerID].
+func (s *chainSink) Accept(_ *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	return s.accept(containerID, container, s.decoder.decodeBlock)
+}
+
+func (s *chainSink) accept(containerID ids.ID, container []byte, decode func([]byte) ([]decodedTx, error)) error {
+	txs, err := decode(container)
+	if err != nil {
+		// Accepting the container must not fail, so the container is
+		// skipped.
+		s.log.Warn("skipping undecodable container",
+			zap.Stringer("blockchainID", s.chainID),
+			zap.Stringer("containerID", containerID),
+			zap.Error(err),
+		)
+		return nil
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+
+	batch := s.db.NewBatch()
+	timestamp := s.clock.Time()
+	nextSequence := s.nextSequence
+	for _, tx := range txs {
+		eventBytes, err := stdjson.Marshal(&Event{
+			ChainID:     s.chainID,
+			Sequence:    json.Uint64(nextSequence),
+			ContainerID: containerID,
+			TxID:        tx.id,
+			Type:        txType(tx.unsigned),
+			Tx:          tx.unsigned,
+			Timestamp:   timestamp,
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't marshal tx %s: %w", tx.id, err)
+		}
+		if err := batch.Put(eventKey(nextSequence), eventBytes); err != nil {
+			return err
+		}
+		nextSequence++
+	}
+	if err := database.PutUInt64(batch, nextSequenceKey, nextSequence); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	s.nextSequence = nextSequence
+
+	select {
+	case s.accepted <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// start publishes the persisted events in the background, until the chain
+// sink is stopped.
+func (s *chainSink) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		for {
+			published, err := s.publish()
+			if err != nil {
+				s.log.Warn("failed to publish events",
+					zap.Stringer("blockchainID", s.chainID),
+					zap.Duration("retryDelay", publishRetryDelay),
+					zap.Error(err),
+				)
+				select {
+				case <-s.ctx.Done():
+					return
+				case <-time.After(publishRetryDelay):
+				}
+				continue
+			}
+			if published {
+				continue
+			}
+
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-s.accepted:
+			}
+		}
+	}()
+}
+
+// publish publishes up to [maxPublishedEvents] events, that weren't published
+// yet. Returns true, if events were published.
+func (s *chainSink) publish() (bool, error) {
+	cursor, err := getUInt64(s.db, cursorKey)
+	if err != nil {
+		return false, err
+	}
+
+	it := s.db.NewIteratorWithStartAndPrefix(eventKey(cursor), eventPrefix)
+	var (
+		msgs [][]byte
+		keys [][]byte
+	)
+	for len(msgs) < maxPublishedEvents && it.Next() {
+		keys = append(keys, slices.Clone(it.Key()))
+		msgs = append(msgs, slices.Clone(it.Value()))
+	}
+	err = it.Error()
+	it.Release()
+	if err != nil || len(msgs) == 0 {
+		return false, err
+	}
+
+	events := make([]eventsink.Message, len(msgs))
+	for i, msg := range msgs {
+		events[i] = eventsink.Message{Value: msg}
+	}
+	if err := s.sink.Publish(s.ctx, s.topic, events); err != nil {
+		return false, err
+	}
+
+	// The published events aren't needed anymore.
+	batch := s.db.NewBatch()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return false, err
+		}
+	}
+	if err := database.PutUInt64(batch, cursorKey, cursor+uint64(len(msgs))); err != nil {
+		return false, err
+	}
+	return true, batch.Write()
+}
+
+// stop stops publishing events and closes [s.db].
+func (s *chainSink) stop() error {
+	s.cancel()
+	s.wg.Wait()
+	return s.db.Close()
+}
+
+// txAcceptor persists the txs accepted by a chain, before it was linearized.
+type txAcceptor struct {
+	sink *chainSink
+}
+
+func (a *txAcceptor) Accept(_ *snow.ConsensusContext, txID ids.ID, txBytes []byte) error {
+	return a.sink.accept(txID, txBytes, a.sink.decoder.decodeTx)
+}
+
+func eventKey(sequence uint64) []byte {
+	key := make([]byte, 0, len(eventPrefix)+wrappers.LongLen)
+	key = append(key, eventPrefix...)
+	return append(key, database.PackUInt64(sequence)...)
+}
+
+// getUInt64 returns the value of [key] or 0, if it doesn't exist.
+func getUInt64(db database.KeyValueReader, key []byte) (uint64, error) {
+	value, err := database.GetUInt64(db, key)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	return value, err
+}
@@ -0,0 +1,188 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	stdcontext "context"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/ipcs/eventsink"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	_ eventsink.Sink = (*testSink)(nil)
+
+	errTestSinkUnavailable = errors.New("unavailable")
+)
+
+type testSink struct {
+	lock sync.Mutex
+	err  error
+	// Topic --> Published messages
+	topics map[string][]eventsink.Message
+}
+
+func (s *testSink) Publish(_ stdcontext.Context, topic string, msgs []eventsink.Message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	s.topics[topic] = append(s.topics[topic], msgs...)
+	return nil
+}
+
+func (*testSink) Close() error {
+	return nil
+}
+
+func (s *testSink) events(topic string) []Event {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	events := make([]Event, len(s.topics[topic]))
+	for i, msg := range s.topics[topic] {
+		events[i] = Event{}
+		if err := stdjson.Unmarshal(msg.Value, &events[i]); err != nil {
+			panic(err)
+		}
+	}
+	return events
+}
+
+func TestChainSinks(t *testing.T) {
+	require := require.New(t)
+
+	baseDB := memdb.New()
+	blockAcceptorGroup := snow.NewAcceptorGroup(logging.NoLog{})
+	txAcceptorGroup := snow.NewAcceptorGroup(logging.NoLog{})
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = constants.PlatformChainID
+	aliaser := ids.NewAliaser()
+	require.NoError(aliaser.Alias(ctx.ChainID, "P"))
+	ctx.BCLookup = aliaser
+	topic := fmt.Sprintf("%d-%s-%s", ctx.NetworkID, ctx.ChainID, ipcEventsIdentifier)
+
+	newChainSinks := func(sink eventsink.Sink) *ChainSinks {
+		chainSinks := NewChainSinks(
+			logging.NoLog{},
+			ctx.NetworkID,
+			baseDB,
+			sink,
+			blockAcceptorGroup,
+			txAcceptorGroup,
+			[]ids.ID{ctx.ChainID},
+		)
+		chainSinks.RegisterChain("P", ctx, nil)
+		return chainSinks
+	}
+	acceptBlock := func(utxs ...txs.UnsignedTx) []ids.ID {
+		blkTxs := make([]*txs.Tx, len(utxs))
+		txIDs := make([]ids.ID, len(utxs))
+		for i, utx := range utxs {
+			blkTxs[i] = &txs.Tx{Unsigned: utx}
+			require.NoError(blkTxs[i].Initialize(txs.Codec))
+			txIDs[i] = blkTxs[i].ID()
+		}
+		blk, err := blocks.NewBanffStandardBlock(time.Unix(0, 0), ids.GenerateTestID(), 1, blkTxs)
+		require.NoError(err)
+		require.NoError(blockAcceptorGroup.Accept(ctx, blk.ID(), blk.Bytes()))
+		return txIDs
+	}
+	newBaseTx := func(memo string) *txs.BaseTx {
+		return &txs.BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    ctx.NetworkID,
+			BlockchainID: ctx.ChainID,
+			Outs: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: ids.GenerateTestID()},
+				Out: &secp256k1fx.TransferOutput{
+					Amt:          1,
+					OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{ids.GenerateTestShortID()}},
+				},
+			}},
+			Memo: []byte(memo),
+		}}
+	}
+
+	// Txs accepted while the sink is unavailable are published, once it's
+	// available again, even after a restart.
+	sink := &testSink{
+		err:    errTestSinkUnavailable,
+		topics: map[string][]eventsink.Message{},
+	}
+	chainSinks := newChainSinks(sink)
+	require.Equal([]ids.ID{ctx.ChainID}, chainSinks.GetPublishedBlockchains())
+	txIDs := acceptBlock(newBaseTx("a"), newBaseTx("b"))
+	require.NoError(chainSinks.Shutdown())
+	require.Empty(sink.events(topic))
+
+	sink.err = nil
+	chainSinks = newChainSinks(sink)
+	txIDs = append(txIDs, acceptBlock(&txs.AdvanceTimeTx{Time: 1})...)
+	// Undecodable containers are skipped.
+	require.NoError(blockAcceptorGroup.Accept(ctx, ids.GenerateTestID(), []byte("undecodable")))
+	txIDs = append(txIDs, acceptBlock(newBaseTx("c"))...)
+
+	require.Eventually(func() bool {
+		return len(sink.events(topic)) == len(txIDs)
+	}, 5*time.Second, 10*time.Millisecond)
+	events := sink.events(topic)
+	for i, event := range events {
+		require.Equal(ctx.ChainID, event.ChainID)
+		require.EqualValues(i, event.Sequence)
+		require.Equal(txIDs[i], event.TxID)
+	}
+	require.Equal("BaseTx", events[0].Type)
+	require.Equal("AdvanceTimeTx", events[2].Type)
+	require.Equal(events[0].ContainerID, events[1].ContainerID)
+	require.NotEqual(events[1].ContainerID, events[2].ContainerID)
+	require.NoError(chainSinks.Shutdown())
+
+	// Published events aren't kept.
+	it := baseDB.NewIterator()
+	defer it.Release()
+	numKeys := 0
+	for it.Next() {
+		numKeys++
+	}
+	require.NoError(it.Error())
+	// The cursor and the next sequence number
+	require.Equal(2, numKeys)
+}
+
+func TestChainSinksIgnoreUnsupportedChains(t *testing.T) {
+	require := require.New(t)
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	chainSinks := NewChainSinks(
+		logging.NoLog{},
+		ctx.NetworkID,
+		memdb.New(),
+		&testSink{topics: map[string][]eventsink.Message{}},
+		snow.NewAcceptorGroup(logging.NoLog{}),
+		snow.NewAcceptorGroup(logging.NoLog{}),
+		nil,
+	)
+	chainSinks.RegisterChain("C", ctx, nil)
+	require.Empty(chainSinks.GetPublishedBlockchains())
+	require.NoError(chainSinks.Shutdown())
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ipcs
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/vms/avm/fxs"
+	"github.com/ava-labs/avalanchego/vms/nftfx"
+	"github.com/ava-labs/avalanchego/vms/propertyfx"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	xchainblocks "github.com/ava-labs/avalanchego/vms/avm/blocks"
+	pchainblocks "github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	pchaintxs "github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	proposerblock "github.com/ava-labs/avalanchego/vms/proposervm/block"
+)
+
+var (
+	_ txDecoder = (*platformTxDecoder)(nil)
+	_ txDecoder = (*avmTxDecoder)(nil)
+)
+
+// Event is an accepted tx, that is published to the event sinks.
+type Event struct {
+	ChainID ids.ID `json:"chainID"`
+	// Sequence number of the event. The events of a chain are numbered
+	// consecutively, starting at 0. Events may be delivered more than once,
+	// so consumers should drop events with already seen sequence numbers.
+	// If the node stopped while a container was accepted, the txs of the
+	// container may be published again with new sequence numbers.
+	Sequence json.Uint64 `json:"sequence"`
+	// ID of the accepted block or, before the chain was linearized, the
+	// accepted DAG tx, that contains the tx
+	ContainerID ids.ID `json:"containerID"`
+	TxID        ids.ID `json:"txID"`
+	// Type of the tx (e.g. "BaseTx" or "DepositTx")
+	Type string `json:"type"`
+	// The unsigned tx
+	Tx interface{} `json:"tx"`
+	// Time the container was accepted by this node
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// decodedTx is an accepted tx, whose addresses are formatted for the chain.
+type decodedTx struct {
+	id       ids.ID
+	unsigned interface{}
+}
+
+// txDecoder returns the txs of the accepted containers of a chain.
+type txDecoder interface {
+	decodeBlock(blkBytes []byte) ([]decodedTx, error)
+	decodeTx(txBytes []byte) ([]decodedTx, error)
+}
+
+// newTxDecoder returns the decoder of the txs of the chain [ctx]. Returns nil,
+// if the txs of the chain can't be decoded.
+func newTxDecoder(ctx *snow.ConsensusContext) (txDecoder, error) {
+	switch ctx.ChainID {
+	case constants.PlatformChainID:
+		return &platformTxDecoder{ctx: ctx.Context}, nil
+	case ctx.XChainID:
+		parser, err := xchainblocks.NewParser([]fxs.Fx{
+			&secp256k1fx.Fx{},
+			&nftfx.Fx{},
+			&propertyfx.Fx{},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &avmTxDecoder{
+			ctx:    ctx.Context,
+			parser: parser,
+		}, nil
+	default:
+		return nil, nil
+	}
+}
+
+type platformTxDecoder struct {
+	ctx *snow.Context
+}
+
+func (d *platformTxDecoder) decodeBlock(blkBytes []byte) ([]decodedTx, error) {
+	blk, err := parseInnerBlock(blkBytes, func(blkBytes []byte) (pchainblocks.Block, error) {
+		return pchainblocks.Parse(pchainblocks.Codec, blkBytes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	blkTxs := blk.Txs()
+	decodedTxs := make([]decodedTx, len(blkTxs))
+	for i, tx := range blkTxs {
+		tx.Unsigned.InitCtx(d.ctx)
+		decodedTxs[i] = decodedTx{
+			id:       tx.ID(),
+			unsigned: tx.Unsigned,
+		}
+	}
+	return decodedTxs, nil
+}
+
+func (d *platformTxDecoder) decodeTx(txBytes []byte) ([]decodedTx, error) {
+	tx, err := pchaintxs.Parse(pchaintxs.Codec, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	tx.Unsigned.InitCtx(d.ctx)
+	return []decodedTx{{
+		id:       tx.ID(),
+		unsigned: tx.Unsigned,
+	}}, nil
+}
+
+type avmTxDecoder struct {
+	ctx    *snow.Context
+	parser xchainblocks.Parser
+}
+
+func (d *avmTxDecoder) decodeBlock(blkBytes []byte) ([]decodedTx, error) {
+	blk, err := parseInnerBlock(blkBytes, d.parser.ParseBlock)
+	if err != nil {
+		return nil, err
+	}
+	blkTxs := blk.Txs()
+	decodedTxs := make([]decodedTx, len(blkTxs))
+	for i, tx := range blkTxs {
+		tx.Unsigned.InitCtx(d.ctx)
+		decodedTxs[i] = decodedTx{
+			id:       tx.ID(),
+			unsigned: tx.Unsigned,
+		}
+	}
+	return decodedTxs, nil
+}
+
+func (d *avmTxDecoder) decodeTx(txBytes []byte) ([]decodedTx, error) {
+	tx, err := d.parser.ParseTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	tx.Unsigned.InitCtx(d.ctx)
+	return []decodedTx{{
+		id:       tx.ID(),
+		unsigned: tx.Unsigned,
+	}}, nil
+}
+
+// parseInnerBlock parses the block, that is wrapped by the proposervm block
+// [blkBytes]. Blocks, that were accepted before the proposervm was activated,
+// aren't wrapped and are parsed as they are.
+func parseInnerBlock[T any](blkBytes []byte, parse func([]byte) (T, error)) (T, error) {
+	if proposerBlk, err := proposerblock.Parse(blkBytes); err == nil {
+		if blk, err := parse(proposerBlk.Block()); err == nil {
+			return blk, nil
+		}
+	}
+	return parse(blkBytes)
+}
+
+// txType returns the name of the type of the unsigned tx [utx].
+func txType(utx interface{}) string {
+	return reflect.TypeOf(utx).Elem().Name()
+}
@@ -0,0 +1,201 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eventsink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const fileExtension = ".ndjson"
+
+var (
+	_ Sink = (*fileSink)(nil)
+
+	errSinkClosed         = errors.New("sink is closed")
+	errInvalidMaxFileSize = errors.New("max file size must be > 0")
+)
+
+// fileSink writes the messages of each topic as newline-delimited values to
+// the file [dir]/[topic].ndjson. Once the file would grow beyond
+// [maxFileSize], it's renamed to [dir]/[topic].[unix time in ns].ndjson and a
+// new file is started. Only the [maxFiles] newest rotated files of a topic are
+// kept.
+type fileSink struct {
+	dir         string
+	maxFileSize uint64
+	maxFiles    int
+
+	lock   sync.Mutex
+	closed bool
+	// Topic --> File, that is currently written to
+	files map[string]*topicFile
+}
+
+type topicFile struct {
+	file *os.File
+	size uint64
+}
+
+// NewFileSink returns a sink, that writes newline-delimited messages to files
+// in [dir]. Files are rotated, once they would grow beyond [maxFileSize]
+// bytes. If [maxFiles] > 0, older rotated files are removed.
+func NewFileSink(dir string, maxFileSize uint64, maxFiles int) (Sink, error) {
+	if maxFileSize == 0 {
+		return nil, errInvalidMaxFileSize
+	}
+	if err := os.MkdirAll(dir, perms.ReadWriteExecute); err != nil {
+		return nil, fmt.Errorf("couldn't create directory %q: %w", dir, err)
+	}
+	return &fileSink{
+		dir:         dir,
+		maxFileSize: maxFileSize,
+		maxFiles:    maxFiles,
+		files:       map[string]*topicFile{},
+	}, nil
+}
+
+func (s *fileSink) Publish(_ context.Context, topic string, msgs []Message) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return errSinkClosed
+	}
+
+	f, err := s.getFile(topic)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		line := make([]byte, 0, len(msg.Value)+1)
+		line = append(line, msg.Value...)
+		line = append(line, '\n')
+
+		if f.size > 0 && f.size+uint64(len(line)) > s.maxFileSize {
+			if err := s.rotate(topic, f); err != nil {
+				return err
+			}
+			if f, err = s.getFile(topic); err != nil {
+				return err
+			}
+		}
+
+		n, err := f.file.Write(line)
+		f.size += uint64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return f.file.Sync()
+}
+
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	errs := wrappers.Errs{}
+	for _, f := range s.files {
+		errs.Add(f.file.Close())
+	}
+	return errs.Err
+}
+
+// Assumes [s.lock] is held.
+func (s *fileSink) getFile(topic string) (*topicFile, error) {
+	if f, ok := s.files[topic]; ok {
+		return f, nil
+	}
+
+	file, err := os.OpenFile(s.path(topic), os.O_WRONLY|os.O_CREATE|os.O_APPEND, perms.ReadWrite)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	f := &topicFile{
+		file: file,
+		size: uint64(info.Size()),
+	}
+	s.files[topic] = f
+	return f, nil
+}
+
+// rotate renames the current file of [topic] and removes the oldest rotated
+// files of [topic].
+// Assumes [s.lock] is held.
+func (s *fileSink) rotate(topic string, f *topicFile) error {
+	delete(s.files, topic)
+	if err := f.file.Sync(); err != nil {
+		_ = f.file.Close()
+		return err
+	}
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+
+	// Rotated files must not be overwritten, even if the clock didn't advance
+	// since the last rotation.
+	timestamp := time.Now().UnixNano()
+	rotatedPath := s.rotatedPath(topic, timestamp)
+	for {
+		_, err := os.Stat(rotatedPath)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		timestamp++
+		rotatedPath = s.rotatedPath(topic, timestamp)
+	}
+	if err := os.Rename(s.path(topic), rotatedPath); err != nil {
+		return err
+	}
+	if s.maxFiles <= 0 {
+		return nil
+	}
+
+	rotatedPaths, err := filepath.Glob(filepath.Join(s.dir, topic+".*"+fileExtension))
+	if err != nil {
+		return err
+	}
+	if len(rotatedPaths) <= s.maxFiles {
+		return nil
+	}
+	// The file names only differ in their fixed length timestamps, so they
+	// are ordered by their age.
+	sort.Strings(rotatedPaths)
+	for _, path := range rotatedPaths[:len(rotatedPaths)-s.maxFiles] {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) path(topic string) string {
+	return filepath.Join(s.dir, topic+fileExtension)
+}
+
+func (s *fileSink) rotatedPath(topic string, timestamp int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.%d%s", topic, timestamp, fileExtension))
+}
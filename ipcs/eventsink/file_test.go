@@ -0,0 +1,62 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eventsink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	sink, err := NewFileSink(dir, 8, 2)
+	require.NoError(err)
+
+	publish := func(values ...string) {
+		msgs := make([]Message, len(values))
+		for i, value := range values {
+			msgs[i] = Message{Value: []byte(value)}
+		}
+		require.NoError(sink.Publish(context.Background(), "topic", msgs))
+	}
+	readFiles := func() []string {
+		paths, err := filepath.Glob(filepath.Join(dir, "topic*"))
+		require.NoError(err)
+		sort.Strings(paths)
+		contents := make([]string, len(paths))
+		for i, path := range paths {
+			content, err := os.ReadFile(path)
+			require.NoError(err)
+			contents[i] = string(content)
+		}
+		return contents
+	}
+
+	publish("a", "b", "c")
+	require.Equal([]string{"a\nb\nc\n"}, readFiles())
+
+	// The file is rotated, once it would grow beyond its maximum size.
+	publish("d", "e")
+	require.Equal([]string{"a\nb\nc\nd\n", "e\n"}, readFiles())
+
+	// Only the newest rotated files are kept.
+	publish("fffffff", "ggggggg", "h")
+	require.Equal([]string{"fffffff\n", "ggggggg\n", "h\n"}, readFiles())
+
+	// Writing continues in the current file after a restart.
+	require.NoError(sink.Close())
+	require.ErrorIs(sink.Publish(context.Background(), "topic", nil), errSinkClosed)
+	sink, err = NewFileSink(dir, 8, 2)
+	require.NoError(err)
+	publish("i")
+	require.Equal([]string{"fffffff\n", "ggggggg\n", "h\ni\n"}, readFiles())
+	require.NoError(sink.Close())
+}
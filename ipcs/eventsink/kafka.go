@@ -0,0 +1,226 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eventsink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// Maximum size of a request, that is sent to a broker
+	maxMessageSize = 64 * units.MiB
+
+	// Partition, that messages are produced to. Only a single partition is
+	// used, so the messages of a topic are totally ordered.
+	kafkaPartition = 0
+
+	defaultKafkaClientID = "camino-node"
+	defaultKafkaTimeout  = 10 * time.Second
+)
+
+var (
+	_ Sink = (*kafkaSink)(nil)
+
+	errNoBrokers           = errors.New("no brokers given")
+	errInvalidRequiredAcks = errors.New("required acks must be -1 or 1")
+)
+
+// KafkaConfig configures a sink, that produces messages to Kafka compatible
+// brokers.
+type KafkaConfig struct {
+	// Addresses (host:port) of the brokers, that are asked for the leaders of
+	// the topics
+	Brokers []string `json:"brokers"`
+	// Client ID sent to the brokers
+	ClientID string `json:"clientID"`
+	// Number of acknowledgements, that the leader must receive before
+	// responding. -1 waits for all in-sync replicas, 1 only for the leader.
+	RequiredAcks int16 `json:"requiredAcks"`
+	// Timeout of a request to a broker
+	Timeout time.Duration `json:"timeout"`
+}
+
+// kafkaSink produces the messages of each topic to partition 0 of the topic
+// on the leader of the partition. The leaders are looked up with metadata
+// requests to the configured brokers.
+type kafkaSink struct {
+	config KafkaConfig
+	dialer net.Dialer
+
+	lock   sync.Mutex
+	closed bool
+	// Correlation ID of the last request
+	correlationID int32
+	// Topic --> Leader of [kafkaPartition] of the topic
+	leaders map[string]kafkaBroker
+	// Broker address --> Connection to the broker
+	conns map[string]*kafkaConn
+}
+
+type kafkaConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewKafkaSink returns a sink, that produces messages to Kafka compatible
+// brokers.
+func NewKafkaSink(config KafkaConfig) (Sink, error) {
+	if len(config.Brokers) == 0 {
+		return nil, errNoBrokers
+	}
+	if config.RequiredAcks != -1 && config.RequiredAcks != 1 {
+		return nil, errInvalidRequiredAcks
+	}
+	if config.ClientID == "" {
+		config.ClientID = defaultKafkaClientID
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultKafkaTimeout
+	}
+	return &kafkaSink{
+		config:  config,
+		dialer:  net.Dialer{Timeout: config.Timeout},
+		leaders: map[string]kafkaBroker{},
+		conns:   map[string]*kafkaConn{},
+	}, nil
+}
+
+func (s *kafkaSink) Publish(ctx context.Context, topic string, msgs []Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return errSinkClosed
+	}
+
+	leader, err := s.getLeader(ctx, topic)
+	if err != nil {
+		return err
+	}
+	if err := s.produce(ctx, leader, topic, msgs); err != nil {
+		// The leader may have changed, so it's looked up again on the next
+		// attempt.
+		delete(s.leaders, topic)
+		return fmt.Errorf("couldn't produce to %s: %w", leader.addr(), err)
+	}
+	return nil
+}
+
+func (s *kafkaSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	errs := wrappers.Errs{}
+	for _, c := range s.conns {
+		errs.Add(c.conn.Close())
+	}
+	return errs.Err
+}
+
+// Assumes [s.lock] is held.
+func (s *kafkaSink) getLeader(ctx context.Context, topic string) (kafkaBroker, error) {
+	if leader, ok := s.leaders[topic]; ok {
+		return leader, nil
+	}
+
+	var lastErr error
+	for _, addr := range s.config.Brokers {
+		metadata, err := s.fetchMetadata(ctx, addr, topic)
+		if err != nil {
+			lastErr = fmt.Errorf("couldn't fetch metadata from %s: %w", addr, err)
+			continue
+		}
+		leader, err := metadata.leader(topic, kafkaPartition)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		s.leaders[topic] = leader
+		return leader, nil
+	}
+	return kafkaBroker{}, lastErr
+}
+
+// Assumes [s.lock] is held.
+func (s *kafkaSink) fetchMetadata(ctx context.Context, addr string, topic string) (*kafkaMetadata, error) {
+	s.correlationID++
+	request := newKafkaRequest(metadataAPIKey, metadataAPIVersion, s.correlationID, s.config.ClientID)
+	packMetadataRequest(request, topic)
+
+	response, err := s.roundTrip(ctx, addr, request, s.correlationID)
+	if err != nil {
+		return nil, err
+	}
+	return unpackMetadataResponse(response)
+}
+
+// Assumes [s.lock] is held.
+func (s *kafkaSink) produce(ctx context.Context, leader kafkaBroker, topic string, msgs []Message) error {
+	s.correlationID++
+	request := newKafkaRequest(produceAPIKey, produceAPIVersion, s.correlationID, s.config.ClientID)
+	records := newRecordBatch(msgs, time.Now().UnixMilli())
+	packProduceRequest(request, s.config.RequiredAcks, int32(s.config.Timeout.Milliseconds()), topic, kafkaPartition, records)
+
+	response, err := s.roundTrip(ctx, leader.addr(), request, s.correlationID)
+	if err != nil {
+		return err
+	}
+	return unpackProduceResponse(response, topic, kafkaPartition)
+}
+
+// roundTrip sends [request] to [addr] and returns the response. The
+// connection to [addr] is closed, if that fails.
+// Assumes [s.lock] is held.
+func (s *kafkaSink) roundTrip(ctx context.Context, addr string, request *wrappers.Packer, correlationID int32) (*wrappers.Packer, error) {
+	c, ok := s.conns[addr]
+	if !ok {
+		conn, err := s.dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		c = &kafkaConn{
+			conn:   conn,
+			reader: bufio.NewReader(conn),
+		}
+		s.conns[addr] = c
+	}
+
+	deadline := time.Now().Add(s.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	response, err := func() (*wrappers.Packer, error) {
+		if err := c.conn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+		if err := writeKafkaRequest(c.conn, request); err != nil {
+			return nil, err
+		}
+		return readKafkaResponse(c.reader, correlationID)
+	}()
+	if err != nil {
+		delete(s.conns, addr)
+		_ = c.conn.Close()
+		return nil, err
+	}
+	return response, nil
+}
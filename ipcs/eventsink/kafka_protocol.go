@@ -0,0 +1,308 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eventsink
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// The subset of the Kafka protocol, that is needed to produce messages to a
+// single partition of a topic. See https://kafka.apache.org/protocol.
+const (
+	produceAPIKey     = 0
+	produceAPIVersion = 3
+
+	metadataAPIKey     = 3
+	metadataAPIVersion = 1
+
+	recordBatchMagic = 2
+
+	// Maximum size of a response, that is read from a broker
+	maxResponseSize = 16 * units.MiB
+)
+
+var (
+	castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+	errResponseTooLarge       = errors.New("response too large")
+	errUnexpectedCorrelation  = errors.New("unexpected correlation ID")
+	errUnexpectedTopic        = errors.New("unexpected topic in response")
+	errUnknownPartitionLeader = errors.New("partition leader is unknown")
+)
+
+// kafkaError is an error code returned by a broker.
+type kafkaError int16
+
+func (e kafkaError) Error() string {
+	return fmt.Sprintf("kafka error code %d", int16(e))
+}
+
+type kafkaBroker struct {
+	nodeID int32
+	host   string
+	port   int32
+}
+
+func (b kafkaBroker) addr() string {
+	return fmt.Sprintf("%s:%d", b.host, b.port)
+}
+
+type kafkaPartitionMetadata struct {
+	errorCode int16
+	index     int32
+	leaderID  int32
+}
+
+type kafkaTopicMetadata struct {
+	errorCode  int16
+	name       string
+	partitions []kafkaPartitionMetadata
+}
+
+type kafkaMetadata struct {
+	brokers []kafkaBroker
+	topics  []kafkaTopicMetadata
+}
+
+// leader returns the broker, that leads [partition] of [topic].
+func (m *kafkaMetadata) leader(topic string, partition int32) (kafkaBroker, error) {
+	for _, t := range m.topics {
+		if t.name != topic {
+			continue
+		}
+		if t.errorCode != 0 {
+			return kafkaBroker{}, fmt.Errorf("couldn't get metadata of topic %q: %w", topic, kafkaError(t.errorCode))
+		}
+		for _, p := range t.partitions {
+			if p.index != partition {
+				continue
+			}
+			if p.errorCode != 0 {
+				return kafkaBroker{}, fmt.Errorf("couldn't get metadata of partition %d of topic %q: %w", partition, topic, kafkaError(p.errorCode))
+			}
+			for _, b := range m.brokers {
+				if b.nodeID == p.leaderID {
+					return b, nil
+				}
+			}
+		}
+	}
+	return kafkaBroker{}, fmt.Errorf("%w: topic %q, partition %d", errUnknownPartitionLeader, topic, partition)
+}
+
+// newKafkaRequest returns a packer, that contains the header of a request.
+// The size of the request is filled in by writeKafkaRequest.
+func newKafkaRequest(apiKey, apiVersion int16, correlationID int32, clientID string) *wrappers.Packer {
+	p := &wrappers.Packer{MaxSize: maxMessageSize}
+	p.PackInt(0) // size
+	p.PackShort(uint16(apiKey))
+	p.PackShort(uint16(apiVersion))
+	p.PackInt(uint32(correlationID))
+	p.PackStr(clientID)
+	return p
+}
+
+// writeKafkaRequest writes the request [p] to [w].
+func writeKafkaRequest(w io.Writer, p *wrappers.Packer) error {
+	if p.Err != nil {
+		return p.Err
+	}
+	binary.BigEndian.PutUint32(p.Bytes, uint32(p.Offset-wrappers.IntLen))
+	_, err := w.Write(p.Bytes[:p.Offset])
+	return err
+}
+
+// readKafkaResponse reads a response to the request [correlationID] from [r]
+// and returns a packer, that is positioned after the response header.
+func readKafkaResponse(r io.Reader, correlationID int32) (*wrappers.Packer, error) {
+	response, err := readKafkaFrame(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &wrappers.Packer{Bytes: response}
+	if gotCorrelationID := int32(p.UnpackInt()); p.Err == nil && gotCorrelationID != correlationID {
+		return nil, fmt.Errorf("%w: expected %d, got %d", errUnexpectedCorrelation, correlationID, gotCorrelationID)
+	}
+	return p, p.Err
+}
+
+// readKafkaFrame reads a size delimited request or response from [r].
+func readKafkaFrame(r io.Reader) ([]byte, error) {
+	sizeBytes := make([]byte, wrappers.IntLen)
+	if _, err := io.ReadFull(r, sizeBytes); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(sizeBytes)
+	if size > maxResponseSize {
+		return nil, fmt.Errorf("%w: %d bytes", errResponseTooLarge, size)
+	}
+	frame := make([]byte, size)
+	_, err := io.ReadFull(r, frame)
+	return frame, err
+}
+
+// packMetadataRequest packs the body of a metadata request of [topic].
+func packMetadataRequest(p *wrappers.Packer, topic string) {
+	p.PackInt(1)
+	p.PackStr(topic)
+}
+
+func unpackMetadataResponse(p *wrappers.Packer) (*kafkaMetadata, error) {
+	m := &kafkaMetadata{}
+	numBrokers := p.UnpackInt()
+	for i := uint32(0); i < numBrokers && !p.Errored(); i++ {
+		m.brokers = append(m.brokers, kafkaBroker{
+			nodeID: int32(p.UnpackInt()),
+			host:   p.UnpackStr(),
+			port:   int32(p.UnpackInt()),
+		})
+		unpackNullableStr(p) // rack
+	}
+	p.UnpackInt() // controller ID
+	numTopics := p.UnpackInt()
+	for i := uint32(0); i < numTopics && !p.Errored(); i++ {
+		topic := kafkaTopicMetadata{
+			errorCode: int16(p.UnpackShort()),
+			name:      p.UnpackStr(),
+		}
+		p.UnpackBool() // is internal
+		numPartitions := p.UnpackInt()
+		for j := uint32(0); j < numPartitions && !p.Errored(); j++ {
+			topic.partitions = append(topic.partitions, kafkaPartitionMetadata{
+				errorCode: int16(p.UnpackShort()),
+				index:     int32(p.UnpackInt()),
+				leaderID:  int32(p.UnpackInt()),
+			})
+			unpackInt32Array(p) // replica nodes
+			unpackInt32Array(p) // in-sync replica nodes
+		}
+		m.topics = append(m.topics, topic)
+	}
+	return m, p.Err
+}
+
+// packProduceRequest packs the body of a request to append [records] to
+// [partition] of [topic].
+func packProduceRequest(p *wrappers.Packer, requiredAcks int16, timeoutMS int32, topic string, partition int32, records []byte) {
+	p.PackShort(0xFFFF) // null transactional ID
+	p.PackShort(uint16(requiredAcks))
+	p.PackInt(uint32(timeoutMS))
+	p.PackInt(1)
+	p.PackStr(topic)
+	p.PackInt(1)
+	p.PackInt(uint32(partition))
+	p.PackBytes(records)
+}
+
+// unpackProduceResponse returns the error returned for [partition] of
+// [topic].
+func unpackProduceResponse(p *wrappers.Packer, topic string, partition int32) error {
+	numTopics := p.UnpackInt()
+	for i := uint32(0); i < numTopics && !p.Errored(); i++ {
+		name := p.UnpackStr()
+		numPartitions := p.UnpackInt()
+		for j := uint32(0); j < numPartitions && !p.Errored(); j++ {
+			index := int32(p.UnpackInt())
+			errorCode := int16(p.UnpackShort())
+			p.UnpackLong() // base offset
+			p.UnpackLong() // log append time
+			if p.Errored() || name != topic || index != partition {
+				continue
+			}
+			if errorCode != 0 {
+				return kafkaError(errorCode)
+			}
+			return nil
+		}
+	}
+	if p.Err != nil {
+		return p.Err
+	}
+	return fmt.Errorf("%w: expected %q", errUnexpectedTopic, topic)
+}
+
+// newRecordBatch returns an uncompressed record batch of [msgs] with the
+// timestamp [timestampMS].
+func newRecordBatch(msgs []Message, timestampMS int64) []byte {
+	var records []byte
+	for i, msg := range msgs {
+		var record []byte
+		record = append(record, 0)                     // attributes
+		record = binary.AppendVarint(record, 0)        // timestamp delta
+		record = binary.AppendVarint(record, int64(i)) // offset delta
+		record = appendVarintBytes(record, msg.Key)
+		record = appendVarintBytes(record, msg.Value)
+		record = binary.AppendVarint(record, 0) // number of headers
+
+		records = binary.AppendVarint(records, int64(len(record)))
+		records = append(records, record...)
+	}
+
+	const (
+		// Length of the base offset and the batch length, which aren't
+		// included in the batch length
+		headerLen = wrappers.LongLen + wrappers.IntLen
+		// Offset of the CRC
+		crcOffset = headerLen + wrappers.IntLen + wrappers.ByteLen
+		// Length of all fields before the records
+		recordsOffset = crcOffset + wrappers.IntLen + wrappers.ShortLen + wrappers.IntLen +
+			2*wrappers.LongLen + wrappers.LongLen + wrappers.ShortLen + 2*wrappers.IntLen
+	)
+	batch := make([]byte, recordsOffset, recordsOffset+len(records))
+	binary.BigEndian.PutUint64(batch, 0) // base offset
+	binary.BigEndian.PutUint32(batch[wrappers.LongLen:], uint32(recordsOffset+len(records)-headerLen))
+	binary.BigEndian.PutUint32(batch[headerLen:], 0xFFFFFFFF) // partition leader epoch
+	batch[headerLen+wrappers.IntLen] = recordBatchMagic
+	offset := crcOffset + wrappers.IntLen
+	binary.BigEndian.PutUint16(batch[offset:], 0) // attributes
+	offset += wrappers.ShortLen
+	binary.BigEndian.PutUint32(batch[offset:], uint32(len(msgs)-1)) // last offset delta
+	offset += wrappers.IntLen
+	binary.BigEndian.PutUint64(batch[offset:], uint64(timestampMS)) // base timestamp
+	offset += wrappers.LongLen
+	binary.BigEndian.PutUint64(batch[offset:], uint64(timestampMS)) // max timestamp
+	offset += wrappers.LongLen
+	binary.BigEndian.PutUint64(batch[offset:], 0xFFFFFFFFFFFFFFFF) // producer ID
+	offset += wrappers.LongLen
+	binary.BigEndian.PutUint16(batch[offset:], 0xFFFF) // producer epoch
+	offset += wrappers.ShortLen
+	binary.BigEndian.PutUint32(batch[offset:], 0xFFFFFFFF) // base sequence
+	offset += wrappers.IntLen
+	binary.BigEndian.PutUint32(batch[offset:], uint32(len(msgs))) // number of records
+	batch = append(batch, records...)
+
+	crc := crc32.Checksum(batch[crcOffset+wrappers.IntLen:], castagnoliTable)
+	binary.BigEndian.PutUint32(batch[crcOffset:], crc)
+	return batch
+}
+
+func appendVarintBytes(b []byte, value []byte) []byte {
+	if value == nil {
+		return binary.AppendVarint(b, -1)
+	}
+	b = binary.AppendVarint(b, int64(len(value)))
+	return append(b, value...)
+}
+
+func unpackNullableStr(p *wrappers.Packer) {
+	size := int16(p.UnpackShort())
+	if size > 0 {
+		p.UnpackFixedBytes(int(size))
+	}
+}
+
+func unpackInt32Array(p *wrappers.Packer) {
+	size := int32(p.UnpackInt())
+	for i := int32(0); i < size && !p.Errored(); i++ {
+		p.UnpackInt()
+	}
+}
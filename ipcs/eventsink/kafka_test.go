@@ -0,0 +1,269 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eventsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const notLeaderForPartition = 6
+
+var errInvalidRecordBatch = errors.New("invalid record batch")
+
+// testBroker is a stand-in for a Kafka broker, that leads partition 0 of all
+// topics and supports the requests, that are sent by the kafka sink.
+type testBroker struct {
+	t        *testing.T
+	listener net.Listener
+	host     string
+	port     int32
+
+	lock sync.Mutex
+	// Topic --> Values of the records of partition 0 of the topic
+	topics map[string][][]byte
+	// Error code returned for the next produce requests
+	produceErrors []int16
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, portStr, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.ParseInt(portStr, 10, 32)
+	require.NoError(t, err)
+
+	b := &testBroker{
+		t:        t,
+		listener: listener,
+		host:     host,
+		port:     int32(port),
+		topics:   map[string][][]byte{},
+	}
+	go b.serve()
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	return b
+}
+
+func (b *testBroker) addr() string {
+	return b.listener.Addr().String()
+}
+
+func (b *testBroker) records(topic string) [][]byte {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.topics[topic]
+}
+
+func (b *testBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *testBroker) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		requestBytes, err := readKafkaFrame(reader)
+		if err != nil {
+			return
+		}
+		request := &wrappers.Packer{Bytes: requestBytes}
+		apiKey := int16(request.UnpackShort())
+		apiVersion := int16(request.UnpackShort())
+		correlationID := request.UnpackInt()
+		request.UnpackStr() // client ID
+
+		response := &wrappers.Packer{MaxSize: maxMessageSize}
+		response.PackInt(0) // size
+		response.PackInt(correlationID)
+		switch {
+		case apiKey == metadataAPIKey && apiVersion == metadataAPIVersion:
+			b.handleMetadata(request, response)
+		case apiKey == produceAPIKey && apiVersion == produceAPIVersion:
+			b.handleProduce(request, response)
+		default:
+			return
+		}
+		if request.Err != nil {
+			return
+		}
+		if err := writeKafkaRequest(conn, response); err != nil {
+			return
+		}
+	}
+}
+
+func (b *testBroker) handleMetadata(request, response *wrappers.Packer) {
+	numTopics := request.UnpackInt()
+	response.PackInt(1)
+	response.PackInt(0) // node ID
+	response.PackStr(b.host)
+	response.PackInt(uint32(b.port))
+	response.PackShort(0xFFFF) // rack
+	response.PackInt(0)        // controller ID
+	response.PackInt(numTopics)
+	for i := uint32(0); i < numTopics; i++ {
+		response.PackShort(0) // error code
+		response.PackStr(request.UnpackStr())
+		response.PackBool(false) // is internal
+		response.PackInt(1)
+		response.PackShort(0) // error code
+		response.PackInt(0)   // partition index
+		response.PackInt(0)   // leader ID
+		response.PackInt(1)   // replica nodes
+		response.PackInt(0)
+		response.PackInt(1) // in-sync replica nodes
+		response.PackInt(0)
+	}
+}
+
+func (b *testBroker) handleProduce(request, response *wrappers.Packer) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	request.UnpackShort() // transactional ID
+	request.UnpackShort() // acks
+	request.UnpackInt()   // timeout
+	require.Equal(b.t, uint32(1), request.UnpackInt())
+	topic := request.UnpackStr()
+	require.Equal(b.t, uint32(1), request.UnpackInt())
+	partition := request.UnpackInt()
+	values, err := parseRecordBatch(request.UnpackBytes())
+	require.NoError(b.t, err)
+
+	var errorCode int16
+	if len(b.produceErrors) > 0 {
+		errorCode = b.produceErrors[0]
+		b.produceErrors = b.produceErrors[1:]
+	}
+	baseOffset := len(b.topics[topic])
+	if errorCode == 0 {
+		b.topics[topic] = append(b.topics[topic], values...)
+	}
+
+	response.PackInt(1)
+	response.PackStr(topic)
+	response.PackInt(1)
+	response.PackInt(partition)
+	response.PackShort(uint16(errorCode))
+	response.PackLong(uint64(baseOffset))
+	response.PackLong(0xFFFFFFFFFFFFFFFF) // log append time
+	response.PackInt(0)                   // throttle time
+}
+
+// parseRecordBatch returns the values of the records of [batch].
+func parseRecordBatch(batch []byte) ([][]byte, error) {
+	p := &wrappers.Packer{Bytes: batch}
+	p.UnpackLong() // base offset
+	batchLength := p.UnpackInt()
+	p.UnpackInt() // partition leader epoch
+	magic := p.UnpackByte()
+	crc := p.UnpackInt()
+	if p.Errored() || int(batchLength) != len(batch)-12 || magic != recordBatchMagic ||
+		crc != crc32.Checksum(batch[p.Offset:], castagnoliTable) {
+		return nil, errInvalidRecordBatch
+	}
+	p.UnpackShort() // attributes
+	p.UnpackInt()   // last offset delta
+	p.UnpackLong()  // base timestamp
+	p.UnpackLong()  // max timestamp
+	p.UnpackLong()  // producer ID
+	p.UnpackShort() // producer epoch
+	p.UnpackInt()   // base sequence
+	numRecords := p.UnpackInt()
+	if p.Errored() {
+		return nil, p.Err
+	}
+
+	records := batch[p.Offset:]
+	readVarint := func() int64 {
+		value, n := binary.Varint(records)
+		records = records[n:]
+		return value
+	}
+	values := make([][]byte, numRecords)
+	for i := range values {
+		readVarint()          // length
+		records = records[1:] // attributes
+		readVarint()          // timestamp delta
+		if offsetDelta := readVarint(); offsetDelta != int64(i) {
+			return nil, errInvalidRecordBatch
+		}
+		if keyLen := readVarint(); keyLen > 0 {
+			records = records[keyLen:]
+		}
+		valueLen := readVarint()
+		values[i] = records[:valueLen]
+		records = records[valueLen:]
+		readVarint() // number of headers
+	}
+	return values, nil
+}
+
+func TestKafkaSink(t *testing.T) {
+	require := require.New(t)
+
+	broker := newTestBroker(t)
+	sink, err := NewKafkaSink(KafkaConfig{
+		Brokers:      []string{broker.addr()},
+		RequiredAcks: -1,
+		Timeout:      5 * time.Second,
+	})
+	require.NoError(err)
+
+	ctx := context.Background()
+	require.NoError(sink.Publish(ctx, "topic", []Message{
+		{Key: []byte("1"), Value: []byte("a")},
+		{Value: []byte("b")},
+	}))
+	require.NoError(sink.Publish(ctx, "topic", []Message{{Value: []byte("c")}}))
+	require.NoError(sink.Publish(ctx, "other", []Message{{Value: []byte("d")}}))
+	require.Equal([][]byte{[]byte("a"), []byte("b"), []byte("c")}, broker.records("topic"))
+	require.Equal([][]byte{[]byte("d")}, broker.records("other"))
+
+	// Errors returned by the broker are returned and publishing can be
+	// retried.
+	broker.lock.Lock()
+	broker.produceErrors = []int16{notLeaderForPartition}
+	broker.lock.Unlock()
+	err = sink.Publish(ctx, "topic", []Message{{Value: []byte("e")}})
+	require.ErrorIs(err, kafkaError(notLeaderForPartition))
+	require.NoError(sink.Publish(ctx, "topic", []Message{{Value: []byte("e")}}))
+	require.Equal([][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("e")}, broker.records("topic"))
+
+	require.NoError(sink.Close())
+	require.ErrorIs(sink.Publish(ctx, "topic", []Message{{Value: []byte("f")}}), errSinkClosed)
+}
+
+func TestNewKafkaSinkInvalidConfig(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewKafkaSink(KafkaConfig{RequiredAcks: 1})
+	require.ErrorIs(err, errNoBrokers)
+	_, err = NewKafkaSink(KafkaConfig{Brokers: []string{"127.0.0.1:9092"}})
+	require.ErrorIs(err, errInvalidRequiredAcks)
+}
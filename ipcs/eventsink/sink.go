@@ -0,0 +1,28 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package eventsink
+
+import (
+	"context"
+	"io"
+)
+
+// Message is a single event written to a sink.
+type Message struct {
+	// Key of the message. May be nil.
+	Key []byte
+	// Value of the message. Must not contain newlines for line based sinks.
+	Value []byte
+}
+
+// Sink is a destination of events. Sinks must be safe for concurrent use.
+type Sink interface {
+	// Publish writes [msgs] to [topic] in order. Publish only returns nil
+	// after all [msgs] were durably written. If an error is returned, some of
+	// [msgs] may have been written, so publishing them again may result in
+	// duplicates.
+	Publish(ctx context.Context, topic string, msgs []Message) error
+
+	io.Closer
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+// Class groups ops by how urgently their messages must be delivered. The
+// transport derives the stream and the queue priority of a message from its
+// class, so that both agree on how a message is treated.
+type Class int
+
+const (
+	// ControlClass contains the handshake and every op that isn't assigned to
+	// a more specific class.
+	ControlClass Class = iota
+	// ConsensusClass contains queries and chits.
+	ConsensusClass
+	// AcceptanceClass contains the gossip and requests of accepted
+	// containers.
+	AcceptanceClass
+	// AppClass contains the messages of the VMs.
+	AppClass
+	// BulkClass contains the bulk data requested while bootstrapping and state
+	// syncing.
+	BulkClass
+)
+
+func (c Class) String() string {
+	switch c {
+	case ControlClass:
+		return "control"
+	case ConsensusClass:
+		return "consensus"
+	case AcceptanceClass:
+		return "acceptance"
+	case AppClass:
+		return "app"
+	case BulkClass:
+		return "bulk"
+	default:
+		return "unknown"
+	}
+}
+
+// ClassOf returns the class of messages with [op]
+func ClassOf(op Op) Class {
+	switch op {
+	case PushQueryOp,
+		PullQueryOp,
+		ChitsOp:
+		return ConsensusClass
+	case GetAcceptedFrontierOp,
+		AcceptedFrontierOp,
+		GetAcceptedOp,
+		AcceptedOp,
+		GetOp,
+		PutOp:
+		return AcceptanceClass
+	case AppRequestOp,
+		AppResponseOp,
+		AppGossipOp,
+		CrossChainAppRequestOp,
+		CrossChainAppResponseOp:
+		return AppClass
+	case GetStateSummaryFrontierOp,
+		StateSummaryFrontierOp,
+		GetAcceptedStateSummaryOp,
+		AcceptedStateSummaryOp,
+		GetAncestorsOp,
+		AncestorsOp:
+		return BulkClass
+	default:
+		return ControlClass
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassOf(t *testing.T) {
+	tests := map[Op]Class{
+		VersionOp:                   ControlClass,
+		PingOp:                      ControlClass,
+		GetAcceptedFrontierFailedOp: ControlClass,
+		PushQueryOp:                 ConsensusClass,
+		PullQueryOp:                 ConsensusClass,
+		ChitsOp:                     ConsensusClass,
+		GetOp:                       AcceptanceClass,
+		PutOp:                       AcceptanceClass,
+		AppGossipOp:                 AppClass,
+		CrossChainAppResponseOp:     AppClass,
+		GetAncestorsOp:              BulkClass,
+		AncestorsOp:                 BulkClass,
+		StateSummaryFrontierOp:      BulkClass,
+	}
+	for op, expectedClass := range tests {
+		t.Run(op.String(), func(t *testing.T) {
+			require.Equal(t, expectedClass, ClassOf(op))
+		})
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package message
+
+import (
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/utils/ips"
+)
+
+func encodeIPPorts(ipPorts []ips.IPPort) []*p2p.IpPort {
+	if len(ipPorts) == 0 {
+		return nil
+	}
+	result := make([]*p2p.IpPort, len(ipPorts))
+	for i, ipPort := range ipPorts {
+		result[i] = &p2p.IpPort{
+			IpAddr: ipPort.IP.To16(),
+			IpPort: uint32(ipPort.Port),
+		}
+	}
+	return result
+}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -49,6 +59,9 @@ type InboundMessage interface {
 	// BytesSavedCompression returns the number of bytes that this message saved
 	// due to being compressed
 	BytesSavedCompression() int
+	// BytesLen returns the number of bytes this message had on the wire. It
+	// is 0 for messages that weren't received from the network.
+	BytesLen() int
 }
 
 type inboundMessage struct {
@@ -58,6 +71,7 @@ type inboundMessage struct {
 	expiration            time.Time
 	onFinishedHandling    func()
 	bytesSavedCompression int
+	bytesLen              int
 }
 
 func (m *inboundMessage) NodeID() ids.NodeID {
@@ -86,6 +100,10 @@ func (m *inboundMessage) BytesSavedCompression() int {
 	return m.bytesSavedCompression
 }
 
+func (m *inboundMessage) BytesLen() int {
+	return m.bytesLen
+}
+
 // OutboundMessage represents a set of fields for an outbound message that can
 // be serialized into a byte stream
 type OutboundMessage interface {
@@ -377,5 +395,6 @@ func (mb *msgBuilder) parseInbound(
 		expiration:            expiration,
 		onFinishedHandling:    onFinishedHandling,
 		bytesSavedCompression: bytesSavedCompression,
+		bytesLen:              len(bytes),
 	}, nil
 }
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -26,6 +36,9 @@ type OutboundMsgBuilder interface {
 		myVersionTime uint64,
 		sig []byte,
 		trackedSubnets []ids.ID,
+		quicPort uint16,
+		extraIPs []ips.IPPort,
+		extraSig []byte,
 	) (OutboundMessage, error)
 
 	PeerList(
@@ -220,6 +233,9 @@ func (b *outMsgBuilder) Version(
 	myVersionTime uint64,
 	sig []byte,
 	trackedSubnets []ids.ID,
+	quicPort uint16,
+	extraIPs []ips.IPPort,
+	extraSig []byte,
 ) (OutboundMessage, error) {
 	subnetIDBytes := make([][]byte, len(trackedSubnets))
 	encodeIDs(trackedSubnets, subnetIDBytes)
@@ -235,6 +251,9 @@ func (b *outMsgBuilder) Version(
 					MyVersionTime:  myVersionTime,
 					Sig:            sig,
 					TrackedSubnets: subnetIDBytes,
+					QuicPort:       uint32(quicPort),
+					ExtraIpPorts:   encodeIPPorts(extraIPs),
+					ExtraSig:       extraSig,
 				},
 			},
 		},
@@ -253,6 +272,8 @@ func (b *outMsgBuilder) PeerList(peers []ips.ClaimedIPPort, bypassThrottling boo
 			Timestamp:       p.Timestamp,
 			Signature:       p.Signature,
 			TxId:            p.TxID[:],
+			ExtraIpPorts:    encodeIPPorts(p.ExtraIPPorts),
+			ExtraSignature:  p.ExtraSignature,
 		}
 	}
 	return b.builder.createOutbound(
@@ -0,0 +1,305 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/utils/ips"
+)
+
+var (
+	banDBPrefix            = []byte("ban")
+	persistentPeerDBPrefix = []byte("persistentPeer")
+
+	errNotBanned = errors.New("not banned")
+	errNotPeer   = errors.New("not a peer")
+)
+
+// Ban prevents this node from connecting to [NodeID] or, if [NodeID] is nil,
+// to any peer at [IP]. A ban without [Expiry] lasts until it is removed.
+type Ban struct {
+	NodeID *ids.NodeID `json:"nodeID,omitempty"`
+	IP     net.IP      `json:"ip,omitempty"`
+	Reason string      `json:"reason"`
+	Expiry *time.Time  `json:"expiry,omitempty"`
+}
+
+func (b *Ban) expired(now time.Time) bool {
+	return b.Expiry != nil && !now.Before(*b.Expiry)
+}
+
+// Node IDs and IPs are keyed by their raw bytes. As node IDs are 20 bytes
+// long and IPs are normalized to 16 bytes, the keys can't collide.
+func nodeBanKey(nodeID ids.NodeID) string {
+	return string(nodeID[:])
+}
+
+func ipBanKey(ip net.IP) string {
+	return string(ip.To16())
+}
+
+func (b *Ban) key() string {
+	if b.NodeID != nil {
+		return nodeBanKey(*b.NodeID)
+	}
+	return ipBanKey(b.IP)
+}
+
+// banList is a persisted set of bans. Expired bans are ignored and removed
+// when the bans are listed.
+type banList struct {
+	db   database.Database
+	lock sync.RWMutex
+	bans map[string]*Ban
+}
+
+func newBanList(db database.Database) (*banList, error) {
+	b := &banList{
+		db:   db,
+		bans: make(map[string]*Ban),
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		ban := &Ban{}
+		if err := json.Unmarshal(it.Value(), ban); err != nil {
+			return nil, err
+		}
+		b.bans[ban.key()] = ban
+	}
+	return b, it.Error()
+}
+
+func (b *banList) add(ban *Ban) error {
+	banBytes, err := json.Marshal(ban)
+	if err != nil {
+		return err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	key := ban.key()
+	if err := b.db.Put([]byte(key), banBytes); err != nil {
+		return err
+	}
+	b.bans[key] = ban
+	return nil
+}
+
+func (b *banList) remove(key string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if _, ok := b.bans[key]; !ok {
+		return errNotBanned
+	}
+	if err := b.db.Delete([]byte(key)); err != nil {
+		return err
+	}
+	delete(b.bans, key)
+	return nil
+}
+
+func (b *banList) get(key string, now time.Time) (*Ban, bool) {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	ban, ok := b.bans[key]
+	if !ok || ban.expired(now) {
+		return nil, false
+	}
+	return ban, true
+}
+
+func (b *banList) list(now time.Time) ([]Ban, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	bans := make([]Ban, 0, len(b.bans))
+	for key, ban := range b.bans {
+		if !ban.expired(now) {
+			bans = append(bans, *ban)
+			continue
+		}
+		if err := b.db.Delete([]byte(key)); err != nil {
+			return nil, err
+		}
+		delete(b.bans, key)
+	}
+	return bans, nil
+}
+
+// persistentPeer is the database entry of a peer added by AddPersistentPeer
+type persistentPeer struct {
+	NodeID ids.NodeID `json:"nodeID"`
+	IP     string     `json:"ip"`
+}
+
+// initPeerDB loads the bans and persistent peers of [db] into the network
+func (n *network) initPeerDB(db database.Database) error {
+	bans, err := newBanList(prefixdb.New(banDBPrefix, db))
+	if err != nil {
+		return err
+	}
+	n.bans = bans
+	n.persistentPeerDB = prefixdb.New(persistentPeerDBPrefix, db)
+
+	it := n.persistentPeerDB.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		p := &persistentPeer{}
+		if err := json.Unmarshal(it.Value(), p); err != nil {
+			return err
+		}
+		ip, err := ips.ToIPPort(p.IP)
+		if err != nil {
+			return err
+		}
+		n.ManuallyTrack(p.NodeID, ip)
+	}
+	return it.Error()
+}
+
+func (n *network) isNodeBanned(nodeID ids.NodeID) bool {
+	_, banned := n.bans.get(nodeBanKey(nodeID), n.peerConfig.Clock.Time())
+	return banned
+}
+
+func (n *network) isIPBanned(ip net.IP) bool {
+	_, banned := n.bans.get(ipBanKey(ip), n.peerConfig.Clock.Time())
+	return banned
+}
+
+func (n *network) BanNode(nodeID ids.NodeID, reason string, expiry *time.Time) error {
+	if err := n.bans.add(&Ban{
+		NodeID: &nodeID,
+		Reason: reason,
+		Expiry: expiry,
+	}); err != nil {
+		return err
+	}
+
+	n.peerConfig.Log.Info("banned node",
+		zap.Stringer("nodeID", nodeID),
+		zap.String("reason", reason),
+	)
+
+	// Outbound connection attempts to [nodeID] stop on their own, as the node
+	// is no longer wanted.
+	_ = n.DisconnectPeer(nodeID)
+	return nil
+}
+
+func (n *network) BanIP(ip net.IP, reason string, expiry *time.Time) error {
+	if err := n.bans.add(&Ban{
+		IP:     ip,
+		Reason: reason,
+		Expiry: expiry,
+	}); err != nil {
+		return err
+	}
+
+	n.peerConfig.Log.Info("banned IP",
+		zap.Stringer("ip", ip),
+		zap.String("reason", reason),
+	)
+
+	// Peers that are still connecting had their IP checked when their
+	// connection was upgraded.
+	n.peersLock.RLock()
+	peers := peersAt(n.connectedPeers, ip)
+	n.peersLock.RUnlock()
+
+	for _, peer := range peers {
+		peer.StartClose()
+	}
+	return nil
+}
+
+func (n *network) UnbanNode(nodeID ids.NodeID) error {
+	return n.bans.remove(nodeBanKey(nodeID))
+}
+
+func (n *network) UnbanIP(ip net.IP) error {
+	return n.bans.remove(ipBanKey(ip))
+}
+
+func (n *network) Bans() ([]Ban, error) {
+	return n.bans.list(n.peerConfig.Clock.Time())
+}
+
+func (n *network) DisconnectPeer(nodeID ids.NodeID) error {
+	n.peersLock.RLock()
+	peer, ok := n.connectedPeers.GetByID(nodeID)
+	if !ok {
+		peer, ok = n.connectingPeers.GetByID(nodeID)
+	}
+	n.peersLock.RUnlock()
+
+	if !ok {
+		return errNotPeer
+	}
+	peer.StartClose()
+	return nil
+}
+
+func (n *network) AddPersistentPeer(nodeID ids.NodeID, ip ips.IPPort) error {
+	peerBytes, err := json.Marshal(&persistentPeer{
+		NodeID: nodeID,
+		IP:     ip.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := n.persistentPeerDB.Put(nodeID[:], peerBytes); err != nil {
+		return err
+	}
+
+	n.ManuallyTrack(nodeID, ip)
+	return nil
+}
+
+// peersAt returns the peers of [peers] that connected from, or claim to be
+// reachable at, [ip].
+func peersAt(peers peer.Set, ip net.IP) []peer.Peer {
+	var matches []peer.Peer
+	for i := 0; i < peers.Len(); i++ {
+		p, _ := peers.GetByIndex(i)
+		if signedIP := p.IP(); signedIP != nil && signedIP.IPPort.IP.Equal(ip) {
+			matches = append(matches, p)
+			continue
+		}
+		if remoteIP, err := ips.ToIPPort(p.Info().IP); err == nil && remoteIP.IP.Equal(ip) {
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
+
+// remoteIP returns the IP [conn] was established with, if it is known
+func remoteIP(conn net.Conn) (net.IP, bool) {
+	switch addr := conn.RemoteAddr().(type) {
+	case *net.TCPAddr:
+		return addr.IP, true
+	case *net.UDPAddr:
+		return addr.IP, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/ips"
+)
+
+func TestBanList(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Unix(1_000_000, 0)
+	expiry := now.Add(time.Hour)
+	nodeID := ids.GenerateTestNodeID()
+	ip := net.IPv4(1, 2, 3, 4)
+
+	db := memdb.New()
+	bans, err := newBanList(db)
+	require.NoError(err)
+	require.NoError(bans.add(&Ban{NodeID: &nodeID, Reason: "spam"}))
+	require.NoError(bans.add(&Ban{IP: ip, Reason: "dos", Expiry: &expiry}))
+
+	nodeBan, banned := bans.get(nodeBanKey(nodeID), now)
+	require.True(banned)
+	require.Equal("spam", nodeBan.Reason)
+	_, banned = bans.get(ipBanKey(ip.To4()), now)
+	require.True(banned)
+	_, banned = bans.get(ipBanKey(ip), expiry)
+	require.False(banned)
+
+	// Bans are loaded from the database
+	bans, err = newBanList(db)
+	require.NoError(err)
+	list, err := bans.list(now)
+	require.NoError(err)
+	require.Len(list, 2)
+
+	// Expired bans are removed when listed
+	list, err = bans.list(expiry)
+	require.NoError(err)
+	require.Len(list, 1)
+	require.Equal(nodeID, *list[0].NodeID)
+
+	bans, err = newBanList(db)
+	require.NoError(err)
+	require.ErrorIs(bans.remove(ipBanKey(ip)), errNotBanned)
+	require.NoError(bans.remove(nodeBanKey(nodeID)))
+	_, banned = bans.get(nodeBanKey(nodeID), now)
+	require.False(banned)
+}
+
+func TestBanNodeDisconnects(t *testing.T) {
+	require := require.New(t)
+
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil, nil})
+
+	net0 := networks[0]
+	require.NoError(net0.BanNode(nodeIDs[1], "spam", nil))
+	require.Eventually(
+		func() bool {
+			return len(net0.PeerInfo(nil)) == 0
+		},
+		10*time.Second,
+		50*time.Millisecond,
+	)
+	require.False(net0.AllowConnection(nodeIDs[1]))
+	require.False(net0.WantsConnection(nodeIDs[1]))
+	require.ErrorIs(net0.DisconnectPeer(nodeIDs[1]), errNotPeer)
+
+	bans, err := net0.Bans()
+	require.NoError(err)
+	require.Equal([]Ban{{NodeID: &nodeIDs[1], Reason: "spam"}}, bans)
+
+	require.NoError(net0.UnbanNode(nodeIDs[1]))
+	require.True(net0.AllowConnection(nodeIDs[1]))
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestTrackIgnoresBannedIPs(t *testing.T) {
+	require := require.New(t)
+
+	_, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil})
+
+	network := networks[0].(*network)
+	nodeID, tlsCert, _ := getTLS(t, 1)
+	err := validators.Add(network.config.Validators, constants.PrimaryNetworkID, nodeID, nil, ids.Empty, 1)
+	require.NoError(err)
+
+	claimedIP := func(timestamp uint64) *ips.ClaimedIPPort {
+		unsignedIP := &peer.UnsignedIP{
+			IPPort: ips.IPPort{
+				IP:   net.IPv4(123, 132, 123, 123),
+				Port: 10000,
+			},
+			Timestamp: timestamp,
+		}
+		signedIP, err := unsignedIP.Sign(tlsCert.PrivateKey.(crypto.Signer))
+		require.NoError(err)
+		return &ips.ClaimedIPPort{
+			Cert:      tlsCert.Leaf,
+			IPPort:    signedIP.IPPort,
+			Timestamp: signedIP.Timestamp,
+			Signature: signedIP.Signature,
+		}
+	}
+
+	require.NoError(network.BanIP(net.IPv4(123, 132, 123, 123), "dos", nil))
+	_, err = network.Track(ids.EmptyNodeID, []*ips.ClaimedIPPort{claimedIP(1000)})
+	require.NoError(err)
+
+	network.peersLock.RLock()
+	require.Empty(network.peerIPs)
+	require.Empty(network.trackedIPs)
+	network.peersLock.RUnlock()
+
+	require.NoError(network.UnbanIP(net.IPv4(123, 132, 123, 123)))
+	_, err = network.Track(ids.EmptyNodeID, []*ips.ClaimedIPPort{claimedIP(1001)})
+	require.NoError(err)
+
+	network.peersLock.RLock()
+	require.Contains(network.trackedIPs, nodeID)
+	network.peersLock.RUnlock()
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// allowedInConsortium returns true if [nodeID] may connect to this node. If
+// the network is restricted to the consortium, only current and pending
+// validators of the primary network, explicitly allowed nodes, beacons and
+// manually tracked nodes may connect. Beacons and manually tracked nodes,
+// like bootstrappers, are exempt so that a node whose bootstrappers aren't
+// validators in its genesis can still bootstrap.
+func (n *network) allowedInConsortium(nodeID ids.NodeID) bool {
+	if !n.config.ConsortiumOnly ||
+		validators.Contains(n.config.Validators, constants.PrimaryNetworkID, nodeID) ||
+		(n.config.PendingValidators != nil && n.config.PendingValidators.Contains(nodeID)) ||
+		(n.config.Beacons != nil && n.config.Beacons.Contains(nodeID)) ||
+		n.config.AllowedNodeIDs.Contains(nodeID) {
+		return true
+	}
+
+	n.consortiumExemptLock.RLock()
+	defer n.consortiumExemptLock.RUnlock()
+
+	return n.consortiumExemptIDs.Contains(nodeID)
+}
+
+// exemptFromConsortium allows [nodeID] to connect, even if it isn't in the
+// consortium.
+func (n *network) exemptFromConsortium(nodeID ids.NodeID) {
+	n.consortiumExemptLock.Lock()
+	defer n.consortiumExemptLock.Unlock()
+
+	n.consortiumExemptIDs.Add(nodeID)
+}
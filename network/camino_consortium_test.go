@@ -0,0 +1,57 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestAllowedInConsortium(t *testing.T) {
+	require := require.New(t)
+
+	nodeIDs, networks, wg := newFullyConnectedTestNetwork(t, []router.InboundHandler{nil})
+
+	network := networks[0].(*network)
+	validatorNodeID := nodeIDs[0]
+	pendingNodeID := ids.GenerateTestNodeID()
+	allowedNodeID := ids.GenerateTestNodeID()
+	otherNodeID := ids.GenerateTestNodeID()
+
+	pendingValidators := validators.NewSet()
+	require.NoError(pendingValidators.Add(pendingNodeID, nil, ids.GenerateTestID(), 1))
+	network.config.PendingValidators = pendingValidators
+	network.config.AllowedNodeIDs.Add(allowedNodeID)
+
+	require.True(network.AllowConnection(otherNodeID))
+
+	network.config.ConsortiumOnly = true
+	require.True(network.AllowConnection(validatorNodeID))
+	require.True(network.AllowConnection(pendingNodeID))
+	require.True(network.AllowConnection(allowedNodeID))
+	require.False(network.AllowConnection(otherNodeID))
+
+	// Beacons are always allowed, so that the node can bootstrap.
+	beaconNodeID := ids.GenerateTestNodeID()
+	require.False(network.AllowConnection(beaconNodeID))
+	beacons := validators.NewSet()
+	require.NoError(beacons.Add(beaconNodeID, nil, ids.Empty, 1))
+	network.config.Beacons = beacons
+	require.True(network.AllowConnection(beaconNodeID))
+
+	// Manually tracked nodes, like state sync beacons, are always allowed.
+	network.ManuallyTrack(otherNodeID, network.config.MyIPPort.IPPort())
+	require.True(network.AllowConnection(otherNodeID))
+	require.True(network.WantsConnection(otherNodeID))
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/math"
+)
+
+// dispatchQUIC accepts QUIC connections until the network is closed
+func (n *network) dispatchQUIC() {
+	for {
+		conn, err := n.config.QUICListener.Accept() // Returns error when n.Close() is called
+		if err != nil {
+			if n.onCloseCtx.Err() != nil {
+				return
+			}
+			n.peerConfig.Log.Debug("error during QUIC accept", zap.Error(err))
+			// Sleep for a small amount of time to try to wait for the
+			// error to go away.
+			time.Sleep(time.Millisecond)
+			n.metrics.acceptFailed.Inc()
+			continue
+		}
+
+		go n.upgradeInbound(conn, n.quicUpgrader)
+	}
+}
+
+// dialPeer connects to [nodeID] at [ip] and returns the upgrader of the
+// connection. If the node advertised a QUIC port in its last handshake, the
+// QUIC transport is attempted first, falling back to TCP.
+func (n *network) dialPeer(ctx context.Context, nodeID ids.NodeID, ip ips.IPPort) (net.Conn, peer.Upgrader, error) {
+	if n.config.QUICListener != nil {
+		n.peersLock.RLock()
+		quicPort, ok := n.quicPorts[nodeID]
+		n.peersLock.RUnlock()
+
+		if ok {
+			quicIP := ips.IPPort{
+				IP:   ip.IP,
+				Port: quicPort,
+			}
+			conn, err := n.config.QUICDialer.Dial(ctx, quicIP)
+			if err == nil {
+				return conn, n.quicUpgrader, nil
+			}
+
+			n.peerConfig.Log.Verbo("failed to reach peer over QUIC, falling back to TCP",
+				zap.Stringer("nodeID", nodeID),
+				zap.Stringer("peerIP", quicIP),
+				zap.Error(err),
+			)
+
+			// The peer is dialed over TCP until it advertises its QUIC port
+			// again, so that the QUIC connection timeout isn't awaited on
+			// every attempt.
+			n.peersLock.Lock()
+			delete(n.quicPorts, nodeID)
+			n.peersLock.Unlock()
+		}
+	}
+
+	conn, err := n.dialer.Dial(ctx, ip)
+	return conn, n.clientUpgrader, err
+}
+
+// newStreamInboundMsgThrottlers returns the inbound message throttlers of the
+// streams of QUIC connections, other than the control stream. Each stream
+// class is throttled independently of the other streams, with an equal share
+// of the budgets of [config], see splitInboundMsgThrottlerConfig.
+func newStreamInboundMsgThrottlers(
+	log logging.Logger,
+	config *Config,
+	registerer prometheus.Registerer,
+	primaryNetworkValidators validators.Set,
+) ([peer.NumStreams]throttling.InboundMsgThrottler, error) {
+	throttlerConfig := splitInboundMsgThrottlerConfig(
+		config.ThrottlerConfig.InboundMsgThrottlerConfig,
+		peer.NumStreams-1,
+	)
+	throttlers := [peer.NumStreams]throttling.InboundMsgThrottler{}
+	for stream := peer.ControlStream + 1; stream < peer.NumStreams; stream++ {
+		name := peer.StreamName(stream)
+		throttler, err := throttling.NewInboundMsgThrottler(
+			log,
+			fmt.Sprintf("%s_%s_stream", config.Namespace, name),
+			registerer,
+			primaryNetworkValidators,
+			throttlerConfig,
+			config.ResourceTracker,
+			config.CPUTargeter,
+			config.DiskTargeter,
+			config.Reputation,
+		)
+		if err != nil {
+			return throttlers, fmt.Errorf("initializing inbound message throttler of the %s stream failed with: %w", name, err)
+		}
+		throttlers[stream] = throttler
+	}
+	return throttlers, nil
+}
+
+// splitInboundMsgThrottlerConfig returns the config of one of [numStreams]
+// throttlers, which together don't exceed the byte, bandwidth and processing
+// message budgets of [config]. The limits that bound the size of a single
+// message are kept, so that the largest message can still be received on any
+// stream.
+func splitInboundMsgThrottlerConfig(config throttling.InboundMsgThrottlerConfig, numStreams uint64) throttling.InboundMsgThrottlerConfig {
+	// A node must be able to take a message of the maximum size from the
+	// at-large allocation, unless the configured allocation is smaller.
+	minAtLargeAllocSize := math.Min(config.AtLargeAllocSize, config.NodeMaxAtLargeBytes)
+	config.VdrAllocSize /= numStreams
+	config.AtLargeAllocSize = math.Max(config.AtLargeAllocSize/numStreams, minAtLargeAllocSize)
+	config.RefillRate = math.Max(config.RefillRate/numStreams, 1)
+	config.MaxProcessingMsgsPerNode = math.Max(config.MaxProcessingMsgsPerNode/numStreams, 1)
+	return config
+}
+
+// newMessageQueue returns the outbound message queue of a peer connected
+// with [conn]. Connections with multiple streams have a queue per stream.
+func (n *network) newMessageQueue(conn net.Conn, nodeID ids.NodeID) peer.MessageQueue {
+	newQueue := func() peer.MessageQueue {
+		return peer.NewPrioritizedMessageQueue(
+			n.config.ThrottlerConfig.OutboundMsgQueueConfig,
+			n.outboundQueueMetrics,
+			n.peerConfig.Metrics,
+			nodeID,
+			n.peerConfig.Log,
+			n.outboundMsgThrottler,
+		)
+	}
+	if _, ok := conn.(peer.MultiStreamConn); ok {
+		return peer.NewStreamMessageQueue(newQueue)
+	}
+	return newQueue()
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/quic"
+	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+func TestReconnectOverQUIC(t *testing.T) {
+	require := require.New(t)
+
+	dialer, listeners, nodeIDs, configs := newTestNetwork(t, 2)
+	for _, config := range configs {
+		// Both nodes redial each other after the disconnect. If their
+		// connections collide, both are dropped, so retries must be quick.
+		config.MaxReconnectDelay = 2 * time.Second
+
+		quicListener, err := quic.Listen("[::1]:0", config.TLSConfig, config.ReadHandshakeTimeout, logging.NoLog{})
+		require.NoError(err)
+
+		config.QUICPort = uint16(quicListener.Addr().(*net.UDPAddr).Port)
+		config.QUICListener = quicListener
+		config.QUICDialer = quic.NewDialer(config.TLSConfig, config.DialerConfig, logging.NoLog{})
+	}
+	networks, wg := connectTestNetworks(t, []router.InboundHandler{nil, nil}, dialer, listeners, nodeIDs, configs)
+
+	// The QUIC ports are only learned during the handshake, so the first
+	// connection is established over TCP.
+	for _, net := range networks {
+		peers := net.PeerInfo(nil)
+		require.Len(peers, 1)
+		require.Equal(peer.TransportTCP, peers[0].Transport)
+	}
+
+	require.NoError(networks[0].DisconnectPeer(nodeIDs[1]))
+	for _, net := range networks {
+		require.Eventually(
+			func() bool {
+				peers := net.PeerInfo(nil)
+				return len(peers) == 1 && peers[0].Transport == peer.TransportQUIC
+			},
+			30*time.Second,
+			50*time.Millisecond,
+		)
+	}
+
+	for _, net := range networks {
+		net.StartClose()
+	}
+	wg.Wait()
+}
+
+func TestSplitInboundMsgThrottlerConfig(t *testing.T) {
+	require := require.New(t)
+
+	config := throttling.InboundMsgThrottlerConfig{
+		MsgByteThrottlerConfig: throttling.MsgByteThrottlerConfig{
+			VdrAllocSize:        32 * units.MiB,
+			AtLargeAllocSize:    6 * units.MiB,
+			NodeMaxAtLargeBytes: 2 * units.MiB,
+		},
+		BandwidthThrottlerConfig: throttling.BandwidthThrottlerConfig{
+			RefillRate:   512 * units.KiB,
+			MaxBurstSize: 2 * units.MiB,
+		},
+		MaxProcessingMsgsPerNode: 1024,
+	}
+	require.Equal(
+		throttling.InboundMsgThrottlerConfig{
+			MsgByteThrottlerConfig: throttling.MsgByteThrottlerConfig{
+				VdrAllocSize:        16 * units.MiB,
+				AtLargeAllocSize:    3 * units.MiB,
+				NodeMaxAtLargeBytes: 2 * units.MiB,
+			},
+			BandwidthThrottlerConfig: throttling.BandwidthThrottlerConfig{
+				RefillRate:   256 * units.KiB,
+				MaxBurstSize: 2 * units.MiB,
+			},
+			MaxProcessingMsgsPerNode: 512,
+		},
+		splitInboundMsgThrottlerConfig(config, 2),
+	)
+
+	// The largest message can still be taken from the at-large allocation
+	split := splitInboundMsgThrottlerConfig(config, 4)
+	require.Equal(uint64(2*units.MiB), split.AtLargeAllocSize)
+	require.Equal(uint64(2*units.MiB), split.NodeMaxAtLargeBytes)
+	require.Equal(uint64(2*units.MiB), split.MaxBurstSize)
+}
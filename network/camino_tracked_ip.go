@@ -0,0 +1,32 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import "github.com/ava-labs/avalanchego/utils/ips"
+
+// dialIP returns the IP that is dialed next and whether it is the first IP of
+// a round over all IPs of the node
+func (ip *trackedIP) dialIP() (ips.IPPort, bool) {
+	ip.dialIndexLock.Lock()
+	defer ip.dialIndexLock.Unlock()
+
+	if ip.dialIndex == 0 {
+		return ip.ip, true
+	}
+	return ip.extraIPs[ip.dialIndex-1], false
+}
+
+// nextDialIP makes the following IP of the node the one that is dialed next,
+// starting over with the primary IP after the last extra IP
+func (ip *trackedIP) nextDialIP() {
+	ip.dialIndexLock.Lock()
+	defer ip.dialIndexLock.Unlock()
+
+	ip.dialIndex = (ip.dialIndex + 1) % (len(ip.extraIPs) + 1)
+}
+
+// sameIPPorts returns true if [a] and [b] claim the same primary and extra IPs
+func sameIPPorts(a, b *ips.ClaimedIPPort) bool {
+	return a.IPPort.Equal(b.IPPort) && ips.EqualIPPorts(a.ExtraIPPorts, b.ExtraIPPorts)
+}
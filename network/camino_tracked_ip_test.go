@@ -0,0 +1,59 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/ips"
+)
+
+func TestTrackedIPDialOrder(t *testing.T) {
+	require := require.New(t)
+
+	primary := ips.IPPort{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	extra1 := ips.IPPort{IP: net.ParseIP("2001:db8::1"), Port: 9651}
+	extra2 := ips.IPPort{IP: net.IPv4(10, 0, 0, 1), Port: 9651}
+	ip := newTrackedIP(primary, extra1, extra2)
+
+	for round := 0; round < 2; round++ {
+		for i, expected := range []ips.IPPort{primary, extra1, extra2} {
+			dialIP, firstOfRound := ip.dialIP()
+			require.Equal(expected, dialIP)
+			require.Equal(i == 0, firstOfRound)
+			ip.nextDialIP()
+		}
+	}
+
+	// A new IP claim starts over with its primary IP
+	ip.nextDialIP()
+	newIP := ip.trackNewIP(extra2, extra1)
+	dialIP, firstOfRound := newIP.dialIP()
+	require.Equal(extra2, dialIP)
+	require.True(firstOfRound)
+
+	// Without extra IPs the primary IP is always dialed
+	ip = newTrackedIP(primary)
+	for i := 0; i < 3; i++ {
+		dialIP, firstOfRound := ip.dialIP()
+		require.Equal(primary, dialIP)
+		require.True(firstOfRound)
+		ip.nextDialIP()
+	}
+}
+
+func TestSameIPPorts(t *testing.T) {
+	require := require.New(t)
+
+	primary := ips.IPPort{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	extra := ips.IPPort{IP: net.ParseIP("2001:db8::1"), Port: 9651}
+
+	a := &ips.ClaimedIPPort{IPPort: primary, ExtraIPPorts: []ips.IPPort{extra}}
+	require.True(sameIPPorts(a, &ips.ClaimedIPPort{IPPort: primary, ExtraIPPorts: []ips.IPPort{extra}}))
+	require.False(sameIPPorts(a, &ips.ClaimedIPPort{IPPort: primary}))
+	require.False(sameIPPorts(a, &ips.ClaimedIPPort{IPPort: extra, ExtraIPPorts: []ips.IPPort{extra}}))
+}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -6,12 +16,15 @@ package network
 import (
 	"crypto"
 	"crypto/tls"
+	"net"
 	"time"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -100,6 +113,7 @@ type ThrottlerConfig struct {
 	InboundConnUpgradeThrottlerConfig throttling.InboundConnUpgradeThrottlerConfig `json:"inboundConnUpgradeThrottlerConfig"`
 	InboundMsgThrottlerConfig         throttling.InboundMsgThrottlerConfig         `json:"inboundMsgThrottlerConfig"`
 	OutboundMsgThrottlerConfig        throttling.MsgByteThrottlerConfig            `json:"outboundMsgThrottlerConfig"`
+	OutboundMsgQueueConfig            peer.MessageQueueConfig                      `json:"outboundMsgQueueConfig"`
 	MaxInboundConnsPerSec             float64                                      `json:"maxInboundConnsPerSec"`
 }
 
@@ -118,13 +132,15 @@ type Config struct {
 
 	TLSKeyLogFile string `json:"tlsKeyLogFile"`
 
-	Namespace          string            `json:"namespace"`
-	MyNodeID           ids.NodeID        `json:"myNodeID"`
-	MyIPPort           ips.DynamicIPPort `json:"myIP"`
-	NetworkID          uint32            `json:"networkID"`
-	MaxClockDifference time.Duration     `json:"maxClockDifference"`
-	PingFrequency      time.Duration     `json:"pingFrequency"`
-	AllowPrivateIPs    bool              `json:"allowPrivateIPs"`
+	Namespace string            `json:"namespace"`
+	MyNodeID  ids.NodeID        `json:"myNodeID"`
+	MyIPPort  ips.DynamicIPPort `json:"myIP"`
+	// Endpoints advertised besides [MyIPPort], in order of preference
+	MyExtraIPPorts     ips.DynamicIPPorts `json:"myExtraIPs"`
+	NetworkID          uint32             `json:"networkID"`
+	MaxClockDifference time.Duration      `json:"maxClockDifference"`
+	PingFrequency      time.Duration      `json:"pingFrequency"`
+	AllowPrivateIPs    bool               `json:"allowPrivateIPs"`
 
 	// The compression type to use when compressing outbound messages.
 	// Assumes all peers support this compression type.
@@ -182,4 +198,46 @@ type Config struct {
 
 	// Tracks which validators have been sent to which peers
 	GossipTracker peer.GossipTracker `json:"-"`
+
+	// PeerDB persists the bans and persistent peers of the node. If nil, they
+	// are lost on restart.
+	PeerDB database.Database `json:"-"`
+
+	// ConsortiumOnly restricts inbound and outbound connections, as well as
+	// the IPs accepted from peer-list gossip, to current and pending
+	// validators of the primary network and [AllowedNodeIDs]. [Beacons] and
+	// manually tracked nodes are always allowed.
+	ConsortiumOnly bool `json:"consortiumOnly"`
+
+	// AllowedNodeIDs are the nodes, like API nodes, that are allowed to
+	// connect without validating if [ConsortiumOnly] is set.
+	AllowedNodeIDs set.Set[ids.NodeID] `json:"allowedNodeIDs"`
+
+	// PendingValidators are the pending validators of the primary network. May
+	// be nil.
+	PendingValidators validators.Set `json:"-"`
+
+	// QUICEnabled is true if the node should listen on [QUICPort] and provide
+	// the QUIC transport.
+	QUICEnabled bool `json:"quicEnabled"`
+
+	// QUICPort is the UDP port of [QUICListener] advertised to peers in the
+	// handshake. Peers that advertised a QUIC port are dialed with
+	// [QUICDialer], falling back to TCP if the QUIC connection fails.
+	QUICPort uint16 `json:"quicPort"`
+
+	// QUICListener accepts QUIC connections. If nil, the QUIC transport is
+	// disabled.
+	QUICListener net.Listener `json:"-"`
+
+	// QUICDialer establishes QUIC connections. Must be set if [QUICListener]
+	// is set.
+	QUICDialer dialer.Dialer `json:"-"`
+
+	// Reputation tracks the behaviour of peers. Peers benched by [Reputation]
+	// aren't gossiped to.
+	Reputation reputation.Tracker `json:"-"`
+
+	// Recorder captures the messages exchanged with peers
+	Recorder peer.Recorder `json:"-"`
 }
@@ -34,10 +34,13 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/quic"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
@@ -102,6 +105,31 @@ type Network interface {
 	// connect to this ID.
 	ManuallyTrack(nodeID ids.NodeID, ip ips.IPPort)
 
+	// AddPersistentPeer manually tracks [nodeID] at [ip] and keeps tracking it
+	// after a restart of the node.
+	AddPersistentPeer(nodeID ids.NodeID, ip ips.IPPort) error
+
+	// BanNode disconnects [nodeID] and refuses connections to it until
+	// [expiry], or until it is unbanned if [expiry] is nil.
+	BanNode(nodeID ids.NodeID, reason string, expiry *time.Time) error
+
+	// BanIP disconnects the peers at [ip] and refuses connections to [ip]
+	// until [expiry], or until it is unbanned if [expiry] is nil.
+	BanIP(ip net.IP, reason string, expiry *time.Time) error
+
+	// UnbanNode lifts the ban of [nodeID]
+	UnbanNode(nodeID ids.NodeID) error
+
+	// UnbanIP lifts the ban of [ip]
+	UnbanIP(ip net.IP) error
+
+	// Bans returns the bans that didn't expire yet
+	Bans() ([]Ban, error)
+
+	// DisconnectPeer closes the connection to [nodeID]. The node reconnects
+	// to the peer if it still wants a connection to it.
+	DisconnectPeer(nodeID ids.NodeID) error
+
 	// PeerInfo returns information about peers. If [nodeIDs] is empty, returns
 	// info about all peers that have finished the handshake. Otherwise, returns
 	// info about the peers in [nodeIDs] that have finished the handshake.
@@ -134,6 +162,7 @@ type network struct {
 	metrics    *metrics
 
 	outboundMsgThrottler throttling.OutboundMsgThrottler
+	outboundQueueMetrics *peer.QueueMetrics
 
 	// Limits the number of connection attempts based on IP.
 	inboundConnUpgradeThrottler throttling.InboundConnUpgradeThrottler
@@ -145,6 +174,8 @@ type network struct {
 	serverUpgrader peer.Upgrader
 	// Does TLS handshakes for outbound connections
 	clientUpgrader peer.Upgrader
+	// Identifies the peers of QUIC connections
+	quicUpgrader peer.Upgrader
 
 	// ensures the close of the network only happens once.
 	closeOnce sync.Once
@@ -173,6 +204,22 @@ type network struct {
 	connectedPeers     peer.Set
 	closing            bool
 
+	// bans are the node IDs and IPs this node refuses to connect to
+	bans *banList
+	// persistentPeerDB contains the peers that are manually tracked after a
+	// restart
+	persistentPeerDB database.Database
+
+	// consortiumExemptIDs are the manually tracked nodes, which may connect
+	// even if they aren't in the consortium. It has its own lock, as the
+	// consortium is checked both with and without [peersLock] held.
+	consortiumExemptLock sync.RWMutex
+	consortiumExemptIDs  set.Set[ids.NodeID]
+
+	// quicPorts contains the QUIC ports advertised by the peers this node
+	// connected to. Peers are dialed over QUIC if their port is known.
+	quicPorts map[ids.NodeID]uint16
+
 	// router is notified about all peer [Connected] and [Disconnected] events
 	// as well as all non-handshake peer messages.
 	//
@@ -235,6 +282,7 @@ func NewNetwork(
 		config.ResourceTracker,
 		config.CPUTargeter,
 		config.DiskTargeter,
+		config.Reputation,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("initializing inbound message throttler failed with: %w", err)
@@ -256,6 +304,11 @@ func NewNetwork(
 		return nil, fmt.Errorf("initializing peer metrics failed with: %w", err)
 	}
 
+	outboundQueueMetrics, err := peer.NewQueueMetrics(config.Namespace, metricsRegisterer)
+	if err != nil {
+		return nil, fmt.Errorf("initializing outbound queue metrics failed with: %w", err)
+	}
+
 	metrics, err := newMetrics(config.Namespace, metricsRegisterer, config.TrackedSubnets)
 	if err != nil {
 		return nil, fmt.Errorf("initializing network metrics failed with: %w", err)
@@ -280,7 +333,21 @@ func NewNetwork(
 		MaxClockDifference:   config.MaxClockDifference,
 		ResourceTracker:      config.ResourceTracker,
 		UptimeCalculator:     config.UptimeCalculator,
-		IPSigner:             peer.NewIPSigner(config.MyIPPort, config.TLSKey),
+		IPSigner:             peer.NewIPSigner(config.MyIPPort, config.MyExtraIPPorts, config.TLSKey),
+		Reputation:           config.Reputation,
+		Recorder:             config.Recorder,
+	}
+	if config.QUICListener != nil {
+		peerConfig.QUICPort = config.QUICPort
+		peerConfig.StreamInboundMsgThrottlers, err = newStreamInboundMsgThrottlers(
+			log,
+			config,
+			metricsRegisterer,
+			primaryNetworkValidators,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	onCloseCtx, cancel := context.WithCancel(context.Background())
@@ -289,12 +356,14 @@ func NewNetwork(
 		peerConfig:           peerConfig,
 		metrics:              metrics,
 		outboundMsgThrottler: outboundMsgThrottler,
+		outboundQueueMetrics: outboundQueueMetrics,
 
 		inboundConnUpgradeThrottler: throttling.NewInboundConnUpgradeThrottler(log, config.ThrottlerConfig.InboundConnUpgradeThrottlerConfig),
 		listener:                    listener,
 		dialer:                      dialer,
 		serverUpgrader:              peer.NewTLSServerUpgrader(config.TLSConfig),
 		clientUpgrader:              peer.NewTLSClientUpgrader(config.TLSConfig),
+		quicUpgrader:                quic.NewUpgrader(),
 
 		onCloseCtx:       onCloseCtx,
 		onCloseCtxCancel: cancel,
@@ -310,9 +379,23 @@ func NewNetwork(
 		gossipTracker:   config.GossipTracker,
 		connectingPeers: peer.NewSet(),
 		connectedPeers:  peer.NewSet(),
+		quicPorts:       make(map[ids.NodeID]uint16),
 		router:          router,
 	}
 	n.peerConfig.Network = n
+	if config.ConsortiumOnly {
+		n.serverUpgrader = peer.NewFilteredUpgrader(n.serverUpgrader, n.allowedInConsortium)
+		n.clientUpgrader = peer.NewFilteredUpgrader(n.clientUpgrader, n.allowedInConsortium)
+		n.quicUpgrader = peer.NewFilteredUpgrader(n.quicUpgrader, n.allowedInConsortium)
+	}
+
+	peerDB := config.PeerDB
+	if peerDB == nil {
+		peerDB = memdb.New()
+	}
+	if err := n.initPeerDB(peerDB); err != nil {
+		return nil, fmt.Errorf("initializing peer database failed with: %w", err)
+	}
 	return n, nil
 }
 
@@ -431,10 +514,12 @@ func (n *network) Connected(nodeID ids.NodeID) {
 
 	peerIP := peer.IP()
 	newIP := &ips.ClaimedIPPort{
-		Cert:      peer.Cert(),
-		IPPort:    peerIP.IPPort,
-		Timestamp: peerIP.Timestamp,
-		Signature: peerIP.Signature,
+		Cert:           peer.Cert(),
+		IPPort:         peerIP.IPPort,
+		Timestamp:      peerIP.Timestamp,
+		Signature:      peerIP.Signature,
+		ExtraIPPorts:   peerIP.ExtraIPPorts,
+		ExtraSignature: peerIP.ExtraSignature,
 	}
 	prevIP, ok := n.peerIPs[nodeID]
 	if !ok {
@@ -446,7 +531,7 @@ func (n *network) Connected(nodeID ids.NodeID) {
 		// The previous IP was stale, so we should gossip the newer IP.
 		n.peerIPs[nodeID] = newIP
 
-		if !prevIP.IPPort.Equal(newIP.IPPort) {
+		if !sameIPPorts(prevIP, newIP) {
 			// This IP is actually different, so we should gossip it.
 			n.peerConfig.Log.Debug("resetting gossip due to ip change",
 				zap.Stringer("nodeID", nodeID),
@@ -455,6 +540,12 @@ func (n *network) Connected(nodeID ids.NodeID) {
 		}
 	}
 
+	if quicPort := peer.QUICPort(); quicPort != 0 {
+		n.quicPorts[nodeID] = quicPort
+	} else {
+		delete(n.quicPorts, nodeID)
+	}
+
 	if tracked, ok := n.trackedIPs[nodeID]; ok {
 		tracked.stopTracking()
 		delete(n.trackedIPs, nodeID)
@@ -477,6 +568,9 @@ func (n *network) Connected(nodeID ids.NodeID) {
 // of peers, then it should only connect if this node is a validator, or the
 // peer is a validator/beacon.
 func (n *network) AllowConnection(nodeID ids.NodeID) bool {
+	if n.isNodeBanned(nodeID) || !n.allowedInConsortium(nodeID) {
+		return false
+	}
 	return !n.config.RequireValidatorToConnect ||
 		validators.Contains(n.config.Validators, constants.PrimaryNetworkID, n.config.MyNodeID) ||
 		n.WantsConnection(nodeID)
@@ -527,6 +621,14 @@ func (n *network) Track(peerID ids.NodeID, claimedIPPorts []*ips.ClaimedIPPort)
 		// Evaluate if the gossiped IP is useful to us or to the peer that
 		// shared it with us.
 		switch {
+		case n.isNodeBanned(nodeID) || n.isIPBanned(ip.IPPort.IP) || !n.allowedInConsortium(nodeID):
+			// We never connect to or gossip banned peers or peers outside of
+			// the consortium. We should tell the peer not to gossip this IP to
+			// us again.
+			newestTimestamp[ip.TxID] = ip.Timestamp
+			txIDsWithUpToDateIP = append(txIDsWithUpToDateIP, ip.TxID)
+
+			n.metrics.numUselessPeerListBytes.Add(float64(ip.BytesLen()))
 		case previouslyTracked && prevIP.Timestamp > ip.Timestamp:
 			// Our previous IP was more up to date. We should tell the peer
 			// not to gossip their IP to us. We should still gossip our IP to
@@ -553,7 +655,7 @@ func (n *network) Track(peerID ids.NodeID, claimedIPPorts []*ips.ClaimedIPPort)
 			// If the new IP is equal to the old IP, there is no reason to
 			// refresh the references to it. This can happen when a node
 			// restarts but does not change their IP.
-			if prevIP.IPPort.Equal(ip.IPPort) {
+			if sameIPPorts(prevIP, ip) {
 				continue
 			}
 
@@ -566,7 +668,7 @@ func (n *network) Track(peerID ids.NodeID, claimedIPPorts []*ips.ClaimedIPPort)
 			// We should update any existing outbound connection attempts.
 			if isTracked {
 				// Stop tracking the old IP and start tracking the new one.
-				tracked := tracked.trackNewIP(ip.IPPort)
+				tracked := tracked.trackNewIP(ip.IPPort, ip.ExtraIPPorts...)
 				n.trackedIPs[nodeID] = tracked
 				n.dial(n.onCloseCtx, nodeID, tracked)
 			}
@@ -583,7 +685,7 @@ func (n *network) Track(peerID ids.NodeID, claimedIPPorts []*ips.ClaimedIPPort)
 			// we've never gossiped it before.
 			n.peerIPs[nodeID] = ip
 
-			tracked := newTrackedIP(ip.IPPort)
+			tracked := newTrackedIP(ip.IPPort, ip.ExtraIPPorts...)
 			n.trackedIPs[nodeID] = tracked
 			n.dial(n.onCloseCtx, nodeID, tracked)
 		default:
@@ -722,11 +824,13 @@ func (n *network) Peers(peerID ids.NodeID) ([]ips.ClaimedIPPort, error) {
 		//       incorrect.
 		validatorIPs = append(validatorIPs,
 			ips.ClaimedIPPort{
-				Cert:      peerIP.Cert,
-				IPPort:    peerIP.IPPort,
-				Timestamp: peerIP.Timestamp,
-				Signature: peerIP.Signature,
-				TxID:      validator.TxID,
+				Cert:           peerIP.Cert,
+				IPPort:         peerIP.IPPort,
+				Timestamp:      peerIP.Timestamp,
+				Signature:      peerIP.Signature,
+				TxID:           validator.TxID,
+				ExtraIPPorts:   peerIP.ExtraIPPorts,
+				ExtraSignature: peerIP.ExtraSignature,
 			},
 		)
 	}
@@ -739,6 +843,9 @@ func (n *network) Peers(peerID ids.NodeID) ([]ips.ClaimedIPPort, error) {
 func (n *network) Dispatch() error {
 	go n.runTimers() // Periodically perform operations
 	go n.inboundConnUpgradeThrottler.Dispatch()
+	if n.config.QUICListener != nil {
+		go n.dispatchQUIC()
+	}
 	errs := wrappers.Errs{}
 	for { // Continuously accept new connections
 		if n.onCloseCtx.Err() != nil {
@@ -757,52 +864,7 @@ func (n *network) Dispatch() error {
 
 		// Note: listener.Accept is rate limited outside of this package, so a
 		// peer can not just arbitrarily spin up goroutines here.
-		go func() {
-			// We pessimistically drop an incoming connection if the remote
-			// address is found in connectedIPs, myIPs, or peerAliasIPs. This
-			// protects our node from spending CPU cycles on TLS handshakes to
-			// upgrade connections from existing peers. Specifically, this can
-			// occur when one of our existing peers attempts to connect to one
-			// our IP aliases (that they aren't yet aware is an alias).
-			//
-			// Note: Calling [RemoteAddr] with the Proxy protocol enabled may
-			// block for up to ProxyReadHeaderTimeout. Therefore, we ensure to
-			// call this function inside the go-routine, rather than the main
-			// accept loop.
-			remoteAddr := conn.RemoteAddr().String()
-			ip, err := ips.ToIPPort(remoteAddr)
-			if err != nil {
-				n.peerConfig.Log.Error("failed to parse remote address",
-					zap.String("peerIP", remoteAddr),
-					zap.Error(err),
-				)
-				_ = conn.Close()
-				return
-			}
-
-			if !n.inboundConnUpgradeThrottler.ShouldUpgrade(ip) {
-				n.peerConfig.Log.Debug("failed to upgrade connection",
-					zap.String("reason", "rate-limiting"),
-					zap.Stringer("peerIP", ip),
-				)
-				n.metrics.inboundConnRateLimited.Inc()
-				_ = conn.Close()
-				return
-			}
-			n.metrics.inboundConnAllowed.Inc()
-
-			n.peerConfig.Log.Verbo("starting to upgrade connection",
-				zap.String("direction", "inbound"),
-				zap.Stringer("peerIP", ip),
-			)
-
-			if err := n.upgrade(conn, n.serverUpgrader); err != nil {
-				n.peerConfig.Log.Verbo("failed to upgrade connection",
-					zap.String("direction", "inbound"),
-					zap.Error(err),
-				)
-			}
-		}()
+		go n.upgradeInbound(conn, n.serverUpgrader)
 	}
 	n.inboundConnUpgradeThrottler.Stop()
 	n.StartClose()
@@ -818,6 +880,55 @@ func (n *network) Dispatch() error {
 	return errs.Err
 }
 
+// upgradeInbound upgrades the inbound connection [conn] with [upgrader], unless
+// the remote IP is rate-limited.
+func (n *network) upgradeInbound(conn net.Conn, upgrader peer.Upgrader) {
+	// We pessimistically drop an incoming connection if the remote
+	// address is found in connectedIPs, myIPs, or peerAliasIPs. This
+	// protects our node from spending CPU cycles on TLS handshakes to
+	// upgrade connections from existing peers. Specifically, this can
+	// occur when one of our existing peers attempts to connect to one
+	// our IP aliases (that they aren't yet aware is an alias).
+	//
+	// Note: Calling [RemoteAddr] with the Proxy protocol enabled may
+	// block for up to ProxyReadHeaderTimeout. Therefore, we ensure to
+	// call this function inside the go-routine, rather than the main
+	// accept loop.
+	remoteAddr := conn.RemoteAddr().String()
+	ip, err := ips.ToIPPort(remoteAddr)
+	if err != nil {
+		n.peerConfig.Log.Error("failed to parse remote address",
+			zap.String("peerIP", remoteAddr),
+			zap.Error(err),
+		)
+		_ = conn.Close()
+		return
+	}
+
+	if !n.inboundConnUpgradeThrottler.ShouldUpgrade(ip) {
+		n.peerConfig.Log.Debug("failed to upgrade connection",
+			zap.String("reason", "rate-limiting"),
+			zap.Stringer("peerIP", ip),
+		)
+		n.metrics.inboundConnRateLimited.Inc()
+		_ = conn.Close()
+		return
+	}
+	n.metrics.inboundConnAllowed.Inc()
+
+	n.peerConfig.Log.Verbo("starting to upgrade connection",
+		zap.String("direction", "inbound"),
+		zap.Stringer("peerIP", ip),
+	)
+
+	if err := n.upgrade(conn, upgrader); err != nil {
+		n.peerConfig.Log.Verbo("failed to upgrade connection",
+			zap.String("direction", "inbound"),
+			zap.Error(err),
+		)
+	}
+}
+
 func (n *network) WantsConnection(nodeID ids.NodeID) bool {
 	n.peersLock.RLock()
 	defer n.peersLock.RUnlock()
@@ -826,6 +937,9 @@ func (n *network) WantsConnection(nodeID ids.NodeID) bool {
 }
 
 func (n *network) wantsConnection(nodeID ids.NodeID) bool {
+	if n.isNodeBanned(nodeID) || !n.allowedInConsortium(nodeID) {
+		return false
+	}
 	return validators.Contains(n.config.Validators, constants.PrimaryNetworkID, nodeID) ||
 		n.manuallyTrackedIDs.Contains(nodeID)
 }
@@ -835,6 +949,7 @@ func (n *network) ManuallyTrack(nodeID ids.NodeID, ip ips.IPPort) {
 	defer n.peersLock.Unlock()
 
 	n.manuallyTrackedIDs.Add(nodeID)
+	n.exemptFromConsortium(nodeID)
 
 	_, connected := n.connectedPeers.GetByID(nodeID)
 	if connected {
@@ -931,6 +1046,11 @@ func (n *network) samplePeers(
 				return false
 			}
 
+			// don't gossip to peers benched due to their reputation
+			if n.config.Reputation.IsBenched(peerID) {
+				return false
+			}
+
 			if numPeersToSample > 0 {
 				numPeersToSample--
 				return true
@@ -982,7 +1102,7 @@ func (n *network) disconnectedFromConnecting(nodeID ids.NodeID) {
 	tracked, ok := n.trackedIPs[nodeID]
 	if ok {
 		if n.wantsConnection(nodeID) {
-			tracked := tracked.trackNewIP(tracked.ip)
+			tracked := tracked.trackNewIP(tracked.ip, tracked.extraIPs...)
 			n.trackedIPs[nodeID] = tracked
 			n.dial(n.onCloseCtx, nodeID, tracked)
 		} else {
@@ -1006,7 +1126,7 @@ func (n *network) disconnectedFromConnected(peer peer.Peer, nodeID ids.NodeID) {
 	// The peer that is disconnecting from us finished the handshake
 	if n.wantsConnection(nodeID) {
 		prevIP := n.peerIPs[nodeID]
-		tracked := newTrackedIP(prevIP.IPPort)
+		tracked := newTrackedIP(prevIP.IPPort, prevIP.ExtraIPPorts...)
 		n.trackedIPs[nodeID] = tracked
 		n.dial(n.onCloseCtx, nodeID, tracked)
 	} else {
@@ -1047,10 +1167,12 @@ func (n *network) authenticateIPs(ips []*ips.ClaimedIPPort) ([]*ipAuth, error) {
 		// Verify signature if needed
 		signedIP := peer.SignedIP{
 			UnsignedIP: peer.UnsignedIP{
-				IPPort:    ip.IPPort,
-				Timestamp: ip.Timestamp,
+				IPPort:       ip.IPPort,
+				Timestamp:    ip.Timestamp,
+				ExtraIPPorts: ip.ExtraIPPorts,
 			},
-			Signature: ip.Signature,
+			Signature:      ip.Signature,
+			ExtraSignature: ip.ExtraSignature,
 		}
 		if err := signedIP.Verify(ip.Cert); err != nil {
 			return nil, err
@@ -1106,8 +1228,10 @@ func (n *network) dial(ctx context.Context, nodeID ids.NodeID, ip *trackedIP) {
 			case <-timer.C:
 			}
 
+			dialIP, firstOfRound := ip.dialIP()
+
 			n.peersLock.Lock()
-			if !n.wantsConnection(nodeID) {
+			if !n.wantsConnection(nodeID) || n.isIPBanned(ip.ip.IP) {
 				// Typically [n.trackedIPs[nodeID]] will already equal [ip], but
 				// the reference to [ip] is refreshed to avoid any potential
 				// race conditions before removing the entry.
@@ -1121,6 +1245,7 @@ func (n *network) dial(ctx context.Context, nodeID ids.NodeID, ip *trackedIP) {
 			}
 			_, connecting := n.connectingPeers.GetByID(nodeID)
 			_, connected := n.connectedPeers.GetByID(nodeID)
+			dialIPBanned := n.isIPBanned(dialIP.IP)
 			n.peersLock.Unlock()
 
 			// While it may not be strictly needed to stop attempting to connect
@@ -1139,17 +1264,25 @@ func (n *network) dial(ctx context.Context, nodeID ids.NodeID, ip *trackedIP) {
 			}
 
 			// Increase the delay that we will use for a future connection
-			// attempt.
-			ip.increaseDelay(
-				n.config.InitialReconnectDelay,
-				n.config.MaxReconnectDelay,
-			)
+			// attempt, once all the IPs of the node were tried.
+			if firstOfRound {
+				ip.increaseDelay(
+					n.config.InitialReconnectDelay,
+					n.config.MaxReconnectDelay,
+				)
+			}
+
+			// If this attempt fails, the next IP of the node is tried.
+			ip.nextDialIP()
+			if dialIPBanned {
+				continue
+			}
 
-			conn, err := n.dialer.Dial(ctx, ip.ip)
+			conn, upgrader, err := n.dialPeer(ctx, nodeID, dialIP)
 			if err != nil {
 				n.peerConfig.Log.Verbo(
 					"failed to reach peer, attempting again",
-					zap.Stringer("peerIP", ip.ip.IP),
+					zap.Stringer("peerIP", dialIP.IP),
 					zap.Duration("delay", ip.delay),
 				)
 				continue
@@ -1157,14 +1290,14 @@ func (n *network) dial(ctx context.Context, nodeID ids.NodeID, ip *trackedIP) {
 
 			n.peerConfig.Log.Verbo("starting to upgrade connection",
 				zap.String("direction", "outbound"),
-				zap.Stringer("peerIP", ip.ip.IP),
+				zap.Stringer("peerIP", dialIP.IP),
 			)
 
-			err = n.upgrade(conn, n.clientUpgrader)
+			err = n.upgrade(conn, upgrader)
 			if err != nil {
 				n.peerConfig.Log.Verbo(
 					"failed to upgrade, attempting again",
-					zap.Stringer("peerIP", ip.ip.IP),
+					zap.Stringer("peerIP", dialIP.IP),
 					zap.Duration("delay", ip.delay),
 				)
 				continue
@@ -1183,6 +1316,16 @@ func (n *network) dial(ctx context.Context, nodeID ids.NodeID, ip *trackedIP) {
 // connection will be used to create a new peer. Otherwise the connection will
 // be immediately closed.
 func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
+	if ip, ok := remoteIP(conn); ok && n.isIPBanned(ip) {
+		_ = conn.Close()
+		n.peerConfig.Log.Verbo(
+			"dropping connection",
+			zap.String("reason", "IP is banned"),
+			zap.Stringer("peerIP", ip),
+		)
+		return nil
+	}
+
 	upgradeTimeout := n.peerConfig.Clock.Time().Add(n.config.ReadHandshakeTimeout)
 	if err := conn.SetReadDeadline(upgradeTimeout); err != nil {
 		_ = conn.Close()
@@ -1283,12 +1426,7 @@ func (n *network) upgrade(conn net.Conn, upgrader peer.Upgrader) error {
 		tlsConn,
 		cert,
 		nodeID,
-		peer.NewThrottledMessageQueue(
-			n.peerConfig.Metrics,
-			nodeID,
-			n.peerConfig.Log,
-			n.outboundMsgThrottler,
-		),
+		n.newMessageQueue(tlsConn, nodeID),
 	)
 	n.connectingPeers.Add(peer)
 	n.peersLock.Unlock()
@@ -1314,6 +1452,13 @@ func (n *network) StartClose() {
 				zap.Error(err),
 			)
 		}
+		if n.config.QUICListener != nil {
+			if err := n.config.QUICListener.Close(); err != nil {
+				n.peerConfig.Log.Debug("closing the QUIC listener",
+					zap.Error(err),
+				)
+			}
+		}
 
 		n.peersLock.Lock()
 		defer n.peersLock.Unlock()
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -21,6 +31,7 @@ import (
 	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
@@ -122,6 +133,8 @@ var (
 		ResourceTracker:              newDefaultResourceTracker(),
 		CPUTargeter:                  nil, // Set in init
 		DiskTargeter:                 nil, // Set in init
+		Reputation:                   reputation.NewNoTracker(),
+		Recorder:                     peer.NewNoRecorder(),
 	}
 )
 
@@ -170,6 +183,7 @@ func newTestNetwork(t *testing.T, count int) (*testDialer, []*testListener, []id
 		config.TLSConfig = tlsConfig
 		config.MyNodeID = nodeID
 		config.MyIPPort = ip
+		config.MyExtraIPPorts = ips.NewDynamicIPPorts()
 		config.TLSKey = tlsCert.PrivateKey.(crypto.Signer)
 
 		listeners[i] = listener
@@ -195,9 +209,22 @@ func newMessageCreator(t *testing.T) message.Creator {
 }
 
 func newFullyConnectedTestNetwork(t *testing.T, handlers []router.InboundHandler) ([]ids.NodeID, []Network, *sync.WaitGroup) {
-	require := require.New(t)
-
 	dialer, listeners, nodeIDs, configs := newTestNetwork(t, len(handlers))
+	networks, wg := connectTestNetworks(t, handlers, dialer, listeners, nodeIDs, configs)
+	return nodeIDs, networks, wg
+}
+
+// connectTestNetworks creates a network for each of [configs] and waits until
+// all of them are connected to each other.
+func connectTestNetworks(
+	t *testing.T,
+	handlers []router.InboundHandler,
+	dialer *testDialer,
+	listeners []*testListener,
+	nodeIDs []ids.NodeID,
+	configs []*Config,
+) ([]Network, *sync.WaitGroup) {
+	require := require.New(t)
 
 	var (
 		networks = make([]Network, len(configs))
@@ -301,7 +328,7 @@ func newFullyConnectedTestNetwork(t *testing.T, handlers []router.InboundHandler
 		<-onAllConnected
 	}
 
-	return nodeIDs, networks, &wg
+	return networks, &wg
 }
 
 func TestNewNetwork(t *testing.T) {
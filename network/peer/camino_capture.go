@@ -0,0 +1,498 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	captureVersion    uint16 = 0
+	captureFilePrefix        = "capture-"
+	captureFileSuffix        = ".bin"
+	captureMagic             = "C4TCAP"
+
+	// Number of records that can be queued to be written. Records are dropped
+	// while the queue is full, so that peers are never blocked by the disk.
+	captureQueueSize = 4096
+
+	// magic and version
+	captureHeaderLen = len(captureMagic) + wrappers.ShortLen
+
+	// direction, timestamp, node ID, op, chain ID, request ID and the length
+	// of the message bytes
+	captureRecordHeaderLen = wrappers.ByteLen + wrappers.LongLen + hashing.AddrLen +
+		wrappers.ByteLen + hashing.HashLen + wrappers.IntLen + wrappers.IntLen
+)
+
+var (
+	_ Recorder = (*FileRecorder)(nil)
+	_ Recorder = noRecorder{}
+
+	errNotCapture                 = errors.New("not a capture file")
+	errUnknownCaptureVersion      = errors.New("unknown capture version")
+	errUnknownDirection           = errors.New("unknown direction")
+	errCapturedMessageTooLarge    = errors.New("captured message too large")
+	errInvalidCaptureMaxFileSize  = errors.New("capture max file size must be > 0")
+	errInvalidCaptureMaxFileCount = errors.New("capture max files must be > 0")
+)
+
+// Direction is whether a captured message was received or sent
+type Direction byte
+
+const (
+	Inbound Direction = iota
+	Outbound
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Inbound:
+		return "inbound"
+	case Outbound:
+		return "outbound"
+	default:
+		return "unknown"
+	}
+}
+
+// Record is a message exchanged with a peer, as written to a capture file
+type Record struct {
+	Direction Direction
+	Timestamp time.Time
+	// NodeID is the node the message was received from or sent to
+	NodeID ids.NodeID
+	Op     message.Op
+	// ChainID is empty for messages that aren't sent to a chain
+	ChainID ids.ID
+	// RequestID is 0 for messages that don't have a request ID
+	RequestID uint32
+	// Bytes are the message bytes as they were sent over the wire
+	Bytes []byte
+}
+
+// Recorder captures the messages exchanged with peers
+type Recorder interface {
+	// RecordInbound records [msg] that was parsed from [msgBytes] received
+	// from [nodeID]
+	RecordInbound(nodeID ids.NodeID, msg message.InboundMessage, msgBytes []byte)
+	// RecordOutbound records [msg] that was sent to [nodeID]
+	RecordOutbound(nodeID ids.NodeID, msg message.OutboundMessage)
+}
+
+type CaptureConfig struct {
+	// Enabled is true if the messages exchanged with peers should be written
+	// to capture files
+	Enabled bool `json:"enabled"`
+	// Dir is the directory the capture files are written to
+	Dir string `json:"dir"`
+	// MaxFileSize is the size in bytes after which a new capture file is
+	// started
+	MaxFileSize uint64 `json:"maxFileSize"`
+	// MaxFiles is the number of capture files that are kept. The oldest file
+	// is removed when a new file would exceed this number.
+	MaxFiles int `json:"maxFiles"`
+}
+
+func (c *CaptureConfig) Verify() error {
+	switch {
+	case !c.Enabled:
+		return nil
+	case c.MaxFileSize == 0:
+		return errInvalidCaptureMaxFileSize
+	case c.MaxFiles <= 0:
+		return errInvalidCaptureMaxFileCount
+	default:
+		return nil
+	}
+}
+
+// queuedRecord is a record waiting to be written
+type queuedRecord struct {
+	record *Record
+	// parse is true if the chain ID and request ID of the record still have
+	// to be parsed from its bytes
+	parse bool
+}
+
+type FileRecorder struct {
+	config CaptureConfig
+	// used to parse outbound messages for their chain ID and request ID
+	creator message.Creator
+	log     logging.Logger
+	clock   mockable.Clock
+
+	// lock guards [closed] and sending on [records]
+	lock    sync.RWMutex
+	closed  bool
+	records chan queuedRecord
+	// closed once all records were written and the file was closed
+	done     chan struct{}
+	closeErr error
+
+	// Only accessed by the writer goroutine once it started
+	file      *os.File
+	writer    *bufio.Writer
+	fileSize  uint64
+	fileIndex uint64
+}
+
+// NewFileRecorder returns a recorder that writes the captured messages to a
+// rotating set of files in [config.Dir]. The messages are written in the
+// background, so recording a message never waits for the disk. Outbound
+// messages are parsed with [creator] to determine their chain ID and request
+// ID.
+func NewFileRecorder(
+	config CaptureConfig,
+	creator message.Creator,
+	log logging.Logger,
+) (*FileRecorder, error) {
+	if err := config.Verify(); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(config.Dir, perms.ReadWriteExecute); err != nil {
+		return nil, fmt.Errorf("couldn't create capture directory: %w", err)
+	}
+
+	files, err := CaptureFiles(config.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &FileRecorder{
+		config:  config,
+		creator: creator,
+		log:     log,
+		records: make(chan queuedRecord, captureQueueSize),
+		done:    make(chan struct{}),
+	}
+	if len(files) > 0 {
+		// Continue after the files of previous runs, so that files are always
+		// ordered by their names
+		r.fileIndex, _ = captureFileIndex(files[len(files)-1])
+	}
+	if err := r.rotate(); err != nil {
+		return nil, err
+	}
+
+	go r.write()
+	return r, nil
+}
+
+func (r *FileRecorder) RecordInbound(nodeID ids.NodeID, msg message.InboundMessage, msgBytes []byte) {
+	record := &Record{
+		Direction: Inbound,
+		NodeID:    nodeID,
+		Op:        msg.Op(),
+		Bytes:     msgBytes,
+	}
+	setRecordIDs(record, msg)
+	r.enqueue(queuedRecord{record: record})
+}
+
+// RecordOutbound records [msg] without parsing it. The chain ID and request ID
+// of [msg] are parsed before the record is written.
+func (r *FileRecorder) RecordOutbound(nodeID ids.NodeID, msg message.OutboundMessage) {
+	r.enqueue(queuedRecord{
+		record: &Record{
+			Direction: Outbound,
+			NodeID:    nodeID,
+			Op:        msg.Op(),
+			Bytes:     msg.Bytes(),
+		},
+		parse: true,
+	})
+}
+
+func (r *FileRecorder) enqueue(queued queuedRecord) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if r.closed {
+		return
+	}
+
+	queued.record.Timestamp = r.clock.Time()
+	select {
+	case r.records <- queued:
+	default:
+		r.log.Debug("dropping captured message",
+			zap.String("reason", "capture queue is full"),
+			zap.Stringer("nodeID", queued.record.NodeID),
+			zap.Stringer("op", queued.record.Op),
+		)
+	}
+}
+
+// write writes the queued records until the recorder is closed. The buffered
+// records are flushed whenever the queue runs empty.
+func (r *FileRecorder) write() {
+	defer close(r.done)
+
+	for queued := range r.records {
+		record := queued.record
+		if queued.parse {
+			msg, err := r.creator.Parse(record.Bytes, record.NodeID, func() {})
+			if err != nil {
+				r.log.Debug("failed to parse captured message",
+					zap.Stringer("nodeID", record.NodeID),
+					zap.Stringer("op", record.Op),
+					zap.Error(err),
+				)
+				continue
+			}
+			setRecordIDs(record, msg)
+		}
+
+		if err := r.writeRecord(record); err != nil {
+			r.log.Warn("failed to write capture file",
+				zap.Error(err),
+			)
+		}
+		if len(r.records) > 0 {
+			continue
+		}
+		if err := r.writer.Flush(); err != nil {
+			r.log.Warn("failed to flush capture file",
+				zap.Error(err),
+			)
+		}
+	}
+
+	if err := r.writer.Flush(); err != nil {
+		r.closeErr = err
+	}
+	if err := r.file.Close(); err != nil && r.closeErr == nil {
+		r.closeErr = err
+	}
+}
+
+func (r *FileRecorder) writeRecord(record *Record) error {
+	recordBytes := marshalRecord(record)
+	if r.fileSize > uint64(captureHeaderLen) && r.fileSize+uint64(len(recordBytes)) > r.config.MaxFileSize {
+		if err := r.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate capture file: %w", err)
+		}
+	}
+
+	if _, err := r.writer.Write(recordBytes); err != nil {
+		return err
+	}
+	r.fileSize += uint64(len(recordBytes))
+	return nil
+}
+
+// rotate closes the current capture file, starts a new one and removes the
+// oldest files exceeding [r.config.MaxFiles].
+//
+// Must only be called by the writer goroutine, or before it is started.
+func (r *FileRecorder) rotate() error {
+	if r.file != nil {
+		if err := r.writer.Flush(); err != nil {
+			return err
+		}
+		if err := r.file.Close(); err != nil {
+			return err
+		}
+		r.file = nil
+	}
+
+	r.fileIndex++
+	path := filepath.Join(r.config.Dir, fmt.Sprintf("%s%010d%s", captureFilePrefix, r.fileIndex, captureFileSuffix))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return err
+	}
+
+	p := wrappers.Packer{Bytes: make([]byte, captureHeaderLen)}
+	p.PackFixedBytes([]byte(captureMagic))
+	p.PackShort(captureVersion)
+	if _, err := file.Write(p.Bytes); err != nil {
+		_ = file.Close()
+		return err
+	}
+	r.file = file
+	r.writer = bufio.NewWriter(file)
+	r.fileSize = uint64(len(p.Bytes))
+
+	files, err := CaptureFiles(r.config.Dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > r.config.MaxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// Close stops the recording once the queued messages are written. Messages
+// recorded afterwards are dropped.
+func (r *FileRecorder) Close() error {
+	r.lock.Lock()
+	if r.closed {
+		r.lock.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.records)
+	r.lock.Unlock()
+
+	<-r.done
+	return r.closeErr
+}
+
+// setRecordIDs sets the chain ID and request ID of [record] to the ones of
+// [msg], if [msg] has them
+func setRecordIDs(record *Record, msg message.InboundMessage) {
+	if chainID, err := message.GetChainID(msg.Message()); err == nil {
+		record.ChainID = chainID
+	}
+	if requestID, ok := message.GetRequestID(msg.Message()); ok {
+		record.RequestID = requestID
+	}
+}
+
+func marshalRecord(record *Record) []byte {
+	p := wrappers.Packer{Bytes: make([]byte, captureRecordHeaderLen+len(record.Bytes))}
+	p.PackByte(byte(record.Direction))
+	p.PackLong(uint64(record.Timestamp.UnixNano()))
+	p.PackFixedBytes(record.NodeID[:])
+	p.PackByte(byte(record.Op))
+	p.PackFixedBytes(record.ChainID[:])
+	p.PackInt(record.RequestID)
+	p.PackBytes(record.Bytes)
+	return p.Bytes
+}
+
+// CaptureReader reads the records of a capture file
+type CaptureReader struct {
+	reader io.Reader
+	header [captureRecordHeaderLen]byte
+}
+
+// NewCaptureReader verifies the file header read from [reader] and returns a
+// reader of the records following it
+func NewCaptureReader(reader io.Reader) (*CaptureReader, error) {
+	header := make([]byte, captureHeaderLen)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("%w: %v", errNotCapture, err)
+	}
+
+	p := wrappers.Packer{Bytes: header}
+	if magic := p.UnpackFixedBytes(len(captureMagic)); string(magic) != captureMagic {
+		return nil, errNotCapture
+	}
+	if version := p.UnpackShort(); version != captureVersion {
+		return nil, fmt.Errorf("%w: %d", errUnknownCaptureVersion, version)
+	}
+	return &CaptureReader{reader: reader}, nil
+}
+
+// Next returns the next record. Returns io.EOF after the last record.
+func (r *CaptureReader) Next() (*Record, error) {
+	if _, err := io.ReadFull(r.reader, r.header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			// The last record of a capture that wasn't closed may have been
+			// written partially
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	p := wrappers.Packer{Bytes: r.header[:]}
+	record := &Record{
+		Direction: Direction(p.UnpackByte()),
+		Timestamp: time.Unix(0, int64(p.UnpackLong())),
+	}
+	copy(record.NodeID[:], p.UnpackFixedBytes(hashing.AddrLen))
+	record.Op = message.Op(p.UnpackByte())
+	copy(record.ChainID[:], p.UnpackFixedBytes(hashing.HashLen))
+	record.RequestID = p.UnpackInt()
+	msgLen := p.UnpackInt()
+
+	switch {
+	case record.Direction != Inbound && record.Direction != Outbound:
+		return nil, fmt.Errorf("%w: %d", errUnknownDirection, record.Direction)
+	case msgLen > constants.DefaultMaxMessageSize:
+		return nil, fmt.Errorf("%w: %d", errCapturedMessageTooLarge, msgLen)
+	}
+
+	record.Bytes = make([]byte, msgLen)
+	if _, err := io.ReadFull(r.reader, record.Bytes); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+	return record, nil
+}
+
+// CaptureFiles returns the paths of the capture files in [dir], from the
+// oldest to the newest
+func CaptureFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, ok := captureFileIndex(entry.Name()); ok {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		iIndex, _ := captureFileIndex(files[i])
+		jIndex, _ := captureFileIndex(files[j])
+		return iIndex < jIndex
+	})
+	return files, nil
+}
+
+func captureFileIndex(path string) (uint64, bool) {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, captureFilePrefix) || !strings.HasSuffix(name, captureFileSuffix) {
+		return 0, false
+	}
+	index, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, captureFilePrefix), captureFileSuffix), 10, 64)
+	return index, err == nil
+}
+
+type noRecorder struct{}
+
+// NewNoRecorder returns a recorder that drops all messages
+func NewNoRecorder() Recorder {
+	return noRecorder{}
+}
+
+func (noRecorder) RecordInbound(ids.NodeID, message.InboundMessage, []byte) {}
+
+func (noRecorder) RecordOutbound(ids.NodeID, message.OutboundMessage) {}
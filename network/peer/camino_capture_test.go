@@ -0,0 +1,261 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTestCaptureCreator(t *testing.T) message.Creator {
+	creator, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		"",
+		constants.DefaultNetworkCompressionType,
+		10*time.Second,
+	)
+	require.NoError(t, err)
+	return creator
+}
+
+func readCapture(t *testing.T, path string) []*Record {
+	require := require.New(t)
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+
+	reader, err := NewCaptureReader(f)
+	require.NoError(err)
+
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		require.NoError(err)
+		records = append(records, record)
+	}
+}
+
+func TestFileRecorder(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	creator := newTestCaptureCreator(t)
+	r, err := NewFileRecorder(
+		CaptureConfig{
+			Enabled:     true,
+			Dir:         dir,
+			MaxFileSize: constants.DefaultNetworkCaptureMaxFileSize,
+			MaxFiles:    1,
+		},
+		creator,
+		logging.NoLog{},
+	)
+	require.NoError(err)
+
+	now := time.Unix(1, 0)
+	r.clock.Set(now)
+
+	nodeID := ids.GenerateTestNodeID()
+	chainID := ids.GenerateTestID()
+	pullQuery, err := creator.PullQuery(chainID, 1, time.Second, ids.GenerateTestID(), p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	require.NoError(err)
+	r.RecordOutbound(nodeID, pullQuery)
+
+	chits, err := creator.Chits(chainID, 1, []ids.ID{ids.GenerateTestID()}, nil)
+	require.NoError(err)
+	inMsg, err := creator.Parse(chits.Bytes(), nodeID, func() {})
+	require.NoError(err)
+	r.RecordInbound(nodeID, inMsg, chits.Bytes())
+
+	ping, err := creator.Ping()
+	require.NoError(err)
+	r.RecordOutbound(nodeID, ping)
+
+	require.NoError(r.Close())
+
+	// Messages recorded after closing are dropped
+	r.RecordOutbound(nodeID, ping)
+
+	files, err := CaptureFiles(dir)
+	require.NoError(err)
+	require.Len(files, 1)
+	require.Equal(
+		[]*Record{
+			{
+				Direction: Outbound,
+				Timestamp: now,
+				NodeID:    nodeID,
+				Op:        message.PullQueryOp,
+				ChainID:   chainID,
+				RequestID: 1,
+				Bytes:     pullQuery.Bytes(),
+			},
+			{
+				Direction: Inbound,
+				Timestamp: now,
+				NodeID:    nodeID,
+				Op:        message.ChitsOp,
+				ChainID:   chainID,
+				RequestID: 1,
+				Bytes:     chits.Bytes(),
+			},
+			{
+				Direction: Outbound,
+				Timestamp: now,
+				NodeID:    nodeID,
+				Op:        message.PingOp,
+				Bytes:     ping.Bytes(),
+			},
+		},
+		readCapture(t, files[0]),
+	)
+}
+
+func TestFileRecorderRotation(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	creator := newTestCaptureCreator(t)
+	config := CaptureConfig{
+		Enabled:     true,
+		Dir:         dir,
+		MaxFileSize: 1,
+		MaxFiles:    2,
+	}
+	r, err := NewFileRecorder(config, creator, logging.NoLog{})
+	require.NoError(err)
+
+	nodeID := ids.GenerateTestNodeID()
+	ping, err := creator.Ping()
+	require.NoError(err)
+
+	// Every file exceeds the max file size after its first record
+	for i := 0; i < 3; i++ {
+		r.RecordOutbound(nodeID, ping)
+	}
+	require.NoError(r.Close())
+
+	files, err := CaptureFiles(dir)
+	require.NoError(err)
+	require.Equal(
+		[]string{
+			filepath.Join(dir, "capture-0000000002.bin"),
+			filepath.Join(dir, "capture-0000000003.bin"),
+		},
+		files,
+	)
+	for _, file := range files {
+		require.Len(readCapture(t, file), 1)
+	}
+
+	// A restarted recorder continues after the files of the previous run
+	r, err = NewFileRecorder(config, creator, logging.NoLog{})
+	require.NoError(err)
+	require.NoError(r.Close())
+
+	files, err = CaptureFiles(dir)
+	require.NoError(err)
+	require.Equal(
+		[]string{
+			filepath.Join(dir, "capture-0000000003.bin"),
+			filepath.Join(dir, "capture-0000000004.bin"),
+		},
+		files,
+	)
+}
+
+func TestCaptureReaderTruncated(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	creator := newTestCaptureCreator(t)
+	r, err := NewFileRecorder(
+		CaptureConfig{
+			Enabled:     true,
+			Dir:         dir,
+			MaxFileSize: constants.DefaultNetworkCaptureMaxFileSize,
+			MaxFiles:    1,
+		},
+		creator,
+		logging.NoLog{},
+	)
+	require.NoError(err)
+
+	nodeID := ids.GenerateTestNodeID()
+	ping, err := creator.Ping()
+	require.NoError(err)
+	r.RecordOutbound(nodeID, ping)
+	r.RecordOutbound(nodeID, ping)
+	require.NoError(r.Close())
+
+	files, err := CaptureFiles(dir)
+	require.NoError(err)
+	require.Len(files, 1)
+
+	// The last record is cut off as if the node crashed while writing it
+	info, err := os.Stat(files[0])
+	require.NoError(err)
+	require.NoError(os.Truncate(files[0], info.Size()-1))
+	require.Len(readCapture(t, files[0]), 1)
+}
+
+func TestCaptureConfigVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		config CaptureConfig
+		err    error
+	}{
+		{
+			name:   "disabled",
+			config: CaptureConfig{},
+		},
+		{
+			name: "valid",
+			config: CaptureConfig{
+				Enabled:     true,
+				MaxFileSize: 1,
+				MaxFiles:    1,
+			},
+		},
+		{
+			name: "no max file size",
+			config: CaptureConfig{
+				Enabled:  true,
+				MaxFiles: 1,
+			},
+			err: errInvalidCaptureMaxFileSize,
+		},
+		{
+			name: "no max files",
+			config: CaptureConfig{
+				Enabled:     true,
+				MaxFileSize: 1,
+			},
+			err: errInvalidCaptureMaxFileCount,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.config.Verify(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+// MaxExtraIPPorts is the maximum number of endpoints a node advertises besides
+// its primary IP
+const MaxExtraIPPorts = 4
+
+// extraIPPortsPrefix separates the signed extra IPs from the signed primary IP
+var extraIPPortsPrefix = []byte("extra-ip-ports")
+
+var (
+	errTooManyExtraIPPorts   = errors.New("too many extra IPs")
+	errMissingExtraSignature = errors.New("missing signature of extra IPs")
+	errInvalidIPLen          = errors.New("invalid IP length")
+	errInvalidPort           = errors.New("invalid port")
+)
+
+// IPPorts returns the primary IP followed by the extra IPs
+func (ip *UnsignedIP) IPPorts() []ips.IPPort {
+	return append([]ips.IPPort{ip.IPPort}, ip.ExtraIPPorts...)
+}
+
+// extraIPPortsBytes returns the bytes signed to claim the extra IPs. The
+// primary IP and the timestamp are included so that the extra IPs can't be
+// replayed with another primary IP.
+func (ip *UnsignedIP) extraIPPortsBytes() []byte {
+	p := wrappers.Packer{
+		Bytes: make([]byte, len(extraIPPortsPrefix)+wrappers.IPLen+wrappers.LongLen+wrappers.IntLen+len(ip.ExtraIPPorts)*wrappers.IPLen),
+	}
+	p.PackFixedBytes(extraIPPortsPrefix)
+	ips.PackIP(&p, ip.IPPort)
+	p.PackLong(ip.Timestamp)
+	p.PackInt(uint32(len(ip.ExtraIPPorts)))
+	for _, extraIPPort := range ip.ExtraIPPorts {
+		ips.PackIP(&p, extraIPPort)
+	}
+	return p.Bytes
+}
+
+func (ip *UnsignedIP) signExtraIPPorts(signer crypto.Signer) ([]byte, error) {
+	if len(ip.ExtraIPPorts) == 0 {
+		return nil, nil
+	}
+	if len(ip.ExtraIPPorts) > MaxExtraIPPorts {
+		return nil, fmt.Errorf("%w: %d > %d", errTooManyExtraIPPorts, len(ip.ExtraIPPorts), MaxExtraIPPorts)
+	}
+	return signer.Sign(
+		rand.Reader,
+		hashing.ComputeHash256(ip.extraIPPortsBytes()),
+		crypto.SHA256,
+	)
+}
+
+func (ip *SignedIP) verifyExtraIPPorts(cert *x509.Certificate) error {
+	switch {
+	case len(ip.ExtraIPPorts) == 0:
+		return nil
+	case len(ip.ExtraIPPorts) > MaxExtraIPPorts:
+		return fmt.Errorf("%w: %d > %d", errTooManyExtraIPPorts, len(ip.ExtraIPPorts), MaxExtraIPPorts)
+	case len(ip.ExtraSignature) == 0:
+		return errMissingExtraSignature
+	}
+	return cert.CheckSignature(
+		cert.SignatureAlgorithm,
+		ip.extraIPPortsBytes(),
+		ip.ExtraSignature,
+	)
+}
+
+// parseExtraIPPorts parses the extra IPs of a Version or PeerList message
+func parseExtraIPPorts(ipPorts []*p2p.IpPort) ([]ips.IPPort, error) {
+	if len(ipPorts) == 0 {
+		return nil, nil
+	}
+	if len(ipPorts) > MaxExtraIPPorts {
+		return nil, fmt.Errorf("%w: %d > %d", errTooManyExtraIPPorts, len(ipPorts), MaxExtraIPPorts)
+	}
+	result := make([]ips.IPPort, len(ipPorts))
+	for i, ipPort := range ipPorts {
+		// "net.IP" type in Golang is 16-byte
+		if ipLen := len(ipPort.IpAddr); ipLen != net.IPv6len {
+			return nil, fmt.Errorf("%w: %d", errInvalidIPLen, ipLen)
+		}
+		if ipPort.IpPort > math.MaxUint16 {
+			return nil, fmt.Errorf("%w: %d", errInvalidPort, ipPort.IpPort)
+		}
+		result[i] = ips.IPPort{
+			IP:   ipPort.IpAddr,
+			Port: uint16(ipPort.IpPort),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/ips"
+)
+
+func TestSignedIPExtraIPPorts(t *testing.T) {
+	tlsCert, err := staking.NewTLSCert()
+	require.NoError(t, err)
+	key := tlsCert.PrivateKey.(crypto.Signer)
+	cert := tlsCert.Leaf
+
+	extraIPPorts := []ips.IPPort{
+		{IP: net.ParseIP("2001:db8::1"), Port: 9651},
+		{IP: net.IPv4(10, 0, 0, 1), Port: 9651},
+	}
+
+	tests := map[string]struct {
+		modify      func(*SignedIP)
+		expectedErr error
+	}{
+		"valid": {
+			modify: func(*SignedIP) {},
+		},
+		"tampered extra IP": {
+			modify: func(ip *SignedIP) {
+				ip.ExtraIPPorts = []ips.IPPort{
+					{IP: net.IPv4(6, 6, 6, 6), Port: 9651},
+					ip.ExtraIPPorts[1],
+				}
+			},
+			expectedErr: rsa.ErrVerification,
+		},
+		"reordered extra IPs": {
+			modify: func(ip *SignedIP) {
+				ip.ExtraIPPorts = []ips.IPPort{ip.ExtraIPPorts[1], ip.ExtraIPPorts[0]}
+			},
+			expectedErr: rsa.ErrVerification,
+		},
+		"replayed with other primary IP": {
+			modify: func(ip *SignedIP) {
+				other, err := (&UnsignedIP{
+					IPPort:    ips.IPPort{IP: net.IPv4(5, 5, 5, 5), Port: 9651},
+					Timestamp: ip.Timestamp,
+				}).Sign(key)
+				require.NoError(t, err)
+				ip.IPPort = other.IPPort
+				ip.Signature = other.Signature
+			},
+			expectedErr: rsa.ErrVerification,
+		},
+		"missing extra signature": {
+			modify: func(ip *SignedIP) {
+				ip.ExtraSignature = nil
+			},
+			expectedErr: errMissingExtraSignature,
+		},
+		"too many extra IPs": {
+			modify: func(ip *SignedIP) {
+				for len(ip.ExtraIPPorts) <= MaxExtraIPPorts {
+					ip.ExtraIPPorts = append(ip.ExtraIPPorts, ip.ExtraIPPorts[0])
+				}
+			},
+			expectedErr: errTooManyExtraIPPorts,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			signedIP, err := (&UnsignedIP{
+				IPPort:       ips.IPPort{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+				Timestamp:    10,
+				ExtraIPPorts: extraIPPorts,
+			}).Sign(key)
+			require.NoError(err)
+			require.NotEmpty(signedIP.ExtraSignature)
+
+			tt.modify(signedIP)
+			err = signedIP.Verify(cert)
+			require.ErrorIs(err, tt.expectedErr)
+		})
+	}
+}
+
+func TestSignedIPWithoutExtraIPPorts(t *testing.T) {
+	require := require.New(t)
+
+	tlsCert, err := staking.NewTLSCert()
+	require.NoError(err)
+
+	signedIP, err := (&UnsignedIP{
+		IPPort:    ips.IPPort{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+		Timestamp: 10,
+	}).Sign(tlsCert.PrivateKey.(crypto.Signer))
+	require.NoError(err)
+	require.Empty(signedIP.ExtraSignature)
+	require.NoError(signedIP.Verify(tlsCert.Leaf))
+}
+
+func TestIPSignerExtraIPPorts(t *testing.T) {
+	require := require.New(t)
+
+	tlsCert, err := staking.NewTLSCert()
+	require.NoError(err)
+
+	dynIP := ips.NewDynamicIPPort(net.IPv4(1, 2, 3, 4), 9651)
+	extraIPs := ips.NewDynamicIPPorts()
+	s := NewIPSigner(dynIP, extraIPs, tlsCert.PrivateKey.(crypto.Signer))
+
+	s.clock.Set(time.Unix(10, 0))
+	signedIP1, err := s.GetSignedIP()
+	require.NoError(err)
+	require.Empty(signedIP1.ExtraIPPorts)
+
+	// Changing the extra IPs causes a new signature
+	s.clock.Set(time.Unix(11, 0))
+	extraIPs.SetIPPorts([]ips.IPPort{{IP: net.ParseIP("2001:db8::1"), Port: 9651}})
+	signedIP2, err := s.GetSignedIP()
+	require.NoError(err)
+	require.EqualValues(11, signedIP2.Timestamp)
+	require.Equal(extraIPs.IPPorts(), signedIP2.ExtraIPPorts)
+	require.NoError(signedIP2.Verify(tlsCert.Leaf))
+
+	// Unchanged IPs reuse the signature
+	s.clock.Set(time.Unix(12, 0))
+	signedIP3, err := s.GetSignedIP()
+	require.NoError(err)
+	require.Equal(signedIP2, signedIP3)
+}
+
+func TestParseExtraIPPorts(t *testing.T) {
+	require := require.New(t)
+
+	ipPorts, err := parseExtraIPPorts([]*p2p.IpPort{
+		{IpAddr: net.ParseIP("2001:db8::1"), IpPort: 9651},
+	})
+	require.NoError(err)
+	require.Equal([]ips.IPPort{{IP: net.ParseIP("2001:db8::1"), Port: 9651}}, ipPorts)
+
+	_, err = parseExtraIPPorts([]*p2p.IpPort{
+		{IpAddr: []byte{1, 2, 3, 4}, IpPort: 9651},
+	})
+	require.ErrorIs(err, errInvalidIPLen)
+
+	_, err = parseExtraIPPorts([]*p2p.IpPort{
+		{IpAddr: net.ParseIP("2001:db8::1"), IpPort: 1 << 16},
+	})
+	require.ErrorIs(err, errInvalidPort)
+}
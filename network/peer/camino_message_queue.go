@@ -0,0 +1,340 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/utils/buffer"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/metric"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+var _ MessageQueue = (*prioritizedMessageQueue)(nil)
+
+// Priority is the class of an outbound message. Messages of a lower priority
+// value are sent before messages of a higher priority value.
+type Priority int
+
+const (
+	// ConsensusPriority contains the handshake, queries and chits.
+	ConsensusPriority Priority = iota
+	// AcceptancePriority contains the gossip and requests of accepted
+	// containers.
+	AcceptancePriority
+	// AppPriority contains the messages of the VMs.
+	AppPriority
+	// BulkPriority contains the bulk data requested while bootstrapping and
+	// state syncing.
+	BulkPriority
+	// NumPriorities is the number of message priorities.
+	NumPriorities
+)
+
+func (p Priority) String() string {
+	switch p {
+	case ConsensusPriority:
+		return "consensus"
+	case AcceptancePriority:
+		return "acceptance"
+	case AppPriority:
+		return "app"
+	case BulkPriority:
+		return "bulk"
+	default:
+		return "unknown"
+	}
+}
+
+// PriorityOf returns the priority outbound messages with [op] are queued with
+func PriorityOf(op message.Op) Priority {
+	switch message.ClassOf(op) {
+	case message.AcceptanceClass:
+		return AcceptancePriority
+	case message.AppClass:
+		return AppPriority
+	case message.BulkClass:
+		return BulkPriority
+	default:
+		return ConsensusPriority
+	}
+}
+
+type MessageQueueConfig struct {
+	// MaxQueuedBytes is the max number of bytes of messages of each priority
+	// that can be queued for a peer. If 0, the messages of the priority are
+	// only limited by the outbound message throttler.
+	MaxQueuedBytes [NumPriorities]uint64 `json:"maxQueuedBytes"`
+	// MaxStarvation is the max time a message is queued while messages of a
+	// higher priority are sent. If 0, messages of a lower priority are only
+	// sent once there are no messages of a higher priority queued.
+	MaxStarvation time.Duration `json:"maxStarvation"`
+}
+
+// QueueMetrics are the metrics of the prioritized message queues of all
+// peers.
+type QueueMetrics struct {
+	Latency [NumPriorities]metric.Averager
+	Dropped [NumPriorities]prometheus.Counter
+}
+
+func NewQueueMetrics(namespace string, registerer prometheus.Registerer) (*QueueMetrics, error) {
+	m := &QueueMetrics{}
+	errs := wrappers.Errs{}
+	for p := Priority(0); p < NumPriorities; p++ {
+		m.Latency[p] = metric.NewAveragerWithErrs(
+			namespace,
+			fmt.Sprintf("%s_queue_latency", p),
+			fmt.Sprintf("time (in ns) %s messages spent in the outbound queue of a peer", p),
+			registerer,
+			&errs,
+		)
+		m.Dropped[p] = prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s_queue_dropped", p),
+			Help:      fmt.Sprintf("Number of %s messages dropped because the outbound queue of a peer was full", p),
+		})
+		errs.Add(registerer.Register(m.Dropped[p]))
+	}
+	return m, errs.Err
+}
+
+type queuedMessage struct {
+	msg    message.OutboundMessage
+	pushed time.Time
+}
+
+type prioritizedMessageQueue struct {
+	config   MessageQueueConfig
+	metrics  *QueueMetrics
+	onFailed SendFailedCallback
+	// [id] of the peer we're sending messages to
+	id                   ids.NodeID
+	log                  logging.Logger
+	outboundMsgThrottler throttling.OutboundMsgThrottler
+	clock                mockable.Clock
+
+	// Signalled when a message is added to the queue and when Close() is
+	// called.
+	cond *sync.Cond
+
+	// closed flags whether the send queue has been closed.
+	// [cond.L] must be held while accessing [closed].
+	closed bool
+
+	// queues of the messages of each priority
+	// [cond.L] must be held while accessing [queues], [queuedBytes] and
+	// [len].
+	queues      [NumPriorities]buffer.Deque[queuedMessage]
+	queuedBytes [NumPriorities]uint64
+	len         int
+}
+
+// NewPrioritizedMessageQueue returns a message queue that sends messages in
+// the order of their [Priority]. Messages of the same priority are sent in
+// the order they were pushed.
+func NewPrioritizedMessageQueue(
+	config MessageQueueConfig,
+	metrics *QueueMetrics,
+	onFailed SendFailedCallback,
+	id ids.NodeID,
+	log logging.Logger,
+	outboundMsgThrottler throttling.OutboundMsgThrottler,
+) MessageQueue {
+	q := &prioritizedMessageQueue{
+		config:               config,
+		metrics:              metrics,
+		onFailed:             onFailed,
+		id:                   id,
+		log:                  log,
+		outboundMsgThrottler: outboundMsgThrottler,
+		cond:                 sync.NewCond(&sync.Mutex{}),
+	}
+	for p := range q.queues {
+		q.queues[p] = buffer.NewUnboundedDeque[queuedMessage](initialQueueSize)
+	}
+	return q
+}
+
+func (q *prioritizedMessageQueue) Push(ctx context.Context, msg message.OutboundMessage) bool {
+	if err := ctx.Err(); err != nil {
+		q.log.Debug(
+			"dropping outgoing message",
+			zap.Stringer("messageOp", msg.Op()),
+			zap.Stringer("nodeID", q.id),
+			zap.Error(err),
+		)
+		q.onFailed.SendFailed(msg)
+		return false
+	}
+
+	// Acquire space on the outbound message queue, or drop [msg] if we can't.
+	if !q.outboundMsgThrottler.Acquire(msg, q.id) {
+		q.log.Debug(
+			"dropping outgoing message",
+			zap.String("reason", "rate-limiting"),
+			zap.Stringer("messageOp", msg.Op()),
+			zap.Stringer("nodeID", q.id),
+		)
+		q.onFailed.SendFailed(msg)
+		return false
+	}
+
+	// Invariant: must call q.outboundMsgThrottler.Release(msg, q.id) when [msg]
+	// is popped or, if this queue closes before [msg] is popped, when this
+	// queue closes.
+
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.closed {
+		q.log.Debug(
+			"dropping outgoing message",
+			zap.String("reason", "closed queue"),
+			zap.Stringer("messageOp", msg.Op()),
+			zap.Stringer("nodeID", q.id),
+		)
+		q.outboundMsgThrottler.Release(msg, q.id)
+		q.onFailed.SendFailed(msg)
+		return false
+	}
+
+	priority := PriorityOf(msg.Op())
+	msgLen := uint64(len(msg.Bytes()))
+	if maxBytes := q.config.MaxQueuedBytes[priority]; maxBytes != 0 && q.queuedBytes[priority]+msgLen > maxBytes {
+		q.log.Debug(
+			"dropping outgoing message",
+			zap.String("reason", "priority queue full"),
+			zap.Stringer("priority", priority),
+			zap.Stringer("messageOp", msg.Op()),
+			zap.Stringer("nodeID", q.id),
+		)
+		q.metrics.Dropped[priority].Inc()
+		q.outboundMsgThrottler.Release(msg, q.id)
+		q.onFailed.SendFailed(msg)
+		return false
+	}
+
+	q.queues[priority].PushRight(queuedMessage{
+		msg:    msg,
+		pushed: q.clock.Time(),
+	})
+	q.queuedBytes[priority] += msgLen
+	q.len++
+	q.cond.Signal()
+	return true
+}
+
+func (q *prioritizedMessageQueue) Pop() (message.OutboundMessage, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	for {
+		if q.closed {
+			return nil, false
+		}
+		if q.len > 0 {
+			// There is a message
+			break
+		}
+		// Wait until there is a message
+		q.cond.Wait()
+	}
+
+	return q.pop(), true
+}
+
+func (q *prioritizedMessageQueue) PopNow() (message.OutboundMessage, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.closed || q.len == 0 {
+		// There isn't a message
+		return nil, false
+	}
+
+	return q.pop(), true
+}
+
+// Assumes [cond.L] is held and that there is a queued message.
+func (q *prioritizedMessageQueue) pop() message.OutboundMessage {
+	now := q.clock.Time()
+	priority := q.next(now)
+	queued, _ := q.queues[priority].PopLeft()
+	msg := queued.msg
+
+	q.queuedBytes[priority] -= uint64(len(msg.Bytes()))
+	q.len--
+	q.metrics.Latency[priority].Observe(float64(now.Sub(queued.pushed)))
+	q.outboundMsgThrottler.Release(msg, q.id)
+	return msg
+}
+
+// next returns the priority of the message to send next. This is the priority
+// with the oldest message that was queued for at least [MaxStarvation] or, if
+// there isn't such a message, the highest priority with a queued message.
+//
+// Assumes [cond.L] is held and that there is a queued message.
+func (q *prioritizedMessageQueue) next(now time.Time) Priority {
+	var (
+		highest = NumPriorities
+		starved = NumPriorities
+		oldest  time.Time
+	)
+	for p, queue := range q.queues {
+		head, ok := queue.PeekLeft()
+		if !ok {
+			continue
+		}
+		if highest == NumPriorities {
+			highest = Priority(p)
+		}
+		if q.config.MaxStarvation > 0 &&
+			now.Sub(head.pushed) >= q.config.MaxStarvation &&
+			(starved == NumPriorities || head.pushed.Before(oldest)) {
+			starved = Priority(p)
+			oldest = head.pushed
+		}
+	}
+	if starved != NumPriorities {
+		return starved
+	}
+	return highest
+}
+
+func (q *prioritizedMessageQueue) Close() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+
+	if q.closed {
+		return
+	}
+
+	q.closed = true
+
+	for p, queue := range q.queues {
+		for queue.Len() > 0 {
+			queued, _ := queue.PopLeft()
+			q.outboundMsgThrottler.Release(queued.msg, q.id)
+			q.onFailed.SendFailed(queued.msg)
+		}
+		q.queues[p] = nil
+		q.queuedBytes[p] = 0
+	}
+	q.len = 0
+
+	q.cond.Broadcast()
+}
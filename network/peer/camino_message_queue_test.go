@@ -0,0 +1,202 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestPriorityOf(t *testing.T) {
+	tests := map[message.Op]Priority{
+		message.VersionOp:              ConsensusPriority,
+		message.PingOp:                 ConsensusPriority,
+		message.PushQueryOp:            ConsensusPriority,
+		message.PullQueryOp:            ConsensusPriority,
+		message.ChitsOp:                ConsensusPriority,
+		message.GetAcceptedOp:          AcceptancePriority,
+		message.PutOp:                  AcceptancePriority,
+		message.AppGossipOp:            AppPriority,
+		message.AppResponseOp:          AppPriority,
+		message.GetAncestorsOp:         BulkPriority,
+		message.AncestorsOp:            BulkPriority,
+		message.StateSummaryFrontierOp: BulkPriority,
+	}
+	for op, expected := range tests {
+		t.Run(op.String(), func(t *testing.T) {
+			require.Equal(t, expected, PriorityOf(op))
+		})
+	}
+}
+
+type testPrioritizedMessages struct {
+	consensus, acceptance, app, bulk message.OutboundMessage
+}
+
+func newTestPrioritizedMessages(t *testing.T) testPrioritizedMessages {
+	require := require.New(t)
+
+	mc := newMessageCreator(t)
+	chainID := ids.GenerateTestID()
+	consensus, err := mc.Chits(chainID, 1, []ids.ID{ids.GenerateTestID()}, nil)
+	require.NoError(err)
+	acceptance, err := mc.Put(chainID, 1, []byte{1}, 0)
+	require.NoError(err)
+	app, err := mc.AppGossip(chainID, []byte{2})
+	require.NoError(err)
+	bulk, err := mc.Ancestors(chainID, 1, [][]byte{{3}})
+	require.NoError(err)
+	return testPrioritizedMessages{
+		consensus:  consensus,
+		acceptance: acceptance,
+		app:        app,
+		bulk:       bulk,
+	}
+}
+
+func newTestPrioritizedMessageQueue(
+	t *testing.T,
+	config MessageQueueConfig,
+	onFailed SendFailedFunc,
+) *prioritizedMessageQueue {
+	metrics, err := NewQueueMetrics("", prometheus.NewRegistry())
+	require.NoError(t, err)
+	return NewPrioritizedMessageQueue(
+		config,
+		metrics,
+		onFailed,
+		ids.GenerateTestNodeID(),
+		logging.NoLog{},
+		throttling.NewNoOutboundThrottler(),
+	).(*prioritizedMessageQueue)
+}
+
+func TestPrioritizedMessageQueueOrder(t *testing.T) {
+	require := require.New(t)
+
+	msgs := newTestPrioritizedMessages(t)
+	q := newTestPrioritizedMessageQueue(t, MessageQueueConfig{}, func(message.OutboundMessage) {
+		require.FailNow("unexpected send failure")
+	})
+
+	ctx := context.Background()
+	require.True(q.Push(ctx, msgs.bulk))
+	require.True(q.Push(ctx, msgs.app))
+	require.True(q.Push(ctx, msgs.acceptance))
+	require.True(q.Push(ctx, msgs.consensus))
+
+	for _, expected := range []message.OutboundMessage{
+		msgs.consensus,
+		msgs.acceptance,
+		msgs.app,
+		msgs.bulk,
+	} {
+		msg, ok := q.Pop()
+		require.True(ok)
+		require.Equal(expected, msg)
+	}
+
+	_, ok := q.PopNow()
+	require.False(ok)
+	require.Zero(q.queuedBytes)
+}
+
+func TestPrioritizedMessageQueueMaxQueuedBytes(t *testing.T) {
+	require := require.New(t)
+
+	msgs := newTestPrioritizedMessages(t)
+	config := MessageQueueConfig{}
+	config.MaxQueuedBytes[AppPriority] = uint64(len(msgs.app.Bytes()))
+
+	var failed []message.OutboundMessage
+	q := newTestPrioritizedMessageQueue(t, config, func(msg message.OutboundMessage) {
+		failed = append(failed, msg)
+	})
+
+	ctx := context.Background()
+	require.True(q.Push(ctx, msgs.app))
+	require.False(q.Push(ctx, msgs.app))
+	require.Equal([]message.OutboundMessage{msgs.app}, failed)
+
+	// Other priorities have their own budget
+	require.True(q.Push(ctx, msgs.bulk))
+	require.True(q.Push(ctx, msgs.bulk))
+
+	// Popping the message frees the budget
+	msg, ok := q.PopNow()
+	require.True(ok)
+	require.Equal(msgs.app, msg)
+	require.True(q.Push(ctx, msgs.app))
+}
+
+func TestPrioritizedMessageQueueMaxStarvation(t *testing.T) {
+	require := require.New(t)
+
+	msgs := newTestPrioritizedMessages(t)
+	q := newTestPrioritizedMessageQueue(t, MessageQueueConfig{MaxStarvation: time.Second}, func(message.OutboundMessage) {
+		require.FailNow("unexpected send failure")
+	})
+
+	now := time.Unix(1, 0)
+	q.clock.Set(now)
+
+	ctx := context.Background()
+	require.True(q.Push(ctx, msgs.bulk))
+	q.clock.Set(now.Add(100 * time.Millisecond))
+	require.True(q.Push(ctx, msgs.app))
+	q.clock.Set(now.Add(200 * time.Millisecond))
+	require.True(q.Push(ctx, msgs.consensus))
+	require.True(q.Push(ctx, msgs.consensus))
+
+	// Messages that didn't wait for [MaxStarvation] are sent by priority
+	msg, ok := q.PopNow()
+	require.True(ok)
+	require.Equal(msgs.consensus, msg)
+
+	// Starved messages are sent before messages of a higher priority
+	q.clock.Set(now.Add(time.Second))
+	msg, ok = q.PopNow()
+	require.True(ok)
+	require.Equal(msgs.bulk, msg)
+
+	msg, ok = q.PopNow()
+	require.True(ok)
+	require.Equal(msgs.consensus, msg)
+
+	msg, ok = q.PopNow()
+	require.True(ok)
+	require.Equal(msgs.app, msg)
+}
+
+func TestPrioritizedMessageQueueClose(t *testing.T) {
+	require := require.New(t)
+
+	msgs := newTestPrioritizedMessages(t)
+	var failed []message.OutboundMessage
+	q := newTestPrioritizedMessageQueue(t, MessageQueueConfig{}, func(msg message.OutboundMessage) {
+		failed = append(failed, msg)
+	})
+
+	ctx := context.Background()
+	require.True(q.Push(ctx, msgs.app))
+	require.True(q.Push(ctx, msgs.consensus))
+
+	q.Close()
+	require.Len(failed, 2)
+
+	_, ok := q.Pop()
+	require.False(ok)
+	require.False(q.Push(ctx, msgs.bulk))
+	require.Len(failed, 3)
+}
@@ -0,0 +1,279 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/throttling"
+)
+
+var _ StreamMessageQueue = (*streamMessageQueue)(nil)
+
+const (
+	TransportTCP  = "tcp"
+	TransportQUIC = "quic"
+)
+
+const (
+	// ControlStream carries the handshake and every message that isn't
+	// assigned to a more specific stream.
+	ControlStream = iota
+	// ConsensusStream carries the messages of the consensus and acceptance
+	// classes, which are latency sensitive.
+	ConsensusStream
+	// BulkStream carries the messages of the app and bulk classes, which may
+	// contain large amounts of containers or application data.
+	BulkStream
+	// NumStreams is the number of streams of a [MultiStreamConn].
+	NumStreams
+)
+
+// MultiStreamConn is a connection that multiplexes independent streams, so
+// that a large message on one stream doesn't delay the messages of the others.
+// The connection itself reads and writes the [ControlStream]. Closing the
+// connection closes all of its streams.
+type MultiStreamConn interface {
+	net.Conn
+
+	// Streams returns the [NumStreams] streams of the connection, indexed by
+	// their stream class.
+	Streams() []net.Conn
+}
+
+// StreamName returns the name of [stream], as used in metrics
+func StreamName(stream int) string {
+	switch stream {
+	case ControlStream:
+		return "control"
+	case ConsensusStream:
+		return "consensus"
+	case BulkStream:
+		return "bulk"
+	default:
+		return "unknown"
+	}
+}
+
+// StreamOf returns the stream class messages with [op] are sent on
+func StreamOf(op message.Op) int {
+	switch message.ClassOf(op) {
+	case message.ConsensusClass, message.AcceptanceClass:
+		return ConsensusStream
+	case message.AppClass, message.BulkClass:
+		return BulkStream
+	default:
+		return ControlStream
+	}
+}
+
+// StreamMessageQueue is the message queue of a peer connected with a
+// [MultiStreamConn]. Each stream has its own queue, so that a full queue of
+// one stream doesn't delay the messages of the others.
+type StreamMessageQueue interface {
+	// Push adds the message to the queue of the stream it's sent on.
+	//
+	// Pop and PopNow only return the messages of the [ControlStream]. Close
+	// closes the queues of all streams.
+	MessageQueue
+
+	// Stream returns the queue of the messages sent on [stream].
+	Stream(stream int) MessageQueue
+}
+
+type streamMessageQueue [NumStreams]MessageQueue
+
+// NewStreamMessageQueue returns a message queue with one queue, returned by
+// [newQueue], per stream.
+func NewStreamMessageQueue(newQueue func() MessageQueue) StreamMessageQueue {
+	q := streamMessageQueue{}
+	for i := range q {
+		q[i] = newQueue()
+	}
+	return &q
+}
+
+func (q *streamMessageQueue) Push(ctx context.Context, msg message.OutboundMessage) bool {
+	return q[StreamOf(msg.Op())].Push(ctx, msg)
+}
+
+func (q *streamMessageQueue) Pop() (message.OutboundMessage, bool) {
+	return q[ControlStream].Pop()
+}
+
+func (q *streamMessageQueue) PopNow() (message.OutboundMessage, bool) {
+	return q[ControlStream].PopNow()
+}
+
+func (q *streamMessageQueue) Close() {
+	for _, queue := range q {
+		queue.Close()
+	}
+}
+
+func (q *streamMessageQueue) Stream(stream int) MessageQueue {
+	return q[stream]
+}
+
+// readStreams reads and handles messages from all streams of [conn]. Each
+// stream is read by its own goroutine and throttled by its own throttler.
+// When this method returns, the connection is closed.
+func (p *peer) readStreams(conn MultiStreamConn) {
+	throttlers := [NumStreams]throttling.InboundMsgThrottler{}
+	for i := range throttlers {
+		throttlers[i] = p.streamInboundMsgThrottler(i)
+		throttlers[i].AddNode(p.id)
+	}
+	defer func() {
+		for _, throttler := range throttlers {
+			throttler.RemoveNode(p.id)
+		}
+		p.StartClose()
+		p.close()
+	}()
+
+	streams := conn.Streams()
+	wg := sync.WaitGroup{}
+	wg.Add(len(streams) - 1)
+	for i, stream := range streams[ControlStream+1:] {
+		go func(stream net.Conn, throttler throttling.InboundMsgThrottler) {
+			defer func() {
+				p.StartClose()
+				wg.Done()
+			}()
+
+			// The messages of the other streams are only handled after the
+			// handshake, which is done on the control stream. As these
+			// streams can be idle for a long time, their reads don't time
+			// out. The pings on the control stream keep the connection alive.
+			select {
+			case <-p.onFinishHandshake:
+			case <-p.onClosingCtx.Done():
+				return
+			}
+			p.readConnMessages(stream, throttler, false)
+		}(stream, throttlers[ControlStream+1+i])
+	}
+
+	p.readConnMessages(streams[ControlStream], throttlers[ControlStream], true /*=setDeadlines*/)
+
+	// The readers of the other streams may still be waiting for the
+	// handshake. The throttlers must not be used after this peer called
+	// RemoveNode.
+	p.StartClose()
+	wg.Wait()
+}
+
+// streamInboundMsgThrottler returns the throttler of the messages read from
+// [stream].
+func (p *peer) streamInboundMsgThrottler(stream int) throttling.InboundMsgThrottler {
+	if stream == ControlStream {
+		return p.InboundMsgThrottler
+	}
+	if throttler := p.StreamInboundMsgThrottlers[stream]; throttler != nil {
+		return throttler
+	}
+	return throttling.NewNoInboundThrottler()
+}
+
+// writeStreams writes the messages of this peer to the streams of [conn].
+// Each stream is written by its own goroutine from its own queue.
+func (p *peer) writeStreams(conn MultiStreamConn) {
+	defer func() {
+		p.StartClose()
+		p.close()
+	}()
+
+	queue, ok := p.messageQueue.(StreamMessageQueue)
+	if !ok {
+		p.Log.Error("multi-stream connection without stream message queue",
+			zap.Stringer("nodeID", p.id),
+		)
+		return
+	}
+
+	// Make sure that the version is the first message sent
+	msg, ok := p.versionMessage()
+	if !ok {
+		return
+	}
+
+	streams := conn.Streams()
+	wg := sync.WaitGroup{}
+	wg.Add(len(streams) - 1)
+	for i, stream := range streams[ControlStream+1:] {
+		go func(stream net.Conn, queue MessageQueue) {
+			defer func() {
+				p.StartClose()
+				wg.Done()
+			}()
+			p.writeStream(stream, queue, nil)
+		}(stream, queue.Stream(ControlStream+1+i))
+	}
+
+	p.writeStream(streams[ControlStream], queue.Stream(ControlStream), msg)
+
+	// Closing the peer closes the queues, which stops the other writers.
+	p.StartClose()
+	wg.Wait()
+}
+
+// writeStream writes [first], if it isn't nil, and then the messages of
+// [queue] to [stream] until [queue] is closed or writing fails.
+func (p *peer) writeStream(stream net.Conn, queue MessageQueue, first message.OutboundMessage) {
+	writer := bufio.NewWriterSize(stream, p.Config.WriteBufferSize)
+	if first != nil {
+		p.writeMessage(stream, writer, first)
+	}
+
+	for {
+		msg, ok := queue.PopNow()
+		if ok {
+			p.writeMessage(stream, writer, msg)
+			continue
+		}
+
+		// Make sure the peer was fully sent all prior messages before
+		// blocking.
+		if err := writer.Flush(); err != nil {
+			p.Log.Verbo("failed to flush writer",
+				zap.Stringer("nodeID", p.id),
+				zap.Error(err),
+			)
+			return
+		}
+
+		msg, ok = queue.Pop()
+		if !ok {
+			// This peer is closing
+			return
+		}
+
+		p.writeMessage(stream, writer, msg)
+	}
+}
+
+func (p *peer) setReadDeadline(conn net.Conn, setDeadline bool) error {
+	if !setDeadline {
+		return nil
+	}
+	return conn.SetReadDeadline(p.nextTimeout())
+}
+
+func (p *peer) QUICPort() uint16 {
+	return p.quicPort
+}
+
+func (p *peer) transport() string {
+	if _, ok := p.conn.(MultiStreamConn); ok {
+		return TransportQUIC
+	}
+	return TransportTCP
+}
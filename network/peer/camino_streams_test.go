@@ -0,0 +1,223 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var _ MultiStreamConn = (*testMultiStreamConn)(nil)
+
+type testMultiStreamConn struct {
+	net.Conn
+	streams []net.Conn
+}
+
+func (c *testMultiStreamConn) Close() error {
+	for _, stream := range c.streams {
+		_ = stream.Close()
+	}
+	return nil
+}
+
+func (c *testMultiStreamConn) Streams() []net.Conn {
+	return c.streams
+}
+
+func newTestMultiStreamConns() (*testMultiStreamConn, *testMultiStreamConn) {
+	conn0 := &testMultiStreamConn{streams: make([]net.Conn, NumStreams)}
+	conn1 := &testMultiStreamConn{streams: make([]net.Conn, NumStreams)}
+	for i := 0; i < NumStreams; i++ {
+		conn0.streams[i], conn1.streams[i] = net.Pipe()
+	}
+	conn0.Conn = conn0.streams[ControlStream]
+	conn1.Conn = conn1.streams[ControlStream]
+	return conn0, conn1
+}
+
+// blockingInboundThrottler never allows a message to be read
+type blockingInboundThrottler struct{}
+
+func (blockingInboundThrottler) Acquire(ctx context.Context, _ uint64, _ ids.NodeID) throttling.ReleaseFunc {
+	<-ctx.Done()
+	return func() {}
+}
+
+func (blockingInboundThrottler) AddNode(ids.NodeID) {}
+
+func (blockingInboundThrottler) RemoveNode(ids.NodeID) {}
+
+func newTestStreamMessageQueue(config *Config, nodeID ids.NodeID) MessageQueue {
+	return NewStreamMessageQueue(func() MessageQueue {
+		return NewThrottledMessageQueue(
+			config.Metrics,
+			nodeID,
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		)
+	})
+}
+
+func TestStreamOf(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal(ControlStream, StreamOf(message.VersionOp))
+	require.Equal(ControlStream, StreamOf(message.PingOp))
+	require.Equal(ControlStream, StreamOf(message.GetAcceptedFrontierFailedOp))
+	require.Equal(ConsensusStream, StreamOf(message.PushQueryOp))
+	require.Equal(ConsensusStream, StreamOf(message.PullQueryOp))
+	require.Equal(ConsensusStream, StreamOf(message.ChitsOp))
+	require.Equal(ConsensusStream, StreamOf(message.PutOp))
+	require.Equal(BulkStream, StreamOf(message.GetAncestorsOp))
+	require.Equal(BulkStream, StreamOf(message.AncestorsOp))
+	require.Equal(BulkStream, StreamOf(message.AppGossipOp))
+}
+
+func TestSendMultiStream(t *testing.T) {
+	require := require.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	rawPeer0.config.QUICPort = 9652
+	conn0, conn1 := newTestMultiStreamConns()
+
+	peer0 := Start(
+		rawPeer0.config,
+		conn0,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+		newTestStreamMessageQueue(rawPeer0.config, rawPeer1.nodeID),
+	)
+	peer1 := Start(
+		rawPeer1.config,
+		conn1,
+		rawPeer0.cert,
+		rawPeer0.nodeID,
+		newTestStreamMessageQueue(rawPeer1.config, rawPeer0.nodeID),
+	)
+
+	require.NoError(peer0.AwaitReady(context.Background()))
+	require.NoError(peer1.AwaitReady(context.Background()))
+	require.Equal(uint16(9652), peer1.QUICPort())
+	require.Zero(peer0.QUICPort())
+	require.Equal(TransportQUIC, peer0.Info().Transport)
+
+	mc := newMessageCreator(t)
+	outboundAppGossipMsg, err := mc.AppGossip(ids.Empty, []byte{0})
+	require.NoError(err)
+	outboundGetMsg, err := mc.Get(ids.Empty, 1, time.Second, ids.Empty, p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	require.NoError(err)
+
+	require.True(peer0.Send(context.Background(), outboundAppGossipMsg))
+	require.True(peer0.Send(context.Background(), outboundGetMsg))
+
+	// The messages are sent on different streams, so they may be received in
+	// any order.
+	ops := []message.Op{
+		(<-rawPeer1.inboundMsgChan).Op(),
+		(<-rawPeer1.inboundMsgChan).Op(),
+	}
+	require.ElementsMatch([]message.Op{message.AppGossipOp, message.GetOp}, ops)
+
+	peer1.StartClose()
+	require.NoError(peer0.AwaitClosed(context.Background()))
+	require.NoError(peer1.AwaitClosed(context.Background()))
+}
+
+func TestStreamMessageQueue(t *testing.T) {
+	require := require.New(t)
+
+	mc := newMessageCreator(t)
+	queue := NewStreamMessageQueue(func() MessageQueue {
+		return NewThrottledMessageQueue(
+			SendFailedFunc(func(message.OutboundMessage) {}),
+			ids.GenerateTestNodeID(),
+			logging.NoLog{},
+			throttling.NewNoOutboundThrottler(),
+		)
+	})
+
+	pingMsg, err := mc.Ping()
+	require.NoError(err)
+	getMsg, err := mc.Get(ids.Empty, 1, time.Second, ids.Empty, p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	require.NoError(err)
+	appGossipMsg, err := mc.AppGossip(ids.Empty, []byte{0})
+	require.NoError(err)
+
+	require.True(queue.Push(context.Background(), appGossipMsg))
+	require.True(queue.Push(context.Background(), getMsg))
+	require.True(queue.Push(context.Background(), pingMsg))
+
+	// Pop only returns the messages of the control stream.
+	msg, ok := queue.PopNow()
+	require.True(ok)
+	require.Equal(message.PingOp, msg.Op())
+	_, ok = queue.PopNow()
+	require.False(ok)
+
+	msg, ok = queue.Stream(ConsensusStream).PopNow()
+	require.True(ok)
+	require.Equal(message.GetOp, msg.Op())
+	msg, ok = queue.Stream(BulkStream).PopNow()
+	require.True(ok)
+	require.Equal(message.AppGossipOp, msg.Op())
+
+	queue.Close()
+	_, ok = queue.Stream(BulkStream).Pop()
+	require.False(ok)
+}
+
+func TestBlockedStreamDoesntDelayOtherStreams(t *testing.T) {
+	require := require.New(t)
+
+	rawPeer0, rawPeer1 := makeRawTestPeers(t)
+	// The bulk stream of peer1 never reads a message, so the bulk stream of
+	// peer0 can't write more than one.
+	rawPeer1.config.StreamInboundMsgThrottlers[BulkStream] = blockingInboundThrottler{}
+	conn0, conn1 := newTestMultiStreamConns()
+
+	peer0 := Start(
+		rawPeer0.config,
+		conn0,
+		rawPeer1.cert,
+		rawPeer1.nodeID,
+		newTestStreamMessageQueue(rawPeer0.config, rawPeer1.nodeID),
+	)
+	peer1 := Start(
+		rawPeer1.config,
+		conn1,
+		rawPeer0.cert,
+		rawPeer0.nodeID,
+		newTestStreamMessageQueue(rawPeer1.config, rawPeer0.nodeID),
+	)
+
+	require.NoError(peer0.AwaitReady(context.Background()))
+	require.NoError(peer1.AwaitReady(context.Background()))
+
+	mc := newMessageCreator(t)
+	for i := 0; i < 128; i++ {
+		outboundAppGossipMsg, err := mc.AppGossip(ids.Empty, []byte{byte(i)})
+		require.NoError(err)
+		require.True(peer0.Send(context.Background(), outboundAppGossipMsg))
+	}
+	outboundGetMsg, err := mc.Get(ids.Empty, 1, time.Second, ids.Empty, p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	require.NoError(err)
+	require.True(peer0.Send(context.Background(), outboundGetMsg))
+
+	require.Equal(message.GetOp, (<-rawPeer1.inboundMsgChan).Op())
+
+	peer1.StartClose()
+	require.NoError(peer0.AwaitClosed(context.Background()))
+	require.NoError(peer1.AwaitClosed(context.Background()))
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+var (
+	errNodeNotAllowed = errors.New("node is not allowed to connect")
+
+	_ Upgrader = (*filteredUpgrader)(nil)
+)
+
+type filteredUpgrader struct {
+	upgrader Upgrader
+	allowed  func(ids.NodeID) bool
+}
+
+// NewFilteredUpgrader returns an upgrader that closes the connections of node
+// IDs [allowed] returns false for right after the TLS handshake of [upgrader].
+// [allowed] must be thread safe.
+func NewFilteredUpgrader(upgrader Upgrader, allowed func(ids.NodeID) bool) Upgrader {
+	return &filteredUpgrader{
+		upgrader: upgrader,
+		allowed:  allowed,
+	}
+}
+
+func (f *filteredUpgrader) Upgrade(conn net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
+	nodeID, tlsConn, cert, err := f.upgrader.Upgrade(conn)
+	if err != nil {
+		return ids.NodeID{}, nil, nil, err
+	}
+	if !f.allowed(nodeID) {
+		_ = tlsConn.Close()
+		return ids.NodeID{}, nil, nil, fmt.Errorf("%w: %s", errNodeNotAllowed, nodeID)
+	}
+	return nodeID, tlsConn, cert, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package peer
+
+import (
+	"crypto/x509"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+type testUpgrader struct {
+	nodeID ids.NodeID
+}
+
+func (u testUpgrader) Upgrade(conn net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
+	return u.nodeID, conn, &x509.Certificate{}, nil
+}
+
+func TestFilteredUpgrader(t *testing.T) {
+	require := require.New(t)
+
+	allowedNodeID := ids.GenerateTestNodeID()
+	upgrader := NewFilteredUpgrader(testUpgrader{nodeID: allowedNodeID}, func(nodeID ids.NodeID) bool {
+		return nodeID == allowedNodeID
+	})
+	conn, _ := net.Pipe()
+	nodeID, upgradedConn, _, err := upgrader.Upgrade(conn)
+	require.NoError(err)
+	require.Equal(allowedNodeID, nodeID)
+	require.Equal(conn, upgradedConn)
+
+	upgrader = NewFilteredUpgrader(testUpgrader{nodeID: ids.GenerateTestNodeID()}, func(nodeID ids.NodeID) bool {
+		return nodeID == allowedNodeID
+	})
+	conn, remoteConn := net.Pipe()
+	_, _, _, err = upgrader.Upgrade(conn)
+	require.ErrorIs(err, errNodeNotAllowed)
+
+	// The rejected connection is closed
+	_, err = remoteConn.Read(make([]byte, 1))
+	require.Error(err)
+}
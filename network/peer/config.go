@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -9,6 +19,7 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
@@ -52,4 +63,20 @@ type Config struct {
 
 	// Signs my IP so I can send my signed IP address in the Version message
 	IPSigner *IPSigner
+
+	// UDP port this node accepts QUIC connections on, 0 if it doesn't
+	QUICPort uint16
+
+	// StreamInboundMsgThrottlers throttle the messages read from the streams
+	// of a [MultiStreamConn] other than the [ControlStream], which is
+	// throttled by [InboundMsgThrottler]. Each stream has its own throttler,
+	// so that waiting to read from one stream doesn't delay the others. The
+	// messages of streams without a throttler aren't throttled.
+	StreamInboundMsgThrottlers [NumStreams]throttling.InboundMsgThrottler
+
+	// Tracks the behaviour of peers
+	Reputation reputation.Tracker
+
+	// Captures the messages exchanged with peers
+	Recorder Recorder
 }
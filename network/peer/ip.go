@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -19,6 +29,9 @@ import (
 type UnsignedIP struct {
 	ips.IPPort
 	Timestamp uint64
+	// ExtraIPPorts are the other endpoints the node is reachable at, in order
+	// of preference
+	ExtraIPPorts []ips.IPPort
 }
 
 // Sign this IP with the provided signer and return the signed IP.
@@ -28,9 +41,14 @@ func (ip *UnsignedIP) Sign(signer crypto.Signer) (*SignedIP, error) {
 		hashing.ComputeHash256(ip.bytes()),
 		crypto.SHA256,
 	)
+	if err != nil {
+		return nil, err
+	}
+	extraSig, err := ip.signExtraIPPorts(signer)
 	return &SignedIP{
-		UnsignedIP: *ip,
-		Signature:  sig,
+		UnsignedIP:     *ip,
+		Signature:      sig,
+		ExtraSignature: extraSig,
 	}, err
 }
 
@@ -47,12 +65,18 @@ func (ip *UnsignedIP) bytes() []byte {
 type SignedIP struct {
 	UnsignedIP
 	Signature []byte
+	// ExtraSignature signs the IP, the timestamp and the extra IPs. Empty if
+	// there are no extra IPs.
+	ExtraSignature []byte
 }
 
 func (ip *SignedIP) Verify(cert *x509.Certificate) error {
-	return cert.CheckSignature(
+	if err := cert.CheckSignature(
 		cert.SignatureAlgorithm,
 		ip.UnsignedIP.bytes(),
 		ip.Signature,
-	)
+	); err != nil {
+		return err
+	}
+	return ip.verifyExtraIPPorts(cert)
 }
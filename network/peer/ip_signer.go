@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -13,9 +23,10 @@ import (
 
 // IPSigner will return a signedIP for the current value of our dynamic IP.
 type IPSigner struct {
-	ip     ips.DynamicIPPort
-	clock  mockable.Clock
-	signer crypto.Signer
+	ip       ips.DynamicIPPort
+	extraIPs ips.DynamicIPPorts
+	clock    mockable.Clock
+	signer   crypto.Signer
 
 	// Must be held while accessing [signedIP]
 	signedIPLock sync.RWMutex
@@ -26,11 +37,13 @@ type IPSigner struct {
 
 func NewIPSigner(
 	ip ips.DynamicIPPort,
+	extraIPs ips.DynamicIPPorts,
 	signer crypto.Signer,
 ) *IPSigner {
 	return &IPSigner{
-		ip:     ip,
-		signer: signer,
+		ip:       ip,
+		extraIPs: extraIPs,
+		signer:   signer,
 	}
 }
 
@@ -46,7 +59,8 @@ func (s *IPSigner) GetSignedIP() (*SignedIP, error) {
 	signedIP := s.signedIP
 	s.signedIPLock.RUnlock()
 	ip := s.ip.IPPort()
-	if signedIP != nil && signedIP.IPPort.Equal(ip) {
+	extraIPs := s.extraIPs.IPPorts()
+	if signedIP != nil && signedIP.IPPort.Equal(ip) && ips.EqualIPPorts(signedIP.ExtraIPPorts, extraIPs) {
 		return signedIP, nil
 	}
 
@@ -58,14 +72,15 @@ func (s *IPSigner) GetSignedIP() (*SignedIP, error) {
 	// same time, we should verify that we are the first thread to attempt to
 	// update it.
 	signedIP = s.signedIP
-	if signedIP != nil && signedIP.IPPort.Equal(ip) {
+	if signedIP != nil && signedIP.IPPort.Equal(ip) && ips.EqualIPPorts(signedIP.ExtraIPPorts, extraIPs) {
 		return signedIP, nil
 	}
 
 	// We should now sign our new IP at the current timestamp.
 	unsignedIP := UnsignedIP{
-		IPPort:    ip,
-		Timestamp: s.clock.Unix(),
+		IPPort:       ip,
+		Timestamp:    s.clock.Unix(),
+		ExtraIPPorts: extraIPs,
 	}
 	signedIP, err := unsignedIP.Sign(s.signer)
 	if err != nil {
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -28,7 +38,7 @@ func TestIPSigner(t *testing.T) {
 
 	key := tlsCert.PrivateKey.(crypto.Signer)
 
-	s := NewIPSigner(dynIP, key)
+	s := NewIPSigner(dynIP, ips.NewDynamicIPPorts(), key)
 
 	s.clock.Set(time.Unix(10, 0))
 
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -19,7 +29,9 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/constants"
 	"github.com/ava-labs/avalanchego/utils/ips"
@@ -76,6 +88,10 @@ type Peer interface {
 	// be called after [Ready] returns true.
 	TrackedSubnets() set.Set[ids.ID]
 
+	// QUICPort returns the UDP port this peer accepts QUIC connections on, or 0
+	// if it doesn't. It should only be called after [Ready] returns true.
+	QUICPort() uint16
+
 	// ObservedUptime returns the local node's subnet uptime according to the
 	// peer. The value ranges from [0, 100]. It should only be called after
 	// [Ready] returns true.
@@ -128,6 +144,8 @@ type peer struct {
 	// trackedSubnets is the subset of subnetIDs the peer sent us in the Version
 	// message that we are also tracking.
 	trackedSubnets set.Set[ids.ID]
+	// quicPort is the UDP port the peer sent us in the Version message.
+	quicPort uint16
 
 	observedUptimesLock sync.RWMutex
 	// [observedUptimesLock] must be held while accessing [observedUptime]
@@ -196,8 +214,13 @@ func Start(
 		peerListChan:       make(chan struct{}, 1),
 	}
 
-	go p.readMessages()
-	go p.writeMessages()
+	if streams, ok := conn.(MultiStreamConn); ok {
+		go p.readStreams(streams)
+		go p.writeStreams(streams)
+	} else {
+		go p.readMessages()
+		go p.writeMessages()
+	}
 	go p.sendNetworkMessages()
 
 	return p
@@ -272,6 +295,8 @@ func (p *peer) Info() Info {
 		ObservedUptime:        json.Uint32(primaryUptime),
 		ObservedSubnetUptimes: uptimes,
 		TrackedSubnets:        trackedSubnets,
+		Transport:             p.transport(),
+		Reputation:            p.Reputation.Score(p.id),
 	}
 }
 
@@ -360,12 +385,20 @@ func (p *peer) readMessages() {
 		p.close()
 	}()
 
+	p.readConnMessages(p.conn, p.InboundMsgThrottler, true /*=setDeadlines*/)
+}
+
+// readConnMessages reads and handles messages from [conn] until reading fails
+// or the peer starts closing. The messages are throttled by [throttler]. If
+// [setDeadlines] is true, the connection is closed if a message isn't received
+// within the pong timeout.
+func (p *peer) readConnMessages(conn net.Conn, throttler throttling.InboundMsgThrottler, setDeadlines bool) {
 	// Continuously read and handle messages from this peer.
-	reader := bufio.NewReaderSize(p.conn, p.Config.ReadBufferSize)
+	reader := bufio.NewReaderSize(conn, p.Config.ReadBufferSize)
 	msgLenBytes := make([]byte, wrappers.IntLen)
 	for {
 		// Time out and close connection if we can't read the message length
-		if err := p.conn.SetReadDeadline(p.nextTimeout()); err != nil {
+		if err := p.setReadDeadline(conn, setDeadlines); err != nil {
 			p.Log.Verbo("error setting the connection read timeout",
 				zap.Stringer("nodeID", p.id),
 				zap.Error(err),
@@ -389,6 +422,7 @@ func (p *peer) readMessages() {
 				zap.Stringer("nodeID", p.id),
 				zap.Error(err),
 			)
+			p.Reputation.Report(p.id, reputation.InvalidMessage)
 			return
 		}
 
@@ -400,12 +434,13 @@ func (p *peer) readMessages() {
 		// throttler metrics to verify that there is no leak.
 		//
 		// Invariant: There must only be one call to Acquire at any given time
-		// with the same nodeID. In this package, only this goroutine ever
-		// performs Acquire. Additionally, we ensure that this goroutine has
-		// exited before calling [Network.Disconnected] to guarantee that there
-		// can't be multiple instances of this goroutine running over different
-		// peer instances.
-		onFinishedHandling := p.InboundMsgThrottler.Acquire(
+		// with the same nodeID. In this package, only the goroutine reading
+		// [conn] ever performs Acquire on [throttler], as every stream of a
+		// connection has its own throttler. Additionally, we ensure that these
+		// goroutines have exited before calling [Network.Disconnected] to
+		// guarantee that there can't be multiple instances of these goroutines
+		// running over different peer instances.
+		onFinishedHandling := throttler.Acquire(
 			p.onClosingCtx,
 			uint64(msgLen),
 			p.id,
@@ -418,7 +453,7 @@ func (p *peer) readMessages() {
 		}
 
 		// Time out and close connection if we can't read message
-		if err := p.conn.SetReadDeadline(p.nextTimeout()); err != nil {
+		if err := p.setReadDeadline(conn, setDeadlines); err != nil {
 			p.Log.Verbo("error setting the connection read timeout",
 				zap.Stringer("nodeID", p.id),
 				zap.Error(err),
@@ -461,6 +496,7 @@ func (p *peer) readMessages() {
 			)
 
 			p.Metrics.FailedToParse.Inc()
+			p.Reputation.Report(p.id, reputation.InvalidMessage)
 
 			// Couldn't parse the message. Read the next one.
 			onFinishedHandling()
@@ -471,6 +507,7 @@ func (p *peer) readMessages() {
 		now := p.Clock.Time()
 		p.storeLastReceived(now)
 		p.Metrics.Received(msg, msgLen)
+		p.Recorder.RecordInbound(p.id, msg, msgBytes)
 
 		// Handle the message. Note that when we are done handling this message,
 		// we must call [msg.OnFinishedHandling()].
@@ -488,37 +525,17 @@ func (p *peer) writeMessages() {
 	writer := bufio.NewWriterSize(p.conn, p.Config.WriteBufferSize)
 
 	// Make sure that the version is the first message sent
-	mySignedIP, err := p.IPSigner.GetSignedIP()
-	if err != nil {
-		p.Log.Error("failed to get signed IP",
-			zap.Error(err),
-		)
+	msg, ok := p.versionMessage()
+	if !ok {
 		return
 	}
 
-	msg, err := p.MessageCreator.Version(
-		p.NetworkID,
-		p.Clock.Unix(),
-		mySignedIP.IPPort,
-		p.VersionCompatibility.Version().String(),
-		mySignedIP.Timestamp,
-		mySignedIP.Signature,
-		p.MySubnets.List(),
-	)
-	if err != nil {
-		p.Log.Error("failed to create message",
-			zap.Stringer("messageOp", message.VersionOp),
-			zap.Error(err),
-		)
-		return
-	}
-
-	p.writeMessage(writer, msg)
+	p.writeMessage(p.conn, writer, msg)
 
 	for {
 		msg, ok := p.messageQueue.PopNow()
 		if ok {
-			p.writeMessage(writer, msg)
+			p.writeMessage(p.conn, writer, msg)
 			continue
 		}
 
@@ -538,18 +555,51 @@ func (p *peer) writeMessages() {
 			return
 		}
 
-		p.writeMessage(writer, msg)
+		p.writeMessage(p.conn, writer, msg)
+	}
+}
+
+// versionMessage returns the Version message this peer sends first. It returns
+// false if the message couldn't be created.
+func (p *peer) versionMessage() (message.OutboundMessage, bool) {
+	mySignedIP, err := p.IPSigner.GetSignedIP()
+	if err != nil {
+		p.Log.Error("failed to get signed IP",
+			zap.Error(err),
+		)
+		return nil, false
+	}
+
+	msg, err := p.MessageCreator.Version(
+		p.NetworkID,
+		p.Clock.Unix(),
+		mySignedIP.IPPort,
+		p.VersionCompatibility.Version().String(),
+		mySignedIP.Timestamp,
+		mySignedIP.Signature,
+		p.MySubnets.List(),
+		p.Config.QUICPort,
+		mySignedIP.ExtraIPPorts,
+		mySignedIP.ExtraSignature,
+	)
+	if err != nil {
+		p.Log.Error("failed to create message",
+			zap.Stringer("messageOp", message.VersionOp),
+			zap.Error(err),
+		)
+		return nil, false
 	}
+	return msg, true
 }
 
-func (p *peer) writeMessage(writer io.Writer, msg message.OutboundMessage) {
+func (p *peer) writeMessage(conn net.Conn, writer io.Writer, msg message.OutboundMessage) {
 	msgBytes := msg.Bytes()
 	p.Log.Verbo("sending message",
 		zap.Stringer("nodeID", p.id),
 		zap.Binary("messageBytes", msgBytes),
 	)
 
-	if err := p.conn.SetWriteDeadline(p.nextTimeout()); err != nil {
+	if err := conn.SetWriteDeadline(p.nextTimeout()); err != nil {
 		p.Log.Verbo("error setting write deadline",
 			zap.Stringer("nodeID", p.id),
 			zap.Error(err),
@@ -580,6 +630,7 @@ func (p *peer) writeMessage(writer io.Writer, msg message.OutboundMessage) {
 	now := p.Clock.Time()
 	p.storeLastSent(now)
 	p.Metrics.Sent(msg)
+	p.Recorder.RecordOutbound(p.id, msg)
 }
 
 func (p *peer) sendNetworkMessages() {
@@ -905,15 +956,29 @@ func (p *peer) handleVersion(msg *p2p.Version) {
 		return
 	}
 
+	extraIPPorts, err := parseExtraIPPorts(msg.ExtraIpPorts)
+	if err != nil {
+		p.Log.Debug("message with invalid field",
+			zap.Stringer("nodeID", p.id),
+			zap.Stringer("messageOp", message.VersionOp),
+			zap.String("field", "ExtraIPPorts"),
+			zap.Error(err),
+		)
+		p.StartClose()
+		return
+	}
+
 	p.ip = &SignedIP{
 		UnsignedIP: UnsignedIP{
 			IPPort: ips.IPPort{
 				IP:   msg.IpAddr,
 				Port: uint16(msg.IpPort),
 			},
-			Timestamp: msg.MyVersionTime,
+			Timestamp:    msg.MyVersionTime,
+			ExtraIPPorts: extraIPPorts,
 		},
-		Signature: msg.Sig,
+		Signature:      msg.Sig,
+		ExtraSignature: msg.ExtraSig,
 	}
 	if err := p.ip.Verify(p.cert); err != nil {
 		p.Log.Debug("signature verification failed",
@@ -924,6 +989,9 @@ func (p *peer) handleVersion(msg *p2p.Version) {
 		return
 	}
 
+	if msg.QuicPort <= math.MaxUint16 {
+		p.quicPort = uint16(msg.QuicPort)
+	}
 	p.gotVersion.Set(true)
 
 	peerIPs, err := p.Network.Peers(p.id)
@@ -1008,15 +1076,29 @@ func (p *peer) handlePeerList(msg *p2p.PeerList) {
 			}
 		}
 
+		extraIPPorts, err := parseExtraIPPorts(claimedIPPort.ExtraIpPorts)
+		if err != nil {
+			p.Log.Debug("message with invalid field",
+				zap.Stringer("nodeID", p.id),
+				zap.Stringer("messageOp", message.PeerListOp),
+				zap.String("field", "ExtraIPPorts"),
+				zap.Error(err),
+			)
+			p.StartClose()
+			return
+		}
+
 		discoveredIPs[i] = &ips.ClaimedIPPort{
 			Cert: tlsCert,
 			IPPort: ips.IPPort{
 				IP:   claimedIPPort.IpAddr,
 				Port: uint16(claimedIPPort.IpPort),
 			},
-			Timestamp: claimedIPPort.Timestamp,
-			Signature: claimedIPPort.Signature,
-			TxID:      txID,
+			Timestamp:      claimedIPPort.Timestamp,
+			Signature:      claimedIPPort.Signature,
+			TxID:           txID,
+			ExtraIPPorts:   extraIPPorts,
+			ExtraSignature: claimedIPPort.ExtraSignature,
 		}
 	}
 
@@ -29,6 +29,7 @@ import (
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network/throttling"
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -117,13 +118,15 @@ func makeRawTestPeers(t *testing.T) (*rawTestPeer, *rawTestPeer) {
 		PongTimeout:          constants.DefaultPingPongTimeout,
 		MaxClockDifference:   time.Minute,
 		ResourceTracker:      resourceTracker,
+		Reputation:           reputation.NewNoTracker(),
+		Recorder:             NewNoRecorder(),
 	}
 	peerConfig0 := sharedConfig
 	peerConfig1 := sharedConfig
 
 	ip0 := ips.NewDynamicIPPort(net.IPv6loopback, 0)
 	tls0 := tlsCert0.PrivateKey.(crypto.Signer)
-	peerConfig0.IPSigner = NewIPSigner(ip0, tls0)
+	peerConfig0.IPSigner = NewIPSigner(ip0, ips.NewDynamicIPPorts(), tls0)
 
 	peerConfig0.Network = TestNetwork
 	inboundMsgChan0 := make(chan message.InboundMessage)
@@ -133,7 +136,7 @@ func makeRawTestPeers(t *testing.T) (*rawTestPeer, *rawTestPeer) {
 
 	ip1 := ips.NewDynamicIPPort(net.IPv6loopback, 1)
 	tls1 := tlsCert1.PrivateKey.(crypto.Signer)
-	peerConfig1.IPSigner = NewIPSigner(ip1, tls1)
+	peerConfig1.IPSigner = NewIPSigner(ip1, ips.NewDynamicIPPorts(), tls1)
 
 	peerConfig1.Network = TestNetwork
 	inboundMsgChan1 := make(chan message.InboundMessage)
@@ -0,0 +1,75 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"net"
+	"time"
+
+	"github.com/ava-labs/avalanchego/network/peer"
+
+	quicgo "github.com/quic-go/quic-go"
+)
+
+var (
+	_ peer.MultiStreamConn = (*conn)(nil)
+	_ net.Conn             = (*stream)(nil)
+)
+
+// conn is a QUIC connection with a stream for each stream class
+type conn struct {
+	conn    quicgo.Connection
+	streams []net.Conn
+}
+
+func (c *conn) Read(b []byte) (int, error) {
+	return c.streams[peer.ControlStream].Read(b)
+}
+
+func (c *conn) Write(b []byte) (int, error) {
+	return c.streams[peer.ControlStream].Write(b)
+}
+
+// Close closes the QUIC connection, which aborts all of its streams
+func (c *conn) Close() error {
+	return c.conn.CloseWithError(0, "")
+}
+
+func (c *conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+func (c *conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+func (c *conn) SetDeadline(t time.Time) error {
+	return c.streams[peer.ControlStream].SetDeadline(t)
+}
+
+func (c *conn) SetReadDeadline(t time.Time) error {
+	return c.streams[peer.ControlStream].SetReadDeadline(t)
+}
+
+func (c *conn) SetWriteDeadline(t time.Time) error {
+	return c.streams[peer.ControlStream].SetWriteDeadline(t)
+}
+
+func (c *conn) Streams() []net.Conn {
+	return c.streams
+}
+
+// stream is a QUIC stream that reports the addresses of its connection
+type stream struct {
+	quicgo.Stream
+	conn quicgo.Connection
+}
+
+func (s *stream) LocalAddr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+func (s *stream) RemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
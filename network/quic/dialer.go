@@ -0,0 +1,99 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	quicgo "github.com/quic-go/quic-go"
+)
+
+var _ dialer.Dialer = (*quicDialer)(nil)
+
+type quicDialer struct {
+	tlsConfig  *tls.Config
+	quicConfig *quicgo.Config
+	timeout    time.Duration
+	log        logging.Logger
+	throttler  throttling.DialThrottler
+}
+
+// NewDialer returns a new Dialer that establishes QUIC connections, using
+// [tlsConfig] for the handshake. The returned connections are already
+// authenticated and must be upgraded by an [Upgrader].
+// [dialerConfig.connectionTimeout] gives the timeout to establish a connection
+// and its streams.
+// [dialerConfig.throttleRps] gives the max number of outgoing connection attempts/second.
+// If [dialerConfig.throttleRps] == 0, outgoing connections aren't rate-limited.
+func NewDialer(tlsConfig *tls.Config, dialerConfig dialer.Config, log logging.Logger) dialer.Dialer {
+	var throttler throttling.DialThrottler
+	if dialerConfig.ThrottleRps <= 0 {
+		throttler = throttling.NewNoDialThrottler()
+	} else {
+		throttler = throttling.NewDialThrottler(int(dialerConfig.ThrottleRps))
+	}
+	return &quicDialer{
+		tlsConfig:  newTLSConfig(tlsConfig),
+		quicConfig: newQUICConfig(dialerConfig.ConnectionTimeout),
+		timeout:    dialerConfig.ConnectionTimeout,
+		log:        log,
+		throttler:  throttler,
+	}
+}
+
+func (d *quicDialer) Dial(ctx context.Context, ip ips.IPPort) (net.Conn, error) {
+	if err := d.throttler.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	d.log.Verbo("dialing",
+		zap.String("transport", peer.TransportQUIC),
+		zap.Stringer("ip", ip),
+	)
+
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	qConn, err := quicgo.DialAddr(ctx, ip.String(), d.tlsConfig, d.quicConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error while dialing %s: %w", ip, err)
+	}
+
+	// Streams are only announced to the peer once data is sent on them, so
+	// every stream starts with its stream class.
+	streams := make([]net.Conn, peer.NumStreams)
+	for i := range streams {
+		qStream, err := qConn.OpenStreamSync(ctx)
+		if err != nil {
+			_ = qConn.CloseWithError(0, "")
+			return nil, fmt.Errorf("error while opening stream to %s: %w", ip, err)
+		}
+		if _, err := qStream.Write([]byte{byte(i)}); err != nil {
+			_ = qConn.CloseWithError(0, "")
+			return nil, fmt.Errorf("error while opening stream to %s: %w", ip, err)
+		}
+		streams[i] = &stream{
+			Stream: qStream,
+			conn:   qConn,
+		}
+	}
+	return &conn{
+		conn:    qConn,
+		streams: streams,
+	}, nil
+}
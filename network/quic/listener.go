@@ -0,0 +1,163 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/utils/logging"
+
+	quicgo "github.com/quic-go/quic-go"
+)
+
+var (
+	errClosed               = errors.New("listener closed")
+	errUnknownStreamClass   = errors.New("unknown stream class")
+	errDuplicateStreamClass = errors.New("duplicate stream class")
+
+	_ net.Listener = (*listener)(nil)
+)
+
+type listener struct {
+	listener *quicgo.Listener
+	timeout  time.Duration
+	log      logging.Logger
+
+	conns     chan net.Conn
+	closeCtx  context.Context
+	closeFunc context.CancelFunc
+}
+
+// Listen returns a listener that accepts QUIC connections on the UDP address
+// [addr], using [tlsConfig] for the handshake. A connection is returned by
+// Accept once all of its streams were opened. Connections that don't open
+// their streams within [timeout] are closed. The returned connections are
+// already authenticated and must be upgraded by an [Upgrader].
+func Listen(addr string, tlsConfig *tls.Config, timeout time.Duration, log logging.Logger) (net.Listener, error) {
+	qListener, err := quicgo.ListenAddr(addr, newTLSConfig(tlsConfig), newQUICConfig(timeout))
+	if err != nil {
+		return nil, err
+	}
+
+	closeCtx, closeFunc := context.WithCancel(context.Background())
+	l := &listener{
+		listener:  qListener,
+		timeout:   timeout,
+		log:       log,
+		conns:     make(chan net.Conn),
+		closeCtx:  closeCtx,
+		closeFunc: closeFunc,
+	}
+	go l.acceptConns()
+	return l, nil
+}
+
+func (l *listener) acceptConns() {
+	for {
+		qConn, err := l.listener.Accept(l.closeCtx)
+		if err != nil {
+			// Accept only fails once the listener is closed
+			l.log.Debug("stopped accepting QUIC connections",
+				zap.Error(err),
+			)
+			return
+		}
+		go l.acceptStreams(qConn)
+	}
+}
+
+func (l *listener) acceptStreams(qConn quicgo.Connection) {
+	ctx := l.closeCtx
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	streams, err := acceptStreams(ctx, qConn)
+	if err != nil {
+		_ = qConn.CloseWithError(0, "")
+		l.log.Verbo("failed to accept QUIC streams",
+			zap.Stringer("peerIP", qConn.RemoteAddr()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	c := &conn{
+		conn:    qConn,
+		streams: streams,
+	}
+	select {
+	case l.conns <- c:
+	case <-l.closeCtx.Done():
+		_ = c.Close()
+	}
+}
+
+// acceptStreams accepts a stream of every stream class from [qConn]
+func acceptStreams(ctx context.Context, qConn quicgo.Connection) ([]net.Conn, error) {
+	streams := make([]net.Conn, peer.NumStreams)
+	class := make([]byte, 1)
+	for range streams {
+		qStream, err := qConn.AcceptStream(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// Reads of the stream class are bounded by the accept timeout
+		deadline, ok := ctx.Deadline()
+		if ok {
+			if err := qStream.SetReadDeadline(deadline); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := io.ReadFull(qStream, class); err != nil {
+			return nil, err
+		}
+		if err := qStream.SetReadDeadline(time.Time{}); err != nil {
+			return nil, err
+		}
+
+		i := int(class[0])
+		if i >= len(streams) {
+			return nil, fmt.Errorf("%w: %d", errUnknownStreamClass, i)
+		}
+		if streams[i] != nil {
+			return nil, fmt.Errorf("%w: %d", errDuplicateStreamClass, i)
+		}
+		streams[i] = &stream{
+			Stream: qStream,
+			conn:   qConn,
+		}
+	}
+	return streams, nil
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closeCtx.Done():
+		return nil, errClosed
+	}
+}
+
+func (l *listener) Close() error {
+	l.closeFunc()
+	return l.listener.Close()
+}
+
+func (l *listener) Addr() net.Addr {
+	return l.listener.Addr()
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package quic implements a QUIC transport for peer-to-peer connections. Each
+// connection opens a stream for every stream class of [peer.MultiStreamConn],
+// so that large messages don't delay small consensus messages. Peers are
+// authenticated by the staking certificate used for the QUIC TLS handshake.
+package quic
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/ava-labs/avalanchego/network/peer"
+
+	quicgo "github.com/quic-go/quic-go"
+)
+
+const (
+	// nextProto is the application protocol negotiated for p2p connections
+	nextProto = "camino-p2p"
+
+	// keepAlivePeriod is the period of keep alive packets, so that idle
+	// connections aren't closed between pings.
+	keepAlivePeriod = 10 * time.Second
+)
+
+func newTLSConfig(tlsConfig *tls.Config) *tls.Config {
+	tlsConfig = tlsConfig.Clone()
+	tlsConfig.NextProtos = []string{nextProto}
+	return tlsConfig
+}
+
+func newQUICConfig(handshakeTimeout time.Duration) *quicgo.Config {
+	return &quicgo.Config{
+		HandshakeIdleTimeout:  handshakeTimeout,
+		KeepAlivePeriod:       keepAlivePeriod,
+		MaxIncomingStreams:    peer.NumStreams,
+		MaxIncomingUniStreams: -1,
+	}
+}
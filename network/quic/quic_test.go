@@ -0,0 +1,98 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/dialer"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/staking"
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func newTLSConfigAndID(t *testing.T) (*tls.Config, ids.NodeID) {
+	require := require.New(t)
+
+	cert, err := staking.NewTLSCert()
+	require.NoError(err)
+	nodeID, err := peer.CertToID(cert.Leaf)
+	require.NoError(err)
+	return peer.TLSConfig(*cert, nil), nodeID
+}
+
+func TestDialAndListen(t *testing.T) {
+	require := require.New(t)
+
+	serverTLSConfig, serverID := newTLSConfigAndID(t)
+	clientTLSConfig, clientID := newTLSConfigAndID(t)
+
+	listener, err := Listen("127.0.0.1:0", serverTLSConfig, 10*time.Second, logging.NoLog{})
+	require.NoError(err)
+	defer listener.Close()
+
+	ip, err := ips.ToIPPort(listener.Addr().String())
+	require.NoError(err)
+
+	d := NewDialer(
+		clientTLSConfig,
+		dialer.Config{ConnectionTimeout: 10 * time.Second},
+		logging.NoLog{},
+	)
+	clientConn, err := d.Dial(context.Background(), ip)
+	require.NoError(err)
+	defer clientConn.Close()
+
+	serverConn, err := listener.Accept()
+	require.NoError(err)
+	defer serverConn.Close()
+
+	upgrader := NewUpgrader()
+	nodeID, clientConn, _, err := upgrader.Upgrade(clientConn)
+	require.NoError(err)
+	require.Equal(serverID, nodeID)
+	nodeID, serverConn, _, err = upgrader.Upgrade(serverConn)
+	require.NoError(err)
+	require.Equal(clientID, nodeID)
+
+	clientStreams := clientConn.(peer.MultiStreamConn).Streams()
+	serverStreams := serverConn.(peer.MultiStreamConn).Streams()
+	require.Len(clientStreams, peer.NumStreams)
+	require.Len(serverStreams, peer.NumStreams)
+
+	// Streams are matched by their stream class
+	for i := range clientStreams {
+		msg := []byte{byte(i), 'm', 's', 'g'}
+		_, err := clientStreams[i].Write(msg)
+		require.NoError(err)
+
+		read := make([]byte, len(msg))
+		_, err = io.ReadFull(serverStreams[i], read)
+		require.NoError(err)
+		require.Equal(msg, read)
+	}
+
+	_, ok := serverConn.RemoteAddr().(*net.UDPAddr)
+	require.True(ok)
+
+	// Closing the connection aborts the streams of the peer
+	require.NoError(clientConn.Close())
+	_, err = serverStreams[peer.BulkStream].Read(make([]byte, 1))
+	require.Error(err)
+}
+
+func TestUpgradeRejectsOtherConns(t *testing.T) {
+	conn, _ := net.Pipe()
+	_, _, _, err := NewUpgrader().Upgrade(conn)
+	require.ErrorIs(t, err, errNotQUICConn)
+}
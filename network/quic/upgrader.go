@@ -0,0 +1,45 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package quic
+
+import (
+	"crypto/x509"
+	"errors"
+	"net"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/network/peer"
+)
+
+var (
+	errNotQUICConn = errors.New("connection wasn't established by the QUIC transport")
+	errNoCert      = errors.New("tls handshake finished with no peer certificate")
+
+	_ peer.Upgrader = upgrader{}
+)
+
+type upgrader struct{}
+
+// NewUpgrader returns an upgrader for inbound and outbound connections of the
+// QUIC transport. As the TLS handshake is part of establishing a QUIC
+// connection, the upgrader only identifies the peer by its certificate.
+func NewUpgrader() peer.Upgrader {
+	return upgrader{}
+}
+
+func (upgrader) Upgrade(c net.Conn) (ids.NodeID, net.Conn, *x509.Certificate, error) {
+	qConn, ok := c.(*conn)
+	if !ok {
+		return ids.NodeID{}, nil, nil, errNotQUICConn
+	}
+
+	state := qConn.conn.ConnectionState().TLS
+	if len(state.PeerCertificates) == 0 {
+		return ids.NodeID{}, nil, nil, errNoCert
+	}
+	peerCert := state.PeerCertificates[0]
+
+	nodeID, err := peer.CertToID(peerCert)
+	return nodeID, qConn, peerCert, err
+}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -18,6 +28,7 @@ import (
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/uptime"
@@ -173,6 +184,9 @@ func NewTestNetwork(
 		RequireValidatorToConnect: constants.DefaultNetworkRequireValidatorToConnect,
 		PeerReadBufferSize:        constants.DefaultNetworkPeerReadBufferSize,
 		PeerWriteBufferSize:       constants.DefaultNetworkPeerWriteBufferSize,
+
+		Reputation: reputation.NewNoTracker(),
+		Recorder:   peer.NewNoRecorder(),
 	}
 
 	networkConfig.NetworkID = networkID
@@ -226,6 +240,7 @@ func NewTestNetwork(
 	)
 
 	networkConfig.MyIPPort = ips.NewDynamicIPPort(net.IPv4zero, 0)
+	networkConfig.MyExtraIPPorts = ips.NewDynamicIPPorts()
 
 	networkConfig.GossipTracker, err = peer.NewGossipTracker(metrics, "")
 	if err != nil {
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -12,6 +22,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/linkedhashmap"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -28,6 +39,7 @@ func newInboundMsgByteThrottler(
 	registerer prometheus.Registerer,
 	vdrs validators.Set,
 	config MsgByteThrottlerConfig,
+	reporter reputation.Reporter,
 ) (*inboundMsgByteThrottler, error) {
 	t := &inboundMsgByteThrottler{
 		commonMsgThrottler: commonMsgThrottler{
@@ -42,6 +54,7 @@ func newInboundMsgByteThrottler(
 		},
 		waitingToAcquire:   linkedhashmap.New[uint64, *msgMetadata](),
 		nodeToWaitingMsgID: make(map[ids.NodeID]uint64),
+		reporter:           reporter,
 	}
 	return t, t.metrics.initialize(namespace, registerer)
 }
@@ -77,6 +90,10 @@ type inboundMsgByteThrottler struct {
 	//
 	// Invariant: len(nodeToWaitingMsgIDs) >= 1
 	// implies waitingToAcquire.Len() >= 1, and vice versa.
+
+	// Notified when a node has to wait because it used up its own
+	// allocations
+	reporter reputation.Reporter
 }
 
 // Returns when we can read a message of size [msgSize] from node [nodeID].
@@ -174,8 +191,19 @@ func (t *inboundMsgByteThrottler) Acquire(ctx context.Context, msgSize uint64, n
 	)
 
 	t.nodeToWaitingMsgID[nodeID] = msgID
+
+	// Only report [nodeID] if it has to wait because it used up both its
+	// validator allocation and the bytes it may take from the at-large
+	// allocation by itself, rather than because other nodes used up the
+	// shared allocations.
+	exhausted := t.nodeToAtLargeBytesUsed[nodeID] >= t.nodeMaxAtLargeBytes &&
+		t.nodeToVdrBytesUsed[nodeID] >= vdrAllocationSize
 	t.lock.Unlock()
 
+	if exhausted {
+		t.reporter.Report(nodeID, reputation.Throttled)
+	}
+
 	t.metrics.awaitingAcquire.Inc()
 	defer t.metrics.awaitingAcquire.Dec()
 
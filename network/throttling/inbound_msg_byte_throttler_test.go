@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -13,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
 )
@@ -34,6 +45,7 @@ func TestInboundMsgByteThrottlerCancelContextDeadlock(t *testing.T) {
 		prometheus.NewRegistry(),
 		vdrs,
 		config,
+		reputation.NewNoTracker(),
 	)
 	require.NoError(err)
 
@@ -64,6 +76,7 @@ func TestInboundMsgByteThrottlerCancelContext(t *testing.T) {
 		prometheus.NewRegistry(),
 		vdrs,
 		config,
+		reputation.NewNoTracker(),
 	)
 	require.NoError(err)
 
@@ -122,6 +135,7 @@ func TestInboundMsgByteThrottler(t *testing.T) {
 		prometheus.NewRegistry(),
 		vdrs,
 		config,
+		reputation.NewNoTracker(),
 	)
 	require.NoError(err)
 
@@ -337,6 +351,7 @@ func TestSybilMsgThrottlerMaxNonVdr(t *testing.T) {
 		prometheus.NewRegistry(),
 		vdrs,
 		config,
+		reputation.NewNoTracker(),
 	)
 	require.NoError(err)
 	nonVdrNodeID1 := ids.GenerateTestNodeID()
@@ -388,6 +403,7 @@ func TestMsgThrottlerNextMsg(t *testing.T) {
 		prometheus.NewRegistry(),
 		vdrs,
 		config,
+		reputation.NewNoTracker(),
 	)
 	require.NoError(err)
 
@@ -444,3 +460,61 @@ func TestMsgThrottlerNextMsg(t *testing.T) {
 	// next non validator message should finish
 	<-done
 }
+
+// throttledReporter counts the Throttled events reported per node
+type throttledReporter map[ids.NodeID]int
+
+func (r throttledReporter) Report(nodeID ids.NodeID, event reputation.Event) {
+	if event == reputation.Throttled {
+		r[nodeID]++
+	}
+}
+
+func TestInboundMsgByteThrottlerReportsThrottledNodes(t *testing.T) {
+	require := require.New(t)
+	config := MsgByteThrottlerConfig{
+		VdrAllocSize:        10,
+		AtLargeAllocSize:    10,
+		NodeMaxAtLargeBytes: 4,
+	}
+	vdrs := validators.NewSet()
+	vdr1ID := ids.GenerateTestNodeID()
+	vdr2ID := ids.GenerateTestNodeID()
+	require.NoError(vdrs.Add(vdr1ID, nil, ids.Empty, 1))
+	require.NoError(vdrs.Add(vdr2ID, nil, ids.Empty, 1))
+	reporter := throttledReporter{}
+	throttler, err := newInboundMsgByteThrottler(
+		logging.NoLog{},
+		"",
+		prometheus.NewRegistry(),
+		vdrs,
+		config,
+		reporter,
+	)
+	require.NoError(err)
+
+	// Acquire calls with a cancelled context return once the message had to
+	// wait for bytes
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// [vdr1ID] uses up its validator allocation and the bytes it may take
+	// from the at-large allocation
+	throttler.Acquire(context.Background(), 9, vdr1ID)
+	throttler.Acquire(cancelledCtx, 1, vdr1ID)
+	require.Equal(throttledReporter{vdr1ID: 1}, reporter)
+
+	// [nonVdrID] has no validator allocation and uses up the bytes it may
+	// take from the at-large allocation
+	nonVdrID := ids.GenerateTestNodeID()
+	throttler.Acquire(context.Background(), 4, nonVdrID)
+	throttler.Acquire(cancelledCtx, 1, nonVdrID)
+	require.Equal(throttledReporter{vdr1ID: 1, nonVdrID: 1}, reporter)
+
+	// [vdr2ID] has to wait because other nodes used up the at-large
+	// allocation
+	throttler.Acquire(context.Background(), 2, ids.GenerateTestNodeID())
+	require.Zero(throttler.remainingAtLargeBytes)
+	throttler.Acquire(cancelledCtx, 6, vdr2ID)
+	require.Equal(throttledReporter{vdr1ID: 1, nonVdrID: 1}, reporter)
+}
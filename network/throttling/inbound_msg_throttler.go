@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -10,6 +20,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/logging"
@@ -61,6 +72,7 @@ func NewInboundMsgThrottler(
 	resourceTracker tracker.ResourceTracker,
 	cpuTargeter tracker.Targeter,
 	diskTargeter tracker.Targeter,
+	reporter reputation.Reporter,
 ) (InboundMsgThrottler, error) {
 	byteThrottler, err := newInboundMsgByteThrottler(
 		log,
@@ -68,6 +80,7 @@ func NewInboundMsgThrottler(
 		registerer,
 		vdrs,
 		throttlerConfig.MsgByteThrottlerConfig,
+		reporter,
 	)
 	if err != nil {
 		return nil, err
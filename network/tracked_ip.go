@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -20,23 +30,32 @@ type trackedIP struct {
 	delay     time.Duration
 
 	ip ips.IPPort
+	// Other IPs of the node, dialed in order after [ip]
+	extraIPs []ips.IPPort
+
+	// Must be held while accessing [dialIndex]
+	dialIndexLock sync.Mutex
+	// Index of the IP that is dialed next, 0 is [ip]
+	dialIndex int
 
 	stopTrackingOnce sync.Once
 	onStopTracking   chan struct{}
 }
 
-func newTrackedIP(ip ips.IPPort) *trackedIP {
+func newTrackedIP(ip ips.IPPort, extraIPs ...ips.IPPort) *trackedIP {
 	return &trackedIP{
 		ip:             ip,
+		extraIPs:       extraIPs,
 		onStopTracking: make(chan struct{}),
 	}
 }
 
-func (ip *trackedIP) trackNewIP(newIP ips.IPPort) *trackedIP {
+func (ip *trackedIP) trackNewIP(newIP ips.IPPort, extraIPs ...ips.IPPort) *trackedIP {
 	ip.stopTracking()
 	return &trackedIP{
 		delay:          ip.getDelay(),
 		ip:             newIP,
+		extraIPs:       extraIPs,
 		onStopTracking: make(chan struct{}),
 	}
 }
@@ -0,0 +1,23 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+)
+
+var _ router.Router = (*bandwidthRouter)(nil)
+
+// bandwidthRouter drops the traffic accounted with peers once they disconnect
+type bandwidthRouter struct {
+	router.Router
+	bandwidth bandwidth.Tracker
+}
+
+func (b *bandwidthRouter) Disconnected(nodeID ids.NodeID) {
+	b.bandwidth.Disconnected(nodeID)
+	b.Router.Disconnected(nodeID)
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+)
+
+func TestBandwidthRouterDisconnected(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	tracker, err := bandwidth.NewTracker("", prometheus.NewRegistry())
+	require.NoError(err)
+	nodeID := ids.GenerateTestNodeID()
+	tracker.Received(ids.GenerateTestID(), nodeID, 10)
+	require.Contains(tracker.Peers(), nodeID)
+
+	mockRouter := router.NewMockRouter(ctrl)
+	mockRouter.EXPECT().Disconnected(nodeID)
+	r := &bandwidthRouter{
+		Router:    mockRouter,
+		bandwidth: tracker,
+	}
+	r.Disconnected(nodeID)
+	require.NotContains(tracker.Peers(), nodeID)
+}
@@ -17,13 +17,17 @@ import (
 	"crypto/tls"
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/auth"
 	"github.com/ava-labs/avalanchego/api/server"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/ipcs/eventsink"
 	"github.com/ava-labs/avalanchego/nat"
 	"github.com/ava-labs/avalanchego/network"
+	"github.com/ava-labs/avalanchego/network/peer"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/subnets"
@@ -37,20 +41,38 @@ import (
 	"github.com/ava-labs/avalanchego/utils/timer"
 )
 
+const (
+	IPCSinkTypeFile  = "file"
+	IPCSinkTypeKafka = "kafka"
+)
+
 type IPCConfig struct {
 	IPCAPIEnabled      bool     `json:"ipcAPIEnabled"`
 	IPCPath            string   `json:"ipcPath"`
 	IPCDefaultChainIDs []string `json:"ipcDefaultChainIDs"`
+
+	// Type of the sink accepted txs are published to. If empty, accepted txs
+	// aren't published.
+	IPCSinkType     string   `json:"ipcSinkType"`
+	IPCSinkChainIDs []string `json:"ipcSinkChainIDs"`
+
+	IPCSinkFileDir      string `json:"ipcSinkFileDir"`
+	IPCSinkFileMaxSize  uint64 `json:"ipcSinkFileMaxSize"`
+	IPCSinkFileMaxFiles int    `json:"ipcSinkFileMaxFiles"`
+
+	IPCSinkKafkaConfig eventsink.KafkaConfig `json:"ipcSinkKafkaConfig"`
 }
 
 type APIAuthConfig struct {
-	APIRequireAuthToken bool   `json:"apiRequireAuthToken"`
-	APIAuthPassword     string `json:"-"`
+	APIRequireAuthToken bool             `json:"apiRequireAuthToken"`
+	APIAuthPassword     string           `json:"-"`
+	APIAuthPrincipals   []auth.Principal `json:"-"`
 }
 
 type APIIndexerConfig struct {
-	IndexAPIEnabled      bool `json:"indexAPIEnabled"`
-	IndexAllowIncomplete bool `json:"indexAllowIncomplete"`
+	IndexAPIEnabled       bool `json:"indexAPIEnabled"`
+	IndexAllowIncomplete  bool `json:"indexAllowIncomplete"`
+	IndexAddressesEnabled bool `json:"indexAddressesEnabled"`
 }
 
 type HTTPConfig struct {
@@ -86,6 +108,10 @@ type IPConfig struct {
 	IPPort           ips.DynamicIPPort `json:"ip"`
 	IPUpdater        dynamicip.Updater `json:"-"`
 	IPResolutionFreq time.Duration     `json:"ipResolutionFrequency"`
+	// Endpoints advertised in addition to [IPPort]
+	ExtraIPPorts ips.DynamicIPPorts `json:"extraIPs"`
+	// Keeps [ExtraIPPorts] up to date
+	ExtraIPsUpdater dynamicip.Updater `json:"-"`
 	// True if we attempted NAT traversal
 	AttemptedNATTraversal bool `json:"attemptedNATTraversal"`
 	// Tries to perform network address translation
@@ -142,6 +168,10 @@ type DatabaseConfig struct {
 
 	// Path to config file
 	Config []byte `json:"-"`
+
+	// MigrateFromLevelDB specifies if an existing leveldb database should be
+	// copied into a new pebble database on startup
+	MigrateFromLevelDB bool `json:"migrateFromLevelDB"`
 }
 
 // Config contains all of the configurations of an Avalanche node.
@@ -171,6 +201,10 @@ type Config struct {
 
 	BenchlistConfig benchlist.Config `json:"benchlistConfig"`
 
+	ReputationConfig reputation.Config `json:"reputationConfig"`
+
+	NetworkCaptureConfig peer.CaptureConfig `json:"networkCaptureConfig"`
+
 	ProfilerConfig profiler.Config `json:"profilerConfig"`
 
 	LoggingConfig logging.Config `json:"loggingConfig"`
@@ -46,19 +46,25 @@ import (
 	"github.com/ava-labs/avalanchego/database/leveldb"
 	"github.com/ava-labs/avalanchego/database/manager"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/pebble"
 	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/genesis"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/indexer"
 	"github.com/ava-labs/avalanchego/ipcs"
+	"github.com/ava-labs/avalanchego/ipcs/eventsink"
 	"github.com/ava-labs/avalanchego/message"
 	"github.com/ava-labs/avalanchego/network"
 	"github.com/ava-labs/avalanchego/network/dialer"
 	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/network/quic"
 	"github.com/ava-labs/avalanchego/network/throttling"
+	"github.com/ava-labs/avalanchego/snapshot"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
 	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/router"
 	"github.com/ava-labs/avalanchego/snow/networking/timeout"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
@@ -96,8 +102,11 @@ import (
 )
 
 var (
-	genesisHashKey  = []byte("genesisID")
-	indexerDBPrefix = []byte{0x00}
+	genesisHashKey          = []byte("genesisID")
+	indexerDBPrefix         = []byte{0x00}
+	ipcSinkDBPrefix         = []byte("ipcsink")
+	networkDBPrefix         = []byte("network")
+	consensusParamsDBPrefix = []byte("consensus params")
 
 	errInvalidTLSKey = errors.New("invalid TLS key")
 	errShuttingDown  = errors.New("server shutting down")
@@ -123,6 +132,9 @@ type Node struct {
 	// Indexes blocks, transactions and blocks
 	indexer indexer.Indexer
 
+	// Exports snapshots of [DB] and verifies restored snapshots
+	snapshotter snapshot.Snapshotter
+
 	// Handles calls to Keystore API
 	keystore keystore.Keystore
 
@@ -141,6 +153,16 @@ type Node struct {
 	// Manages validator benching
 	benchlistManager benchlist.Manager
 
+	// Tracks the behaviour of peers
+	reputation reputation.Tracker
+
+	// Accounts for the traffic of chains and enforces subnet quotas
+	bandwidth bandwidth.Tracker
+
+	// Captures the messages exchanged with peers, nil if network capture is
+	// disabled
+	networkRecorder *peer.FileRecorder
+
 	uptimeCalculator uptime.LockedCalculator
 
 	// dispatcher for events as they happen in consensus
@@ -149,6 +171,9 @@ type Node struct {
 	VertexAcceptorGroup snow.AcceptorGroup
 
 	IPCs *ipcs.ChainIPCs
+	// Publishes accepted txs to the configured event sink. Nil, if no event
+	// sink is configured.
+	IPCSinks *ipcs.ChainSinks
 
 	// Net runs the networking stack
 	networkNamespace string
@@ -164,6 +189,9 @@ type Node struct {
 	// current validators of the network
 	vdrs validators.Manager
 
+	// pending validators of the primary network
+	pendingVdrs validators.Set
+
 	// Handles HTTP API calls
 	APIServer server.Server
 
@@ -261,15 +289,79 @@ func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
 
 	tlsConfig := peer.TLSConfig(n.Config.StakingTLSCert, n.tlsKeyLogWriterCloser)
 
+	if n.Config.NetworkConfig.QUICEnabled {
+		quicPort := n.Config.NetworkConfig.QUICPort
+		if quicPort == 0 {
+			quicPort = currentIPPort.Port
+		}
+		quicListener, err := quic.Listen(
+			fmt.Sprintf(":%d", quicPort),
+			tlsConfig,
+			n.Config.NetworkConfig.ReadHandshakeTimeout,
+			n.Log,
+		)
+		if err != nil {
+			return err
+		}
+		if addr, ok := quicListener.Addr().(*net.UDPAddr); ok {
+			quicPort = uint16(addr.Port)
+		}
+		n.Log.Info("accepting QUIC connections",
+			zap.Uint16("port", quicPort),
+		)
+
+		n.Config.NetworkConfig.QUICPort = quicPort
+		// Wrap listener so it will only accept a certain number of incoming
+		// connections per second
+		n.Config.NetworkConfig.QUICListener = throttling.NewThrottledListener(quicListener, n.Config.NetworkConfig.ThrottlerConfig.MaxInboundConnsPerSec)
+		n.Config.NetworkConfig.QUICDialer = quic.NewDialer(tlsConfig, n.Config.NetworkConfig.DialerConfig, n.Log)
+	}
+
+	// Configure reputation tracking
+	n.reputation = reputation.NewNoTracker()
+	if n.Config.ReputationConfig.Enabled {
+		n.reputation, err = reputation.NewTracker(n.Config.ReputationConfig, "reputation", n.MetricsRegisterer)
+		if err != nil {
+			return fmt.Errorf("couldn't initialize reputation tracker: %w", err)
+		}
+	}
+	n.Config.NetworkConfig.Reputation = n.reputation
+
+	// Configure bandwidth accounting
+	n.bandwidth, err = bandwidth.NewTracker("bandwidth", n.MetricsRegisterer)
+	if err != nil {
+		return fmt.Errorf("couldn't initialize bandwidth tracker: %w", err)
+	}
+
+	// Configure network capture
+	n.Config.NetworkConfig.Recorder = peer.NewNoRecorder()
+	if n.Config.NetworkCaptureConfig.Enabled {
+		n.networkRecorder, err = peer.NewFileRecorder(n.Config.NetworkCaptureConfig, n.msgCreator, n.Log)
+		if err != nil {
+			return fmt.Errorf("couldn't initialize network capture: %w", err)
+		}
+		n.Config.NetworkConfig.Recorder = n.networkRecorder
+		n.Log.Info("capturing network messages",
+			zap.String("dir", n.Config.NetworkCaptureConfig.Dir),
+		)
+	}
+
 	// Configure benchlist
 	n.Config.BenchlistConfig.Validators = n.vdrs
 	n.Config.BenchlistConfig.Benchable = n.Config.ConsensusRouter
 	n.Config.BenchlistConfig.StakingEnabled = n.Config.EnableStaking
-	n.benchlistManager = benchlist.NewManager(&n.Config.BenchlistConfig)
+	n.benchlistManager = benchlist.NewReputationManager(
+		benchlist.NewManager(&n.Config.BenchlistConfig),
+		n.reputation,
+		&n.Config.BenchlistConfig,
+	)
 
 	n.uptimeCalculator = uptime.NewLockedCalculator()
 
-	consensusRouter := n.Config.ConsensusRouter
+	var consensusRouter router.Router = &bandwidthRouter{
+		Router:    n.Config.ConsensusRouter,
+		bandwidth: n.bandwidth,
+	}
 	if !n.Config.EnableStaking {
 		// Staking is disabled so we don't have a txID that added us as a
 		// validator. Because each validator needs a txID associated with it, we
@@ -338,6 +430,7 @@ func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
 	n.Config.NetworkConfig.Namespace = n.networkNamespace
 	n.Config.NetworkConfig.MyNodeID = n.ID
 	n.Config.NetworkConfig.MyIPPort = n.Config.IPPort
+	n.Config.NetworkConfig.MyExtraIPPorts = n.Config.ExtraIPPorts
 	n.Config.NetworkConfig.NetworkID = n.Config.NetworkID
 	n.Config.NetworkConfig.Validators = n.vdrs
 	n.Config.NetworkConfig.Beacons = n.beacons
@@ -350,6 +443,8 @@ func (n *Node) initNetworking(primaryNetVdrs validators.Set) error {
 	n.Config.NetworkConfig.CPUTargeter = n.cpuTargeter
 	n.Config.NetworkConfig.DiskTargeter = n.diskTargeter
 	n.Config.NetworkConfig.GossipTracker = gossipTracker
+	n.Config.NetworkConfig.PeerDB = prefixdb.New(networkDBPrefix, n.DB)
+	n.Config.NetworkConfig.PendingValidators = n.pendingVdrs
 
 	n.Net, err = network.NewNetwork(
 		&n.Config.NetworkConfig,
@@ -439,12 +534,23 @@ func (n *Node) initDatabase() error {
 		dbManager, err = manager.NewLevelDB(n.Config.DatabaseConfig.Path, n.Config.DatabaseConfig.Config, n.Log, version.CurrentDatabase, "db_internal", n.MetricsRegisterer)
 	case memdb.Name:
 		dbManager = manager.NewMemDB(version.CurrentDatabase)
+	case pebble.Name:
+		// Pebble databases are stored next to the leveldb version directories
+		// so that both can coexist during a migration.
+		pebbleDBPath := filepath.Join(n.Config.DatabaseConfig.Path, pebble.Name)
+		if n.Config.DatabaseConfig.MigrateFromLevelDB {
+			if err := n.migrateLevelDBToPebble(pebbleDBPath); err != nil {
+				return err
+			}
+		}
+		dbManager, err = manager.NewPebbleDB(pebbleDBPath, n.Config.DatabaseConfig.Config, n.Log, version.CurrentDatabase, "db_internal", n.MetricsRegisterer)
 	default:
 		err = fmt.Errorf(
-			"db-type was %q but should have been one of {%s, %s}",
+			"db-type was %q but should have been one of {%s, %s, %s}",
 			n.Config.DatabaseConfig.Name,
 			leveldb.Name,
 			memdb.Name,
+			pebble.Name,
 		)
 	}
 	if err != nil {
@@ -490,6 +596,39 @@ func (n *Node) initDatabase() error {
 	return nil
 }
 
+// migrateLevelDBToPebble copies the current leveldb database into a pebble
+// database under [pebbleDBPath]. The migration is skipped if there is no
+// leveldb database or if the pebble database already exists.
+func (n *Node) migrateLevelDBToPebble(pebbleDBPath string) error {
+	versionStr := version.CurrentDatabase.String()
+	levelDBPath := filepath.Join(n.Config.DatabaseConfig.Path, versionStr)
+	dstPath := filepath.Join(pebbleDBPath, versionStr)
+
+	if _, err := os.Stat(levelDBPath); os.IsNotExist(err) {
+		n.Log.Info("skipping leveldb migration",
+			zap.String("reason", "no leveldb database found"),
+			zap.String("path", levelDBPath),
+		)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dstPath); err == nil {
+		n.Log.Info("skipping leveldb migration",
+			zap.String("reason", "pebble database already exists"),
+			zap.String("path", dstPath),
+		)
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(pebbleDBPath, perms.ReadWriteExecute); err != nil {
+		return err
+	}
+	return pebble.MigrateFromLevelDB(levelDBPath, dstPath, n.Config.DatabaseConfig.Config, n.Log)
+}
+
 // Set the node IDs of the peers this node should first connect to
 func (n *Node) initBeacons() error {
 	n.beacons = validators.NewSet()
@@ -535,16 +674,85 @@ func (n *Node) initIPCs() error {
 	return err
 }
 
+// initIPCSinks initializes [n.IPCSinks], if an event sink is configured.
+// Should only be called after [n.DB], [n.Log] and [n.chainManager] are
+// initialized
+func (n *Node) initIPCSinks() error {
+	var (
+		sink eventsink.Sink
+		err  error
+	)
+	switch n.Config.IPCSinkType {
+	case "":
+		n.Log.Info("skipping ipc sink initialization because no sink is configured")
+		return nil
+	case IPCSinkTypeFile:
+		sink, err = eventsink.NewFileSink(n.Config.IPCSinkFileDir, n.Config.IPCSinkFileMaxSize, n.Config.IPCSinkFileMaxFiles)
+	case IPCSinkTypeKafka:
+		sink, err = eventsink.NewKafkaSink(n.Config.IPCSinkKafkaConfig)
+	default:
+		err = fmt.Errorf("unknown ipc sink type %q", n.Config.IPCSinkType)
+	}
+	if err != nil {
+		return err
+	}
+
+	chainIDs := make([]ids.ID, len(n.Config.IPCSinkChainIDs))
+	for i, chainID := range n.Config.IPCSinkChainIDs {
+		id, err := ids.FromString(chainID)
+		if err != nil {
+			_ = sink.Close()
+			return err
+		}
+		chainIDs[i] = id
+	}
+
+	n.IPCSinks = ipcs.NewChainSinks(
+		n.Log,
+		n.Config.NetworkID,
+		prefixdb.New(ipcSinkDBPrefix, n.DB),
+		sink,
+		n.BlockAcceptorGroup,
+		n.TxAcceptorGroup,
+		chainIDs,
+	)
+	n.chainManager.AddRegistrant(n.IPCSinks)
+	return nil
+}
+
+// Initialize [n.snapshotter].
+// Should only be called after [n.DB], [n.Log] and [n.chainManager] are
+// initialized
+func (n *Node) initSnapshotter() error {
+	var err error
+	n.snapshotter, err = snapshot.New(snapshot.Config{
+		DB:                 n.DB,
+		DBPrefix:           snapshot.DBPrefix,
+		NetworkID:          n.Config.NetworkID,
+		DatabaseVersion:    version.CurrentDatabase.String(),
+		Log:                n.Log,
+		BlockAcceptorGroup: n.BlockAcceptorGroup,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Chain manager will notify the snapshotter when a chain is created
+	n.chainManager.AddRegistrant(n.snapshotter)
+	return nil
+}
+
 // Initialize [n.indexer].
 // Should only be called after [n.DB], [n.DecisionAcceptorGroup],
-// [n.ConsensusAcceptorGroup], [n.Log], [n.APIServer], [n.chainManager] are
-// initialized
+// [n.ConsensusAcceptorGroup], [n.Log], [n.APIServer], [n.chainManager],
+// [n.health] are initialized
 func (n *Node) initIndexer() error {
 	txIndexerDB := prefixdb.New(indexerDBPrefix, n.DB)
 	var err error
 	n.indexer, err = indexer.NewIndexer(indexer.Config{
 		IndexingEnabled:      n.Config.IndexAPIEnabled,
 		AllowIncompleteIndex: n.Config.IndexAllowIncomplete,
+		AddressIndexEnabled:  n.Config.IndexAddressesEnabled,
 		DB:                   txIndexerDB,
 		Log:                  n.Log,
 		BlockAcceptorGroup:   n.BlockAcceptorGroup,
@@ -562,6 +770,11 @@ func (n *Node) initIndexer() error {
 	// Chain manager will notify indexer when a chain is created
 	n.chainManager.AddRegistrant(n.indexer)
 
+	// Reports the progress of backfilling the indices
+	err = n.health.RegisterHealthCheck("indexer", n.indexer, health.GlobalTag)
+	if err != nil {
+		return fmt.Errorf("couldn't register indexer health check: %w", err)
+	}
 	return nil
 }
 
@@ -610,7 +823,11 @@ func (n *Node) initAPIServer() error {
 		return err
 	}
 
-	a, err := auth.New(n.Log, "auth", n.Config.APIAuthPassword)
+	auditLog, err := n.LogFactory.Make("auth-audit")
+	if err != nil {
+		return fmt.Errorf("problem creating auth audit logger: %w", err)
+	}
+	a, err := auth.New(n.Log, auditLog, "auth", n.Config.APIAuthPassword, n.Config.APIAuthPrincipals)
 	if err != nil {
 		return err
 	}
@@ -741,6 +958,8 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		CChainID:                                cChainID,
 		CriticalChains:                          criticalChains,
 		TimeoutManager:                          timeoutManager,
+		Reputation:                              n.reputation,
+		Bandwidth:                               n.bandwidth,
 		Health:                                  n.health,
 		RetryBootstrap:                          n.Config.RetryBootstrap,
 		RetryBootstrapWarnFrequency:             n.Config.RetryBootstrapWarnFrequency,
@@ -761,6 +980,7 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		TracingEnabled:                          n.Config.TraceConfig.Enabled,
 		Tracer:                                  n.tracer,
 		ChainDataDir:                            n.Config.ChainDataDir,
+		ConsensusParamsDB:                       prefixdb.New(consensusParamsDBPrefix, n.DB),
 	})
 
 	// Notify the API server when new chains are created
@@ -797,8 +1017,10 @@ func (n *Node) initVMs() error {
 			Config: platformconfig.Config{
 				Chains:                          n.chainManager,
 				Validators:                      vdrs,
+				PendingValidators:               n.pendingVdrs,
 				UptimeLockedCalculator:          n.uptimeCalculator,
 				StakingEnabled:                  n.Config.EnableStaking,
+				Health:                          n.health,
 				TrackedSubnets:                  n.Config.TrackedSubnets,
 				TxFee:                           n.Config.TxFee,
 				CreateAssetTxFee:                n.Config.CreateAssetTxFee,
@@ -948,24 +1170,31 @@ func (n *Node) initAdminAPI() error {
 	}
 
 	n.Log.Info("initializing admin API")
-	service, err := admin.NewService(
-		admin.Config{
-			Secret:         n.Config.AdminAPIEnabledSecret,
-			Log:            n.Log,
-			ChainManager:   n.chainManager,
-			HTTPServer:     n.APIServer,
-			ProfileDir:     n.Config.ProfilerConfig.Dir,
-			LogFactory:     n.LogFactory,
-			NodeConfig:     n.Config,
-			VMManager:      n.VMManager,
-			VMRegistry:     n.VMRegistry,
-			StakingTLSCert: n.Config.StakingTLSCert,
-		},
-	)
+	config := admin.Config{
+		Secret:         n.Config.AdminAPIEnabledSecret,
+		Log:            n.Log,
+		ChainManager:   n.chainManager,
+		HTTPServer:     n.APIServer,
+		ProfileDir:     n.Config.ProfilerConfig.Dir,
+		LogFactory:     n.LogFactory,
+		NodeConfig:     n.Config,
+		VMManager:      n.VMManager,
+		VMRegistry:     n.VMRegistry,
+		Snapshotter:    n.snapshotter,
+		StakingTLSCert: n.Config.StakingTLSCert,
+		Network:        n.Net,
+	}
+	service, err := admin.NewService(config)
 	if err != nil {
 		return err
 	}
-	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "")
+	// The JSON-RPC and the gRPC admin API share their lock, so admin calls are
+	// serialized regardless of the protocol they are made with
+	lock := &sync.RWMutex{}
+	if err := n.APIServer.AddRoute(service, lock, "admin", ""); err != nil {
+		return err
+	}
+	return n.APIServer.AddGRPCRoute(admin.NewGRPCService(config), lock)
 }
 
 // initProfiler initializes the continuous profiling
@@ -1001,26 +1230,28 @@ func (n *Node) initInfoAPI() error {
 	n.Log.Info("initializing info API")
 
 	primaryValidators, _ := n.vdrs.Get(constants.PrimaryNetworkID)
+	parameters := info.Parameters{
+		Version:                       version.CurrentApp,
+		GitVersion:                    version.GitVersion,
+		GitCommit:                     version.GitCommit,
+		NodeID:                        n.ID,
+		NodePOP:                       signer.NewProofOfPossession(n.Config.StakingSigningKey),
+		NetworkID:                     n.Config.NetworkID,
+		TxFee:                         n.Config.TxFee,
+		CreateAssetTxFee:              n.Config.CreateAssetTxFee,
+		CreateSubnetTxFee:             n.Config.CreateSubnetTxFee,
+		TransformSubnetTxFee:          n.Config.TransformSubnetTxFee,
+		CreateBlockchainTxFee:         n.Config.CreateBlockchainTxFee,
+		AddPrimaryNetworkValidatorFee: n.Config.AddPrimaryNetworkValidatorFee,
+		AddPrimaryNetworkDelegatorFee: n.Config.AddPrimaryNetworkDelegatorFee,
+		AddSubnetValidatorFee:         n.Config.AddSubnetValidatorFee,
+		AddSubnetDelegatorFee:         n.Config.AddSubnetDelegatorFee,
+		VMManager:                     n.VMManager,
+		GenesisBytes:                  n.Config.GenesisBytes,
+		Bandwidth:                     n.bandwidth,
+	}
 	service, err := info.NewService(
-		info.Parameters{
-			Version:                       version.CurrentApp,
-			GitVersion:                    version.GitVersion,
-			GitCommit:                     version.GitCommit,
-			NodeID:                        n.ID,
-			NodePOP:                       signer.NewProofOfPossession(n.Config.StakingSigningKey),
-			NetworkID:                     n.Config.NetworkID,
-			TxFee:                         n.Config.TxFee,
-			CreateAssetTxFee:              n.Config.CreateAssetTxFee,
-			CreateSubnetTxFee:             n.Config.CreateSubnetTxFee,
-			TransformSubnetTxFee:          n.Config.TransformSubnetTxFee,
-			CreateBlockchainTxFee:         n.Config.CreateBlockchainTxFee,
-			AddPrimaryNetworkValidatorFee: n.Config.AddPrimaryNetworkValidatorFee,
-			AddPrimaryNetworkDelegatorFee: n.Config.AddPrimaryNetworkDelegatorFee,
-			AddSubnetValidatorFee:         n.Config.AddSubnetValidatorFee,
-			AddSubnetDelegatorFee:         n.Config.AddSubnetDelegatorFee,
-			VMManager:                     n.VMManager,
-			GenesisBytes:                  n.Config.GenesisBytes,
-		},
+		parameters,
 		n.Log,
 		n.chainManager,
 		n.VMManager,
@@ -1032,7 +1263,21 @@ func (n *Node) initInfoAPI() error {
 	if err != nil {
 		return err
 	}
-	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "info", "")
+	if err := n.APIServer.AddRoute(service, &sync.RWMutex{}, "info", ""); err != nil {
+		return err
+	}
+
+	grpcService := info.NewGRPCService(
+		parameters,
+		n.Log,
+		n.chainManager,
+		n.VMManager,
+		n.Config.NetworkConfig.MyIPPort,
+		n.Net,
+		primaryValidators,
+		n.benchlistManager,
+	)
+	return n.APIServer.AddGRPCRoute(grpcService, &sync.RWMutex{})
 }
 
 // initHealthAPI initializes the Health API service
@@ -1137,7 +1382,7 @@ func (n *Node) initHealthAPI() error {
 		return err
 	}
 
-	return n.APIServer.AddRoute(
+	err = n.APIServer.AddRoute(
 		&common.HTTPHandler{
 			LockOptions: common.NoLock,
 			Handler:     health.NewGetHandler(healthChecker.Liveness),
@@ -1146,6 +1391,17 @@ func (n *Node) initHealthAPI() error {
 		"health",
 		"/liveness",
 	)
+	if err != nil {
+		return err
+	}
+
+	return n.APIServer.AddGRPCRoute(
+		&common.HTTPHandler{
+			LockOptions: common.NoLock,
+			Handler:     health.NewGRPCService(healthChecker),
+		},
+		&sync.RWMutex{},
+	)
 }
 
 // initIPCAPI initializes the IPC API service
@@ -1206,9 +1462,11 @@ func (n *Node) initAPIAliases(genesisBytes []byte) error {
 	return nil
 }
 
-// Initializes [n.vdrs] and returns the Primary Network validator set.
+// Initializes [n.vdrs] and [n.pendingVdrs] and returns the Primary Network
+// validator set.
 func (n *Node) initVdrs() validators.Set {
 	n.vdrs = validators.NewManager()
+	n.pendingVdrs = validators.NewSet()
 	vdrSet := validators.NewSet()
 	_ = n.vdrs.Add(constants.PrimaryNetworkID, vdrSet)
 	return vdrSet
@@ -1365,6 +1623,9 @@ func (n *Node) Initialize(
 	if err := n.initVMs(); err != nil { // Initialize the VM registry.
 		return fmt.Errorf("couldn't initialize VM registry: %w", err)
 	}
+	if err := n.initSnapshotter(); err != nil {
+		return fmt.Errorf("couldn't initialize snapshotter: %w", err)
+	}
 	if err := n.initAdminAPI(); err != nil { // Start the Admin API
 		return fmt.Errorf("couldn't initialize admin API: %w", err)
 	}
@@ -1377,6 +1638,9 @@ func (n *Node) Initialize(
 	if err := n.initIPCAPI(); err != nil { // Start the IPC API
 		return fmt.Errorf("couldn't initialize the IPC API: %w", err)
 	}
+	if err := n.initIPCSinks(); err != nil { // Start publishing events
+		return fmt.Errorf("couldn't initialize IPC sinks: %w", err)
+	}
 	if err := n.initChainAliases(n.Config.GenesisBytes); err != nil {
 		return fmt.Errorf("couldn't initialize chain aliases: %w", err)
 	}
@@ -1440,6 +1704,13 @@ func (n *Node) shutdown() {
 			)
 		}
 	}
+	if n.IPCSinks != nil {
+		if err := n.IPCSinks.Shutdown(); err != nil {
+			n.Log.Debug("error during IPC sinks shutdown",
+				zap.Error(err),
+			)
+		}
+	}
 	if n.chainManager != nil {
 		n.chainManager.Shutdown()
 	}
@@ -1449,6 +1720,13 @@ func (n *Node) shutdown() {
 	if n.Net != nil {
 		n.Net.StartClose()
 	}
+	if n.networkRecorder != nil {
+		if err := n.networkRecorder.Close(); err != nil {
+			n.Log.Debug("error closing network capture",
+				zap.Error(err),
+			)
+		}
+	}
 	if err := n.APIServer.Shutdown(); err != nil {
 		n.Log.Debug("error during API shutdown",
 			zap.Error(err),
@@ -0,0 +1,861 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: admin/v1/admin.proto
+
+package adminv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdminClient interface {
+	StartCPUProfiler(ctx context.Context, in *StartCPUProfilerRequest, opts ...grpc.CallOption) (*StartCPUProfilerResponse, error)
+	StopCPUProfiler(ctx context.Context, in *StopCPUProfilerRequest, opts ...grpc.CallOption) (*StopCPUProfilerResponse, error)
+	MemoryProfile(ctx context.Context, in *MemoryProfileRequest, opts ...grpc.CallOption) (*MemoryProfileResponse, error)
+	LockProfile(ctx context.Context, in *LockProfileRequest, opts ...grpc.CallOption) (*LockProfileResponse, error)
+	Alias(ctx context.Context, in *AliasRequest, opts ...grpc.CallOption) (*AliasResponse, error)
+	AliasChain(ctx context.Context, in *AliasChainRequest, opts ...grpc.CallOption) (*AliasChainResponse, error)
+	GetChainAliases(ctx context.Context, in *GetChainAliasesRequest, opts ...grpc.CallOption) (*GetChainAliasesResponse, error)
+	Stacktrace(ctx context.Context, in *StacktraceRequest, opts ...grpc.CallOption) (*StacktraceResponse, error)
+	SetLoggerLevel(ctx context.Context, in *SetLoggerLevelRequest, opts ...grpc.CallOption) (*SetLoggerLevelResponse, error)
+	GetLoggerLevel(ctx context.Context, in *GetLoggerLevelRequest, opts ...grpc.CallOption) (*GetLoggerLevelResponse, error)
+	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error)
+	LoadVMs(ctx context.Context, in *LoadVMsRequest, opts ...grpc.CallOption) (*LoadVMsResponse, error)
+	ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*ExportSnapshotResponse, error)
+	BanPeer(ctx context.Context, in *BanPeerRequest, opts ...grpc.CallOption) (*BanPeerResponse, error)
+	UnbanPeer(ctx context.Context, in *UnbanPeerRequest, opts ...grpc.CallOption) (*UnbanPeerResponse, error)
+	DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error)
+	AddPersistentPeer(ctx context.Context, in *AddPersistentPeerRequest, opts ...grpc.CallOption) (*AddPersistentPeerResponse, error)
+	GetConsensusState(ctx context.Context, in *GetConsensusStateRequest, opts ...grpc.CallOption) (*GetConsensusStateResponse, error)
+	GetPreferenceChanges(ctx context.Context, in *GetPreferenceChangesRequest, opts ...grpc.CallOption) (*GetPreferenceChangesResponse, error)
+	ProposeConsensusParameters(ctx context.Context, in *ProposeConsensusParametersRequest, opts ...grpc.CallOption) (*ProposeConsensusParametersResponse, error)
+	ClearConsensusParameters(ctx context.Context, in *ClearConsensusParametersRequest, opts ...grpc.CallOption) (*ClearConsensusParametersResponse, error)
+	GetNodeSigner(ctx context.Context, in *GetNodeSignerRequest, opts ...grpc.CallOption) (*GetNodeSignerResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) StartCPUProfiler(ctx context.Context, in *StartCPUProfilerRequest, opts ...grpc.CallOption) (*StartCPUProfilerResponse, error) {
+	out := new(StartCPUProfilerResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/StartCPUProfiler", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) StopCPUProfiler(ctx context.Context, in *StopCPUProfilerRequest, opts ...grpc.CallOption) (*StopCPUProfilerResponse, error) {
+	out := new(StopCPUProfilerResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/StopCPUProfiler", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) MemoryProfile(ctx context.Context, in *MemoryProfileRequest, opts ...grpc.CallOption) (*MemoryProfileResponse, error) {
+	out := new(MemoryProfileResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/MemoryProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) LockProfile(ctx context.Context, in *LockProfileRequest, opts ...grpc.CallOption) (*LockProfileResponse, error) {
+	out := new(LockProfileResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/LockProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Alias(ctx context.Context, in *AliasRequest, opts ...grpc.CallOption) (*AliasResponse, error) {
+	out := new(AliasResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/Alias", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AliasChain(ctx context.Context, in *AliasChainRequest, opts ...grpc.CallOption) (*AliasChainResponse, error) {
+	out := new(AliasChainResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/AliasChain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetChainAliases(ctx context.Context, in *GetChainAliasesRequest, opts ...grpc.CallOption) (*GetChainAliasesResponse, error) {
+	out := new(GetChainAliasesResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/GetChainAliases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Stacktrace(ctx context.Context, in *StacktraceRequest, opts ...grpc.CallOption) (*StacktraceResponse, error) {
+	out := new(StacktraceResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/Stacktrace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) SetLoggerLevel(ctx context.Context, in *SetLoggerLevelRequest, opts ...grpc.CallOption) (*SetLoggerLevelResponse, error) {
+	out := new(SetLoggerLevelResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/SetLoggerLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetLoggerLevel(ctx context.Context, in *GetLoggerLevelRequest, opts ...grpc.CallOption) (*GetLoggerLevelResponse, error) {
+	out := new(GetLoggerLevelResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/GetLoggerLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigResponse, error) {
+	out := new(GetConfigResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/GetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) LoadVMs(ctx context.Context, in *LoadVMsRequest, opts ...grpc.CallOption) (*LoadVMsResponse, error) {
+	out := new(LoadVMsResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/LoadVMs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*ExportSnapshotResponse, error) {
+	out := new(ExportSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/ExportSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) BanPeer(ctx context.Context, in *BanPeerRequest, opts ...grpc.CallOption) (*BanPeerResponse, error) {
+	out := new(BanPeerResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/BanPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) UnbanPeer(ctx context.Context, in *UnbanPeerRequest, opts ...grpc.CallOption) (*UnbanPeerResponse, error) {
+	out := new(UnbanPeerResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/UnbanPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DisconnectPeer(ctx context.Context, in *DisconnectPeerRequest, opts ...grpc.CallOption) (*DisconnectPeerResponse, error) {
+	out := new(DisconnectPeerResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/DisconnectPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AddPersistentPeer(ctx context.Context, in *AddPersistentPeerRequest, opts ...grpc.CallOption) (*AddPersistentPeerResponse, error) {
+	out := new(AddPersistentPeerResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/AddPersistentPeer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetConsensusState(ctx context.Context, in *GetConsensusStateRequest, opts ...grpc.CallOption) (*GetConsensusStateResponse, error) {
+	out := new(GetConsensusStateResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/GetConsensusState", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetPreferenceChanges(ctx context.Context, in *GetPreferenceChangesRequest, opts ...grpc.CallOption) (*GetPreferenceChangesResponse, error) {
+	out := new(GetPreferenceChangesResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/GetPreferenceChanges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ProposeConsensusParameters(ctx context.Context, in *ProposeConsensusParametersRequest, opts ...grpc.CallOption) (*ProposeConsensusParametersResponse, error) {
+	out := new(ProposeConsensusParametersResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/ProposeConsensusParameters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ClearConsensusParameters(ctx context.Context, in *ClearConsensusParametersRequest, opts ...grpc.CallOption) (*ClearConsensusParametersResponse, error) {
+	out := new(ClearConsensusParametersResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/ClearConsensusParameters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetNodeSigner(ctx context.Context, in *GetNodeSignerRequest, opts ...grpc.CallOption) (*GetNodeSignerResponse, error) {
+	out := new(GetNodeSignerResponse)
+	err := c.cc.Invoke(ctx, "/admin.v1.Admin/GetNodeSigner", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility
+type AdminServer interface {
+	StartCPUProfiler(context.Context, *StartCPUProfilerRequest) (*StartCPUProfilerResponse, error)
+	StopCPUProfiler(context.Context, *StopCPUProfilerRequest) (*StopCPUProfilerResponse, error)
+	MemoryProfile(context.Context, *MemoryProfileRequest) (*MemoryProfileResponse, error)
+	LockProfile(context.Context, *LockProfileRequest) (*LockProfileResponse, error)
+	Alias(context.Context, *AliasRequest) (*AliasResponse, error)
+	AliasChain(context.Context, *AliasChainRequest) (*AliasChainResponse, error)
+	GetChainAliases(context.Context, *GetChainAliasesRequest) (*GetChainAliasesResponse, error)
+	Stacktrace(context.Context, *StacktraceRequest) (*StacktraceResponse, error)
+	SetLoggerLevel(context.Context, *SetLoggerLevelRequest) (*SetLoggerLevelResponse, error)
+	GetLoggerLevel(context.Context, *GetLoggerLevelRequest) (*GetLoggerLevelResponse, error)
+	GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error)
+	LoadVMs(context.Context, *LoadVMsRequest) (*LoadVMsResponse, error)
+	ExportSnapshot(context.Context, *ExportSnapshotRequest) (*ExportSnapshotResponse, error)
+	BanPeer(context.Context, *BanPeerRequest) (*BanPeerResponse, error)
+	UnbanPeer(context.Context, *UnbanPeerRequest) (*UnbanPeerResponse, error)
+	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
+	AddPersistentPeer(context.Context, *AddPersistentPeerRequest) (*AddPersistentPeerResponse, error)
+	GetConsensusState(context.Context, *GetConsensusStateRequest) (*GetConsensusStateResponse, error)
+	GetPreferenceChanges(context.Context, *GetPreferenceChangesRequest) (*GetPreferenceChangesResponse, error)
+	ProposeConsensusParameters(context.Context, *ProposeConsensusParametersRequest) (*ProposeConsensusParametersResponse, error)
+	ClearConsensusParameters(context.Context, *ClearConsensusParametersRequest) (*ClearConsensusParametersResponse, error)
+	GetNodeSigner(context.Context, *GetNodeSignerRequest) (*GetNodeSignerResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (UnimplementedAdminServer) StartCPUProfiler(context.Context, *StartCPUProfilerRequest) (*StartCPUProfilerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartCPUProfiler not implemented")
+}
+func (UnimplementedAdminServer) StopCPUProfiler(context.Context, *StopCPUProfilerRequest) (*StopCPUProfilerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopCPUProfiler not implemented")
+}
+func (UnimplementedAdminServer) MemoryProfile(context.Context, *MemoryProfileRequest) (*MemoryProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MemoryProfile not implemented")
+}
+func (UnimplementedAdminServer) LockProfile(context.Context, *LockProfileRequest) (*LockProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LockProfile not implemented")
+}
+func (UnimplementedAdminServer) Alias(context.Context, *AliasRequest) (*AliasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Alias not implemented")
+}
+func (UnimplementedAdminServer) AliasChain(context.Context, *AliasChainRequest) (*AliasChainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AliasChain not implemented")
+}
+func (UnimplementedAdminServer) GetChainAliases(context.Context, *GetChainAliasesRequest) (*GetChainAliasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChainAliases not implemented")
+}
+func (UnimplementedAdminServer) Stacktrace(context.Context, *StacktraceRequest) (*StacktraceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stacktrace not implemented")
+}
+func (UnimplementedAdminServer) SetLoggerLevel(context.Context, *SetLoggerLevelRequest) (*SetLoggerLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLoggerLevel not implemented")
+}
+func (UnimplementedAdminServer) GetLoggerLevel(context.Context, *GetLoggerLevelRequest) (*GetLoggerLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLoggerLevel not implemented")
+}
+func (UnimplementedAdminServer) GetConfig(context.Context, *GetConfigRequest) (*GetConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
+}
+func (UnimplementedAdminServer) LoadVMs(context.Context, *LoadVMsRequest) (*LoadVMsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadVMs not implemented")
+}
+func (UnimplementedAdminServer) ExportSnapshot(context.Context, *ExportSnapshotRequest) (*ExportSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSnapshot not implemented")
+}
+func (UnimplementedAdminServer) BanPeer(context.Context, *BanPeerRequest) (*BanPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BanPeer not implemented")
+}
+func (UnimplementedAdminServer) UnbanPeer(context.Context, *UnbanPeerRequest) (*UnbanPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnbanPeer not implemented")
+}
+func (UnimplementedAdminServer) DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisconnectPeer not implemented")
+}
+func (UnimplementedAdminServer) AddPersistentPeer(context.Context, *AddPersistentPeerRequest) (*AddPersistentPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPersistentPeer not implemented")
+}
+func (UnimplementedAdminServer) GetConsensusState(context.Context, *GetConsensusStateRequest) (*GetConsensusStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetConsensusState not implemented")
+}
+func (UnimplementedAdminServer) GetPreferenceChanges(context.Context, *GetPreferenceChangesRequest) (*GetPreferenceChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPreferenceChanges not implemented")
+}
+func (UnimplementedAdminServer) ProposeConsensusParameters(context.Context, *ProposeConsensusParametersRequest) (*ProposeConsensusParametersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProposeConsensusParameters not implemented")
+}
+func (UnimplementedAdminServer) ClearConsensusParameters(context.Context, *ClearConsensusParametersRequest) (*ClearConsensusParametersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearConsensusParameters not implemented")
+}
+func (UnimplementedAdminServer) GetNodeSigner(context.Context, *GetNodeSignerRequest) (*GetNodeSignerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeSigner not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_StartCPUProfiler_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartCPUProfilerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).StartCPUProfiler(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/StartCPUProfiler",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).StartCPUProfiler(ctx, req.(*StartCPUProfilerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_StopCPUProfiler_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopCPUProfilerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).StopCPUProfiler(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/StopCPUProfiler",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).StopCPUProfiler(ctx, req.(*StopCPUProfilerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_MemoryProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MemoryProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).MemoryProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/MemoryProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).MemoryProfile(ctx, req.(*MemoryProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_LockProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).LockProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/LockProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).LockProfile(ctx, req.(*LockProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Alias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AliasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Alias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/Alias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Alias(ctx, req.(*AliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AliasChain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AliasChainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AliasChain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/AliasChain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AliasChain(ctx, req.(*AliasChainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetChainAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChainAliasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetChainAliases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/GetChainAliases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetChainAliases(ctx, req.(*GetChainAliasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Stacktrace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StacktraceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Stacktrace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/Stacktrace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Stacktrace(ctx, req.(*StacktraceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_SetLoggerLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLoggerLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).SetLoggerLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/SetLoggerLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).SetLoggerLevel(ctx, req.(*SetLoggerLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetLoggerLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLoggerLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetLoggerLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/GetLoggerLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetLoggerLevel(ctx, req.(*GetLoggerLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/GetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetConfig(ctx, req.(*GetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_LoadVMs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadVMsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).LoadVMs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/LoadVMs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).LoadVMs(ctx, req.(*LoadVMsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ExportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ExportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/ExportSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ExportSnapshot(ctx, req.(*ExportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_BanPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BanPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).BanPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/BanPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).BanPeer(ctx, req.(*BanPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_UnbanPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnbanPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).UnbanPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/UnbanPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).UnbanPeer(ctx, req.(*UnbanPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DisconnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/DisconnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DisconnectPeer(ctx, req.(*DisconnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AddPersistentPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPersistentPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AddPersistentPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/AddPersistentPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AddPersistentPeer(ctx, req.(*AddPersistentPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetConsensusState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetConsensusStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetConsensusState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/GetConsensusState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetConsensusState(ctx, req.(*GetConsensusStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetPreferenceChanges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPreferenceChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetPreferenceChanges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/GetPreferenceChanges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetPreferenceChanges(ctx, req.(*GetPreferenceChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ProposeConsensusParameters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProposeConsensusParametersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ProposeConsensusParameters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/ProposeConsensusParameters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ProposeConsensusParameters(ctx, req.(*ProposeConsensusParametersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ClearConsensusParameters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearConsensusParametersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ClearConsensusParameters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/ClearConsensusParameters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ClearConsensusParameters(ctx, req.(*ClearConsensusParametersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetNodeSigner_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeSignerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetNodeSigner(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/admin.v1.Admin/GetNodeSigner",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetNodeSigner(ctx, req.(*GetNodeSignerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "admin.v1.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartCPUProfiler",
+			Handler:    _Admin_StartCPUProfiler_Handler,
+		},
+		{
+			MethodName: "StopCPUProfiler",
+			Handler:    _Admin_StopCPUProfiler_Handler,
+		},
+		{
+			MethodName: "MemoryProfile",
+			Handler:    _Admin_MemoryProfile_Handler,
+		},
+		{
+			MethodName: "LockProfile",
+			Handler:    _Admin_LockProfile_Handler,
+		},
+		{
+			MethodName: "Alias",
+			Handler:    _Admin_Alias_Handler,
+		},
+		{
+			MethodName: "AliasChain",
+			Handler:    _Admin_AliasChain_Handler,
+		},
+		{
+			MethodName: "GetChainAliases",
+			Handler:    _Admin_GetChainAliases_Handler,
+		},
+		{
+			MethodName: "Stacktrace",
+			Handler:    _Admin_Stacktrace_Handler,
+		},
+		{
+			MethodName: "SetLoggerLevel",
+			Handler:    _Admin_SetLoggerLevel_Handler,
+		},
+		{
+			MethodName: "GetLoggerLevel",
+			Handler:    _Admin_GetLoggerLevel_Handler,
+		},
+		{
+			MethodName: "GetConfig",
+			Handler:    _Admin_GetConfig_Handler,
+		},
+		{
+			MethodName: "LoadVMs",
+			Handler:    _Admin_LoadVMs_Handler,
+		},
+		{
+			MethodName: "ExportSnapshot",
+			Handler:    _Admin_ExportSnapshot_Handler,
+		},
+		{
+			MethodName: "BanPeer",
+			Handler:    _Admin_BanPeer_Handler,
+		},
+		{
+			MethodName: "UnbanPeer",
+			Handler:    _Admin_UnbanPeer_Handler,
+		},
+		{
+			MethodName: "DisconnectPeer",
+			Handler:    _Admin_DisconnectPeer_Handler,
+		},
+		{
+			MethodName: "AddPersistentPeer",
+			Handler:    _Admin_AddPersistentPeer_Handler,
+		},
+		{
+			MethodName: "GetConsensusState",
+			Handler:    _Admin_GetConsensusState_Handler,
+		},
+		{
+			MethodName: "GetPreferenceChanges",
+			Handler:    _Admin_GetPreferenceChanges_Handler,
+		},
+		{
+			MethodName: "ProposeConsensusParameters",
+			Handler:    _Admin_ProposeConsensusParameters_Handler,
+		},
+		{
+			MethodName: "ClearConsensusParameters",
+			Handler:    _Admin_ClearConsensusParameters_Handler,
+		},
+		{
+			MethodName: "GetNodeSigner",
+			Handler:    _Admin_GetNodeSigner_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin/v1/admin.proto",
+}
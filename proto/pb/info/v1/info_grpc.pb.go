@@ -0,0 +1,429 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: info/v1/info.proto
+
+package infov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// InfoClient is the client API for Info service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InfoClient interface {
+	GetNodeVersion(ctx context.Context, in *GetNodeVersionRequest, opts ...grpc.CallOption) (*GetNodeVersionResponse, error)
+	GetNodeID(ctx context.Context, in *GetNodeIDRequest, opts ...grpc.CallOption) (*GetNodeIDResponse, error)
+	GetNodeIP(ctx context.Context, in *GetNodeIPRequest, opts ...grpc.CallOption) (*GetNodeIPResponse, error)
+	GetNetworkID(ctx context.Context, in *GetNetworkIDRequest, opts ...grpc.CallOption) (*GetNetworkIDResponse, error)
+	GetNetworkName(ctx context.Context, in *GetNetworkNameRequest, opts ...grpc.CallOption) (*GetNetworkNameResponse, error)
+	GetBlockchainID(ctx context.Context, in *GetBlockchainIDRequest, opts ...grpc.CallOption) (*GetBlockchainIDResponse, error)
+	IsBootstrapped(ctx context.Context, in *IsBootstrappedRequest, opts ...grpc.CallOption) (*IsBootstrappedResponse, error)
+	Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error)
+	Uptime(ctx context.Context, in *UptimeRequest, opts ...grpc.CallOption) (*UptimeResponse, error)
+	GetTxFee(ctx context.Context, in *GetTxFeeRequest, opts ...grpc.CallOption) (*GetTxFeeResponse, error)
+}
+
+type infoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInfoClient(cc grpc.ClientConnInterface) InfoClient {
+	return &infoClient{cc}
+}
+
+func (c *infoClient) GetNodeVersion(ctx context.Context, in *GetNodeVersionRequest, opts ...grpc.CallOption) (*GetNodeVersionResponse, error) {
+	out := new(GetNodeVersionResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/GetNodeVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) GetNodeID(ctx context.Context, in *GetNodeIDRequest, opts ...grpc.CallOption) (*GetNodeIDResponse, error) {
+	out := new(GetNodeIDResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/GetNodeID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) GetNodeIP(ctx context.Context, in *GetNodeIPRequest, opts ...grpc.CallOption) (*GetNodeIPResponse, error) {
+	out := new(GetNodeIPResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/GetNodeIP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) GetNetworkID(ctx context.Context, in *GetNetworkIDRequest, opts ...grpc.CallOption) (*GetNetworkIDResponse, error) {
+	out := new(GetNetworkIDResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/GetNetworkID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) GetNetworkName(ctx context.Context, in *GetNetworkNameRequest, opts ...grpc.CallOption) (*GetNetworkNameResponse, error) {
+	out := new(GetNetworkNameResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/GetNetworkName", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) GetBlockchainID(ctx context.Context, in *GetBlockchainIDRequest, opts ...grpc.CallOption) (*GetBlockchainIDResponse, error) {
+	out := new(GetBlockchainIDResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/GetBlockchainID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) IsBootstrapped(ctx context.Context, in *IsBootstrappedRequest, opts ...grpc.CallOption) (*IsBootstrappedResponse, error) {
+	out := new(IsBootstrappedResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/IsBootstrapped", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error) {
+	out := new(PeersResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/Peers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) Uptime(ctx context.Context, in *UptimeRequest, opts ...grpc.CallOption) (*UptimeResponse, error) {
+	out := new(UptimeResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/Uptime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infoClient) GetTxFee(ctx context.Context, in *GetTxFeeRequest, opts ...grpc.CallOption) (*GetTxFeeResponse, error) {
+	out := new(GetTxFeeResponse)
+	err := c.cc.Invoke(ctx, "/info.v1.Info/GetTxFee", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InfoServer is the server API for Info service.
+// All implementations must embed UnimplementedInfoServer
+// for forward compatibility
+type InfoServer interface {
+	GetNodeVersion(context.Context, *GetNodeVersionRequest) (*GetNodeVersionResponse, error)
+	GetNodeID(context.Context, *GetNodeIDRequest) (*GetNodeIDResponse, error)
+	GetNodeIP(context.Context, *GetNodeIPRequest) (*GetNodeIPResponse, error)
+	GetNetworkID(context.Context, *GetNetworkIDRequest) (*GetNetworkIDResponse, error)
+	GetNetworkName(context.Context, *GetNetworkNameRequest) (*GetNetworkNameResponse, error)
+	GetBlockchainID(context.Context, *GetBlockchainIDRequest) (*GetBlockchainIDResponse, error)
+	IsBootstrapped(context.Context, *IsBootstrappedRequest) (*IsBootstrappedResponse, error)
+	Peers(context.Context, *PeersRequest) (*PeersResponse, error)
+	Uptime(context.Context, *UptimeRequest) (*UptimeResponse, error)
+	GetTxFee(context.Context, *GetTxFeeRequest) (*GetTxFeeResponse, error)
+	mustEmbedUnimplementedInfoServer()
+}
+
+// UnimplementedInfoServer must be embedded to have forward compatible implementations.
+type UnimplementedInfoServer struct {
+}
+
+func (UnimplementedInfoServer) GetNodeVersion(context.Context, *GetNodeVersionRequest) (*GetNodeVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeVersion not implemented")
+}
+func (UnimplementedInfoServer) GetNodeID(context.Context, *GetNodeIDRequest) (*GetNodeIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeID not implemented")
+}
+func (UnimplementedInfoServer) GetNodeIP(context.Context, *GetNodeIPRequest) (*GetNodeIPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeIP not implemented")
+}
+func (UnimplementedInfoServer) GetNetworkID(context.Context, *GetNetworkIDRequest) (*GetNetworkIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkID not implemented")
+}
+func (UnimplementedInfoServer) GetNetworkName(context.Context, *GetNetworkNameRequest) (*GetNetworkNameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkName not implemented")
+}
+func (UnimplementedInfoServer) GetBlockchainID(context.Context, *GetBlockchainIDRequest) (*GetBlockchainIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlockchainID not implemented")
+}
+func (UnimplementedInfoServer) IsBootstrapped(context.Context, *IsBootstrappedRequest) (*IsBootstrappedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IsBootstrapped not implemented")
+}
+func (UnimplementedInfoServer) Peers(context.Context, *PeersRequest) (*PeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Peers not implemented")
+}
+func (UnimplementedInfoServer) Uptime(context.Context, *UptimeRequest) (*UptimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Uptime not implemented")
+}
+func (UnimplementedInfoServer) GetTxFee(context.Context, *GetTxFeeRequest) (*GetTxFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTxFee not implemented")
+}
+func (UnimplementedInfoServer) mustEmbedUnimplementedInfoServer() {}
+
+// UnsafeInfoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InfoServer will
+// result in compilation errors.
+type UnsafeInfoServer interface {
+	mustEmbedUnimplementedInfoServer()
+}
+
+func RegisterInfoServer(s grpc.ServiceRegistrar, srv InfoServer) {
+	s.RegisterService(&Info_ServiceDesc, srv)
+}
+
+func _Info_GetNodeVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).GetNodeVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/GetNodeVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).GetNodeVersion(ctx, req.(*GetNodeVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_GetNodeID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).GetNodeID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/GetNodeID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).GetNodeID(ctx, req.(*GetNodeIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_GetNodeIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).GetNodeIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/GetNodeIP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).GetNodeIP(ctx, req.(*GetNodeIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_GetNetworkID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNetworkIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).GetNetworkID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/GetNetworkID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).GetNetworkID(ctx, req.(*GetNetworkIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_GetNetworkName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNetworkNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).GetNetworkName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/GetNetworkName",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).GetNetworkName(ctx, req.(*GetNetworkNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_GetBlockchainID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockchainIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).GetBlockchainID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/GetBlockchainID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).GetBlockchainID(ctx, req.(*GetBlockchainIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_IsBootstrapped_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IsBootstrappedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).IsBootstrapped(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/IsBootstrapped",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).IsBootstrapped(ctx, req.(*IsBootstrappedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_Peers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).Peers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/Peers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).Peers(ctx, req.(*PeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_Uptime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UptimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).Uptime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/Uptime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).Uptime(ctx, req.(*UptimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Info_GetTxFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTxFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InfoServer).GetTxFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/info.v1.Info/GetTxFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InfoServer).GetTxFee(ctx, req.(*GetTxFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Info_ServiceDesc is the grpc.ServiceDesc for Info service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Info_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "info.v1.Info",
+	HandlerType: (*InfoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetNodeVersion",
+			Handler:    _Info_GetNodeVersion_Handler,
+		},
+		{
+			MethodName: "GetNodeID",
+			Handler:    _Info_GetNodeID_Handler,
+		},
+		{
+			MethodName: "GetNodeIP",
+			Handler:    _Info_GetNodeIP_Handler,
+		},
+		{
+			MethodName: "GetNetworkID",
+			Handler:    _Info_GetNetworkID_Handler,
+		},
+		{
+			MethodName: "GetNetworkName",
+			Handler:    _Info_GetNetworkName_Handler,
+		},
+		{
+			MethodName: "GetBlockchainID",
+			Handler:    _Info_GetBlockchainID_Handler,
+		},
+		{
+			MethodName: "IsBootstrapped",
+			Handler:    _Info_IsBootstrapped_Handler,
+		},
+		{
+			MethodName: "Peers",
+			Handler:    _Info_Peers_Handler,
+		},
+		{
+			MethodName: "Uptime",
+			Handler:    _Info_Uptime_Handler,
+		},
+		{
+			MethodName: "GetTxFee",
+			Handler:    _Info_GetTxFee_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "info/v1/info.proto",
+}
@@ -0,0 +1,297 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+// A snapshot file is laid out as:
+//
+//	magic           [8]byte
+//	formatVersion   uint16
+//	metadataLength  uint32
+//	metadata        [metadataLength]byte (JSON encoded Metadata)
+//	records         (recordKeyValue, uvarint key length, key, uvarint value length, value)*
+//	recordEnd       byte
+//	numKeys         uint64
+//	checksum        [32]byte (sha256 of all preceding bytes)
+const (
+	formatVersion uint16 = 0
+
+	recordEnd      byte = 0x00
+	recordKeyValue byte = 0x01
+
+	// maxMetadataLength is the maximum number of bytes of metadata that will
+	// be read from a snapshot.
+	maxMetadataLength = units.MiB
+	// maxEntryLength is the maximum number of bytes of a key or value that
+	// will be read from a snapshot.
+	maxEntryLength = 256 * units.MiB
+
+	// importBatchSize is the number of bytes that are accumulated before a
+	// batch is written during an import.
+	importBatchSize = 4 * units.MiB
+)
+
+var (
+	magic = [8]byte{'C', 'A', 'M', 'S', 'N', 'A', 'P', 0x00}
+
+	errInvalidMagic         = errors.New("not a snapshot file")
+	errUnknownFormatVersion = errors.New("unknown snapshot format version")
+	errMetadataTooLarge     = errors.New("snapshot metadata too large")
+	errEntryTooLarge        = errors.New("snapshot entry too large")
+	errUnknownRecordType    = errors.New("unknown snapshot record type")
+	errInvalidChecksum      = errors.New("invalid snapshot checksum")
+	errInvalidNumKeys       = errors.New("invalid number of keys in snapshot")
+)
+
+// ChainHead is the last accepted block of a chain at the time a snapshot was
+// taken.
+type ChainHead struct {
+	ChainID      ids.ID `json:"chainID"`
+	LastAccepted ids.ID `json:"lastAccepted"`
+	Height       uint64 `json:"height"`
+}
+
+// Metadata describes the state that is contained in a snapshot.
+type Metadata struct {
+	NetworkID       uint32      `json:"networkID"`
+	DatabaseVersion string      `json:"databaseVersion"`
+	Timestamp       time.Time   `json:"timestamp"`
+	Chains          []ChainHead `json:"chains"`
+}
+
+// Summary describes a written or read snapshot.
+type Summary struct {
+	Metadata
+	NumKeys  uint64 `json:"numKeys"`
+	Checksum ids.ID `json:"checksum"`
+}
+
+// Write writes the [metadata] followed by all key-value pairs of [it] to
+// [w]. Keys for which [skip] returns true aren't written.
+func Write(w io.Writer, metadata Metadata, it database.Iterator, skip func(key []byte) bool) (*Summary, error) {
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	bufWriter := bufio.NewWriter(w)
+	hasher := sha256.New()
+	hw := io.MultiWriter(bufWriter, hasher)
+
+	header := make([]byte, len(magic)+2+4)
+	copy(header, magic[:])
+	binary.BigEndian.PutUint16(header[len(magic):], formatVersion)
+	binary.BigEndian.PutUint32(header[len(magic)+2:], uint32(len(metadataBytes)))
+	if _, err := hw.Write(header); err != nil {
+		return nil, err
+	}
+	if _, err := hw.Write(metadataBytes); err != nil {
+		return nil, err
+	}
+
+	var (
+		numKeys uint64
+		lenBuf  [binary.MaxVarintLen64]byte
+	)
+	for it.Next() {
+		key := it.Key()
+		if skip != nil && skip(key) {
+			continue
+		}
+		value := it.Value()
+
+		if _, err := hw.Write([]byte{recordKeyValue}); err != nil {
+			return nil, err
+		}
+		n := binary.PutUvarint(lenBuf[:], uint64(len(key)))
+		if _, err := hw.Write(lenBuf[:n]); err != nil {
+			return nil, err
+		}
+		if _, err := hw.Write(key); err != nil {
+			return nil, err
+		}
+		n = binary.PutUvarint(lenBuf[:], uint64(len(value)))
+		if _, err := hw.Write(lenBuf[:n]); err != nil {
+			return nil, err
+		}
+		if _, err := hw.Write(value); err != nil {
+			return nil, err
+		}
+		numKeys++
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	trailer := make([]byte, 1+8)
+	trailer[0] = recordEnd
+	binary.BigEndian.PutUint64(trailer[1:], numKeys)
+	if _, err := hw.Write(trailer); err != nil {
+		return nil, err
+	}
+
+	checksum := ids.ID{}
+	copy(checksum[:], hasher.Sum(nil))
+	if _, err := bufWriter.Write(checksum[:]); err != nil {
+		return nil, err
+	}
+	if err := bufWriter.Flush(); err != nil {
+		return nil, err
+	}
+
+	return &Summary{
+		Metadata: metadata,
+		NumKeys:  numKeys,
+		Checksum: checksum,
+	}, nil
+}
+
+// Read reads a snapshot from [r] and writes all of its key-value pairs into
+// [db]. If [db] is nil, the snapshot is only verified.
+//
+// The checksum is only known once the whole snapshot was read, so if an error
+// is returned, [db] may contain a partial snapshot.
+func Read(r io.Reader, db database.Batcher) (*Summary, error) {
+	hasher := sha256.New()
+	sr := &hashingReader{
+		r:      bufio.NewReader(r),
+		hasher: hasher,
+	}
+
+	header := make([]byte, len(magic)+2+4)
+	if _, err := io.ReadFull(sr, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(magic)], magic[:]) {
+		return nil, errInvalidMagic
+	}
+	if version := binary.BigEndian.Uint16(header[len(magic):]); version != formatVersion {
+		return nil, fmt.Errorf("%w: %d", errUnknownFormatVersion, version)
+	}
+	metadataLen := binary.BigEndian.Uint32(header[len(magic)+2:])
+	if metadataLen > maxMetadataLength {
+		return nil, fmt.Errorf("%w: %d bytes", errMetadataTooLarge, metadataLen)
+	}
+	metadataBytes := make([]byte, metadataLen)
+	if _, err := io.ReadFull(sr, metadataBytes); err != nil {
+		return nil, err
+	}
+	summary := &Summary{}
+	if err := json.Unmarshal(metadataBytes, &summary.Metadata); err != nil {
+		return nil, fmt.Errorf("couldn't parse snapshot metadata: %w", err)
+	}
+
+	var batch database.Batch
+	if db != nil {
+		batch = db.NewBatch()
+	}
+	for {
+		recordType, err := sr.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if recordType == recordEnd {
+			break
+		}
+		if recordType != recordKeyValue {
+			return nil, fmt.Errorf("%w: %d", errUnknownRecordType, recordType)
+		}
+
+		key, err := readEntry(sr)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readEntry(sr)
+		if err != nil {
+			return nil, err
+		}
+		summary.NumKeys++
+
+		if batch == nil {
+			continue
+		}
+		if err := batch.Put(key, value); err != nil {
+			return nil, err
+		}
+		if batch.Size() >= importBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, err
+			}
+			batch.Reset()
+		}
+	}
+
+	numKeysBytes := make([]byte, 8)
+	if _, err := io.ReadFull(sr, numKeysBytes); err != nil {
+		return nil, err
+	}
+	if numKeys := binary.BigEndian.Uint64(numKeysBytes); numKeys != summary.NumKeys {
+		return nil, fmt.Errorf("%w: expected %d but read %d", errInvalidNumKeys, numKeys, summary.NumKeys)
+	}
+
+	copy(summary.Checksum[:], hasher.Sum(nil))
+	var expectedChecksum ids.ID
+	if _, err := io.ReadFull(sr.r, expectedChecksum[:]); err != nil {
+		return nil, err
+	}
+	if expectedChecksum != summary.Checksum {
+		return nil, fmt.Errorf("%w: expected %s but computed %s", errInvalidChecksum, expectedChecksum, summary.Checksum)
+	}
+
+	if batch != nil {
+		if err := batch.Write(); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+func readEntry(r *hashingReader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxEntryLength {
+		return nil, fmt.Errorf("%w: %d bytes", errEntryTooLarge, length)
+	}
+	entry := make([]byte, length)
+	_, err = io.ReadFull(r, entry)
+	return entry, err
+}
+
+// hashingReader adds all read bytes to [hasher].
+type hashingReader struct {
+	r      *bufio.Reader
+	hasher hash.Hash
+}
+
+func (h *hashingReader) Read(p []byte) (int, error) {
+	n, err := h.r.Read(p)
+	_, _ = h.hasher.Write(p[:n])
+	return n, err
+}
+
+func (h *hashingReader) ReadByte() (byte, error) {
+	b, err := h.r.ReadByte()
+	if err == nil {
+		_, _ = h.hasher.Write([]byte{b})
+	}
+	return b, err
+}
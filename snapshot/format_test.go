@@ -0,0 +1,154 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func newTestSnapshot(t *testing.T, numKeys int) (*memdb.Database, Metadata, []byte) {
+	require := require.New(t)
+
+	db := memdb.New()
+	for i := 0; i < numKeys; i++ {
+		require.NoError(db.Put([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))))
+	}
+	metadata := Metadata{
+		NetworkID:       12345,
+		DatabaseVersion: "v1.4.5",
+		Timestamp:       time.Unix(1000, 0).UTC(),
+		Chains: []ChainHead{
+			{
+				ChainID:      ids.GenerateTestID(),
+				LastAccepted: ids.GenerateTestID(),
+				Height:       10,
+			},
+		},
+	}
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	buf := &bytes.Buffer{}
+	summary, err := Write(buf, metadata, it, nil)
+	require.NoError(err)
+	require.Equal(uint64(numKeys), summary.NumKeys)
+	require.Equal(metadata, summary.Metadata)
+	return db, metadata, buf.Bytes()
+}
+
+func TestWriteRead(t *testing.T) {
+	require := require.New(t)
+
+	srcDB, metadata, snapshotBytes := newTestSnapshot(t, 100)
+
+	dstDB := memdb.New()
+	summary, err := Read(bytes.NewReader(snapshotBytes), dstDB)
+	require.NoError(err)
+	require.Equal(metadata, summary.Metadata)
+	require.Equal(uint64(100), summary.NumKeys)
+
+	it := srcDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		value, err := dstDB.Get(it.Key())
+		require.NoError(err)
+		require.Equal(it.Value(), value)
+	}
+	require.NoError(it.Error())
+
+	count, err := database.Count(dstDB)
+	require.NoError(err)
+	require.Equal(100, count)
+}
+
+func TestWriteSkip(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	require.NoError(db.Put([]byte("keep"), []byte("value")))
+	require.NoError(db.Put([]byte("skip"), []byte("value")))
+
+	it := db.NewIterator()
+	defer it.Release()
+
+	buf := &bytes.Buffer{}
+	summary, err := Write(buf, Metadata{}, it, func(key []byte) bool {
+		return bytes.Equal(key, []byte("skip"))
+	})
+	require.NoError(err)
+	require.Equal(uint64(1), summary.NumKeys)
+
+	dstDB := memdb.New()
+	_, err = Read(buf, dstDB)
+	require.NoError(err)
+
+	has, err := dstDB.Has([]byte("keep"))
+	require.NoError(err)
+	require.True(has)
+	has, err = dstDB.Has([]byte("skip"))
+	require.NoError(err)
+	require.False(has)
+}
+
+func TestReadInvalid(t *testing.T) {
+	_, _, snapshotBytes := newTestSnapshot(t, 10)
+
+	tests := []struct {
+		name        string
+		modify      func([]byte) []byte
+		expectedErr error
+	}{
+		{
+			name: "invalid magic",
+			modify: func(b []byte) []byte {
+				b[0]++
+				return b
+			},
+			expectedErr: errInvalidMagic,
+		},
+		{
+			name: "unknown version",
+			modify: func(b []byte) []byte {
+				b[len(magic)+1]++
+				return b
+			},
+			expectedErr: errUnknownFormatVersion,
+		},
+		{
+			name: "corrupted value",
+			modify: func(b []byte) []byte {
+				b[len(b)-64]++
+				return b
+			},
+			expectedErr: errInvalidChecksum,
+		},
+		{
+			name: "corrupted checksum",
+			modify: func(b []byte) []byte {
+				b[len(b)-1]++
+				return b
+			},
+			expectedErr: errInvalidChecksum,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := test.modify(slices.Clone(snapshotBytes))
+			_, err := Read(bytes.NewReader(b), nil)
+			require.ErrorIs(t, err, test.expectedErr)
+		})
+	}
+}
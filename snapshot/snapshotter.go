@@ -0,0 +1,557 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/ava-labs/avalanchego/chains"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/perms"
+)
+
+const tmpSuffix = ".tmp"
+
+var (
+	// DBPrefix is the prefix of the node's database under which the
+	// snapshotter keeps its state.
+	DBPrefix = []byte("snapshot")
+
+	// pendingKey maps to the metadata of a restored snapshot whose chain
+	// heads haven't all been verified yet.
+	pendingKey = []byte("pending")
+	// restoringKey is set while a snapshot is written into the database. The
+	// snapshot is only verified once it was read completely, so the database
+	// can't be used, if the restore didn't finish.
+	restoringKey = []byte("restoring")
+
+	errDatabaseNotEmpty  = errors.New("database is not empty")
+	errRestoreIncomplete = errors.New("database contains an incomplete snapshot restore")
+	errFileExists        = errors.New("snapshot file already exists")
+	errHeadMismatch      = errors.New("last accepted block doesn't match snapshot")
+	errWrongNetwork      = errors.New("snapshot is from a different network")
+	errUnknownChain      = errors.New("unknown linear chain")
+	errHeightAccepted    = errors.New("chain already accepted a block above the height")
+	errExportPending     = errors.New("snapshot of chain is already pending")
+	errPauseUnsupported  = errors.New("chains can't be paused for snapshots")
+
+	_ Snapshotter   = (*snapshotter)(nil)
+	_ snow.Acceptor = (*snapshotter)(nil)
+)
+
+// Snapshotter exports consistent snapshots of the node's database and
+// verifies that chains restored from a snapshot start from the snapshotted
+// state. Snapshots are restored offline, into the empty database of a stopped
+// node, with Restore.
+//
+// Snapshotter is thread-safe.
+type Snapshotter interface {
+	chains.VerifyingRegistrant
+
+	// Export writes a snapshot of the node's database to a new file at [path].
+	// The node keeps running while the snapshot is written. If [chainID] isn't
+	// empty, the snapshot is taken once the linear chain [chainID] accepted
+	// the block at [height], and the chain doesn't accept further blocks until
+	// the snapshot was taken. Fails, if the chain already accepted a block
+	// above [height].
+	Export(ctx context.Context, path string, chainID ids.ID, height uint64) (*Summary, error)
+}
+
+// chainVM is the part of a linear chain's VM that is used to determine the
+// last accepted block of the chain.
+type chainVM interface {
+	LastAccepted(context.Context) (ids.ID, error)
+	GetBlock(context.Context, ids.ID) (snowman.Block, error)
+}
+
+type Config struct {
+	// DB is the node's database that snapshots are taken of.
+	DB database.Database
+	// DBPrefix is the prefix of [DB] under which the snapshotter keeps its own
+	// state. Keys under this prefix are excluded from snapshots.
+	DBPrefix        []byte
+	NetworkID       uint32
+	DatabaseVersion string
+	Log             logging.Logger
+	// Accepted blocks are passed to the snapshotter to pause chains at the
+	// height of a snapshot. Can be nil, if snapshots are only exported at the
+	// current height.
+	BlockAcceptorGroup snow.AcceptorGroup
+}
+
+type chain struct {
+	name string
+	ctx  *snow.ConsensusContext
+	vm   chainVM
+}
+
+// pause stops a chain at [height], until a snapshot was taken.
+type pause struct {
+	chain  *chain
+	height uint64
+	// Closed, once the chain is paused at [height]
+	reached     chan struct{}
+	reachedOnce sync.Once
+	// Closed, once the chain can accept blocks again
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+func (p *pause) reach() {
+	p.reachedOnce.Do(func() {
+		close(p.reached)
+	})
+}
+
+func (p *pause) resume() {
+	p.doneOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+type snapshotter struct {
+	lock sync.Mutex
+
+	log             logging.Logger
+	db              database.Database
+	stateDB         database.Database
+	excludedPrefix  []byte
+	networkID       uint32
+	databaseVersion string
+	acceptorGroup   snow.AcceptorGroup
+
+	// Chain ID --> linear chain that can be snapshotted
+	chains map[ids.ID]*chain
+
+	pauseLock sync.Mutex
+	// Chain ID --> pause of the chain for a pending snapshot
+	pauses map[ids.ID]*pause
+	// Metadata of a restored snapshot. Nil if there is nothing to verify.
+	pending *Metadata
+	// Chain ID --> head that must match the chain once it is registered
+	pendingHeads map[ids.ID]ChainHead
+}
+
+// New returns a new Snapshotter. If the database was restored from a snapshot
+// that wasn't verified yet, the chain heads of the snapshot are verified as
+// the chains are registered.
+func New(config Config) (Snapshotter, error) {
+	s := &snapshotter{
+		log:             config.Log,
+		db:              config.DB,
+		stateDB:         prefixdb.New(config.DBPrefix, config.DB),
+		excludedPrefix:  prefixdb.MakePrefix(config.DBPrefix),
+		networkID:       config.NetworkID,
+		databaseVersion: config.DatabaseVersion,
+		acceptorGroup:   config.BlockAcceptorGroup,
+		chains:          make(map[ids.ID]*chain),
+		pauses:          make(map[ids.ID]*pause),
+		pendingHeads:    make(map[ids.ID]ChainHead),
+	}
+
+	isRestoring, err := s.stateDB.Has(restoringKey)
+	if err != nil {
+		return nil, err
+	}
+	if isRestoring {
+		return nil, fmt.Errorf("%w: the database must be deleted before restoring the snapshot again", errRestoreIncomplete)
+	}
+
+	pendingBytes, err := s.stateDB.Get(pendingKey)
+	switch {
+	case err == database.ErrNotFound:
+		return s, nil
+	case err != nil:
+		return nil, err
+	}
+
+	s.pending = &Metadata{}
+	if err := json.Unmarshal(pendingBytes, s.pending); err != nil {
+		return nil, fmt.Errorf("couldn't parse pending snapshot metadata: %w", err)
+	}
+	if s.pending.NetworkID != s.networkID {
+		return nil, fmt.Errorf("%w: expected network %d but snapshot is from network %d",
+			errWrongNetwork,
+			s.networkID,
+			s.pending.NetworkID,
+		)
+	}
+	for _, head := range s.pending.Chains {
+		s.pendingHeads[head.ChainID] = head
+	}
+	s.log.Info("verifying restored snapshot",
+		zap.Time("timestamp", s.pending.Timestamp),
+		zap.Int("numChains", len(s.pendingHeads)),
+	)
+	return s, nil
+}
+
+// Assumes [ctx.Lock] is not held
+func (s *snapshotter) RegisterChain(chainName string, ctx *snow.ConsensusContext, vm common.VM) {
+	linearVM, ok := vm.(chainVM)
+	if !ok {
+		s.log.Debug("not registering chain to snapshotter",
+			zap.String("reason", "not a linear chain"),
+			zap.String("chainName", chainName),
+		)
+		return
+	}
+
+	s.lock.Lock()
+	s.chains[ctx.ChainID] = &chain{
+		name: chainName,
+		ctx:  ctx,
+		vm:   linearVM,
+	}
+	s.lock.Unlock()
+
+	if s.acceptorGroup == nil {
+		return
+	}
+	if err := s.acceptorGroup.RegisterAcceptor(ctx.ChainID, "snapshotter", s, false); err != nil {
+		s.log.Error("couldn't register snapshotter as block acceptor",
+			zap.String("chainName", chainName),
+			zap.Error(err),
+		)
+	}
+}
+
+// VerifyChain verifies that the last accepted block of [vm] matches the
+// snapshot, that the database was restored from. Chains, that aren't part of
+// the snapshot, aren't verified.
+// Assumes [ctx.Lock] is not held
+func (s *snapshotter) VerifyChain(chainName string, ctx *snow.ConsensusContext, vm common.VM) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	expectedHead, ok := s.pendingHeads[ctx.ChainID]
+	if !ok {
+		return nil
+	}
+	linearVM, ok := vm.(chainVM)
+	if !ok {
+		return fmt.Errorf("%w: %s", errUnknownChain, chainName)
+	}
+
+	ctx.Lock.Lock()
+	head, err := getHead(ctx, linearVM)
+	ctx.Lock.Unlock()
+	if err != nil {
+		return err
+	}
+	if head != expectedHead {
+		return fmt.Errorf("%w: expected %s at height %d but got %s at height %d",
+			errHeadMismatch,
+			expectedHead.LastAccepted,
+			expectedHead.Height,
+			head.LastAccepted,
+			head.Height,
+		)
+	}
+
+	s.log.Info("verified restored chain",
+		zap.String("chainName", chainName),
+		zap.Stringer("lastAccepted", head.LastAccepted),
+		zap.Uint64("height", head.Height),
+	)
+	delete(s.pendingHeads, ctx.ChainID)
+	if len(s.pendingHeads) > 0 {
+		return nil
+	}
+
+	s.pending = nil
+	if err := s.stateDB.Delete(pendingKey); err != nil {
+		s.log.Error("couldn't mark restored snapshot as verified",
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// Accept pauses the chain of [ctx], if a snapshot is pending at the height of
+// its last accepted block. While paused, [ctx.Lock] is released, so that the
+// snapshot can lock the chains in order. The chain can't accept blocks while
+// paused, as [containerID] is accepted after Accept returned.
+// Assumes [ctx.Lock] is held
+func (s *snapshotter) Accept(ctx *snow.ConsensusContext, _ ids.ID, _ []byte) error {
+	s.pauseLock.Lock()
+	p, ok := s.pauses[ctx.ChainID]
+	if !ok {
+		s.pauseLock.Unlock()
+		return nil
+	}
+	head, err := getHead(ctx, p.chain.vm)
+	if err != nil || head.Height != p.height {
+		s.pauseLock.Unlock()
+		return err
+	}
+	delete(s.pauses, ctx.ChainID)
+	s.pauseLock.Unlock()
+
+	p.reach()
+	ctx.Lock.Unlock()
+	<-p.done
+	ctx.Lock.Lock()
+	return nil
+}
+
+func (s *snapshotter) Export(ctx context.Context, path string, chainID ids.ID, height uint64) (*Summary, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("%w: %s", errFileExists, path)
+	}
+
+	var p *pause
+	if chainID != ids.Empty {
+		var err error
+		p, err = s.pauseAt(chainID, height)
+		if err != nil {
+			return nil, err
+		}
+		defer s.resume(p)
+
+		select {
+		case <-p.reached:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tmpPath := path + tmpSuffix
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perms.ReadWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.export(ctx, f, p)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, err
+	}
+
+	s.log.Info("exported snapshot",
+		zap.String("path", path),
+		zap.Uint64("numKeys", summary.NumKeys),
+		zap.Stringer("checksum", summary.Checksum),
+	)
+	return summary, nil
+}
+
+// pauseAt pauses the chain [chainID] once its last accepted block is at
+// [height]. If the last accepted block is already at [height], the returned
+// pause is reached.
+func (s *snapshotter) pauseAt(chainID ids.ID, height uint64) (*pause, error) {
+	s.lock.Lock()
+	chain, ok := s.chains[chainID]
+	s.lock.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownChain, chainID)
+	}
+	if s.acceptorGroup == nil {
+		return nil, errPauseUnsupported
+	}
+
+	chain.ctx.Lock.Lock()
+	defer chain.ctx.Lock.Unlock()
+
+	head, err := getHead(chain.ctx, chain.vm)
+	if err != nil {
+		return nil, err
+	}
+	if head.Height > height {
+		return nil, fmt.Errorf("%w: %s is at height %d", errHeightAccepted, chain.name, head.Height)
+	}
+
+	p := &pause{
+		chain:   chain,
+		height:  height,
+		reached: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if head.Height == height {
+		// The chain can't accept further blocks before the pause is added, as
+		// [chain.ctx.Lock] is held.
+		p.reach()
+	}
+
+	s.pauseLock.Lock()
+	defer s.pauseLock.Unlock()
+
+	if _, ok := s.pauses[chainID]; ok {
+		return nil, fmt.Errorf("%w: %s", errExportPending, chain.name)
+	}
+	s.pauses[chainID] = p
+	return p, nil
+}
+
+// resume lets the chain of [p] accept blocks again. Can be called multiple
+// times.
+func (s *snapshotter) resume(p *pause) {
+	s.pauseLock.Lock()
+	if s.pauses[p.chain.ctx.ChainID] == p {
+		delete(s.pauses, p.chain.ctx.ChainID)
+	}
+	s.pauseLock.Unlock()
+	p.resume()
+}
+
+// export writes a snapshot to [w]. The chain of [p] is resumed, once the
+// snapshot was taken. [p] can be nil.
+//
+// Assumes [s.lock] is held.
+func (s *snapshotter) export(ctx context.Context, w io.Writer, p *pause) (*Summary, error) {
+	metadata := Metadata{
+		NetworkID:       s.networkID,
+		DatabaseVersion: s.databaseVersion,
+		Timestamp:       time.Now().UTC(),
+	}
+
+	// Database iterators provide a point-in-time view of the database. While
+	// every chain is locked, no blocks can be accepted, so the iterator
+	// matches the recorded chain heads. The P-chain is locked last because
+	// other chains access the P-chain state while holding their own lock.
+	chainIDs := maps.Keys(s.chains)
+	utils.Sort(chainIDs)
+	for i, chainID := range chainIDs {
+		if chainID == constants.PlatformChainID {
+			chainIDs = append(chainIDs[:i], chainIDs[i+1:]...)
+			chainIDs = append(chainIDs, constants.PlatformChainID)
+			break
+		}
+	}
+
+	for _, chainID := range chainIDs {
+		s.chains[chainID].ctx.Lock.Lock()
+	}
+	var (
+		it  database.Iterator
+		err error
+	)
+	for _, chainID := range chainIDs {
+		chain := s.chains[chainID]
+		var head ChainHead
+		head, err = getHead(chain.ctx, chain.vm)
+		if err != nil {
+			err = fmt.Errorf("couldn't get last accepted block of %s: %w", chain.name, err)
+			break
+		}
+		metadata.Chains = append(metadata.Chains, head)
+	}
+	if err == nil {
+		it = s.db.NewIterator()
+	}
+	for i := len(chainIDs) - 1; i >= 0; i-- {
+		s.chains[chainIDs[i]].ctx.Lock.Unlock()
+	}
+	if p != nil {
+		s.resume(p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	return Write(w, metadata, &contextIterator{Iterator: it, ctx: ctx}, func(key []byte) bool {
+		return bytes.HasPrefix(key, s.excludedPrefix)
+	})
+}
+
+// Restore writes the snapshot read from [r] into the empty database [db] and
+// marks its chain heads to be verified by the Snapshotter created with
+// [dbPrefix] on the next start of the node. Until the snapshot was read and
+// verified completely, [db] is marked as incomplete, so that the Snapshotter
+// refuses to start on a partial snapshot.
+func Restore(r io.Reader, db database.Database, dbPrefix []byte) (*Summary, error) {
+	isEmpty, err := database.IsEmpty(db)
+	if err != nil {
+		return nil, err
+	}
+	if !isEmpty {
+		return nil, errDatabaseNotEmpty
+	}
+
+	stateDB := prefixdb.New(dbPrefix, db)
+	if err := stateDB.Put(restoringKey, nil); err != nil {
+		return nil, err
+	}
+	summary, err := Read(r, db)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataBytes, err := json.Marshal(summary.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	if err := stateDB.Put(pendingKey, metadataBytes); err != nil {
+		return nil, err
+	}
+	return summary, stateDB.Delete(restoringKey)
+}
+
+// Assumes [ctx.Lock] is held
+func getHead(ctx *snow.ConsensusContext, vm chainVM) (ChainHead, error) {
+	lastAcceptedID, err := vm.LastAccepted(context.TODO())
+	if err != nil {
+		return ChainHead{}, err
+	}
+	lastAccepted, err := vm.GetBlock(context.TODO(), lastAcceptedID)
+	if err != nil {
+		return ChainHead{}, err
+	}
+	return ChainHead{
+		ChainID:      ctx.ChainID,
+		LastAccepted: lastAcceptedID,
+		Height:       lastAccepted.Height(),
+	}, nil
+}
+
+// contextIterator stops iterating once [ctx] is cancelled.
+type contextIterator struct {
+	database.Iterator
+	ctx context.Context
+	err error
+}
+
+func (it *contextIterator) Next() bool {
+	if it.err == nil {
+		it.err = it.ctx.Err()
+	}
+	return it.err == nil && it.Iterator.Next()
+}
+
+func (it *contextIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.Iterator.Error()
+}
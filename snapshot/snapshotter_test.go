@@ -0,0 +1,332 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const testNetworkID = 12345
+
+func newTestChain(lastAccepted ids.ID, height uint64) (*snow.ConsensusContext, *block.TestVM) {
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     lastAccepted,
+			StatusV: choices.Accepted,
+		},
+		HeightV: height,
+	}
+	vm := &block.TestVM{
+		LastAcceptedF: func(context.Context) (ids.ID, error) {
+			return blk.ID(), nil
+		},
+		GetBlockF: func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+			if blkID != blk.ID() {
+				return nil, database.ErrNotFound
+			}
+			return blk, nil
+		},
+	}
+	return ctx, vm
+}
+
+func TestExportRestore(t *testing.T) {
+	require := require.New(t)
+
+	srcDB := memdb.New()
+	require.NoError(srcDB.Put([]byte("key"), []byte("value")))
+
+	src, err := New(Config{
+		DB:        srcDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID,
+		Log:       logging.NoLog{},
+	})
+	require.NoError(err)
+
+	lastAccepted := ids.GenerateTestID()
+	ctx, vm := newTestChain(lastAccepted, 7)
+	src.RegisterChain("test", ctx, vm)
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	summary, err := src.Export(context.Background(), path, ids.Empty, 0)
+	require.NoError(err)
+	require.Equal(uint64(1), summary.NumKeys)
+	require.Equal([]ChainHead{{
+		ChainID:      ctx.ChainID,
+		LastAccepted: lastAccepted,
+		Height:       7,
+	}}, summary.Chains)
+
+	// Exporting to an existing file must fail.
+	_, err = src.Export(context.Background(), path, ids.Empty, 0)
+	require.ErrorIs(err, errFileExists)
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+
+	dstDB := memdb.New()
+	restored, err := Restore(f, dstDB, DBPrefix)
+	require.NoError(err)
+	require.Equal(summary.Checksum, restored.Checksum)
+
+	value, err := dstDB.Get([]byte("key"))
+	require.NoError(err)
+	require.Equal([]byte("value"), value)
+
+	dst, err := New(Config{
+		DB:        dstDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID,
+		Log:       logging.NoLog{},
+	})
+	require.NoError(err)
+
+	// Verifying the restored chain clears the pending verification.
+	require.NoError(dst.VerifyChain("test", ctx, vm))
+	dst.RegisterChain("test", ctx, vm)
+
+	has, err := prefixdb.New(DBPrefix, dstDB).Has(pendingKey)
+	require.NoError(err)
+	require.False(has)
+
+	// The snapshotter's state must not be included in new snapshots.
+	count, err := database.Count(dstDB)
+	require.NoError(err)
+	require.Equal(1, count)
+}
+
+func TestRestoreMismatchingHead(t *testing.T) {
+	require := require.New(t)
+
+	srcDB := memdb.New()
+	src, err := New(Config{
+		DB:        srcDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID,
+		Log:       logging.NoLog{},
+	})
+	require.NoError(err)
+
+	ctx, vm := newTestChain(ids.GenerateTestID(), 7)
+	src.RegisterChain("test", ctx, vm)
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	_, err = src.Export(context.Background(), path, ids.Empty, 0)
+	require.NoError(err)
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+
+	dstDB := memdb.New()
+	_, err = Restore(f, dstDB, DBPrefix)
+	require.NoError(err)
+
+	dst, err := New(Config{
+		DB:        dstDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID,
+		Log:       logging.NoLog{},
+	})
+	require.NoError(err)
+
+	_, otherVM := newTestChain(ids.GenerateTestID(), 7)
+	err = dst.VerifyChain("test", ctx, otherVM)
+	require.ErrorIs(err, errHeadMismatch)
+
+	// The chain stays pending, until it is verified.
+	has, err := prefixdb.New(DBPrefix, dstDB).Has(pendingKey)
+	require.NoError(err)
+	require.True(has)
+}
+
+func TestExportAtHeight(t *testing.T) {
+	require := require.New(t)
+
+	acceptorGroup := snow.NewAcceptorGroup(logging.NoLog{})
+	s, err := New(Config{
+		DB:                 memdb.New(),
+		DBPrefix:           DBPrefix,
+		NetworkID:          testNetworkID,
+		Log:                logging.NoLog{},
+		BlockAcceptorGroup: acceptorGroup,
+	})
+	require.NoError(err)
+
+	var (
+		ctx = snow.DefaultConsensusContextTest()
+		// Height --> accepted block
+		blks = map[uint64]*snowman.TestBlock{}
+		// Height of the last accepted block
+		height uint64
+	)
+	ctx.ChainID = ids.GenerateTestID()
+	vm := &block.TestVM{
+		LastAcceptedF: func(context.Context) (ids.ID, error) {
+			return blks[height].ID(), nil
+		},
+		GetBlockF: func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+			for _, blk := range blks {
+				if blk.ID() == blkID {
+					return blk, nil
+				}
+			}
+			return nil, database.ErrNotFound
+		},
+	}
+	accept := func() {
+		ctx.Lock.Lock()
+		defer ctx.Lock.Unlock()
+
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Accepted,
+			},
+			HeightV: height + 1,
+		}
+		require.NoError(acceptorGroup.Accept(ctx, blk.ID(), nil))
+		blks[blk.HeightV] = blk
+		height = blk.HeightV
+	}
+	accept()
+	s.RegisterChain("test", ctx, vm)
+
+	type result struct {
+		summary *Summary
+		err     error
+	}
+	exported := make(chan result, 1)
+	go func() {
+		summary, err := s.Export(context.Background(), filepath.Join(t.TempDir(), "snapshot"), ctx.ChainID, 2)
+		exported <- result{summary: summary, err: err}
+	}()
+	require.Eventually(func() bool {
+		s.(*snapshotter).pauseLock.Lock()
+		defer s.(*snapshotter).pauseLock.Unlock()
+		return len(s.(*snapshotter).pauses) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The chain is paused, when it accepts the block above the height of the
+	// snapshot, until the snapshot was taken.
+	accept()
+	accept()
+	res := <-exported
+	require.NoError(res.err)
+	require.Equal([]ChainHead{{
+		ChainID:      ctx.ChainID,
+		LastAccepted: blks[2].ID(),
+		Height:       2,
+	}}, res.summary.Chains)
+	require.Equal(uint64(3), height)
+
+	// Heights, that were already accepted, can't be snapshotted.
+	_, err = s.Export(context.Background(), filepath.Join(t.TempDir(), "snapshot"), ctx.ChainID, 2)
+	require.ErrorIs(err, errHeightAccepted)
+}
+
+func TestRestoreNonEmptyDatabase(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	require.NoError(db.Put([]byte("key"), []byte("value")))
+
+	_, err := Restore(nil, db, DBPrefix)
+	require.ErrorIs(err, errDatabaseNotEmpty)
+}
+
+func TestRestoreTruncatedSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	srcDB := memdb.New()
+	for i := 0; i < 16; i++ {
+		require.NoError(srcDB.Put([]byte{byte(i)}, []byte{byte(i)}))
+	}
+	src, err := New(Config{
+		DB:        srcDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID,
+		Log:       logging.NoLog{},
+	})
+	require.NoError(err)
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	_, err = src.Export(context.Background(), path, ids.Empty, 0)
+	require.NoError(err)
+	snapshotBytes, err := os.ReadFile(path)
+	require.NoError(err)
+
+	// The checksum is missing.
+	dstDB := memdb.New()
+	_, err = Restore(bytes.NewReader(snapshotBytes[:len(snapshotBytes)-len(ids.Empty)]), dstDB, DBPrefix)
+	require.ErrorIs(err, io.EOF)
+
+	// The node doesn't start on the partial snapshot.
+	_, err = New(Config{
+		DB:        dstDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID,
+		Log:       logging.NoLog{},
+	})
+	require.ErrorIs(err, errRestoreIncomplete)
+
+	_, err = Restore(bytes.NewReader(snapshotBytes), dstDB, DBPrefix)
+	require.ErrorIs(err, errDatabaseNotEmpty)
+}
+
+func TestNewWrongNetwork(t *testing.T) {
+	require := require.New(t)
+
+	srcDB := memdb.New()
+	src, err := New(Config{
+		DB:        srcDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID,
+		Log:       logging.NoLog{},
+	})
+	require.NoError(err)
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	_, err = src.Export(context.Background(), path, ids.Empty, 0)
+	require.NoError(err)
+
+	f, err := os.Open(path)
+	require.NoError(err)
+	defer f.Close()
+
+	dstDB := memdb.New()
+	_, err = Restore(f, dstDB, DBPrefix)
+	require.NoError(err)
+
+	_, err = New(Config{
+		DB:        dstDB,
+		DBPrefix:  DBPrefix,
+		NetworkID: testNetworkID + 1,
+		Log:       logging.NoLog{},
+	})
+	require.ErrorIs(err, errWrongNetwork)
+}
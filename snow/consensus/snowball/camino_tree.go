@@ -0,0 +1,65 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowball
+
+// Confidence returns the number of consecutive successful polls of the
+// preference, which is the lowest confidence of the snowball instances on the
+// path of the preference
+func (t *Tree) Confidence() int {
+	confidence := -1
+	reset := t.shouldReset
+	for n := t.node; n != nil; {
+		// A pending reset resets the confidence of the whole sub-tree
+		if reset {
+			return 0
+		}
+
+		var nodeConfidence int
+		switch typed := n.(type) {
+		case *unaryNode:
+			nodeConfidence = typed.snowball.(unaryConfidencer).Confidence()
+			reset = typed.shouldReset
+			n = typed.child
+		case *binaryNode:
+			bit := typed.snowball.Preference()
+			nodeConfidence = typed.snowball.(binaryConfidencer).Confidence(bit)
+			reset = typed.shouldReset[bit]
+			n = typed.children[bit]
+		default:
+			return 0
+		}
+		if confidence < 0 || nodeConfidence < confidence {
+			confidence = nodeConfidence
+		}
+	}
+	if confidence < 0 {
+		return 0
+	}
+	return confidence
+}
+
+// unaryConfidencer reports the number of consecutive successful polls of a
+// unary snowflake instance
+type unaryConfidencer interface {
+	Confidence() int
+}
+
+// binaryConfidencer reports the number of consecutive successful polls of a
+// choice of a binary snowflake instance
+type binaryConfidencer interface {
+	Confidence(choice int) int
+}
+
+func (sf *unarySnowflake) Confidence() int {
+	return sf.confidence
+}
+
+// Confidence returns the confidence of [choice], which is zero unless the last
+// successful poll was for [choice]
+func (sf *binarySnowflake) Confidence(choice int) int {
+	if sf.Preference() != choice {
+		return 0
+	}
+	return sf.confidence
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowball
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+func TestTreeConfidence(t *testing.T) {
+	require := require.New(t)
+
+	params := Parameters{
+		K: 1, Alpha: 1, BetaVirtuous: 5, BetaRogue: 5,
+	}
+	tree := Tree{}
+	tree.Initialize(params, Red)
+	require.Zero(tree.Confidence())
+
+	oneRed := bag.Bag[ids.ID]{}
+	oneRed.Add(Red)
+	require.True(tree.RecordPoll(oneRed))
+	require.True(tree.RecordPoll(oneRed))
+	require.Equal(2, tree.Confidence())
+
+	// The confidence of the binary instance is kept when the conflict is added
+	tree.Add(Blue)
+	require.Equal(2, tree.Confidence())
+
+	// An unsuccessful poll resets the confidence
+	tree.RecordUnsuccessfulPoll()
+	require.Zero(tree.Confidence())
+	require.True(tree.RecordPoll(oneRed))
+	require.Equal(1, tree.Confidence())
+
+	// A poll for the other choice resets the confidence of the preference
+	oneBlue := bag.Bag[ids.ID]{}
+	oneBlue.Add(Blue)
+	require.True(tree.RecordPoll(oneBlue))
+	require.Equal(Red, tree.Preference())
+	require.Zero(tree.Confidence())
+	require.True(tree.RecordPoll(oneBlue))
+	require.True(tree.RecordPoll(oneBlue))
+	require.Equal(Red, tree.Preference())
+	require.True(tree.RecordPoll(oneBlue))
+	require.Equal(Blue, tree.Preference())
+	require.Equal(4, tree.Confidence())
+
+	require.False(tree.RecordPoll(bag.Bag[ids.ID]{}))
+	require.Zero(tree.Confidence())
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+// BlockState is the consensus state of a block in the processing block tree
+type BlockState struct {
+	ID       ids.ID      `json:"id"`
+	ParentID ids.ID      `json:"parentID"`
+	Height   json.Uint64 `json:"height"`
+	// Accepted is only true for the last accepted block, which is the root of
+	// the tree
+	Accepted  bool `json:"accepted"`
+	Preferred bool `json:"preferred"`
+	// Confidence is the number of consecutive successful polls the block
+	// received, as tracked by the snowball instance of its parent. Only the
+	// preferred child of a block has confidence.
+	Confidence int `json:"confidence"`
+	// Snowball is the state of the snowball instance deciding between the
+	// children of the block. Empty if the block has no children.
+	Snowball string `json:"snowball,omitempty"`
+}
+
+// Blocks returns the last accepted block followed by the processing blocks,
+// ordered by height
+func (ts *Topological) Blocks() []BlockState {
+	states := make([]BlockState, 0, len(ts.blocks))
+	for blkID, n := range ts.blocks {
+		state := BlockState{
+			ID:        blkID,
+			Accepted:  n.Accepted(),
+			Preferred: blkID == ts.head || ts.preferredIDs.Contains(blkID),
+		}
+		if blkID == ts.head {
+			state.Height = json.Uint64(ts.height)
+		}
+		if n.blk != nil {
+			state.ParentID = n.blk.Parent()
+			state.Height = json.Uint64(n.blk.Height())
+			state.Confidence = ts.confidence(state.ParentID, blkID)
+		}
+		if n.sb != nil {
+			state.Snowball = n.sb.String()
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Height != states[j].Height {
+			return states[i].Height < states[j].Height
+		}
+		return states[i].ID.Hex() < states[j].ID.Hex()
+	})
+	return states
+}
+
+// confidence returns the confidence of [blkID] in the snowball instance of its
+// parent [parentID]
+func (ts *Topological) confidence(parentID ids.ID, blkID ids.ID) int {
+	parent, ok := ts.blocks[parentID]
+	if !ok || parent.sb == nil || parent.sb.Preference() != blkID {
+		return 0
+	}
+	tree, ok := parent.sb.(*snowball.Tree)
+	if !ok {
+		return 0
+	}
+	return tree.Confidence()
+}
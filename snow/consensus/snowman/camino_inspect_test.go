@@ -0,0 +1,93 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/utils/bag"
+	"github.com/ava-labs/avalanchego/utils/json"
+)
+
+func TestTopologicalBlocks(t *testing.T) {
+	require := require.New(t)
+
+	sm := &Topological{}
+	params := snowball.Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          3,
+		BetaRogue:             3,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	require.NoError(sm.Initialize(snow.DefaultConsensusContextTest(), params, GenesisID, GenesisHeight, GenesisTimestamp))
+
+	block1 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis.IDV,
+		HeightV: Genesis.HeightV + 1,
+	}
+	block2 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(2),
+			StatusV: choices.Processing,
+		},
+		ParentV: block1.IDV,
+		HeightV: block1.HeightV + 1,
+	}
+	require.NoError(sm.Add(context.Background(), block1))
+	require.NoError(sm.Add(context.Background(), block2))
+
+	confidences := func() []int {
+		blocks := sm.Blocks()
+		confidences := make([]int, len(blocks))
+		for i, blk := range blocks {
+			confidences[i] = blk.Confidence
+		}
+		return confidences
+	}
+
+	votes := bag.Bag[ids.ID]{}
+	votes.Add(block2.ID())
+	require.NoError(sm.RecordPoll(context.Background(), votes))
+	require.NoError(sm.RecordPoll(context.Background(), votes))
+	require.Equal([]int{0, 2, 2}, confidences())
+
+	// A failed poll resets the confidence once the next poll is applied
+	require.NoError(sm.RecordPoll(context.Background(), bag.Bag[ids.ID]{}))
+	require.Equal([]int{0, 2, 2}, confidences())
+	require.NoError(sm.RecordPoll(context.Background(), votes))
+	require.Equal([]int{0, 1, 1}, confidences())
+
+	blocks := sm.Blocks()
+	require.Len(blocks, 3)
+	require.Equal(GenesisID, blocks[0].ID)
+	require.Equal(json.Uint64(GenesisHeight), blocks[0].Height)
+	require.True(blocks[0].Accepted)
+	require.NotEmpty(blocks[0].Snowball)
+
+	require.Equal(
+		BlockState{
+			ID:         block2.ID(),
+			ParentID:   block1.ID(),
+			Height:     json.Uint64(block2.Height()),
+			Preferred:  true,
+			Confidence: 1,
+		},
+		blocks[2],
+	)
+}
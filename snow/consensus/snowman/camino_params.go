@@ -0,0 +1,30 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+)
+
+var errProcessingBlocks = errors.New("can't change the parameters while blocks are processing")
+
+// SetParameters replaces the parameters consensus was initialized with. As the
+// snowball instances of processing blocks keep their parameters, this fails
+// unless no blocks are processing.
+func (ts *Topological) SetParameters(params snowball.Parameters) error {
+	if err := params.Verify(); err != nil {
+		return err
+	}
+	if ts.NumProcessing() > 0 {
+		return errProcessingBlocks
+	}
+	ts.params = params
+	// Consensus may not be initialized yet
+	if head, ok := ts.blocks[ts.head]; ok {
+		head.params = params
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+func TestTopologicalSetParameters(t *testing.T) {
+	require := require.New(t)
+
+	sm := &Topological{}
+	params := snowball.Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: 1,
+	}
+	require.NoError(sm.Initialize(snow.DefaultConsensusContextTest(), params, GenesisID, GenesisHeight, GenesisTimestamp))
+
+	block1 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: GenesisID,
+		HeightV: GenesisHeight + 1,
+	}
+	block2 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(2),
+			StatusV: choices.Processing,
+		},
+		ParentV: block1.ID(),
+		HeightV: GenesisHeight + 2,
+	}
+	require.NoError(sm.Add(context.Background(), block1))
+
+	newParams := params
+	newParams.BetaVirtuous = 2
+	newParams.BetaRogue = 2
+	require.ErrorIs(sm.SetParameters(newParams), errProcessingBlocks)
+
+	votes := bag.Bag[ids.ID]{}
+	votes.Add(block1.ID())
+	require.NoError(sm.RecordPoll(context.Background(), votes))
+	require.Equal(choices.Accepted, block1.Status())
+
+	invalidParams := newParams
+	invalidParams.BetaRogue = 1
+	require.Error(sm.SetParameters(invalidParams))
+
+	require.NoError(sm.SetParameters(newParams))
+
+	// A block added afterwards needs two successful polls
+	require.NoError(sm.Add(context.Background(), block2))
+	votes = bag.Bag[ids.ID]{}
+	votes.Add(block2.ID())
+	require.NoError(sm.RecordPoll(context.Background(), votes))
+	require.Equal(choices.Processing, block2.Status())
+	require.NoError(sm.RecordPoll(context.Background(), votes))
+	require.Equal(choices.Accepted, block2.Status())
+}
@@ -0,0 +1,138 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+// maxResponses is the number of recent responses that are kept
+const maxResponses = 1024
+
+var _ Poll = (*trackedPoll)(nil)
+
+// Info describes an outstanding poll
+type Info struct {
+	RequestID uint32    `json:"requestID"`
+	Start     time.Time `json:"start"`
+	// Polled are the validators that were sent the query, with the number of
+	// times they were sampled
+	Polled map[ids.NodeID]int `json:"polled"`
+	// Votes are the validators that responded, with the block they voted for
+	Votes map[ids.NodeID]ids.ID `json:"votes"`
+	// Dropped are the validators whose queries failed or timed out
+	Dropped []ids.NodeID `json:"dropped"`
+}
+
+// Response is the outcome of a query sent to a polled validator
+type Response struct {
+	Latency time.Duration `json:"latency"`
+	// Dropped is true if the query failed or timed out
+	Dropped bool `json:"dropped"`
+}
+
+// responses keeps the last [maxResponses] responses in a ring buffer
+type responses struct {
+	list []Response
+	next int
+}
+
+func (r *responses) add(response Response) {
+	if len(r.list) < maxResponses {
+		r.list = append(r.list, response)
+		return
+	}
+	r.list[r.next] = response
+	r.next = (r.next + 1) % maxResponses
+}
+
+// trackedPoll records which validators responded with what, so that
+// outstanding polls can be inspected
+type trackedPoll struct {
+	Poll
+	start     time.Time
+	responses *responses
+	polled    bag.Bag[ids.NodeID]
+	votes     map[ids.NodeID]ids.ID
+	dropped   map[ids.NodeID]struct{}
+}
+
+func newTrackedPoll(p Poll, vdrs bag.Bag[ids.NodeID], start time.Time, responses *responses) *trackedPoll {
+	// [p] removes the validators that responded from [vdrs], so a copy is kept
+	polled := bag.Bag[ids.NodeID]{}
+	for _, vdr := range vdrs.List() {
+		polled.AddCount(vdr, vdrs.Count(vdr))
+	}
+	return &trackedPoll{
+		Poll:      p,
+		start:     start,
+		responses: responses,
+		polled:    polled,
+		votes:     make(map[ids.NodeID]ids.ID),
+		dropped:   make(map[ids.NodeID]struct{}),
+	}
+}
+
+func (p *trackedPoll) Vote(vdr ids.NodeID, vote ids.ID) {
+	if p.responded(vdr) {
+		return
+	}
+	p.votes[vdr] = vote
+	p.responses.add(Response{Latency: time.Since(p.start)})
+	p.Poll.Vote(vdr, vote)
+}
+
+func (p *trackedPoll) Drop(vdr ids.NodeID) {
+	if p.responded(vdr) {
+		return
+	}
+	p.dropped[vdr] = struct{}{}
+	p.responses.add(Response{
+		Latency: time.Since(p.start),
+		Dropped: true,
+	})
+	p.Poll.Drop(vdr)
+}
+
+// responded returns true if [vdr] wasn't polled or already responded
+func (p *trackedPoll) responded(vdr ids.NodeID) bool {
+	_, voted := p.votes[vdr]
+	_, dropped := p.dropped[vdr]
+	return voted || dropped || p.polled.Count(vdr) == 0
+}
+
+func (s *set) Outstanding() []Info {
+	infos := make([]Info, 0, s.polls.Len())
+	iter := s.polls.NewIterator()
+	for iter.Next() {
+		holder := iter.Value()
+		info := Info{
+			RequestID: iter.Key(),
+			Start:     holder.StartTime(),
+		}
+		if p, ok := holder.GetPoll().(poll).Poll.(*trackedPoll); ok {
+			info.Polled = make(map[ids.NodeID]int, p.polled.Len())
+			for _, vdr := range p.polled.List() {
+				info.Polled[vdr] = p.polled.Count(vdr)
+			}
+			info.Votes = make(map[ids.NodeID]ids.ID, len(p.votes))
+			for vdr, vote := range p.votes {
+				info.Votes[vdr] = vote
+			}
+			info.Dropped = make([]ids.NodeID, 0, len(p.dropped))
+			for vdr := range p.dropped {
+				info.Dropped = append(info.Dropped, vdr)
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func (s *set) Responses() []Response {
+	return append([]Response(nil), s.responses.list...)
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package poll
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/bag"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestSetOutstanding(t *testing.T) {
+	require := require.New(t)
+
+	s := NewSet(NewNoEarlyTermFactory(), logging.NoLog{}, "", prometheus.NewRegistry())
+
+	vdr1 := ids.NodeID{1}
+	vdr2 := ids.NodeID{2}
+	vdr3 := ids.NodeID{3}
+	vdrBag := bag.Bag[ids.NodeID]{}
+	vdrBag.Add(vdr1, vdr2, vdr2, vdr3)
+	require.True(s.Add(1, vdrBag))
+
+	blkID := ids.ID{1}
+	require.Empty(s.Vote(1, vdr1, blkID))
+	require.Empty(s.Drop(1, vdr2))
+
+	// Later responses of a validator are ignored
+	require.Empty(s.Vote(1, vdr1, ids.ID{2}))
+
+	// Responses of validators that weren't polled are ignored
+	require.Empty(s.Vote(1, ids.NodeID{4}, blkID))
+
+	polls := s.Outstanding()
+	require.Len(polls, 1)
+	require.Equal(uint32(1), polls[0].RequestID)
+	require.Equal(
+		map[ids.NodeID]int{
+			vdr1: 1,
+			vdr2: 2,
+			vdr3: 1,
+		},
+		polls[0].Polled,
+	)
+	require.Equal(map[ids.NodeID]ids.ID{vdr1: blkID}, polls[0].Votes)
+	require.Equal([]ids.NodeID{vdr2}, polls[0].Dropped)
+
+	results := s.Vote(1, vdr3, blkID)
+	require.Len(results, 1)
+	require.Equal(2, results[0].Count(blkID))
+	require.Empty(s.Outstanding())
+
+	// Only the first response of each polled validator is recorded
+	responses := s.Responses()
+	require.Len(responses, 3)
+	require.False(responses[0].Dropped)
+	require.True(responses[1].Dropped)
+	require.False(responses[2].Dropped)
+}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -19,6 +29,13 @@ type Set interface {
 	Vote(requestID uint32, vdr ids.NodeID, vote ids.ID) []bag.Bag[ids.ID]
 	Drop(requestID uint32, vdr ids.NodeID) []bag.Bag[ids.ID]
 	Len() int
+
+	// Outstanding returns the outstanding polls, from the oldest to the newest
+	Outstanding() []Info
+
+	// Responses returns the recent responses of polled validators, which
+	// includes only the responses received before their poll finished
+	Responses() []Response
 }
 
 // Poll is an outstanding poll
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -44,6 +54,8 @@ type set struct {
 	factory  Factory
 	// maps requestID -> poll
 	polls linkedhashmap.LinkedHashmap[uint32, pollHolder]
+	// recent responses of polled validators
+	responses responses
 }
 
 // NewSet returns a new empty set of polls
@@ -102,9 +114,10 @@ func (s *set) Add(requestID uint32, vdrs bag.Bag[ids.NodeID]) bool {
 		zap.Stringer("validators", &vdrs),
 	)
 
+	start := time.Now()
 	s.polls.Put(requestID, poll{
-		Poll:  s.factory.New(vdrs), // create the new poll
-		start: time.Now(),
+		Poll:  newTrackedPoll(s.factory.New(vdrs), vdrs, start, &s.responses), // create the new poll
+		start: start,
 	})
 	s.numPolls.Inc() // increase the metrics
 	return true
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -12,6 +22,8 @@ import (
 	"github.com/ava-labs/avalanchego/api/metrics"
 	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
@@ -87,6 +99,13 @@ type ConsensusContext struct {
 	// accepted.
 	VertexAcceptor Acceptor
 
+	// Reputation tracks the behaviour of the peers of this chain.
+	Reputation reputation.Tracker
+
+	// Bandwidth accounts for the traffic of this chain and enforces the
+	// quota of its subnet.
+	Bandwidth bandwidth.Tracker
+
 	// State indicates the current state of this consensus instance.
 	State utils.Atomic[EngineState]
 
@@ -124,5 +143,7 @@ func DefaultConsensusContextTest() *ConsensusContext {
 		BlockAcceptor:       noOpAcceptor{},
 		TxAcceptor:          noOpAcceptor{},
 		VertexAcceptor:      noOpAcceptor{},
+		Reputation:          reputation.NewNoTracker(),
+		Bandwidth:           bandwidth.NewNoTracker(),
 	}
 }
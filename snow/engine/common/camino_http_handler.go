@@ -0,0 +1,10 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// GRPCExtension is the extension of the handler returned by CreateHandlers
+// that serves the VM's gRPC services. The handler must be a *grpc.Server. Its
+// services are served at the root of the API server, rather than under the
+// chain's endpoint, so only the chains of the primary network can serve them.
+const GRPCExtension = "/grpc"
@@ -0,0 +1,23 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+// WrappedVM is implemented by VMs, that wrap another VM to add behavior, like
+// metrics or tracing, to it.
+type WrappedVM interface {
+	// Unwrap returns the wrapped VM
+	Unwrap() VM
+}
+
+// UnwrapVM returns the innermost VM, that is wrapped by [vm]. Returns [vm], if
+// it doesn't wrap another VM.
+func UnwrapVM(vm VM) VM {
+	for {
+		wrappedVM, ok := vm.(WrappedVM)
+		if !ok {
+			return vm
+		}
+		vm = wrappedVM.Unwrap()
+	}
+}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -20,6 +30,7 @@ import (
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/timer"
 	"github.com/ava-labs/avalanchego/version"
@@ -196,6 +207,7 @@ func (b *bootstrapper) Ancestors(ctx context.Context, nodeID ids.NodeID, request
 			zap.Uint32("requestID", requestID),
 			zap.Error(err),
 		)
+		b.Ctx.Reputation.Report(nodeID, reputation.InvalidContainer)
 		return b.fetch(ctx, wantedBlkID)
 	}
 
@@ -213,6 +225,7 @@ func (b *bootstrapper) Ancestors(ctx context.Context, nodeID ids.NodeID, request
 			zap.Stringer("expectedBlkID", wantedBlkID),
 			zap.Stringer("blkID", actualID),
 		)
+		b.Ctx.Reputation.Report(nodeID, reputation.InvalidContainer)
 		return b.fetch(ctx, wantedBlkID)
 	}
 
@@ -375,7 +388,8 @@ func (b *bootstrapper) fetch(ctx context.Context, blkID ids.ID) error {
 		return b.checkFinish(ctx)
 	}
 
-	validatorID, ok := b.fetchFrom.Peek()
+	// Prefer the nodes with the best reputation
+	validatorID, ok := reputation.Best(b.Ctx.Reputation, b.fetchFrom)
 	if !ok {
 		return fmt.Errorf("dropping request for %s as there are no validators", blkID)
 	}
@@ -0,0 +1,133 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// maxPreferenceChanges is the number of preference changes that are kept
+const maxPreferenceChanges = 1024
+
+var (
+	_ Inspector = (*Transitive)(nil)
+	_ Inspector = (*tracedEngine)(nil)
+)
+
+// ConsensusState is the state of the consensus of a snowman chain
+type ConsensusState struct {
+	LastAccepted ids.ID `json:"lastAccepted"`
+	Preference   ids.ID `json:"preference"`
+	// Blocks are the last accepted block followed by the processing blocks
+	Blocks []snowman.BlockState `json:"blocks"`
+	// Polls are the outstanding polls, from the oldest to the newest
+	Polls []poll.Info `json:"polls"`
+	// NumPending is the number of blocks that wait for missing ancestors
+	// before they can be issued
+	NumPending int `json:"numPending"`
+}
+
+// PreferenceChange is a change of the preferred block of a snowman chain
+type PreferenceChange struct {
+	// Sequence increases by 1 with every preference change
+	Sequence     json.Uint64 `json:"sequence"`
+	Time         time.Time   `json:"time"`
+	Previous     ids.ID      `json:"previous"`
+	Preference   ids.ID      `json:"preference"`
+	LastAccepted ids.ID      `json:"lastAccepted"`
+}
+
+// Inspector exposes the consensus state of a snowman engine, to diagnose slow
+// finalization. The context lock of the chain must be held while calling its
+// methods.
+type Inspector interface {
+	// ConsensusState returns the processing blocks and the outstanding polls
+	ConsensusState() *ConsensusState
+
+	// PreferenceChanges returns the kept preference changes with a sequence
+	// number greater than [after], from the oldest to the newest
+	PreferenceChanges(after uint64) []PreferenceChange
+}
+
+// preferenceLog keeps the last [maxPreferenceChanges] preference changes
+type preferenceLog struct {
+	clock      mockable.Clock
+	preference ids.ID
+	sequence   uint64
+	changes    []PreferenceChange
+}
+
+func (t *Transitive) ConsensusState() *ConsensusState {
+	return &ConsensusState{
+		LastAccepted: t.Consensus.LastAccepted(),
+		Preference:   t.Consensus.Preference(),
+		Blocks:       t.Consensus.Blocks(),
+		Polls:        t.polls.Outstanding(),
+		NumPending:   len(t.pending),
+	}
+}
+
+func (t *Transitive) PreferenceChanges(after uint64) []PreferenceChange {
+	changes := t.preferences.changes
+	for i, change := range changes {
+		if uint64(change.Sequence) > after {
+			return append([]PreferenceChange(nil), changes[i:]...)
+		}
+	}
+	return nil
+}
+
+// recordPreference adds a preference change to the log if the preference of
+// consensus changed since it was last recorded
+func (t *Transitive) recordPreference() {
+	preference := t.Consensus.Preference()
+	if preference == t.preferences.preference {
+		return
+	}
+
+	t.preferences.sequence++
+	change := PreferenceChange{
+		Sequence:     json.Uint64(t.preferences.sequence),
+		Time:         t.preferences.clock.Time(),
+		Previous:     t.preferences.preference,
+		Preference:   preference,
+		LastAccepted: t.Consensus.LastAccepted(),
+	}
+	t.Ctx.Log.Debug("preference changed",
+		zap.Uint64("sequence", t.preferences.sequence),
+		zap.Stringer("previous", change.Previous),
+		zap.Stringer("preference", change.Preference),
+		zap.Stringer("lastAccepted", change.LastAccepted),
+	)
+
+	t.preferences.preference = preference
+	if len(t.preferences.changes) == maxPreferenceChanges {
+		t.preferences.changes = t.preferences.changes[1:]
+	}
+	t.preferences.changes = append(t.preferences.changes, change)
+}
+
+func (e *tracedEngine) ConsensusState() *ConsensusState {
+	inspector, ok := e.engine.(Inspector)
+	if !ok {
+		return nil
+	}
+	return inspector.ConsensusState()
+}
+
+func (e *tracedEngine) PreferenceChanges(after uint64) []PreferenceChange {
+	inspector, ok := e.engine.(Inspector)
+	if !ok {
+		return nil
+	}
+	return inspector.PreferenceChanges(after)
+}
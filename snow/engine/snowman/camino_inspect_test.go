@@ -0,0 +1,82 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+func TestEngineInspector(t *testing.T) {
+	require := require.New(t)
+
+	vdr, _, sender, vm, te, gBlk := setupDefaultConfig(t)
+
+	sender.Default(true)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk.ID():
+			return blk, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	var reqID uint32
+	sender.SendPushQueryF = func(_ context.Context, _ set.Set[ids.NodeID], requestID uint32, _ []byte) {
+		reqID = requestID
+	}
+
+	require.Empty(te.PreferenceChanges(0))
+
+	require.NoError(te.issue(context.Background(), blk))
+
+	state := te.ConsensusState()
+	require.Equal(gBlk.ID(), state.LastAccepted)
+	require.Equal(blk.ID(), state.Preference)
+	require.Len(state.Blocks, 2)
+	require.Equal(blk.ID(), state.Blocks[1].ID)
+	require.Zero(state.NumPending)
+	require.Len(state.Polls, 1)
+	require.Equal(reqID, state.Polls[0].RequestID)
+	require.Equal(map[ids.NodeID]int{vdr: 1}, state.Polls[0].Polled)
+	require.Empty(state.Polls[0].Votes)
+
+	changes := te.PreferenceChanges(0)
+	require.Len(changes, 1)
+	require.Equal(gBlk.ID(), changes[0].Previous)
+	require.Equal(blk.ID(), changes[0].Preference)
+	require.Equal(gBlk.ID(), changes[0].LastAccepted)
+	require.Empty(te.PreferenceChanges(uint64(changes[0].Sequence)))
+
+	require.NoError(te.Chits(context.Background(), vdr, reqID, []ids.ID{blk.ID()}, nil))
+	require.Equal(choices.Accepted, blk.Status())
+
+	state = te.ConsensusState()
+	require.Equal(blk.ID(), state.LastAccepted)
+	require.Len(state.Blocks, 1)
+	require.Empty(state.Polls)
+
+	// Accepting the preferred block doesn't change the preference
+	require.Len(te.PreferenceChanges(0), 1)
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
+	"github.com/ava-labs/avalanchego/utils/bag"
+)
+
+var (
+	errNoResponses          = errors.New("no recorded poll responses")
+	errUnsupportedSetParams = errors.New("engine can't change its parameters")
+
+	_ ResponseRecorder = (*Transitive)(nil)
+	_ ResponseRecorder = (*tracedEngine)(nil)
+	_ ParametersSetter = (*Transitive)(nil)
+	_ ParametersSetter = (*tracedEngine)(nil)
+	_ poll.Factory     = pollFactory{}
+)
+
+// ResponseRecorder records the responses of the validators polled by a snowman
+// engine. The context lock of the chain must be held while calling its methods.
+type ResponseRecorder interface {
+	// Responses returns the recent responses of polled validators
+	Responses() []poll.Response
+}
+
+// ParametersSetter changes the snowball parameters of a running snowman
+// engine. The context lock of the chain must be held while calling its methods.
+type ParametersSetter interface {
+	// SetParameters verifies [params] and uses them once no blocks are
+	// processing, which may be immediately. Until then, the current parameters
+	// are kept. A later call replaces parameters that weren't used yet.
+	SetParameters(params snowball.Parameters) error
+}
+
+// FinalityEstimate is the expected time to finalize a virtuous block with a
+// set of parameters, based on the recent responses of polled validators
+type FinalityEstimate struct {
+	// NumResponses is the number of responses the estimate is based on
+	NumResponses int `json:"numResponses"`
+	// DropRate is the fraction of queries that failed or timed out
+	DropRate float64 `json:"dropRate"`
+	// ExpectedResponses is the expected number of responses to a poll of K
+	// validators
+	ExpectedResponses float64 `json:"expectedResponses"`
+	// Finalizes is false if less than alpha validators are expected to respond
+	// to a poll, in which case blocks are unlikely to be finalized
+	Finalizes bool `json:"finalizes"`
+	// PollLatency is the expected time until alpha validators of a poll
+	// responded
+	PollLatency time.Duration `json:"pollLatency"`
+	// FinalityLatency is the expected time until a block received beta
+	// consecutive successful polls
+	FinalityLatency time.Duration `json:"finalityLatency"`
+}
+
+// EstimateFinality estimates the finality latency of virtuous blocks with
+// [params] from the recorded [responses] of polled validators
+func EstimateFinality(params snowball.Parameters, responses []poll.Response) (*FinalityEstimate, error) {
+	if len(responses) == 0 {
+		return nil, errNoResponses
+	}
+
+	latencies := make([]time.Duration, 0, len(responses))
+	for _, response := range responses {
+		if !response.Dropped {
+			latencies = append(latencies, response.Latency)
+		}
+	}
+	estimate := &FinalityEstimate{
+		NumResponses: len(responses),
+		DropRate:     1 - float64(len(latencies))/float64(len(responses)),
+	}
+	estimate.ExpectedResponses = float64(params.K) * (1 - estimate.DropRate)
+	if estimate.ExpectedResponses < float64(params.Alpha) {
+		return estimate, nil
+	}
+
+	// A poll succeeds once the alpha fastest of its expected responses arrived
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	i := int(math.Ceil(float64(len(latencies))*float64(params.Alpha)/estimate.ExpectedResponses)) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(latencies) {
+		i = len(latencies) - 1
+	}
+	estimate.Finalizes = true
+	estimate.PollLatency = latencies[i]
+
+	// Up to [ConcurrentRepolls] polls are outstanding at the same time
+	rounds := (params.BetaVirtuous + params.ConcurrentRepolls - 1) / params.ConcurrentRepolls
+	estimate.FinalityLatency = time.Duration(rounds) * estimate.PollLatency
+	return estimate, nil
+}
+
+func (t *Transitive) Responses() []poll.Response {
+	return t.polls.Responses()
+}
+
+func (e *tracedEngine) Responses() []poll.Response {
+	recorder, ok := e.engine.(ResponseRecorder)
+	if !ok {
+		return nil
+	}
+	return recorder.Responses()
+}
+
+func (t *Transitive) SetParameters(params snowball.Parameters) error {
+	if err := params.Verify(); err != nil {
+		return err
+	}
+	t.pendingParams = &params
+	return t.applyParameters()
+}
+
+// applyParameters switches to the pending parameters, if there are any and
+// no blocks are processing. Otherwise, they are applied once the processing
+// blocks are decided.
+func (t *Transitive) applyParameters() error {
+	if t.pendingParams == nil || t.Consensus.NumProcessing() > 0 {
+		return nil
+	}
+	params := *t.pendingParams
+	if err := t.Consensus.SetParameters(params); err != nil {
+		return err
+	}
+	t.Params = params
+	t.pendingParams = nil
+	t.Ctx.Log.Info("switched consensus parameters",
+		zap.Reflect("parameters", params),
+	)
+	return nil
+}
+
+func (e *tracedEngine) SetParameters(params snowball.Parameters) error {
+	setter, ok := e.engine.(ParametersSetter)
+	if !ok {
+		return errUnsupportedSetParams
+	}
+	return setter.SetParameters(params)
+}
+
+// pollFactory creates polls, which terminate early with the alpha of the
+// current [params] of the engine
+type pollFactory struct {
+	params *snowball.Parameters
+}
+
+func (f pollFactory) New(vdrs bag.Bag[ids.NodeID]) poll.Poll {
+	return poll.NewEarlyTermNoTraversalFactory(f.params.Alpha).New(vdrs)
+}
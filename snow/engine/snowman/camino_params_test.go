@@ -0,0 +1,174 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+func TestEngineResponses(t *testing.T) {
+	require := require.New(t)
+
+	vdr, _, sender, vm, te, gBlk := setupDefaultConfig(t)
+
+	sender.Default(true)
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk.ID():
+			return blk, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	var reqID uint32
+	sender.SendPushQueryF = func(_ context.Context, _ set.Set[ids.NodeID], requestID uint32, _ []byte) {
+		reqID = requestID
+	}
+
+	require.NoError(te.issue(context.Background(), blk))
+	require.Empty(te.Responses())
+
+	require.NoError(te.Chits(context.Background(), vdr, reqID, []ids.ID{blk.ID()}, nil))
+	require.Equal(choices.Accepted, blk.Status())
+	responses := te.Responses()
+	require.Len(responses, 1)
+	require.False(responses[0].Dropped)
+}
+
+func TestEngineSetParameters(t *testing.T) {
+	require := require.New(t)
+
+	vdr, _, sender, vm, te, gBlk := setupDefaultConfig(t)
+
+	sender.Default(true)
+
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blk2 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk1.ID(),
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		switch blkID {
+		case gBlk.ID():
+			return gBlk, nil
+		case blk1.ID():
+			return blk1, nil
+		case blk2.ID():
+			return blk2, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	var reqID uint32
+	sender.SendPushQueryF = func(_ context.Context, _ set.Set[ids.NodeID], requestID uint32, _ []byte) {
+		reqID = requestID
+	}
+	sender.SendPullQueryF = func(_ context.Context, _ set.Set[ids.NodeID], requestID uint32, _ ids.ID) {
+		reqID = requestID
+	}
+
+	invalidParams := te.Params
+	invalidParams.Alpha = 0
+	require.Error(te.SetParameters(invalidParams))
+
+	require.NoError(te.issue(context.Background(), blk1))
+
+	// The parameters aren't switched while a block is processing
+	params := te.Params
+	params.BetaVirtuous = 2
+	params.BetaRogue = 3
+	require.NoError(te.SetParameters(params))
+	require.Equal(1, te.Params.BetaVirtuous)
+
+	require.NoError(te.Chits(context.Background(), vdr, reqID, []ids.ID{blk1.ID()}, nil))
+	require.Equal(choices.Accepted, blk1.Status())
+	require.Equal(params, te.Params)
+
+	// Blocks issued afterwards need two successful polls
+	require.NoError(te.issue(context.Background(), blk2))
+	require.NoError(te.Chits(context.Background(), vdr, reqID, []ids.ID{blk2.ID()}, nil))
+	require.Equal(choices.Processing, blk2.Status())
+	require.NoError(te.Chits(context.Background(), vdr, reqID, []ids.ID{blk2.ID()}, nil))
+	require.Equal(choices.Accepted, blk2.Status())
+}
+
+func TestEstimateFinality(t *testing.T) {
+	require := require.New(t)
+
+	params := snowball.Parameters{
+		K:                 4,
+		Alpha:             2,
+		BetaVirtuous:      3,
+		BetaRogue:         4,
+		ConcurrentRepolls: 2,
+	}
+
+	_, err := EstimateFinality(params, nil)
+	require.ErrorIs(err, errNoResponses)
+
+	responses := []poll.Response{
+		{Latency: 40 * time.Millisecond},
+		{Latency: 10 * time.Millisecond},
+		{Latency: 30 * time.Millisecond},
+		{Latency: 20 * time.Millisecond},
+	}
+	estimate, err := EstimateFinality(params, responses)
+	require.NoError(err)
+	require.True(estimate.Finalizes)
+	require.Equal(4, estimate.NumResponses)
+	require.Zero(estimate.DropRate)
+	require.Equal(20*time.Millisecond, estimate.PollLatency)
+	require.Equal(40*time.Millisecond, estimate.FinalityLatency)
+
+	responses = append(responses,
+		poll.Response{Dropped: true},
+		poll.Response{Dropped: true},
+		poll.Response{Dropped: true},
+		poll.Response{Dropped: true},
+		poll.Response{Dropped: true},
+		poll.Response{Dropped: true},
+	)
+	estimate, err = EstimateFinality(params, responses)
+	require.NoError(err)
+	require.False(estimate.Finalizes)
+	require.InDelta(0.6, estimate.DropRate, 0.0001)
+}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -15,11 +25,13 @@ import (
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman/poll"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
 	"github.com/ava-labs/avalanchego/snow/events"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/validators"
 	"github.com/ava-labs/avalanchego/utils/bag"
 	"github.com/ava-labs/avalanchego/utils/set"
@@ -81,6 +93,13 @@ type Transitive struct {
 	// processing blocks has gone below the optimal number.
 	pendingBuildBlocks int
 
+	// recent changes of the preference of consensus
+	preferences preferenceLog
+
+	// parameters to use once no blocks are processing, nil if the current
+	// parameters are kept
+	pendingParams *snowball.Parameters
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -100,7 +119,6 @@ func newTransitive(config Config) (*Transitive, error) {
 	acceptedFrontiers := tracker.NewAccepted()
 	config.Validators.RegisterCallbackListener(acceptedFrontiers)
 
-	factory := poll.NewEarlyTermNoTraversalFactory(config.Params.Alpha)
 	t := &Transitive{
 		Config:                      config,
 		StateSummaryFrontierHandler: common.NewNoOpStateSummaryFrontierHandler(config.Ctx.Log),
@@ -114,12 +132,14 @@ func newTransitive(config Config) (*Transitive, error) {
 		nonVerifieds:                NewAncestorTree(),
 		nonVerifiedCache:            nonVerifiedCache,
 		acceptedFrontiers:           acceptedFrontiers,
-		polls: poll.NewSet(factory,
-			config.Ctx.Log,
-			"",
-			config.Ctx.Registerer,
-		),
 	}
+	// Polls are created with the current alpha of the engine, which changes
+	// when new parameters are applied
+	t.polls = poll.NewSet(pollFactory{params: &t.Params},
+		config.Ctx.Log,
+		"",
+		config.Ctx.Registerer,
+	)
 
 	return t, t.metrics.Initialize("", config.Ctx.Registerer)
 }
@@ -138,6 +158,7 @@ func (t *Transitive) Put(ctx context.Context, nodeID ids.NodeID, requestID uint3
 			zap.Binary("block", blkBytes),
 			zap.Error(err),
 		)
+		t.Ctx.Reputation.Report(nodeID, reputation.InvalidContainer)
 		// because GetFailed doesn't utilize the assumption that we actually
 		// sent a Get message, we can safely call GetFailed here to potentially
 		// abandon the request.
@@ -155,6 +176,7 @@ func (t *Transitive) Put(ctx context.Context, nodeID ids.NodeID, requestID uint3
 			zap.Stringer("blkID", actualBlkID),
 			zap.Stringer("expectedBlkID", expectedBlkID),
 		)
+		t.Ctx.Reputation.Report(nodeID, reputation.InvalidContainer)
 		// We assume that [blk] is useless because it doesn't match what we
 		// expected.
 		return t.GetFailed(ctx, nodeID, requestID)
@@ -223,6 +245,7 @@ func (t *Transitive) PushQuery(ctx context.Context, nodeID ids.NodeID, requestID
 			zap.Binary("block", blkBytes),
 			zap.Error(err),
 		)
+		t.Ctx.Reputation.Report(nodeID, reputation.InvalidContainer)
 		return nil
 	}
 
@@ -380,6 +403,7 @@ func (t *Transitive) Start(ctx context.Context, startReqID uint32) error {
 	if err := t.Consensus.Initialize(t.Ctx, t.Params, lastAcceptedID, lastAccepted.Height(), lastAccepted.Timestamp()); err != nil {
 		return err
 	}
+	t.preferences.preference = lastAcceptedID
 
 	// to maintain the invariant that oracle blocks are issued in the correct
 	// preferences, we need to handle the case that we are bootstrapping into an oracle block
@@ -813,6 +837,7 @@ func (t *Transitive) deliver(ctx context.Context, blk snowman.Block) error {
 	if err := t.VM.SetPreference(ctx, t.Consensus.Preference()); err != nil {
 		return err
 	}
+	t.recordPreference()
 
 	// If the block is now preferred, query the network for its preferences
 	// with this new block.
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -78,9 +88,13 @@ func (v *voter) Update(ctx context.Context) {
 		v.t.errs.Add(err)
 		return
 	}
+	v.t.recordPreference()
 
 	if v.t.Consensus.Finalized() {
 		v.t.Ctx.Log.Debug("Snowman engine can quiesce")
+		if err := v.t.applyParameters(); err != nil {
+			v.t.errs.Add(err)
+		}
 		return
 	}
 
@@ -0,0 +1,325 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bandwidth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var (
+	_ Tracker = (*tracker)(nil)
+	_ Tracker = noTracker{}
+)
+
+// Quota limits the traffic of all chains of a subnet. A limit of 0 means the
+// traffic in that direction isn't limited.
+type Quota struct {
+	// InboundBytesPerSecond is the rate at which the chains of the subnet may
+	// receive bytes. Messages received above it are dropped.
+	InboundBytesPerSecond uint64 `json:"inboundBytesPerSecond" yaml:"inboundBytesPerSecond"`
+	// OutboundBytesPerSecond is the rate at which the chains of the subnet may
+	// send bytes, counted per recipient. Messages sent above it fail as if the
+	// peers were unreachable.
+	OutboundBytesPerSecond uint64 `json:"outboundBytesPerSecond" yaml:"outboundBytesPerSecond"`
+}
+
+// Usage is the traffic accounted for a chain or a peer
+type Usage struct {
+	InboundBytes         json.Uint64 `json:"inboundBytes"`
+	InboundMessages      json.Uint64 `json:"inboundMessages"`
+	OutboundBytes        json.Uint64 `json:"outboundBytes"`
+	OutboundMessages     json.Uint64 `json:"outboundMessages"`
+	DroppedInboundBytes  json.Uint64 `json:"droppedInboundBytes"`
+	DroppedOutboundBytes json.Uint64 `json:"droppedOutboundBytes"`
+}
+
+// Tracker accounts for the bytes chains exchange with peers and enforces the
+// quotas of their subnets.
+type Tracker interface {
+	// SetQuota replaces the quota of [subnetID]
+	SetQuota(subnetID ids.ID, quota Quota)
+
+	// AllowReceive returns false if the inbound quota of [subnetID] is
+	// exhausted and a message of [numBytes] from [nodeID] for [chainID] must
+	// be dropped.
+	AllowReceive(subnetID, chainID ids.ID, nodeID ids.NodeID, numBytes int) bool
+
+	// Received accounts for a message of [numBytes] from [nodeID] for
+	// [chainID].
+	Received(chainID ids.ID, nodeID ids.NodeID, numBytes int)
+
+	// AllowSend returns false if the outbound quota of [subnetID] is exhausted
+	// and a message must not be sent for [chainID]. If not allowed,
+	// [numBytes] are accounted as dropped. The quota is only charged once the
+	// message is Sent.
+	AllowSend(subnetID, chainID ids.ID, numBytes int) bool
+
+	// Sent accounts for a message of [numBytes] sent for [chainID] to each of
+	// [nodeIDs] and charges the outbound quota of [subnetID] for each of them.
+	Sent(subnetID, chainID ids.ID, nodeIDs set.Set[ids.NodeID], numBytes int)
+
+	// Disconnected drops the traffic accounted with [nodeID]
+	Disconnected(nodeID ids.NodeID)
+
+	// ChainStopped drops the traffic accounted for [chainID]
+	ChainStopped(chainID ids.ID)
+
+	// Chains returns the traffic of every chain that sent or received bytes
+	Chains() map[ids.ID]Usage
+
+	// Peers returns the traffic with every peer bytes were exchanged with
+	Peers() map[ids.NodeID]Usage
+}
+
+type tracker struct {
+	clock   mockable.Clock
+	metrics *metrics
+
+	lock   sync.Mutex
+	quotas map[ids.ID]*quota
+	chains map[ids.ID]*Usage
+	peers  map[ids.NodeID]*Usage
+}
+
+type quota struct {
+	inbound  *bucket
+	outbound *bucket
+}
+
+func NewTracker(namespace string, registerer prometheus.Registerer) (Tracker, error) {
+	metrics, err := newMetrics(namespace, registerer)
+	return &tracker{
+		metrics: metrics,
+		quotas:  make(map[ids.ID]*quota),
+		chains:  make(map[ids.ID]*Usage),
+		peers:   make(map[ids.NodeID]*Usage),
+	}, err
+}
+
+func (t *tracker) SetQuota(subnetID ids.ID, q Quota) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if q.InboundBytesPerSecond == 0 && q.OutboundBytesPerSecond == 0 {
+		delete(t.quotas, subnetID)
+		return
+	}
+	now := t.clock.Time()
+	t.quotas[subnetID] = &quota{
+		inbound:  newBucket(q.InboundBytesPerSecond, now),
+		outbound: newBucket(q.OutboundBytesPerSecond, now),
+	}
+}
+
+func (t *tracker) AllowReceive(subnetID, chainID ids.ID, nodeID ids.NodeID, numBytes int) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	q, ok := t.quotas[subnetID]
+	if !ok || q.inbound.take(t.clock.Time(), numBytes) {
+		return true
+	}
+	t.chainUsage(chainID).DroppedInboundBytes += json.Uint64(numBytes)
+	t.peerUsage(nodeID).DroppedInboundBytes += json.Uint64(numBytes)
+	t.metrics.dropped(chainID, inbound, numBytes)
+	return false
+}
+
+func (t *tracker) Received(chainID ids.ID, nodeID ids.NodeID, numBytes int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	chain := t.chainUsage(chainID)
+	chain.InboundBytes += json.Uint64(numBytes)
+	chain.InboundMessages++
+
+	peer := t.peerUsage(nodeID)
+	peer.InboundBytes += json.Uint64(numBytes)
+	peer.InboundMessages++
+	t.metrics.accounted(chainID, inbound, numBytes)
+}
+
+func (t *tracker) AllowSend(subnetID, chainID ids.ID, numBytes int) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	q, ok := t.quotas[subnetID]
+	if !ok || q.outbound.allow(t.clock.Time()) {
+		return true
+	}
+	t.chainUsage(chainID).DroppedOutboundBytes += json.Uint64(numBytes)
+	t.metrics.dropped(chainID, outbound, numBytes)
+	return false
+}
+
+func (t *tracker) Sent(subnetID, chainID ids.ID, nodeIDs set.Set[ids.NodeID], numBytes int) {
+	if nodeIDs.Len() == 0 {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if q, ok := t.quotas[subnetID]; ok {
+		q.outbound.charge(nodeIDs.Len() * numBytes)
+	}
+	chain := t.chainUsage(chainID)
+	for nodeID := range nodeIDs {
+		chain.OutboundBytes += json.Uint64(numBytes)
+		chain.OutboundMessages++
+
+		peer := t.peerUsage(nodeID)
+		peer.OutboundBytes += json.Uint64(numBytes)
+		peer.OutboundMessages++
+	}
+	t.metrics.accounted(chainID, outbound, nodeIDs.Len()*numBytes)
+}
+
+func (t *tracker) Disconnected(nodeID ids.NodeID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.peers, nodeID)
+}
+
+func (t *tracker) ChainStopped(chainID ids.ID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.chains, chainID)
+}
+
+func (t *tracker) Chains() map[ids.ID]Usage {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	chains := make(map[ids.ID]Usage, len(t.chains))
+	for chainID, usage := range t.chains {
+		chains[chainID] = *usage
+	}
+	return chains
+}
+
+func (t *tracker) Peers() map[ids.NodeID]Usage {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	peers := make(map[ids.NodeID]Usage, len(t.peers))
+	for nodeID, usage := range t.peers {
+		peers[nodeID] = *usage
+	}
+	return peers
+}
+
+// Assumes [t.lock] is held
+func (t *tracker) chainUsage(chainID ids.ID) *Usage {
+	usage, ok := t.chains[chainID]
+	if !ok {
+		usage = &Usage{}
+		t.chains[chainID] = usage
+	}
+	return usage
+}
+
+// Assumes [t.lock] is held
+func (t *tracker) peerUsage(nodeID ids.NodeID) *Usage {
+	usage, ok := t.peers[nodeID]
+	if !ok {
+		usage = &Usage{}
+		t.peers[nodeID] = usage
+	}
+	return usage
+}
+
+// bucket is a token bucket holding at most one second worth of bytes. A
+// message is allowed as long as the bucket isn't empty, so messages larger
+// than the rate aren't dropped forever; they put the bucket into debt
+// instead.
+type bucket struct {
+	// Bytes per second, 0 if unlimited
+	rate       float64
+	tokens     float64
+	lastUpdate time.Time
+}
+
+func newBucket(rate uint64, now time.Time) *bucket {
+	return &bucket{
+		rate:       float64(rate),
+		tokens:     float64(rate),
+		lastUpdate: now,
+	}
+}
+
+// take charges [numBytes] if the bucket isn't empty
+func (b *bucket) take(now time.Time, numBytes int) bool {
+	if !b.allow(now) {
+		return false
+	}
+	b.charge(numBytes)
+	return true
+}
+
+// allow refills the bucket and returns true if it isn't empty
+func (b *bucket) allow(now time.Time) bool {
+	if b.rate == 0 {
+		return true
+	}
+	if elapsed := now.Sub(b.lastUpdate); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.rate {
+			b.tokens = b.rate
+		}
+		b.lastUpdate = now
+	}
+	return b.tokens > 0
+}
+
+// charge takes [numBytes] from the bucket, possibly putting it into debt
+func (b *bucket) charge(numBytes int) {
+	if b.rate != 0 {
+		b.tokens -= float64(numBytes)
+	}
+}
+
+type noTracker struct{}
+
+// NewNoTracker returns a tracker that doesn't account for anything and
+// allows all traffic
+func NewNoTracker() Tracker {
+	return noTracker{}
+}
+
+func (noTracker) SetQuota(ids.ID, Quota) {}
+
+func (noTracker) AllowReceive(ids.ID, ids.ID, ids.NodeID, int) bool {
+	return true
+}
+
+func (noTracker) Received(ids.ID, ids.NodeID, int) {}
+
+func (noTracker) AllowSend(ids.ID, ids.ID, int) bool {
+	return true
+}
+
+func (noTracker) Sent(ids.ID, ids.ID, set.Set[ids.NodeID], int) {}
+
+func (noTracker) Disconnected(ids.NodeID) {}
+
+func (noTracker) ChainStopped(ids.ID) {}
+
+func (noTracker) Chains() map[ids.ID]Usage {
+	return map[ids.ID]Usage{}
+}
+
+func (noTracker) Peers() map[ids.NodeID]Usage {
+	return map[ids.NodeID]Usage{}
+}
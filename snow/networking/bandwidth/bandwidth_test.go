@@ -0,0 +1,144 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bandwidth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+func TestTrackerAccounting(t *testing.T) {
+	require := require.New(t)
+
+	trackerIntf, err := NewTracker("", prometheus.NewRegistry())
+	require.NoError(err)
+
+	subnetID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+
+	require.True(trackerIntf.AllowReceive(subnetID, chainID, nodeID1, 100))
+	trackerIntf.Received(chainID, nodeID1, 100)
+	require.True(trackerIntf.AllowSend(subnetID, chainID, 2*50))
+	trackerIntf.Sent(subnetID, chainID, set.Set[ids.NodeID]{nodeID1: {}, nodeID2: {}}, 50)
+
+	require.Equal(map[ids.ID]Usage{
+		chainID: {
+			InboundBytes:     100,
+			InboundMessages:  1,
+			OutboundBytes:    100,
+			OutboundMessages: 2,
+		},
+	}, trackerIntf.Chains())
+	require.Equal(map[ids.NodeID]Usage{
+		nodeID1: {
+			InboundBytes:     100,
+			InboundMessages:  1,
+			OutboundBytes:    50,
+			OutboundMessages: 1,
+		},
+		nodeID2: {
+			OutboundBytes:    50,
+			OutboundMessages: 1,
+		},
+	}, trackerIntf.Peers())
+
+	// Disconnected peers and stopped chains aren't tracked anymore
+	trackerIntf.Disconnected(nodeID1)
+	require.Equal(map[ids.NodeID]Usage{
+		nodeID2: {
+			OutboundBytes:    50,
+			OutboundMessages: 1,
+		},
+	}, trackerIntf.Peers())
+	trackerIntf.ChainStopped(chainID)
+	require.Empty(trackerIntf.Chains())
+}
+
+func TestTrackerQuota(t *testing.T) {
+	require := require.New(t)
+
+	trackerIntf, err := NewTracker("", prometheus.NewRegistry())
+	require.NoError(err)
+	tracker := trackerIntf.(*tracker)
+	now := time.Now()
+	tracker.clock.Set(now)
+
+	limitedSubnetID := ids.GenerateTestID()
+	limitedChainID := ids.GenerateTestID()
+	otherSubnetID := ids.GenerateTestID()
+	otherChainID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+
+	tracker.SetQuota(limitedSubnetID, Quota{
+		InboundBytesPerSecond:  1000,
+		OutboundBytesPerSecond: 500,
+	})
+
+	// A message larger than the quota is allowed while the bucket isn't empty
+	require.True(tracker.AllowReceive(limitedSubnetID, limitedChainID, nodeID, 1500))
+	require.False(tracker.AllowReceive(limitedSubnetID, limitedChainID, nodeID, 10))
+
+	// The outbound quota is only charged for the nodes a message was sent to
+	require.True(tracker.AllowSend(limitedSubnetID, limitedChainID, 500))
+	tracker.Sent(limitedSubnetID, limitedChainID, nil, 500)
+	require.True(tracker.AllowSend(limitedSubnetID, limitedChainID, 500))
+	tracker.Sent(limitedSubnetID, limitedChainID, set.Set[ids.NodeID]{nodeID: {}}, 500)
+	require.False(tracker.AllowSend(limitedSubnetID, limitedChainID, 20))
+
+	// Other subnets aren't affected
+	require.True(tracker.AllowReceive(otherSubnetID, otherChainID, nodeID, 10000))
+	require.True(tracker.AllowSend(otherSubnetID, otherChainID, 10000))
+
+	require.Equal(Usage{
+		OutboundBytes:        500,
+		OutboundMessages:     1,
+		DroppedInboundBytes:  10,
+		DroppedOutboundBytes: 20,
+	}, tracker.Chains()[limitedChainID])
+	require.Equal(Usage{
+		OutboundBytes:       500,
+		OutboundMessages:    1,
+		DroppedInboundBytes: 10,
+	}, tracker.Peers()[nodeID])
+
+	// The debt of 500 inbound bytes is paid back after half a second
+	tracker.clock.Set(now.Add(500 * time.Millisecond))
+	require.False(tracker.AllowReceive(limitedSubnetID, limitedChainID, nodeID, 10))
+	tracker.clock.Set(now.Add(501 * time.Millisecond))
+	require.True(tracker.AllowReceive(limitedSubnetID, limitedChainID, nodeID, 10))
+	require.True(tracker.AllowSend(limitedSubnetID, limitedChainID, 20))
+
+	// Removing the quota allows all traffic
+	tracker.SetQuota(limitedSubnetID, Quota{})
+	require.True(tracker.AllowReceive(limitedSubnetID, limitedChainID, nodeID, 1<<30))
+	require.True(tracker.AllowSend(limitedSubnetID, limitedChainID, 1<<30))
+}
+
+func TestTrackerOneDirectionQuota(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := NewTracker("", prometheus.NewRegistry())
+	require.NoError(err)
+
+	subnetID := ids.GenerateTestID()
+	chainID := ids.GenerateTestID()
+	nodeID := ids.GenerateTestNodeID()
+	tracker.SetQuota(subnetID, Quota{OutboundBytesPerSecond: 1})
+
+	require.True(tracker.AllowSend(subnetID, chainID, 10))
+	tracker.Sent(subnetID, chainID, set.Set[ids.NodeID]{nodeID: {}}, 10)
+	require.False(tracker.AllowSend(subnetID, chainID, 10))
+	for i := 0; i < 10; i++ {
+		require.True(tracker.AllowReceive(subnetID, chainID, nodeID, 1<<20))
+	}
+}
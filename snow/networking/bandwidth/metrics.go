@@ -0,0 +1,67 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package bandwidth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	chainLabel     = "chain"
+	directionLabel = "direction"
+
+	inbound  = "inbound"
+	outbound = "outbound"
+)
+
+type metrics struct {
+	bytes        *prometheus.CounterVec
+	droppedBytes *prometheus.CounterVec
+}
+
+func newMetrics(namespace string, registerer prometheus.Registerer) (*metrics, error) {
+	labels := []string{chainLabel, directionLabel}
+	m := &metrics{
+		bytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "bytes",
+				Help:      "Number of bytes chains exchanged with peers",
+			},
+			labels,
+		),
+		droppedBytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "dropped_bytes",
+				Help:      "Number of bytes dropped because the quota of the subnet of a chain was exhausted",
+			},
+			labels,
+		),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.bytes),
+		registerer.Register(m.droppedBytes),
+	)
+	return m, errs.Err
+}
+
+func (m *metrics) accounted(chainID ids.ID, direction string, numBytes int) {
+	m.bytes.With(prometheus.Labels{
+		chainLabel:     chainID.String(),
+		directionLabel: direction,
+	}).Add(float64(numBytes))
+}
+
+func (m *metrics) dropped(chainID ids.ID, direction string, numBytes int) {
+	m.droppedBytes.With(prometheus.Labels{
+		chainLabel:     chainID.String(),
+		directionLabel: direction,
+	}).Add(float64(numBytes))
+}
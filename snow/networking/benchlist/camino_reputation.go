@@ -0,0 +1,169 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package benchlist
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var _ Manager = (*reputationManager)(nil)
+
+// reputationBenchlist holds the validators of a chain that are benched due to
+// their reputation.
+type reputationBenchlist struct {
+	vdrs    validators.Set
+	benched set.Set[ids.NodeID]
+}
+
+type reputationManager struct {
+	Manager
+	config  *Config
+	tracker reputation.Tracker
+
+	lock sync.Mutex
+	// Chain ID --> validators benched on that chain due to their reputation
+	chains map[ids.ID]*reputationBenchlist
+}
+
+// NewReputationManager returns a manager that reports the responses and
+// timeouts registered with [manager] to [tracker]. Validators benched by
+// [tracker] are benched on the chains they validate, in addition to the nodes
+// benched by [manager], as long as the stake benched on a chain doesn't exceed
+// [config.MaxPortion].
+func NewReputationManager(manager Manager, tracker reputation.Tracker, config *Config) Manager {
+	return &reputationManager{
+		Manager: manager,
+		config:  config,
+		tracker: tracker,
+		chains:  make(map[ids.ID]*reputationBenchlist),
+	}
+}
+
+func (m *reputationManager) RegisterResponse(chainID ids.ID, nodeID ids.NodeID) {
+	m.tracker.Report(nodeID, reputation.Response)
+	m.Manager.RegisterResponse(chainID, nodeID)
+}
+
+func (m *reputationManager) RegisterFailure(chainID ids.ID, nodeID ids.NodeID) {
+	m.tracker.Report(nodeID, reputation.Timeout)
+	m.Manager.RegisterFailure(chainID, nodeID)
+}
+
+func (m *reputationManager) RegisterChain(ctx *snow.ConsensusContext) error {
+	if err := m.Manager.RegisterChain(ctx); err != nil {
+		return err
+	}
+	if m.config.MaxPortion <= 0 {
+		// No stake may be benched
+		return nil
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, exists := m.chains[ctx.ChainID]; exists {
+		return nil
+	}
+
+	var (
+		vdrs validators.Set
+		ok   bool
+	)
+	if m.config.StakingEnabled {
+		vdrs, ok = m.config.Validators.Get(ctx.SubnetID)
+	} else {
+		// If staking is disabled, everyone validates every chain
+		vdrs, ok = m.config.Validators.Get(constants.PrimaryNetworkID)
+	}
+	if !ok {
+		return errUnknownValidators
+	}
+
+	m.chains[ctx.ChainID] = &reputationBenchlist{
+		vdrs:    vdrs,
+		benched: set.Set[ids.NodeID]{},
+	}
+	return nil
+}
+
+func (m *reputationManager) IsBenched(nodeID ids.NodeID, chainID ids.ID) bool {
+	return m.Manager.IsBenched(nodeID, chainID) || m.isReputationBenched(nodeID, chainID)
+}
+
+func (m *reputationManager) GetBenched(nodeID ids.NodeID) []ids.ID {
+	benched := m.Manager.GetBenched(nodeID)
+	if !m.tracker.IsBenched(nodeID) {
+		return benched
+	}
+
+	m.lock.Lock()
+	chainIDs := make([]ids.ID, 0, len(m.chains))
+	for chainID := range m.chains {
+		chainIDs = append(chainIDs, chainID)
+	}
+	m.lock.Unlock()
+
+	benchedChains := set.NewSet[ids.ID](len(benched))
+	benchedChains.Add(benched...)
+	for _, chainID := range chainIDs {
+		if !benchedChains.Contains(chainID) && m.isReputationBenched(nodeID, chainID) {
+			benched = append(benched, chainID)
+		}
+	}
+	return benched
+}
+
+// isReputationBenched returns true if [nodeID] is benched by [m.tracker] and
+// either already counts towards the stake benched on [chainID] or can be
+// benched on [chainID] without the benched stake exceeding the max portion.
+func (m *reputationManager) isReputationBenched(nodeID ids.NodeID, chainID ids.ID) bool {
+	if !m.tracker.IsBenched(nodeID) {
+		return false
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	chain, exists := m.chains[chainID]
+	if !exists {
+		return false
+	}
+	if chain.benched.Contains(nodeID) {
+		return true
+	}
+
+	validatorStake := chain.vdrs.GetWeight(nodeID)
+	if validatorStake == 0 {
+		// Like the benchlist, we only bench validators.
+		return false
+	}
+
+	// Release the stake of the validators that recovered their reputation
+	for benchedID := range chain.benched {
+		if !m.tracker.IsBenched(benchedID) {
+			chain.benched.Remove(benchedID)
+		}
+	}
+
+	// The stake benched by the benchlist and due to reputation share the cap
+	benchedStake := validatorStake
+	for _, vdr := range chain.vdrs.List() {
+		if chain.benched.Contains(vdr.NodeID) || m.Manager.IsBenched(vdr.NodeID, chainID) {
+			benchedStake += vdr.Weight
+		}
+	}
+	if float64(benchedStake) > float64(chain.vdrs.Weight())*m.config.MaxPortion {
+		return false
+	}
+
+	chain.benched.Add(nodeID)
+	return true
+}
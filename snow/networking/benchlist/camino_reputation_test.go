@@ -0,0 +1,117 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package benchlist
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
+	"github.com/ava-labs/avalanchego/snow/validators"
+)
+
+func TestReputationManager(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := reputation.NewTracker(
+		reputation.Config{
+			Enabled:    true,
+			HalfLife:   time.Hour,
+			BenchScore: -2,
+			MaxScore:   1,
+		},
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vdrID0 := ids.GenerateTestNodeID()
+	vdrID1 := ids.GenerateTestNodeID()
+	vdrID2 := ids.GenerateTestNodeID()
+	nonVdrID := ids.GenerateTestNodeID()
+
+	vdrs := validators.NewManager()
+	require.True(vdrs.Add(ctx.SubnetID, validators.NewSet()))
+	require.NoError(validators.Add(vdrs, ctx.SubnetID, vdrID0, nil, ids.Empty, 50))
+	require.NoError(validators.Add(vdrs, ctx.SubnetID, vdrID1, nil, ids.Empty, 50))
+	require.NoError(validators.Add(vdrs, ctx.SubnetID, vdrID2, nil, ids.Empty, 50))
+
+	m := NewReputationManager(NewNoBenchlist(), tracker, &Config{
+		Validators:     vdrs,
+		StakingEnabled: true,
+		MaxPortion:     .5,
+	})
+	require.NoError(m.RegisterChain(ctx))
+
+	m.RegisterResponse(ctx.ChainID, vdrID0)
+	m.RegisterFailure(ctx.ChainID, vdrID0)
+	m.RegisterFailure(ctx.ChainID, vdrID0)
+	require.False(m.IsBenched(vdrID0, ctx.ChainID))
+	require.Empty(m.GetBenched(vdrID0))
+
+	m.RegisterFailure(ctx.ChainID, vdrID0)
+	m.RegisterFailure(ctx.ChainID, vdrID0)
+	require.True(m.IsBenched(vdrID0, ctx.ChainID))
+	require.Equal([]ids.ID{ctx.ChainID}, m.GetBenched(vdrID0))
+
+	// Benching [vdrID1] too would bench 2/3 of the stake
+	for i := 0; i < 3; i++ {
+		m.RegisterFailure(ctx.ChainID, vdrID1)
+	}
+	require.True(tracker.IsBenched(vdrID1))
+	require.False(m.IsBenched(vdrID1, ctx.ChainID))
+	require.Empty(m.GetBenched(vdrID1))
+	require.True(m.IsBenched(vdrID0, ctx.ChainID))
+
+	// Only validators are benched
+	for i := 0; i < 3; i++ {
+		m.RegisterFailure(ctx.ChainID, nonVdrID)
+	}
+	require.True(tracker.IsBenched(nonVdrID))
+	require.False(m.IsBenched(nonVdrID, ctx.ChainID))
+
+	// Nodes aren't benched on unknown chains
+	require.False(m.IsBenched(vdrID0, ids.GenerateTestID()))
+}
+
+func TestReputationManagerNoMaxPortion(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := reputation.NewTracker(
+		reputation.Config{
+			Enabled:    true,
+			HalfLife:   time.Hour,
+			BenchScore: -1,
+			MaxScore:   1,
+		},
+		"",
+		prometheus.NewRegistry(),
+	)
+	require.NoError(err)
+
+	ctx := snow.DefaultConsensusContextTest()
+	vdrID := ids.GenerateTestNodeID()
+	vdrs := validators.NewManager()
+	require.True(vdrs.Add(ctx.SubnetID, validators.NewSet()))
+	require.NoError(validators.Add(vdrs, ctx.SubnetID, vdrID, nil, ids.Empty, 1))
+
+	m := NewReputationManager(NewNoBenchlist(), tracker, &Config{
+		Validators:     vdrs,
+		StakingEnabled: true,
+	})
+	require.NoError(m.RegisterChain(ctx))
+
+	m.RegisterFailure(ctx.ChainID, vdrID)
+	m.RegisterFailure(ctx.ChainID, vdrID)
+	require.True(tracker.IsBenched(vdrID))
+	require.False(m.IsBenched(vdrID, ctx.ChainID))
+	require.Empty(m.GetBenched(vdrID))
+}
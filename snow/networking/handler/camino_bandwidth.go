@@ -0,0 +1,38 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/message"
+)
+
+// meterBandwidth accounts for the bytes of [msg] and returns false if [msg]
+// was dropped because the inbound quota of the subnet is exhausted. Only
+// unrequested messages are dropped; the timeouts of the requests responses
+// belong to have already been cleared.
+func (h *handler) meterBandwidth(msg Message) bool {
+	numBytes := msg.BytesLen()
+	if numBytes == 0 {
+		// Not received from the network
+		return true
+	}
+
+	nodeID := msg.NodeID()
+	op := msg.Op()
+	if message.UnrequestedOps.Contains(op) &&
+		!h.ctx.Bandwidth.AllowReceive(h.ctx.SubnetID, h.ctx.ChainID, nodeID, numBytes) {
+		h.ctx.Log.Debug("dropping message",
+			zap.String("reason", "subnet inbound bandwidth quota exhausted"),
+			zap.Stringer("nodeID", nodeID),
+			zap.Stringer("messageOp", op),
+			zap.Int("numBytes", numBytes),
+		)
+		msg.OnFinishedHandling()
+		return false
+	}
+	h.ctx.Bandwidth.Received(h.ctx.ChainID, nodeID, numBytes)
+	return true
+}
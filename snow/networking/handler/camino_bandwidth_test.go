@@ -0,0 +1,78 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
+)
+
+var _ message.InboundMessage = (*sizedMessage)(nil)
+
+// sizedMessage pretends that a message was received from the network
+type sizedMessage struct {
+	message.InboundMessage
+	bytesLen int
+}
+
+func (m *sizedMessage) BytesLen() int {
+	return m.bytesLen
+}
+
+func TestHandlerMeterBandwidth(t *testing.T) {
+	require := require.New(t)
+
+	tracker, err := bandwidth.NewTracker("", prometheus.NewRegistry())
+	require.NoError(err)
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.Bandwidth = tracker
+	tracker.SetQuota(ctx.SubnetID, bandwidth.Quota{InboundBytesPerSecond: 100})
+	h := &handler{ctx: ctx}
+
+	nodeID := ids.GenerateTestNodeID()
+	newQuery := func() Message {
+		return Message{
+			InboundMessage: &sizedMessage{
+				InboundMessage: message.InboundPushQuery(ctx.ChainID, 1, time.Minute, []byte{1}, nodeID, p2p.EngineType_ENGINE_TYPE_SNOWMAN),
+				bytesLen:       200,
+			},
+		}
+	}
+	newChits := func() Message {
+		return Message{
+			InboundMessage: &sizedMessage{
+				InboundMessage: message.InboundChits(ctx.ChainID, 1, nil, nil, nodeID),
+				bytesLen:       200,
+			},
+		}
+	}
+
+	// The first query exhausts the quota
+	require.True(h.meterBandwidth(newQuery()))
+	require.False(h.meterBandwidth(newQuery()))
+
+	// Responses are never dropped
+	require.True(h.meterBandwidth(newChits()))
+
+	// Internal messages aren't accounted
+	require.True(h.meterBandwidth(Message{
+		InboundMessage: message.InternalGetFailed(nodeID, ctx.ChainID, 1, p2p.EngineType_ENGINE_TYPE_SNOWMAN),
+	}))
+
+	require.Equal(bandwidth.Usage{
+		InboundBytes:        400,
+		InboundMessages:     2,
+		DroppedInboundBytes: 200,
+	}, tracker.Chains()[ctx.ChainID])
+}
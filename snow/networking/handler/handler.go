@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -23,6 +33,7 @@ import (
 	"github.com/ava-labs/avalanchego/proto/pb/p2p"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/networking/reputation"
 	"github.com/ava-labs/avalanchego/snow/networking/tracker"
 	"github.com/ava-labs/avalanchego/snow/networking/worker"
 	"github.com/ava-labs/avalanchego/snow/validators"
@@ -268,6 +279,10 @@ func (h *handler) HealthCheck(ctx context.Context) (interface{}, error) {
 
 // Push the message onto the handler's queue
 func (h *handler) Push(ctx context.Context, msg Message) {
+	if !h.meterBandwidth(msg) {
+		return
+	}
+
 	switch msg.Op() {
 	case message.AppRequestOp, message.AppRequestFailedOp, message.AppResponseOp, message.AppGossipOp,
 		message.CrossChainAppRequestOp, message.CrossChainAppRequestFailedOp, message.CrossChainAppResponseOp:
@@ -544,6 +559,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.Uint32("requestID", msg.RequestId),
 				zap.String("field", "Heights"),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return engine.GetAcceptedStateSummaryFailed(ctx, nodeID, msg.RequestId)
 		}
 
@@ -564,6 +580,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "SummaryIDs"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return engine.GetAcceptedStateSummaryFailed(ctx, nodeID, msg.RequestId)
 		}
 
@@ -587,6 +604,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "ContainerIDs"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return engine.GetAcceptedFrontierFailed(ctx, nodeID, msg.RequestId)
 		}
 
@@ -605,6 +623,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "ContainerIDs"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return nil
 		}
 
@@ -620,6 +639,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "ContainerIDs"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return engine.GetAcceptedFailed(ctx, nodeID, msg.RequestId)
 		}
 
@@ -638,6 +658,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "ContainerID"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return nil
 		}
 
@@ -659,6 +680,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "ContainerID"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return nil
 		}
 
@@ -683,6 +705,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "ContainerID"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return nil
 		}
 
@@ -698,6 +721,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "PreferredContainerIDs"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return engine.QueryFailed(ctx, nodeID, msg.RequestId)
 		}
 
@@ -710,6 +734,7 @@ func (h *handler) handleSyncMsg(ctx context.Context, msg Message) error {
 				zap.String("field", "AcceptedContainerIDs"),
 				zap.Error(err),
 			)
+			h.ctx.Reputation.Report(nodeID, reputation.InvalidMessage)
 			return engine.QueryFailed(ctx, nodeID, msg.RequestId)
 		}
 
@@ -977,6 +1002,7 @@ func (h *handler) closeDispatcher(ctx context.Context) {
 // Note: shutdown is only called after all message dispatchers have exited.
 func (h *handler) shutdown(ctx context.Context) {
 	defer func() {
+		h.ctx.Bandwidth.ChainStopped(h.ctx.ChainID)
 		if h.onStopped != nil {
 			go h.onStopped()
 		}
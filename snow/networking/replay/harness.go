@@ -0,0 +1,487 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/common/queue"
+	"github.com/ava-labs/avalanchego/snow/engine/common/tracker"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/networking/benchlist"
+	"github.com/ava-labs/avalanchego/snow/networking/handler"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/snow/networking/sender"
+	"github.com/ava-labs/avalanchego/snow/networking/timeout"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/math/meter"
+	"github.com/ava-labs/avalanchego/utils/resource"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer"
+	"github.com/ava-labs/avalanchego/version"
+
+	smcon "github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	aveng "github.com/ava-labs/avalanchego/snow/engine/avalanche"
+	avbootstrap "github.com/ava-labs/avalanchego/snow/engine/avalanche/bootstrap"
+	avagetter "github.com/ava-labs/avalanchego/snow/engine/avalanche/getter"
+	smeng "github.com/ava-labs/avalanchego/snow/engine/snowman"
+	smbootstrap "github.com/ava-labs/avalanchego/snow/engine/snowman/bootstrap"
+	snowgetter "github.com/ava-labs/avalanchego/snow/engine/snowman/getter"
+	timetracker "github.com/ava-labs/avalanchego/snow/networking/tracker"
+)
+
+const (
+	// Requests of the replayed engines never time out, so that replaying a
+	// capture doesn't depend on how long handling the messages takes.
+	harnessRequestTimeout = 365 * 24 * time.Hour
+	// The engines gossip as rarely as possible for the same reason.
+	harnessGossipFrequency = harnessRequestTimeout
+	// App messages are handled one after another.
+	harnessAppConcurrency = 1
+
+	harnessCloseTimeout = 10 * time.Second
+
+	harnessAncestorsMaxContainers = 2000
+)
+
+var (
+	_ router.InboundHandler = (*Harness)(nil)
+
+	// The bootstrapping prefixes of the chains manager, so that the
+	// database of a chain of a node can be replayed
+	vertexBootstrappingDBPrefix = []byte("vertex_bs")
+	txBootstrappingDBPrefix     = []byte("tx_bs")
+	blockBootstrappingDBPrefix  = []byte("block_bs")
+	bootstrappingDBPrefix       = []byte("bs")
+)
+
+type HarnessConfig struct {
+	Log logging.Logger
+	// NodeID is the node whose capture is replayed
+	NodeID ids.NodeID
+	// Creator creates the messages sent by the engines
+	Creator message.Creator
+	// Network receives the messages sent by the engines
+	Network *Network
+	// Validators are the validators the engines sample for their queries
+	Validators validators.Set
+	// Params are the consensus parameters of the engines
+	Params avalanche.Parameters
+}
+
+// SnowmanChain is a chain run by the snowman engine
+type SnowmanChain struct {
+	Ctx *snow.ConsensusContext
+	// VM must be initialized with the sender returned by [Harness.NewSender]
+	// for [Ctx] as its app sender
+	VM block.ChainVM
+	// DB holds the bootstrapping jobs of the chain
+	DB database.Database
+	// MsgChan is the channel [VM] was initialized with
+	MsgChan <-chan common.Message
+}
+
+// AvalancheChain is a chain run by the avalanche engine until it is
+// linearized, and by the snowman engine afterwards
+type AvalancheChain struct {
+	Ctx *snow.ConsensusContext
+	// VM must be initialized with the snowman sender returned by
+	// [Harness.NewSender] for [Ctx] as its app sender
+	VM      vertex.LinearizableVM
+	Manager vertex.Manager
+	// BlockVM is the VM run by the snowman engine after the linearization
+	BlockVM block.ChainVM
+	// DB holds the bootstrapping jobs of the chain
+	DB database.Database
+	// MsgChan is the channel [VM] was initialized with
+	MsgChan <-chan common.Message
+}
+
+// Harness runs the handlers and engines of the replayed chains. Like on a
+// node, the replayed messages are routed to the handlers by a chain router.
+// The messages sent by the engines are passed to a [Network].
+//
+// The request IDs of the engines start at 0, so the responses of a capture
+// that was started together with its node match the requests of the replayed
+// engines.
+type Harness struct {
+	config          HarnessConfig
+	router          *router.ChainRouter
+	timeoutManager  timeout.Manager
+	resourceTracker timetracker.ResourceTracker
+	subnet          subnets.Subnet
+
+	lock sync.Mutex
+	// Subnets of the replayed chains
+	subnetIDs set.Set[ids.ID]
+	// Nodes that sent a replayed message
+	connected set.Set[ids.NodeID]
+}
+
+// NewHarness returns a harness without chains. Chains are added with
+// [AddSnowmanChain] and [AddAvalancheChain].
+func NewHarness(config HarnessConfig) (*Harness, error) {
+	timeoutManager, err := timeout.NewManager(
+		&timer.AdaptiveTimeoutConfig{
+			InitialTimeout:     harnessRequestTimeout,
+			MinimumTimeout:     harnessRequestTimeout,
+			MaximumTimeout:     harnessRequestTimeout,
+			TimeoutCoefficient: 1,
+			TimeoutHalflife:    time.Minute,
+		},
+		benchlist.NewNoBenchlist(),
+		"",
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	chainRouter := &router.ChainRouter{}
+	err = chainRouter.Initialize(
+		config.NodeID,
+		config.Log,
+		timeoutManager,
+		harnessCloseTimeout,
+		set.Set[ids.ID]{},
+		false,
+		set.Set[ids.ID]{},
+		func(int) {},
+		router.HealthConfig{},
+		"",
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceTracker, err := timetracker.NewResourceTracker(
+		prometheus.NewRegistry(),
+		resource.NoUsage,
+		meter.ContinuousFactory{},
+		time.Second,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	go timeoutManager.Dispatch()
+
+	return &Harness{
+		config:          config,
+		router:          chainRouter,
+		timeoutManager:  timeoutManager,
+		resourceTracker: resourceTracker,
+		subnet:          subnets.New(config.NodeID, subnets.Config{}),
+		connected:       set.Set[ids.NodeID]{},
+	}, nil
+}
+
+// NewSender returns a sender of the messages of the chain [ctx] that are sent
+// by the engine of type [engineType]
+func (h *Harness) NewSender(ctx *snow.ConsensusContext, engineType p2p.EngineType) (common.Sender, error) {
+	return sender.New(
+		ctx,
+		h.config.Creator,
+		h.config.Network,
+		h.router,
+		h.timeoutManager,
+		engineType,
+		h.subnet,
+	)
+}
+
+// AddSnowmanChain starts the handler and the engines of [chain] and routes the
+// replayed messages of the chain to them. The chain starts with its last
+// accepted block, as the harness has no beacons to bootstrap from.
+func (h *Harness) AddSnowmanChain(chain SnowmanChain) error {
+	chainHandler, err := h.newSnowmanHandler(chain)
+	if err != nil {
+		return err
+	}
+	h.start(chainHandler)
+	return nil
+}
+
+func (h *Harness) newSnowmanHandler(chain SnowmanChain) (handler.Handler, error) {
+	ctx := chain.Ctx
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	ctx.State.Set(snow.EngineState{
+		Type:  p2p.EngineType_ENGINE_TYPE_SNOWMAN,
+		State: snow.Initializing,
+	})
+
+	messageSender, err := h.NewSender(ctx, p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	if err != nil {
+		return nil, err
+	}
+
+	chainHandler, commonCfg, err := h.newHandler(ctx, chain.MsgChan, messageSender)
+	if err != nil {
+		return nil, err
+	}
+
+	engine, bootstrapper, err := h.newSnowmanEngine(
+		commonCfg,
+		chain.VM,
+		prefixdb.New(bootstrappingDBPrefix, chain.DB),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	chainHandler.SetEngineManager(&handler.EngineManager{
+		Snowman: &handler.Engine{
+			Bootstrapper: bootstrapper,
+			Consensus:    engine,
+		},
+	})
+	return chainHandler, nil
+}
+
+// AddAvalancheChain starts the handler and the engines of [chain] and routes
+// the replayed messages of the chain to them. The chain starts with its
+// accepted frontier, as the harness has no beacons to bootstrap from.
+func (h *Harness) AddAvalancheChain(chain AvalancheChain) error {
+	chainHandler, err := h.newAvalancheHandler(chain)
+	if err != nil {
+		return err
+	}
+	h.start(chainHandler)
+	return nil
+}
+
+func (h *Harness) newAvalancheHandler(chain AvalancheChain) (handler.Handler, error) {
+	ctx := chain.Ctx
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	ctx.State.Set(snow.EngineState{
+		Type:  p2p.EngineType_ENGINE_TYPE_AVALANCHE,
+		State: snow.Initializing,
+	})
+
+	avalancheMessageSender, err := h.NewSender(ctx, p2p.EngineType_ENGINE_TYPE_AVALANCHE)
+	if err != nil {
+		return nil, err
+	}
+	snowmanMessageSender, err := h.NewSender(ctx, p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	if err != nil {
+		return nil, err
+	}
+
+	vtxBlocker, err := queue.NewWithMissing(prefixdb.New(vertexBootstrappingDBPrefix, chain.DB), "vtx", ctx.AvalancheRegisterer)
+	if err != nil {
+		return nil, err
+	}
+	txBlocker, err := queue.New(prefixdb.New(txBootstrappingDBPrefix, chain.DB), "tx", ctx.AvalancheRegisterer)
+	if err != nil {
+		return nil, err
+	}
+
+	chainHandler, snowmanCommonCfg, err := h.newHandler(ctx, chain.MsgChan, snowmanMessageSender)
+	if err != nil {
+		return nil, err
+	}
+
+	snowmanEngine, snowmanBootstrapper, err := h.newSnowmanEngine(
+		snowmanCommonCfg,
+		chain.BlockVM,
+		prefixdb.New(blockBootstrappingDBPrefix, chain.DB),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	avalancheCommonCfg := snowmanCommonCfg
+	avalancheCommonCfg.Sender = avalancheMessageSender
+	avalancheCommonCfg.SharedCfg = &common.SharedConfig{}
+
+	avaGetHandler, err := avagetter.New(chain.Manager, avalancheCommonCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	avalancheEngine, err := aveng.New(
+		aveng.Config{
+			Ctx:           ctx,
+			AllGetsServer: avaGetHandler,
+			VM:            chain.VM,
+			Manager:       chain.Manager,
+			Sender:        avalancheMessageSender,
+			Validators:    h.config.Validators,
+			Params:        h.config.Params,
+			Consensus:     &avalanche.Topological{},
+		},
+		snowmanEngine.Start,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	avalancheBootstrapper, err := avbootstrap.New(
+		context.TODO(),
+		avbootstrap.Config{
+			Config:        avalancheCommonCfg,
+			AllGetsServer: avaGetHandler,
+			VtxBlocked:    vtxBlocker,
+			TxBlocked:     txBlocker,
+			Manager:       chain.Manager,
+			VM:            chain.VM,
+		},
+		avalancheEngine.Start,
+		snowmanBootstrapper.Start,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	chainHandler.SetEngineManager(&handler.EngineManager{
+		Avalanche: &handler.Engine{
+			Bootstrapper: avalancheBootstrapper,
+			Consensus:    avalancheEngine,
+		},
+		Snowman: &handler.Engine{
+			Bootstrapper: snowmanBootstrapper,
+			Consensus:    snowmanEngine,
+		},
+	})
+	return chainHandler, nil
+}
+
+// newHandler returns the handler of the chain [ctx] and the config its
+// engines have in common. The engines have no beacons, so they start right
+// after they were bootstrapped from the local state.
+func (h *Harness) newHandler(
+	ctx *snow.ConsensusContext,
+	msgChan <-chan common.Message,
+	messageSender common.Sender,
+) (handler.Handler, common.Config, error) {
+	if err := h.timeoutManager.RegisterChain(ctx); err != nil {
+		return nil, common.Config{}, err
+	}
+
+	chainHandler, err := handler.New(
+		ctx,
+		h.config.Validators,
+		msgChan,
+		harnessGossipFrequency,
+		harnessAppConcurrency,
+		h.resourceTracker,
+		validators.UnhandledSubnetConnector,
+		h.subnet,
+	)
+	if err != nil {
+		return nil, common.Config{}, err
+	}
+
+	return chainHandler, common.Config{
+		Ctx:                            ctx,
+		Beacons:                        validators.NewSet(),
+		StartupTracker:                 tracker.NewStartup(tracker.NewPeers(), 0),
+		Sender:                         messageSender,
+		BootstrapTracker:               h.subnet,
+		Timer:                          chainHandler,
+		AncestorsMaxContainersSent:     harnessAncestorsMaxContainers,
+		AncestorsMaxContainersReceived: harnessAncestorsMaxContainers,
+		SharedCfg:                      &common.SharedConfig{},
+	}, nil
+}
+
+func (h *Harness) newSnowmanEngine(
+	commonCfg common.Config,
+	vm block.ChainVM,
+	db database.Database,
+) (smeng.Engine, common.BootstrapableEngine, error) {
+	blocked, err := queue.NewWithMissing(db, "block", commonCfg.Ctx.Registerer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snowGetHandler, err := snowgetter.New(vm, commonCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	engine, err := smeng.New(smeng.Config{
+		Ctx:           commonCfg.Ctx,
+		AllGetsServer: snowGetHandler,
+		VM:            vm,
+		Sender:        commonCfg.Sender,
+		Validators:    h.config.Validators,
+		Params:        h.config.Params.Parameters,
+		Consensus:     &smcon.Topological{},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bootstrapper, err := smbootstrap.New(
+		context.TODO(),
+		smbootstrap.Config{
+			Config:        commonCfg,
+			AllGetsServer: snowGetHandler,
+			Blocked:       blocked,
+			VM:            vm,
+		},
+		engine.Start,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return engine, bootstrapper, nil
+}
+
+// start registers [chainHandler] with the router and starts it
+func (h *Harness) start(chainHandler handler.Handler) {
+	h.lock.Lock()
+	ctx := chainHandler.Context()
+	h.subnetIDs.Add(ctx.SubnetID)
+	h.router.AddChain(context.TODO(), chainHandler)
+	// The chain learns about the nodes that already sent replayed messages
+	for nodeID := range h.connected {
+		h.router.Connected(nodeID, version.CurrentApp, ctx.SubnetID)
+	}
+	h.lock.Unlock()
+
+	chainHandler.Start(context.TODO(), false)
+}
+
+// HandleInbound routes [msg] to the handler of its chain. The sender of [msg]
+// is connected to the replayed chains before its first message is routed.
+func (h *Harness) HandleInbound(ctx context.Context, msg message.InboundMessage) {
+	h.lock.Lock()
+	nodeID := msg.NodeID()
+	if !h.connected.Contains(nodeID) {
+		h.connected.Add(nodeID)
+		for subnetID := range h.subnetIDs {
+			h.router.Connected(nodeID, version.CurrentApp, subnetID)
+		}
+	}
+	h.lock.Unlock()
+
+	h.router.HandleInbound(ctx, msg)
+}
+
+// Shutdown stops the handlers of the replayed chains
+func (h *Harness) Shutdown(ctx context.Context) {
+	h.router.Shutdown(ctx)
+}
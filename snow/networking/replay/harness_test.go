@@ -0,0 +1,261 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowball"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ava-labs/avalanchego/snow/engine/avalanche/vertex"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errUnknownBlock = errors.New("unknown block")
+
+var testHarnessParams = avalanche.Parameters{
+	Parameters: snowball.Parameters{
+		K:                     1,
+		Alpha:                 1,
+		BetaVirtuous:          1,
+		BetaRogue:             1,
+		ConcurrentRepolls:     1,
+		OptimalProcessing:     1,
+		MaxOutstandingItems:   1,
+		MaxItemProcessingTime: time.Minute,
+	},
+	Parents:   2,
+	BatchSize: 1,
+}
+
+func newTestHarness(t *testing.T, nodeID ids.NodeID) (*Harness, message.Creator, *Network) {
+	require := require.New(t)
+
+	creator, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		"",
+		constants.DefaultNetworkCompressionType,
+		10*time.Second,
+	)
+	require.NoError(err)
+
+	vdrs := validators.NewSet()
+	require.NoError(vdrs.Add(nodeID, nil, ids.Empty, 1))
+
+	network := NewNetwork()
+	harness, err := NewHarness(HarnessConfig{
+		Log:        logging.NoLog{},
+		NodeID:     ids.GenerateTestNodeID(),
+		Creator:    creator,
+		Network:    network,
+		Validators: vdrs,
+		Params:     testHarnessParams,
+	})
+	require.NoError(err)
+	t.Cleanup(func() {
+		harness.Shutdown(context.Background())
+	})
+	return harness, creator, network
+}
+
+// writeCapture writes a capture of [msgs] received from [nodeID] and returns
+// its files
+func writeCapture(
+	t *testing.T,
+	creator message.Creator,
+	nodeID ids.NodeID,
+	msgs ...message.OutboundMessage,
+) []string {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	recorder, err := peer.NewFileRecorder(
+		peer.CaptureConfig{
+			Enabled:     true,
+			Dir:         dir,
+			MaxFileSize: constants.DefaultNetworkCaptureMaxFileSize,
+			MaxFiles:    1,
+		},
+		creator,
+		logging.NoLog{},
+	)
+	require.NoError(err)
+	for _, msg := range msgs {
+		inMsg, err := creator.Parse(msg.Bytes(), nodeID, func() {})
+		require.NoError(err)
+		recorder.RecordInbound(nodeID, inMsg, msg.Bytes())
+	}
+	require.NoError(recorder.Close())
+
+	files, err := peer.CaptureFiles(dir)
+	require.NoError(err)
+	return files
+}
+
+func TestHarnessSnowman(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	harness, creator, network := newTestHarness(t, nodeID)
+
+	genesis := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		BytesV: []byte{0},
+	}
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: genesis.ID(),
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blks := map[ids.ID]*snowman.TestBlock{
+		genesis.ID(): genesis,
+		blk.ID():     blk,
+	}
+
+	vm := &block.TestVM{TestVM: common.TestVM{T: t}}
+	vm.LastAcceptedF = func(context.Context) (ids.ID, error) {
+		return genesis.ID(), nil
+	}
+	vm.GetBlockF = func(_ context.Context, blkID ids.ID) (snowman.Block, error) {
+		b, ok := blks[blkID]
+		if !ok {
+			return nil, errUnknownBlock
+		}
+		return b, nil
+	}
+	vm.ParseBlockF = func(_ context.Context, b []byte) (snowman.Block, error) {
+		for _, blk := range blks {
+			if string(blk.Bytes()) == string(b) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	require.NoError(harness.AddSnowmanChain(SnowmanChain{
+		Ctx:     ctx,
+		VM:      vm,
+		DB:      memdb.New(),
+		MsgChan: make(chan common.Message),
+	}))
+
+	// The node pushes [blk] to the replayed engine and votes for it once the
+	// engine queries it
+	pushQuery, err := creator.PushQuery(ctx.ChainID, 7, time.Second, blk.Bytes(), p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	require.NoError(err)
+	chits, err := creator.Chits(ctx.ChainID, 1, []ids.ID{blk.ID()}, []ids.ID{blk.ID()})
+	require.NoError(err)
+	files := writeCapture(t, creator, nodeID, pushQuery, chits)
+
+	replayer := New(Config{}, creator, harness)
+	require.NoError(replayer.ReplayFiles(context.Background(), files))
+	require.Equal(Stats{Replayed: 2}, replayer.Stats())
+
+	ctx.Lock.Lock()
+	require.Equal(choices.Accepted, blk.Status())
+	ctx.Lock.Unlock()
+
+	sent := network.Sent()
+	require.Len(sent, 2)
+	require.Equal(message.ChitsOp, sent[0].Op)
+	require.Equal(message.PullQueryOp, sent[1].Op)
+	for _, s := range sent {
+		require.True(s.NodeIDs.Contains(nodeID))
+	}
+	query, err := creator.Parse(sent[1].Bytes, nodeID, func() {})
+	require.NoError(err)
+	requestID, ok := message.GetRequestID(query.Message())
+	require.True(ok)
+	require.Equal(uint32(1), requestID)
+}
+
+func TestHarnessAvalanche(t *testing.T) {
+	require := require.New(t)
+
+	nodeID := ids.GenerateTestNodeID()
+	harness, creator, network := newTestHarness(t, nodeID)
+
+	vtx := &avalanche.TestVertex{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Accepted,
+		},
+		BytesV: []byte{0},
+	}
+
+	manager := vertex.NewTestManager(t)
+	manager.StopVertexAcceptedF = func(context.Context) (bool, error) {
+		return false, nil
+	}
+	manager.EdgeF = func(context.Context) []ids.ID {
+		return []ids.ID{vtx.ID()}
+	}
+	manager.GetVtxF = func(_ context.Context, vtxID ids.ID) (avalanche.Vertex, error) {
+		if vtxID != vtx.ID() {
+			return nil, errUnknownBlock
+		}
+		return vtx, nil
+	}
+
+	vm := &vertex.TestVM{TestVM: block.TestVM{TestVM: common.TestVM{T: t}}}
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	require.NoError(harness.AddAvalancheChain(AvalancheChain{
+		Ctx:     ctx,
+		VM:      vm,
+		Manager: manager,
+		BlockVM: &block.TestVM{TestVM: common.TestVM{T: t}},
+		DB:      memdb.New(),
+		MsgChan: make(chan common.Message),
+	}))
+
+	pullQuery, err := creator.PullQuery(ctx.ChainID, 7, time.Second, vtx.ID(), p2p.EngineType_ENGINE_TYPE_AVALANCHE)
+	require.NoError(err)
+	files := writeCapture(t, creator, nodeID, pullQuery)
+
+	replayer := New(Config{}, creator, harness)
+	require.NoError(replayer.ReplayFiles(context.Background(), files))
+	require.Equal(Stats{Replayed: 1}, replayer.Stats())
+	require.Equal(p2p.EngineType_ENGINE_TYPE_AVALANCHE, ctx.State.Get().Type)
+
+	sent := network.Sent()
+	require.Len(sent, 1)
+	require.Equal(message.ChitsOp, sent[0].Op)
+	require.True(sent[0].NodeIDs.Contains(nodeID))
+
+	chits, err := creator.Parse(sent[0].Bytes, nodeID, func() {})
+	require.NoError(err)
+	requestID, ok := message.GetRequestID(chits.Message())
+	require.True(ok)
+	require.Equal(uint32(7), requestID)
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow/networking/sender"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var _ sender.ExternalSender = (*Network)(nil)
+
+// Sent is a message an engine sent while a capture was replayed
+type Sent struct {
+	Op message.Op
+	// NodeIDs are the nodes the message was sent to, empty if the message was
+	// gossiped
+	NodeIDs set.Set[ids.NodeID]
+	Bytes   []byte
+}
+
+// Network is the network the engines send their messages to while a capture
+// is replayed. Instead of sending the messages, it keeps them, so that they
+// can be compared to the outbound messages of the capture.
+type Network struct {
+	lock sync.Mutex
+	sent []Sent
+}
+
+func NewNetwork() *Network {
+	return &Network{}
+}
+
+func (n *Network) Send(
+	msg message.OutboundMessage,
+	nodeIDs set.Set[ids.NodeID],
+	_ ids.ID,
+	_ subnets.Allower,
+) set.Set[ids.NodeID] {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.sent = append(n.sent, Sent{
+		Op:      msg.Op(),
+		NodeIDs: nodeIDs,
+		Bytes:   msg.Bytes(),
+	})
+	return nodeIDs
+}
+
+func (n *Network) Gossip(
+	msg message.OutboundMessage,
+	_ ids.ID,
+	_ int,
+	_ int,
+	_ int,
+	_ subnets.Allower,
+) set.Set[ids.NodeID] {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	n.sent = append(n.sent, Sent{
+		Op:    msg.Op(),
+		Bytes: msg.Bytes(),
+	})
+	return nil
+}
+
+// Sent returns the messages sent so far and forgets them
+func (n *Network) Sent() []Sent {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	sent := n.sent
+	n.sent = nil
+	return sent
+}
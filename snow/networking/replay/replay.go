@@ -0,0 +1,151 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+var _ message.InboundMessage = (*replayedMessage)(nil)
+
+type Config struct {
+	// ChainIDs are the chains whose messages are replayed. If empty, the
+	// messages of all chains are replayed.
+	ChainIDs set.Set[ids.ID]
+	// Clock, if not nil, is set to the time a message was captured at before
+	// the message is replayed
+	Clock *mockable.Clock
+}
+
+type Stats struct {
+	// Replayed is the number of inbound messages passed to the handler
+	Replayed int `json:"replayed"`
+	// Skipped is the number of captured messages that weren't replayed,
+	// because they were outbound, not sent to a replayed chain or handled by
+	// the network itself
+	Skipped int `json:"skipped"`
+	// Invalid is the number of inbound messages that couldn't be parsed
+	Invalid int `json:"invalid"`
+}
+
+// Replayer feeds the inbound messages of a capture to a handler
+type Replayer struct {
+	config  Config
+	creator message.Creator
+	handler router.InboundHandler
+	stats   Stats
+}
+
+// New returns a replayer that parses the captured messages with [creator] and
+// passes them to [handler]. [handler] is usually a [Harness] that runs the
+// engines of the replayed chains.
+func New(config Config, creator message.Creator, handler router.InboundHandler) *Replayer {
+	return &Replayer{
+		config:  config,
+		creator: creator,
+		handler: handler,
+	}
+}
+
+// ReplayFiles replays the capture files at [paths] in the given order
+func (r *Replayer) ReplayFiles(ctx context.Context, paths []string) error {
+	for _, path := range paths {
+		if err := r.replayFile(ctx, path); err != nil {
+			return fmt.Errorf("couldn't replay %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func (r *Replayer) replayFile(ctx context.Context, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader, err := peer.NewCaptureReader(f)
+	if err != nil {
+		return err
+	}
+	return r.Replay(ctx, reader)
+}
+
+// Replay passes the inbound messages read from [reader] to the handler in the
+// order they were received. The next message is only passed to the handler
+// after the previous message finished handling, so that replaying a capture is
+// deterministic.
+func (r *Replayer) Replay(ctx context.Context, reader *peer.CaptureReader) error {
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if record.Direction != peer.Inbound ||
+			record.ChainID == ids.Empty ||
+			(r.config.ChainIDs.Len() > 0 && !r.config.ChainIDs.Contains(record.ChainID)) {
+			r.stats.Skipped++
+			continue
+		}
+
+		if err := r.replay(ctx, record); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Replayer) replay(ctx context.Context, record *peer.Record) error {
+	finished := make(chan struct{})
+	msg, err := r.creator.Parse(record.Bytes, record.NodeID, func() {
+		close(finished)
+	})
+	if err != nil {
+		r.stats.Invalid++
+		return nil
+	}
+
+	if r.config.Clock != nil {
+		r.config.Clock.Set(record.Timestamp)
+	}
+	r.stats.Replayed++
+	r.handler.HandleInbound(ctx, &replayedMessage{InboundMessage: msg})
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns the number of messages replayed so far
+func (r *Replayer) Stats() Stats {
+	return r.stats
+}
+
+// replayedMessage never expires, so that replayed messages aren't dropped
+// because of the time that passed since they were captured
+type replayedMessage struct {
+	message.InboundMessage
+}
+
+func (*replayedMessage) Expiration() time.Time {
+	return mockable.MaxTime
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/proto/pb/p2p"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+func TestReplayer(t *testing.T) {
+	require := require.New(t)
+
+	creator, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		"",
+		constants.DefaultNetworkCompressionType,
+		10*time.Second,
+	)
+	require.NoError(err)
+
+	dir := t.TempDir()
+	recorder, err := peer.NewFileRecorder(
+		peer.CaptureConfig{
+			Enabled:     true,
+			Dir:         dir,
+			MaxFileSize: constants.DefaultNetworkCaptureMaxFileSize,
+			MaxFiles:    1,
+		},
+		creator,
+		logging.NoLog{},
+	)
+	require.NoError(err)
+
+	var (
+		nodeID       = ids.GenerateTestNodeID()
+		chainID      = ids.GenerateTestID()
+		otherChainID = ids.GenerateTestID()
+	)
+	recordInbound := func(msg message.OutboundMessage) {
+		inMsg, err := creator.Parse(msg.Bytes(), nodeID, func() {})
+		require.NoError(err)
+		recorder.RecordInbound(nodeID, inMsg, msg.Bytes())
+	}
+
+	ping, err := creator.Ping()
+	require.NoError(err)
+	recordInbound(ping)
+
+	pullQuery, err := creator.PullQuery(chainID, 1, time.Second, ids.GenerateTestID(), p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	require.NoError(err)
+	recordInbound(pullQuery)
+	recorder.RecordOutbound(nodeID, pullQuery)
+
+	otherPullQuery, err := creator.PullQuery(otherChainID, 2, time.Second, ids.GenerateTestID(), p2p.EngineType_ENGINE_TYPE_SNOWMAN)
+	require.NoError(err)
+	recordInbound(otherPullQuery)
+
+	chits, err := creator.Chits(chainID, 3, []ids.ID{ids.GenerateTestID()}, nil)
+	require.NoError(err)
+	recordInbound(chits)
+	require.NoError(recorder.Close())
+
+	files, err := peer.CaptureFiles(dir)
+	require.NoError(err)
+
+	var (
+		clock    mockable.Clock
+		replayed []message.Op
+	)
+	handler := router.InboundHandlerFunc(func(_ context.Context, msg message.InboundMessage) {
+		require.Equal(nodeID, msg.NodeID())
+		require.Equal(mockable.MaxTime, msg.Expiration())
+		replayed = append(replayed, msg.Op())
+
+		// Messages are handled asynchronously by chain handlers
+		go msg.OnFinishedHandling()
+	})
+
+	replayer := New(
+		Config{
+			ChainIDs: set.Set[ids.ID]{chainID: struct{}{}},
+			Clock:    &clock,
+		},
+		creator,
+		handler,
+	)
+	require.NoError(replayer.ReplayFiles(context.Background(), files))
+	require.Equal([]message.Op{message.PullQueryOp, message.ChitsOp}, replayed)
+	require.Equal(
+		Stats{
+			Replayed: 2,
+			Skipped:  3,
+		},
+		replayer.Stats(),
+	)
+	require.NotEqual(time.Time{}, clock.Time())
+}
+
+func TestReplayerCanceled(t *testing.T) {
+	require := require.New(t)
+
+	creator, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		"",
+		constants.DefaultNetworkCompressionType,
+		10*time.Second,
+	)
+	require.NoError(err)
+
+	dir := t.TempDir()
+	recorder, err := peer.NewFileRecorder(
+		peer.CaptureConfig{
+			Enabled:     true,
+			Dir:         dir,
+			MaxFileSize: constants.DefaultNetworkCaptureMaxFileSize,
+			MaxFiles:    1,
+		},
+		creator,
+		logging.NoLog{},
+	)
+	require.NoError(err)
+
+	nodeID := ids.GenerateTestNodeID()
+	chits, err := creator.Chits(ids.GenerateTestID(), 1, []ids.ID{ids.GenerateTestID()}, nil)
+	require.NoError(err)
+	inMsg, err := creator.Parse(chits.Bytes(), nodeID, func() {})
+	require.NoError(err)
+	recorder.RecordInbound(nodeID, inMsg, chits.Bytes())
+	require.NoError(recorder.Close())
+
+	files, err := peer.CaptureFiles(dir)
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// The handler never finishes handling the message
+	handler := router.InboundHandlerFunc(func(context.Context, message.InboundMessage) {
+		cancel()
+	})
+
+	replayer := New(Config{}, creator, handler)
+	require.ErrorIs(replayer.ReplayFiles(ctx, files), context.Canceled)
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+type metrics struct {
+	events *prometheus.CounterVec
+}
+
+func newMetrics(
+	namespace string,
+	registerer prometheus.Registerer,
+	numTracked func() float64,
+	numBenched func() float64,
+) (*metrics, error) {
+	m := &metrics{
+		events: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "events",
+				Help:      "Number of reported events affecting the reputation of peers",
+			},
+			[]string{"event"},
+		),
+	}
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.events),
+		registerer.Register(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "tracked",
+				Help:      "Number of peers with a non-zero reputation score",
+			},
+			numTracked,
+		)),
+		registerer.Register(prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "benched",
+				Help:      "Number of peers benched due to their reputation score",
+			},
+			numBenched,
+		)),
+	)
+	return m, errs.Err
+}
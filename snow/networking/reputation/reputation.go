@@ -0,0 +1,286 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// minTrackedScore is the absolute score below which a node is forgotten
+const minTrackedScore = .01
+
+var (
+	_ Tracker = (*tracker)(nil)
+	_ Tracker = noTracker{}
+)
+
+// Event is an observation about the behaviour of a peer.
+type Event int
+
+const (
+	// Response is reported when a peer answered a request in time.
+	Response Event = iota
+	// Timeout is reported when a request to a peer timed out.
+	Timeout
+	// Unrequested is reported when a peer sent a response to a request that
+	// was never sent or that already timed out.
+	Unrequested
+	// InvalidMessage is reported when a peer sent a message that couldn't be
+	// parsed or that contained invalid fields.
+	InvalidMessage
+	// InvalidContainer is reported when a peer sent a container that couldn't
+	// be parsed or that wasn't the requested one.
+	InvalidContainer
+	// Throttled is reported when a peer used up its own inbound message
+	// allocations and has to wait before its messages are read.
+	Throttled
+
+	numEvents
+)
+
+// eventScores are the amounts the score of a peer changes by per event
+var eventScores = [numEvents]float64{
+	Response:         1,
+	Timeout:          -1,
+	Unrequested:      -.5,
+	InvalidMessage:   -5,
+	InvalidContainer: -10,
+	Throttled:        -1,
+}
+
+func (e Event) String() string {
+	switch e {
+	case Response:
+		return "response"
+	case Timeout:
+		return "timeout"
+	case Unrequested:
+		return "unrequested"
+	case InvalidMessage:
+		return "invalid_message"
+	case InvalidContainer:
+		return "invalid_container"
+	case Throttled:
+		return "throttled"
+	default:
+		return "unknown"
+	}
+}
+
+// Reporter receives observations about the behaviour of peers
+type Reporter interface {
+	// Report that [nodeID] caused [event]
+	Report(nodeID ids.NodeID, event Event)
+}
+
+// Tracker accumulates the events reported about peers into scores that decay
+// towards 0 over time.
+type Tracker interface {
+	Reporter
+
+	// Score returns the current score of [nodeID]. Nodes without reported
+	// events have a score of 0.
+	Score(nodeID ids.NodeID) float64
+
+	// Weight returns the factor in [0, 1] the chance of [nodeID] to be chosen
+	// as a peer should be multiplied with. Nodes without a negative score have
+	// a weight of 1, benched nodes have a weight of 0.
+	//
+	// Weights bias the choice of the peers containers are fetched from. They
+	// don't influence the validators sampled for consensus polls, which stay
+	// weighted by stake only, as consensus safety relies on the stake
+	// weights. A bad reputation only affects polls by benching the node.
+	Weight(nodeID ids.NodeID) float64
+
+	// IsBenched returns true if the score of [nodeID] dropped to the bench
+	// score, so that requests to it should immediately fail.
+	IsBenched(nodeID ids.NodeID) bool
+}
+
+type Config struct {
+	// Enabled is false if every node should be considered to be in good
+	// standing
+	Enabled bool `json:"enabled"`
+	// HalfLife is the time it takes for a score to decay to half its value
+	HalfLife time.Duration `json:"halfLife"`
+	// BenchScore is the negative score at or below which a node is benched
+	BenchScore float64 `json:"benchScore"`
+	// MaxScore is the max score a node can build up by behaving well
+	MaxScore float64 `json:"maxScore"`
+}
+
+type score struct {
+	value      float64
+	lastUpdate time.Time
+}
+
+type tracker struct {
+	config  Config
+	clock   mockable.Clock
+	metrics *metrics
+
+	lock sync.Mutex
+	// node ID --> score of the node as of its last update
+	scores    map[ids.NodeID]*score
+	lastPrune time.Time
+}
+
+// NewTracker returns a tracker that registers its metrics under [namespace]
+func NewTracker(
+	config Config,
+	namespace string,
+	registerer prometheus.Registerer,
+) (Tracker, error) {
+	t := &tracker{
+		config: config,
+		scores: make(map[ids.NodeID]*score),
+	}
+	t.lastPrune = t.clock.Time()
+
+	var err error
+	t.metrics, err = newMetrics(namespace, registerer, t.numTracked, t.numBenched)
+	return t, err
+}
+
+func (t *tracker) Report(nodeID ids.NodeID, event Event) {
+	t.metrics.events.WithLabelValues(event.String()).Inc()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	s, ok := t.scores[nodeID]
+	if !ok {
+		s = &score{}
+		t.scores[nodeID] = s
+	}
+	s.value = math.Min(t.decayed(s, now)+eventScores[event], t.config.MaxScore)
+	s.lastUpdate = now
+
+	t.prune(now)
+}
+
+func (t *tracker) Score(nodeID ids.NodeID) float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.score(nodeID, t.clock.Time())
+}
+
+func (t *tracker) Weight(nodeID ids.NodeID) float64 {
+	score := t.Score(nodeID)
+	switch {
+	case score >= 0:
+		return 1
+	case score <= t.config.BenchScore:
+		return 0
+	default:
+		return 1 - score/t.config.BenchScore
+	}
+}
+
+func (t *tracker) IsBenched(nodeID ids.NodeID) bool {
+	return t.Score(nodeID) <= t.config.BenchScore
+}
+
+// Assumes [t.lock] is held
+func (t *tracker) score(nodeID ids.NodeID, now time.Time) float64 {
+	s, ok := t.scores[nodeID]
+	if !ok {
+		return 0
+	}
+	return t.decayed(s, now)
+}
+
+func (t *tracker) decayed(s *score, now time.Time) float64 {
+	if t.config.HalfLife <= 0 {
+		return s.value
+	}
+	elapsed := now.Sub(s.lastUpdate)
+	return s.value * math.Exp2(-float64(elapsed)/float64(t.config.HalfLife))
+}
+
+// prune forgets the nodes whose score decayed to about 0. It iterates over
+// the scores at most once per half-life.
+//
+// Assumes [t.lock] is held
+func (t *tracker) prune(now time.Time) {
+	if now.Sub(t.lastPrune) < t.config.HalfLife {
+		return
+	}
+	t.lastPrune = now
+
+	for nodeID, s := range t.scores {
+		if math.Abs(t.decayed(s, now)) < minTrackedScore {
+			delete(t.scores, nodeID)
+		}
+	}
+}
+
+func (t *tracker) numTracked() float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return float64(len(t.scores))
+}
+
+func (t *tracker) numBenched() float64 {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.clock.Time()
+	numBenched := 0
+	for _, s := range t.scores {
+		if t.decayed(s, now) <= t.config.BenchScore {
+			numBenched++
+		}
+	}
+	return float64(numBenched)
+}
+
+// Best returns a node of [nodeIDs] with the highest weight in [tracker].
+// Returns false if [nodeIDs] is empty.
+func Best(tracker Tracker, nodeIDs set.Set[ids.NodeID]) (ids.NodeID, bool) {
+	var (
+		best       ids.NodeID
+		bestWeight = -1.
+	)
+	for nodeID := range nodeIDs {
+		if weight := tracker.Weight(nodeID); weight > bestWeight {
+			best = nodeID
+			bestWeight = weight
+		}
+	}
+	return best, bestWeight >= 0
+}
+
+type noTracker struct{}
+
+// NewNoTracker returns a tracker that ignores all events and considers every
+// node to be in good standing
+func NewNoTracker() Tracker {
+	return noTracker{}
+}
+
+func (noTracker) Report(ids.NodeID, Event) {}
+
+func (noTracker) Score(ids.NodeID) float64 {
+	return 0
+}
+
+func (noTracker) Weight(ids.NodeID) float64 {
+	return 1
+}
+
+func (noTracker) IsBenched(ids.NodeID) bool {
+	return false
+}
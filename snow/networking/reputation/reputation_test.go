@@ -0,0 +1,135 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var testConfig = Config{
+	Enabled:    true,
+	HalfLife:   time.Minute,
+	BenchScore: -10,
+	MaxScore:   5,
+}
+
+func newTestTracker(t *testing.T) *tracker {
+	tr, err := NewTracker(testConfig, "", prometheus.NewRegistry())
+	require.NoError(t, err)
+	return tr.(*tracker)
+}
+
+func TestTrackerScore(t *testing.T) {
+	require := require.New(t)
+
+	tr := newTestTracker(t)
+	now := time.Unix(1, 0)
+	tr.clock.Set(now)
+
+	nodeID := ids.GenerateTestNodeID()
+	require.Zero(tr.Score(nodeID))
+
+	tr.Report(nodeID, Timeout)
+	tr.Report(nodeID, InvalidMessage)
+	require.Equal(-6., tr.Score(nodeID))
+
+	// Scores decay towards 0
+	tr.clock.Set(now.Add(testConfig.HalfLife))
+	require.Equal(-3., tr.Score(nodeID))
+
+	// Good behaviour can't build up a score above the max score
+	for i := 0; i < 10; i++ {
+		tr.Report(nodeID, Response)
+	}
+	require.Equal(testConfig.MaxScore, tr.Score(nodeID))
+}
+
+func TestTrackerWeight(t *testing.T) {
+	require := require.New(t)
+
+	tr := newTestTracker(t)
+	tr.clock.Set(time.Unix(1, 0))
+
+	nodeID := ids.GenerateTestNodeID()
+	tr.Report(nodeID, Response)
+	require.Equal(1., tr.Weight(nodeID))
+	require.False(tr.IsBenched(nodeID))
+
+	// 1 + -6 = -5, half of the bench score
+	tr.Report(nodeID, InvalidMessage)
+	tr.Report(nodeID, Timeout)
+	require.Equal(.5, tr.Weight(nodeID))
+	require.False(tr.IsBenched(nodeID))
+
+	tr.Report(nodeID, InvalidMessage)
+	require.Zero(tr.Weight(nodeID))
+	require.True(tr.IsBenched(nodeID))
+}
+
+func TestTrackerPrune(t *testing.T) {
+	require := require.New(t)
+
+	tr := newTestTracker(t)
+	now := time.Unix(1, 0)
+	tr.clock.Set(now)
+	tr.lastPrune = now
+
+	nodeID0 := ids.GenerateTestNodeID()
+	nodeID1 := ids.GenerateTestNodeID()
+	tr.Report(nodeID0, Timeout)
+	require.Equal(1., tr.numTracked())
+
+	// After 10 half-lives the score of [nodeID0] is below [minTrackedScore]
+	tr.clock.Set(now.Add(10 * testConfig.HalfLife))
+	tr.Report(nodeID1, InvalidContainer)
+	require.Equal(1., tr.numTracked())
+	require.Equal(1., tr.numBenched())
+	require.Zero(tr.Score(nodeID0))
+}
+
+func TestBest(t *testing.T) {
+	require := require.New(t)
+
+	tr := newTestTracker(t)
+	tr.clock.Set(time.Unix(1, 0))
+
+	_, ok := Best(tr, nil)
+	require.False(ok)
+
+	bad := ids.GenerateTestNodeID()
+	worse := ids.GenerateTestNodeID()
+	good := ids.GenerateTestNodeID()
+	tr.Report(bad, Timeout)
+	tr.Report(worse, InvalidMessage)
+
+	nodeIDs := set.Set[ids.NodeID]{}
+	nodeIDs.Add(bad, worse)
+	best, ok := Best(tr, nodeIDs)
+	require.True(ok)
+	require.Equal(bad, best)
+
+	nodeIDs.Add(good)
+	best, ok = Best(tr, nodeIDs)
+	require.True(ok)
+	require.Equal(good, best)
+}
+
+func TestNoTracker(t *testing.T) {
+	require := require.New(t)
+
+	tr := NewNoTracker()
+	nodeID := ids.GenerateTestNodeID()
+	tr.Report(nodeID, InvalidContainer)
+	require.Zero(tr.Score(nodeID))
+	require.Equal(1., tr.Weight(nodeID))
+	require.False(tr.IsBenched(nodeID))
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var _ ExternalSender = (*meteredSender)(nil)
+
+// meteredSender accounts for the bytes a chain sends and drops messages while
+// the outbound quota of its subnet is exhausted. The quota is charged for each
+// node a message was actually sent to. Dropped messages aren't sent to any
+// node, so requests fail as if the nodes were unreachable.
+type meteredSender struct {
+	ctx    *snow.ConsensusContext
+	sender ExternalSender
+}
+
+// NewMeteredSender returns an ExternalSender that meters the traffic [sender]
+// sends for the chain of [ctx].
+func NewMeteredSender(ctx *snow.ConsensusContext, sender ExternalSender) ExternalSender {
+	return &meteredSender{
+		ctx:    ctx,
+		sender: sender,
+	}
+}
+
+func (s *meteredSender) Send(
+	msg message.OutboundMessage,
+	nodeIDs set.Set[ids.NodeID],
+	subnetID ids.ID,
+	allower subnets.Allower,
+) set.Set[ids.NodeID] {
+	numBytes := len(msg.Bytes())
+	if !s.ctx.Bandwidth.AllowSend(subnetID, s.ctx.ChainID, nodeIDs.Len()*numBytes) {
+		return nil
+	}
+	sentTo := s.sender.Send(msg, nodeIDs, subnetID, allower)
+	s.ctx.Bandwidth.Sent(subnetID, s.ctx.ChainID, sentTo, numBytes)
+	return sentTo
+}
+
+func (s *meteredSender) Gossip(
+	msg message.OutboundMessage,
+	subnetID ids.ID,
+	numValidatorsToSend int,
+	numNonValidatorsToSend int,
+	numPeersToSend int,
+	allower subnets.Allower,
+) set.Set[ids.NodeID] {
+	// The recipients of dropped gossip aren't known, so its bytes are only
+	// accounted as dropped once.
+	numBytes := len(msg.Bytes())
+	if !s.ctx.Bandwidth.AllowSend(subnetID, s.ctx.ChainID, numBytes) {
+		return nil
+	}
+	sentTo := s.sender.Gossip(msg, subnetID, numValidatorsToSend, numNonValidatorsToSend, numPeersToSend, allower)
+	s.ctx.Bandwidth.Sent(subnetID, s.ctx.ChainID, sentTo, numBytes)
+	return sentTo
+}
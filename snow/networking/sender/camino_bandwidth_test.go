@@ -0,0 +1,65 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sender
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
+	"github.com/ava-labs/avalanchego/subnets"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+func TestMeteredSender(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+
+	tracker, err := bandwidth.NewTracker("", prometheus.NewRegistry())
+	require.NoError(err)
+	ctx := snow.DefaultConsensusContextTest()
+	ctx.Bandwidth = tracker
+	tracker.SetQuota(ctx.SubnetID, bandwidth.Quota{OutboundBytesPerSecond: 100})
+
+	externalSender := NewMockExternalSender(ctrl)
+	s := NewMeteredSender(ctx, externalSender)
+
+	msg := message.NewMockOutboundMessage(ctrl)
+	msg.EXPECT().Bytes().Return(make([]byte, 60)).AnyTimes()
+
+	nodeID1 := ids.GenerateTestNodeID()
+	nodeID2 := ids.GenerateTestNodeID()
+	nodeIDs := set.Set[ids.NodeID]{nodeID1: {}, nodeID2: {}}
+	allower := subnets.NoOpAllower
+
+	// Only [nodeID1] is connected, so the quota is only charged for it
+	externalSender.EXPECT().Send(msg, nodeIDs, ctx.SubnetID, allower).Return(set.Set[ids.NodeID]{nodeID1: {}})
+	require.Equal(set.Set[ids.NodeID]{nodeID1: {}}, s.Send(msg, nodeIDs, ctx.SubnetID, allower))
+
+	// Gossip isn't dropped because of the number of nodes it may be sent to
+	externalSender.EXPECT().Gossip(msg, ctx.SubnetID, 5, 0, 0, allower).Return(nodeIDs)
+	require.Equal(nodeIDs, s.Gossip(msg, ctx.SubnetID, 5, 0, 0, allower))
+
+	// The quota is exhausted, so nothing is passed to the network
+	require.Empty(s.Send(msg, nodeIDs, ctx.SubnetID, allower))
+	require.Empty(s.Gossip(msg, ctx.SubnetID, 1, 0, 0, allower))
+
+	require.Equal(bandwidth.Usage{
+		OutboundBytes:        180,
+		OutboundMessages:     3,
+		DroppedOutboundBytes: 180,
+	}, tracker.Chains()[ctx.ChainID])
+	require.Equal(bandwidth.Usage{
+		OutboundBytes:    120,
+		OutboundMessages: 2,
+	}, tracker.Peers()[nodeID1])
+}
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -10,6 +20,7 @@ import (
 
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/avalanche"
+	"github.com/ava-labs/avalanchego/snow/networking/bandwidth"
 	"github.com/ava-labs/avalanchego/utils/set"
 )
 
@@ -47,6 +58,10 @@ type Config struct {
 
 	// See comment on [MinPercentConnectedStakeHealthy] in platformvm.Config
 	MinPercentConnectedStakeHealthy float64 `json:"minPercentConnectedStakeHealthy" yaml:"minPercentConnectedStakeHealthy"`
+
+	// BandwidthQuota limits the bytes all chains of this Subnet exchange with
+	// peers, so that one Subnet can't monopolize the uplink of the node.
+	BandwidthQuota bandwidth.Quota `json:"bandwidthQuota" yaml:"bandwidthQuota"`
 }
 
 func (c *Config) Valid() error {
@@ -0,0 +1,159 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/message"
+	"github.com/ava-labs/avalanchego/network/peer"
+	"github.com/ava-labs/avalanchego/snow/networking/replay"
+	"github.com/ava-labs/avalanchego/snow/networking/router"
+	"github.com/ava-labs/avalanchego/utils/compression"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+// Prints the messages of the network capture files written by a node started
+// with --network-capture-enabled.
+//
+// By default the inbound messages are printed in the order the chains of the
+// node received them. The same replayer passes them to the engines of a
+// replay.Harness, which runs the VMs of the replayed chains. With -records,
+// the headers of all captured inbound and outbound records are printed
+// instead.
+func main() {
+	var (
+		dir      string
+		chainIDs string
+		records  bool
+	)
+	flag.StringVar(&dir, "dir", dir, "Path of the network capture directory")
+	flag.StringVar(&chainIDs, "chain-ids", chainIDs, "Comma separated list of the chains whose messages are printed. All chains if empty")
+	flag.BoolVar(&records, "records", records, "Print the headers of all captured records instead of replaying the inbound messages")
+	flag.Parse()
+
+	if dir == "" {
+		fmt.Println("capture directory must be provided")
+		os.Exit(1)
+	}
+
+	chains := set.Set[ids.ID]{}
+	for _, chainIDStr := range strings.Split(chainIDs, ",") {
+		if chainIDStr == "" {
+			continue
+		}
+		chainID, err := ids.FromString(chainIDStr)
+		if err != nil {
+			fmt.Printf("couldn't parse chain ID %q: %s\n", chainIDStr, err)
+			os.Exit(1)
+		}
+		chains.Add(chainID)
+	}
+
+	files, err := peer.CaptureFiles(dir)
+	if err != nil {
+		fmt.Printf("couldn't list capture files: %s\n", err)
+		os.Exit(1)
+	}
+
+	if records {
+		err = printRecords(files, chains)
+	} else {
+		err = printReplay(files, chains)
+	}
+	if err != nil {
+		fmt.Printf("couldn't read capture: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func printRecords(files []string, chains set.Set[ids.ID]) error {
+	for _, path := range files {
+		if err := printFileRecords(path, chains); err != nil {
+			return fmt.Errorf("couldn't read %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func printFileRecords(path string, chains set.Set[ids.ID]) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader, err := peer.NewCaptureReader(f)
+	if err != nil {
+		return err
+	}
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if chains.Len() > 0 && !chains.Contains(record.ChainID) {
+			continue
+		}
+		fmt.Printf("%s %-8s %s %-24s chainID=%s requestID=%d size=%d\n",
+			record.Timestamp.UTC().Format("2006-01-02T15:04:05.000000000Z"),
+			record.Direction,
+			record.NodeID,
+			record.Op,
+			record.ChainID,
+			record.RequestID,
+			len(record.Bytes),
+		)
+	}
+}
+
+func printReplay(files []string, chains set.Set[ids.ID]) error {
+	creator, err := message.NewCreator(
+		logging.NoLog{},
+		prometheus.NewRegistry(),
+		"",
+		compression.TypeNone,
+		constants.DefaultNetworkMaximumInboundTimeout,
+	)
+	if err != nil {
+		return err
+	}
+
+	handler := router.InboundHandlerFunc(func(_ context.Context, msg message.InboundMessage) {
+		defer msg.OnFinishedHandling()
+
+		msgJSON, err := json.Marshal(msg.Message())
+		if err != nil {
+			msgJSON = []byte(err.Error())
+		}
+		fmt.Printf("%s %-24s %s\n", msg.NodeID(), msg.Op(), msgJSON)
+	})
+
+	replayer := replay.New(replay.Config{ChainIDs: chains}, creator, handler)
+	if err := replayer.ReplayFiles(context.Background(), files); err != nil {
+		return err
+	}
+
+	statsJSON, err := json.MarshalIndent(replayer.Stats(), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(statsJSON))
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/leveldb"
+	"github.com/ava-labs/avalanchego/database/pebble"
+	"github.com/ava-labs/avalanchego/snapshot"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+// Restores a node database from a snapshot that was exported with the
+// admin.exportSnapshot API, or verifies the checksum of a snapshot.
+//
+// The database path is the versioned database directory of the node, e.g.
+// ~/.caminogo/db/camino/v1.4.5 for leveldb or
+// ~/.caminogo/db/camino/pebble/v1.4.5 for pebble.
+func main() {
+	var (
+		snapshotPath string
+		dbPath       string
+		dbType       = leveldb.Name
+		verifyOnly   bool
+	)
+	flag.StringVar(&snapshotPath, "snapshot", snapshotPath, "Path of the snapshot file")
+	flag.StringVar(&dbPath, "db-path", dbPath, "Path of the database directory to restore into. Must be empty")
+	flag.StringVar(&dbType, "db-type", dbType, fmt.Sprintf("Database type. Should be one of {%s, %s}", leveldb.Name, pebble.Name))
+	flag.BoolVar(&verifyOnly, "verify-only", verifyOnly, "Only verify the checksum of the snapshot")
+	flag.Parse()
+
+	if snapshotPath == "" {
+		fmt.Println("snapshot path must be provided")
+		os.Exit(1)
+	}
+
+	f, err := os.Open(snapshotPath)
+	if err != nil {
+		fmt.Printf("couldn't open snapshot: %s\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	var summary *snapshot.Summary
+	if verifyOnly {
+		summary, err = snapshot.Read(f, nil)
+	} else {
+		summary, err = restore(f, dbPath, dbType)
+	}
+	if err != nil {
+		fmt.Printf("couldn't read snapshot: %s\n", err)
+		os.Exit(1)
+	}
+
+	summaryJSON, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		fmt.Printf("couldn't marshal snapshot summary: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(summaryJSON))
+}
+
+func restore(f *os.File, dbPath string, dbType string) (*snapshot.Summary, error) {
+	if dbPath == "" {
+		return nil, fmt.Errorf("database path must be provided")
+	}
+
+	var (
+		db  database.Database
+		err error
+	)
+	switch dbType {
+	case leveldb.Name:
+		db, err = leveldb.New(dbPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	case pebble.Name:
+		db, err = pebble.New(dbPath, nil, logging.NoLog{}, "", prometheus.NewRegistry())
+	default:
+		err = fmt.Errorf("db-type was %q but should have been one of {%s, %s}", dbType, leveldb.Name, pebble.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := snapshot.Restore(f, db, snapshot.DBPrefix)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return summary, err
+}
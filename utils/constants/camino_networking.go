@@ -0,0 +1,40 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package constants
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// Outbound Queues
+	//
+	// The queues of all priorities are only limited by the outbound message
+	// throttler by default.
+	DefaultOutboundQueueConsensusMaxBytes  = 0
+	DefaultOutboundQueueAcceptanceMaxBytes = 0
+	DefaultOutboundQueueAppMaxBytes        = 0
+	DefaultOutboundQueueBulkMaxBytes       = 0
+	DefaultOutboundQueueMaxStarvation      = time.Second
+)
+
+const (
+	// Reputation
+	DefaultReputationHalfLife   = 5 * time.Minute
+	DefaultReputationBenchScore = -10.
+	DefaultReputationMaxScore   = 10.
+)
+
+const (
+	// Network Capture
+	DefaultNetworkCaptureMaxFileSize = 64 * units.MiB
+	DefaultNetworkCaptureMaxFiles    = 10
+)
+
+const (
+	// Dynamic IP
+	DefaultPublicIPEndpointsUpdateFreq = 30 * time.Second
+)
@@ -0,0 +1,168 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var _ Updater = (*endpointsUpdater)(nil)
+
+// EndpointsConfig describes where the endpoints advertised in addition to
+// the primary IP come from.
+type EndpointsConfig struct {
+	// Endpoints that are always advertised.
+	Static []ips.IPPort
+	// Resolvers whose result is advertised with the primary port, e.g. one
+	// STUN resolver per address family.
+	Resolvers []Resolver
+	// Advertise the public addresses of the local interfaces with the
+	// primary port.
+	Interfaces bool
+	// Maximum number of endpoints to advertise.
+	MaxEndpoints int
+}
+
+type endpointsUpdater struct {
+	// The primary IP; never advertised twice.
+	primary ips.DynamicIPPort
+	// The endpoints we periodically modify.
+	endpoints ips.DynamicIPPorts
+	config    EndpointsConfig
+	// Returns the addresses of the local interfaces.
+	interfaceAddrs func() ([]net.Addr, error)
+
+	rootCtx       context.Context
+	rootCtxCancel context.CancelFunc
+	doneChan      chan struct{}
+	updateFreq    time.Duration
+}
+
+// NewEndpointsUpdater returns a new Updater that sets [endpoints] to the
+// endpoints described by [config] every [updateFreq].
+func NewEndpointsUpdater(
+	primary ips.DynamicIPPort,
+	endpoints ips.DynamicIPPorts,
+	config EndpointsConfig,
+	updateFreq time.Duration,
+) Updater {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &endpointsUpdater{
+		primary:        primary,
+		endpoints:      endpoints,
+		config:         config,
+		interfaceAddrs: net.InterfaceAddrs,
+		rootCtx:        ctx,
+		rootCtxCancel:  cancel,
+		doneChan:       make(chan struct{}),
+		updateFreq:     updateFreq,
+	}
+}
+
+// Dispatch updates the endpoints right away, then every [u.updateFreq], so
+// interface changes are picked up without a restart.
+func (u *endpointsUpdater) Dispatch(log logging.Logger) {
+	ticker := time.NewTicker(u.updateFreq)
+	defer func() {
+		ticker.Stop()
+		close(u.doneChan)
+	}()
+
+	for {
+		u.update(log)
+
+		select {
+		case <-ticker.C:
+		case <-u.rootCtx.Done():
+			return
+		}
+	}
+}
+
+func (u *endpointsUpdater) Stop() {
+	u.rootCtxCancel()
+	<-u.doneChan
+}
+
+func (u *endpointsUpdater) update(log logging.Logger) {
+	newEndpoints := u.collect(log)
+	if ips.EqualIPPorts(newEndpoints, u.endpoints.IPPorts()) {
+		return
+	}
+	u.endpoints.SetIPPorts(newEndpoints)
+	log.Info("updated advertised endpoints",
+		zap.Stringers("endpoints", newEndpoints),
+	)
+}
+
+// collect returns the deduplicated endpoints in order of preference: static
+// endpoints, resolved endpoints and finally interface addresses.
+func (u *endpointsUpdater) collect(log logging.Logger) []ips.IPPort {
+	primary := u.primary.IPPort()
+	endpoints := make([]ips.IPPort, 0, u.config.MaxEndpoints)
+	add := func(ipPort ips.IPPort) {
+		if len(endpoints) >= u.config.MaxEndpoints ||
+			ipPort.IP == nil || ipPort.IP.IsUnspecified() ||
+			ipPort.Equal(primary) {
+			return
+		}
+		for _, endpoint := range endpoints {
+			if endpoint.Equal(ipPort) {
+				return
+			}
+		}
+		endpoints = append(endpoints, ipPort)
+	}
+
+	for _, ipPort := range u.config.Static {
+		add(ipPort)
+	}
+
+	for _, resolver := range u.config.Resolvers {
+		ctx, cancel := context.WithTimeout(u.rootCtx, ipResolutionTimeout)
+		ip, err := resolver.Resolve(ctx)
+		cancel()
+		if err != nil {
+			log.Debug("couldn't resolve endpoint",
+				zap.Error(err),
+			)
+			continue
+		}
+		add(ips.IPPort{IP: ip, Port: primary.Port})
+	}
+
+	if u.config.Interfaces {
+		addrs, err := u.interfaceAddrs()
+		if err != nil {
+			log.Warn("couldn't list interface addresses",
+				zap.Error(err),
+			)
+			return endpoints
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || !isPublicIP(ipNet.IP) {
+				continue
+			}
+			add(ips.IPPort{IP: ipNet.IP, Port: primary.Port})
+		}
+	}
+	return endpoints
+}
+
+// isPublicIP returns true if [ip] may be reachable from other networks. Private
+// addresses, e.g. of docker bridges, aren't, so they aren't advertised.
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() &&
+		!ip.IsPrivate() &&
+		!ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast()
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/utils/ips"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+func TestEndpointsUpdaterCollect(t *testing.T) {
+	require := require.New(t)
+
+	primary := ips.NewDynamicIPPort(net.IPv4(1, 2, 3, 4), 9651)
+	static := ips.IPPort{IP: net.IPv4(5, 6, 7, 8), Port: 9000}
+	resolvedIPv6 := net.ParseIP("2001:db8::1")
+	interfaceIP := net.IPv4(203, 0, 113, 10)
+
+	updaterIntf := NewEndpointsUpdater(
+		primary,
+		ips.NewDynamicIPPorts(),
+		EndpointsConfig{
+			// The primary IP is never advertised twice
+			Static: []ips.IPPort{static, primary.IPPort()},
+			Resolvers: []Resolver{
+				&mockResolver{onResolve: func(context.Context) (net.IP, error) {
+					return net.IPv4(1, 2, 3, 4), nil
+				}},
+				&mockResolver{onResolve: func(context.Context) (net.IP, error) {
+					return nil, errors.New("unreachable")
+				}},
+				&mockResolver{onResolve: func(context.Context) (net.IP, error) {
+					return resolvedIPv6, nil
+				}},
+			},
+			Interfaces:   true,
+			MaxEndpoints: 3,
+		},
+		time.Minute,
+	)
+	updater := updaterIntf.(*endpointsUpdater)
+	updater.interfaceAddrs = func() ([]net.Addr, error) {
+		return []net.Addr{
+			// Loopback, link-local and private addresses aren't advertised
+			&net.IPNet{IP: net.IPv4(127, 0, 0, 1), Mask: net.CIDRMask(8, 32)},
+			&net.IPNet{IP: net.ParseIP("fe80::1"), Mask: net.CIDRMask(64, 128)},
+			&net.IPNet{IP: net.IPv4(192, 168, 1, 10), Mask: net.CIDRMask(24, 32)},
+			&net.IPNet{IP: net.IPv4(172, 17, 0, 1), Mask: net.CIDRMask(16, 32)},
+			&net.IPNet{IP: net.ParseIP("fd00::1"), Mask: net.CIDRMask(64, 128)},
+			&net.IPNet{IP: interfaceIP, Mask: net.CIDRMask(24, 32)},
+			&net.IPNet{IP: resolvedIPv6, Mask: net.CIDRMask(64, 128)},
+			&net.IPNet{IP: net.IPv4(198, 51, 100, 10), Mask: net.CIDRMask(24, 32)},
+		}, nil
+	}
+
+	require.Equal(
+		[]ips.IPPort{
+			static,
+			{IP: resolvedIPv6, Port: 9651},
+			{IP: interfaceIP, Port: 9651},
+		},
+		updater.collect(logging.NoLog{}),
+	)
+}
+
+func TestEndpointsUpdaterDispatch(t *testing.T) {
+	require := require.New(t)
+
+	primary := ips.NewDynamicIPPort(net.IPv4(1, 2, 3, 4), 9651)
+	endpoints := ips.NewDynamicIPPorts()
+
+	var (
+		lock  sync.Mutex
+		addrs = []net.Addr{&net.IPNet{IP: net.IPv4(203, 0, 113, 1), Mask: net.CIDRMask(8, 32)}}
+	)
+	updaterIntf := NewEndpointsUpdater(
+		primary,
+		endpoints,
+		EndpointsConfig{
+			Interfaces:   true,
+			MaxEndpoints: 4,
+		},
+		time.Millisecond,
+	)
+	updater := updaterIntf.(*endpointsUpdater)
+	updater.interfaceAddrs = func() ([]net.Addr, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return addrs, nil
+	}
+
+	go updater.Dispatch(logging.NoLog{})
+
+	require.Eventually(func() bool {
+		return ips.EqualIPPorts(
+			[]ips.IPPort{{IP: net.IPv4(203, 0, 113, 1), Port: 9651}},
+			endpoints.IPPorts(),
+		)
+	}, 5*time.Second, time.Millisecond)
+
+	// Interface changes are picked up without a restart
+	lock.Lock()
+	addrs = []net.Addr{&net.IPNet{IP: net.IPv4(203, 0, 113, 2), Mask: net.CIDRMask(8, 32)}}
+	lock.Unlock()
+
+	require.Eventually(func() bool {
+		return ips.EqualIPPorts(
+			[]ips.IPPort{{IP: net.IPv4(203, 0, 113, 2), Port: 9651}},
+			endpoints.IPPorts(),
+		)
+	}, 5*time.Second, time.Millisecond)
+
+	updater.Stop()
+}
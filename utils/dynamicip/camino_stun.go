@@ -0,0 +1,332 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// STUNName selects a resolver that asks STUN servers (RFC 5389) for the
+// address our packets are seen from.
+const STUNName = "stun"
+
+const (
+	stunHeaderLen     = 20
+	stunMagicCookie   = 0x2112A442
+	stunMaxPacketSize = 1500
+
+	stunBindingRequest  = 0x0001
+	stunBindingResponse = 0x0101
+
+	stunAttrMappedAddress    = 0x0001
+	stunAttrXORMappedAddress = 0x0020
+
+	stunFamilyIPv4 = 0x01
+	stunFamilyIPv6 = 0x02
+
+	// Used when the context passed to Resolve has no deadline
+	stunDefaultTimeout = 5 * time.Second
+)
+
+var (
+	errNoSTUNServers         = errors.New("no STUN servers configured")
+	errSTUNShortMessage      = errors.New("STUN message too short")
+	errSTUNUnexpectedType    = errors.New("unexpected STUN message type")
+	errSTUNBadCookie         = errors.New("STUN message has wrong magic cookie")
+	errSTUNTransactionID     = errors.New("STUN transaction ID mismatch")
+	errSTUNNoMappedAddress   = errors.New("STUN response has no mapped address")
+	errSTUNBadAddress        = errors.New("malformed STUN address attribute")
+	errSTUNUnexpectedNetwork = errors.New("STUN network must be one of udp, udp4 or udp6")
+
+	_ Resolver = (*stunResolver)(nil)
+)
+
+// stunResolver resolves our public IP by sending binding requests to STUN
+// servers. Servers are tried in order until one answers.
+type stunResolver struct {
+	servers []string
+	network string
+}
+
+// NewSTUNResolver returns a resolver that queries [servers] ("host:port")
+// over [network], which must be "udp", "udp4" or "udp6". The network selects
+// which address family is resolved.
+func NewSTUNResolver(servers []string, network string) (Resolver, error) {
+	if len(servers) == 0 {
+		return nil, errNoSTUNServers
+	}
+	switch network {
+	case "udp", "udp4", "udp6":
+	default:
+		return nil, fmt.Errorf("%w: %q", errSTUNUnexpectedNetwork, network)
+	}
+	return &stunResolver{
+		servers: servers,
+		network: network,
+	}, nil
+}
+
+func (r *stunResolver) Resolve(ctx context.Context) (net.IP, error) {
+	var errs []error
+	for _, server := range r.servers {
+		ip, err := r.resolve(ctx, server)
+		if err == nil {
+			return ip, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", server, err))
+	}
+	return nil, fmt.Errorf("all STUN servers failed: %v", errs)
+}
+
+func (r *stunResolver) resolve(ctx context.Context, server string) (net.IP, error) {
+	d := net.Dialer{}
+	conn, err := d.DialContext(ctx, r.network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(stunDefaultTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var txID [12]byte
+	if _, err := rand.Read(txID[:]); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(newSTUNMessage(stunBindingRequest, txID, nil)); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, stunMaxPacketSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		addr, err := parseSTUNBindingResponse(buf[:n], txID)
+		if errors.Is(err, errSTUNTransactionID) {
+			// A stale response to an earlier request; keep waiting.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return addr.IP, nil
+	}
+}
+
+// STUNServer answers STUN binding requests with the address they were
+// received from. If MappedIP is set it's reported instead, which allows
+// simulating a NAT locally.
+type STUNServer struct {
+	conn     net.PacketConn
+	MappedIP net.IP
+}
+
+// NewSTUNServer returns a server that answers requests arriving on [conn].
+func NewSTUNServer(conn net.PacketConn, mappedIP net.IP) *STUNServer {
+	return &STUNServer{
+		conn:     conn,
+		MappedIP: mappedIP,
+	}
+}
+
+// Addr returns the address the server is listening on.
+func (s *STUNServer) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Serve answers requests until the underlying connection is closed.
+func (s *STUNServer) Serve() error {
+	buf := make([]byte, stunMaxPacketSize)
+	for {
+		n, from, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		udpAddr, ok := from.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+		txID, err := parseSTUNBindingRequest(buf[:n])
+		if err != nil {
+			// Drop anything that isn't a binding request
+			continue
+		}
+		mapped := *udpAddr
+		if s.MappedIP != nil {
+			mapped.IP = s.MappedIP
+		}
+		attr, err := xorMappedAddress(&mapped, txID)
+		if err != nil {
+			continue
+		}
+		resp := newSTUNMessage(stunBindingResponse, txID, attr)
+		if _, err := s.conn.WriteTo(resp, from); err != nil && errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+	}
+}
+
+// Close stops the server.
+func (s *STUNServer) Close() error {
+	return s.conn.Close()
+}
+
+func newSTUNMessage(msgType uint16, txID [12]byte, attrs []byte) []byte {
+	msg := make([]byte, stunHeaderLen, stunHeaderLen+len(attrs))
+	binary.BigEndian.PutUint16(msg[0:2], msgType)
+	binary.BigEndian.PutUint16(msg[2:4], uint16(len(attrs)))
+	binary.BigEndian.PutUint32(msg[4:8], stunMagicCookie)
+	copy(msg[8:20], txID[:])
+	return append(msg, attrs...)
+}
+
+// parseSTUNHeader validates the fixed header of [msg] and returns the
+// transaction ID and the attributes.
+func parseSTUNHeader(msg []byte, msgType uint16) ([12]byte, []byte, error) {
+	var txID [12]byte
+	if len(msg) < stunHeaderLen {
+		return txID, nil, errSTUNShortMessage
+	}
+	if gotType := binary.BigEndian.Uint16(msg[0:2]); gotType != msgType {
+		return txID, nil, fmt.Errorf("%w: %#04x", errSTUNUnexpectedType, gotType)
+	}
+	if binary.BigEndian.Uint32(msg[4:8]) != stunMagicCookie {
+		return txID, nil, errSTUNBadCookie
+	}
+	length := int(binary.BigEndian.Uint16(msg[2:4]))
+	if len(msg) < stunHeaderLen+length {
+		return txID, nil, errSTUNShortMessage
+	}
+	copy(txID[:], msg[8:20])
+	return txID, msg[stunHeaderLen : stunHeaderLen+length], nil
+}
+
+func parseSTUNBindingRequest(msg []byte) ([12]byte, error) {
+	txID, _, err := parseSTUNHeader(msg, stunBindingRequest)
+	return txID, err
+}
+
+func parseSTUNBindingResponse(msg []byte, txID [12]byte) (*net.UDPAddr, error) {
+	gotTxID, attrs, err := parseSTUNHeader(msg, stunBindingResponse)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(gotTxID[:], txID[:]) {
+		return nil, errSTUNTransactionID
+	}
+
+	var mapped *net.UDPAddr
+	for len(attrs) >= 4 {
+		attrType := binary.BigEndian.Uint16(attrs[0:2])
+		attrLen := int(binary.BigEndian.Uint16(attrs[2:4]))
+		if len(attrs) < 4+attrLen {
+			return nil, errSTUNShortMessage
+		}
+		value := attrs[4 : 4+attrLen]
+		switch attrType {
+		case stunAttrXORMappedAddress:
+			// XOR-MAPPED-ADDRESS takes precedence over MAPPED-ADDRESS
+			return parseSTUNAddress(value, txID, true)
+		case stunAttrMappedAddress:
+			if mapped, err = parseSTUNAddress(value, txID, false); err != nil {
+				return nil, err
+			}
+		}
+		// Attributes are padded to a multiple of 4 bytes
+		padded := (attrLen + 3) &^ 3
+		if len(attrs) < 4+padded {
+			break
+		}
+		attrs = attrs[4+padded:]
+	}
+	if mapped == nil {
+		return nil, errSTUNNoMappedAddress
+	}
+	return mapped, nil
+}
+
+func parseSTUNAddress(value []byte, txID [12]byte, xor bool) (*net.UDPAddr, error) {
+	if len(value) < 4 {
+		return nil, errSTUNBadAddress
+	}
+	var ipLen int
+	switch value[1] {
+	case stunFamilyIPv4:
+		ipLen = net.IPv4len
+	case stunFamilyIPv6:
+		ipLen = net.IPv6len
+	default:
+		return nil, errSTUNBadAddress
+	}
+	if len(value) < 4+ipLen {
+		return nil, errSTUNBadAddress
+	}
+
+	port := binary.BigEndian.Uint16(value[2:4])
+	ip := make(net.IP, ipLen)
+	copy(ip, value[4:4+ipLen])
+	if xor {
+		port ^= stunMagicCookie >> 16
+		mask := stunXORMask(txID)
+		for i := range ip {
+			ip[i] ^= mask[i]
+		}
+	}
+	return &net.UDPAddr{
+		IP:   ip,
+		Port: int(port),
+	}, nil
+}
+
+func xorMappedAddress(addr *net.UDPAddr, txID [12]byte) ([]byte, error) {
+	family := byte(stunFamilyIPv6)
+	ip := addr.IP.To16()
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		family = stunFamilyIPv4
+		ip = ip4
+	}
+	if ip == nil {
+		return nil, errSTUNBadAddress
+	}
+
+	attr := make([]byte, 8+len(ip))
+	binary.BigEndian.PutUint16(attr[0:2], stunAttrXORMappedAddress)
+	binary.BigEndian.PutUint16(attr[2:4], uint16(4+len(ip)))
+	attr[5] = family
+	binary.BigEndian.PutUint16(attr[6:8], uint16(addr.Port)^(stunMagicCookie>>16))
+	mask := stunXORMask(txID)
+	for i, b := range ip {
+		attr[8+i] = b ^ mask[i]
+	}
+	return attr, nil
+}
+
+// stunXORMask returns the magic cookie followed by the transaction ID, which
+// is what (XOR-)mapped addresses are obfuscated with.
+func stunXORMask(txID [12]byte) [16]byte {
+	var mask [16]byte
+	binary.BigEndian.PutUint32(mask[0:4], stunMagicCookie)
+	copy(mask[4:], txID[:])
+	return mask
+}
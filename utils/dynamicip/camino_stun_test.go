@@ -0,0 +1,139 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package dynamicip
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSTUNServer(t *testing.T, network, address string, mappedIP net.IP) *STUNServer {
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		t.Skipf("couldn't listen on %s: %s", address, err)
+	}
+	server := NewSTUNServer(conn, mappedIP)
+	go func() {
+		_ = server.Serve()
+	}()
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+	return server
+}
+
+func TestSTUNResolver(t *testing.T) {
+	tests := map[string]struct {
+		network    string
+		address    string
+		mappedIP   net.IP
+		expectedIP net.IP
+	}{
+		"ipv4": {
+			network:    "udp4",
+			address:    "127.0.0.1:0",
+			expectedIP: net.IPv4(127, 0, 0, 1),
+		},
+		"ipv4 behind simulated NAT": {
+			network:    "udp4",
+			address:    "127.0.0.1:0",
+			mappedIP:   net.IPv4(203, 0, 113, 7),
+			expectedIP: net.IPv4(203, 0, 113, 7),
+		},
+		"ipv6 behind simulated NAT": {
+			network:    "udp6",
+			address:    "[::1]:0",
+			mappedIP:   net.ParseIP("2001:db8::7"),
+			expectedIP: net.ParseIP("2001:db8::7"),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			server := newTestSTUNServer(t, tt.network, tt.address, tt.mappedIP)
+			resolver, err := NewSTUNResolver([]string{server.Addr().String()}, tt.network)
+			require.NoError(err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			ip, err := resolver.Resolve(ctx)
+			require.NoError(err)
+			require.True(tt.expectedIP.Equal(ip), "expected %s, got %s", tt.expectedIP, ip)
+		})
+	}
+}
+
+func TestSTUNResolverFallback(t *testing.T) {
+	require := require.New(t)
+
+	// Reserve a port and close it so requests to it fail
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	require.NoError(err)
+	deadServer := conn.LocalAddr().String()
+	require.NoError(conn.Close())
+
+	server := newTestSTUNServer(t, "udp4", "127.0.0.1:0", net.IPv4(203, 0, 113, 7))
+	resolver, err := NewSTUNResolver([]string{deadServer, server.Addr().String()}, "udp4")
+	require.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ip, err := resolver.Resolve(ctx)
+	require.NoError(err)
+	require.True(net.IPv4(203, 0, 113, 7).Equal(ip))
+}
+
+func TestNewSTUNResolverErrors(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewSTUNResolver(nil, "udp4")
+	require.ErrorIs(err, errNoSTUNServers)
+
+	_, err = NewSTUNResolver([]string{"127.0.0.1:3478"}, "tcp")
+	require.ErrorIs(err, errSTUNUnexpectedNetwork)
+}
+
+func TestParseSTUNBindingResponse(t *testing.T) {
+	require := require.New(t)
+
+	txID := [12]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	addr := &net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 9651}
+
+	attr, err := xorMappedAddress(addr, txID)
+	require.NoError(err)
+	parsed, err := parseSTUNBindingResponse(newSTUNMessage(stunBindingResponse, txID, attr), txID)
+	require.NoError(err)
+	require.True(addr.IP.Equal(parsed.IP))
+	require.Equal(addr.Port, parsed.Port)
+
+	// Plain MAPPED-ADDRESS is accepted from servers that don't send
+	// XOR-MAPPED-ADDRESS
+	mapped := []byte{0, 0, 0, 8, 0, stunFamilyIPv4, 0, 0, 198, 51, 100, 1}
+	binary.BigEndian.PutUint16(mapped[0:2], stunAttrMappedAddress)
+	binary.BigEndian.PutUint16(mapped[6:8], 9651)
+	parsed, err = parseSTUNBindingResponse(newSTUNMessage(stunBindingResponse, txID, mapped), txID)
+	require.NoError(err)
+	require.True(net.IPv4(198, 51, 100, 1).Equal(parsed.IP))
+	require.Equal(9651, parsed.Port)
+
+	otherTxID := txID
+	otherTxID[0]++
+	_, err = parseSTUNBindingResponse(newSTUNMessage(stunBindingResponse, otherTxID, attr), txID)
+	require.ErrorIs(err, errSTUNTransactionID)
+
+	_, err = parseSTUNBindingResponse(newSTUNMessage(stunBindingRequest, txID, attr), txID)
+	require.ErrorIs(err, errSTUNUnexpectedType)
+
+	_, err = parseSTUNBindingResponse(newSTUNMessage(stunBindingResponse, txID, nil), txID)
+	require.ErrorIs(err, errSTUNNoMappedAddress)
+
+	_, err = parseSTUNBindingResponse([]byte{1, 1}, txID)
+	require.ErrorIs(err, errSTUNShortMessage)
+}
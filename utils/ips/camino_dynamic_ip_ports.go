@@ -0,0 +1,66 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ips
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+var _ DynamicIPPorts = (*dynamicIPPorts)(nil)
+
+// A list of IPPorts that can change.
+// Safe for use by multiple goroutines.
+type DynamicIPPorts interface {
+	// Returns the IP + port pairs.
+	IPPorts() []IPPort
+	// Replaces the IP + port pairs.
+	SetIPPorts(ipPorts []IPPort)
+}
+
+type dynamicIPPorts struct {
+	lock    sync.RWMutex
+	ipPorts []IPPort
+}
+
+func NewDynamicIPPorts(ipPorts ...IPPort) DynamicIPPorts {
+	return &dynamicIPPorts{
+		ipPorts: ipPorts,
+	}
+}
+
+func (i *dynamicIPPorts) IPPorts() []IPPort {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return append([]IPPort(nil), i.ipPorts...)
+}
+
+func (i *dynamicIPPorts) SetIPPorts(ipPorts []IPPort) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.ipPorts = append([]IPPort(nil), ipPorts...)
+}
+
+func (i *dynamicIPPorts) MarshalJSON() ([]byte, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return json.Marshal(i.ipPorts)
+}
+
+// EqualIPPorts returns true if [a] and [b] contain the same IPPorts in the same
+// order
+func EqualIPPorts(a, b []IPPort) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ips
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicIPPorts(t *testing.T) {
+	require := require.New(t)
+
+	ipPort := IPPort{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	dynamicIPPorts := NewDynamicIPPorts(ipPort)
+	require.Equal([]IPPort{ipPort}, dynamicIPPorts.IPPorts())
+
+	// Returned slices don't alias the internal state
+	got := dynamicIPPorts.IPPorts()
+	got[0] = IPPort{}
+	require.Equal([]IPPort{ipPort}, dynamicIPPorts.IPPorts())
+
+	newIPPorts := []IPPort{
+		{IP: net.ParseIP("2001:db8::1"), Port: 9651},
+		ipPort,
+	}
+	dynamicIPPorts.SetIPPorts(newIPPorts)
+	newIPPorts[0] = IPPort{}
+	require.True(EqualIPPorts(
+		[]IPPort{{IP: net.ParseIP("2001:db8::1"), Port: 9651}, ipPort},
+		dynamicIPPorts.IPPorts(),
+	))
+
+	jsonBytes, err := json.Marshal(dynamicIPPorts)
+	require.NoError(err)
+	require.JSONEq(`[{"ip":"2001:db8::1","port":9651},{"ip":"1.2.3.4","port":9651}]`, string(jsonBytes))
+}
+
+func TestEqualIPPorts(t *testing.T) {
+	require := require.New(t)
+
+	a := IPPort{IP: net.IPv4(1, 2, 3, 4), Port: 9651}
+	b := IPPort{IP: net.ParseIP("2001:db8::1"), Port: 9651}
+
+	require.True(EqualIPPorts(nil, []IPPort{}))
+	require.True(EqualIPPorts([]IPPort{a, b}, []IPPort{a, b}))
+	require.False(EqualIPPorts([]IPPort{a, b}, []IPPort{b, a}))
+	require.False(EqualIPPorts([]IPPort{a}, []IPPort{a, b}))
+}
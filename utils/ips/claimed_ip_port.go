@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -35,10 +45,17 @@ type ClaimedIPPort struct {
 	Signature []byte
 	// The txID that added this peer into the validator set
 	TxID ids.ID
+	// Additional IPs and ports the peer claimed to be reachable at, in the
+	// order they should be tried after [IPPort].
+	ExtraIPPorts []IPPort
+	// [Cert]'s signature over the IPPort, timestamp and ExtraIPPorts. Empty if
+	// there are no ExtraIPPorts.
+	ExtraSignature []byte
 }
 
 // Returns the length of the byte representation of this ClaimedIPPort.
 func (i *ClaimedIPPort) BytesLen() int {
 	// See wrappers.PackPeerTrackInfo.
-	return baseIPCertDescLen + len(i.Cert.Raw) + len(i.Signature)
+	return baseIPCertDescLen + len(i.Cert.Raw) + len(i.Signature) +
+		len(i.ExtraIPPorts)*ipLen + len(i.ExtraSignature)
 }
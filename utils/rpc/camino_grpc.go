@@ -0,0 +1,27 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// NewGRPCContext returns [ctx] with the headers of [options] attached as the
+// metadata of outgoing gRPC calls. gRPC calls don't have query parameters, so
+// they are dropped.
+func NewGRPCContext(ctx context.Context, options []Option) context.Context {
+	headers := NewOptions(options).Headers()
+	if len(headers) == 0 {
+		return ctx
+	}
+	pairs := make([]string, 0, 2*len(headers))
+	for key, values := range headers {
+		for _, value := range values {
+			pairs = append(pairs, key, value)
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
@@ -0,0 +1,541 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/api/graphql"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/avm/blocks"
+	"github.com/ava-labs/avalanchego/vms/avm/txs"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+var (
+	errMissingBlockArgs = errors.New("either id or height must be given")
+	errInvalidCursor    = errors.New("invalid cursor")
+)
+
+// graphQLAsset is the source of the Asset type.
+type graphQLAsset struct {
+	ID ids.ID
+	*txs.CreateAssetTx
+}
+
+// graphQLSchema builds the GraphQL schema of the X-chain. Its resolvers read
+// the state of [vm]. They must be called while holding the chain lock.
+type graphQLSchema struct {
+	vm *VM
+
+	blockType   *graphql.Object
+	txType      *graphql.Object
+	utxoType    *graphql.Object
+	utxosType   *graphql.Object
+	addressType *graphql.Object
+	assetType   *graphql.Object
+}
+
+// newGraphQLSchema returns the GraphQL schema of the X-chain, whose resolvers
+// read the state of [vm].
+func newGraphQLSchema(vm *VM) (*graphql.Schema, error) {
+	g := &graphQLSchema{
+		vm:          vm,
+		blockType:   &graphql.Object{Name: "Block", Description: "Accepted block"},
+		txType:      &graphql.Object{Name: "Tx", Description: "Accepted transaction"},
+		utxoType:    &graphql.Object{Name: "UTXO", Description: "Unspent transaction output"},
+		addressType: &graphql.Object{Name: "Address", Description: "Address of the X-chain"},
+		assetType:   &graphql.Object{Name: "Asset", Description: "Asset created by a CreateAssetTx"},
+	}
+	// The connection type is shared by the fields, that return UTXOs.
+	g.utxosType = graphql.NewConnectionType(g.utxoType)
+	g.blockType.Fields = g.blockFields()
+	g.txType.Fields = g.txFields()
+	g.utxoType.Fields = g.utxoFields()
+	g.addressType.Fields = g.addressFields()
+	g.assetType.Fields = g.assetFields()
+	return graphql.NewSchema(&graphql.Object{
+		Name:   "Query",
+		Fields: g.queryFields(),
+	})
+}
+
+func (g *graphQLSchema) queryFields() graphql.Fields {
+	return graphql.Fields{
+		"lastAcceptedBlock": {
+			Type:        g.blockType,
+			Description: "Last accepted block. Null before the linearization",
+			Resolve: func(graphql.ResolveParams) (interface{}, error) {
+				if g.vm.chainManager == nil {
+					return nil, nil
+				}
+				return g.vm.state.GetBlock(g.vm.state.GetLastAccepted())
+			},
+		},
+		"block": {
+			Type:        g.blockType,
+			Description: "Accepted block with the given ID or at the given height",
+			Args: graphql.Args{
+				"id":     {Type: graphql.ID},
+				"height": {Type: graphql.Uint64},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var blkID ids.ID
+				switch {
+				case p.Args["id"] != nil:
+					var err error
+					if blkID, err = ids.FromString(p.Args["id"].(string)); err != nil {
+						return nil, err
+					}
+				case p.Args["height"] != nil:
+					var err error
+					blkID, err = g.vm.state.GetBlockID(p.Args["height"].(uint64))
+					if err == database.ErrNotFound {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, err
+					}
+				default:
+					return nil, errMissingBlockArgs
+				}
+				return nullIfNotFound(g.vm.state.GetBlock(blkID))
+			},
+		},
+		"blocks": {
+			Type:        graphql.NewNonNull(graphql.NewConnectionType(g.blockType)),
+			Description: "Accepted blocks ordered by descending height, starting at the last accepted block. Empty before the linearization",
+			Args:        graphql.ConnectionArgs(nil),
+			Resolve:     g.resolveBlocks,
+		},
+		"tx": {
+			Type: g.txType,
+			Args: graphql.Args{
+				"id": {Type: graphql.NewNonNull(graphql.ID)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				txID, err := ids.FromString(p.Args["id"].(string))
+				if err != nil {
+					return nil, err
+				}
+				return g.getTx(txID)
+			},
+		},
+		"asset": {
+			Type: g.assetType,
+			Args: graphql.Args{
+				"id": {Type: graphql.NewNonNull(graphql.String), Description: "ID or alias of the asset"},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				assetID, err := g.vm.lookupAssetID(p.Args["id"].(string))
+				if err != nil {
+					return nil, err
+				}
+				return g.getAsset(assetID)
+			},
+		},
+		"address": {
+			Type: g.addressType,
+			Args: graphql.Args{
+				"address": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return avax.ParseServiceAddress(g.vm, p.Args["address"].(string))
+			},
+		},
+		"utxos": {
+			Type:        graphql.NewNonNull(g.utxosType),
+			Description: "UTXOs of the given addresses",
+			Args: graphql.ConnectionArgs(graphql.Args{
+				"addresses": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			}),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				addrStrs := make([]string, len(p.Args["addresses"].([]interface{})))
+				for i, addrStr := range p.Args["addresses"].([]interface{}) {
+					addrStrs[i] = addrStr.(string)
+				}
+				addrs, err := avax.ParseServiceAddresses(g.vm, addrStrs)
+				if err != nil {
+					return nil, err
+				}
+				return g.getUTXOs(p, addrs)
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) blockFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(blocks.Block).ID(), nil
+			},
+		},
+		"height": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(blocks.Block).Height(), nil
+			},
+		},
+		"timestamp": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Unix time of the block",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(blocks.Block).Timestamp().Unix(), nil
+			},
+		},
+		"parent": {
+			Type:        g.blockType,
+			Description: "Parent of the block. Null for the first block after the linearization or if the parent was pruned",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				blk := p.Source.(blocks.Block)
+				if blk.Height() == 0 {
+					return nil, nil
+				}
+				return nullIfNotFound(g.vm.state.GetBlock(blk.Parent()))
+			},
+		},
+		"txs": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.txType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(blocks.Block).Txs(), nil
+			},
+		},
+		"json": {
+			Type:        graphql.NewNonNull(graphql.JSON),
+			Description: "JSON representation of the block",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				blk := p.Source.(blocks.Block)
+				blk.InitCtx(g.vm.ctx)
+				for _, tx := range blk.Txs() {
+					if err := g.initTx(tx); err != nil {
+						return nil, err
+					}
+				}
+				return blk, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) txFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*txs.Tx).ID(), nil
+			},
+		},
+		"type": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return typeName(p.Source.(*txs.Tx).Unsigned), nil
+			},
+		},
+		"asset": {
+			Type:        g.assetType,
+			Description: "Asset created by the tx",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx := p.Source.(*txs.Tx)
+				createAssetTx, ok := tx.Unsigned.(*txs.CreateAssetTx)
+				if !ok {
+					return nil, nil
+				}
+				return &graphQLAsset{ID: tx.ID(), CreateAssetTx: createAssetTx}, nil
+			},
+		},
+		"json": {
+			Type:        graphql.NewNonNull(graphql.JSON),
+			Description: "JSON representation of the tx",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx := p.Source.(*txs.Tx)
+				return tx, g.initTx(tx)
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) utxoFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*avax.UTXO).InputID(), nil
+			},
+		},
+		"tx": {
+			Type:        g.txType,
+			Description: "Tx, that created the UTXO. Null if it was pruned",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return g.getTx(p.Source.(*avax.UTXO).TxID)
+			},
+		},
+		"outputIndex": {
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*avax.UTXO).OutputIndex, nil
+			},
+		},
+		"asset": {
+			Type: g.assetType,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return g.getAsset(p.Source.(*avax.UTXO).AssetID())
+			},
+		},
+		"amount": {
+			Type: graphql.Uint64,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if out, ok := p.Source.(*avax.UTXO).Out.(avax.Amounter); ok {
+					return out.Amount(), nil
+				}
+				return nil, nil
+			},
+		},
+		"addresses": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.addressType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				out, ok := p.Source.(*avax.UTXO).Out.(avax.Addressable)
+				if !ok {
+					return []ids.ShortID{}, nil
+				}
+				addrs := make([]ids.ShortID, len(out.Addresses()))
+				for i, addrBytes := range out.Addresses() {
+					addr, err := ids.ToShortID(addrBytes)
+					if err != nil {
+						return nil, err
+					}
+					addrs[i] = addr
+				}
+				return addrs, nil
+			},
+		},
+		"json": {
+			Type:        graphql.NewNonNull(graphql.JSON),
+			Description: "JSON representation of the UTXO",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				utxo := p.Source.(*avax.UTXO)
+				if out, ok := utxo.Out.(snow.ContextInitializable); ok {
+					out.InitCtx(g.vm.ctx)
+				}
+				return utxo, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) addressFields() graphql.Fields {
+	return graphql.Fields{
+		"address": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return g.vm.FormatLocalAddress(p.Source.(ids.ShortID))
+			},
+		},
+		"utxos": {
+			Type: graphql.NewNonNull(g.utxosType),
+			Args: graphql.ConnectionArgs(nil),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				addrs := set.NewSet[ids.ShortID](1)
+				addrs.Add(p.Source.(ids.ShortID))
+				return g.getUTXOs(p, addrs)
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) assetFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+		},
+		"name": {
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"symbol": {
+			Type: graphql.NewNonNull(graphql.String),
+		},
+		"denomination": {
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"tx": {
+			Type:        graphql.NewNonNull(g.txType),
+			Description: "Tx, that created the asset",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return g.getTx(p.Source.(*graphQLAsset).ID)
+			},
+		},
+	}
+}
+
+// resolveBlocks returns the page of accepted blocks below the height in the
+// cursor. Pruned blocks end the connection.
+func (g *graphQLSchema) resolveBlocks(p graphql.ResolveParams) (interface{}, error) {
+	first, after, err := graphql.PageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	if g.vm.chainManager == nil {
+		return graphql.NewConnection(nil, false), nil
+	}
+	var height uint64
+	if after == "" {
+		lastAccepted, err := g.vm.state.GetBlock(g.vm.state.GetLastAccepted())
+		if err != nil {
+			return nil, err
+		}
+		height = lastAccepted.Height()
+	} else {
+		afterHeight, err := strconv.ParseUint(after, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+		if afterHeight == 0 {
+			return graphql.NewConnection(nil, false), nil
+		}
+		height = afterHeight - 1
+	}
+
+	var edges []graphql.Edge
+	for {
+		blkID, err := g.vm.state.GetBlockID(height)
+		if err == database.ErrNotFound {
+			return graphql.NewConnection(edges, false), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(edges) == first {
+			return graphql.NewConnection(edges, true), nil
+		}
+		blk, err := g.vm.state.GetBlock(blkID)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, graphql.Edge{
+			Cursor: strconv.FormatUint(height, 10),
+			Node:   blk,
+		})
+		if height == 0 {
+			return graphql.NewConnection(edges, false), nil
+		}
+		height--
+	}
+}
+
+// getUTXOs returns the page of UTXOs of [addrs] after the cursor. The cursor
+// is the address and the ID of the last UTXO of the previous page joined by a
+// colon. Like with getUTXOs of the JSON-RPC API, UTXOs owned by several of
+// [addrs] can be returned on several pages.
+func (g *graphQLSchema) getUTXOs(p graphql.ResolveParams, addrs set.Set[ids.ShortID]) (interface{}, error) {
+	first, after, err := graphql.PageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		lastAddr   ids.ShortID
+		lastUTXOID ids.ID
+	)
+	if after != "" {
+		addrStr, utxoIDStr, ok := strings.Cut(after, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+		if lastAddr, err = ids.ShortFromString(addrStr); err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+		if lastUTXOID, err = ids.FromString(utxoIDStr); err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+	}
+
+	// The UTXOs are fetched one by one to get the cursor of every edge.
+	var (
+		edges []graphql.Edge
+		seen  set.Set[ids.ID]
+	)
+	for {
+		utxos, addr, utxoID, err := avax.GetPaginatedUTXOs(g.vm.state, addrs, lastAddr, lastUTXOID, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(utxos) == 0 {
+			return graphql.NewConnection(edges, false), nil
+		}
+		lastAddr, lastUTXOID = addr, utxoID
+		if seen.Contains(utxoID) {
+			continue
+		}
+		if len(edges) == first {
+			return graphql.NewConnection(edges, true), nil
+		}
+		seen.Add(utxoID)
+		edges = append(edges, graphql.Edge{
+			Cursor: addr.String() + ":" + utxoID.String(),
+			Node:   utxos[0],
+		})
+	}
+}
+
+// getTx returns the accepted tx [txID] or null, if it doesn't exist.
+func (g *graphQLSchema) getTx(txID ids.ID) (interface{}, error) {
+	chainState := &chainState{
+		State: g.vm.state,
+	}
+	return nullIfNotFound(chainState.GetTx(txID))
+}
+
+// getAsset returns the asset [assetID] or null, if it doesn't exist.
+func (g *graphQLSchema) getAsset(assetID ids.ID) (interface{}, error) {
+	chainState := &chainState{
+		State: g.vm.state,
+	}
+	tx, err := chainState.GetTx(assetID)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	createAssetTx, ok := tx.Unsigned.(*txs.CreateAssetTx)
+	if !ok {
+		return nil, nil
+	}
+	return &graphQLAsset{ID: assetID, CreateAssetTx: createAssetTx}, nil
+}
+
+// initTx initializes the outputs of [tx], so that it can be marshalled to
+// JSON.
+func (g *graphQLSchema) initTx(tx *txs.Tx) error {
+	return tx.Unsigned.Visit(&txInit{
+		tx:            tx,
+		ctx:           g.vm.ctx,
+		typeToFxIndex: g.vm.typeToFxIndex,
+		fxs:           g.vm.fxs,
+	})
+}
+
+// nullIfNotFound returns [value] and [err], unless [err] is
+// database.ErrNotFound. Then, it returns null.
+func nullIfNotFound[T any](value T, err error) (interface{}, error) {
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// typeName returns the name of the concrete type of [v].
+func typeName(v interface{}) string {
+	return reflect.Indirect(reflect.ValueOf(v)).Type().Name()
+}
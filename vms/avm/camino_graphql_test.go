@@ -0,0 +1,125 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api/graphql"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+)
+
+func TestGraphQLSchema(t *testing.T) {
+	_, vm, _, _, genesisTx := setup(t, true)
+	defer func() {
+		require.NoError(t, vm.Shutdown(context.Background()))
+		vm.ctx.Lock.Unlock()
+	}()
+
+	schema, err := newGraphQLSchema(vm)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		query    string
+		expected string
+	}{
+		"before linearization": {
+			query:    `{ lastAcceptedBlock { id } blocks { nodes { id } pageInfo { hasNextPage } } }`,
+			expected: `{"data":{"lastAcceptedBlock":null,"blocks":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}`,
+		},
+		"asset": {
+			query:    fmt.Sprintf(`{ asset(id: %q) { id name symbol denomination tx { type } } }`, genesisTx.ID()),
+			expected: fmt.Sprintf(`{"data":{"asset":{"id":%q,"name":"AVAX","symbol":"SYMB","denomination":0,"tx":{"type":"CreateAssetTx"}}}}`, genesisTx.ID()),
+		},
+		"tx": {
+			query:    fmt.Sprintf(`{ tx(id: %q) { id type asset { name } } }`, genesisTx.ID()),
+			expected: fmt.Sprintf(`{"data":{"tx":{"id":%q,"type":"CreateAssetTx","asset":{"name":"AVAX"}}}}`, genesisTx.ID()),
+		},
+		"unknown tx": {
+			query:    fmt.Sprintf(`{ tx(id: %q) { id } }`, ids.ID{1}),
+			expected: `{"data":{"tx":null}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := schema.Execute(context.Background(), &graphql.Request{Query: tt.query})
+			responseBytes, err := stdjson.Marshal(response)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(responseBytes))
+		})
+	}
+}
+
+func TestGraphQLUTXOsPagination(t *testing.T) {
+	require := require.New(t)
+
+	_, vm, _, _, _ := setup(t, true)
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+		vm.ctx.Lock.Unlock()
+	}()
+
+	schema, err := newGraphQLSchema(vm)
+	require.NoError(err)
+	addr, err := vm.FormatLocalAddress(addrs[0])
+	require.NoError(err)
+
+	addrSet := set.NewSet[ids.ShortID](1)
+	addrSet.Add(addrs[0])
+	utxos, err := avax.GetAllUTXOs(vm.state, addrSet)
+	require.NoError(err)
+	expectedUTXOIDs := make([]string, len(utxos))
+	for i, utxo := range utxos {
+		expectedUTXOIDs[i] = utxo.InputID().String()
+	}
+
+	type page struct {
+		Data struct {
+			UTXOs struct {
+				Nodes []struct {
+					ID string `json:"id"`
+				} `json:"nodes"`
+				PageInfo graphql.PageInfo `json:"pageInfo"`
+			} `json:"utxos"`
+		} `json:"data"`
+	}
+
+	var (
+		utxoIDs []string
+		after   string
+	)
+	for {
+		response := schema.Execute(context.Background(), &graphql.Request{
+			Query: `query ($addr: String!, $after: String) {
+				utxos(addresses: [$addr], first: 2, after: $after) { nodes { id } pageInfo { hasNextPage endCursor } }
+			}`,
+			Variables: map[string]interface{}{
+				"addr":  addr,
+				"after": after,
+			},
+		})
+		require.Empty(response.Errors)
+		responseBytes, err := stdjson.Marshal(response)
+		require.NoError(err)
+		p := page{}
+		require.NoError(stdjson.Unmarshal(responseBytes, &p))
+		for _, node := range p.Data.UTXOs.Nodes {
+			utxoIDs = append(utxoIDs, node.ID)
+		}
+		if !p.Data.UTXOs.PageInfo.HasNextPage {
+			break
+		}
+		require.Len(p.Data.UTXOs.Nodes, 2)
+		after = *p.Data.UTXOs.PageInfo.EndCursor
+	}
+	require.Greater(len(expectedUTXOIDs), 2)
+	require.ElementsMatch(expectedUTXOIDs, utxoIDs)
+}
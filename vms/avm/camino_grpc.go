@@ -0,0 +1,217 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/json"
+
+	avmv1 "github.com/ava-labs/avalanchego/proto/pb/avm/v1"
+)
+
+var (
+	_ avmv1.AVMServer = (*grpcService)(nil)
+
+	errUnexpectedEncoding = errors.New("unexpected encoding of API response")
+)
+
+// grpcService serves the read methods of the avm API over gRPC
+type grpcService struct {
+	avmv1.UnsafeAVMServer
+	service *Service
+}
+
+func newGRPCServer(service *Service) *grpc.Server {
+	server := grpc.NewServer()
+	avmv1.RegisterAVMServer(server, &grpcService{
+		service: service,
+	})
+	return server
+}
+
+func (s *grpcService) GetHeight(context.Context, *avmv1.GetHeightRequest) (*avmv1.GetHeightResponse, error) {
+	reply := api.GetHeightResponse{}
+	if err := s.service.GetHeight(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &avmv1.GetHeightResponse{
+		Height: uint64(reply.Height),
+	}, nil
+}
+
+func (s *grpcService) GetBlock(_ context.Context, req *avmv1.GetBlockRequest) (*avmv1.GetBlockResponse, error) {
+	blkID, err := ids.FromString(req.BlockId)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := api.GetBlockResponse{}
+	if err := s.service.GetBlock(nil, &api.GetBlockArgs{
+		BlockID:  blkID,
+		Encoding: formatting.Hex,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	blkBytes, err := decodeHex(reply.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &avmv1.GetBlockResponse{
+		Block: blkBytes,
+	}, nil
+}
+
+func (s *grpcService) GetBlockByHeight(_ context.Context, req *avmv1.GetBlockByHeightRequest) (*avmv1.GetBlockByHeightResponse, error) {
+	reply := api.GetBlockResponse{}
+	if err := s.service.GetBlockByHeight(nil, &api.GetBlockByHeightArgs{
+		Height:   req.Height,
+		Encoding: formatting.Hex,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	blkBytes, err := decodeHex(reply.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &avmv1.GetBlockByHeightResponse{
+		Block: blkBytes,
+	}, nil
+}
+
+func (s *grpcService) GetTx(_ context.Context, req *avmv1.GetTxRequest) (*avmv1.GetTxResponse, error) {
+	txID, err := ids.FromString(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := api.GetTxReply{}
+	if err := s.service.GetTx(nil, &api.GetTxArgs{
+		TxID:     txID,
+		Encoding: formatting.Hex,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	txBytes, err := decodeHex(reply.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return &avmv1.GetTxResponse{
+		Tx: txBytes,
+	}, nil
+}
+
+func (s *grpcService) GetTxStatus(_ context.Context, req *avmv1.GetTxStatusRequest) (*avmv1.GetTxStatusResponse, error) {
+	txID, err := ids.FromString(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := GetTxStatusReply{}
+	if err := s.service.GetTxStatus(nil, &api.JSONTxID{TxID: txID}, &reply); err != nil {
+		return nil, err
+	}
+	return &avmv1.GetTxStatusResponse{
+		Status: reply.Status.String(),
+	}, nil
+}
+
+func (s *grpcService) GetBalance(_ context.Context, req *avmv1.GetBalanceRequest) (*avmv1.GetBalanceResponse, error) {
+	reply := GetBalanceReply{}
+	if err := s.service.GetBalance(nil, &GetBalanceArgs{
+		Address:        req.Address,
+		AssetID:        req.AssetId,
+		IncludePartial: req.IncludePartial,
+	}, &reply); err != nil {
+		return nil, err
+	}
+
+	response := &avmv1.GetBalanceResponse{
+		Balance: uint64(reply.Balance),
+		UtxoIds: make([]string, len(reply.UTXOIDs)),
+	}
+	for i := range reply.UTXOIDs {
+		response.UtxoIds[i] = reply.UTXOIDs[i].String()
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetAllBalances(_ context.Context, req *avmv1.GetAllBalancesRequest) (*avmv1.GetAllBalancesResponse, error) {
+	reply := GetAllBalancesReply{}
+	if err := s.service.GetAllBalances(nil, &GetAllBalancesArgs{
+		JSONAddress:    api.JSONAddress{Address: req.Address},
+		IncludePartial: req.IncludePartial,
+	}, &reply); err != nil {
+		return nil, err
+	}
+
+	response := &avmv1.GetAllBalancesResponse{
+		Balances: make([]*avmv1.Balance, len(reply.Balances)),
+	}
+	for i, balance := range reply.Balances {
+		response.Balances[i] = &avmv1.Balance{
+			AssetId: balance.AssetID,
+			Balance: uint64(balance.Balance),
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetUTXOs(_ context.Context, req *avmv1.GetUTXOsRequest) (*avmv1.GetUTXOsResponse, error) {
+	reply := api.GetUTXOsReply{}
+	if err := s.service.GetUTXOs(nil, &api.GetUTXOsArgs{
+		Addresses:   req.Addresses,
+		SourceChain: req.SourceChain,
+		Limit:       json.Uint32(req.Limit),
+		StartIndex: api.Index{
+			Address: req.StartAddress,
+			UTXO:    req.StartUtxoId,
+		},
+		Encoding: formatting.Hex,
+	}, &reply); err != nil {
+		return nil, err
+	}
+
+	response := &avmv1.GetUTXOsResponse{
+		Utxos:      make([][]byte, len(reply.UTXOs)),
+		EndAddress: reply.EndIndex.Address,
+		EndUtxoId:  reply.EndIndex.UTXO,
+	}
+	for i, utxoStr := range reply.UTXOs {
+		utxoBytes, err := formatting.Decode(formatting.Hex, utxoStr)
+		if err != nil {
+			return nil, err
+		}
+		response.Utxos[i] = utxoBytes
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetAssetDescription(_ context.Context, req *avmv1.GetAssetDescriptionRequest) (*avmv1.GetAssetDescriptionResponse, error) {
+	reply := GetAssetDescriptionReply{}
+	if err := s.service.GetAssetDescription(nil, &GetAssetDescriptionArgs{AssetID: req.AssetId}, &reply); err != nil {
+		return nil, err
+	}
+	return &avmv1.GetAssetDescriptionResponse{
+		AssetId:      reply.AssetID.String(),
+		Name:         reply.Name,
+		Symbol:       reply.Symbol,
+		Denomination: uint32(reply.Denomination),
+	}, nil
+}
+
+// decodeHex returns the bytes of a hex encoded API response.
+func decodeHex(encoded interface{}) ([]byte, error) {
+	str, ok := encoded.(string)
+	if !ok {
+		return nil, errUnexpectedEncoding
+	}
+	return formatting.Decode(formatting.Hex, str)
+}
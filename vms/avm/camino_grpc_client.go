@@ -0,0 +1,246 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	avmv1 "github.com/ava-labs/avalanchego/proto/pb/avm/v1"
+	cjson "github.com/ava-labs/avalanchego/utils/json"
+)
+
+var (
+	_ GRPCClient = Client(nil)
+	_ GRPCClient = (*grpcClient)(nil)
+)
+
+// GRPCClient is the part of the AVM API Client that is served by the gRPC
+// service avm.v1.AVM. Only the headers of the options are sent, as gRPC
+// metadata.
+type GRPCClient interface {
+	// GetBlock returns the block with the given id.
+	GetBlock(ctx context.Context, blkID ids.ID, options ...rpc.Option) ([]byte, error)
+	// GetBlockByHeight returns the block at the given [height].
+	GetBlockByHeight(ctx context.Context, height uint64, options ...rpc.Option) ([]byte, error)
+	// GetHeight returns the height of the last accepted block.
+	GetHeight(ctx context.Context, options ...rpc.Option) (uint64, error)
+	// GetTxStatus returns the status of [txID]
+	//
+	// Deprecated: GetTxStatus only returns Accepted or Unknown, GetTx should be
+	// used instead to determine if the tx was accepted.
+	GetTxStatus(ctx context.Context, txID ids.ID, options ...rpc.Option) (choices.Status, error)
+	// GetTx returns the byte representation of [txID]
+	GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error)
+	// GetUTXOs returns the byte representation of the UTXOs controlled by [addrs]
+	GetUTXOs(
+		ctx context.Context,
+		addrs []ids.ShortID,
+		limit uint32,
+		startAddress ids.ShortID,
+		startUTXOID ids.ID,
+		options ...rpc.Option,
+	) ([][]byte, ids.ShortID, ids.ID, error)
+	// GetAtomicUTXOs returns the byte representation of the atomic UTXOs controlled by [addrs]
+	// from [sourceChain]
+	GetAtomicUTXOs(
+		ctx context.Context,
+		addrs []ids.ShortID,
+		sourceChain string,
+		limit uint32,
+		startAddress ids.ShortID,
+		startUTXOID ids.ID,
+		options ...rpc.Option,
+	) ([][]byte, ids.ShortID, ids.ID, error)
+	// GetAssetDescription returns a description of [assetID]
+	GetAssetDescription(ctx context.Context, assetID string, options ...rpc.Option) (*GetAssetDescriptionReply, error)
+	// GetBalance returns the balance of [assetID] held by [addr].
+	// If [includePartial], balance includes partial owned (i.e. in a multisig) funds.
+	//
+	// Deprecated: GetUTXOs should be used instead.
+	GetBalance(ctx context.Context, addr ids.ShortID, assetID string, includePartial bool, options ...rpc.Option) (*GetBalanceReply, error)
+	// GetAllBalances returns all asset balances for [addr]
+	//
+	// Deprecated: GetUTXOs should be used instead.
+	GetAllBalances(ctx context.Context, addr ids.ShortID, includePartial bool, options ...rpc.Option) ([]Balance, error)
+}
+
+// grpcClient implements GRPCClient with the generated avm.v1.AVM client
+type grpcClient struct {
+	client avmv1.AVMClient
+}
+
+// NewGRPCClient returns a new AVM API Client that calls the gRPC service
+// avm.v1.AVM over [conn]
+func NewGRPCClient(conn grpc.ClientConnInterface) GRPCClient {
+	return &grpcClient{client: avmv1.NewAVMClient(conn)}
+}
+
+func (c *grpcClient) GetBlock(ctx context.Context, blkID ids.ID, options ...rpc.Option) ([]byte, error) {
+	res, err := c.client.GetBlock(rpc.NewGRPCContext(ctx, options), &avmv1.GetBlockRequest{
+		BlockId: blkID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Block, nil
+}
+
+func (c *grpcClient) GetBlockByHeight(ctx context.Context, height uint64, options ...rpc.Option) ([]byte, error) {
+	res, err := c.client.GetBlockByHeight(rpc.NewGRPCContext(ctx, options), &avmv1.GetBlockByHeightRequest{
+		Height: height,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Block, nil
+}
+
+func (c *grpcClient) GetHeight(ctx context.Context, options ...rpc.Option) (uint64, error) {
+	res, err := c.client.GetHeight(rpc.NewGRPCContext(ctx, options), &avmv1.GetHeightRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return res.Height, nil
+}
+
+func (c *grpcClient) GetTxStatus(ctx context.Context, txID ids.ID, options ...rpc.Option) (choices.Status, error) {
+	res, err := c.client.GetTxStatus(rpc.NewGRPCContext(ctx, options), &avmv1.GetTxStatusRequest{
+		TxId: txID.String(),
+	})
+	if err != nil {
+		return choices.Unknown, err
+	}
+	var status choices.Status
+	return status, status.UnmarshalJSON([]byte(strconv.Quote(res.Status)))
+}
+
+func (c *grpcClient) GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error) {
+	res, err := c.client.GetTx(rpc.NewGRPCContext(ctx, options), &avmv1.GetTxRequest{
+		TxId: txID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Tx, nil
+}
+
+func (c *grpcClient) GetUTXOs(
+	ctx context.Context,
+	addrs []ids.ShortID,
+	limit uint32,
+	startAddress ids.ShortID,
+	startUTXOID ids.ID,
+	options ...rpc.Option,
+) ([][]byte, ids.ShortID, ids.ID, error) {
+	return c.GetAtomicUTXOs(ctx, addrs, "", limit, startAddress, startUTXOID, options...)
+}
+
+func (c *grpcClient) GetAtomicUTXOs(
+	ctx context.Context,
+	addrs []ids.ShortID,
+	sourceChain string,
+	limit uint32,
+	startAddress ids.ShortID,
+	startUTXOID ids.ID,
+	options ...rpc.Option,
+) ([][]byte, ids.ShortID, ids.ID, error) {
+	res, err := c.client.GetUTXOs(rpc.NewGRPCContext(ctx, options), &avmv1.GetUTXOsRequest{
+		Addresses:    ids.ShortIDsToStrings(addrs),
+		SourceChain:  sourceChain,
+		Limit:        limit,
+		StartAddress: startAddress.String(),
+		StartUtxoId:  startUTXOID.String(),
+	})
+	if err != nil {
+		return nil, ids.ShortID{}, ids.Empty, err
+	}
+	endAddr, err := address.ParseToID(res.EndAddress)
+	if err != nil {
+		return nil, ids.ShortID{}, ids.Empty, err
+	}
+	endUTXOID, err := ids.FromString(res.EndUtxoId)
+	return res.Utxos, endAddr, endUTXOID, err
+}
+
+func (c *grpcClient) GetAssetDescription(ctx context.Context, assetID string, options ...rpc.Option) (*GetAssetDescriptionReply, error) {
+	res, err := c.client.GetAssetDescription(rpc.NewGRPCContext(ctx, options), &avmv1.GetAssetDescriptionRequest{
+		AssetId: assetID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	id, err := ids.FromString(res.AssetId)
+	if err != nil {
+		return nil, err
+	}
+	return &GetAssetDescriptionReply{
+		FormattedAssetID: FormattedAssetID{AssetID: id},
+		Name:             res.Name,
+		Symbol:           res.Symbol,
+		Denomination:     cjson.Uint8(res.Denomination),
+	}, nil
+}
+
+func (c *grpcClient) GetBalance(
+	ctx context.Context,
+	addr ids.ShortID,
+	assetID string,
+	includePartial bool,
+	options ...rpc.Option,
+) (*GetBalanceReply, error) {
+	res, err := c.client.GetBalance(rpc.NewGRPCContext(ctx, options), &avmv1.GetBalanceRequest{
+		Address:        addr.String(),
+		AssetId:        assetID,
+		IncludePartial: includePartial,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &GetBalanceReply{
+		Balance: cjson.Uint64(res.Balance),
+		UTXOIDs: make([]avax.UTXOID, len(res.UtxoIds)),
+	}
+	for i, utxoIDStr := range res.UtxoIds {
+		utxoID, err := avax.UTXOIDFromString(utxoIDStr)
+		if err != nil {
+			return nil, err
+		}
+		reply.UTXOIDs[i] = *utxoID
+	}
+	return reply, nil
+}
+
+func (c *grpcClient) GetAllBalances(
+	ctx context.Context,
+	addr ids.ShortID,
+	includePartial bool,
+	options ...rpc.Option,
+) ([]Balance, error) {
+	res, err := c.client.GetAllBalances(rpc.NewGRPCContext(ctx, options), &avmv1.GetAllBalancesRequest{
+		Address:        addr.String(),
+		IncludePartial: includePartial,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]Balance, len(res.Balances))
+	for i, balance := range res.Balances {
+		balances[i] = Balance{
+			AssetID: balance.AssetId,
+			Balance: cjson.Uint64(balance.Balance),
+		}
+	}
+	return balances, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+
+	avmv1 "github.com/ava-labs/avalanchego/proto/pb/avm/v1"
+)
+
+func TestGRPCService(t *testing.T) {
+	require := require.New(t)
+
+	_, vm, _, _, genesisTx := setup(t, true)
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+		vm.ctx.Lock.Unlock()
+	}()
+
+	ctx := context.Background()
+	service := &grpcService{service: &Service{vm: vm}}
+
+	tx, err := service.GetTx(ctx, &avmv1.GetTxRequest{TxId: genesisTx.ID().String()})
+	require.NoError(err)
+	require.Equal(genesisTx.Bytes(), tx.Tx)
+
+	txStatus, err := service.GetTxStatus(ctx, &avmv1.GetTxStatusRequest{TxId: genesisTx.ID().String()})
+	require.NoError(err)
+	require.Equal(choices.Accepted.String(), txStatus.Status)
+
+	asset, err := service.GetAssetDescription(ctx, &avmv1.GetAssetDescriptionRequest{AssetId: genesisTx.ID().String()})
+	require.NoError(err)
+	require.Equal(&avmv1.GetAssetDescriptionResponse{
+		AssetId: genesisTx.ID().String(),
+		Name:    "AVAX",
+		Symbol:  "SYMB",
+	}, asset)
+}
+
+func TestGRPCClient(t *testing.T) {
+	require := require.New(t)
+
+	_, vm, _, _, genesisTx := setup(t, true)
+	defer func() {
+		require.NoError(vm.Shutdown(context.Background()))
+		vm.ctx.Lock.Unlock()
+	}()
+
+	listener, err := grpcutils.NewListener()
+	require.NoError(err)
+	server := newGRPCServer(&Service{vm: vm})
+	defer server.Stop()
+	go grpcutils.Serve(listener, server)
+
+	conn, err := grpcutils.Dial(listener.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+	client := NewGRPCClient(conn)
+
+	ctx := context.Background()
+	tx, err := client.GetTx(ctx, genesisTx.ID())
+	require.NoError(err)
+	require.Equal(genesisTx.Bytes(), tx)
+
+	txStatus, err := client.GetTxStatus(ctx, genesisTx.ID())
+	require.NoError(err)
+	require.Equal(choices.Accepted, txStatus)
+
+	asset, err := client.GetAssetDescription(ctx, genesisTx.ID().String())
+	require.NoError(err)
+	require.Equal(genesisTx.ID(), asset.AssetID)
+	require.Equal("AVAX", asset.Name)
+	require.Equal("SYMB", asset.Symbol)
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import "github.com/ava-labs/avalanchego/vms/components/pruning"
+
+// startPruning starts to prune old blocks in the background, if pruning is
+// enabled.
+func (vm *VM) startPruning() {
+	if !vm.pruningEnabled || vm.pruner != nil {
+		return
+	}
+
+	vm.pruner = pruning.NewPruner(
+		&vm.ctx.Lock,
+		vm.ctx.Log,
+		vm.state,
+		vm.pruningRetainedBlocks,
+		func() (uint64, bool, error) {
+			// Blocks only exist after the linearization.
+			if vm.chainManager == nil {
+				return 0, false, nil
+			}
+			lastAccepted, err := vm.state.GetBlock(vm.state.GetLastAccepted())
+			if err != nil {
+				return 0, false, err
+			}
+			return lastAccepted.Height(), true, nil
+		},
+	)
+	vm.pruner.Start()
+}
+
+// prunedErr explains [err], if it was returned because a block or tx wasn't
+// found and pruning is enabled.
+func (vm *VM) prunedErr(err error) error {
+	if !vm.pruningEnabled {
+		return err
+	}
+	return pruning.PrunedErr(err, vm.state.GetPrunedHeight())
+}
@@ -85,7 +85,7 @@ func (s *Service) GetBlock(_ *http.Request, args *api.GetBlockArgs, reply *api.G
 	}
 	block, err := s.vm.chainManager.GetStatelessBlock(args.BlockID)
 	if err != nil {
-		return fmt.Errorf("couldn't get block with id %s: %w", args.BlockID, err)
+		return fmt.Errorf("couldn't get block with id %s: %w", args.BlockID, s.vm.prunedErr(err))
 	}
 	reply.Encoding = args.Encoding
 
@@ -137,7 +137,7 @@ func (s *Service) GetBlockByHeight(_ *http.Request, args *api.GetBlockByHeightAr
 			zap.Stringer("blkID", blockID),
 			zap.Error(err),
 		)
-		return fmt.Errorf("couldn't get block with id %s: %w", blockID, err)
+		return fmt.Errorf("couldn't get block with id %s: %w", blockID, s.vm.prunedErr(err))
 	}
 
 	if args.Encoding == formatting.JSON {
@@ -339,7 +339,7 @@ func (s *Service) GetTx(_ *http.Request, args *api.GetTxArgs, reply *api.GetTxRe
 	}
 	tx, err := chainState.GetTx(args.TxID)
 	if err != nil {
-		return err
+		return s.vm.prunedErr(err)
 	}
 
 	reply.Encoding = args.Encoding
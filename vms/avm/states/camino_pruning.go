@@ -0,0 +1,111 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package states
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/avm/txs"
+)
+
+var (
+	prunedHeightKey = []byte{0x03}
+
+	errPruneAboveLastAccepted = errors.New("can't prune the last accepted block")
+)
+
+func (s *state) GetPrunedHeight() uint64 {
+	return s.prunedHeight
+}
+
+// Prune deletes the accepted blocks with heights below [height], starting with
+// the lowest block that isn't pruned yet, and the txs of these blocks that
+// don't create assets. At most [maxBlocks] blocks are deleted. Returns the new
+// pruned height.
+//
+// Invariant: There must not be any uncommitted changes.
+func (s *state) Prune(height uint64, maxBlocks int) (uint64, error) {
+	lastAccepted, err := s.GetBlock(s.lastAccepted)
+	if err != nil {
+		return s.prunedHeight, err
+	}
+	if height > lastAccepted.Height() {
+		return s.prunedHeight, fmt.Errorf("%w: height %d, last accepted height %d",
+			errPruneAboveLastAccepted,
+			height,
+			lastAccepted.Height(),
+		)
+	}
+
+	prunedHeight := s.prunedHeight
+	for ; prunedHeight < height && maxBlocks > 0; prunedHeight++ {
+		blkID, err := s.GetBlockID(prunedHeight)
+		if err != nil {
+			return s.prunedHeight, fmt.Errorf("failed to get block ID at height %d: %w", prunedHeight, err)
+		}
+		if err := s.pruneBlock(blkID); err != nil {
+			return s.prunedHeight, err
+		}
+		maxBlocks--
+	}
+	if prunedHeight == s.prunedHeight {
+		return s.prunedHeight, nil
+	}
+
+	if err := database.PutUInt64(s.singletonDB, prunedHeightKey, prunedHeight); err != nil {
+		return s.prunedHeight, err
+	}
+	if err := s.db.Commit(); err != nil {
+		return s.prunedHeight, err
+	}
+	s.prunedHeight = prunedHeight
+	return s.prunedHeight, nil
+}
+
+func (s *state) pruneBlock(blkID ids.ID) error {
+	blk, err := s.GetBlock(blkID)
+	if err == database.ErrNotFound {
+		// The block was already pruned, but the pruned height wasn't
+		// committed.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get block %s: %w", blkID, err)
+	}
+
+	for _, tx := range blk.Txs() {
+		// Assets are looked up by the ID of the tx that created them.
+		if _, ok := tx.Unsigned.(*txs.CreateAssetTx); ok {
+			continue
+		}
+		txID := tx.ID()
+		s.txCache.Evict(txID)
+		if err := s.txDB.Delete(txID[:]); err != nil {
+			return err
+		}
+		s.statusCache.Evict(txID)
+		if err := s.statusDB.Delete(txID[:]); err != nil {
+			return err
+		}
+	}
+
+	s.blockCache.Evict(blkID)
+	return s.blockDB.Delete(blkID[:])
+}
+
+func (s *state) loadPrunedHeight() error {
+	prunedHeight, err := database.GetUInt64(s.singletonDB, prunedHeightKey)
+	switch {
+	case err == database.ErrNotFound:
+		s.prunedHeight = 0
+	case err != nil:
+		return err
+	default:
+		s.prunedHeight = prunedHeight
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package states
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/avm/blocks"
+	"github.com/ava-labs/avalanchego/vms/avm/txs"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestPrune(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	s, err := New(versiondb.New(db), parser, prometheus.NewRegistry())
+	require.NoError(err)
+
+	stopVertexID := ids.GenerateTestID()
+	genesisTimestamp := version.CortinaDefaultTime
+	require.NoError(s.InitializeChainState(stopVertexID, genesisTimestamp))
+
+	// Accept blocks that contain a tx that is pruned and a tx that creates an
+	// asset.
+	var (
+		blks        []blocks.Block
+		prunableTxs []*txs.Tx
+		assetTxs    []*txs.Tx
+	)
+	parentID := s.GetLastAccepted()
+	for height := uint64(1); height <= 5; height++ {
+		prunableTx := &txs.Tx{Unsigned: &txs.BaseTx{BaseTx: avax.BaseTx{
+			Memo: database.PackUInt64(height),
+		}}}
+		require.NoError(parser.InitializeTx(prunableTx))
+		assetTx := &txs.Tx{Unsigned: &txs.CreateAssetTx{
+			BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{
+				Memo: database.PackUInt64(height),
+			}},
+			Name:   "asset",
+			Symbol: "A",
+			States: []*txs.InitialState{{
+				Outs: []verify.State{&secp256k1fx.TransferOutput{}},
+			}},
+		}}
+		require.NoError(parser.InitializeTx(assetTx))
+
+		blk, err := blocks.NewStandardBlock(
+			parentID,
+			height,
+			genesisTimestamp,
+			[]*txs.Tx{prunableTx, assetTx},
+			parser.Codec(),
+		)
+		require.NoError(err)
+		s.AddBlock(blk)
+		s.AddTx(prunableTx)
+		s.AddTx(assetTx)
+		s.SetLastAccepted(blk.ID())
+		require.NoError(s.Commit())
+
+		blks = append(blks, blk)
+		prunableTxs = append(prunableTxs, prunableTx)
+		assetTxs = append(assetTxs, assetTx)
+		parentID = blk.ID()
+	}
+
+	_, err = s.Prune(6, 1)
+	require.ErrorIs(err, errPruneAboveLastAccepted)
+
+	// Prune in batches until all blocks below height 3 are pruned.
+	for s.GetPrunedHeight() < 3 {
+		prunedHeight, err := s.Prune(3, 2)
+		require.NoError(err)
+		require.Equal(s.GetPrunedHeight(), prunedHeight)
+	}
+	require.Equal(uint64(3), s.GetPrunedHeight())
+
+	for i, blk := range blks {
+		_, blkErr := s.GetBlock(blk.ID())
+		_, prunableTxErr := s.GetTx(prunableTxs[i].ID())
+		if blk.Height() < 3 {
+			require.ErrorIs(blkErr, database.ErrNotFound)
+			require.ErrorIs(prunableTxErr, database.ErrNotFound)
+		} else {
+			require.NoError(blkErr)
+			require.NoError(prunableTxErr)
+		}
+		_, err := s.GetTx(assetTxs[i].ID())
+		require.NoError(err)
+	}
+
+	// The pruned height is persisted.
+	s, err = New(versiondb.New(db), parser, prometheus.NewRegistry())
+	require.NoError(err)
+	require.NoError(s.InitializeChainState(stopVertexID, genesisTimestamp))
+	require.Equal(uint64(3), s.GetPrunedHeight())
+}
@@ -400,6 +400,20 @@ func (mr *MockStateMockRecorder) GetLastAccepted() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastAccepted", reflect.TypeOf((*MockState)(nil).GetLastAccepted))
 }
 
+// GetPrunedHeight mocks base method.
+func (m *MockState) GetPrunedHeight() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrunedHeight")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetPrunedHeight indicates an expected call of GetPrunedHeight.
+func (mr *MockStateMockRecorder) GetPrunedHeight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrunedHeight", reflect.TypeOf((*MockState)(nil).GetPrunedHeight))
+}
+
 // GetStatus mocks base method.
 func (m *MockState) GetStatus(arg0 ids.ID) (choices.Status, error) {
 	m.ctrl.T.Helper()
@@ -503,6 +517,21 @@ func (mr *MockStateMockRecorder) IsInitialized() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsInitialized", reflect.TypeOf((*MockState)(nil).IsInitialized))
 }
 
+// Prune mocks base method.
+func (m *MockState) Prune(arg0 uint64, arg1 int) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockStateMockRecorder) Prune(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockState)(nil).Prune), arg0, arg1)
+}
+
 // SetInitialized mocks base method.
 func (m *MockState) SetInitialized() error {
 	m.ctrl.T.Helper()
@@ -91,6 +91,15 @@ type State interface {
 	// AddStatus saves a status in storage.
 	AddStatus(id ids.ID, status choices.Status)
 
+	// GetPrunedHeight returns the height of the lowest accepted block that
+	// wasn't pruned.
+	GetPrunedHeight() uint64
+
+	// Prune deletes up to [maxBlocks] accepted blocks below [height] and the
+	// txs of these blocks that don't create assets. Returns the new pruned
+	// height.
+	Prune(height uint64, maxBlocks int) (uint64, error)
+
 	// Discard uncommitted changes to the database.
 	Abort()
 
@@ -119,7 +128,8 @@ type State interface {
  * '-. singletons
  *   |-- initializedKey -> nil
  *   |-- timestampKey -> timestamp
- *   '-- lastAcceptedKey -> lastAccepted
+ *   |-- lastAcceptedKey -> lastAccepted
+ *   '-- prunedHeightKey -> prunedHeight
  */
 type state struct {
 	parser blocks.Parser
@@ -148,6 +158,7 @@ type state struct {
 	// [lastAccepted] is the most recently accepted block.
 	lastAccepted, persistedLastAccepted ids.ID
 	timestamp, persistedTimestamp       time.Time
+	prunedHeight                        uint64
 	singletonDB                         database.Database
 }
 
@@ -361,8 +372,11 @@ func (s *state) InitializeChainState(stopVertexID ids.ID, genesisTimestamp time.
 	s.lastAccepted = lastAccepted
 	s.persistedLastAccepted = lastAccepted
 	s.timestamp, err = database.GetTimestamp(s.singletonDB, timestampKey)
+	if err != nil {
+		return err
+	}
 	s.persistedTimestamp = s.timestamp
-	return err
+	return s.loadPrunedHeight()
 }
 
 func (s *state) initializeChainState(stopVertexID ids.ID, genesisTimestamp time.Time) error {
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -12,12 +22,12 @@ import (
 
 	stdjson "encoding/json"
 
-	"github.com/gorilla/rpc/v2"
-
 	"github.com/prometheus/client_golang/prometheus"
 
 	"go.uber.org/zap"
 
+	"github.com/ava-labs/avalanchego/api/graphql"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
@@ -48,6 +58,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/components/index"
 	"github.com/ava-labs/avalanchego/vms/components/keystore"
+	"github.com/ava-labs/avalanchego/vms/components/pruning"
 	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 
 	blockbuilder "github.com/ava-labs/avalanchego/vms/avm/blocks/builder"
@@ -135,6 +146,12 @@ type VM struct {
 	blockbuilder.Builder
 	chainManager blockexecutor.Manager
 	network      network.Network
+
+	pruningEnabled        bool
+	pruningRetainedBlocks uint64
+	graphQLEnabled        bool
+	// Prunes old blocks in the background. Nil if pruning wasn't started.
+	pruner *pruning.Pruner
 }
 
 func (*VM) Connected(context.Context, ids.NodeID, *version.Application) error {
@@ -154,6 +171,15 @@ func (*VM) Disconnected(context.Context, ids.NodeID) error {
 type Config struct {
 	IndexTransactions    bool `json:"index-transactions"`
 	IndexAllowIncomplete bool `json:"index-allow-incomplete"`
+
+	// If true, the accepted blocks below the last [PruningRetainedBlocks]
+	// blocks are deleted in the background, together with their txs that don't
+	// create assets. Txs that were accepted before the linearization are kept.
+	PruningEnabled        bool   `json:"pruning-enabled"`
+	PruningRetainedBlocks uint64 `json:"pruning-retained-blocks"`
+
+	// If true, the /graphql endpoint serves GraphQL queries over the state.
+	GraphQLEnabled bool `json:"graphql-enabled"`
 }
 
 func (vm *VM) Initialize(
@@ -170,11 +196,16 @@ func (vm *VM) Initialize(
 	noopMessageHandler := common.NewNoOpAppHandler(ctx.Log)
 	vm.Atomic = network.NewAtomic(noopMessageHandler)
 
-	avmConfig := Config{}
+	avmConfig := Config{
+		PruningRetainedBlocks: pruning.DefaultRetainedBlocks,
+	}
 	if len(configBytes) > 0 {
 		if err := stdjson.Unmarshal(configBytes, &avmConfig); err != nil {
 			return err
 		}
+		if err := pruning.VerifyConfig(avmConfig.PruningEnabled, avmConfig.PruningRetainedBlocks); err != nil {
+			return err
+		}
 		ctx.Log.Info("VM config initialized",
 			zap.Reflect("config", avmConfig),
 		)
@@ -265,6 +296,10 @@ func (vm *VM) Initialize(
 	vm.walletService.pendingTxs = linkedhashmap.New[ids.ID, *txs.Tx]()
 
 	// use no op impl when disabled in config
+	vm.pruningEnabled = avmConfig.PruningEnabled
+	vm.pruningRetainedBlocks = avmConfig.PruningRetainedBlocks
+	vm.graphQLEnabled = avmConfig.GraphQLEnabled
+
 	if avmConfig.IndexTransactions {
 		vm.ctx.Log.Warn("deprecated address transaction indexing is enabled")
 		vm.addressTxsIndexer, err = index.NewIndexer(vm.db, vm.ctx.Log, "", vm.registerer, avmConfig.IndexAllowIncomplete)
@@ -329,6 +364,7 @@ func (vm *VM) onNormalOperationsStarted() error {
 	}
 
 	vm.bootstrapped = true
+	vm.startPruning()
 	return nil
 }
 
@@ -354,6 +390,10 @@ func (vm *VM) Shutdown(context.Context) error {
 	vm.timer.Stop()
 	vm.ctx.Lock.Lock()
 
+	if vm.pruner != nil {
+		vm.pruner.Stop()
+	}
+
 	errs := wrappers.Errs{}
 	errs.Add(
 		vm.state.Close(),
@@ -369,33 +409,51 @@ func (*VM) Version(context.Context) (string, error) {
 func (vm *VM) CreateHandlers(context.Context) (map[string]*common.HTTPHandler, error) {
 	codec := json.NewCodec()
 
-	rpcServer := rpc.NewServer()
+	rpcServer := openrpc.NewServer()
 	rpcServer.RegisterCodec(codec, "application/json")
 	rpcServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	rpcServer.RegisterInterceptFunc(vm.metrics.InterceptRequest)
 	rpcServer.RegisterAfterFunc(vm.metrics.AfterRequest)
+	service := &Service{vm: vm}
 	// name this service "avm"
-	if err := rpcServer.RegisterService(&Service{vm: vm}, "avm"); err != nil {
+	if err := rpcServer.RegisterService(service, "avm"); err != nil {
 		return nil, err
 	}
 
-	walletServer := rpc.NewServer()
+	walletServer := openrpc.NewServer()
 	walletServer.RegisterCodec(codec, "application/json")
 	walletServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	walletServer.RegisterInterceptFunc(vm.metrics.InterceptRequest)
 	walletServer.RegisterAfterFunc(vm.metrics.AfterRequest)
 	// name this service "wallet"
-	err := walletServer.RegisterService(&vm.walletService, "wallet")
+	if err := walletServer.RegisterService(&vm.walletService, "wallet"); err != nil {
+		return nil, err
+	}
 
-	return map[string]*common.HTTPHandler{
+	handlers := map[string]*common.HTTPHandler{
 		"":        {Handler: rpcServer},
 		"/wallet": {Handler: walletServer},
 		"/events": {LockOptions: common.NoLock, Handler: vm.pubsub},
-	}, err
+		// The service fills the caches of the state, so it needs the write
+		// lock like the JSON-RPC service.
+		common.GRPCExtension: {Handler: newGRPCServer(service)},
+	}
+	if vm.graphQLEnabled {
+		schema, err := newGraphQLSchema(vm)
+		if err != nil {
+			return nil, err
+		}
+		// The resolvers fill the caches of the state, so they need the write
+		// lock like the JSON-RPC service.
+		handlers["/graphql"] = &common.HTTPHandler{
+			Handler: graphql.NewHandler(schema),
+		}
+	}
+	return handlers, nil
 }
 
 func (*VM) CreateStaticHandlers(context.Context) (map[string]*common.HTTPHandler, error) {
-	newServer := rpc.NewServer()
+	newServer := openrpc.NewServer()
 	codec := json.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
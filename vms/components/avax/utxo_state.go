@@ -118,6 +118,13 @@ func NewMeteredUTXOState(db database.Database, codec codec.Manager, metrics prom
 	}, err
 }
 
+// UTXODB returns the database that a UTXOState created over [db] stores its
+// UTXOs in. The database maps UTXO IDs to serialized UTXOs and doesn't contain
+// the address index.
+func UTXODB(db database.Database) database.Database {
+	return prefixdb.New(utxoPrefix, db)
+}
+
 func (s *utxoState) GetUTXO(utxoID ids.ID) (*UTXO, error) {
 	if utxo, found := s.utxoCache.Get(utxoID); found {
 		if utxo == nil {
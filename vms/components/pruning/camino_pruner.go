@@ -0,0 +1,170 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pruning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+const (
+	DefaultRetainedBlocks = 65536
+
+	// MinRetainedBlocks is the lowest number of blocks that pruning retains.
+	// Recently accepted blocks are still requested by peers.
+	MinRetainedBlocks = 1024
+
+	// batchSize is the maximum number of blocks that are pruned while the
+	// context lock is held.
+	batchSize = 1024
+
+	// interval is the time to wait before pruning again, once all blocks
+	// that can be pruned were pruned.
+	interval = 10 * time.Second
+)
+
+var (
+	ErrTooFewRetainedBlocks = errors.New("too few retained blocks")
+	ErrPruned               = errors.New("not found, it may have been pruned")
+)
+
+// State is the state of a chain, whose accepted blocks can be pruned.
+type State interface {
+	// GetPrunedHeight returns the height, below which all blocks were pruned
+	GetPrunedHeight() uint64
+	// Prune prunes up to [maxBlocks] blocks below [height] and returns the
+	// new pruned height
+	Prune(height uint64, maxBlocks int) (uint64, error)
+}
+
+// Pruner prunes the accepted blocks of a chain, except for the last
+// [retainedBlocks] blocks, in the background.
+type Pruner struct {
+	lock           sync.Locker
+	log            logging.Logger
+	state          State
+	retainedBlocks uint64
+	// Returns the height of the last accepted block. Returns false, if the
+	// chain has no blocks yet.
+	lastAcceptedHeight func() (uint64, bool, error)
+
+	cancel context.CancelFunc
+}
+
+// VerifyConfig returns an error, if pruning is [enabled] and retains fewer
+// than [MinRetainedBlocks] blocks.
+func VerifyConfig(enabled bool, retainedBlocks uint64) error {
+	if enabled && retainedBlocks < MinRetainedBlocks {
+		return fmt.Errorf("%w: %d < %d",
+			ErrTooFewRetainedBlocks,
+			retainedBlocks,
+			MinRetainedBlocks,
+		)
+	}
+	return nil
+}
+
+// PrunedErr explains [err], if it was returned because a block or tx wasn't
+// found and the blocks below [prunedHeight] were pruned.
+func PrunedErr(err error, prunedHeight uint64) error {
+	if err != database.ErrNotFound || prunedHeight == 0 {
+		return err
+	}
+	return fmt.Errorf("%w: blocks below height %d and their txs were pruned", ErrPruned, prunedHeight)
+}
+
+// NewPruner returns a pruner of the blocks of [state]. [lock] is held while
+// blocks are pruned.
+func NewPruner(
+	lock sync.Locker,
+	log logging.Logger,
+	state State,
+	retainedBlocks uint64,
+	lastAcceptedHeight func() (uint64, bool, error),
+) *Pruner {
+	return &Pruner{
+		lock:               lock,
+		log:                log,
+		state:              state,
+		retainedBlocks:     retainedBlocks,
+		lastAcceptedHeight: lastAcceptedHeight,
+	}
+}
+
+// Start starts to prune blocks in the background, until the pruner is
+// stopped.
+func (p *Pruner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	go p.prune(ctx)
+}
+
+// Stop stops pruning blocks. It doesn't wait for the batch, that is pruned
+// while [lock] is held, so it may be called while [lock] is held.
+func (p *Pruner) Stop() {
+	p.cancel()
+}
+
+func (p *Pruner) prune(ctx context.Context) {
+	for {
+		done, err := p.pruneBatch(ctx)
+		if err != nil {
+			p.log.Error("failed to prune blocks",
+				zap.Error(err),
+			)
+			return
+		}
+
+		var wait time.Duration
+		if done {
+			wait = interval
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pruneBatch prunes the next batch of blocks. Returns true, if there are no
+// more blocks to prune.
+func (p *Pruner) pruneBatch(ctx context.Context) (bool, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// The pruner may have been stopped while waiting for the lock.
+	if ctx.Err() != nil {
+		return true, nil
+	}
+
+	lastAcceptedHeight, ok, err := p.lastAcceptedHeight()
+	if err != nil {
+		return false, err
+	}
+	if !ok || lastAcceptedHeight <= p.retainedBlocks {
+		return true, nil
+	}
+
+	height := lastAcceptedHeight - p.retainedBlocks
+	if p.state.GetPrunedHeight() >= height {
+		return true, nil
+	}
+	prunedHeight, err := p.state.Prune(height, batchSize)
+	if err != nil {
+		return false, err
+	}
+	p.log.Debug("pruned blocks",
+		zap.Uint64("prunedHeight", prunedHeight),
+	)
+	return prunedHeight >= height, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package pruning
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/utils/logging"
+)
+
+var errTest = errors.New("test error")
+
+type testState struct {
+	prunedHeight uint64
+	pruneErr     error
+}
+
+func (s *testState) GetPrunedHeight() uint64 {
+	return s.prunedHeight
+}
+
+func (s *testState) Prune(height uint64, maxBlocks int) (uint64, error) {
+	if s.pruneErr != nil {
+		return 0, s.pruneErr
+	}
+	s.prunedHeight += uint64(maxBlocks)
+	if s.prunedHeight > height {
+		s.prunedHeight = height
+	}
+	return s.prunedHeight, nil
+}
+
+func TestVerifyConfig(t *testing.T) {
+	require.NoError(t, VerifyConfig(false, 0))
+	require.NoError(t, VerifyConfig(true, MinRetainedBlocks))
+	require.ErrorIs(t, VerifyConfig(true, MinRetainedBlocks-1), ErrTooFewRetainedBlocks)
+}
+
+func TestPrunedErr(t *testing.T) {
+	require.Equal(t, errTest, PrunedErr(errTest, 1))
+	require.Equal(t, database.ErrNotFound, PrunedErr(database.ErrNotFound, 0))
+	require.ErrorIs(t, PrunedErr(database.ErrNotFound, 1), ErrPruned)
+}
+
+func TestPrunerPruneBatch(t *testing.T) {
+	tests := map[string]struct {
+		lastAcceptedHeight   uint64
+		noBlocks             bool
+		pruneErr             error
+		expectedDone         bool
+		expectedErr          error
+		expectedPrunedHeight uint64
+	}{
+		"no blocks": {
+			noBlocks:     true,
+			expectedDone: true,
+		},
+		"all blocks retained": {
+			lastAcceptedHeight: 2 * batchSize,
+			expectedDone:       true,
+		},
+		"batch": {
+			lastAcceptedHeight:   4*batchSize + 1,
+			expectedPrunedHeight: batchSize,
+		},
+		"last batch": {
+			lastAcceptedHeight:   3 * batchSize,
+			expectedDone:         true,
+			expectedPrunedHeight: batchSize,
+		},
+		"failed": {
+			lastAcceptedHeight: 4 * batchSize,
+			pruneErr:           errTest,
+			expectedErr:        errTest,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+
+			state := &testState{pruneErr: tt.pruneErr}
+			pruner := NewPruner(&sync.Mutex{}, logging.NoLog{}, state, 2*batchSize, func() (uint64, bool, error) {
+				return tt.lastAcceptedHeight, !tt.noBlocks, nil
+			})
+			done, err := pruner.pruneBatch(context.Background())
+			require.ErrorIs(err, tt.expectedErr)
+			require.Equal(tt.expectedDone, done)
+			require.Equal(tt.expectedPrunedHeight, state.prunedHeight)
+		})
+	}
+}
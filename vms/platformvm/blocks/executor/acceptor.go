@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -27,6 +37,12 @@ type acceptor struct {
 	metrics          metrics.Metrics
 	recentlyAccepted window.Window[ids.ID]
 	bootstrapped     *utils.Atomic[bool]
+	// onAccepting is called before the state of an accepted block is applied,
+	// while the state is still at the parent of the block. May be nil.
+	onAccepting func(blocks.Block)
+	// onCommit is called after the state of an accepted block was committed
+	// to the database. May be nil.
+	onCommit func(blocks.Block)
 }
 
 func (a *acceptor) BanffAbortBlock(b *blocks.BanffAbortBlock) error {
@@ -148,6 +164,8 @@ func (a *acceptor) ApricotAtomicBlock(b *blocks.ApricotAtomicBlock) error {
 		return fmt.Errorf("couldn't find state of block %s", blkID)
 	}
 
+	a.accepting(b)
+
 	// Update the state to reflect the changes made in [onAcceptState].
 	if err := blkState.onAcceptState.Apply(a.state); err != nil {
 		return err
@@ -172,6 +190,8 @@ func (a *acceptor) ApricotAtomicBlock(b *blocks.ApricotAtomicBlock) error {
 			err,
 		)
 	}
+
+	a.committed(b)
 	return nil
 }
 
@@ -235,10 +255,16 @@ func (a *acceptor) optionBlock(b, parent blocks.Block) error {
 	if !ok {
 		return fmt.Errorf("couldn't find state of block %s", blkID)
 	}
+	a.accepting(b)
 	if err := blkState.onAcceptState.Apply(a.state); err != nil {
 		return err
 	}
-	return a.state.Commit()
+	if err := a.state.Commit(); err != nil {
+		return err
+	}
+
+	a.committed(b)
+	return nil
 }
 
 func (a *acceptor) proposalBlock(b blocks.Block) {
@@ -274,6 +300,8 @@ func (a *acceptor) standardBlock(b blocks.Block) error {
 		return fmt.Errorf("couldn't find state of block %s", blkID)
 	}
 
+	a.accepting(b)
+
 	// Update the state to reflect the changes made in [onAcceptState].
 	if err := blkState.onAcceptState.Apply(a.state); err != nil {
 		return err
@@ -297,6 +325,8 @@ func (a *acceptor) standardBlock(b blocks.Block) error {
 	if onAcceptFunc := blkState.onAcceptFunc; onAcceptFunc != nil {
 		onAcceptFunc()
 	}
+
+	a.committed(b)
 	return nil
 }
 
@@ -314,3 +344,15 @@ func (a *acceptor) commonAccept(b blocks.Block) error {
 	a.recentlyAccepted.Add(blkID)
 	return nil
 }
+
+func (a *acceptor) accepting(b blocks.Block) {
+	if a.onAccepting != nil {
+		a.onAccepting(b)
+	}
+}
+
+func (a *acceptor) committed(b blocks.Block) {
+	if a.onCommit != nil {
+		a.onCommit(b)
+	}
+}
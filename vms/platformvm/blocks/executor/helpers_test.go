@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -214,6 +224,8 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller) *environment {
 			res.state,
 			res.backend,
 			window,
+			nil,
+			nil,
 		)
 		addSubnet(res)
 	} else {
@@ -223,6 +235,8 @@ func newEnvironment(t *testing.T, ctrl *gomock.Controller) *environment {
 			res.mockedState,
 			res.backend,
 			window,
+			nil,
+			nil,
 		)
 		// we do not add any subnet to state, since we can mock
 		// whatever we need
@@ -1,3 +1,13 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+//
+// This file is a derived work, based on ava-labs code whose
+// original notices appear below.
+//
+// It is distributed under the same license conditions as the
+// original code from which it is derived.
+//
+// Much love to the original authors for their work.
+// **********************************************************
 // Copyright (C) 2019-2023, Ava Labs, Inc. All rights reserved.
 // See the file LICENSE for licensing terms.
 
@@ -24,6 +34,10 @@ type Manager interface {
 	GetBlock(blkID ids.ID) (snowman.Block, error)
 	GetStatelessBlock(blkID ids.ID) (blocks.Block, error)
 	NewBlock(blocks.Block) snowman.Block
+
+	// SetLastAccepted sets the most recently accepted block after the state
+	// was replaced by state sync.
+	SetLastAccepted(blkID ids.ID)
 }
 
 func NewManager(
@@ -32,6 +46,8 @@ func NewManager(
 	s state.State,
 	txExecutorBackend *executor.Backend,
 	recentlyAccepted window.Window[ids.ID],
+	onAccepting func(blocks.Block),
+	onCommit func(blocks.Block),
 ) Manager {
 	backend := &backend{
 		Mempool:      mempool,
@@ -52,6 +68,8 @@ func NewManager(
 			metrics:          metrics,
 			recentlyAccepted: recentlyAccepted,
 			bootstrapped:     txExecutorBackend.Bootstrapped,
+			onAccepting:      onAccepting,
+			onCommit:         onCommit,
 		},
 		rejector: &rejector{backend: backend},
 	}
@@ -76,6 +94,10 @@ func (m *manager) GetStatelessBlock(blkID ids.ID) (blocks.Block, error) {
 	return m.backend.GetBlock(blkID)
 }
 
+func (m *manager) SetLastAccepted(blkID ids.ID) {
+	m.backend.lastAccepted = blkID
+}
+
 func (m *manager) NewBlock(blk blocks.Block) snowman.Block {
 	return &Block{
 		manager: m,
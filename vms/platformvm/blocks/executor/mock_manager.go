@@ -112,3 +112,15 @@ func (mr *MockManagerMockRecorder) NewBlock(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewBlock", reflect.TypeOf((*MockManager)(nil).NewBlock), arg0)
 }
+
+// SetLastAccepted mocks base method.
+func (m *MockManager) SetLastAccepted(arg0 ids.ID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetLastAccepted", arg0)
+}
+
+// SetLastAccepted indicates an expected call of SetLastAccepted.
+func (mr *MockManagerMockRecorder) SetLastAccepted(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLastAccepted", reflect.TypeOf((*MockManager)(nil).SetLastAccepted), arg0)
+}
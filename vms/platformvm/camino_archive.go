@@ -0,0 +1,69 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+)
+
+var errArchiveDisabled = errors.New("archive is disabled")
+
+// getAllUTXOs returns the UTXOs of [addrs] after the block at [height] was
+// accepted. If [height] is nil, the current UTXOs are returned.
+func (s *Service) getAllUTXOs(addrs set.Set[ids.ShortID], height *json.Uint64) ([]*avax.UTXO, error) {
+	if height == nil {
+		return avax.GetAllUTXOs(s.vm.state, addrs)
+	}
+	if !s.vm.executionConfig.ArchiveEnabled {
+		return nil, errArchiveDisabled
+	}
+	return s.vm.state.GetArchivedUTXOs(addrs, uint64(*height))
+}
+
+// getDeposit returns the deposit [depositTxID] after the block at [height] was
+// accepted. If [height] is nil, the current deposit is returned.
+func (s *CaminoService) getDeposit(depositTxID ids.ID, height *json.Uint64) (*deposit.Deposit, error) {
+	if height == nil {
+		return s.vm.state.GetDeposit(depositTxID)
+	}
+	if !s.vm.executionConfig.ArchiveEnabled {
+		return nil, errArchiveDisabled
+	}
+	return s.vm.state.GetArchivedDeposit(depositTxID, uint64(*height))
+}
+
+// getClaimable returns the claimable of [ownerID] after the block at [height]
+// was accepted. If [height] is nil, the current claimable is returned.
+func (s *CaminoService) getClaimable(ownerID ids.ID, height *json.Uint64) (*state.Claimable, error) {
+	if height == nil {
+		return s.vm.state.GetClaimable(ownerID)
+	}
+	if !s.vm.executionConfig.ArchiveEnabled {
+		return nil, errArchiveDisabled
+	}
+	return s.vm.state.GetArchivedClaimable(ownerID, uint64(*height))
+}
+
+// getUnixTimestamp returns the chain time after the block at [height] was
+// accepted. If [height] is nil, the local time is returned.
+func (s *CaminoService) getUnixTimestamp(height *json.Uint64) (uint64, error) {
+	if height == nil {
+		return s.vm.clock.Unix(), nil
+	}
+	if !s.vm.executionConfig.ArchiveEnabled {
+		return 0, errArchiveDisabled
+	}
+	timestamp, err := s.vm.state.GetArchivedTimestamp(uint64(*height))
+	if err != nil {
+		return 0, err
+	}
+	return uint64(timestamp.Unix()), nil
+}
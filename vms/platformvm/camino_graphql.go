@@ -0,0 +1,1107 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/api/graphql"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/multisig"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/dac"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+var (
+	errMissingBlockArgs = errors.New("either id or height must be given")
+	errInvalidCursor    = errors.New("invalid cursor")
+)
+
+// graphQLDeposit is the source of the Deposit type.
+type graphQLDeposit struct {
+	ID ids.ID
+	*deposit.Deposit
+}
+
+// graphQLProposal is the source of the Proposal type.
+type graphQLProposal struct {
+	ID ids.ID
+	dac.ProposalState
+}
+
+// graphQLSchema builds the GraphQL schema of the P-chain. Its resolvers read
+// the state through the service [s]. They must be called while holding the
+// chain lock.
+type graphQLSchema struct {
+	s *CaminoService
+
+	blockType         *graphql.Object
+	txType            *graphql.Object
+	utxoType          *graphql.Object
+	utxosType         *graphql.Object
+	addressType       *graphql.Object
+	ownerType         *graphql.Object
+	multisigAliasType *graphql.Object
+	claimableType     *graphql.Object
+	depositType       *graphql.Object
+	depositOfferType  *graphql.Object
+	validatorType     *graphql.Object
+	proposalType      *graphql.Object
+}
+
+// newGraphQLSchema returns the GraphQL schema of the P-chain, whose resolvers
+// read the state through [s].
+func newGraphQLSchema(s *CaminoService) (*graphql.Schema, error) {
+	g := &graphQLSchema{
+		s:                 s,
+		blockType:         &graphql.Object{Name: "Block", Description: "Accepted block"},
+		txType:            &graphql.Object{Name: "Tx", Description: "Transaction"},
+		utxoType:          &graphql.Object{Name: "UTXO", Description: "Unspent transaction output"},
+		addressType:       &graphql.Object{Name: "Address", Description: "Address of the P-chain"},
+		ownerType:         &graphql.Object{Name: "Owner", Description: "Addresses, that own an output or a reward, and their threshold"},
+		multisigAliasType: &graphql.Object{Name: "MultisigAlias", Description: "Alias of a multisig owner"},
+		claimableType:     &graphql.Object{Name: "Claimable", Description: "Rewards, that can be claimed by an owner"},
+		depositType:       &graphql.Object{Name: "Deposit", Description: "Tokens, that are deposited with a deposit offer"},
+		depositOfferType:  &graphql.Object{Name: "DepositOffer", Description: "Offer, that deposits can be created with"},
+		validatorType:     &graphql.Object{Name: "Validator", Description: "Current validator"},
+		proposalType:      &graphql.Object{Name: "Proposal", Description: "Proposal, that is voted on"},
+	}
+	// The connection type is shared by the fields, that return UTXOs.
+	g.utxosType = graphql.NewConnectionType(g.utxoType)
+	g.blockType.Fields = g.blockFields()
+	g.txType.Fields = g.txFields()
+	g.utxoType.Fields = g.utxoFields()
+	g.addressType.Fields = g.addressFields()
+	g.ownerType.Fields = g.ownerFields()
+	g.multisigAliasType.Fields = g.multisigAliasFields()
+	g.claimableType.Fields = g.claimableFields()
+	g.depositType.Fields = g.depositFields()
+	g.depositOfferType.Fields = g.depositOfferFields()
+	g.validatorType.Fields = g.validatorFields()
+	g.proposalType.Fields = g.proposalFields()
+	return graphql.NewSchema(&graphql.Object{
+		Name:   "Query",
+		Fields: g.queryFields(),
+	})
+}
+
+func (g *graphQLSchema) queryFields() graphql.Fields {
+	return graphql.Fields{
+		"chainTime": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Unix time of the last accepted block",
+			Resolve: func(graphql.ResolveParams) (interface{}, error) {
+				return g.s.vm.state.GetTimestamp().Unix(), nil
+			},
+		},
+		"lastAcceptedBlock": {
+			Type: graphql.NewNonNull(g.blockType),
+			Resolve: func(graphql.ResolveParams) (interface{}, error) {
+				return g.getBlock(g.s.vm.state.GetLastAccepted())
+			},
+		},
+		"block": {
+			Type:        g.blockType,
+			Description: "Accepted block with the given ID or at the given height",
+			Args: graphql.Args{
+				"id":     {Type: graphql.ID},
+				"height": {Type: graphql.Uint64},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				var blkID ids.ID
+				switch {
+				case p.Args["id"] != nil:
+					var err error
+					if blkID, err = ids.FromString(p.Args["id"].(string)); err != nil {
+						return nil, err
+					}
+				case p.Args["height"] != nil:
+					var err error
+					blkID, err = g.s.vm.state.GetBlockIDAtHeight(p.Args["height"].(uint64))
+					if err == database.ErrNotFound {
+						return nil, nil
+					}
+					if err != nil {
+						return nil, err
+					}
+				default:
+					return nil, errMissingBlockArgs
+				}
+				return nullIfNotFound(g.getBlock(blkID))
+			},
+		},
+		"blocks": {
+			Type:        graphql.NewNonNull(graphql.NewConnectionType(g.blockType)),
+			Description: "Accepted blocks ordered by descending height, starting at the last accepted block",
+			Args:        graphql.ConnectionArgs(nil),
+			Resolve:     g.resolveBlocks,
+		},
+		"tx": {
+			Type: g.txType,
+			Args: graphql.Args{
+				"id": {Type: graphql.NewNonNull(graphql.ID)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				txID, err := ids.FromString(p.Args["id"].(string))
+				if err != nil {
+					return nil, err
+				}
+				tx, _, err := g.s.vm.state.GetTx(txID)
+				if err == database.ErrNotFound {
+					return nil, nil
+				}
+				return tx, err
+			},
+		},
+		"address": {
+			Type: g.addressType,
+			Args: graphql.Args{
+				"address": {Type: graphql.NewNonNull(graphql.String)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return avax.ParseServiceAddress(g.s.addrManager, p.Args["address"].(string))
+			},
+		},
+		"utxos": {
+			Type:        graphql.NewNonNull(g.utxosType),
+			Description: "UTXOs of the given addresses",
+			Args: graphql.ConnectionArgs(graphql.Args{
+				"addresses": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+			}),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				addrs := set.NewSet[ids.ShortID](len(p.Args["addresses"].([]interface{})))
+				for _, addrStr := range p.Args["addresses"].([]interface{}) {
+					addr, err := avax.ParseServiceAddress(g.s.addrManager, addrStr.(string))
+					if err != nil {
+						return nil, err
+					}
+					addrs.Add(addr)
+				}
+				return g.getUTXOs(p, addrs)
+			},
+		},
+		"claimable": {
+			Type:        g.claimableType,
+			Description: "Rewards, that can be claimed by the owner with the given addresses, threshold and locktime",
+			Args: graphql.Args{
+				"addresses": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+				"threshold": {Type: graphql.Int, DefaultValue: 1},
+				"locktime":  {Type: graphql.Uint64, DefaultValue: "0"},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				owner := &secp256k1fx.OutputOwners{
+					Locktime:  p.Args["locktime"].(uint64),
+					Threshold: uint32(p.Args["threshold"].(int)),
+				}
+				for _, addrStr := range p.Args["addresses"].([]interface{}) {
+					addr, err := avax.ParseServiceAddress(g.s.addrManager, addrStr.(string))
+					if err != nil {
+						return nil, err
+					}
+					owner.Addrs = append(owner.Addrs, addr)
+				}
+				owner.Sort()
+				return g.getClaimable(owner)
+			},
+		},
+		"currentValidators": {
+			Type:        graphql.NewNonNull(graphql.NewConnectionType(g.validatorType)),
+			Description: "Current validators of the given subnet ordered by their tx ID",
+			Args: graphql.ConnectionArgs(graphql.Args{
+				"subnetID": {Type: graphql.ID, DefaultValue: constants.PrimaryNetworkID.String()},
+			}),
+			Resolve: g.resolveCurrentValidators,
+		},
+		"depositOffers": {
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.depositOfferType))),
+			Description: "Deposit offers ordered by their ID. If activeAt is given, only the offers active at this unix time are returned",
+			Args: graphql.Args{
+				"activeAt": {Type: graphql.Uint64},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				offers, err := g.s.vm.state.GetAllDepositOffers()
+				if err != nil {
+					return nil, err
+				}
+				if activeAt, ok := p.Args["activeAt"].(uint64); ok {
+					activeOffers := offers[:0]
+					for _, offer := range offers {
+						if offer.Start <= activeAt && offer.End >= activeAt {
+							activeOffers = append(activeOffers, offer)
+						}
+					}
+					offers = activeOffers
+				}
+				slices.SortFunc(offers, func(a, b *deposit.Offer) bool {
+					return bytes.Compare(a.ID[:], b.ID[:]) < 0
+				})
+				return offers, nil
+			},
+		},
+		"depositOffer": {
+			Type: g.depositOfferType,
+			Args: graphql.Args{
+				"id": {Type: graphql.NewNonNull(graphql.ID)},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				offerID, err := ids.FromString(p.Args["id"].(string))
+				if err != nil {
+					return nil, err
+				}
+				return nullIfNotFound(g.s.vm.state.GetDepositOffer(offerID))
+			},
+		},
+		"deposit": {
+			Type: g.depositType,
+			Args: graphql.Args{
+				"id": {Type: graphql.NewNonNull(graphql.ID), Description: "ID of the deposit tx"},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				depositTxID, err := ids.FromString(p.Args["id"].(string))
+				if err != nil {
+					return nil, err
+				}
+				return g.getDeposit(depositTxID)
+			},
+		},
+		"proposal": {
+			Type: g.proposalType,
+			Args: graphql.Args{
+				"id": {Type: graphql.NewNonNull(graphql.ID), Description: "ID of the tx, that added the proposal"},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				proposalID, err := ids.FromString(p.Args["id"].(string))
+				if err != nil {
+					return nil, err
+				}
+				proposal, err := g.s.vm.state.GetProposal(proposalID)
+				if err == database.ErrNotFound {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				return &graphQLProposal{ID: proposalID, ProposalState: proposal}, nil
+			},
+		},
+		"proposals": {
+			Type:        graphql.NewNonNull(graphql.NewConnectionType(g.proposalType)),
+			Description: "Active proposals ordered by their ID",
+			Args:        graphql.ConnectionArgs(nil),
+			Resolve:     g.resolveProposals,
+		},
+	}
+}
+
+func (g *graphQLSchema) blockFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(blocks.Block).ID(), nil
+			},
+		},
+		"type": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return typeName(p.Source), nil
+			},
+		},
+		"height": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(blocks.Block).Height(), nil
+			},
+		},
+		"timestamp": {
+			Type:        graphql.Uint64,
+			Description: "Unix time of the block. Null for blocks before the Banff upgrade",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if blk, ok := p.Source.(blocks.BanffBlock); ok {
+					return blk.Timestamp().Unix(), nil
+				}
+				return nil, nil
+			},
+		},
+		"parent": {
+			Type:        g.blockType,
+			Description: "Parent of the block. Null for the genesis block or if the parent was pruned",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				blk := p.Source.(blocks.Block)
+				if blk.Height() == 0 {
+					return nil, nil
+				}
+				return nullIfNotFound(g.getBlock(blk.Parent()))
+			},
+		},
+		"txs": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.txType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(blocks.Block).Txs(), nil
+			},
+		},
+		"json": {
+			Type:        graphql.NewNonNull(graphql.JSON),
+			Description: "JSON representation of the block",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				blk := p.Source.(blocks.Block)
+				blk.InitCtx(g.s.vm.ctx)
+				return blk, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) txFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*txs.Tx).ID(), nil
+			},
+		},
+		"type": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return typeName(p.Source.(*txs.Tx).Unsigned), nil
+			},
+		},
+		"status": {
+			Type:        graphql.String,
+			Description: "Status of the tx. Null if it isn't stored",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				_, status, err := g.s.vm.state.GetTx(p.Source.(*txs.Tx).ID())
+				if err == database.ErrNotFound {
+					return nil, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+				return status.String(), nil
+			},
+		},
+		"deposit": {
+			Type:        g.depositType,
+			Description: "Deposit created by the tx",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx := p.Source.(*txs.Tx)
+				if _, ok := tx.Unsigned.(*txs.DepositTx); !ok {
+					return nil, nil
+				}
+				return g.getDeposit(tx.ID())
+			},
+		},
+		"json": {
+			Type:        graphql.NewNonNull(graphql.JSON),
+			Description: "JSON representation of the tx",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx := p.Source.(*txs.Tx)
+				tx.Unsigned.InitCtx(g.s.vm.ctx)
+				return tx, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) utxoFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*avax.UTXO).InputID(), nil
+			},
+		},
+		"tx": {
+			Type:        g.txType,
+			Description: "Tx, that created the UTXO. Null if it was pruned",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx, _, err := g.s.vm.state.GetTx(p.Source.(*avax.UTXO).TxID)
+				if err == database.ErrNotFound {
+					return nil, nil
+				}
+				return tx, err
+			},
+		},
+		"outputIndex": {
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*avax.UTXO).OutputIndex, nil
+			},
+		},
+		"assetID": {
+			Type: graphql.NewNonNull(graphql.ID),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*avax.UTXO).AssetID(), nil
+			},
+		},
+		"amount": {
+			Type: graphql.Uint64,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if out, ok := p.Source.(*avax.UTXO).Out.(avax.Amounter); ok {
+					return out.Amount(), nil
+				}
+				return nil, nil
+			},
+		},
+		"addresses": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.addressType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				out, ok := p.Source.(*avax.UTXO).Out.(avax.Addressable)
+				if !ok {
+					return []ids.ShortID{}, nil
+				}
+				addrs := make([]ids.ShortID, len(out.Addresses()))
+				for i, addrBytes := range out.Addresses() {
+					addr, err := ids.ToShortID(addrBytes)
+					if err != nil {
+						return nil, err
+					}
+					addrs[i] = addr
+				}
+				return addrs, nil
+			},
+		},
+		"json": {
+			Type:        graphql.NewNonNull(graphql.JSON),
+			Description: "JSON representation of the UTXO",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				utxo := p.Source.(*avax.UTXO)
+				if out, ok := utxo.Out.(snow.ContextInitializable); ok {
+					out.InitCtx(g.s.vm.ctx)
+				}
+				return utxo, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) addressFields() graphql.Fields {
+	return graphql.Fields{
+		"address": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return g.s.addrManager.FormatLocalAddress(p.Source.(ids.ShortID))
+			},
+		},
+		"states": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Bitfield of the address states",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				addrStates, err := g.s.vm.state.GetAddressStates(p.Source.(ids.ShortID))
+				return uint64(addrStates), err
+			},
+		},
+		"multisigAlias": {
+			Type:        g.multisigAliasType,
+			Description: "Multisig alias, if the address is one",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return nullIfNotFound(g.s.vm.state.GetMultisigAlias(p.Source.(ids.ShortID)))
+			},
+		},
+		"utxos": {
+			Type: graphql.NewNonNull(g.utxosType),
+			Args: graphql.ConnectionArgs(nil),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				addrs := set.NewSet[ids.ShortID](1)
+				addrs.Add(p.Source.(ids.ShortID))
+				return g.getUTXOs(p, addrs)
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) ownerFields() graphql.Fields {
+	return graphql.Fields{
+		"locktime": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"threshold": {
+			Type: graphql.NewNonNull(graphql.Int),
+		},
+		"addresses": {
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(g.addressType))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*secp256k1fx.OutputOwners).Addrs, nil
+			},
+		},
+		"claimable": {
+			Type:        g.claimableType,
+			Description: "Rewards, that can be claimed by the owner",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return g.getClaimable(p.Source.(*secp256k1fx.OutputOwners))
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) multisigAliasFields() graphql.Fields {
+	return graphql.Fields{
+		"address": {
+			Type: graphql.NewNonNull(g.addressType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*multisig.AliasWithNonce).ID, nil
+			},
+		},
+		"memo": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return formatting.Encode(formatting.HexNC, p.Source.(*multisig.AliasWithNonce).Memo)
+			},
+		},
+		"nonce": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Number of times the owner of the alias changed",
+		},
+		"owner": {
+			Type: graphql.NewNonNull(g.ownerType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				owner, ok := p.Source.(*multisig.AliasWithNonce).Owners.(*secp256k1fx.OutputOwners)
+				if !ok {
+					return nil, ErrWrongOwnerType
+				}
+				return owner, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) claimableFields() graphql.Fields {
+	return graphql.Fields{
+		"owner": {
+			Type: graphql.NewNonNull(g.ownerType),
+		},
+		"validatorRewards": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*state.Claimable).ValidatorReward, nil
+			},
+		},
+		"expiredDepositRewards": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*state.Claimable).ExpiredDepositReward, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) depositFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "ID of the deposit tx",
+		},
+		"tx": {
+			Type:        g.txType,
+			Description: "Deposit tx. Null if it was pruned",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx, _, err := g.s.vm.state.GetTx(p.Source.(*graphQLDeposit).ID)
+				if err == database.ErrNotFound {
+					return nil, nil
+				}
+				return tx, err
+			},
+		},
+		"offer": {
+			Type: graphql.NewNonNull(g.depositOfferType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return g.s.vm.state.GetDepositOffer(p.Source.(*graphQLDeposit).DepositOfferID)
+			},
+		},
+		"amount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"unlockedAmount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"claimedRewardAmount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"unlockableAmount": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Amount, that can be unlocked at the local time",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				d := p.Source.(*graphQLDeposit)
+				offer, err := g.s.vm.state.GetDepositOffer(d.DepositOfferID)
+				if err != nil {
+					return nil, err
+				}
+				return d.UnlockableAmount(offer, g.s.vm.clock.Unix()), nil
+			},
+		},
+		"claimableReward": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Reward, that can be claimed at the local time",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				d := p.Source.(*graphQLDeposit)
+				offer, err := g.s.vm.state.GetDepositOffer(d.DepositOfferID)
+				if err != nil {
+					return nil, err
+				}
+				return d.ClaimableReward(offer, g.s.vm.clock.Unix()), nil
+			},
+		},
+		"start": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Unix time, when the deposit was created",
+		},
+		"duration": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Duration of the deposit in seconds",
+		},
+		"rewardOwner": {
+			Type: graphql.NewNonNull(g.ownerType),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				owner, ok := p.Source.(*graphQLDeposit).RewardOwner.(*secp256k1fx.OutputOwners)
+				if !ok {
+					return nil, ErrWrongOwnerType
+				}
+				return owner, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) depositOfferFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type: graphql.NewNonNull(graphql.ID),
+		},
+		"upgradeVersion": {
+			Type: graphql.NewNonNull(graphql.Int),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*deposit.Offer).UpgradeVersionID.Version(), nil
+			},
+		},
+		"interestRateNominator": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"start": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Unix time, when the offer becomes active",
+		},
+		"end": {
+			Type:        graphql.NewNonNull(graphql.Uint64),
+			Description: "Unix time, when the offer becomes inactive",
+		},
+		"minAmount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"totalMaxAmount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"depositedAmount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"minDuration": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"maxDuration": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"unlockPeriodDuration": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"noRewardsPeriodDuration": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"memo": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return formatting.Encode(formatting.HexNC, p.Source.(*deposit.Offer).Memo)
+			},
+		},
+		"flags": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return uint64(p.Source.(*deposit.Offer).Flags), nil
+			},
+		},
+		"totalMaxRewardAmount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"rewardedAmount": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"ownerAddress": {
+			Type:        g.addressType,
+			Description: "Address, that can permit the creation of deposits with the offer",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				ownerAddr := p.Source.(*deposit.Offer).OwnerAddress
+				if ownerAddr == ids.ShortEmpty {
+					return nil, nil
+				}
+				return ownerAddr, nil
+			},
+		},
+	}
+}
+
+func (g *graphQLSchema) validatorFields() graphql.Fields {
+	return graphql.Fields{
+		"tx": {
+			Type:        g.txType,
+			Description: "Tx, that added the validator. Null if it was pruned",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx, _, err := g.s.vm.state.GetTx(p.Source.(*state.Staker).TxID)
+				if err == database.ErrNotFound {
+					return nil, nil
+				}
+				return tx, err
+			},
+		},
+		"nodeID": {
+			Type: graphql.NewNonNull(graphql.ID),
+		},
+		"subnetID": {
+			Type: graphql.NewNonNull(graphql.ID),
+		},
+		"weight": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+		"startTime": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*state.Staker).StartTime.Unix(), nil
+			},
+		},
+		"endTime": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*state.Staker).EndTime.Unix(), nil
+			},
+		},
+		"potentialReward": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+		},
+	}
+}
+
+func (g *graphQLSchema) proposalFields() graphql.Fields {
+	return graphql.Fields{
+		"id": {
+			Type:        graphql.NewNonNull(graphql.ID),
+			Description: "ID of the tx, that added the proposal",
+		},
+		"type": {
+			Type: graphql.NewNonNull(graphql.String),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return typeName(p.Source.(*graphQLProposal).ProposalState), nil
+			},
+		},
+		"tx": {
+			Type:        g.txType,
+			Description: "Tx, that added the proposal. Null if it was pruned",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				tx, _, err := g.s.vm.state.GetTx(p.Source.(*graphQLProposal).ID)
+				if err == database.ErrNotFound {
+					return nil, nil
+				}
+				return tx, err
+			},
+		},
+		"endTime": {
+			Type: graphql.NewNonNull(graphql.Uint64),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*graphQLProposal).EndTime().Unix(), nil
+			},
+		},
+		"canBeFinished": {
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "True, if more votes can't change the outcome of the proposal",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*graphQLProposal).CanBeFinished(), nil
+			},
+		},
+		"json": {
+			Type:        graphql.NewNonNull(graphql.JSON),
+			Description: "JSON representation of the proposal state",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*graphQLProposal).ProposalState, nil
+			},
+		},
+	}
+}
+
+// resolveBlocks returns the page of accepted blocks below the height in the
+// cursor. Pruned blocks end the connection.
+func (g *graphQLSchema) resolveBlocks(p graphql.ResolveParams) (interface{}, error) {
+	first, after, err := graphql.PageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	var height uint64
+	if after == "" {
+		lastAccepted, err := g.getBlock(g.s.vm.state.GetLastAccepted())
+		if err != nil {
+			return nil, err
+		}
+		height = lastAccepted.Height()
+	} else {
+		afterHeight, err := strconv.ParseUint(after, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+		if afterHeight == 0 {
+			return graphql.NewConnection(nil, false), nil
+		}
+		height = afterHeight - 1
+	}
+
+	var edges []graphql.Edge
+	for {
+		blkID, err := g.s.vm.state.GetBlockIDAtHeight(height)
+		if err == database.ErrNotFound {
+			return graphql.NewConnection(edges, false), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(edges) == first {
+			return graphql.NewConnection(edges, true), nil
+		}
+		blk, err := g.getBlock(blkID)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, graphql.Edge{
+			Cursor: strconv.FormatUint(height, 10),
+			Node:   blk,
+		})
+		if height == 0 {
+			return graphql.NewConnection(edges, false), nil
+		}
+		height--
+	}
+}
+
+// resolveCurrentValidators returns the page of current validators of a subnet
+// after the tx ID in the cursor.
+func (g *graphQLSchema) resolveCurrentValidators(p graphql.ResolveParams) (interface{}, error) {
+	first, after, err := graphql.PageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	afterTxID, err := parseIDCursor(after)
+	if err != nil {
+		return nil, err
+	}
+	subnetID, err := ids.FromString(p.Args["subnetID"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	stakerIterator, err := g.s.vm.state.GetCurrentStakerIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer stakerIterator.Release()
+
+	var validators []*state.Staker
+	for stakerIterator.Next() {
+		staker := stakerIterator.Value()
+		if staker.SubnetID != subnetID || !isValidatorPriority(staker.Priority) {
+			continue
+		}
+		if after != "" && bytes.Compare(staker.TxID[:], afterTxID[:]) <= 0 {
+			continue
+		}
+		validators = append(validators, staker)
+	}
+	slices.SortFunc(validators, func(a, b *state.Staker) bool {
+		return bytes.Compare(a.TxID[:], b.TxID[:]) < 0
+	})
+
+	hasNextPage := len(validators) > first
+	if hasNextPage {
+		validators = validators[:first]
+	}
+	edges := make([]graphql.Edge, len(validators))
+	for i, validator := range validators {
+		edges[i] = graphql.Edge{
+			Cursor: validator.TxID.String(),
+			Node:   validator,
+		}
+	}
+	return graphql.NewConnection(edges, hasNextPage), nil
+}
+
+// resolveProposals returns the page of proposals after the proposal ID in the
+// cursor.
+func (g *graphQLSchema) resolveProposals(p graphql.ResolveParams) (interface{}, error) {
+	first, after, err := graphql.PageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	afterProposalID, err := parseIDCursor(after)
+	if err != nil {
+		return nil, err
+	}
+
+	proposalsIterator, err := g.s.vm.state.GetProposalIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer proposalsIterator.Release()
+
+	var edges []graphql.Edge
+	for proposalsIterator.Next() {
+		proposalID, err := proposalsIterator.ProposalID()
+		if err != nil {
+			return nil, err
+		}
+		if after != "" && bytes.Compare(proposalID[:], afterProposalID[:]) <= 0 {
+			continue
+		}
+		if len(edges) == first {
+			return graphql.NewConnection(edges, true), nil
+		}
+		proposal, err := proposalsIterator.Value()
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, graphql.Edge{
+			Cursor: proposalID.String(),
+			Node:   &graphQLProposal{ID: proposalID, ProposalState: proposal},
+		})
+	}
+	if err := proposalsIterator.Error(); err != nil {
+		return nil, err
+	}
+	return graphql.NewConnection(edges, false), nil
+}
+
+// getUTXOs returns the page of UTXOs of [addrs] after the cursor. The cursor
+// is the address and the ID of the last UTXO of the previous page joined by a
+// colon. Like with getUTXOs of the JSON-RPC API, UTXOs owned by several of
+// [addrs] can be returned on several pages.
+func (g *graphQLSchema) getUTXOs(p graphql.ResolveParams, addrs set.Set[ids.ShortID]) (interface{}, error) {
+	first, after, err := graphql.PageArgs(p)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		lastAddr   ids.ShortID
+		lastUTXOID ids.ID
+	)
+	if after != "" {
+		addrStr, utxoIDStr, ok := strings.Cut(after, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+		if lastAddr, err = ids.ShortFromString(addrStr); err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+		if lastUTXOID, err = ids.FromString(utxoIDStr); err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidCursor, after)
+		}
+	}
+
+	// The UTXOs are fetched one by one to get the cursor of every edge.
+	var (
+		edges []graphql.Edge
+		seen  set.Set[ids.ID]
+	)
+	for {
+		utxos, addr, utxoID, err := avax.GetPaginatedUTXOs(g.s.vm.state, addrs, lastAddr, lastUTXOID, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(utxos) == 0 {
+			return graphql.NewConnection(edges, false), nil
+		}
+		lastAddr, lastUTXOID = addr, utxoID
+		if seen.Contains(utxoID) {
+			continue
+		}
+		if len(edges) == first {
+			return graphql.NewConnection(edges, true), nil
+		}
+		seen.Add(utxoID)
+		edges = append(edges, graphql.Edge{
+			Cursor: addr.String() + ":" + utxoID.String(),
+			Node:   utxos[0],
+		})
+	}
+}
+
+func (g *graphQLSchema) getBlock(blkID ids.ID) (blocks.Block, error) {
+	blk, _, err := g.s.vm.state.GetStatelessBlock(blkID)
+	return blk, err
+}
+
+func (g *graphQLSchema) getDeposit(depositTxID ids.ID) (interface{}, error) {
+	d, err := g.s.vm.state.GetDeposit(depositTxID)
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &graphQLDeposit{ID: depositTxID, Deposit: d}, nil
+}
+
+func (g *graphQLSchema) getClaimable(owner *secp256k1fx.OutputOwners) (interface{}, error) {
+	ownerID, err := txs.GetOwnerID(owner)
+	if err != nil {
+		return nil, err
+	}
+	return nullIfNotFound(g.s.vm.state.GetClaimable(ownerID))
+}
+
+// nullIfNotFound returns [value] and [err], unless [err] is
+// database.ErrNotFound. Then, it returns null.
+func nullIfNotFound[T any](value T, err error) (interface{}, error) {
+	if err == database.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func parseIDCursor(cursor string) (ids.ID, error) {
+	if cursor == "" {
+		return ids.Empty, nil
+	}
+	id, err := ids.FromString(cursor)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("%w: %s", errInvalidCursor, cursor)
+	}
+	return id, nil
+}
+
+func isValidatorPriority(priority txs.Priority) bool {
+	switch priority {
+	case txs.PrimaryNetworkValidatorCurrentPriority,
+		txs.SubnetPermissionedValidatorCurrentPriority,
+		txs.SubnetPermissionlessValidatorCurrentPriority:
+		return true
+	default:
+		return false
+	}
+}
+
+// typeName returns the name of the concrete type of [v].
+func typeName(v interface{}) string {
+	return reflect.Indirect(reflect.ValueOf(v)).Type().Name()
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api/graphql"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/multisig"
+	as "github.com/ava-labs/avalanchego/vms/platformvm/addrstate"
+	"github.com/ava-labs/avalanchego/vms/platformvm/api"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/test"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestGraphQLSchema(t *testing.T) {
+	s := newCaminoService(t, api.Camino{}, test.PhaseLast, nil)
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	schema, err := newGraphQLSchema(s)
+	require.NoError(t, err)
+
+	var (
+		fundedAddr       = test.FundedKeys[0].Address()
+		aliasAddr        = ids.ShortID{1}
+		depositTxID      = ids.ID{2}
+		offer            = &deposit.Offer{ID: ids.ID{3}, Start: 10, End: 100, MinAmount: 1, Memo: []byte{4}}
+		inactiveOffer    = &deposit.Offer{ID: ids.ID{5}, Start: 200, End: 300}
+		fundedAddrOwners = &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{fundedAddr}}
+	)
+	fundedAddrStr, err := s.addrManager.FormatLocalAddress(fundedAddr)
+	require.NoError(t, err)
+	aliasAddrStr, err := s.addrManager.FormatLocalAddress(aliasAddr)
+	require.NoError(t, err)
+	ownerID, err := txs.GetOwnerID(fundedAddrOwners)
+	require.NoError(t, err)
+
+	s.vm.state.SetDepositOffer(offer)
+	s.vm.state.SetDepositOffer(inactiveOffer)
+	s.vm.state.AddDeposit(depositTxID, &deposit.Deposit{
+		DepositOfferID: offer.ID,
+		Start:          10,
+		Duration:       50,
+		Amount:         1000,
+		RewardOwner:    fundedAddrOwners,
+	})
+	s.vm.state.SetClaimable(ownerID, &state.Claimable{
+		Owner:                fundedAddrOwners,
+		ValidatorReward:      10,
+		ExpiredDepositReward: 20,
+	})
+	s.vm.state.SetMultisigAlias(aliasAddr, &multisig.AliasWithNonce{
+		Alias: multisig.Alias{
+			ID:     aliasAddr,
+			Memo:   []byte{6},
+			Owners: fundedAddrOwners,
+		},
+		Nonce: 1,
+	})
+
+	tests := map[string]struct {
+		query    string
+		expected string
+	}{
+		"blocks": {
+			query:    `{ blocks(first: 1) { nodes { height type timestamp parent { id } txs { id } } pageInfo { hasNextPage endCursor } } }`,
+			expected: `{"data":{"blocks":{"nodes":[{"height":"0","type":"ApricotCommitBlock","timestamp":null,"parent":null,"txs":[]}],"pageInfo":{"hasNextPage":false,"endCursor":"0"}}}}`,
+		},
+		"block at unknown height": {
+			query:    `{ block(height: "1") { id } }`,
+			expected: `{"data":{"block":null}}`,
+		},
+		"unknown tx": {
+			query:    fmt.Sprintf(`{ tx(id: %q) { id } }`, ids.ID{7}),
+			expected: `{"data":{"tx":null}}`,
+		},
+		"current validators": {
+			query:    `{ currentValidators(first: 1) { nodes { weight subnetID } pageInfo { hasNextPage } } }`,
+			expected: fmt.Sprintf(`{"data":{"currentValidators":{"nodes":[{"weight":"%d","subnetID":"11111111111111111111111111111111LpoYY"}],"pageInfo":{"hasNextPage":true}}}}`, test.ValidatorWeight),
+		},
+		"active deposit offers": {
+			query:    `{ depositOffers(activeAt: "50") { id start end minAmount memo ownerAddress { address } } }`,
+			expected: fmt.Sprintf(`{"data":{"depositOffers":[{"id":%q,"start":"10","end":"100","minAmount":"1","memo":"0x04","ownerAddress":null}]}}`, offer.ID),
+		},
+		"deposit with offer and reward owner": {
+			query: fmt.Sprintf(`{ deposit(id: %q) { id amount duration tx { id } offer { id } rewardOwner { threshold addresses { address } claimable { validatorRewards } } } }`, depositTxID),
+			expected: fmt.Sprintf(
+				`{"data":{"deposit":{"id":%q,"amount":"1000","duration":"50","tx":null,"offer":{"id":%q},"rewardOwner":{"threshold":1,"addresses":[{"address":%q}],"claimable":{"validatorRewards":"10"}}}}}`,
+				depositTxID, offer.ID, fundedAddrStr,
+			),
+		},
+		"claimable": {
+			query:    fmt.Sprintf(`{ claimable(addresses: [%q]) { validatorRewards expiredDepositRewards } }`, fundedAddrStr),
+			expected: `{"data":{"claimable":{"validatorRewards":"10","expiredDepositRewards":"20"}}}`,
+		},
+		"multisig alias": {
+			query: fmt.Sprintf(`{ address(address: %q) { multisigAlias { memo nonce owner { addresses { address states } } } } }`, aliasAddrStr),
+			expected: fmt.Sprintf(
+				`{"data":{"address":{"multisigAlias":{"memo":"0x06","nonce":"1","owner":{"addresses":[{"address":%q,"states":"%d"}]}}}}}`,
+				fundedAddrStr, as.AddressStateConsortium,
+			),
+		},
+		"no proposals": {
+			query:    `{ proposals { nodes { id } pageInfo { hasNextPage endCursor } } }`,
+			expected: `{"data":{"proposals":{"nodes":[],"pageInfo":{"hasNextPage":false,"endCursor":null}}}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := schema.Execute(context.Background(), &graphql.Request{Query: tt.query})
+			responseBytes, err := stdjson.Marshal(response)
+			require.NoError(t, err)
+			require.JSONEq(t, tt.expected, string(responseBytes))
+		})
+	}
+}
+
+func TestGraphQLUTXOsPagination(t *testing.T) {
+	require := require.New(t)
+
+	const additionalAmount = 10
+	s := newCaminoService(t, api.Camino{}, test.PhaseLast, []api.UTXO{{
+		Amount:  additionalAmount,
+		Address: test.FundedKeysBech32[0],
+	}})
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	schema, err := newGraphQLSchema(s)
+	require.NoError(err)
+	fundedAddrStr, err := s.addrManager.FormatLocalAddress(test.FundedKeys[0].Address())
+	require.NoError(err)
+
+	type page struct {
+		Data struct {
+			UTXOs struct {
+				Nodes []struct {
+					Amount string `json:"amount"`
+				} `json:"nodes"`
+				PageInfo graphql.PageInfo `json:"pageInfo"`
+			} `json:"utxos"`
+		} `json:"data"`
+	}
+
+	// The funded address owns the pre-funded and the additional UTXO.
+	var (
+		amounts []string
+		after   string
+	)
+	for i := 0; i < 2; i++ {
+		response := schema.Execute(context.Background(), &graphql.Request{
+			Query: `query ($addr: String!, $after: String) {
+				utxos(addresses: [$addr], first: 1, after: $after) { nodes { amount } pageInfo { hasNextPage endCursor } }
+			}`,
+			Variables: map[string]interface{}{
+				"addr":  fundedAddrStr,
+				"after": after,
+			},
+		})
+		require.Empty(response.Errors)
+		responseBytes, err := stdjson.Marshal(response)
+		require.NoError(err)
+		p := page{}
+		require.NoError(stdjson.Unmarshal(responseBytes, &p))
+		require.Len(p.Data.UTXOs.Nodes, 1)
+		require.Equal(i == 0, p.Data.UTXOs.PageInfo.HasNextPage)
+		amounts = append(amounts, p.Data.UTXOs.Nodes[0].Amount)
+		after = *p.Data.UTXOs.PageInfo.EndCursor
+	}
+	require.ElementsMatch([]string{
+		fmt.Sprint(test.PreFundedBalance),
+		fmt.Sprint(additionalAmount),
+	}, amounts)
+}
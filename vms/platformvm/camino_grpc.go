@@ -0,0 +1,302 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ava-labs/avalanchego/api"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+
+	platformv1 "github.com/ava-labs/avalanchego/proto/pb/platform/v1"
+	utilsjson "github.com/ava-labs/avalanchego/utils/json"
+	platformapi "github.com/ava-labs/avalanchego/vms/platformvm/api"
+)
+
+var (
+	_ platformv1.PlatformServer = (*grpcService)(nil)
+
+	errUnexpectedEncoding = errors.New("unexpected encoding of API response")
+)
+
+// grpcService serves the read methods of the platform API over gRPC
+type grpcService struct {
+	platformv1.UnsafePlatformServer
+	service *CaminoService
+}
+
+func newGRPCServer(service *CaminoService) *grpc.Server {
+	server := grpc.NewServer()
+	platformv1.RegisterPlatformServer(server, &grpcService{
+		service: service,
+	})
+	return server
+}
+
+func (s *grpcService) GetHeight(ctx context.Context, _ *platformv1.GetHeightRequest) (*platformv1.GetHeightResponse, error) {
+	reply := api.GetHeightResponse{}
+	if err := s.service.GetHeight((&http.Request{}).WithContext(ctx), nil, &reply); err != nil {
+		return nil, err
+	}
+	return &platformv1.GetHeightResponse{
+		Height: uint64(reply.Height),
+	}, nil
+}
+
+func (s *grpcService) GetTimestamp(context.Context, *platformv1.GetTimestampRequest) (*platformv1.GetTimestampResponse, error) {
+	reply := GetTimestampReply{}
+	if err := s.service.GetTimestamp(nil, nil, &reply); err != nil {
+		return nil, err
+	}
+	return &platformv1.GetTimestampResponse{
+		Timestamp: timestamppb.New(reply.Timestamp),
+	}, nil
+}
+
+func (s *grpcService) GetBalance(_ context.Context, req *platformv1.GetBalanceRequest) (*platformv1.GetBalanceResponse, error) {
+	reply := GetBalanceResponseWrapper{}
+	if err := s.service.GetBalance(nil, &GetBalanceRequest{
+		Addresses: req.Addresses,
+		Height:    (*utilsjson.Uint64)(req.Height),
+	}, &reply); err != nil {
+		return nil, err
+	}
+
+	if !reply.LockModeBondDeposit {
+		return &platformv1.GetBalanceResponse{
+			Balances:                  assetAmounts(reply.avax.Balances),
+			UnlockedOutputs:           assetAmounts(reply.avax.Unlockeds),
+			LockedStakeableOutputs:    assetAmounts(reply.avax.LockedStakeables),
+			LockedNotStakeableOutputs: assetAmounts(reply.avax.LockedNotStakeables),
+			UtxoIds:                   utxoIDStrings(reply.avax.UTXOIDs),
+		}, nil
+	}
+	return &platformv1.GetBalanceResponse{
+		Balances:               assetAmounts(reply.camino.Balances),
+		UnlockedOutputs:        assetAmounts(reply.camino.UnlockedOutputs),
+		BondedOutputs:          assetAmounts(reply.camino.BondedOutputs),
+		DepositedOutputs:       assetAmounts(reply.camino.DepositedOutputs),
+		DepositedBondedOutputs: assetAmounts(reply.camino.DepositedBondedOutputs),
+		UtxoIds:                utxoIDStrings(reply.camino.UTXOIDs),
+	}, nil
+}
+
+func (s *grpcService) GetUTXOs(_ context.Context, req *platformv1.GetUTXOsRequest) (*platformv1.GetUTXOsResponse, error) {
+	reply := api.GetUTXOsReply{}
+	if err := s.service.GetUTXOs(nil, &api.GetUTXOsArgs{
+		Addresses:   req.Addresses,
+		SourceChain: req.SourceChain,
+		Limit:       utilsjson.Uint32(req.Limit),
+		StartIndex: api.Index{
+			Address: req.StartAddress,
+			UTXO:    req.StartUtxoId,
+		},
+		Encoding: formatting.Hex,
+	}, &reply); err != nil {
+		return nil, err
+	}
+
+	response := &platformv1.GetUTXOsResponse{
+		Utxos:      make([][]byte, len(reply.UTXOs)),
+		EndAddress: reply.EndIndex.Address,
+		EndUtxoId:  reply.EndIndex.UTXO,
+	}
+	for i, utxoStr := range reply.UTXOs {
+		utxoBytes, err := formatting.Decode(formatting.Hex, utxoStr)
+		if err != nil {
+			return nil, err
+		}
+		response.Utxos[i] = utxoBytes
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetTx(_ context.Context, req *platformv1.GetTxRequest) (*platformv1.GetTxResponse, error) {
+	txID, err := ids.FromString(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := api.GetTxReply{}
+	if err := s.service.GetTx(nil, &api.GetTxArgs{
+		TxID:     txID,
+		Encoding: formatting.Hex,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	txBytes, err := decodeHex(reply.Tx)
+	if err != nil {
+		return nil, err
+	}
+	return &platformv1.GetTxResponse{
+		Tx: txBytes,
+	}, nil
+}
+
+func (s *grpcService) GetTxStatus(_ context.Context, req *platformv1.GetTxStatusRequest) (*platformv1.GetTxStatusResponse, error) {
+	txID, err := ids.FromString(req.TxId)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := GetTxStatusResponse{}
+	if err := s.service.GetTxStatus(nil, &GetTxStatusArgs{TxID: txID}, &reply); err != nil {
+		return nil, err
+	}
+	return &platformv1.GetTxStatusResponse{
+		Status: reply.Status.String(),
+		Reason: reply.Reason,
+	}, nil
+}
+
+func (s *grpcService) GetBlock(_ context.Context, req *platformv1.GetBlockRequest) (*platformv1.GetBlockResponse, error) {
+	blkID, err := ids.FromString(req.BlockId)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := api.GetBlockResponse{}
+	if err := s.service.GetBlock(nil, &api.GetBlockArgs{
+		BlockID:  blkID,
+		Encoding: formatting.Hex,
+	}, &reply); err != nil {
+		return nil, err
+	}
+	blkBytes, err := decodeHex(reply.Block)
+	if err != nil {
+		return nil, err
+	}
+	return &platformv1.GetBlockResponse{
+		Block: blkBytes,
+	}, nil
+}
+
+func (s *grpcService) GetDeposits(_ context.Context, req *platformv1.GetDepositsRequest) (*platformv1.GetDepositsResponse, error) {
+	args := GetDepositsArgs{
+		DepositTxIDs: make([]ids.ID, len(req.DepositTxIds)),
+		Height:       (*utilsjson.Uint64)(req.Height),
+	}
+	for i, depositTxIDStr := range req.DepositTxIds {
+		depositTxID, err := ids.FromString(depositTxIDStr)
+		if err != nil {
+			return nil, err
+		}
+		args.DepositTxIDs[i] = depositTxID
+	}
+
+	reply := GetDepositsReply{}
+	if err := s.service.GetDeposits(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+
+	response := &platformv1.GetDepositsResponse{
+		Deposits:         make([]*platformv1.Deposit, len(reply.Deposits)),
+		AvailableRewards: make([]uint64, len(reply.AvailableRewards)),
+		Timestamp:        uint64(reply.Timestamp),
+	}
+	for i, deposit := range reply.Deposits {
+		response.Deposits[i] = &platformv1.Deposit{
+			DepositTxId:         deposit.DepositTxID.String(),
+			DepositOfferId:      deposit.DepositOfferID.String(),
+			UnlockedAmount:      uint64(deposit.UnlockedAmount),
+			UnlockableAmount:    uint64(deposit.UnlockableAmount),
+			ClaimedRewardAmount: uint64(deposit.ClaimedRewardAmount),
+			Start:               uint64(deposit.Start),
+			Duration:            deposit.Duration,
+			Amount:              uint64(deposit.Amount),
+			RewardOwner:         ownerToProto(&deposit.RewardOwner),
+		}
+	}
+	for i, availableReward := range reply.AvailableRewards {
+		response.AvailableRewards[i] = uint64(availableReward)
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetClaimables(_ context.Context, req *platformv1.GetClaimablesRequest) (*platformv1.GetClaimablesResponse, error) {
+	args := GetClaimablesArgs{
+		Owners: make([]platformapi.Owner, len(req.Owners)),
+		Height: (*utilsjson.Uint64)(req.Height),
+	}
+	for i, owner := range req.Owners {
+		args.Owners[i] = platformapi.Owner{
+			Locktime:  utilsjson.Uint64(owner.GetLocktime()),
+			Threshold: utilsjson.Uint32(owner.GetThreshold()),
+			Addresses: owner.GetAddresses(),
+		}
+	}
+
+	reply := GetClaimablesReply{}
+	if err := s.service.GetClaimables(nil, &args, &reply); err != nil {
+		return nil, err
+	}
+
+	response := &platformv1.GetClaimablesResponse{
+		Claimables: make([]*platformv1.Claimable, len(reply.Claimables)),
+	}
+	for i := range reply.Claimables {
+		claimable := &reply.Claimables[i]
+		response.Claimables[i] = &platformv1.Claimable{
+			RewardOwner:           ownerToProto(&claimable.RewardOwner),
+			ValidatorRewards:      uint64(claimable.ValidatorRewards),
+			ExpiredDepositRewards: uint64(claimable.ExpiredDepositRewards),
+		}
+	}
+	return response, nil
+}
+
+func (s *grpcService) GetMultisigAlias(_ context.Context, req *platformv1.GetMultisigAliasRequest) (*platformv1.GetMultisigAliasResponse, error) {
+	reply := GetMultisigAliasReply{}
+	if err := s.service.GetMultisigAlias(nil, &api.JSONAddress{Address: req.Address}, &reply); err != nil {
+		return nil, err
+	}
+	return &platformv1.GetMultisigAliasResponse{
+		Memo:  reply.Memo,
+		Owner: ownerToProto(&reply.Owner),
+	}, nil
+}
+
+func ownerToProto(owner *platformapi.Owner) *platformv1.Owner {
+	return &platformv1.Owner{
+		Locktime:  uint64(owner.Locktime),
+		Threshold: uint32(owner.Threshold),
+		Addresses: owner.Addresses,
+	}
+}
+
+func assetAmounts(amounts map[ids.ID]utilsjson.Uint64) map[string]uint64 {
+	if len(amounts) == 0 {
+		return nil
+	}
+	strAmounts := make(map[string]uint64, len(amounts))
+	for assetID, amount := range amounts {
+		strAmounts[assetID.String()] = uint64(amount)
+	}
+	return strAmounts
+}
+
+func utxoIDStrings(utxoIDs []*avax.UTXOID) []string {
+	strs := make([]string, len(utxoIDs))
+	for i, utxoID := range utxoIDs {
+		strs[i] = utxoID.String()
+	}
+	return strs
+}
+
+// decodeHex returns the bytes of a hex encoded API response.
+func decodeHex(encoded interface{}) ([]byte, error) {
+	str, ok := encoded.(string)
+	if !ok {
+		return nil, errUnexpectedEncoding
+	}
+	return formatting.Decode(formatting.Hex, str)
+}
@@ -0,0 +1,258 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	platformv1 "github.com/ava-labs/avalanchego/proto/pb/platform/v1"
+	utilsjson "github.com/ava-labs/avalanchego/utils/json"
+	platformapi "github.com/ava-labs/avalanchego/vms/platformvm/api"
+)
+
+var (
+	_ GRPCClient = Client(nil)
+	_ GRPCClient = (*grpcClient)(nil)
+)
+
+// GRPCClient is the part of the platform API Client that is served by the gRPC
+// service platform.v1.Platform. Only the headers of the options are sent, as
+// gRPC metadata.
+type GRPCClient interface {
+	// GetHeight returns the current block height of the P Chain
+	GetHeight(ctx context.Context, options ...rpc.Option) (uint64, error)
+	// GetTimestamp returns the current chain timestamp
+	GetTimestamp(ctx context.Context, options ...rpc.Option) (time.Time, error)
+	// GetBalance returns the balance of [addrs] on the P Chain. The deprecated
+	// balances of AVAX aren't set.
+	GetBalance(ctx context.Context, addrs []ids.ShortID, options ...rpc.Option) (*GetBalanceResponse, error)
+	// GetUTXOs returns the byte representation of the UTXOs controlled by [addrs]
+	GetUTXOs(
+		ctx context.Context,
+		addrs []ids.ShortID,
+		limit uint32,
+		startAddress ids.ShortID,
+		startUTXOID ids.ID,
+		options ...rpc.Option,
+	) ([][]byte, ids.ShortID, ids.ID, error)
+	// GetAtomicUTXOs returns the byte representation of the atomic UTXOs controlled by [addrs]
+	// from [sourceChain]
+	GetAtomicUTXOs(
+		ctx context.Context,
+		addrs []ids.ShortID,
+		sourceChain string,
+		limit uint32,
+		startAddress ids.ShortID,
+		startUTXOID ids.ID,
+		options ...rpc.Option,
+	) ([][]byte, ids.ShortID, ids.ID, error)
+	// GetTx returns the byte representation of [txID]
+	GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error)
+	// GetTxStatus returns the status of the transaction corresponding to [txID]
+	GetTxStatus(ctx context.Context, txID ids.ID, options ...rpc.Option) (*GetTxStatusResponse, error)
+	// GetBlock returns the block with the given id.
+	GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error)
+	// GetMultisigAlias returns the alias definition of the given multisig address
+	GetMultisigAlias(ctx context.Context, multisigAddress string, options ...rpc.Option) (*GetMultisigAliasReply, error)
+	GetClaimables(ctx context.Context, owners []*secp256k1fx.OutputOwners, options ...rpc.Option) ([]*state.Claimable, error)
+}
+
+// grpcClient implements GRPCClient with the generated platform.v1.Platform
+// client
+type grpcClient struct {
+	client platformv1.PlatformClient
+}
+
+// NewGRPCClient returns a new platform API Client that calls the gRPC service
+// platform.v1.Platform over [conn]
+func NewGRPCClient(conn grpc.ClientConnInterface) GRPCClient {
+	return &grpcClient{client: platformv1.NewPlatformClient(conn)}
+}
+
+func (c *grpcClient) GetHeight(ctx context.Context, options ...rpc.Option) (uint64, error) {
+	res, err := c.client.GetHeight(rpc.NewGRPCContext(ctx, options), &platformv1.GetHeightRequest{})
+	if err != nil {
+		return 0, err
+	}
+	return res.Height, nil
+}
+
+func (c *grpcClient) GetTimestamp(ctx context.Context, options ...rpc.Option) (time.Time, error) {
+	res, err := c.client.GetTimestamp(rpc.NewGRPCContext(ctx, options), &platformv1.GetTimestampRequest{})
+	if err != nil {
+		return time.Time{}, err
+	}
+	return res.Timestamp.AsTime(), nil
+}
+
+func (c *grpcClient) GetBalance(ctx context.Context, addrs []ids.ShortID, options ...rpc.Option) (*GetBalanceResponse, error) {
+	res, err := c.client.GetBalance(rpc.NewGRPCContext(ctx, options), &platformv1.GetBalanceRequest{
+		Addresses: ids.ShortIDsToStrings(addrs),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetBalanceResponse{
+		UTXOIDs: make([]*avax.UTXOID, len(res.UtxoIds)),
+	}
+	for i, utxoIDStr := range res.UtxoIds {
+		response.UTXOIDs[i], err = avax.UTXOIDFromString(utxoIDStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if response.Balances, err = assetAmountsFromProto(res.Balances); err != nil {
+		return nil, err
+	}
+	if response.Unlockeds, err = assetAmountsFromProto(res.UnlockedOutputs); err != nil {
+		return nil, err
+	}
+	if response.LockedStakeables, err = assetAmountsFromProto(res.LockedStakeableOutputs); err != nil {
+		return nil, err
+	}
+	response.LockedNotStakeables, err = assetAmountsFromProto(res.LockedNotStakeableOutputs)
+	return response, err
+}
+
+func (c *grpcClient) GetUTXOs(
+	ctx context.Context,
+	addrs []ids.ShortID,
+	limit uint32,
+	startAddress ids.ShortID,
+	startUTXOID ids.ID,
+	options ...rpc.Option,
+) ([][]byte, ids.ShortID, ids.ID, error) {
+	return c.GetAtomicUTXOs(ctx, addrs, "", limit, startAddress, startUTXOID, options...)
+}
+
+func (c *grpcClient) GetAtomicUTXOs(
+	ctx context.Context,
+	addrs []ids.ShortID,
+	sourceChain string,
+	limit uint32,
+	startAddress ids.ShortID,
+	startUTXOID ids.ID,
+	options ...rpc.Option,
+) ([][]byte, ids.ShortID, ids.ID, error) {
+	res, err := c.client.GetUTXOs(rpc.NewGRPCContext(ctx, options), &platformv1.GetUTXOsRequest{
+		Addresses:    ids.ShortIDsToStrings(addrs),
+		SourceChain:  sourceChain,
+		Limit:        limit,
+		StartAddress: startAddress.String(),
+		StartUtxoId:  startUTXOID.String(),
+	})
+	if err != nil {
+		return nil, ids.ShortID{}, ids.Empty, err
+	}
+	endAddr, err := address.ParseToID(res.EndAddress)
+	if err != nil {
+		return nil, ids.ShortID{}, ids.Empty, err
+	}
+	endUTXOID, err := ids.FromString(res.EndUtxoId)
+	return res.Utxos, endAddr, endUTXOID, err
+}
+
+func (c *grpcClient) GetTx(ctx context.Context, txID ids.ID, options ...rpc.Option) ([]byte, error) {
+	res, err := c.client.GetTx(rpc.NewGRPCContext(ctx, options), &platformv1.GetTxRequest{
+		TxId: txID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Tx, nil
+}
+
+func (c *grpcClient) GetTxStatus(ctx context.Context, txID ids.ID, options ...rpc.Option) (*GetTxStatusResponse, error) {
+	res, err := c.client.GetTxStatus(rpc.NewGRPCContext(ctx, options), &platformv1.GetTxStatusRequest{
+		TxId: txID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	response := &GetTxStatusResponse{
+		Reason: res.Reason,
+	}
+	return response, response.Status.UnmarshalJSON([]byte(strconv.Quote(res.Status)))
+}
+
+func (c *grpcClient) GetBlock(ctx context.Context, blockID ids.ID, options ...rpc.Option) ([]byte, error) {
+	res, err := c.client.GetBlock(rpc.NewGRPCContext(ctx, options), &platformv1.GetBlockRequest{
+		BlockId: blockID.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Block, nil
+}
+
+func (c *grpcClient) GetMultisigAlias(ctx context.Context, multisigAddress string, options ...rpc.Option) (*GetMultisigAliasReply, error) {
+	res, err := c.client.GetMultisigAlias(rpc.NewGRPCContext(ctx, options), &platformv1.GetMultisigAliasRequest{
+		Address: multisigAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GetMultisigAliasReply{
+		Memo:  res.Memo,
+		Owner: ownerFromProto(res.Owner),
+	}, nil
+}
+
+func (c *grpcClient) GetClaimables(ctx context.Context, owners []*secp256k1fx.OutputOwners, options ...rpc.Option) ([]*state.Claimable, error) {
+	req := &platformv1.GetClaimablesRequest{
+		Owners: make([]*platformv1.Owner, len(owners)),
+	}
+	for i, owner := range owners {
+		req.Owners[i] = ownerToProto(apiOwnerFromSECP(owner))
+	}
+	res, err := c.client.GetClaimables(rpc.NewGRPCContext(ctx, options), req)
+	if err != nil {
+		return nil, err
+	}
+
+	apiClaimables := make([]APIClaimable, len(res.Claimables))
+	for i, claimable := range res.Claimables {
+		apiClaimables[i] = APIClaimable{
+			RewardOwner:           ownerFromProto(claimable.RewardOwner),
+			ValidatorRewards:      utilsjson.Uint64(claimable.ValidatorRewards),
+			ExpiredDepositRewards: utilsjson.Uint64(claimable.ExpiredDepositRewards),
+		}
+	}
+	return claimablesFromAPI(apiClaimables)
+}
+
+func ownerFromProto(owner *platformv1.Owner) platformapi.Owner {
+	return platformapi.Owner{
+		Locktime:  utilsjson.Uint64(owner.GetLocktime()),
+		Threshold: utilsjson.Uint32(owner.GetThreshold()),
+		Addresses: owner.GetAddresses(),
+	}
+}
+
+func assetAmountsFromProto(strAmounts map[string]uint64) (map[ids.ID]utilsjson.Uint64, error) {
+	if len(strAmounts) == 0 {
+		return nil, nil
+	}
+	amounts := make(map[ids.ID]utilsjson.Uint64, len(strAmounts))
+	for assetIDStr, amount := range strAmounts {
+		assetID, err := ids.FromString(assetIDStr)
+		if err != nil {
+			return nil, err
+		}
+		amounts[assetID] = utilsjson.Uint64(amount)
+	}
+	return amounts, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/multisig"
+	"github.com/ava-labs/avalanchego/vms/platformvm/api"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/platformvm/test"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/rpcchainvm/grpcutils"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	platformv1 "github.com/ava-labs/avalanchego/proto/pb/platform/v1"
+)
+
+func TestGRPCService(t *testing.T) {
+	require := require.New(t)
+	s := newCaminoService(t, api.Camino{LockModeBondDeposit: true}, test.PhaseLast, nil)
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	var (
+		ctx              = context.Background()
+		service          = &grpcService{service: s}
+		fundedAddr       = test.FundedKeys[0].Address()
+		aliasAddr        = ids.ShortID{1}
+		depositTxID      = ids.ID{2}
+		offer            = &deposit.Offer{ID: ids.ID{3}, Start: 10, End: 100, MinAmount: 1}
+		fundedAddrOwners = &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{fundedAddr}}
+	)
+	fundedAddrStr, err := s.addrManager.FormatLocalAddress(fundedAddr)
+	require.NoError(err)
+	aliasAddrStr, err := s.addrManager.FormatLocalAddress(aliasAddr)
+	require.NoError(err)
+	ownerID, err := txs.GetOwnerID(fundedAddrOwners)
+	require.NoError(err)
+	expectedOwner := &platformv1.Owner{Threshold: 1, Addresses: []string{fundedAddrStr}}
+
+	s.vm.state.SetDepositOffer(offer)
+	s.vm.state.AddDeposit(depositTxID, &deposit.Deposit{
+		DepositOfferID: offer.ID,
+		Start:          10,
+		Duration:       50,
+		Amount:         1000,
+		RewardOwner:    fundedAddrOwners,
+	})
+	s.vm.state.SetClaimable(ownerID, &state.Claimable{
+		Owner:                fundedAddrOwners,
+		ValidatorReward:      10,
+		ExpiredDepositReward: 20,
+	})
+	s.vm.state.SetMultisigAlias(aliasAddr, &multisig.AliasWithNonce{
+		Alias: multisig.Alias{
+			ID:     aliasAddr,
+			Memo:   []byte{6},
+			Owners: fundedAddrOwners,
+		},
+		Nonce: 1,
+	})
+
+	lastAccepted, err := s.vm.GetBlock(ctx, s.vm.manager.LastAccepted())
+	require.NoError(err)
+	height, err := service.GetHeight(ctx, &platformv1.GetHeightRequest{})
+	require.NoError(err)
+	require.Equal(lastAccepted.Height(), height.Height)
+
+	balance, err := service.GetBalance(ctx, &platformv1.GetBalanceRequest{Addresses: []string{fundedAddrStr}})
+	require.NoError(err)
+	avaxAssetID := s.vm.ctx.AVAXAssetID.String()
+	require.NotZero(balance.Balances[avaxAssetID])
+	require.Equal(balance.Balances[avaxAssetID], balance.UnlockedOutputs[avaxAssetID]+balance.BondedOutputs[avaxAssetID])
+	require.NotEmpty(balance.UtxoIds)
+
+	utxos, err := service.GetUTXOs(ctx, &platformv1.GetUTXOsRequest{Addresses: []string{fundedAddrStr}})
+	require.NoError(err)
+	require.Len(utxos.Utxos, len(balance.UtxoIds))
+
+	deposits, err := service.GetDeposits(ctx, &platformv1.GetDepositsRequest{DepositTxIds: []string{depositTxID.String()}})
+	require.NoError(err)
+	require.Len(deposits.Deposits, 1)
+	require.Len(deposits.AvailableRewards, 1)
+	require.Equal(&platformv1.Deposit{
+		DepositTxId:      depositTxID.String(),
+		DepositOfferId:   offer.ID.String(),
+		UnlockableAmount: 1000,
+		Start:            10,
+		Duration:         50,
+		Amount:           1000,
+		RewardOwner:      expectedOwner,
+	}, deposits.Deposits[0])
+
+	claimables, err := service.GetClaimables(ctx, &platformv1.GetClaimablesRequest{Owners: []*platformv1.Owner{expectedOwner}})
+	require.NoError(err)
+	require.Equal([]*platformv1.Claimable{{
+		RewardOwner:           expectedOwner,
+		ValidatorRewards:      10,
+		ExpiredDepositRewards: 20,
+	}}, claimables.Claimables)
+
+	alias, err := service.GetMultisigAlias(ctx, &platformv1.GetMultisigAliasRequest{Address: aliasAddrStr})
+	require.NoError(err)
+	require.Equal([]byte{6}, alias.Memo)
+	require.Equal(expectedOwner, alias.Owner)
+
+	txStatus, err := service.GetTxStatus(ctx, &platformv1.GetTxStatusRequest{TxId: ids.GenerateTestID().String()})
+	require.NoError(err)
+	require.Equal("Unknown", txStatus.Status)
+
+	block, err := service.GetBlock(ctx, &platformv1.GetBlockRequest{BlockId: lastAccepted.ID().String()})
+	require.NoError(err)
+	require.Equal(lastAccepted.Bytes(), block.Block)
+}
+
+func TestGRPCClient(t *testing.T) {
+	require := require.New(t)
+	s := newCaminoService(t, api.Camino{LockModeBondDeposit: true}, test.PhaseLast, nil)
+	s.vm.ctx.Lock.Lock()
+	defer s.vm.ctx.Lock.Unlock()
+
+	var (
+		ctx              = context.Background()
+		fundedAddr       = test.FundedKeys[0].Address()
+		aliasAddr        = ids.ShortID{1}
+		fundedAddrOwners = &secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{fundedAddr}}
+	)
+	aliasAddrStr, err := s.addrManager.FormatLocalAddress(aliasAddr)
+	require.NoError(err)
+	ownerID, err := txs.GetOwnerID(fundedAddrOwners)
+	require.NoError(err)
+
+	s.vm.state.SetClaimable(ownerID, &state.Claimable{
+		Owner:                fundedAddrOwners,
+		ValidatorReward:      10,
+		ExpiredDepositReward: 20,
+	})
+	s.vm.state.SetMultisigAlias(aliasAddr, &multisig.AliasWithNonce{
+		Alias: multisig.Alias{
+			ID:     aliasAddr,
+			Memo:   []byte{6},
+			Owners: fundedAddrOwners,
+		},
+		Nonce: 1,
+	})
+
+	listener, err := grpcutils.NewListener()
+	require.NoError(err)
+	server := newGRPCServer(s)
+	defer server.Stop()
+	go grpcutils.Serve(listener, server)
+
+	conn, err := grpcutils.Dial(listener.Addr().String())
+	require.NoError(err)
+	defer conn.Close()
+	client := NewGRPCClient(conn)
+
+	lastAccepted, err := s.vm.GetBlock(ctx, s.vm.manager.LastAccepted())
+	require.NoError(err)
+	height, err := client.GetHeight(ctx)
+	require.NoError(err)
+	require.Equal(lastAccepted.Height(), height)
+
+	balance, err := client.GetBalance(ctx, []ids.ShortID{fundedAddr})
+	require.NoError(err)
+	require.NotZero(balance.Balances[s.vm.ctx.AVAXAssetID])
+	require.NotEmpty(balance.UTXOIDs)
+
+	utxos, _, _, err := client.GetUTXOs(ctx, []ids.ShortID{fundedAddr}, 0, ids.ShortEmpty, ids.Empty)
+	require.NoError(err)
+	require.Len(utxos, len(balance.UTXOIDs))
+
+	claimables, err := client.GetClaimables(ctx, []*secp256k1fx.OutputOwners{fundedAddrOwners})
+	require.NoError(err)
+	require.Equal([]*state.Claimable{{
+		Owner:                fundedAddrOwners,
+		ValidatorReward:      10,
+		ExpiredDepositReward: 20,
+	}}, claimables)
+
+	alias, err := client.GetMultisigAlias(ctx, aliasAddrStr)
+	require.NoError(err)
+	require.Equal([]byte{6}, []byte(alias.Memo))
+
+	txStatus, err := client.GetTxStatus(ctx, ids.GenerateTestID())
+	require.NoError(err)
+	require.Equal(status.Unknown, txStatus.Status)
+
+	block, err := client.GetBlock(ctx, lastAccepted.ID())
+	require.NoError(err)
+	require.Equal(lastAccepted.Bytes(), block)
+}
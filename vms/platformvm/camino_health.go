@@ -0,0 +1,167 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+
+	as "github.com/ava-labs/avalanchego/vms/platformvm/addrstate"
+)
+
+const caminoNodeCheckName = "caminoNode"
+
+var (
+	errCaminoNotBootstrapped        = errors.New("platform chain isn't bootstrapped")
+	errCaminoNodeNotRegistered      = errors.New("node isn't registered by a consortium member")
+	errCaminoNodeKeyMismatch        = errors.New("node isn't the node registered by its consortium member")
+	errCaminoNodeOwnerNotConsortium = errors.New("node owner isn't a consortium member")
+	errCaminoNodeDeferred           = errors.New("node is deferred")
+)
+
+// caminoNodeHealth describes how this node is registered on the platform chain
+type caminoNodeHealth struct {
+	NodeID           ids.NodeID `json:"nodeID"`
+	Validator        bool       `json:"validator"`
+	Deferred         bool       `json:"deferred"`
+	NodeOwner        string     `json:"nodeOwner,omitempty"`
+	ConsortiumMember bool       `json:"consortiumMember"`
+}
+
+// registerCaminoHealthChecks registers the checks reporting whether this node
+// is able to validate a Camino network. The checks are only registered if the
+// network requires validators to be registered by consortium members.
+//
+// Both checks pass while this node doesn't take part in validation, i.e. it
+// isn't registered, validating or deferred. Otherwise, the health check fails
+// if this node validates or is deferred without being registered by an owner,
+// if node signatures are verified and it isn't the node currently registered
+// by its owner, if its owner isn't a consortium member or if it's deferred.
+// The readiness check fails on the same conditions except deferral, which is
+// revoked by the network rather than by fixing the node. No liveness check is
+// registered, as restarting the node can't fix any of these conditions.
+func (vm *VM) registerCaminoHealthChecks() error {
+	if vm.Health == nil || !vm.StakingEnabled {
+		return nil
+	}
+	caminoConfig, err := vm.state.CaminoConfig()
+	if err != nil {
+		return err
+	}
+	if !caminoConfig.LockModeBondDeposit {
+		return nil
+	}
+
+	tag := constants.PrimaryNetworkID.String()
+	readinessCheck := health.CheckerFunc(func(context.Context) (interface{}, error) {
+		return vm.caminoNodeHealth(false)
+	})
+	if err := vm.Health.RegisterReadinessCheck(caminoNodeCheckName, readinessCheck, tag); err != nil {
+		return fmt.Errorf("couldn't register %s readiness check: %w", caminoNodeCheckName, err)
+	}
+	healthCheck := health.CheckerFunc(func(context.Context) (interface{}, error) {
+		return vm.caminoNodeHealth(true)
+	})
+	if err := vm.Health.RegisterHealthCheck(caminoNodeCheckName, healthCheck, tag); err != nil {
+		return fmt.Errorf("couldn't register %s health check: %w", caminoNodeCheckName, err)
+	}
+	return nil
+}
+
+// caminoNodeHealth returns the registration of this node and an error if this
+// node isn't able to validate. Deferral is only reported as error if
+// [checkDeferred] is true.
+func (vm *VM) caminoNodeHealth(checkDeferred bool) (*caminoNodeHealth, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if !vm.bootstrapped.Get() {
+		return nil, errCaminoNotBootstrapped
+	}
+
+	nodeID := vm.ctx.NodeID
+	details := &caminoNodeHealth{NodeID: nodeID}
+
+	var err error
+	details.Validator, err = vm.isPrimaryValidator(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	switch _, err := vm.state.GetDeferredValidator(constants.PrimaryNetworkID, nodeID); err {
+	case nil:
+		details.Deferred = true
+	case database.ErrNotFound:
+	default:
+		return nil, fmt.Errorf("couldn't get deferred validator: %w", err)
+	}
+
+	nodeOwner, err := vm.state.GetShortIDLink(ids.ShortID(nodeID), state.ShortLinkKeyRegisterNode)
+	switch {
+	case err == database.ErrNotFound && !details.Validator && !details.Deferred:
+		// This node doesn't take part in validation
+		return details, nil
+	case err == database.ErrNotFound:
+		return details, errCaminoNodeNotRegistered
+	case err != nil:
+		return nil, fmt.Errorf("couldn't get node owner: %w", err)
+	}
+	details.NodeOwner = vm.formatAddress(nodeOwner)
+
+	ownerState, err := vm.state.GetAddressStates(nodeOwner)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get address states of node owner: %w", err)
+	}
+	details.ConsortiumMember = ownerState.Is(as.AddressStateConsortium)
+	details.Deferred = details.Deferred || ownerState.Is(as.AddressStateNodeDeferred)
+
+	caminoConfig, err := vm.state.CaminoConfig()
+	if err != nil {
+		return nil, err
+	}
+	if caminoConfig.VerifyNodeSignature {
+		registeredNodeID, err := vm.state.GetShortIDLink(nodeOwner, state.ShortLinkKeyRegisterNode)
+		if err != nil && err != database.ErrNotFound {
+			return nil, fmt.Errorf("couldn't get registered node of node owner: %w", err)
+		}
+		if err == database.ErrNotFound || registeredNodeID != ids.ShortID(nodeID) {
+			return details, errCaminoNodeKeyMismatch
+		}
+	}
+
+	switch {
+	case !details.ConsortiumMember:
+		return details, errCaminoNodeOwnerNotConsortium
+	case checkDeferred && details.Deferred:
+		return details, errCaminoNodeDeferred
+	}
+	return details, nil
+}
+
+// isPrimaryValidator returns true if [nodeID] is a current or pending validator
+// of the primary network.
+func (vm *VM) isPrimaryValidator(nodeID ids.NodeID) (bool, error) {
+	switch _, err := vm.state.GetCurrentValidator(constants.PrimaryNetworkID, nodeID); err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+	default:
+		return false, fmt.Errorf("couldn't get current validator: %w", err)
+	}
+
+	switch _, err := vm.state.GetPendingValidator(constants.PrimaryNetworkID, nodeID); err {
+	case nil:
+		return true, nil
+	case database.ErrNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("couldn't get pending validator: %w", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/api/health"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/platformvm/api"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/test"
+
+	as "github.com/ava-labs/avalanchego/vms/platformvm/addrstate"
+)
+
+func TestCaminoNodeHealth(t *testing.T) {
+	nodeID := test.FundedNodeIDs[0]
+	notValidatorNodeID := ids.GenerateTestNodeID()
+	nodeOwner := test.FundedKeys[0].Address()
+	nodeOwnerStr := "P-" + test.FundedKeysBech32[0]
+
+	tests := map[string]struct {
+		nodeID            ids.NodeID
+		updateState       func(state.State)
+		expectedDetails   *caminoNodeHealth
+		expectedHealthErr error
+		expectedReadyErr  error
+	}{
+		"Not validating": {
+			nodeID:          notValidatorNodeID,
+			updateState:     func(state.State) {},
+			expectedDetails: &caminoNodeHealth{NodeID: notValidatorNodeID},
+		},
+		"Registered validator": {
+			nodeID:      nodeID,
+			updateState: func(state.State) {},
+			expectedDetails: &caminoNodeHealth{
+				NodeID:           nodeID,
+				NodeOwner:        nodeOwnerStr,
+				Validator:        true,
+				ConsortiumMember: true,
+			},
+		},
+		"Not registered": {
+			nodeID: nodeID,
+			updateState: func(s state.State) {
+				s.SetShortIDLink(ids.ShortID(nodeID), state.ShortLinkKeyRegisterNode, nil)
+			},
+			expectedDetails: &caminoNodeHealth{
+				NodeID:    nodeID,
+				Validator: true,
+			},
+			expectedHealthErr: errCaminoNodeNotRegistered,
+			expectedReadyErr:  errCaminoNodeNotRegistered,
+		},
+		"Owner registered other node": {
+			nodeID: nodeID,
+			updateState: func(s state.State) {
+				otherNodeID := ids.GenerateTestShortID()
+				s.SetShortIDLink(nodeOwner, state.ShortLinkKeyRegisterNode, &otherNodeID)
+			},
+			expectedDetails: &caminoNodeHealth{
+				NodeID:           nodeID,
+				NodeOwner:        nodeOwnerStr,
+				Validator:        true,
+				ConsortiumMember: true,
+			},
+			expectedHealthErr: errCaminoNodeKeyMismatch,
+			expectedReadyErr:  errCaminoNodeKeyMismatch,
+		},
+		"Owner isn't consortium member": {
+			nodeID: nodeID,
+			updateState: func(s state.State) {
+				s.SetAddressStates(nodeOwner, as.AddressStateEmpty)
+			},
+			expectedDetails: &caminoNodeHealth{
+				NodeID:    nodeID,
+				NodeOwner: nodeOwnerStr,
+				Validator: true,
+			},
+			expectedHealthErr: errCaminoNodeOwnerNotConsortium,
+			expectedReadyErr:  errCaminoNodeOwnerNotConsortium,
+		},
+		"Deferred": {
+			nodeID: nodeID,
+			updateState: func(s state.State) {
+				s.SetAddressStates(nodeOwner, as.AddressStateConsortium|as.AddressStateNodeDeferred)
+			},
+			expectedDetails: &caminoNodeHealth{
+				NodeID:           nodeID,
+				NodeOwner:        nodeOwnerStr,
+				Validator:        true,
+				Deferred:         true,
+				ConsortiumMember: true,
+			},
+			expectedHealthErr: errCaminoNodeDeferred,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			vm := newCaminoVM(t, api.Camino{
+				VerifyNodeSignature: true,
+				LockModeBondDeposit: true,
+			}, test.PhaseLast, nil)
+
+			vm.ctx.Lock.Lock()
+			vm.ctx.NodeID = tt.nodeID
+			tt.updateState(vm.state)
+			vm.ctx.Lock.Unlock()
+
+			details, err := vm.caminoNodeHealth(true)
+			require.ErrorIs(err, tt.expectedHealthErr)
+			require.Equal(tt.expectedDetails, details)
+
+			details, err = vm.caminoNodeHealth(false)
+			require.ErrorIs(err, tt.expectedReadyErr)
+			require.Equal(tt.expectedDetails, details)
+		})
+	}
+}
+
+func TestCaminoNodeHealthNotBootstrapped(t *testing.T) {
+	require := require.New(t)
+	vm := newCaminoVM(t, api.Camino{LockModeBondDeposit: true}, test.PhaseLast, nil)
+	vm.bootstrapped.Set(false)
+
+	_, err := vm.caminoNodeHealth(true)
+	require.ErrorIs(err, errCaminoNotBootstrapped)
+}
+
+func TestRegisterCaminoHealthChecks(t *testing.T) {
+	require := require.New(t)
+	vm := newCaminoVM(t, api.Camino{LockModeBondDeposit: true}, test.PhaseLast, nil)
+
+	h, err := health.New(logging.NoLog{}, prometheus.NewRegistry())
+	require.NoError(err)
+	vm.Health = h
+	require.NoError(vm.registerCaminoHealthChecks())
+
+	h.Start(context.Background(), time.Millisecond)
+	defer h.Stop()
+
+	tag := constants.PrimaryNetworkID.String()
+	require.Eventually(func() bool {
+		_, ready := h.Readiness(tag)
+		_, healthy := h.Health(tag)
+		return ready && healthy
+	}, time.Second, time.Millisecond)
+
+	results, _ := h.Health(tag)
+	require.Contains(results, caminoNodeCheckName)
+	results, _ = h.Liveness(tag)
+	require.NotContains(results, caminoNodeCheckName)
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/vms/components/pruning"
+)
+
+// startPruning starts to prune old blocks in the background, if pruning is
+// enabled.
+func (vm *VM) startPruning() {
+	if !vm.executionConfig.PruningEnabled || vm.pruner != nil {
+		return
+	}
+
+	vm.pruner = pruning.NewPruner(
+		&vm.ctx.Lock,
+		vm.ctx.Log,
+		vm.state,
+		vm.executionConfig.PruningRetainedBlocks,
+		func() (uint64, bool, error) {
+			height, err := vm.GetCurrentHeight(context.TODO())
+			return height, true, err
+		},
+	)
+	vm.pruner.Start()
+}
+
+// prunedErr explains [err], if it was returned because a block or tx wasn't
+// found and blocks were pruned.
+func (vm *VM) prunedErr(err error) error {
+	return pruning.PrunedErr(err, vm.state.GetPrunedHeight())
+}
@@ -0,0 +1,324 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/multisig"
+	as "github.com/ava-labs/avalanchego/vms/platformvm/addrstate"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/locked"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+// Types of the events, that are published with accepted txs.
+const (
+	EventDepositCreated       = "depositCreated"
+	EventDepositUnlocked      = "depositUnlocked"
+	EventClaimExecuted        = "claimExecuted"
+	EventProposalCreated      = "proposalCreated"
+	EventProposalVoted        = "proposalVoted"
+	EventProposalFinished     = "proposalFinished"
+	EventAddressStateChanged  = "addressStateChanged"
+	EventMultisigAliasUpdated = "multisigAliasUpdated"
+	EventValidatorDeferred    = "validatorDeferred"
+)
+
+var (
+	_ pubsub.Filterer = (*txFilterer)(nil)
+
+	proposalVotersPrefix = []byte("proposalVoters")
+)
+
+// PubSubTx is sent to the subscribers of an address, when a tx, that is
+// related to the address, is accepted.
+type PubSubTx struct {
+	TxID    ids.ID        `json:"txID"`
+	BlockID ids.ID        `json:"blockID"`
+	Height  json.Uint64   `json:"height"`
+	Events  []PubSubEvent `json:"events,omitempty"`
+}
+
+// PubSubEvent is a change of the Camino state, that is caused by an accepted
+// tx. Only the fields, that are relevant to the event type, are set.
+type PubSubEvent struct {
+	Type        string              `json:"type"`
+	DepositTxID *ids.ID             `json:"depositTxID,omitempty"`
+	ProposalID  *ids.ID             `json:"proposalID,omitempty"`
+	Successful  *bool               `json:"successful,omitempty"`
+	Address     string              `json:"address,omitempty"`
+	State       *as.AddressStateBit `json:"state,omitempty"`
+	Remove      bool                `json:"remove,omitempty"`
+	NodeID      *ids.NodeID         `json:"nodeID,omitempty"`
+}
+
+type txFilterer struct {
+	addrs set.Set[ids.ShortID]
+	msg   *PubSubTx
+
+	// Vote, that is recorded once the tx was committed. Empty, if the tx
+	// isn't a vote.
+	votedProposalID ids.ID
+	voterAddr       ids.ShortID
+	// Proposals, whose recorded voters are dropped once the tx was committed
+	finishedProposalIDs []ids.ID
+}
+
+// Filter returns true for the filters, that match any address of the tx.
+func (f *txFilterer) Filter(filters []pubsub.Filter) ([]bool, interface{}) {
+	resp := make([]bool, len(filters))
+	for addr := range f.addrs {
+		for i, c := range filters {
+			if resp[i] {
+				continue
+			}
+			resp[i] = c.Check(addr[:])
+		}
+	}
+	return resp, f.msg
+}
+
+// onBlockAccepting prepares the txs, that are accepted with [blk], to be
+// published, while the state is still at the parent of [blk]. The txs of a
+// proposal block are published, when its commit block is accepted.
+func (vm *VM) onBlockAccepting(blk blocks.Block) {
+	vm.acceptedTxFilterers = nil
+
+	txsBlk := blk
+	switch blk.(type) {
+	case *blocks.ApricotCommitBlock, *blocks.BanffCommitBlock:
+		parent, _, err := vm.state.GetStatelessBlock(blk.Parent())
+		if err != nil {
+			vm.ctx.Log.Error("failed to get proposal block to publish",
+				zap.Stringer("blkID", blk.Parent()),
+				zap.Error(err),
+			)
+			return
+		}
+		txsBlk = parent
+	case *blocks.ApricotAbortBlock, *blocks.BanffAbortBlock:
+		return
+	}
+
+	for _, tx := range txsBlk.Txs() {
+		vm.acceptedTxFilterers = append(vm.acceptedTxFilterers, vm.newTxFilterer(blk, tx))
+	}
+}
+
+// publishAcceptedTxs publishes the txs, that were prepared by
+// onBlockAccepting, once the state of their block was committed.
+func (vm *VM) publishAcceptedTxs() {
+	for _, f := range vm.acceptedTxFilterers {
+		if err := vm.recordVoters(f); err != nil {
+			vm.ctx.Log.Error("failed to record proposal voters",
+				zap.Stringer("txID", f.msg.TxID),
+				zap.Error(err),
+			)
+		}
+		vm.pubsub.Publish(f)
+	}
+	vm.acceptedTxFilterers = nil
+}
+
+// recordVoters records the vote of [f] and drops the voters of the proposals,
+// that [f] finished. Voters are recorded, so that they can be notified when
+// the proposal is finished.
+func (vm *VM) recordVoters(f *txFilterer) error {
+	if f.voterAddr != ids.ShortEmpty {
+		if err := vm.proposalVotersDB.Put(proposalVoterKey(f.votedProposalID, f.voterAddr), nil); err != nil {
+			return err
+		}
+	}
+	for _, proposalID := range f.finishedProposalIDs {
+		voters, err := vm.getProposalVoters(proposalID)
+		if err != nil {
+			return err
+		}
+		for _, voterAddr := range voters {
+			if err := vm.proposalVotersDB.Delete(proposalVoterKey(proposalID, voterAddr)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getProposalVoters returns the recorded voters of [proposalID].
+func (vm *VM) getProposalVoters(proposalID ids.ID) ([]ids.ShortID, error) {
+	it := vm.proposalVotersDB.NewIteratorWithPrefix(proposalID[:])
+	defer it.Release()
+
+	var voters []ids.ShortID
+	for it.Next() {
+		voterAddr, err := ids.ToShortID(it.Key()[len(proposalID):])
+		if err != nil {
+			return nil, err
+		}
+		voters = append(voters, voterAddr)
+	}
+	return voters, it.Error()
+}
+
+func proposalVoterKey(proposalID ids.ID, voterAddr ids.ShortID) []byte {
+	key := make([]byte, 0, len(proposalID)+len(voterAddr))
+	key = append(key, proposalID[:]...)
+	return append(key, voterAddr[:]...)
+}
+
+func (vm *VM) newTxFilterer(blk blocks.Block, tx *txs.Tx) *txFilterer {
+	var (
+		txID = tx.ID()
+		f    = &txFilterer{msg: &PubSubTx{
+			TxID:    txID,
+			BlockID: blk.ID(),
+			Height:  json.Uint64(blk.Height()),
+		}}
+	)
+	for _, out := range tx.Unsigned.Outputs() {
+		if addressable, ok := out.Out.(avax.Addressable); ok {
+			f.addAddresses(addressable.Addresses())
+		}
+	}
+
+	switch utx := tx.Unsigned.(type) {
+	case *txs.DepositTx:
+		if owner, ok := utx.RewardsOwner.(*secp256k1fx.OutputOwners); ok {
+			f.addrs.Add(owner.Addrs...)
+		}
+		f.addEvent(PubSubEvent{Type: EventDepositCreated, DepositTxID: &txID})
+	case *txs.UnlockDepositTx:
+		depositTxIDs := set.Set[ids.ID]{}
+		for _, in := range utx.Ins {
+			if lockedIn, ok := in.In.(*locked.In); ok && lockedIn.DepositTxID != ids.Empty {
+				depositTxIDs.Add(lockedIn.DepositTxID)
+			}
+		}
+		sortedDepositTxIDs := depositTxIDs.List()
+		utils.Sort(sortedDepositTxIDs)
+		for i := range sortedDepositTxIDs {
+			f.addEvent(PubSubEvent{Type: EventDepositUnlocked, DepositTxID: &sortedDepositTxIDs[i]})
+		}
+	case *txs.ClaimTx:
+		for _, claimable := range utx.Claimables {
+			vm.addClaimableOwner(f, claimable)
+		}
+		f.addEvent(PubSubEvent{Type: EventClaimExecuted})
+	case *txs.AddProposalTx:
+		f.addrs.Add(utx.ProposerAddress)
+		f.addEvent(PubSubEvent{Type: EventProposalCreated, ProposalID: &txID})
+	case *txs.AddVoteTx:
+		f.addrs.Add(utx.VoterAddress)
+		f.votedProposalID = utx.ProposalID
+		f.voterAddr = utx.VoterAddress
+		f.addEvent(PubSubEvent{Type: EventProposalVoted, ProposalID: &utx.ProposalID})
+	case *txs.FinishProposalsTx:
+		vm.addFinishedProposals(f, utx.EarlyFinishedSuccessfulProposalIDs, true)
+		vm.addFinishedProposals(f, utx.ExpiredSuccessfulProposalIDs, true)
+		vm.addFinishedProposals(f, utx.EarlyFinishedFailedProposalIDs, false)
+		vm.addFinishedProposals(f, utx.ExpiredFailedProposalIDs, false)
+	case *txs.AddressStateTx:
+		f.addrs.Add(utx.Address)
+		address := vm.formatAddress(utx.Address)
+		stateBit := utx.StateBit
+		f.addEvent(PubSubEvent{
+			Type:    EventAddressStateChanged,
+			Address: address,
+			State:   &stateBit,
+			Remove:  utx.Remove,
+		})
+		if stateBit == as.AddressStateBitNodeDeferred && !utx.Remove {
+			event := PubSubEvent{Type: EventValidatorDeferred, Address: address}
+			if nodeID, err := vm.state.GetShortIDLink(utx.Address, state.ShortLinkKeyRegisterNode); err == nil {
+				nodeID := ids.NodeID(nodeID)
+				event.NodeID = &nodeID
+			}
+			f.addEvent(event)
+		}
+	case *txs.MultisigAliasTx:
+		aliasID := utx.MultisigAlias.ID
+		if aliasID == ids.ShortEmpty {
+			aliasID = multisig.ComputeAliasID(txID)
+		}
+		f.addrs.Add(aliasID)
+		if owners, ok := utx.MultisigAlias.Owners.(*secp256k1fx.OutputOwners); ok {
+			f.addrs.Add(owners.Addrs...)
+		}
+		f.addEvent(PubSubEvent{Type: EventMultisigAliasUpdated, Address: vm.formatAddress(aliasID)})
+	}
+	return f
+}
+
+func (f *txFilterer) addAddresses(addrs [][]byte) {
+	for _, addrBytes := range addrs {
+		if addr, err := ids.ToShortID(addrBytes); err == nil {
+			f.addrs.Add(addr)
+		}
+	}
+}
+
+func (f *txFilterer) addEvent(event PubSubEvent) {
+	f.msg.Events = append(f.msg.Events, event)
+}
+
+// addFinishedProposals adds the proposers and the recorded voters of
+// [proposalIDs] to [f].
+func (vm *VM) addFinishedProposals(f *txFilterer, proposalIDs []ids.ID, successful bool) {
+	for i, proposalID := range proposalIDs {
+		if proposalTx, _, err := vm.state.GetTx(proposalID); err == nil {
+			if utx, ok := proposalTx.Unsigned.(*txs.AddProposalTx); ok {
+				f.addrs.Add(utx.ProposerAddress)
+			}
+		}
+		if voters, err := vm.getProposalVoters(proposalID); err == nil {
+			f.addrs.Add(voters...)
+		}
+		f.finishedProposalIDs = append(f.finishedProposalIDs, proposalID)
+		f.addEvent(PubSubEvent{
+			Type:       EventProposalFinished,
+			ProposalID: &proposalIDs[i],
+			Successful: &successful,
+		})
+	}
+}
+
+// addClaimableOwner adds the owner of [claimable] to [f]. The owner is read
+// from the state, before the claim is applied, as fully claimed claimables are
+// removed.
+func (vm *VM) addClaimableOwner(f *txFilterer, claimable txs.ClaimAmount) {
+	var owner interface{}
+	switch claimable.Type {
+	case txs.ClaimTypeActiveDepositReward:
+		deposit, err := vm.state.GetDeposit(claimable.ID)
+		if err != nil {
+			return
+		}
+		owner = deposit.RewardOwner
+	default:
+		treasuryClaimable, err := vm.state.GetClaimable(claimable.ID)
+		if err != nil {
+			return
+		}
+		owner = treasuryClaimable.Owner
+	}
+	if owners, ok := owner.(*secp256k1fx.OutputOwners); ok {
+		f.addrs.Add(owners.Addrs...)
+	}
+}
+
+func (vm *VM) formatAddress(addr ids.ShortID) string {
+	address, err := avax.NewAddressManager(vm.ctx).FormatLocalAddress(addr)
+	if err != nil {
+		return addr.String()
+	}
+	return address
+}
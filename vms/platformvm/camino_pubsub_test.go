@@ -0,0 +1,214 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/pubsub"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	as "github.com/ava-labs/avalanchego/vms/platformvm/addrstate"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/locked"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestNewTxFilterer(t *testing.T) {
+	var (
+		outAddr      = ids.ShortID{1}
+		ownerAddr    = ids.ShortID{2}
+		targetAddr   = ids.ShortID{3}
+		nodeID       = ids.NodeID{4}
+		depositTxID1 = ids.ID{5}
+		depositTxID2 = ids.ID{6}
+		proposalID1  = ids.ID{7}
+		proposalID2  = ids.ID{8}
+		proposerAddr = ids.ShortID{9}
+		voterAddr    = ids.ShortID{10}
+		claimOwnerID = ids.ID{11}
+		successful   = true
+		failed       = false
+		deferredBit  = as.AddressStateBitNodeDeferred
+		baseTx       = txs.BaseTx{BaseTx: avax.BaseTx{Outs: []*avax.TransferableOutput{{
+			Out: &secp256k1fx.TransferOutput{OutputOwners: secp256k1fx.OutputOwners{Addrs: []ids.ShortID{outAddr}}},
+		}}}}
+	)
+
+	tests := map[string]struct {
+		utx            txs.UnsignedTx
+		state          func(*gomock.Controller) state.State
+		voters         map[ids.ID][]ids.ShortID
+		expectedAddrs  []ids.ShortID
+		expectedEvents func(txID ids.ID) []PubSubEvent
+	}{
+		"BaseTx": {
+			utx:           &baseTx,
+			expectedAddrs: []ids.ShortID{outAddr},
+			expectedEvents: func(ids.ID) []PubSubEvent {
+				return nil
+			},
+		},
+		"DepositTx": {
+			utx: &txs.DepositTx{
+				BaseTx:       baseTx,
+				RewardsOwner: &secp256k1fx.OutputOwners{Addrs: []ids.ShortID{ownerAddr}},
+			},
+			expectedAddrs: []ids.ShortID{outAddr, ownerAddr},
+			expectedEvents: func(txID ids.ID) []PubSubEvent {
+				return []PubSubEvent{{Type: EventDepositCreated, DepositTxID: &txID}}
+			},
+		},
+		"UnlockDepositTx": {
+			utx: &txs.UnlockDepositTx{BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{Ins: []*avax.TransferableInput{
+				{In: &locked.In{IDs: locked.IDs{DepositTxID: depositTxID2}, TransferableIn: &secp256k1fx.TransferInput{}}},
+				{In: &locked.In{IDs: locked.IDs{DepositTxID: depositTxID1}, TransferableIn: &secp256k1fx.TransferInput{}}},
+				{In: &locked.In{IDs: locked.IDs{DepositTxID: depositTxID1}, TransferableIn: &secp256k1fx.TransferInput{}}},
+				{In: &secp256k1fx.TransferInput{}},
+			}}}},
+			expectedEvents: func(ids.ID) []PubSubEvent {
+				return []PubSubEvent{
+					{Type: EventDepositUnlocked, DepositTxID: &depositTxID1},
+					{Type: EventDepositUnlocked, DepositTxID: &depositTxID2},
+				}
+			},
+		},
+		"ClaimTx": {
+			utx: &txs.ClaimTx{Claimables: []txs.ClaimAmount{
+				{ID: depositTxID1, Type: txs.ClaimTypeActiveDepositReward, OwnerAuth: &secp256k1fx.Input{}},
+				{ID: claimOwnerID, Type: txs.ClaimTypeValidatorReward, OwnerAuth: &secp256k1fx.Input{}},
+			}},
+			state: func(c *gomock.Controller) state.State {
+				s := state.NewMockState(c)
+				s.EXPECT().GetDeposit(depositTxID1).Return(&deposit.Deposit{
+					RewardOwner: &secp256k1fx.OutputOwners{Addrs: []ids.ShortID{ownerAddr}},
+				}, nil)
+				s.EXPECT().GetClaimable(claimOwnerID).Return(&state.Claimable{
+					Owner: &secp256k1fx.OutputOwners{Addrs: []ids.ShortID{targetAddr}},
+				}, nil)
+				return s
+			},
+			expectedAddrs: []ids.ShortID{ownerAddr, targetAddr},
+			expectedEvents: func(ids.ID) []PubSubEvent {
+				return []PubSubEvent{{Type: EventClaimExecuted}}
+			},
+		},
+		"FinishProposalsTx": {
+			utx: &txs.FinishProposalsTx{
+				EarlyFinishedSuccessfulProposalIDs: []ids.ID{proposalID1},
+				ExpiredFailedProposalIDs:           []ids.ID{proposalID2},
+			},
+			state: func(c *gomock.Controller) state.State {
+				s := state.NewMockState(c)
+				s.EXPECT().GetTx(proposalID1).Return(&txs.Tx{Unsigned: &txs.AddProposalTx{
+					ProposerAddress: proposerAddr,
+				}}, status.Committed, nil)
+				s.EXPECT().GetTx(proposalID2).Return(nil, status.Unknown, database.ErrNotFound)
+				return s
+			},
+			voters:        map[ids.ID][]ids.ShortID{proposalID2: {voterAddr}},
+			expectedAddrs: []ids.ShortID{proposerAddr, voterAddr},
+			expectedEvents: func(ids.ID) []PubSubEvent {
+				return []PubSubEvent{
+					{Type: EventProposalFinished, ProposalID: &proposalID1, Successful: &successful},
+					{Type: EventProposalFinished, ProposalID: &proposalID2, Successful: &failed},
+				}
+			},
+		},
+		"AddressStateTx: node deferred": {
+			utx: &txs.AddressStateTx{
+				Address:  targetAddr,
+				StateBit: as.AddressStateBitNodeDeferred,
+			},
+			state: func(c *gomock.Controller) state.State {
+				s := state.NewMockState(c)
+				s.EXPECT().GetShortIDLink(targetAddr, state.ShortLinkKeyRegisterNode).
+					Return(ids.ShortID(nodeID), nil)
+				return s
+			},
+			expectedAddrs: []ids.ShortID{targetAddr},
+			expectedEvents: func(ids.ID) []PubSubEvent {
+				return []PubSubEvent{
+					{Type: EventAddressStateChanged, Address: targetAddr.String(), State: &deferredBit},
+					{Type: EventValidatorDeferred, Address: targetAddr.String(), NodeID: &nodeID},
+				}
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require := require.New(t)
+			ctrl := gomock.NewController(t)
+
+			vm := &VM{ctx: snow.DefaultContextTest(), proposalVotersDB: memdb.New()}
+			if tt.state != nil {
+				vm.state = tt.state(ctrl)
+			}
+			for proposalID, voters := range tt.voters {
+				for _, voterAddr := range voters {
+					require.NoError(vm.proposalVotersDB.Put(proposalVoterKey(proposalID, voterAddr), nil))
+				}
+			}
+
+			tx := &txs.Tx{Unsigned: tt.utx}
+			require.NoError(tx.Initialize(txs.Codec))
+			blk, err := blocks.NewBanffStandardBlock(time.Unix(0, 0), ids.GenerateTestID(), 1, []*txs.Tx{tx})
+			require.NoError(err)
+
+			f := vm.newTxFilterer(blk, tx)
+			require.Equal(&PubSubTx{
+				TxID:    tx.ID(),
+				BlockID: blk.ID(),
+				Height:  1,
+				Events:  tt.expectedEvents(tx.ID()),
+			}, f.msg)
+			require.ElementsMatch(tt.expectedAddrs, f.addrs.List())
+
+			matchingFilter := pubsub.NewFilterParam()
+			otherFilter := pubsub.NewFilterParam()
+			require.NoError(otherFilter.Add(ids.GenerateTestShortID().Bytes()))
+			for _, addr := range tt.expectedAddrs {
+				require.NoError(matchingFilter.Add(addr.Bytes()))
+			}
+			notify, msg := f.Filter([]pubsub.Filter{matchingFilter, otherFilter})
+			require.Equal([]bool{len(tt.expectedAddrs) > 0, false}, notify)
+			require.Equal(f.msg, msg)
+		})
+	}
+}
+
+func TestRecordVoters(t *testing.T) {
+	require := require.New(t)
+
+	var (
+		proposalID = ids.GenerateTestID()
+		voterAddr1 = ids.GenerateTestShortID()
+		voterAddr2 = ids.GenerateTestShortID()
+	)
+	vm := &VM{ctx: snow.DefaultContextTest(), proposalVotersDB: memdb.New()}
+
+	require.NoError(vm.recordVoters(&txFilterer{votedProposalID: proposalID, voterAddr: voterAddr1}))
+	require.NoError(vm.recordVoters(&txFilterer{votedProposalID: proposalID, voterAddr: voterAddr2}))
+	require.NoError(vm.recordVoters(&txFilterer{votedProposalID: ids.GenerateTestID(), voterAddr: voterAddr1}))
+	voters, err := vm.getProposalVoters(proposalID)
+	require.NoError(err)
+	require.ElementsMatch([]ids.ShortID{voterAddr1, voterAddr2}, voters)
+
+	// The voters of finished proposals are dropped.
+	require.NoError(vm.recordVoters(&txFilterer{finishedProposalIDs: []ids.ID{proposalID}}))
+	voters, err = vm.getProposalVoters(proposalID)
+	require.NoError(err)
+	require.Empty(voters)
+}
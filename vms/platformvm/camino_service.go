@@ -93,7 +93,7 @@ func (s *CaminoService) GetBalance(_ *http.Request, args *GetBalanceRequest, res
 		return err
 	}
 
-	utxos, err := avax.GetAllUTXOs(s.vm.state, addrs)
+	utxos, err := s.getAllUTXOs(addrs, args.Height)
 	if err != nil {
 		return fmt.Errorf("couldn't get UTXO set of %v: %w", args.Addresses, err)
 	}
@@ -661,6 +661,9 @@ type APIClaimable struct {
 
 type GetClaimablesArgs struct {
 	Owners []platformapi.Owner `json:"owners"`
+	// If set, the claimables after the block at this height was accepted are
+	// returned. Requires the archive to be enabled.
+	Height *utilsjson.Uint64 `json:"height,omitempty"`
 }
 
 type GetClaimablesReply struct {
@@ -683,7 +686,7 @@ func (s *CaminoService) GetClaimables(_ *http.Request, args *GetClaimablesArgs,
 			return err
 		}
 
-		claimable, err := s.vm.state.GetClaimable(ownerID)
+		claimable, err := s.getClaimable(ownerID, args.Height)
 		if err == database.ErrNotFound {
 			continue
 		} else if err != nil {
@@ -735,6 +738,10 @@ func (s *CaminoService) apiDepositFromDeposit(depositTxID ids.ID, deposit *depos
 
 type GetDepositsArgs struct {
 	DepositTxIDs []ids.ID `json:"depositTxIDs"`
+	// If set, the deposits after the block at this height was accepted are
+	// returned. Their amounts are calculated at the chain time of that block.
+	// Requires the archive to be enabled.
+	Height *utilsjson.Uint64 `json:"height,omitempty"`
 }
 
 type GetDepositsReply struct {
@@ -746,12 +753,15 @@ type GetDepositsReply struct {
 // GetDeposits returns deposits by IDs
 func (s *CaminoService) GetDeposits(_ *http.Request, args *GetDepositsArgs, reply *GetDepositsReply) error {
 	s.vm.ctx.Log.Debug("Platform: GetDeposits called")
-	timestamp := s.vm.clock.Unix()
+	timestamp, err := s.getUnixTimestamp(args.Height)
+	if err != nil {
+		return err
+	}
 	reply.Deposits = make([]*APIDeposit, len(args.DepositTxIDs))
 	reply.AvailableRewards = make([]utilsjson.Uint64, len(args.DepositTxIDs))
 	reply.Timestamp = utilsjson.Uint64(timestamp)
 	for i := range args.DepositTxIDs {
-		deposit, err := s.vm.state.GetDeposit(args.DepositTxIDs[i])
+		deposit, err := s.getDeposit(args.DepositTxIDs[i], args.Height)
 		if err != nil {
 			return fmt.Errorf("could't get deposit from state: %w", err)
 		}
@@ -0,0 +1,301 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/snowman/block"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/statesync"
+)
+
+var (
+	_ block.StateSyncableVM      = (*VM)(nil)
+	_ block.HeightIndexedChainVM = (*VM)(nil)
+	_ block.StateSummary         = (*stateSummary)(nil)
+
+	stateSyncPrefix = []byte("stateSync")
+
+	errSummaryBlockMismatch  = errors.New("summary block doesn't match summary")
+	errValidatorSetNotSynced = errors.New("validator set precedes synced state")
+)
+
+// stateSummary binds a summary to the VM, which accepts it.
+type stateSummary struct {
+	*statesync.Summary
+	vm *VM
+}
+
+// Accept starts to sync the VM to the summary, unless the summary isn't ahead
+// of the last accepted block.
+func (s *stateSummary) Accept(ctx context.Context) (block.StateSyncMode, error) {
+	lastAcceptedHeight, err := s.vm.GetCurrentHeight(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if s.Height() <= lastAcceptedHeight {
+		s.vm.ctx.Log.Info("skipping state sync",
+			zap.Uint64("summaryHeight", s.Height()),
+			zap.Uint64("lastAcceptedHeight", lastAcceptedHeight),
+		)
+		return block.StateSyncSkipped, nil
+	}
+
+	if _, err := parseSummaryBlock(s.Summary); err != nil {
+		return 0, err
+	}
+
+	s.vm.ctx.Log.Info("starting state sync",
+		zap.Stringer("summaryID", s.ID()),
+		zap.Uint64("summaryHeight", s.Height()),
+	)
+	if err := s.vm.stateSyncClient.Start(s.Summary); err != nil {
+		return 0, err
+	}
+	return block.StateSyncStatic, nil
+}
+
+// initStateSync creates the state sync server and client. It must be called
+// after the state and block manager were created.
+func (vm *VM) initStateSync() error {
+	stateSyncDB := prefixdb.New(stateSyncPrefix, vm.dbManager.Current().Database)
+
+	var err error
+	vm.stateSyncServer, err = statesync.NewServer(
+		vm.ctx.Log,
+		stateSyncDB,
+		vm.appSender,
+		vm.executionConfig.StateSyncSummaryInterval,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize state sync server: %w", err)
+	}
+
+	vm.stateSyncClient = statesync.NewClient(
+		vm.ctx.Log,
+		stateSyncDB,
+		vm.appSender,
+		vm.toEngine,
+		vm.applyStateSummary,
+	)
+	syncedHeight, err := vm.stateSyncClient.GetSyncedHeight()
+	switch {
+	case err == nil:
+		vm.stateSyncHeight = syncedHeight
+	case err != database.ErrNotFound:
+		return err
+	}
+
+	if vm.stateSyncServer.Enabled() {
+		ctx, cancel := context.WithCancel(context.Background())
+		vm.summaryReady = make(chan struct{}, 1)
+		vm.stopSummaries = cancel
+		go vm.generateSummaries(ctx)
+	}
+	return nil
+}
+
+// pendingSummary is a state summary, that is due at the height of [blk].
+type pendingSummary struct {
+	blk blocks.Block
+	// Snapshot of the state right after [blk] was accepted
+	snapshot state.SyncSnapshot
+}
+
+// onBlockCommitted publishes the txs of [blk], which were collected while it
+// was accepted, and schedules the generation of a state summary, if one is due
+// at the height of [blk]. The summary is generated from a snapshot of the
+// committed state, so it's generated even if more blocks are accepted before.
+func (vm *VM) onBlockCommitted(blk blocks.Block) {
+	vm.publishAcceptedTxs()
+
+	if !vm.bootstrapped.Get() || !vm.stateSyncServer.Due(blk.Height()) {
+		return
+	}
+
+	vm.summaryLock.Lock()
+	vm.pendingSummaries = append(vm.pendingSummaries, pendingSummary{
+		blk:      blk,
+		snapshot: vm.state.NewSyncSnapshot(),
+	})
+	vm.summaryLock.Unlock()
+
+	select {
+	case vm.summaryReady <- struct{}{}:
+	default:
+	}
+}
+
+// generateSummaries generates the state summaries scheduled by
+// onBlockCommitted in the order of their heights until [ctx] is cancelled.
+// The chain lock isn't held while a summary is generated.
+func (vm *VM) generateSummaries(ctx context.Context) {
+	defer vm.releasePendingSummaries()
+
+	for {
+		select {
+		case <-vm.summaryReady:
+		case <-ctx.Done():
+			return
+		}
+
+		for {
+			vm.summaryLock.Lock()
+			if len(vm.pendingSummaries) == 0 {
+				vm.summaryLock.Unlock()
+				break
+			}
+			summary := vm.pendingSummaries[0]
+			vm.pendingSummaries = vm.pendingSummaries[1:]
+			vm.summaryLock.Unlock()
+
+			err := vm.stateSyncServer.Generate(ctx, summary.blk.Height(), summary.blk.Bytes(), summary.snapshot)
+			if err := summary.snapshot.Release(); err != nil {
+				vm.ctx.Log.Warn("failed to release state snapshot",
+					zap.Uint64("height", summary.blk.Height()),
+					zap.Error(err),
+				)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				vm.ctx.Log.Error("failed to generate state summary",
+					zap.Stringer("blkID", summary.blk.ID()),
+					zap.Uint64("height", summary.blk.Height()),
+					zap.Error(err),
+				)
+			}
+		}
+	}
+}
+
+// releasePendingSummaries drops the summaries, that weren't generated before
+// the VM was shut down.
+func (vm *VM) releasePendingSummaries() {
+	vm.summaryLock.Lock()
+	defer vm.summaryLock.Unlock()
+
+	for _, summary := range vm.pendingSummaries {
+		_ = summary.snapshot.Release()
+	}
+	vm.pendingSummaries = nil
+}
+
+// applyStateSummary replaces the state of the VM with [records] of [summary].
+func (vm *VM) applyStateSummary(ctx context.Context, summary *statesync.Summary, records database.Iterator) error {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	// The VM may have been shut down while the records were fetched.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	blk, err := parseSummaryBlock(summary)
+	if err != nil {
+		return err
+	}
+	if err := vm.state.ApplySyncRecords(records, blk); err != nil {
+		return err
+	}
+
+	vm.manager.SetLastAccepted(blk.ID())
+	vm.stateSyncHeight = blk.Height()
+	vm.validatorSetCaches = make(map[ids.ID]cache.Cacher[uint64, map[ids.NodeID]*validators.GetValidatorOutput])
+
+	if err := vm.initBlockchains(); err != nil {
+		return fmt.Errorf("failed to initialize blockchains: %w", err)
+	}
+	return vm.SetPreference(ctx, blk.ID())
+}
+
+func (vm *VM) StateSyncEnabled(context.Context) (bool, error) {
+	return vm.executionConfig.StateSyncEnabled, nil
+}
+
+func (vm *VM) GetOngoingSyncStateSummary(context.Context) (block.StateSummary, error) {
+	summary, err := vm.stateSyncClient.GetOngoingSummary()
+	if err != nil {
+		return nil, err
+	}
+	return &stateSummary{Summary: summary, vm: vm}, nil
+}
+
+func (vm *VM) GetLastStateSummary(context.Context) (block.StateSummary, error) {
+	summary, err := vm.stateSyncServer.GetLastSummary()
+	if err != nil {
+		return nil, err
+	}
+	return &stateSummary{Summary: summary, vm: vm}, nil
+}
+
+func (vm *VM) ParseStateSummary(_ context.Context, summaryBytes []byte) (block.StateSummary, error) {
+	summary, err := statesync.ParseSummary(summaryBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &stateSummary{Summary: summary, vm: vm}, nil
+}
+
+func (vm *VM) GetStateSummary(_ context.Context, summaryHeight uint64) (block.StateSummary, error) {
+	summary, err := vm.stateSyncServer.GetSummary(summaryHeight)
+	if err != nil {
+		return nil, err
+	}
+	return &stateSummary{Summary: summary, vm: vm}, nil
+}
+
+func (*VM) VerifyHeightIndex(context.Context) error {
+	return nil
+}
+
+func (vm *VM) GetBlockIDAtHeight(_ context.Context, height uint64) (ids.ID, error) {
+	return vm.state.GetBlockIDAtHeight(height)
+}
+
+func (vm *VM) AppRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, _ time.Time, request []byte) error {
+	return vm.stateSyncServer.HandleRequest(ctx, nodeID, requestID, request)
+}
+
+func (vm *VM) AppResponse(_ context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+	vm.stateSyncClient.HandleResponse(nodeID, requestID, response)
+	return nil
+}
+
+func (vm *VM) AppRequestFailed(_ context.Context, nodeID ids.NodeID, requestID uint32) error {
+	vm.stateSyncClient.HandleRequestFailed(nodeID, requestID)
+	return nil
+}
+
+// parseSummaryBlock returns the block, that was accepted at the height of
+// [summary].
+func parseSummaryBlock(summary *statesync.Summary) (blocks.Block, error) {
+	// Note: the summary block isn't verified, so we must use blocks.Codec
+	// rather than blocks.GenesisCodec
+	blk, err := blocks.Parse(blocks.Codec, summary.BlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse summary block: %w", err)
+	}
+	if blk.Height() != summary.Height() {
+		return nil, fmt.Errorf("%w: block height %d, summary height %d",
+			errSummaryBlockMismatch,
+			blk.Height(),
+			summary.Height(),
+		)
+	}
+	return blk, nil
+}
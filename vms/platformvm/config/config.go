@@ -16,6 +16,7 @@ package config
 import (
 	"time"
 
+	"github.com/ava-labs/avalanchego/api/health"
 	"github.com/ava-labs/avalanchego/chains"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/uptime"
@@ -40,12 +41,20 @@ type Config struct {
 	//            calling VM.Initialize.
 	Validators validators.Manager
 
+	// Pending validators of the primary network. May be nil, in which case
+	// they aren't tracked.
+	PendingValidators validators.Set
+
 	// Provides access to the uptime manager as a thread safe data structure
 	UptimeLockedCalculator uptime.LockedCalculator
 
 	// True if the node is being run with staking enabled
 	StakingEnabled bool
 
+	// Registers the Camino specific health checks of the node. May be nil, in
+	// which case no such checks are registered.
+	Health health.Registerer
+
 	// Set of subnets that this node is validating
 	TrackedSubnets set.Set[ids.ID]
 
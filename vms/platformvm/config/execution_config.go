@@ -0,0 +1,58 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package config
+
+import (
+	"encoding/json"
+
+	"github.com/ava-labs/avalanchego/vms/components/pruning"
+)
+
+// ExecutionConfig collects the node-local parameters of the PlatformVM. It is
+// read from the chain config of the P-chain.
+type ExecutionConfig struct {
+	// If true, the node will try to state sync instead of replaying all blocks
+	// when it is bootstrapping a fresh database.
+	StateSyncEnabled bool `json:"state-sync-enabled"`
+
+	// Every block with a height divisible by [StateSyncSummaryInterval]
+	// produces a state summary, which is served to syncing peers. Summaries
+	// are generated in the background from a snapshot of the state, so the
+	// state isn't locked while it is exported. If 0, which is the default, no
+	// summaries are produced.
+	StateSyncSummaryInterval uint64 `json:"state-sync-summary-interval"`
+
+	// If true, the accepted blocks below the last [PruningRetainedBlocks]
+	// blocks are deleted in the background. Their txs are deleted too, unless
+	// they are needed to execute later blocks.
+	PruningEnabled        bool   `json:"pruning-enabled"`
+	PruningRetainedBlocks uint64 `json:"pruning-retained-blocks"`
+
+	// If true, the values of UTXOs, deposits and claimables are archived for
+	// every height accepted since archiving was enabled, so that they can be
+	// queried at these heights. Disabling it stops archiving, but keeps the
+	// archived values.
+	ArchiveEnabled bool `json:"archive-enabled"`
+	// If true, the archived values are deleted on startup, before archiving
+	// is enabled or disabled.
+	ArchiveDrop bool `json:"archive-drop"`
+
+	// If true, the /graphql endpoint serves GraphQL queries over the state.
+	GraphQLEnabled bool `json:"graphql-enabled"`
+}
+
+// GetExecutionConfig returns the execution config parsed from [b]. Fields,
+// that aren't set in [b], have their default values.
+func GetExecutionConfig(b []byte) (ExecutionConfig, error) {
+	config := ExecutionConfig{
+		PruningRetainedBlocks: pruning.DefaultRetainedBlocks,
+	}
+	if len(b) == 0 {
+		return config, nil
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return config, err
+	}
+	return config, pruning.VerifyConfig(config.PruningEnabled, config.PruningRetainedBlocks)
+}
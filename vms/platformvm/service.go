@@ -211,6 +211,9 @@ func (s *Service) ImportKey(_ *http.Request, args *ImportKeyArgs, reply *api.JSO
 
 type GetBalanceRequest struct {
 	Addresses []string `json:"addresses"`
+	// If set, the balance after the block at this height was accepted is
+	// returned. Requires the archive to be enabled.
+	Height *json.Uint64 `json:"height,omitempty"`
 }
 
 // Note: We explicitly duplicate AVAX out of the maps to ensure backwards
@@ -242,7 +245,7 @@ func (s *Service) GetBalance(_ *http.Request, args *GetBalanceRequest, response
 		return err
 	}
 
-	utxos, err := avax.GetAllUTXOs(s.vm.state, addrs)
+	utxos, err := s.getAllUTXOs(addrs, args.Height)
 	if err != nil {
 		return fmt.Errorf("couldn't get UTXO set of %v: %w", args.Addresses, err)
 	}
@@ -2050,7 +2053,7 @@ func (s *Service) GetTx(_ *http.Request, args *api.GetTxArgs, response *api.GetT
 
 	tx, _, err := s.vm.state.GetTx(args.TxID)
 	if err != nil {
-		return fmt.Errorf("couldn't get tx: %w", err)
+		return fmt.Errorf("couldn't get tx: %w", s.vm.prunedErr(err))
 	}
 	txBytes := tx.Bytes()
 	response.Encoding = args.Encoding
@@ -2471,7 +2474,7 @@ func (s *Service) GetBlock(_ *http.Request, args *api.GetBlockArgs, response *ap
 
 	block, err := s.vm.manager.GetStatelessBlock(args.BlockID)
 	if err != nil {
-		return fmt.Errorf("couldn't get block with id %s: %w", args.BlockID, err)
+		return fmt.Errorf("couldn't get block with id %s: %w", args.BlockID, s.vm.prunedErr(err))
 	}
 	response.Encoding = args.Encoding
 
@@ -165,6 +165,8 @@ type CaminoState interface {
 	Close() error
 
 	syncGenesis(*state, *genesis.State) error
+	reset()
+	writeArchive(archiveDepositDB, archiveClaimableDB database.Database, height uint64) error
 }
 
 type CaminoConfig struct {
@@ -0,0 +1,414 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var (
+	archivePrefix          = []byte("archive")
+	archiveUTXOPrefix      = []byte("utxo")
+	archiveDepositPrefix   = []byte("deposit")
+	archiveClaimablePrefix = []byte("claimable")
+	archiveSingletonPrefix = []byte("singleton")
+
+	archiveHeightKey        = []byte("archive height")
+	archiveStoppedHeightKey = []byte("archive stopped height")
+
+	errHeightNotArchived = errors.New("height isn't archived")
+	errArchiveIncomplete = errors.New("archive is incomplete")
+)
+
+func (s *state) SetArchiveEnabled(enabled bool) error {
+	if enabled == s.archived {
+		return nil
+	}
+
+	lastAccepted, _, err := s.GetStatelessBlock(s.lastAccepted)
+	if err != nil {
+		return err
+	}
+	height := lastAccepted.Height()
+
+	switch {
+	case !enabled:
+		// The archived values are kept, so that archiving can be resumed at
+		// the same height.
+		if err := database.PutUInt64(s.singletonDB, archiveStoppedHeightKey, height); err != nil {
+			return err
+		}
+		s.archived = false
+		s.archiveStopped = true
+		s.archiveStoppedHeight = height
+	case s.archiveStopped:
+		if s.archiveStoppedHeight != height {
+			return fmt.Errorf("%w: archiving stopped at height %d, but the last accepted height is %d; drop the archive to start a new one",
+				errArchiveIncomplete,
+				s.archiveStoppedHeight,
+				height,
+			)
+		}
+		if err := s.singletonDB.Delete(archiveStoppedHeightKey); err != nil {
+			return err
+		}
+		s.archived = true
+		s.archiveStopped = false
+	default:
+		if err := s.resetArchive(height); err != nil {
+			return err
+		}
+	}
+	return s.baseDB.Commit()
+}
+
+func (s *state) DropArchive() error {
+	if err := database.Clear(s.archiveDB, s.archiveDB); err != nil {
+		return err
+	}
+	if err := s.singletonDB.Delete(archiveHeightKey); err != nil {
+		return err
+	}
+	if err := s.singletonDB.Delete(archiveStoppedHeightKey); err != nil {
+		return err
+	}
+	if err := s.baseDB.Commit(); err != nil {
+		return err
+	}
+	s.archived = false
+	s.archiveStopped = false
+	return nil
+}
+
+func (s *state) GetArchivedUTXOs(addrs set.Set[ids.ShortID], height uint64) ([]*avax.UTXO, error) {
+	if err := s.verifyArchivedHeight(height); err != nil {
+		return nil, err
+	}
+
+	currentUTXOs, err := avax.GetAllUTXOs(s, addrs)
+	if err != nil {
+		return nil, err
+	}
+	utxos := make(map[ids.ID]*avax.UTXO, len(currentUTXOs))
+	for _, utxo := range currentUTXOs {
+		utxos[utxo.InputID()] = utxo
+	}
+
+	// A UTXO is archived under all of its addresses at the same height, so
+	// it doesn't matter under which address it is found first.
+	archivedUTXOIDs := set.Set[ids.ID]{}
+	for addr := range addrs {
+		if err := s.rewindUTXOs(addr, height, utxos, archivedUTXOIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]*avax.UTXO, 0, len(utxos))
+	for _, utxo := range utxos {
+		result = append(result, utxo)
+	}
+	slices.SortFunc(result, func(a, b *avax.UTXO) bool {
+		return a.InputID().Less(b.InputID())
+	})
+	return result, nil
+}
+
+func (s *state) GetArchivedDeposit(depositTxID ids.ID, height uint64) (*deposit.Deposit, error) {
+	if err := s.verifyArchivedHeight(height); err != nil {
+		return nil, err
+	}
+
+	depositBytes, archived, err := getArchivedValue(s.archiveDepositDB, depositTxID[:], height)
+	switch {
+	case err != nil:
+		return nil, err
+	case !archived:
+		return s.GetDeposit(depositTxID)
+	case len(depositBytes) == 0:
+		return nil, database.ErrNotFound
+	}
+
+	deposit := &deposit.Deposit{}
+	if _, err := blocks.GenesisCodec.Unmarshal(depositBytes, deposit); err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+func (s *state) GetArchivedClaimable(ownerID ids.ID, height uint64) (*Claimable, error) {
+	if err := s.verifyArchivedHeight(height); err != nil {
+		return nil, err
+	}
+
+	claimableBytes, archived, err := getArchivedValue(s.archiveClaimableDB, ownerID[:], height)
+	switch {
+	case err != nil:
+		return nil, err
+	case !archived:
+		return s.GetClaimable(ownerID)
+	case len(claimableBytes) == 0:
+		return nil, database.ErrNotFound
+	}
+
+	claimable := &Claimable{}
+	if _, err := blocks.GenesisCodec.Unmarshal(claimableBytes, claimable); err != nil {
+		return nil, err
+	}
+	return claimable, nil
+}
+
+func (s *state) GetArchivedTimestamp(height uint64) (time.Time, error) {
+	if err := s.verifyArchivedHeight(height); err != nil {
+		return time.Time{}, err
+	}
+
+	timestampBytes, archived, err := getArchivedValue(s.archiveSingletonDB, timestampKey, height)
+	switch {
+	case err != nil:
+		return time.Time{}, err
+	case !archived:
+		return s.persistedTimestamp, nil
+	}
+	return database.ParseTimestamp(timestampBytes)
+}
+
+// writeArchive archives the committed values, that are modified by the
+// uncommitted changes, at [height]. The archive stores the value, that a key
+// had before the block at a height was accepted, under the key and the height.
+// An empty value means that the key didn't exist. So, the value of a key at
+// height h is the first value archived above h or, if there is none, the
+// current value.
+//
+// Must be called before the modifications are written.
+func (s *state) writeArchive(height uint64) error {
+	if !s.archived || height <= s.archiveHeight {
+		return nil
+	}
+
+	for utxoID, utxo := range s.modifiedUTXOs {
+		if err := s.archiveUTXO(utxoID, utxo, height); err != nil {
+			return fmt.Errorf("failed to archive UTXO %s: %w", utxoID, err)
+		}
+	}
+
+	if !s.timestamp.Equal(s.persistedTimestamp) {
+		if err := archiveValue(s.archiveSingletonDB, s.singletonDB, timestampKey, height); err != nil {
+			return fmt.Errorf("failed to archive timestamp: %w", err)
+		}
+	}
+
+	return s.caminoState.writeArchive(s.archiveDepositDB, s.archiveClaimableDB, height)
+}
+
+func (cs *caminoState) writeArchive(archiveDepositDB, archiveClaimableDB database.Database, height uint64) error {
+	for depositTxID := range cs.modifiedDeposits {
+		if err := archiveValue(archiveDepositDB, cs.depositsDB, depositTxID[:], height); err != nil {
+			return fmt.Errorf("failed to archive deposit %s: %w", depositTxID, err)
+		}
+	}
+	for ownerID := range cs.modifiedClaimables {
+		if err := archiveValue(archiveClaimableDB, cs.claimablesDB, ownerID[:], height); err != nil {
+			return fmt.Errorf("failed to archive claimable %s: %w", ownerID, err)
+		}
+	}
+	return nil
+}
+
+// archiveUTXO archives the committed value of the UTXO [utxoID], which is
+// modified to [utxo], under the addresses of both.
+func (s *state) archiveUTXO(utxoID ids.ID, utxo *avax.UTXO, height uint64) error {
+	prevUTXO, err := s.utxoState.GetUTXO(utxoID)
+	switch {
+	case err == database.ErrNotFound:
+		prevUTXO = nil
+	case err != nil:
+		return err
+	}
+	if prevUTXO == nil && utxo == nil {
+		return nil
+	}
+
+	var prevUTXOBytes []byte
+	if prevUTXO != nil {
+		prevUTXOBytes, err = txs.GenesisCodec.Marshal(txs.Version, prevUTXO)
+		if err != nil {
+			return err
+		}
+	}
+
+	addrs := set.Set[ids.ShortID]{}
+	for _, u := range []*avax.UTXO{prevUTXO, utxo} {
+		if u == nil {
+			continue
+		}
+		addressable, ok := u.Out.(avax.Addressable)
+		if !ok {
+			continue
+		}
+		for _, addrBytes := range addressable.Addresses() {
+			addr, err := ids.ToShortID(addrBytes)
+			if err != nil {
+				return err
+			}
+			addrs.Add(addr)
+		}
+	}
+
+	for addr := range addrs {
+		key := append(archiveKey(addr[:], height), utxoID[:]...)
+		has, err := s.archiveUTXODB.Has(key)
+		if err != nil {
+			return err
+		}
+		if has {
+			continue
+		}
+		if err := s.archiveUTXODB.Put(key, prevUTXOBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewindUTXOs replaces the current UTXOs of [addr] in [utxos] with their
+// values at [height]. UTXOs in [skip] are ignored and the rewound UTXOs are
+// added to [skip].
+func (s *state) rewindUTXOs(addr ids.ShortID, height uint64, utxos map[ids.ID]*avax.UTXO, skip set.Set[ids.ID]) error {
+	it := s.archiveUTXODB.NewIteratorWithStartAndPrefix(archiveKey(addr[:], height+1), addr[:])
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		utxoID, err := ids.ToID(key[len(addr)+wrappers.LongLen:])
+		if err != nil {
+			return err
+		}
+		if skip.Contains(utxoID) {
+			continue
+		}
+		skip.Add(utxoID)
+
+		utxoBytes := it.Value()
+		if len(utxoBytes) == 0 {
+			delete(utxos, utxoID)
+			continue
+		}
+		utxo := &avax.UTXO{}
+		if _, err := txs.GenesisCodec.Unmarshal(utxoBytes, utxo); err != nil {
+			return err
+		}
+		utxos[utxoID] = utxo
+	}
+	return it.Error()
+}
+
+// resetArchive drops all archived values and starts the archive at [height].
+func (s *state) resetArchive(height uint64) error {
+	if err := database.Clear(s.archiveDB, s.archiveDB); err != nil {
+		return err
+	}
+	if err := database.PutUInt64(s.singletonDB, archiveHeightKey, height); err != nil {
+		return err
+	}
+	if err := s.singletonDB.Delete(archiveStoppedHeightKey); err != nil {
+		return err
+	}
+	s.archived = true
+	s.archiveStopped = false
+	s.archiveHeight = height
+	return nil
+}
+
+func (s *state) loadArchiveHeight() error {
+	archiveHeight, err := database.GetUInt64(s.singletonDB, archiveHeightKey)
+	switch {
+	case err == database.ErrNotFound:
+		s.archived = false
+		s.archiveStopped = false
+		return nil
+	case err != nil:
+		return err
+	}
+	s.archiveHeight = archiveHeight
+
+	stoppedHeight, err := database.GetUInt64(s.singletonDB, archiveStoppedHeightKey)
+	switch {
+	case err == database.ErrNotFound:
+		s.archived = true
+		s.archiveStopped = false
+	case err != nil:
+		return err
+	default:
+		s.archived = false
+		s.archiveStopped = true
+		s.archiveStoppedHeight = stoppedHeight
+	}
+	return nil
+}
+
+func (s *state) verifyArchivedHeight(height uint64) error {
+	if !s.archived || height < s.archiveHeight {
+		return fmt.Errorf("%w: %d", errHeightNotArchived, height)
+	}
+	lastAccepted, _, err := s.GetStatelessBlock(s.lastAccepted)
+	if err != nil {
+		return err
+	}
+	if height > lastAccepted.Height() {
+		return fmt.Errorf("%w: %d is above last accepted height %d",
+			errHeightNotArchived,
+			height,
+			lastAccepted.Height(),
+		)
+	}
+	return nil
+}
+
+// archiveValue archives the committed value of [key] in [db] at [height],
+// unless a value of [key] was already archived at [height].
+func archiveValue(archiveDB, db database.Database, key []byte, height uint64) error {
+	archivedKey := archiveKey(key, height)
+	if has, err := archiveDB.Has(archivedKey); err != nil || has {
+		return err
+	}
+	value, err := db.Get(key)
+	switch {
+	case err == database.ErrNotFound:
+		value = nil
+	case err != nil:
+		return err
+	}
+	return archiveDB.Put(archivedKey, value)
+}
+
+// getArchivedValue returns the first value of [key], that was archived above
+// [height]. Returns false, if no value was archived above [height].
+func getArchivedValue(archiveDB database.Database, key []byte, height uint64) ([]byte, bool, error) {
+	it := archiveDB.NewIteratorWithStartAndPrefix(archiveKey(key, height+1), key)
+	defer it.Release()
+
+	if !it.Next() {
+		return nil, false, it.Error()
+	}
+	return slices.Clone(it.Value()), true, nil
+}
+
+func archiveKey(key []byte, height uint64) []byte {
+	archivedKey := make([]byte, len(key), len(key)+wrappers.LongLen+len(ids.Empty))
+	copy(archivedKey, key)
+	return append(archivedKey, database.PackUInt64(height)...)
+}
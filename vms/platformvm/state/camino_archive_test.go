@@ -0,0 +1,165 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/set"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestArchive(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes, _, err := genesis.FromConfig(testGenesisConfig(true, true, true))
+	require.NoError(err)
+
+	db := memdb.New()
+	s := newEmptyStateWithDB(t, db)
+	require.NoError(s.sync(genesisBytes))
+	require.NoError(s.SetArchiveEnabled(true))
+
+	lastAccepted, _, err := s.GetStatelessBlock(s.GetLastAccepted())
+	require.NoError(err)
+	archiveHeight := lastAccepted.Height()
+	genesisTimestamp := s.GetTimestamp()
+
+	var (
+		addr        = ids.GenerateTestShortID()
+		addrs       = set.Set[ids.ShortID]{addr: struct{}{}}
+		depositTxID = ids.GenerateTestID()
+		ownerID     = ids.GenerateTestID()
+		newUTXO     = func() *avax.UTXO {
+			return &avax.UTXO{
+				UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+				Asset:  avax.Asset{ID: ids.GenerateTestID()},
+				Out: &secp256k1fx.TransferOutput{
+					Amt:          1,
+					OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{addr}},
+				},
+			}
+		}
+		utxo1     = newUTXO()
+		utxo2     = newUTXO()
+		deposit1  = &deposit.Deposit{Duration: 100, Amount: 10, RewardOwner: &secp256k1fx.OutputOwners{}}
+		deposit2  = &deposit.Deposit{Duration: 100, Amount: 10, UnlockedAmount: 5, RewardOwner: &secp256k1fx.OutputOwners{}}
+		claimable = &Claimable{Owner: &secp256k1fx.OutputOwners{}, ValidatorReward: 1}
+	)
+
+	acceptBlock := func(height uint64, timestamp time.Time) {
+		blk, err := blocks.NewBanffStandardBlock(timestamp, s.GetLastAccepted(), height, nil)
+		require.NoError(err)
+		s.AddStatelessBlock(blk, choices.Accepted)
+		s.SetLastAccepted(blk.ID())
+		s.SetTimestamp(timestamp)
+		s.SetHeight(height)
+		require.NoError(s.Commit())
+	}
+
+	// The first block creates all values.
+	timestamp1 := genesisTimestamp.Add(time.Second)
+	s.AddUTXO(utxo1)
+	s.AddDeposit(depositTxID, deposit1)
+	s.SetClaimable(ownerID, claimable)
+	acceptBlock(archiveHeight+1, timestamp1)
+
+	// The second block modifies or removes them.
+	timestamp2 := timestamp1.Add(time.Second)
+	s.DeleteUTXO(utxo1.InputID())
+	s.AddUTXO(utxo2)
+	s.ModifyDeposit(depositTxID, deposit2)
+	s.SetClaimable(ownerID, nil)
+	acceptBlock(archiveHeight+2, timestamp2)
+
+	requireArchive := func(s *state) {
+		utxos, err := s.GetArchivedUTXOs(addrs, archiveHeight)
+		require.NoError(err)
+		require.Empty(utxos)
+		_, err = s.GetArchivedDeposit(depositTxID, archiveHeight)
+		require.ErrorIs(err, database.ErrNotFound)
+		_, err = s.GetArchivedClaimable(ownerID, archiveHeight)
+		require.ErrorIs(err, database.ErrNotFound)
+		timestamp, err := s.GetArchivedTimestamp(archiveHeight)
+		require.NoError(err)
+		require.Equal(genesisTimestamp.Unix(), timestamp.Unix())
+
+		utxos, err = s.GetArchivedUTXOs(addrs, archiveHeight+1)
+		require.NoError(err)
+		require.Len(utxos, 1)
+		require.Equal(utxo1.InputID(), utxos[0].InputID())
+		archivedDeposit, err := s.GetArchivedDeposit(depositTxID, archiveHeight+1)
+		require.NoError(err)
+		require.Equal(deposit1.UnlockedAmount, archivedDeposit.UnlockedAmount)
+		archivedClaimable, err := s.GetArchivedClaimable(ownerID, archiveHeight+1)
+		require.NoError(err)
+		require.Equal(claimable.ValidatorReward, archivedClaimable.ValidatorReward)
+		timestamp, err = s.GetArchivedTimestamp(archiveHeight + 1)
+		require.NoError(err)
+		require.Equal(timestamp1.Unix(), timestamp.Unix())
+
+		utxos, err = s.GetArchivedUTXOs(addrs, archiveHeight+2)
+		require.NoError(err)
+		require.Len(utxos, 1)
+		require.Equal(utxo2.InputID(), utxos[0].InputID())
+		archivedDeposit, err = s.GetArchivedDeposit(depositTxID, archiveHeight+2)
+		require.NoError(err)
+		require.Equal(deposit2.UnlockedAmount, archivedDeposit.UnlockedAmount)
+		_, err = s.GetArchivedClaimable(ownerID, archiveHeight+2)
+		require.ErrorIs(err, database.ErrNotFound)
+		timestamp, err = s.GetArchivedTimestamp(archiveHeight + 2)
+		require.NoError(err)
+		require.Equal(timestamp2.Unix(), timestamp.Unix())
+
+		_, err = s.GetArchivedUTXOs(addrs, archiveHeight+3)
+		require.ErrorIs(err, errHeightNotArchived)
+	}
+	requireArchive(s)
+
+	// The archive is persisted.
+	s = newEmptyStateWithDB(t, db)
+	require.NoError(s.load())
+	require.NoError(s.SetArchiveEnabled(true))
+	requireArchive(s)
+
+	// Disabling the archive keeps it.
+	require.NoError(s.SetArchiveEnabled(false))
+	_, err = s.GetArchivedUTXOs(addrs, archiveHeight+1)
+	require.ErrorIs(err, errHeightNotArchived)
+	isEmpty, err := database.IsEmpty(s.archiveDB)
+	require.NoError(err)
+	require.False(isEmpty)
+
+	// Archiving is resumed at the height it was stopped at.
+	s = newEmptyStateWithDB(t, db)
+	require.NoError(s.load())
+	require.NoError(s.SetArchiveEnabled(false))
+	require.NoError(s.SetArchiveEnabled(true))
+	requireArchive(s)
+
+	// Archiving can't be resumed after blocks were accepted without it.
+	require.NoError(s.SetArchiveEnabled(false))
+	acceptBlock(archiveHeight+3, timestamp2.Add(time.Second))
+	require.ErrorIs(s.SetArchiveEnabled(true), errArchiveIncomplete)
+
+	// Dropping the archive deletes it.
+	require.NoError(s.DropArchive())
+	_, err = s.GetArchivedUTXOs(addrs, archiveHeight+1)
+	require.ErrorIs(err, errHeightNotArchived)
+	isEmpty, err = database.IsEmpty(s.archiveDB)
+	require.NoError(err)
+	require.True(isEmpty)
+	require.NoError(s.SetArchiveEnabled(true))
+}
@@ -573,7 +573,7 @@ type diffProposalsIterator struct {
 
 func (it *diffProposalsIterator) Next() bool {
 	for it.parentIterator.Next() {
-		proposalID, err := it.parentIterator.key()
+		proposalID, err := it.parentIterator.ProposalID()
 		if err != nil { // should never happen
 			it.err = err
 			return false
@@ -586,7 +586,7 @@ func (it *diffProposalsIterator) Next() bool {
 }
 
 func (it *diffProposalsIterator) Value() (dac.ProposalState, error) {
-	proposalID, err := it.parentIterator.key()
+	proposalID, err := it.parentIterator.ProposalID()
 	if err != nil { // should never happen
 		return nil, err
 	}
@@ -613,8 +613,8 @@ func (it *diffProposalsIterator) Release() {
 	it.parentIterator.Release()
 }
 
-func (it *diffProposalsIterator) key() (ids.ID, error) {
-	return it.parentIterator.key() // err should never happen
+func (it *diffProposalsIterator) ProposalID() (ids.ID, error) {
+	return it.parentIterator.ProposalID() // err should never happen
 }
 
 func (d *diff) GetBaseFee() (uint64, error) {
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow"
@@ -54,10 +55,14 @@ func generateBaseTx(assetID ids.ID, amount uint64, outputOwners secp256k1fx.Outp
 }
 
 func newEmptyState(t *testing.T) *state {
+	return newEmptyStateWithDB(t, memdb.New())
+}
+
+func newEmptyStateWithDB(t *testing.T, db database.Database) *state {
 	vdrs := validators.NewManager()
 	_ = vdrs.Add(constants.PrimaryNetworkID, validators.NewSet())
 	newState, err := new(
-		memdb.New(),
+		db,
 		metrics.Noop,
 		&config.Config{
 			Validators: vdrs,
@@ -0,0 +1,71 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/constants"
+)
+
+// initPendingValidatorSet adds the pending validators of the primary network
+// to the pending validator set of the config, if there is one.
+//
+// Invariant: initPendingValidatorSet requires loadPendingValidators to have
+// already been called.
+func (s *state) initPendingValidatorSet() error {
+	if s.cfg.PendingValidators == nil {
+		return nil
+	}
+	if s.cfg.PendingValidators.Len() != 0 {
+		// Enforce the invariant that the validator set is empty here.
+		return errValidatorSetAlreadyPopulated
+	}
+
+	for nodeID, validator := range s.pendingStakers.validators[constants.PrimaryNetworkID] {
+		staker := validator.validator
+		if err := s.cfg.PendingValidators.Add(nodeID, staker.PublicKey, staker.TxID, staker.Weight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetPendingValidatorSet replaces the pending validator set of the config
+// with the pending validators of the state.
+func (s *state) resetPendingValidatorSet() error {
+	if s.cfg.PendingValidators == nil {
+		return nil
+	}
+
+	for _, vdr := range s.cfg.PendingValidators.List() {
+		if err := s.cfg.PendingValidators.RemoveWeight(vdr.NodeID, vdr.Weight); err != nil {
+			return err
+		}
+	}
+	return s.initPendingValidatorSet()
+}
+
+// updatePendingValidatorSet applies the diff of a pending primary network
+// validator to the pending validator set of the config, if there is one.
+func (s *state) updatePendingValidatorSet(validatorDiff *diffValidator) error {
+	if s.cfg.PendingValidators == nil {
+		return nil
+	}
+
+	var (
+		staker = validatorDiff.validator
+		err    error
+	)
+	switch validatorDiff.validatorStatus {
+	case added:
+		err = s.cfg.PendingValidators.Add(staker.NodeID, staker.PublicKey, staker.TxID, staker.Weight)
+	case deleted:
+		err = s.cfg.PendingValidators.RemoveWeight(staker.NodeID, staker.Weight)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update pending validator: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/validators"
+	"github.com/ava-labs/avalanchego/utils"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/vms/platformvm/config"
+	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
+	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+func TestPendingValidatorSet(t *testing.T) {
+	require := require.New(t)
+
+	vdrs := validators.NewManager()
+	_ = vdrs.Add(constants.PrimaryNetworkID, validators.NewSet())
+	pendingVdrs := validators.NewSet()
+	s, err := new(
+		memdb.New(),
+		metrics.Noop,
+		&config.Config{
+			Validators:        vdrs,
+			PendingValidators: pendingVdrs,
+		},
+		&snow.Context{},
+		prometheus.NewRegistry(),
+		reward.NewCalculator(reward.Config{}),
+		&utils.Atomic[bool]{},
+	)
+	require.NoError(err)
+
+	startTime := time.Unix(1_000_000, 0)
+	staker := &Staker{
+		TxID:      ids.GenerateTestID(),
+		NodeID:    ids.GenerateTestNodeID(),
+		SubnetID:  constants.PrimaryNetworkID,
+		Weight:    1,
+		StartTime: startTime,
+		EndTime:   startTime.Add(time.Hour),
+		NextTime:  startTime,
+		Priority:  txs.PrimaryNetworkValidatorPendingPriority,
+	}
+	s.PutPendingValidator(staker)
+	require.NoError(s.Commit())
+	require.True(pendingVdrs.Contains(staker.NodeID))
+
+	// The pending validators are reloaded when the state is reset
+	require.NoError(s.resetPendingValidatorSet())
+	require.Equal(1, pendingVdrs.Len())
+	require.True(pendingVdrs.Contains(staker.NodeID))
+
+	s.DeletePendingValidator(staker)
+	require.NoError(s.Commit())
+	require.False(pendingVdrs.Contains(staker.NodeID))
+}
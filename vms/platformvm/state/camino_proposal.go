@@ -347,7 +347,8 @@ type ProposalsIterator interface {
 	Error() error
 	Release()
 
-	key() (ids.ID, error)
+	// ProposalID returns the ID of the current proposal.
+	ProposalID() (ids.ID, error)
 }
 
 type proposalsIterator struct {
@@ -409,6 +410,6 @@ func (it *proposalsIterator) Release() {
 	it.dbIterator.Release()
 }
 
-func (it *proposalsIterator) key() (ids.ID, error) {
+func (it *proposalsIterator) ProposalID() (ids.ID, error) {
 	return ids.ToID(it.dbIterator.Key()) // err should never happen
 }
@@ -0,0 +1,142 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/platformvm/locked"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+var (
+	prunedHeightKey = []byte("pruned height")
+
+	errPruneAboveLastAccepted = errors.New("can't prune the last accepted block")
+)
+
+func (s *state) GetPrunedHeight() uint64 {
+	return s.prunedHeight
+}
+
+// Prune deletes the accepted blocks with heights below [height], starting with
+// the lowest block that isn't pruned yet, and the txs of these blocks that
+// aren't retained. At most [maxBlocks] blocks are deleted. Returns the new
+// pruned height.
+//
+// Invariant: There must not be any uncommitted changes.
+func (s *state) Prune(height uint64, maxBlocks int) (uint64, error) {
+	lastAccepted, _, err := s.GetStatelessBlock(s.lastAccepted)
+	if err != nil {
+		return s.prunedHeight, err
+	}
+	if height > lastAccepted.Height() {
+		return s.prunedHeight, fmt.Errorf("%w: height %d, last accepted height %d",
+			errPruneAboveLastAccepted,
+			height,
+			lastAccepted.Height(),
+		)
+	}
+
+	prunedHeight := s.prunedHeight
+	for ; prunedHeight < height && maxBlocks > 0; prunedHeight++ {
+		blkID, err := s.GetBlockIDAtHeight(prunedHeight)
+		if err != nil {
+			return s.prunedHeight, fmt.Errorf("failed to get block ID at height %d: %w", prunedHeight, err)
+		}
+		if err := s.pruneBlock(blkID); err != nil {
+			return s.prunedHeight, err
+		}
+		maxBlocks--
+	}
+	if prunedHeight == s.prunedHeight {
+		return s.prunedHeight, nil
+	}
+
+	if err := database.PutUInt64(s.singletonDB, prunedHeightKey, prunedHeight); err != nil {
+		return s.prunedHeight, err
+	}
+	if err := s.baseDB.Commit(); err != nil {
+		return s.prunedHeight, err
+	}
+	s.prunedHeight = prunedHeight
+	return s.prunedHeight, nil
+}
+
+func (s *state) pruneBlock(blkID ids.ID) error {
+	blk, _, err := s.GetStatelessBlock(blkID)
+	if err == database.ErrNotFound {
+		// The block was already pruned, but the pruned height wasn't
+		// committed.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get block %s: %w", blkID, err)
+	}
+
+	for _, tx := range blk.Txs() {
+		if isRetainedTx(tx) {
+			continue
+		}
+		txID := tx.ID()
+		s.txCache.Evict(txID)
+		if err := s.txDB.Delete(txID[:]); err != nil {
+			return err
+		}
+	}
+
+	s.blockCache.Evict(blkID)
+	return s.blockDB.Delete(blkID[:])
+}
+
+func (s *state) loadPrunedHeight() error {
+	prunedHeight, err := database.GetUInt64(s.singletonDB, prunedHeightKey)
+	switch {
+	case err == database.ErrNotFound:
+		s.prunedHeight = 0
+	case err != nil:
+		return err
+	default:
+		s.prunedHeight = prunedHeight
+	}
+	return nil
+}
+
+// isRetainedTx returns true if [tx] must be kept after the block, that
+// accepted it, was pruned. These txs are read when later txs are executed or
+// the state is loaded:
+//   - txs that create subnets and chains
+//   - txs that add stakers
+//   - txs that lock outputs, because unlocking them requires the lock tx
+func isRetainedTx(tx *txs.Tx) bool {
+	switch tx.Unsigned.(type) {
+	case *txs.CreateSubnetTx, *txs.CreateChainTx, *txs.TransformSubnetTx, txs.Staker:
+		return true
+	}
+	for _, out := range tx.Unsigned.Outputs() {
+		lockedOut, ok := out.Out.(*locked.Out)
+		if ok && (lockedOut.IsNewlyLockedWith(locked.StateDeposited) ||
+			lockedOut.IsNewlyLockedWith(locked.StateBonded)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetainedTxBytes returns true if the stored tx [txBytes] is retained by
+// pruning.
+func isRetainedTxBytes(txBytes []byte) (bool, error) {
+	stx := txBytesAndStatus{}
+	if _, err := txs.GenesisCodec.Unmarshal(txBytes, &stx); err != nil {
+		return false, err
+	}
+	tx, err := txs.Parse(txs.GenesisCodec, stx.Tx)
+	if err != nil {
+		return false, err
+	}
+	return isRetainedTx(tx), nil
+}
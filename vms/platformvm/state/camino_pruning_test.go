@@ -0,0 +1,151 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/locked"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func TestIsRetainedTx(t *testing.T) {
+	tests := map[string]struct {
+		tx       txs.UnsignedTx
+		retained bool
+	}{
+		"BaseTx": {
+			tx:       &txs.BaseTx{},
+			retained: false,
+		},
+		"CreateSubnetTx": {
+			tx:       &txs.CreateSubnetTx{Owner: &secp256k1fx.OutputOwners{}},
+			retained: true,
+		},
+		"CaminoAddValidatorTx": {
+			tx:       &txs.CaminoAddValidatorTx{AddValidatorTx: txs.AddValidatorTx{RewardsOwner: &secp256k1fx.OutputOwners{}}},
+			retained: true,
+		},
+		"Deposit lock tx": {
+			tx: &txs.DepositTx{
+				BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{Outs: []*avax.TransferableOutput{{
+					Out: &locked.Out{
+						IDs:             locked.IDs{DepositTxID: locked.ThisTxID},
+						TransferableOut: &secp256k1fx.TransferOutput{},
+					},
+				}}}},
+				RewardsOwner: &secp256k1fx.OutputOwners{},
+			},
+			retained: true,
+		},
+		"Tx with already locked output": {
+			tx: &txs.BaseTx{BaseTx: avax.BaseTx{Outs: []*avax.TransferableOutput{{
+				Out: &locked.Out{
+					IDs:             locked.IDs{DepositTxID: ids.GenerateTestID()},
+					TransferableOut: &secp256k1fx.TransferOutput{},
+				},
+			}}}},
+			retained: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tt.retained, isRetainedTx(&txs.Tx{Unsigned: tt.tx}))
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes, _, err := genesis.FromConfig(testGenesisConfig(true, true, true))
+	require.NoError(err)
+
+	db := memdb.New()
+	s := newEmptyStateWithDB(t, db)
+	require.NoError(s.sync(genesisBytes))
+
+	// Accept blocks that contain a tx that is pruned and a tx that is
+	// retained.
+	var (
+		blks        []blocks.Block
+		prunableTxs []*txs.Tx
+		retainedTxs []*txs.Tx
+	)
+	parentID := s.GetLastAccepted()
+	parent, _, err := s.GetStatelessBlock(parentID)
+	require.NoError(err)
+	firstHeight := parent.Height() + 1
+	for height := firstHeight; height < firstHeight+5; height++ {
+		prunableTx := &txs.Tx{Unsigned: &txs.BaseTx{BaseTx: avax.BaseTx{Memo: database.PackUInt64(height)}}}
+		require.NoError(prunableTx.Initialize(txs.Codec))
+		retainedTx := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+			BaseTx: txs.BaseTx{BaseTx: avax.BaseTx{Memo: database.PackUInt64(height)}},
+			Owner:  &secp256k1fx.OutputOwners{},
+		}}
+		require.NoError(retainedTx.Initialize(txs.Codec))
+
+		blk, err := blocks.NewBanffStandardBlock(s.GetTimestamp(), parentID, height, []*txs.Tx{prunableTx, retainedTx})
+		require.NoError(err)
+		s.AddStatelessBlock(blk, choices.Accepted)
+		s.AddTx(prunableTx, status.Committed)
+		s.AddTx(retainedTx, status.Committed)
+		s.SetLastAccepted(blk.ID())
+		s.SetHeight(height)
+		require.NoError(s.Commit())
+
+		blks = append(blks, blk)
+		prunableTxs = append(prunableTxs, prunableTx)
+		retainedTxs = append(retainedTxs, retainedTx)
+		parentID = blk.ID()
+	}
+
+	lastHeight := firstHeight + 4
+	_, err = s.Prune(lastHeight+1, 1)
+	require.ErrorIs(err, errPruneAboveLastAccepted)
+
+	// Prune in batches until all blocks below the third block are pruned.
+	pruneHeight := firstHeight + 2
+	for s.GetPrunedHeight() < pruneHeight {
+		prunedHeight, err := s.Prune(pruneHeight, 2)
+		require.NoError(err)
+		require.Equal(s.GetPrunedHeight(), prunedHeight)
+	}
+	require.Equal(pruneHeight, s.GetPrunedHeight())
+
+	for i, blk := range blks {
+		_, _, blkErr := s.GetStatelessBlock(blk.ID())
+		_, _, prunableTxErr := s.GetTx(prunableTxs[i].ID())
+		if blk.Height() < pruneHeight {
+			require.ErrorIs(blkErr, database.ErrNotFound)
+			require.ErrorIs(prunableTxErr, database.ErrNotFound)
+		} else {
+			require.NoError(blkErr)
+			require.NoError(prunableTxErr)
+		}
+		_, _, err := s.GetTx(retainedTxs[i].ID())
+		require.NoError(err)
+
+		// The height index is kept.
+		blkID, err := s.GetBlockIDAtHeight(blk.Height())
+		require.NoError(err)
+		require.Equal(blk.ID(), blkID)
+	}
+
+	// The pruned height is persisted.
+	s = newEmptyStateWithDB(t, db)
+	require.NoError(s.load())
+	require.Equal(pruneHeight, s.GetPrunedHeight())
+}
@@ -0,0 +1,246 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+var (
+	_ database.Database = (*snapshotDB)(nil)
+	_ database.Batch    = (*snapshotBatch)(nil)
+	_ database.Database = (*dbSnapshot)(nil)
+	_ database.Batch    = (*failingBatch)(nil)
+
+	errReadOnlySnapshot = errors.New("snapshot is read-only")
+)
+
+// snapshotDB is a database, of which consistent snapshots can be read while
+// the database is modified. Before a key is modified, its current value is
+// preserved by every open snapshot, so a snapshot keeps returning the values
+// the database had when the snapshot was taken.
+type snapshotDB struct {
+	database.Database
+
+	// lock is held while the database is modified and while a snapshot reads
+	// from the database, so the modification of a key and the preservation
+	// of its previous value are atomic for the snapshots.
+	lock      sync.RWMutex
+	snapshots set.Set[*dbSnapshot]
+}
+
+func newSnapshotDB(db database.Database) *snapshotDB {
+	return &snapshotDB{Database: db}
+}
+
+// newSnapshot returns a snapshot of the current content of the database. It
+// must be closed, once it isn't used anymore.
+func (db *snapshotDB) newSnapshot() *dbSnapshot {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	snapshot := &dbSnapshot{
+		db:       db,
+		Database: versiondb.New(db.Database),
+	}
+	db.snapshots.Add(snapshot)
+	return snapshot
+}
+
+func (db *snapshotDB) Put(key, value []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := db.preserve(key); err != nil {
+		return err
+	}
+	return db.Database.Put(key, value)
+}
+
+func (db *snapshotDB) Delete(key []byte) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if err := db.preserve(key); err != nil {
+		return err
+	}
+	return db.Database.Delete(key)
+}
+
+func (db *snapshotDB) NewBatch() database.Batch {
+	return &snapshotBatch{
+		Batch: db.Database.NewBatch(),
+		db:    db,
+	}
+}
+
+// preserve passes the current value of [key] to the open snapshots.
+// Assumes [db.lock] is held.
+func (db *snapshotDB) preserve(key []byte) error {
+	if db.snapshots.Len() == 0 {
+		return nil
+	}
+	value, err := db.Database.Get(key)
+	if err != nil && err != database.ErrNotFound {
+		return err
+	}
+	for snapshot := range db.snapshots {
+		if err := snapshot.preserve(key, value, err == nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotBatch preserves the values of the keys it modifies, before it's
+// written.
+type snapshotBatch struct {
+	database.Batch
+
+	db *snapshotDB
+}
+
+func (b *snapshotBatch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if err := b.preserve(); err != nil {
+		return err
+	}
+	return b.Batch.Write()
+}
+
+// Inner preserves the values of the keys the batch modifies, because the
+// returned batch is written without the snapshotDB. The preserved values
+// remain correct, if the returned batch is never written.
+func (b *snapshotBatch) Inner() database.Batch {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	if err := b.preserve(); err != nil {
+		return &failingBatch{err: err}
+	}
+	return b.Batch.Inner()
+}
+
+// Assumes [b.db.lock] is held.
+func (b *snapshotBatch) preserve() error {
+	if b.db.snapshots.Len() == 0 {
+		return nil
+	}
+	return b.Batch.Replay(batchPreserver{db: b.db})
+}
+
+// batchPreserver preserves the values of the keys of a replayed batch.
+type batchPreserver struct {
+	db *snapshotDB
+}
+
+func (p batchPreserver) Put(key, _ []byte) error {
+	return p.db.preserve(key)
+}
+
+func (p batchPreserver) Delete(key []byte) error {
+	return p.db.preserve(key)
+}
+
+// dbSnapshot is a read-only snapshot of a snapshotDB. The preserved values
+// are kept in memory and take precedence over the values of the database.
+type dbSnapshot struct {
+	// Reads the preserved values before the database
+	database.Database
+
+	db *snapshotDB
+	// Keys whose values were preserved
+	preserved set.Set[string]
+}
+
+// preserve stores [value] of [key], unless a value of [key] was already
+// preserved. Assumes [s.db.lock] is held.
+func (s *dbSnapshot) preserve(key, value []byte, exists bool) error {
+	if s.preserved.Contains(string(key)) {
+		return nil
+	}
+	s.preserved.Add(string(key))
+	if !exists {
+		return s.Database.Delete(key)
+	}
+	return s.Database.Put(key, value)
+}
+
+func (s *dbSnapshot) Has(key []byte) (bool, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	return s.Database.Has(key)
+}
+
+func (s *dbSnapshot) Get(key []byte) ([]byte, error) {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	return s.Database.Get(key)
+}
+
+func (s *dbSnapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *dbSnapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *dbSnapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix relies on iterators of the database, which
+// don't observe modifications made after their creation.
+func (s *dbSnapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	s.db.lock.RLock()
+	defer s.db.lock.RUnlock()
+
+	return s.Database.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+func (*dbSnapshot) Put([]byte, []byte) error {
+	return errReadOnlySnapshot
+}
+
+func (*dbSnapshot) Delete([]byte) error {
+	return errReadOnlySnapshot
+}
+
+func (*dbSnapshot) NewBatch() database.Batch {
+	return &failingBatch{err: errReadOnlySnapshot}
+}
+
+// Close releases the snapshot. The database isn't closed.
+func (s *dbSnapshot) Close() error {
+	s.db.lock.Lock()
+	defer s.db.lock.Unlock()
+
+	s.db.snapshots.Remove(s)
+	return s.Database.Close()
+}
+
+// failingBatch is a batch, whose writes fail with [err].
+type failingBatch struct {
+	database.BatchOps
+
+	err error
+}
+
+func (b *failingBatch) Write() error {
+	return b.err
+}
+
+func (b *failingBatch) Inner() database.Batch {
+	return b
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+)
+
+func readTestSnapshot(require *require.Assertions, db database.Database) map[string]string {
+	it := db.NewIterator()
+	defer it.Release()
+
+	content := map[string]string{}
+	for it.Next() {
+		content[string(it.Key())] = string(it.Value())
+	}
+	require.NoError(it.Error())
+	return content
+}
+
+func TestSnapshotDB(t *testing.T) {
+	require := require.New(t)
+
+	db := newSnapshotDB(memdb.New())
+	require.NoError(db.Put([]byte("a"), []byte("1")))
+	require.NoError(db.Put([]byte("b"), []byte("2")))
+	expected := map[string]string{"a": "1", "b": "2"}
+
+	snapshot := db.newSnapshot()
+
+	// Direct writes
+	require.NoError(db.Put([]byte("a"), []byte("3")))
+	require.NoError(db.Delete([]byte("b")))
+	require.NoError(db.Put([]byte("c"), []byte("4")))
+	// Writes of a batch
+	batch := db.NewBatch()
+	require.NoError(batch.Put([]byte("a"), []byte("5")))
+	require.NoError(batch.Put([]byte("b"), []byte("6")))
+	require.NoError(batch.Write())
+	// Writes of the inner batch, that is written by shared memory
+	batch = db.NewBatch()
+	require.NoError(batch.Put([]byte("d"), []byte("7")))
+	require.NoError(batch.Inner().Write())
+
+	require.Equal(expected, readTestSnapshot(require, snapshot))
+	value, err := snapshot.Get([]byte("a"))
+	require.NoError(err)
+	require.Equal([]byte("1"), value)
+	_, err = snapshot.Get([]byte("d"))
+	require.ErrorIs(err, database.ErrNotFound)
+	require.ErrorIs(snapshot.Put([]byte("a"), nil), errReadOnlySnapshot)
+
+	// A closed snapshot doesn't preserve values anymore.
+	require.NoError(snapshot.Close())
+	require.Zero(db.snapshots.Len())
+	require.Equal(
+		map[string]string{"a": "5", "b": "6", "c": "4", "d": "7"},
+		readTestSnapshot(require, db),
+	)
+}
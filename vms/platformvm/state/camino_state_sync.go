@@ -0,0 +1,540 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/linkeddb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+)
+
+// Sync areas identify the part of the state a sync record belongs to.
+const (
+	syncAreaTx uint8 = iota
+	syncAreaUTXO
+	syncAreaCurrentValidator
+	syncAreaCurrentDelegator
+	syncAreaCurrentSubnetValidator
+	syncAreaCurrentSubnetDelegator
+	syncAreaPendingValidator
+	syncAreaPendingDelegator
+	syncAreaPendingSubnetValidator
+	syncAreaPendingSubnetDelegator
+	syncAreaSubnet
+	syncAreaChain
+	syncAreaTransformedSubnet
+	syncAreaSupply
+	syncAreaSingleton
+	syncAreaCamino
+	syncAreaDeferredValidator
+	syncAreaAddressState
+	syncAreaDepositOffer
+	syncAreaDeposit
+	syncAreaDepositIDByEndtime
+	syncAreaMultisigAlias
+	syncAreaShortLink
+	syncAreaClaimable
+	syncAreaProposal
+	syncAreaProposalIDByEndtime
+	syncAreaProposalIDToFinish
+)
+
+var (
+	errUnknownSyncArea   = errors.New("unknown state sync area")
+	errInvalidChainKey   = errors.New("invalid chain key")
+	errMissingSyncRecord = errors.New("missing state sync record")
+
+	// syncedSingletonKeys are the singletons that are synced. The last accepted
+	// block is set from the synced block and the initialized key is written
+	// when the genesis is applied.
+	syncedSingletonKeys = [][]byte{timestampKey, currentSupplyKey}
+)
+
+// syncRecord is a single entry of the state that is transferred during state
+// sync. Records don't depend on the layout of the database, so every node
+// produces the same records for the same state.
+type syncRecord struct {
+	Area  uint8  `serialize:"true"`
+	Key   []byte `serialize:"true"`
+	Value []byte `serialize:"true"`
+}
+
+// syncDB is a database of the state that is synced entry by entry.
+type syncDB struct {
+	area uint8
+	db   database.Database
+}
+
+// SyncSnapshot is a snapshot of the committed state, whose state sync records
+// can be exported without holding the chain lock.
+type SyncSnapshot interface {
+	// ExportSyncRecords passes the state sync records of the snapshot to [f],
+	// in a deterministic order. Every node produces the same records for the
+	// same state.
+	ExportSyncRecords(f func([]byte) error) error
+
+	// Release drops the values, that the snapshot preserved of the modified
+	// state. The snapshot can't be used afterwards.
+	Release() error
+}
+
+type syncSnapshot struct {
+	snapshot *dbSnapshot
+	view     *syncView
+}
+
+// NewSyncSnapshot assumes the state is committed, because the uncommitted
+// changes aren't part of the snapshot.
+func (s *state) NewSyncSnapshot() SyncSnapshot {
+	snapshot := s.snapshotDB.newSnapshot()
+	return &syncSnapshot{
+		snapshot: snapshot,
+		view:     newSyncView(snapshot),
+	}
+}
+
+func (s *syncSnapshot) ExportSyncRecords(f func([]byte) error) error {
+	return s.view.exportSyncRecords(func(record *syncRecord) error {
+		recordBytes, err := blocks.GenesisCodec.Marshal(blocks.Version, record)
+		if err != nil {
+			return err
+		}
+		return f(recordBytes)
+	})
+}
+
+func (s *syncSnapshot) Release() error {
+	return s.snapshot.Close()
+}
+
+func (v *syncView) exportSyncRecords(f func(*syncRecord) error) error {
+	for _, d := range v.dbs {
+		export := f
+		if d.area == syncAreaTx {
+			// Only the txs that are retained by pruning are synced, so pruned
+			// and archival nodes produce the same records.
+			export = func(record *syncRecord) error {
+				retained, err := isRetainedTxBytes(record.Value)
+				if err != nil || !retained {
+					return err
+				}
+				return f(record)
+			}
+		}
+		if err := exportSyncDB(d, export); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range syncedSingletonKeys {
+		value, err := v.singletonDB.Get(key)
+		if err != nil {
+			return err
+		}
+		if err := f(&syncRecord{Area: syncAreaSingleton, Key: key, Value: value}); err != nil {
+			return err
+		}
+	}
+
+	for _, l := range v.lists {
+		records, err := readSyncList(l)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if l.area == syncAreaCurrentValidator || l.area == syncAreaCurrentSubnetValidator {
+				record.Value, err = v.canonicalValidatorMetadata(record.Key, record.Value)
+				if err != nil {
+					return err
+				}
+			}
+			if err := f(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Chains are stored in one list per subnet, so they are keyed by the
+	// subnet ID and the chain ID.
+	subnets, err := readSyncList(syncDB{area: syncAreaSubnet, db: v.subnetDB})
+	if err != nil {
+		return err
+	}
+	subnetIDs := []ids.ID{constants.PrimaryNetworkID}
+	for _, subnet := range subnets {
+		subnetID, err := ids.ToID(subnet.Key)
+		if err != nil {
+			return err
+		}
+		subnetIDs = append(subnetIDs, subnetID)
+	}
+	slices.SortFunc(subnetIDs, func(a, b ids.ID) bool {
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	for _, subnetID := range subnetIDs {
+		chains, err := readSyncList(syncDB{
+			area: syncAreaChain,
+			db:   prefixdb.New(subnetID[:], v.chainDB),
+		})
+		if err != nil {
+			return err
+		}
+		for _, chain := range chains {
+			chain.Key = append(subnetID[:], chain.Key...)
+			if err := f(chain); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *state) ApplySyncRecords(records database.Iterator, blk blocks.Block) error {
+	defer s.Abort()
+
+	if err := s.clearSyncedState(); err != nil {
+		return err
+	}
+
+	view := newSyncView(s.baseDB)
+	lists := make(map[uint8]linkeddb.LinkedDB)
+	for _, l := range view.lists {
+		lists[l.area] = linkeddb.NewDefault(l.db)
+	}
+	dbs := make(map[uint8]database.Database)
+	for _, d := range view.dbs {
+		dbs[d.area] = d.db
+	}
+
+	for records.Next() {
+		record := &syncRecord{}
+		if _, err := blocks.GenesisCodec.Unmarshal(records.Value(), record); err != nil {
+			return err
+		}
+		if err := s.applySyncRecord(record, dbs, lists); err != nil {
+			return fmt.Errorf("failed to apply state sync record: %w", err)
+		}
+	}
+	if err := records.Error(); err != nil {
+		return err
+	}
+
+	// Validate that the mandatory singletons were synced before loading them.
+	for _, key := range syncedSingletonKeys {
+		has, err := s.singletonDB.Has(key)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return fmt.Errorf("%w: %s", errMissingSyncRecord, key)
+		}
+	}
+
+	blkID := blk.ID()
+	if err := database.PutID(s.singletonDB, lastAcceptedKey, blkID); err != nil {
+		return err
+	}
+	// The blocks below the synced block aren't available.
+	if err := database.PutUInt64(s.singletonDB, prunedHeightKey, blk.Height()); err != nil {
+		return err
+	}
+	// The archived values precede the synced state.
+	if s.archived {
+		if err := s.resetArchive(blk.Height()); err != nil {
+			return err
+		}
+	}
+	if err := s.reload(); err != nil {
+		return err
+	}
+
+	s.AddStatelessBlock(blk, choices.Accepted)
+	s.SetHeight(blk.Height())
+	return s.Commit()
+}
+
+func (s *state) applySyncRecord(record *syncRecord, dbs map[uint8]database.Database, lists map[uint8]linkeddb.LinkedDB) error {
+	switch record.Area {
+	case syncAreaUTXO:
+		utxo := &avax.UTXO{}
+		if _, err := txs.GenesisCodec.Unmarshal(record.Value, utxo); err != nil {
+			return err
+		}
+		return s.utxoState.PutUTXO(utxo)
+	case syncAreaSingleton:
+		return s.singletonDB.Put(record.Key, record.Value)
+	case syncAreaChain:
+		if len(record.Key) <= len(ids.Empty) {
+			return errInvalidChainKey
+		}
+		subnetID, err := ids.ToID(record.Key[:len(ids.Empty)])
+		if err != nil {
+			return err
+		}
+		chainDB := linkeddb.NewDefault(prefixdb.New(subnetID[:], s.chainDB))
+		return chainDB.Put(record.Key[len(ids.Empty):], record.Value)
+	}
+
+	if db, ok := dbs[record.Area]; ok {
+		return db.Put(record.Key, record.Value)
+	}
+	if list, ok := lists[record.Area]; ok {
+		return list.Put(record.Key, record.Value)
+	}
+	return fmt.Errorf("%w: %d", errUnknownSyncArea, record.Area)
+}
+
+// clearSyncedState removes the synced part of the state, except for the txs,
+// which are immutable.
+func (s *state) clearSyncedState() error {
+	utxoIt := avax.UTXODB(s.utxoDB).NewIterator()
+	defer utxoIt.Release()
+	for utxoIt.Next() {
+		utxoID, err := ids.ToID(utxoIt.Key())
+		if err != nil {
+			return err
+		}
+		if err := s.utxoState.DeleteUTXO(utxoID); err != nil {
+			return err
+		}
+	}
+	if err := utxoIt.Error(); err != nil {
+		return err
+	}
+
+	view := newSyncView(s.baseDB)
+	errs := wrappers.Errs{}
+	for _, d := range view.dbs {
+		if d.area != syncAreaTx && d.area != syncAreaUTXO {
+			errs.Add(database.Clear(d.db, d.db))
+		}
+	}
+	for _, l := range view.lists {
+		errs.Add(database.Clear(l.db, l.db))
+	}
+	errs.Add(database.Clear(s.chainDB, s.chainDB))
+	for _, key := range syncedSingletonKeys {
+		errs.Add(s.singletonDB.Delete(key))
+	}
+	return errs.Err
+}
+
+// reload drops everything that is cached in memory and loads the state from
+// the database again.
+func (s *state) reload() error {
+	s.blockCache.Flush()
+	s.validatorWeightDiffsCache.Flush()
+	s.validatorPublicKeyDiffsCache.Flush()
+	s.txCache.Flush()
+	s.rewardUTXOsCache.Flush()
+	s.transformedSubnetCache.Flush()
+	s.supplyCache.Flush()
+	s.chainCache.Flush()
+	s.chainDBCache.Flush()
+	s.cachedSubnets = nil
+	s.validatorState = newValidatorState()
+
+	s.currentValidatorList = linkeddb.NewDefault(s.currentValidatorBaseDB)
+	s.currentDelegatorList = linkeddb.NewDefault(s.currentDelegatorBaseDB)
+	s.currentSubnetValidatorList = linkeddb.NewDefault(s.currentSubnetValidatorBaseDB)
+	s.currentSubnetDelegatorList = linkeddb.NewDefault(s.currentSubnetDelegatorBaseDB)
+	s.pendingValidatorList = linkeddb.NewDefault(s.pendingValidatorBaseDB)
+	s.pendingDelegatorList = linkeddb.NewDefault(s.pendingDelegatorBaseDB)
+	s.pendingSubnetValidatorList = linkeddb.NewDefault(s.pendingSubnetValidatorBaseDB)
+	s.pendingSubnetDelegatorList = linkeddb.NewDefault(s.pendingSubnetDelegatorBaseDB)
+	s.subnetDB = linkeddb.NewDefault(s.subnetBaseDB)
+	s.caminoState.reset()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		s.loadMetadata(),
+		s.loadCurrentValidators(),
+		s.loadPendingValidators(),
+		s.resetValidatorSets(),
+		s.resetPendingValidatorSet(),
+		s.caminoState.Load(s),
+	)
+	return errs.Err
+}
+
+// resetValidatorSets replaces the validators of the primary network and the
+// tracked subnets with the current validators of the state.
+func (s *state) resetValidatorSets() error {
+	subnetIDs := []ids.ID{constants.PrimaryNetworkID}
+	for subnetID := range s.cfg.TrackedSubnets {
+		subnetIDs = append(subnetIDs, subnetID)
+	}
+
+	for _, subnetID := range subnetIDs {
+		vdrs, ok := s.cfg.Validators.Get(subnetID)
+		if !ok {
+			return fmt.Errorf("%w: %s", errMissingValidatorSet, subnetID)
+		}
+		for _, vdr := range vdrs.List() {
+			if err := vdrs.RemoveWeight(vdr.NodeID, vdr.Weight); err != nil {
+				return err
+			}
+		}
+		if err := s.ValidatorSet(subnetID, vdrs); err != nil {
+			return err
+		}
+	}
+
+	primaryValidators, _ := s.cfg.Validators.Get(constants.PrimaryNetworkID)
+	s.metrics.SetLocalStake(primaryValidators.GetWeight(s.ctx.NodeID))
+	s.metrics.SetTotalStake(primaryValidators.Weight())
+	return nil
+}
+
+// syncView contains the databases of the synced part of the state, which are
+// opened on the database [db] of the state with the layout of the state. This
+// allows to read the synced part of a snapshot of the state.
+type syncView struct {
+	txDB        database.Database
+	singletonDB database.Database
+	subnetDB    database.Database
+	chainDB     database.Database
+	// Databases that are synced entry by entry
+	dbs []syncDB
+	// Databases of the linked lists that are synced entry by entry
+	lists []syncDB
+}
+
+func newSyncView(db database.Database) *syncView {
+	validatorsDB := prefixdb.New(validatorsPrefix, db)
+	currentValidatorsDB := prefixdb.New(currentPrefix, validatorsDB)
+	pendingValidatorsDB := prefixdb.New(pendingPrefix, validatorsDB)
+	txDB := prefixdb.New(txPrefix, db)
+	subnetDB := prefixdb.New(subnetPrefix, db)
+	return &syncView{
+		txDB:        txDB,
+		singletonDB: prefixdb.New(singletonPrefix, db),
+		subnetDB:    subnetDB,
+		chainDB:     prefixdb.New(chainPrefix, db),
+		dbs: []syncDB{
+			{area: syncAreaTx, db: txDB},
+			{area: syncAreaUTXO, db: avax.UTXODB(prefixdb.New(utxoPrefix, db))},
+			{area: syncAreaTransformedSubnet, db: prefixdb.New(transformedSubnetPrefix, db)},
+			{area: syncAreaSupply, db: prefixdb.New(supplyPrefix, db)},
+			{area: syncAreaCamino, db: prefixdb.New(caminoPrefix, db)},
+			{area: syncAreaAddressState, db: prefixdb.New(addressStatePrefix, db)},
+			{area: syncAreaDepositOffer, db: prefixdb.New(depositOffersPrefix, db)},
+			{area: syncAreaDeposit, db: prefixdb.New(depositsPrefix, db)},
+			{area: syncAreaDepositIDByEndtime, db: prefixdb.New(depositIDsByEndtimePrefix, db)},
+			{area: syncAreaMultisigAlias, db: prefixdb.New(multisigOwnersPrefix, db)},
+			{area: syncAreaShortLink, db: prefixdb.New(shortLinksPrefix, db)},
+			{area: syncAreaClaimable, db: prefixdb.New(claimablesPrefix, db)},
+			{area: syncAreaProposal, db: prefixdb.New(proposalsPrefix, db)},
+			{area: syncAreaProposalIDByEndtime, db: prefixdb.New(proposalIDsByEndtimePrefix, db)},
+			{area: syncAreaProposalIDToFinish, db: prefixdb.New(proposalIDsToFinishPrefix, db)},
+		},
+		lists: []syncDB{
+			{area: syncAreaCurrentValidator, db: prefixdb.New(validatorPrefix, currentValidatorsDB)},
+			{area: syncAreaCurrentDelegator, db: prefixdb.New(delegatorPrefix, currentValidatorsDB)},
+			{area: syncAreaCurrentSubnetValidator, db: prefixdb.New(subnetValidatorPrefix, currentValidatorsDB)},
+			{area: syncAreaCurrentSubnetDelegator, db: prefixdb.New(subnetDelegatorPrefix, currentValidatorsDB)},
+			{area: syncAreaPendingValidator, db: prefixdb.New(validatorPrefix, pendingValidatorsDB)},
+			{area: syncAreaPendingDelegator, db: prefixdb.New(delegatorPrefix, pendingValidatorsDB)},
+			{area: syncAreaPendingSubnetValidator, db: prefixdb.New(subnetValidatorPrefix, pendingValidatorsDB)},
+			{area: syncAreaPendingSubnetDelegator, db: prefixdb.New(subnetDelegatorPrefix, pendingValidatorsDB)},
+			{area: syncAreaSubnet, db: subnetDB},
+			{area: syncAreaDeferredValidator, db: prefixdb.New(deferredPrefix, validatorsDB)},
+		},
+	}
+}
+
+// canonicalValidatorMetadata returns the metadata of the current validator
+// [txIDBytes] without the uptime, which is measured by every node itself. The
+// uptime is reset to the uptime of a validator that was just added.
+func (v *syncView) canonicalValidatorMetadata(txIDBytes, metadataBytes []byte) ([]byte, error) {
+	txBytes, err := v.txDB.Get(txIDBytes)
+	if err != nil {
+		return nil, err
+	}
+	stx := txBytesAndStatus{}
+	if _, err := txs.GenesisCodec.Unmarshal(txBytes, &stx); err != nil {
+		return nil, err
+	}
+	tx, err := txs.Parse(txs.GenesisCodec, stx.Tx)
+	if err != nil {
+		return nil, err
+	}
+	stakerTx, ok := tx.Unsigned.(txs.Staker)
+	if !ok {
+		return nil, fmt.Errorf("expected tx type txs.Staker but got %T", tx.Unsigned)
+	}
+
+	metadata := &validatorMetadata{}
+	if err := parseValidatorMetadata(metadataBytes, metadata); err != nil {
+		return nil, err
+	}
+	metadata.UpDuration = 0
+	metadata.LastUpdated = uint64(stakerTx.StartTime().Unix())
+	return blocks.GenesisCodec.Marshal(blocks.Version, metadata)
+}
+
+// reset drops everything that is cached in memory, so the camino state can be
+// loaded from the database again.
+func (cs *caminoState) reset() {
+	cs.caminoDiff = newCaminoDiff()
+	cs.deferredValidatorList = linkeddb.NewDefault(cs.deferredValidatorsDB)
+	cs.addressStateCache.Flush()
+	cs.depositOffers = make(map[ids.ID]*deposit.Offer)
+	cs.depositsCache.Flush()
+	cs.multisigAliasesCache.Flush()
+	cs.shortLinksCache.Flush()
+	cs.claimablesCache.Flush()
+	cs.proposalIDsToFinish = nil
+	cs.proposalsCache.Flush()
+}
+
+func exportSyncDB(d syncDB, f func(*syncRecord) error) error {
+	it := d.db.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		err := f(&syncRecord{
+			Area:  d.area,
+			Key:   slices.Clone(it.Key()),
+			Value: slices.Clone(it.Value()),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
+// readSyncList returns the entries of the linked list in [d] sorted by key,
+// because the order of a linked list depends on the order of its insertions.
+func readSyncList(d syncDB) ([]*syncRecord, error) {
+	it := linkeddb.NewDefault(d.db).NewIterator()
+	defer it.Release()
+
+	var records []*syncRecord
+	for it.Next() {
+		records = append(records, &syncRecord{
+			Area:  d.area,
+			Key:   slices.Clone(it.Key()),
+			Value: slices.Clone(it.Value()),
+		})
+	}
+	slices.SortFunc(records, func(a, b *syncRecord) bool {
+		return bytes.Compare(a.Key, b.Key) < 0
+	})
+	return records, it.Error()
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/genesis"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/utils/constants"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	as "github.com/ava-labs/avalanchego/vms/platformvm/addrstate"
+	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
+	"github.com/ava-labs/avalanchego/vms/platformvm/status"
+	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+)
+
+func exportTestSyncRecords(require *require.Assertions, s State) [][]byte {
+	snapshot := s.NewSyncSnapshot()
+	var records [][]byte
+	require.NoError(snapshot.ExportSyncRecords(func(record []byte) error {
+		records = append(records, slices.Clone(record))
+		return nil
+	}))
+	require.NoError(snapshot.Release())
+	return records
+}
+
+func TestStateSyncRecords(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes, _, err := genesis.FromConfig(testGenesisConfig(true, true, true))
+	require.NoError(err)
+
+	src := newEmptyStateWithDB(t, memdb.New())
+	require.NoError(src.sync(genesisBytes))
+
+	// Modify the state, so it differs from the genesis state.
+	createSubnetTx := &txs.Tx{Unsigned: &txs.CreateSubnetTx{
+		Owner: &secp256k1fx.OutputOwners{},
+	}}
+	require.NoError(createSubnetTx.Initialize(txs.Codec))
+	createChainTx := &txs.Tx{Unsigned: &txs.CreateChainTx{
+		SubnetID:   createSubnetTx.ID(),
+		ChainName:  "chain",
+		VMID:       constants.AVMID,
+		SubnetAuth: &secp256k1fx.Input{},
+	}}
+	require.NoError(createChainTx.Initialize(txs.Codec))
+	src.AddSubnet(createSubnetTx)
+	src.AddTx(createSubnetTx, status.Committed)
+	src.AddChain(createChainTx)
+	src.AddTx(createChainTx, status.Committed)
+
+	address := ids.GenerateTestShortID()
+	src.SetAddressStates(address, as.AddressStateKYCVerified)
+	offer := &deposit.Offer{
+		ID:    ids.GenerateTestID(),
+		End:   100,
+		Memo:  []byte("memo"),
+		Flags: deposit.OfferFlagLocked,
+	}
+	src.SetDepositOffer(offer)
+	src.SetTimestamp(src.GetTimestamp().Add(time.Second))
+	src.SetHeight(1)
+	require.NoError(src.Commit())
+
+	// The uptime is measured by every node itself and must not change the
+	// records.
+	stakerIt, err := src.GetCurrentStakerIterator()
+	require.NoError(err)
+	require.True(stakerIt.Next())
+	staker := stakerIt.Value()
+	stakerIt.Release()
+
+	records := exportTestSyncRecords(require, src)
+	require.NoError(src.SetUptime(staker.NodeID, staker.SubnetID, time.Hour, staker.StartTime.Add(time.Hour)))
+	require.NoError(src.Commit())
+	require.Equal(records, exportTestSyncRecords(require, src))
+
+	// A snapshot isn't affected by modifications of the state, that are
+	// committed after the snapshot was taken.
+	snapshot := src.NewSyncSnapshot()
+	timestamp := src.GetTimestamp()
+	src.SetAddressStates(address, as.AddressStateEmpty)
+	src.SetTimestamp(timestamp.Add(time.Second))
+	src.AddTx(createChainTx, status.Aborted)
+	require.NoError(src.Commit())
+	var snapshotRecords [][]byte
+	require.NoError(snapshot.ExportSyncRecords(func(record []byte) error {
+		snapshotRecords = append(snapshotRecords, slices.Clone(record))
+		return nil
+	}))
+	require.NoError(snapshot.Release())
+	require.Equal(records, snapshotRecords)
+	require.NotEqual(records, exportTestSyncRecords(require, src))
+	src.SetAddressStates(address, as.AddressStateKYCVerified)
+	src.SetTimestamp(timestamp)
+	src.AddTx(createChainTx, status.Committed)
+	require.NoError(src.Commit())
+	require.Equal(records, exportTestSyncRecords(require, src))
+
+	recordsDB := memdb.New()
+	for i, record := range records {
+		require.NoError(recordsDB.Put(database.PackUInt64(uint64(i)), record))
+	}
+
+	dstDB := memdb.New()
+	dst := newEmptyStateWithDB(t, dstDB)
+	require.NoError(dst.sync(genesisBytes))
+
+	blk, err := blocks.NewBanffStandardBlock(src.GetTimestamp(), ids.GenerateTestID(), 10, nil)
+	require.NoError(err)
+
+	recordsIt := recordsDB.NewIterator()
+	defer recordsIt.Release()
+	require.NoError(dst.ApplySyncRecords(recordsIt, blk))
+
+	require.Equal(blk.ID(), dst.GetLastAccepted())
+	_, blkStatus, err := dst.GetStatelessBlock(blk.ID())
+	require.NoError(err)
+	require.Equal(choices.Accepted, blkStatus)
+	blkID, err := dst.GetBlockIDAtHeight(blk.Height())
+	require.NoError(err)
+	require.Equal(blk.ID(), blkID)
+	require.Equal(src.GetTimestamp(), dst.GetTimestamp())
+
+	subnets, err := dst.GetSubnets()
+	require.NoError(err)
+	require.Len(subnets, 1)
+	require.Equal(createSubnetTx.ID(), subnets[0].ID())
+	chains, err := dst.GetChains(createSubnetTx.ID())
+	require.NoError(err)
+	require.Len(chains, 1)
+	require.Equal(createChainTx.ID(), chains[0].ID())
+
+	addressStates, err := dst.GetAddressStates(address)
+	require.NoError(err)
+	require.Equal(as.AddressStateKYCVerified, addressStates)
+	dstOffer, err := dst.GetDepositOffer(offer.ID)
+	require.NoError(err)
+	require.Equal(offer, dstOffer)
+
+	dstStaker, err := dst.GetCurrentValidator(staker.SubnetID, staker.NodeID)
+	require.NoError(err)
+	require.Equal(staker, dstStaker)
+	primaryValidators, ok := dst.cfg.Validators.Get(constants.PrimaryNetworkID)
+	require.True(ok)
+	require.Equal(staker.Weight, primaryValidators.GetWeight(staker.NodeID))
+
+	// The synced state must produce the same records.
+	require.Equal(records, exportTestSyncRecords(require, dst))
+
+	// The synced state must be persisted.
+	reloaded := newEmptyStateWithDB(t, dstDB)
+	require.NoError(reloaded.load())
+	require.Equal(blk.ID(), reloaded.GetLastAccepted())
+	require.Equal(records, exportTestSyncRecords(require, reloaded))
+}
+
+func TestIndexBlocks(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes, _, err := genesis.FromConfig(testGenesisConfig(true, true, true))
+	require.NoError(err)
+
+	db := memdb.New()
+	s := newEmptyStateWithDB(t, db)
+	require.NoError(s.sync(genesisBytes))
+
+	genesisID := s.GetLastAccepted()
+	genesisBlk, _, err := s.GetStatelessBlock(genesisID)
+	require.NoError(err)
+	blk, err := blocks.NewBanffStandardBlock(s.GetTimestamp(), genesisID, genesisBlk.Height()+1, nil)
+	require.NoError(err)
+	s.AddStatelessBlock(blk, choices.Accepted)
+	s.SetLastAccepted(blk.ID())
+	s.SetHeight(blk.Height())
+	require.NoError(s.Commit())
+
+	// Remove the index, as if the database was created before blocks were
+	// indexed.
+	require.NoError(database.Clear(s.blockIDDB, s.blockIDDB))
+	require.NoError(s.singletonDB.Delete(blocksIndexedKey))
+	require.NoError(s.Commit())
+	_, err = s.GetBlockIDAtHeight(blk.Height())
+	require.ErrorIs(err, database.ErrNotFound)
+
+	s = newEmptyStateWithDB(t, db)
+	s.ctx.Log = logging.NoLog{}
+	require.NoError(s.sync(genesisBytes))
+
+	blkID, err := s.GetBlockIDAtHeight(genesisBlk.Height())
+	require.NoError(err)
+	require.Equal(genesisID, blkID)
+	blkID, err = s.GetBlockIDAtHeight(blk.Height())
+	require.NoError(err)
+	require.Equal(blk.ID(), blkID)
+	_, err = s.GetBlockIDAtHeight(0)
+	require.NoError(err)
+}
@@ -81,29 +81,29 @@ func (mr *MockProposalsIteratorMockRecorder) Error() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Error", reflect.TypeOf((*MockProposalsIterator)(nil).Error))
 }
 
-// Release mocks base method.
-func (m *MockProposalsIterator) Release() {
+// ProposalID mocks base method.
+func (m *MockProposalsIterator) ProposalID() (ids.ID, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "Release")
+	ret := m.ctrl.Call(m, "ProposalID")
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Release indicates an expected call of Release.
-func (mr *MockProposalsIteratorMockRecorder) Release() *gomock.Call {
+// ProposalID indicates an expected call of key.
+func (mr *MockProposalsIteratorMockRecorder) ProposalID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockProposalsIterator)(nil).Release))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProposalID", reflect.TypeOf((*MockProposalsIterator)(nil).ProposalID))
 }
 
-// key mocks base method.
-func (m *MockProposalsIterator) key() (ids.ID, error) {
+// Release mocks base method.
+func (m *MockProposalsIterator) Release() {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "key")
-	ret0, _ := ret[0].(ids.ID)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	m.ctrl.Call(m, "Release")
 }
 
-// key indicates an expected call of key.
-func (mr *MockProposalsIteratorMockRecorder) key() *gomock.Call {
+// Release indicates an expected call of Release.
+func (mr *MockProposalsIteratorMockRecorder) Release() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "key", reflect.TypeOf((*MockProposalsIterator)(nil).key))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockProposalsIterator)(nil).Release))
 }
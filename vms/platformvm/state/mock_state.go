@@ -161,6 +161,20 @@ func (mr *MockStateMockRecorder) AddUTXO(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUTXO", reflect.TypeOf((*MockState)(nil).AddUTXO), arg0)
 }
 
+// ApplySyncRecords mocks base method.
+func (m *MockState) ApplySyncRecords(arg0 database.Iterator, arg1 blocks.Block) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplySyncRecords", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplySyncRecords indicates an expected call of ApplySyncRecords.
+func (mr *MockStateMockRecorder) ApplySyncRecords(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplySyncRecords", reflect.TypeOf((*MockState)(nil).ApplySyncRecords), arg0, arg1)
+}
+
 // CaminoConfig mocks base method.
 func (m *MockState) CaminoConfig() (*CaminoConfig, error) {
 	m.ctrl.T.Helper()
@@ -294,6 +308,20 @@ func (mr *MockStateMockRecorder) DeleteUTXO(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUTXO", reflect.TypeOf((*MockState)(nil).DeleteUTXO), arg0)
 }
 
+// DropArchive mocks base method.
+func (m *MockState) DropArchive() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropArchive")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropArchive indicates an expected call of DropArchive.
+func (mr *MockStateMockRecorder) DropArchive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropArchive", reflect.TypeOf((*MockState)(nil).DropArchive))
+}
+
 // GetAddressStates mocks base method.
 func (m *MockState) GetAddressStates(arg0 ids.ShortID) (addrstate.AddressState, error) {
 	m.ctrl.T.Helper()
@@ -399,6 +427,81 @@ func (mr *MockStateMockRecorder) GetFeeDistribution() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeeDistribution", reflect.TypeOf((*MockState)(nil).GetFeeDistribution))
 }
 
+// GetArchivedClaimable mocks base method.
+func (m *MockState) GetArchivedClaimable(arg0 ids.ID, arg1 uint64) (*Claimable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedClaimable", arg0, arg1)
+	ret0, _ := ret[0].(*Claimable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedClaimable indicates an expected call of GetArchivedClaimable.
+func (mr *MockStateMockRecorder) GetArchivedClaimable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedClaimable", reflect.TypeOf((*MockState)(nil).GetArchivedClaimable), arg0, arg1)
+}
+
+// GetArchivedDeposit mocks base method.
+func (m *MockState) GetArchivedDeposit(arg0 ids.ID, arg1 uint64) (*deposit.Deposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedDeposit", arg0, arg1)
+	ret0, _ := ret[0].(*deposit.Deposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedDeposit indicates an expected call of GetArchivedDeposit.
+func (mr *MockStateMockRecorder) GetArchivedDeposit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedDeposit", reflect.TypeOf((*MockState)(nil).GetArchivedDeposit), arg0, arg1)
+}
+
+// GetArchivedTimestamp mocks base method.
+func (m *MockState) GetArchivedTimestamp(arg0 uint64) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedTimestamp", arg0)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedTimestamp indicates an expected call of GetArchivedTimestamp.
+func (mr *MockStateMockRecorder) GetArchivedTimestamp(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedTimestamp", reflect.TypeOf((*MockState)(nil).GetArchivedTimestamp), arg0)
+}
+
+// GetArchivedUTXOs mocks base method.
+func (m *MockState) GetArchivedUTXOs(arg0 set.Set[ids.ShortID], arg1 uint64) ([]*avax.UTXO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetArchivedUTXOs", arg0, arg1)
+	ret0, _ := ret[0].([]*avax.UTXO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetArchivedUTXOs indicates an expected call of GetArchivedUTXOs.
+func (mr *MockStateMockRecorder) GetArchivedUTXOs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetArchivedUTXOs", reflect.TypeOf((*MockState)(nil).GetArchivedUTXOs), arg0, arg1)
+}
+
+// GetBlockIDAtHeight mocks base method.
+func (m *MockState) GetBlockIDAtHeight(arg0 uint64) (ids.ID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockIDAtHeight", arg0)
+	ret0, _ := ret[0].(ids.ID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockIDAtHeight indicates an expected call of GetBlockIDAtHeight.
+func (mr *MockStateMockRecorder) GetBlockIDAtHeight(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockIDAtHeight", reflect.TypeOf((*MockState)(nil).GetBlockIDAtHeight), arg0)
+}
+
 // GetCurrentDelegatorIterator mocks base method.
 func (m *MockState) GetCurrentDelegatorIterator(arg0 ids.ID, arg1 ids.NodeID) (StakerIterator, error) {
 	m.ctrl.T.Helper()
@@ -739,6 +842,20 @@ func (mr *MockStateMockRecorder) PutDeferredValidator(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutDeferredValidator", reflect.TypeOf((*MockState)(nil).PutDeferredValidator), arg0)
 }
 
+// GetPrunedHeight mocks base method.
+func (m *MockState) GetPrunedHeight() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrunedHeight")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// GetPrunedHeight indicates an expected call of GetPrunedHeight.
+func (mr *MockStateMockRecorder) GetPrunedHeight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrunedHeight", reflect.TypeOf((*MockState)(nil).GetPrunedHeight))
+}
+
 // GetRewardUTXOs mocks base method.
 func (m *MockState) GetRewardUTXOs(arg0 ids.ID) ([]*avax.UTXO, error) {
 	m.ctrl.T.Helper()
@@ -938,6 +1055,20 @@ func (mr *MockStateMockRecorder) LockedUTXOs(arg0, arg1, arg2 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockedUTXOs", reflect.TypeOf((*MockState)(nil).LockedUTXOs), arg0, arg1, arg2)
 }
 
+// NewSyncSnapshot mocks base method.
+func (m *MockState) NewSyncSnapshot() SyncSnapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NewSyncSnapshot")
+	ret0, _ := ret[0].(SyncSnapshot)
+	return ret0
+}
+
+// NewSyncSnapshot indicates an expected call of NewSyncSnapshot.
+func (mr *MockStateMockRecorder) NewSyncSnapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewSyncSnapshot", reflect.TypeOf((*MockState)(nil).NewSyncSnapshot))
+}
+
 // PutCurrentDelegator mocks base method.
 func (m *MockState) PutCurrentDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -962,6 +1093,21 @@ func (mr *MockStateMockRecorder) PutCurrentValidator(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutCurrentValidator", reflect.TypeOf((*MockState)(nil).PutCurrentValidator), arg0)
 }
 
+// Prune mocks base method.
+func (m *MockState) Prune(arg0 uint64, arg1 int) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", arg0, arg1)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockStateMockRecorder) Prune(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockState)(nil).Prune), arg0, arg1)
+}
+
 // PutPendingDelegator mocks base method.
 func (m *MockState) PutPendingDelegator(arg0 *Staker) {
 	m.ctrl.T.Helper()
@@ -998,6 +1144,20 @@ func (mr *MockStateMockRecorder) SetAddressStates(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAddressStates", reflect.TypeOf((*MockState)(nil).SetAddressStates), arg0, arg1)
 }
 
+// SetArchiveEnabled mocks base method.
+func (m *MockState) SetArchiveEnabled(arg0 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArchiveEnabled", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArchiveEnabled indicates an expected call of SetArchiveEnabled.
+func (mr *MockStateMockRecorder) SetArchiveEnabled(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArchiveEnabled", reflect.TypeOf((*MockState)(nil).SetArchiveEnabled), arg0)
+}
+
 // SetClaimable mocks base method.
 func (m *MockState) SetClaimable(arg0 ids.ID, arg1 *Claimable) {
 	m.ctrl.T.Helper()
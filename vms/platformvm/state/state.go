@@ -38,10 +38,12 @@ import (
 	"github.com/ava-labs/avalanchego/utils/crypto/bls"
 	"github.com/ava-labs/avalanchego/utils/hashing"
 	"github.com/ava-labs/avalanchego/utils/math"
+	"github.com/ava-labs/avalanchego/utils/set"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
 	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
 	"github.com/ava-labs/avalanchego/vms/platformvm/config"
+	"github.com/ava-labs/avalanchego/vms/platformvm/deposit"
 	"github.com/ava-labs/avalanchego/vms/platformvm/genesis"
 	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
@@ -56,6 +58,10 @@ const (
 	rewardUTXOsCacheSize    = 2048
 	chainCacheSize          = 2048
 	chainDBCacheSize        = 2048
+
+	// blockIndexCommitInterval is the number of blocks that are indexed by
+	// indexBlocks before the index is committed.
+	blockIndexCommitInterval = 4096
 )
 
 var (
@@ -67,6 +73,7 @@ var (
 	errDuplicateValidatorSet        = errors.New("duplicate validator set")
 
 	blockPrefix                   = []byte("block")
+	blockIDPrefix                 = []byte("blockID")
 	validatorsPrefix              = []byte("validators")
 	currentPrefix                 = []byte("current")
 	pendingPrefix                 = []byte("pending")
@@ -89,6 +96,7 @@ var (
 	currentSupplyKey = []byte("current supply")
 	lastAcceptedKey  = []byte("last accepted")
 	initializedKey   = []byte("initialized")
+	blocksIndexedKey = []byte("blocks indexed")
 )
 
 // Chain collects all methods to manage the state of the chain for block
@@ -133,6 +141,9 @@ type State interface {
 	GetStatelessBlock(blockID ids.ID) (blocks.Block, choices.Status, error)
 	AddStatelessBlock(block blocks.Block, status choices.Status)
 
+	// GetBlockIDAtHeight returns the ID of the accepted block at [height].
+	GetBlockIDAtHeight(height uint64) (ids.ID, error)
+
 	// ValidatorSet adds all the validators and delegators of [subnetID] into
 	// [vdrs].
 	ValidatorSet(subnetID ids.ID, vdrs validators.Set) error
@@ -145,6 +156,54 @@ type State interface {
 
 	SetHeight(height uint64)
 
+	// NewSyncSnapshot returns a snapshot of the committed state, whose state
+	// sync records can be exported while the state is modified.
+	NewSyncSnapshot() SyncSnapshot
+
+	// ApplySyncRecords replaces the synced part of the state with the state
+	// sync records in the values of [records] and commits [blk] as the last
+	// accepted block. An error leaves the in-memory state inconsistent.
+	ApplySyncRecords(records database.Iterator, blk blocks.Block) error
+
+	// GetPrunedHeight returns the height of the lowest accepted block that
+	// wasn't pruned.
+	GetPrunedHeight() uint64
+
+	// Prune deletes up to [maxBlocks] accepted blocks below [height] and the
+	// txs of these blocks that aren't needed to execute later blocks. Returns
+	// the new pruned height.
+	Prune(height uint64, maxBlocks int) (uint64, error)
+
+	// SetArchiveEnabled starts to archive the values, that are modified by
+	// accepted blocks, at the last accepted height or stops archiving them.
+	// The values archived before archiving was stopped are kept. Archiving
+	// can only be resumed at the height it was stopped at, otherwise the
+	// archive must be dropped first.
+	//
+	// Invariant: There must not be any uncommitted changes.
+	SetArchiveEnabled(enabled bool) error
+
+	// DropArchive deletes all archived values and stops archiving.
+	//
+	// Invariant: There must not be any uncommitted changes.
+	DropArchive() error
+
+	// GetArchivedUTXOs returns the UTXOs of [addrs] after the block at
+	// [height] was accepted.
+	GetArchivedUTXOs(addrs set.Set[ids.ShortID], height uint64) ([]*avax.UTXO, error)
+
+	// GetArchivedDeposit returns the deposit [depositTxID] after the block at
+	// [height] was accepted.
+	GetArchivedDeposit(depositTxID ids.ID, height uint64) (*deposit.Deposit, error)
+
+	// GetArchivedClaimable returns the claimable of [ownerID] after the block
+	// at [height] was accepted.
+	GetArchivedClaimable(ownerID ids.ID, height uint64) (*Claimable, error)
+
+	// GetArchivedTimestamp returns the chain time after the block at [height]
+	// was accepted.
+	GetArchivedTimestamp(height uint64) (time.Time, error)
+
 	// Discard uncommitted changes to the database.
 	Abort()
 
@@ -203,6 +262,8 @@ type stateBlk struct {
  * |       '-- nodeID -> public key
  * |-. blocks
  * | '-- blockID -> block bytes
+ * |-. blockIDs
+ * | '-- height -> blockID
  * |-. txs
  * | '-- txID -> tx bytes + tx status
  * |- rewardUTXOs
@@ -218,8 +279,21 @@ type stateBlk struct {
  * | '-. subnetID
  * |   '-. list
  * |     '-- txID -> nil
+ * |-. archive
+ * | |-. utxo
+ * | | '-- address + height + utxoID -> utxo bytes before height
+ * | |-. deposit
+ * | | '-- depositTxID + height -> deposit bytes before height
+ * | |-. claimable
+ * | | '-- ownerID + height -> claimable bytes before height
+ * | '-. singleton
+ * |   '-- timestampKey + height -> timestamp before height
  * '-. singletons
  *   |-- initializedKey -> nil
+ *   |-- blocksIndexedKey -> nil
+ *   |-- prunedHeightKey -> prunedHeight
+ *   |-- archiveHeightKey -> archiveHeight
+ *   |-- archiveStoppedHeightKey -> archiveStoppedHeight
  *   |-- timestampKey -> timestamp
  *   |-- currentSupplyKey -> currentSupply
  *   '-- lastAcceptedKey -> lastAccepted
@@ -233,7 +307,10 @@ type state struct {
 	rewards      reward.Calculator
 	bootstrapped *utils.Atomic[bool]
 
-	baseDB *versiondb.Database
+	// Takes the snapshots of the committed state, that are exported to
+	// syncing nodes
+	snapshotDB *snapshotDB
+	baseDB     *versiondb.Database
 
 	currentStakers *baseStakers
 	pendingStakers *baseStakers
@@ -247,6 +324,7 @@ type state struct {
 	// If the block isn't known, nil is cached.
 	blockCache cache.Cacher[ids.ID, *stateBlk]
 	blockDB    database.Database
+	blockIDDB  database.Database
 
 	validatorsDB                 database.Database
 	currentValidatorsDB          database.Database
@@ -304,11 +382,25 @@ type state struct {
 	chainDBCache cache.Cacher[ids.ID, linkeddb.LinkedDB] // cache of subnetID -> linkedDB
 	chainDB      database.Database
 
+	// [archived] is true if values are archived since [archiveHeight]
+	archived      bool
+	archiveHeight uint64
+	// [archiveStopped] is true if values were archived from [archiveHeight]
+	// to [archiveStoppedHeight]
+	archiveStopped       bool
+	archiveStoppedHeight uint64
+	archiveDB            database.Database
+	archiveUTXODB        database.Database
+	archiveDepositDB     database.Database
+	archiveClaimableDB   database.Database
+	archiveSingletonDB   database.Database
+
 	// The persisted fields represent the current database value
 	timestamp, persistedTimestamp         time.Time
 	currentSupply, persistedCurrentSupply uint64
 	// [lastAccepted] is the most recently accepted block.
 	lastAccepted, persistedLastAccepted ids.ID
+	prunedHeight                        uint64
 	singletonDB                         database.Database
 }
 
@@ -399,7 +491,8 @@ func new(
 		return nil, err
 	}
 
-	baseDB := versiondb.New(db)
+	snapshotDB := newSnapshotDB(db)
+	baseDB := versiondb.New(snapshotDB)
 
 	validatorsDB := prefixdb.New(validatorsPrefix, baseDB)
 
@@ -503,6 +596,8 @@ func new(
 		return nil, err
 	}
 
+	archiveDB := prefixdb.New(archivePrefix, baseDB)
+
 	return &state{
 		validatorState: newValidatorState(),
 
@@ -511,11 +606,13 @@ func new(
 		metrics:      metrics,
 		rewards:      rewards,
 		bootstrapped: bootstrapped,
+		snapshotDB:   snapshotDB,
 		baseDB:       baseDB,
 
 		addedBlocks: make(map[ids.ID]stateBlk),
 		blockCache:  blockCache,
 		blockDB:     prefixdb.New(blockPrefix, baseDB),
+		blockIDDB:   prefixdb.New(blockIDPrefix, baseDB),
 
 		currentStakers: newBaseStakers(),
 		pendingStakers: newBaseStakers(),
@@ -574,6 +671,12 @@ func new(
 		chainCache:   chainCache,
 		chainDBCache: chainDBCache,
 
+		archiveDB:          archiveDB,
+		archiveUTXODB:      prefixdb.NewNested(archiveUTXOPrefix, archiveDB),
+		archiveDepositDB:   prefixdb.NewNested(archiveDepositPrefix, archiveDB),
+		archiveClaimableDB: prefixdb.NewNested(archiveClaimablePrefix, archiveDB),
+		archiveSingletonDB: prefixdb.NewNested(archiveSingletonPrefix, archiveDB),
+
 		singletonDB: prefixdb.New(singletonPrefix, baseDB),
 	}, nil
 }
@@ -640,7 +743,12 @@ func (s *state) shouldInit() (bool, error) {
 }
 
 func (s *state) doneInit() error {
-	return s.singletonDB.Put(initializedKey, nil)
+	errs := wrappers.Errs{}
+	errs.Add(
+		s.singletonDB.Put(initializedKey, nil),
+		s.singletonDB.Put(blocksIndexedKey, nil),
+	)
+	return errs.Err
 }
 
 func (s *state) GetSubnets() ([]*txs.Tx, error) {
@@ -1090,6 +1198,7 @@ func (s *state) load() error {
 		s.loadCurrentValidators(),
 		s.loadPendingValidators(),
 		s.initValidatorSets(),
+		s.initPendingValidatorSet(),
 		s.caminoState.Load(s),
 	)
 	return errs.Err
@@ -1116,7 +1225,10 @@ func (s *state) loadMetadata() error {
 	}
 	s.persistedLastAccepted = lastAccepted
 	s.lastAccepted = lastAccepted
-	return nil
+	if err := s.loadPrunedHeight(); err != nil {
+		return err
+	}
+	return s.loadArchiveHeight()
 }
 
 func (s *state) loadCurrentValidators() error {
@@ -1386,9 +1498,10 @@ func (s *state) initValidatorSets() error {
 func (s *state) write(updateValidators bool, height uint64) error {
 	errs := wrappers.Errs{}
 	errs.Add(
+		s.writeArchive(height), // Must be called before the modifications are written
 		s.writeBlocks(),
 		s.writeCurrentStakers(updateValidators, height),
-		s.writePendingStakers(),
+		s.writePendingStakers(updateValidators),
 		s.WriteValidatorMetadata(s.currentValidatorList, s.currentSubnetValidatorList), // Must be called after writeCurrentStakers
 		s.writeTXs(),
 		s.writeRewardUTXOs(),
@@ -1426,6 +1539,7 @@ func (s *state) Close() error {
 		s.chainDB.Close(),
 		s.singletonDB.Close(),
 		s.blockDB.Close(),
+		s.blockIDDB.Close(),
 		s.caminoState.Close(),
 	)
 	return errs.Err
@@ -1457,6 +1571,13 @@ func (s *state) sync(genesis []byte) error {
 			err,
 		)
 	}
+
+	if err := s.indexBlocks(); err != nil {
+		return fmt.Errorf(
+			"failed to index the accepted blocks: %w",
+			err,
+		)
+	}
 	return nil
 }
 
@@ -1542,10 +1663,62 @@ func (s *state) writeBlocks() error {
 		if err := s.blockDB.Put(blkID[:], blockBytes); err != nil {
 			return fmt.Errorf("failed to write block %s: %w", blkID, err)
 		}
+
+		if stBlk.Status != choices.Accepted {
+			continue
+		}
+		heightBytes := database.PackUInt64(stBlk.Blk.Height())
+		if err := database.PutID(s.blockIDDB, heightBytes, blkID); err != nil {
+			return fmt.Errorf("failed to write block ID at height %d: %w", stBlk.Blk.Height(), err)
+		}
 	}
 	return nil
 }
 
+func (s *state) GetBlockIDAtHeight(height uint64) (ids.ID, error) {
+	return database.GetID(s.blockIDDB, database.PackUInt64(height))
+}
+
+// indexBlocks indexes the IDs of the accepted blocks by their height, if the
+// database was created before blocks were indexed.
+func (s *state) indexBlocks() error {
+	indexed, err := s.singletonDB.Has(blocksIndexedKey)
+	if err != nil || indexed {
+		return err
+	}
+
+	s.ctx.Log.Info("indexing accepted blocks by height")
+	blkID := s.lastAccepted
+	for {
+		blk, _, err := s.GetStatelessBlock(blkID)
+		if err != nil {
+			return fmt.Errorf("failed to get block %s: %w", blkID, err)
+		}
+		height := blk.Height()
+		if err := database.PutID(s.blockIDDB, database.PackUInt64(height), blkID); err != nil {
+			return err
+		}
+		if height%blockIndexCommitInterval == 0 {
+			if err := s.baseDB.Commit(); err != nil {
+				return err
+			}
+		}
+		if height == 0 {
+			break
+		}
+		blkID = blk.Parent()
+	}
+
+	if err := s.singletonDB.Put(blocksIndexedKey, nil); err != nil {
+		return err
+	}
+	if err := s.baseDB.Commit(); err != nil {
+		return err
+	}
+	s.ctx.Log.Info("finished indexing accepted blocks by height")
+	return nil
+}
+
 func (s *state) GetStatelessBlock(blockID ids.ID) (blocks.Block, choices.Status, error) {
 	if blk, ok := s.addedBlocks[blockID]; ok {
 		return blk.Blk, blk.Status, nil
@@ -1775,7 +1948,7 @@ func writeCurrentDelegatorDiff(
 	return nil
 }
 
-func (s *state) writePendingStakers() error {
+func (s *state) writePendingStakers(updateValidators bool) error {
 	for subnetID, subnetValidatorDiffs := range s.pendingStakers.validatorDiffs {
 		delete(s.pendingStakers.validatorDiffs, subnetID)
 
@@ -1795,6 +1968,12 @@ func (s *state) writePendingStakers() error {
 			if err != nil {
 				return err
 			}
+
+			if updateValidators && subnetID == constants.PrimaryNetworkID {
+				if err := s.updatePendingValidatorSet(validatorDiff); err != nil {
+					return err
+				}
+			}
 		}
 	}
 	return nil
@@ -0,0 +1,435 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/sampler"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+const (
+	// maxAttempts is the number of times all records of a summary are
+	// fetched, before the sync fails.
+	maxAttempts = 3
+
+	// maxRequestFailures is the number of consecutive failed requests, after
+	// which the sync fails.
+	maxRequestFailures = 64
+
+	requestTimeout  = 30 * time.Second
+	noPeersWaitTime = time.Second
+)
+
+var (
+	ongoingKey      = []byte("ongoing")
+	syncedHeightKey = []byte("synced height")
+	stagedPrefix    = []byte("staged")
+
+	errRequestFailed   = errors.New("request failed")
+	errTooManyFailures = errors.New("too many failed requests")
+	errRootMismatch    = errors.New("records don't match summary root")
+)
+
+// ApplyFunc replaces the state with the records of [summary], which are
+// provided by [records] in order. [ctx] is cancelled if the client is shut
+// down.
+type ApplyFunc func(ctx context.Context, summary *Summary, records database.Iterator) error
+
+type pendingRequest struct {
+	nodeID   ids.NodeID
+	response chan []byte
+}
+
+// Client fetches the records of a summary from peers and applies them once
+// they match the summary.
+//
+// The client stores its progress in [db] as follows:
+// |-- ongoing -> bytes of the summary that is synced
+// |-. staged
+// | '-- index -> record
+// '-- synced height -> height of the last applied summary
+type Client struct {
+	log      logging.Logger
+	sender   common.AppSender
+	toEngine chan<- common.Message
+	apply    ApplyFunc
+
+	db       database.Database
+	stagedDB database.Database
+
+	lock      sync.Mutex
+	peers     set.Set[ids.NodeID]
+	requestID uint32
+	pending   map[uint32]pendingRequest
+	// err is the result of the last sync
+	err    error
+	cancel context.CancelFunc
+}
+
+func NewClient(
+	log logging.Logger,
+	db database.Database,
+	sender common.AppSender,
+	toEngine chan<- common.Message,
+	apply ApplyFunc,
+) *Client {
+	return &Client{
+		log:      log,
+		sender:   sender,
+		toEngine: toEngine,
+		apply:    apply,
+		db:       db,
+		stagedDB: prefixdb.New(stagedPrefix, db),
+		pending:  make(map[uint32]pendingRequest),
+	}
+}
+
+// GetOngoingSummary returns the summary of an interrupted sync.
+//
+// Returns database.ErrNotFound if there is no interrupted sync.
+func (c *Client) GetOngoingSummary() (*Summary, error) {
+	bytes, err := c.db.Get(ongoingKey)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSummary(bytes)
+}
+
+// GetSyncedHeight returns the height of the last applied summary.
+//
+// Returns database.ErrNotFound if no summary was applied.
+func (c *Client) GetSyncedHeight() (uint64, error) {
+	return database.GetUInt64(c.db, syncedHeightKey)
+}
+
+// Error returns the error of the last sync, if it failed.
+func (c *Client) Error() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.err
+}
+
+// Start syncs to [summary] in the background. Records of an interrupted sync
+// of the same summary are reused. [common.StateSyncDone] is sent to the
+// engine once the sync finished.
+func (c *Client) Start(summary *Summary) error {
+	ongoing, err := c.GetOngoingSummary()
+	switch {
+	case err == database.ErrNotFound:
+	case err != nil:
+		return err
+	case ongoing.ID() != summary.ID():
+		if err := database.Clear(c.stagedDB, c.stagedDB); err != nil {
+			return err
+		}
+	}
+	if err := c.db.Put(ongoingKey, summary.Bytes()); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c.lock.Lock()
+	c.err = nil
+	c.cancel = cancel
+	c.lock.Unlock()
+
+	go c.run(ctx, summary)
+	return nil
+}
+
+// Shutdown stops the ongoing sync. The sync is continued by the next call to
+// [Start] with the same summary.
+func (c *Client) Shutdown() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.cancel != nil {
+		c.cancel()
+	}
+}
+
+func (c *Client) Connected(nodeID ids.NodeID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.peers.Add(nodeID)
+}
+
+func (c *Client) Disconnected(nodeID ids.NodeID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.peers.Remove(nodeID)
+}
+
+// HandleResponse delivers the response to the request [requestID].
+func (c *Client) HandleResponse(nodeID ids.NodeID, requestID uint32, responseBytes []byte) {
+	c.deliver(nodeID, requestID, responseBytes)
+}
+
+// HandleRequestFailed marks the request [requestID] as failed.
+func (c *Client) HandleRequestFailed(nodeID ids.NodeID, requestID uint32) {
+	c.deliver(nodeID, requestID, nil)
+}
+
+func (c *Client) deliver(nodeID ids.NodeID, requestID uint32, responseBytes []byte) {
+	c.lock.Lock()
+	request, ok := c.pending[requestID]
+	if ok && request.nodeID == nodeID {
+		delete(c.pending, requestID)
+	}
+	c.lock.Unlock()
+
+	if !ok || request.nodeID != nodeID {
+		c.log.Debug("dropping unexpected state sync response",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+		)
+		return
+	}
+	request.response <- responseBytes
+}
+
+func (c *Client) run(ctx context.Context, summary *Summary) {
+	err := c.sync(ctx, summary)
+	if ctx.Err() != nil {
+		// The client was shut down.
+		return
+	}
+	if err != nil {
+		c.log.Error("state sync failed",
+			zap.Stringer("summaryID", summary.ID()),
+			zap.Uint64("height", summary.Height()),
+			zap.Error(err),
+		)
+	} else {
+		c.log.Info("state sync finished",
+			zap.Stringer("summaryID", summary.ID()),
+			zap.Uint64("height", summary.Height()),
+		)
+	}
+
+	c.lock.Lock()
+	c.err = err
+	c.lock.Unlock()
+
+	select {
+	case c.toEngine <- common.StateSyncDone:
+	case <-ctx.Done():
+	}
+}
+
+func (c *Client) sync(ctx context.Context, summary *Summary) error {
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := c.fetch(ctx, summary); err != nil {
+			return err
+		}
+
+		hasher := NewRootHasher()
+		it := c.stagedDB.NewIterator()
+		for it.Next() {
+			hasher.Add(it.Value())
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return err
+		}
+
+		if hasher.Root() == summary.Root && hasher.NumRecords() == summary.NumRecords {
+			break
+		}
+
+		c.log.Warn("fetched records don't match summary",
+			zap.Stringer("summaryID", summary.ID()),
+			zap.Int("attempt", attempt),
+		)
+		if err := database.Clear(c.stagedDB, c.stagedDB); err != nil {
+			return err
+		}
+		if attempt == maxAttempts {
+			return errRootMismatch
+		}
+	}
+
+	it := c.stagedDB.NewIterator()
+	err := c.apply(ctx, summary, it)
+	it.Release()
+	if err != nil {
+		return fmt.Errorf("failed to apply records: %w", err)
+	}
+
+	if err := database.PutUInt64(c.db, syncedHeightKey, summary.Height()); err != nil {
+		return err
+	}
+	if err := c.db.Delete(ongoingKey); err != nil {
+		return err
+	}
+	return database.Clear(c.stagedDB, c.stagedDB)
+}
+
+// fetch stages the records of [summary], that aren't staged yet.
+func (c *Client) fetch(ctx context.Context, summary *Summary) error {
+	next, err := c.numStaged()
+	if err != nil {
+		return err
+	}
+
+	var (
+		failedPeers set.Set[ids.NodeID]
+		failures    int
+	)
+	for next < summary.NumRecords {
+		if failures >= maxRequestFailures {
+			return errTooManyFailures
+		}
+
+		nodeID, ok := c.samplePeer(failedPeers)
+		if !ok {
+			// Give all peers another chance once every peer failed.
+			failedPeers.Clear()
+			select {
+			case <-time.After(noPeersWaitTime):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		records, err := c.request(ctx, nodeID, &Request{
+			Height:     summary.Height(),
+			Start:      next,
+			MaxRecords: maxRecordsPerResponse,
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil || len(records) == 0 || uint64(len(records)) > summary.NumRecords-next {
+			c.log.Debug("state sync request failed",
+				zap.Stringer("nodeID", nodeID),
+				zap.Int("numRecords", len(records)),
+				zap.Error(err),
+			)
+			failedPeers.Add(nodeID)
+			failures++
+			continue
+		}
+		failures = 0
+
+		batch := c.stagedDB.NewBatch()
+		for _, record := range records {
+			if err := batch.Put(database.PackUInt64(next), record); err != nil {
+				return err
+			}
+			next++
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) request(ctx context.Context, nodeID ids.NodeID, request *Request) ([][]byte, error) {
+	requestBytes, err := marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make(chan []byte, 1)
+
+	c.lock.Lock()
+	requestID := c.requestID
+	c.requestID++
+	c.pending[requestID] = pendingRequest{
+		nodeID:   nodeID,
+		response: response,
+	}
+	c.lock.Unlock()
+
+	nodeIDs := set.NewSet[ids.NodeID](1)
+	nodeIDs.Add(nodeID)
+	if err := c.sender.SendAppRequest(ctx, nodeIDs, requestID, requestBytes); err != nil {
+		c.removePending(requestID)
+		return nil, err
+	}
+
+	var responseBytes []byte
+	select {
+	case responseBytes = <-response:
+	case <-time.After(requestTimeout):
+		c.removePending(requestID)
+		return nil, context.DeadlineExceeded
+	case <-ctx.Done():
+		c.removePending(requestID)
+		return nil, ctx.Err()
+	}
+	if responseBytes == nil {
+		return nil, errRequestFailed
+	}
+
+	resp := Response{}
+	if err := unmarshal(responseBytes, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Records, nil
+}
+
+func (c *Client) removePending(requestID uint32) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.pending, requestID)
+}
+
+func (c *Client) samplePeer(skip set.Set[ids.NodeID]) (ids.NodeID, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	candidates := make([]ids.NodeID, 0, c.peers.Len())
+	for nodeID := range c.peers {
+		if !skip.Contains(nodeID) {
+			candidates = append(candidates, nodeID)
+		}
+	}
+	if len(candidates) == 0 {
+		return ids.EmptyNodeID, false
+	}
+
+	s := sampler.NewUniform()
+	if err := s.Initialize(uint64(len(candidates))); err != nil {
+		return ids.EmptyNodeID, false
+	}
+	index, err := s.Next()
+	if err != nil {
+		return ids.EmptyNodeID, false
+	}
+	return candidates[index], true
+}
+
+func (c *Client) numStaged() (uint64, error) {
+	it := c.stagedDB.NewIterator()
+	defer it.Release()
+
+	var count uint64
+	for it.Next() {
+		count++
+	}
+	return count, it.Error()
+}
@@ -0,0 +1,28 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"errors"
+	"math"
+
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+)
+
+const codecVersion = 0
+
+var (
+	c codec.Manager
+
+	errWrongCodecVersion = errors.New("wrong codec version")
+)
+
+func init() {
+	lc := linearcodec.NewCustomMaxLength(math.MaxUint32)
+	c = codec.NewManager(math.MaxInt32)
+	if err := c.RegisterCodec(codecVersion, lc); err != nil {
+		panic(err)
+	}
+}
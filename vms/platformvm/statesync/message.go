@@ -0,0 +1,49 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+)
+
+const (
+	// maxRecordsPerResponse is the maximum number of records, that are sent
+	// in a single response.
+	maxRecordsPerResponse = 4096
+
+	// maxResponseSize is the soft limit of the size of the records in a
+	// single response. A single record that exceeds it is still sent.
+	maxResponseSize = units.MiB
+)
+
+// Request asks for up to [MaxRecords] records of the summary at [Height],
+// starting with the record at index [Start].
+type Request struct {
+	Height     uint64 `serialize:"true"`
+	Start      uint64 `serialize:"true"`
+	MaxRecords uint32 `serialize:"true"`
+}
+
+// Response contains the requested records. An empty response signals that the
+// summary isn't served.
+type Response struct {
+	Records [][]byte `serialize:"true"`
+}
+
+func marshal(msg interface{}) ([]byte, error) {
+	return c.Marshal(codecVersion, msg)
+}
+
+func unmarshal(bytes []byte, msg interface{}) error {
+	version, err := c.Unmarshal(bytes, msg)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal message due to: %w", err)
+	}
+	if version != codecVersion {
+		return errWrongCodecVersion
+	}
+	return nil
+}
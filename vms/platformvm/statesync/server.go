@@ -0,0 +1,334 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+)
+
+const (
+	// summariesToKeep is the number of generated summaries, that are served.
+	// Keeping more than one summary allows peers to finish a sync after a
+	// new summary was generated.
+	summariesToKeep = 2
+
+	batchSize = units.MiB
+)
+
+var (
+	summaryPrefix = []byte("summary")
+	recordPrefix  = []byte("record")
+	generatingKey = []byte("generating")
+
+	errNoSummary = errors.New("no summary")
+)
+
+// Exporter provides the records of a state.
+type Exporter interface {
+	ExportSyncRecords(f func(record []byte) error) error
+}
+
+// Server generates summaries of the accepted state and serves their records
+// to syncing peers.
+//
+// The generated records are stored in [db] as follows:
+// |-. summary
+// | '-- height -> summary bytes
+// |-. record
+// | '-- height + index -> record
+// '-- generating -> height of the summary that is generated
+type Server struct {
+	log      logging.Logger
+	sender   common.AppSender
+	interval uint64
+
+	db        database.Database
+	summaryDB database.Database
+	recordDB  database.Database
+
+	// lock protects [heights] and prevents that the records of a summary are
+	// removed while they are served. Summaries are generated without the chain
+	// lock.
+	lock sync.RWMutex
+	// heights of the served summaries in ascending order
+	heights []uint64
+}
+
+// NewServer returns a server, that generates a summary every [interval]
+// blocks. If [interval] is 0, no summaries are generated.
+func NewServer(
+	log logging.Logger,
+	db database.Database,
+	sender common.AppSender,
+	interval uint64,
+) (*Server, error) {
+	s := &Server{
+		log:       log,
+		sender:    sender,
+		interval:  interval,
+		db:        db,
+		summaryDB: prefixdb.New(summaryPrefix, db),
+		recordDB:  prefixdb.New(recordPrefix, db),
+	}
+
+	// Remove the records of a summary, whose generation was interrupted.
+	height, err := database.GetUInt64(db, generatingKey)
+	switch {
+	case err == nil:
+		if err := s.deleteRecords(height); err != nil {
+			return nil, err
+		}
+		if err := db.Delete(generatingKey); err != nil {
+			return nil, err
+		}
+	case err != database.ErrNotFound:
+		return nil, err
+	}
+
+	it := s.summaryDB.NewIterator()
+	defer it.Release()
+	for it.Next() {
+		height, err := database.ParseUInt64(it.Key())
+		if err != nil {
+			return nil, err
+		}
+		s.heights = append(s.heights, height)
+	}
+	return s, it.Error()
+}
+
+// Enabled returns true if the server generates summaries.
+func (s *Server) Enabled() bool {
+	return s.interval != 0
+}
+
+// Due returns true if a summary must be generated at [height].
+func (s *Server) Due(height uint64) bool {
+	if s.interval == 0 || height%s.interval != 0 {
+		return false
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return len(s.heights) == 0 || s.heights[len(s.heights)-1] < height
+}
+
+// Generate generates the summary of the state at [height] from the records
+// of [exporter], if a summary is due at [height]. [exporter] must provide the
+// state right after the block at [height] was accepted, so it doesn't need to
+// be the current state. Summaries must be generated one at a time in the order
+// of their heights. The generation is aborted, once [ctx] is cancelled.
+func (s *Server) Generate(ctx context.Context, height uint64, blockBytes []byte, exporter Exporter) error {
+	if !s.Due(height) {
+		return nil
+	}
+
+	if err := database.PutUInt64(s.db, generatingKey, height); err != nil {
+		return err
+	}
+
+	var (
+		hasher = NewRootHasher()
+		batch  = s.recordDB.NewBatch()
+	)
+	err := exporter.ExportSyncRecords(func(record []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := recordKey(height, hasher.NumRecords())
+		hasher.Add(record)
+		if err := batch.Put(key, record); err != nil {
+			return err
+		}
+		if batch.Size() < batchSize {
+			return nil
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	})
+	if err != nil {
+		// The records must be removed now, because the next generation
+		// overwrites the generating key. If they can't be removed, they
+		// are removed after a restart.
+		if err := s.deleteRecords(height); err == nil {
+			_ = s.db.Delete(generatingKey)
+		}
+		return fmt.Errorf("failed to export records: %w", err)
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+
+	summary, err := NewSummary(height, blockBytes, hasher.Root(), hasher.NumRecords())
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.summaryDB.Put(database.PackUInt64(height), summary.Bytes()); err != nil {
+		return err
+	}
+	if err := s.db.Delete(generatingKey); err != nil {
+		return err
+	}
+	s.heights = append(s.heights, height)
+
+	s.log.Info("generated state summary",
+		zap.Uint64("height", height),
+		zap.Stringer("summaryID", summary.ID()),
+		zap.Uint64("numRecords", summary.NumRecords),
+	)
+
+	for len(s.heights) > summariesToKeep {
+		if err := s.deleteSummary(s.heights[0]); err != nil {
+			return err
+		}
+		s.heights = s.heights[1:]
+	}
+	return nil
+}
+
+// GetLastSummary returns the most recently generated summary.
+//
+// Returns database.ErrNotFound if no summary was generated.
+func (s *Server) GetLastSummary() (*Summary, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if len(s.heights) == 0 {
+		return nil, database.ErrNotFound
+	}
+	return s.getSummary(s.heights[len(s.heights)-1])
+}
+
+// GetSummary returns the summary generated at [height].
+//
+// Returns database.ErrNotFound if no summary was generated at [height].
+func (s *Server) GetSummary(height uint64) (*Summary, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.getSummary(height)
+}
+
+// Assumes [s.lock] is held.
+func (s *Server) getSummary(height uint64) (*Summary, error) {
+	bytes, err := s.summaryDB.Get(database.PackUInt64(height))
+	if err != nil {
+		return nil, err
+	}
+	return ParseSummary(bytes)
+}
+
+// HandleRequest responds to a request of records by [nodeID].
+func (s *Server) HandleRequest(ctx context.Context, nodeID ids.NodeID, requestID uint32, requestBytes []byte) error {
+	request := Request{}
+	if err := unmarshal(requestBytes, &request); err != nil {
+		s.log.Debug("dropping state sync request",
+			zap.Stringer("nodeID", nodeID),
+			zap.Uint32("requestID", requestID),
+			zap.Error(err),
+		)
+		return nil
+	}
+
+	records, err := s.getRecords(request)
+	if err != nil && err != errNoSummary {
+		return err
+	}
+
+	responseBytes, err := marshal(&Response{Records: records})
+	if err != nil {
+		return err
+	}
+	return s.sender.SendAppResponse(ctx, nodeID, requestID, responseBytes)
+}
+
+func (s *Server) getRecords(request Request) ([][]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if has, err := s.summaryDB.Has(database.PackUInt64(request.Height)); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, errNoSummary
+	}
+
+	maxRecords := int(request.MaxRecords)
+	if maxRecords == 0 || maxRecords > maxRecordsPerResponse {
+		maxRecords = maxRecordsPerResponse
+	}
+
+	it := s.recordDB.NewIteratorWithStartAndPrefix(
+		recordKey(request.Height, request.Start),
+		database.PackUInt64(request.Height),
+	)
+	defer it.Release()
+
+	var (
+		records [][]byte
+		size    int
+	)
+	for len(records) < maxRecords && size < maxResponseSize && it.Next() {
+		record := it.Value()
+		records = append(records, record)
+		size += len(record)
+	}
+	return records, it.Error()
+}
+
+func (s *Server) deleteSummary(height uint64) error {
+	if err := s.summaryDB.Delete(database.PackUInt64(height)); err != nil {
+		return err
+	}
+	return s.deleteRecords(height)
+}
+
+func (s *Server) deleteRecords(height uint64) error {
+	it := s.recordDB.NewIteratorWithPrefix(database.PackUInt64(height))
+	defer it.Release()
+
+	batch := s.recordDB.NewBatch()
+	for it.Next() {
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+		if batch.Size() < batchSize {
+			continue
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+	}
+	errs := wrappers.Errs{}
+	errs.Add(it.Error(), batch.Write())
+	return errs.Err
+}
+
+func recordKey(height, index uint64) []byte {
+	key := make([]byte, wrappers.LongLen*2)
+	copy(key, database.PackUInt64(height))
+	copy(key[wrappers.LongLen:], database.PackUInt64(index))
+	return key
+}
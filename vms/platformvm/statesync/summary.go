@@ -0,0 +1,99 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+)
+
+// Summary describes the state of the P-chain after the block [BlockBytes] was
+// accepted. The state is transferred as [NumRecords] opaque records, which
+// hash to [Root].
+type Summary struct {
+	BlockHeight uint64 `serialize:"true"`
+	BlockBytes  []byte `serialize:"true"`
+	Root        ids.ID `serialize:"true"`
+	NumRecords  uint64 `serialize:"true"`
+
+	id    ids.ID
+	bytes []byte
+}
+
+func NewSummary(height uint64, blockBytes []byte, root ids.ID, numRecords uint64) (*Summary, error) {
+	summary := &Summary{
+		BlockHeight: height,
+		BlockBytes:  blockBytes,
+		Root:        root,
+		NumRecords:  numRecords,
+	}
+	bytes, err := c.Marshal(codecVersion, summary)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal summary due to: %w", err)
+	}
+	summary.id = hashing.ComputeHash256Array(bytes)
+	summary.bytes = bytes
+	return summary, nil
+}
+
+func ParseSummary(bytes []byte) (*Summary, error) {
+	summary := &Summary{
+		id:    hashing.ComputeHash256Array(bytes),
+		bytes: bytes,
+	}
+	version, err := c.Unmarshal(bytes, summary)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal summary due to: %w", err)
+	}
+	if version != codecVersion {
+		return nil, errWrongCodecVersion
+	}
+	return summary, nil
+}
+
+func (s *Summary) ID() ids.ID {
+	return s.id
+}
+
+func (s *Summary) Height() uint64 {
+	return s.BlockHeight
+}
+
+func (s *Summary) Bytes() []byte {
+	return s.bytes
+}
+
+// RootHasher calculates the root of a sequence of records.
+type RootHasher struct {
+	hash       hash.Hash
+	numRecords uint64
+}
+
+func NewRootHasher() *RootHasher {
+	return &RootHasher{hash: sha256.New()}
+}
+
+// Add appends [record] to the hashed sequence.
+func (r *RootHasher) Add(record []byte) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(record)))
+	_, _ = r.hash.Write(length[:])
+	_, _ = r.hash.Write(record)
+	r.numRecords++
+}
+
+func (r *RootHasher) NumRecords() uint64 {
+	return r.numRecords
+}
+
+func (r *RootHasher) Root() ids.ID {
+	var root ids.ID
+	copy(root[:], r.hash.Sum(nil))
+	return root
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestSummaryParse(t *testing.T) {
+	require := require.New(t)
+
+	summary, err := NewSummary(4096, []byte("block"), ids.GenerateTestID(), 10)
+	require.NoError(err)
+
+	parsed, err := ParseSummary(summary.Bytes())
+	require.NoError(err)
+	require.Equal(summary.ID(), parsed.ID())
+	require.Equal(summary.Height(), parsed.Height())
+	require.Equal(summary.BlockBytes, parsed.BlockBytes)
+	require.Equal(summary.Root, parsed.Root)
+	require.Equal(summary.NumRecords, parsed.NumRecords)
+
+	_, err = ParseSummary([]byte{1, 2, 3})
+	require.Error(err)
+}
+
+func TestRootHasher(t *testing.T) {
+	require := require.New(t)
+
+	hash := func(records ...[]byte) ids.ID {
+		hasher := NewRootHasher()
+		for _, record := range records {
+			hasher.Add(record)
+		}
+		return hasher.Root()
+	}
+
+	require.Equal(hash([]byte("a"), []byte("b")), hash([]byte("a"), []byte("b")))
+	require.NotEqual(hash([]byte("a"), []byte("b")), hash([]byte("b"), []byte("a")))
+	// Records are length-prefixed, so their boundaries are part of the root.
+	require.NotEqual(hash([]byte("ab"), []byte("c")), hash([]byte("a"), []byte("bc")))
+}
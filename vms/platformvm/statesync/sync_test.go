@@ -0,0 +1,232 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package statesync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/set"
+)
+
+type testExporter [][]byte
+
+func (e testExporter) ExportSyncRecords(f func([]byte) error) error {
+	for _, record := range e {
+		if err := f(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestRecords(n int) testExporter {
+	records := make(testExporter, n)
+	for i := range records {
+		records[i] = []byte(fmt.Sprintf("record %d", i))
+	}
+	return records
+}
+
+// newTestPair returns a server and a client, which requests records from the
+// server.
+func newTestPair(
+	t *testing.T,
+	apply ApplyFunc,
+) (*Server, *Client, chan common.Message) {
+	serverNodeID := ids.GenerateTestNodeID()
+	clientNodeID := ids.GenerateTestNodeID()
+
+	var client *Client
+	serverSender := &common.SenderTest{T: t}
+	serverSender.SendAppResponseF = func(_ context.Context, nodeID ids.NodeID, requestID uint32, response []byte) error {
+		require.Equal(t, clientNodeID, nodeID)
+		client.HandleResponse(serverNodeID, requestID, response)
+		return nil
+	}
+	server, err := NewServer(logging.NoLog{}, memdb.New(), serverSender, 10)
+	require.NoError(t, err)
+
+	clientSender := &common.SenderTest{T: t}
+	clientSender.SendAppRequestF = func(ctx context.Context, nodeIDs set.Set[ids.NodeID], requestID uint32, request []byte) error {
+		require.Equal(t, set.Set[ids.NodeID]{serverNodeID: struct{}{}}, nodeIDs)
+		return server.HandleRequest(ctx, clientNodeID, requestID, request)
+	}
+	toEngine := make(chan common.Message, 1)
+	client = NewClient(logging.NoLog{}, memdb.New(), clientSender, toEngine, apply)
+	client.Connected(serverNodeID)
+	return server, client, toEngine
+}
+
+func TestServerGenerate(t *testing.T) {
+	require := require.New(t)
+
+	records := newTestRecords(3)
+	db := memdb.New()
+	server, err := NewServer(logging.NoLog{}, db, &common.SenderTest{T: t}, 10)
+	require.NoError(err)
+
+	_, err = server.GetLastSummary()
+	require.ErrorIs(err, database.ErrNotFound)
+
+	// No summary is due at this height.
+	require.True(server.Enabled())
+	require.False(server.Due(5))
+	require.NoError(server.Generate(context.Background(), 5, []byte("block 5"), records))
+	_, err = server.GetLastSummary()
+	require.ErrorIs(err, database.ErrNotFound)
+
+	for height := uint64(10); height <= 30; height += 10 {
+		require.NoError(server.Generate(context.Background(), height, []byte("block"), records))
+	}
+
+	require.False(server.Due(30))
+	require.True(server.Due(40))
+
+	summary, err := server.GetLastSummary()
+	require.NoError(err)
+	require.Equal(uint64(30), summary.Height())
+	require.Equal(uint64(len(records)), summary.NumRecords)
+
+	// Only the latest summaries are kept.
+	_, err = server.GetSummary(10)
+	require.ErrorIs(err, database.ErrNotFound)
+	_, err = server.GetSummary(20)
+	require.NoError(err)
+	recordsAt10, err := server.getRecords(Request{Height: 10})
+	require.ErrorIs(err, errNoSummary)
+	require.Empty(recordsAt10)
+
+	// The summaries are kept after a restart.
+	server, err = NewServer(logging.NoLog{}, db, &common.SenderTest{T: t}, 10)
+	require.NoError(err)
+	reloaded, err := server.GetLastSummary()
+	require.NoError(err)
+	require.Equal(summary.ID(), reloaded.ID())
+}
+
+func TestServerRemovesInterruptedGeneration(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	server, err := NewServer(logging.NoLog{}, db, &common.SenderTest{T: t}, 10)
+	require.NoError(err)
+
+	// Simulate a crash during the generation at height 10.
+	require.NoError(database.PutUInt64(db, generatingKey, 10))
+	require.NoError(server.recordDB.Put(recordKey(10, 0), []byte("record")))
+
+	server, err = NewServer(logging.NoLog{}, db, &common.SenderTest{T: t}, 10)
+	require.NoError(err)
+	has, err := server.recordDB.Has(recordKey(10, 0))
+	require.NoError(err)
+	require.False(has)
+	has, err = db.Has(generatingKey)
+	require.NoError(err)
+	require.False(has)
+}
+
+func TestServerAbortsGeneration(t *testing.T) {
+	require := require.New(t)
+
+	db := memdb.New()
+	server, err := NewServer(logging.NoLog{}, db, &common.SenderTest{T: t}, 10)
+	require.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = server.Generate(ctx, 10, []byte("block"), newTestRecords(3))
+	require.ErrorIs(err, context.Canceled)
+
+	// The records of the aborted generation are removed and the summary is
+	// still due.
+	require.True(server.Due(10))
+	_, err = server.GetLastSummary()
+	require.ErrorIs(err, database.ErrNotFound)
+	has, err := db.Has(generatingKey)
+	require.NoError(err)
+	require.False(has)
+	it := server.recordDB.NewIterator()
+	defer it.Release()
+	require.False(it.Next())
+}
+
+func TestSync(t *testing.T) {
+	require := require.New(t)
+
+	records := newTestRecords(2*maxRecordsPerResponse + 1)
+	var applied [][]byte
+	server, client, toEngine := newTestPair(t, func(_ context.Context, _ *Summary, it database.Iterator) error {
+		for it.Next() {
+			applied = append(applied, it.Value())
+		}
+		return it.Error()
+	})
+
+	require.NoError(server.Generate(context.Background(), 10, []byte("block"), records))
+	summary, err := server.GetLastSummary()
+	require.NoError(err)
+
+	require.NoError(client.Start(summary))
+	require.Equal(common.StateSyncDone, <-toEngine)
+	require.NoError(client.Error())
+	require.Equal([][]byte(records), applied)
+
+	syncedHeight, err := client.GetSyncedHeight()
+	require.NoError(err)
+	require.Equal(summary.Height(), syncedHeight)
+	_, err = client.GetOngoingSummary()
+	require.ErrorIs(err, database.ErrNotFound)
+	numStaged, err := client.numStaged()
+	require.NoError(err)
+	require.Zero(numStaged)
+}
+
+func TestSyncRootMismatch(t *testing.T) {
+	require := require.New(t)
+
+	// The records must not be applied, so the sync would succeed if they were.
+	server, client, toEngine := newTestPair(t, func(context.Context, *Summary, database.Iterator) error {
+		return nil
+	})
+
+	require.NoError(server.Generate(context.Background(), 10, []byte("block"), newTestRecords(3)))
+	summary, err := server.GetLastSummary()
+	require.NoError(err)
+
+	// The records served for this summary don't hash to its root.
+	forged, err := NewSummary(summary.Height(), summary.BlockBytes, ids.GenerateTestID(), summary.NumRecords)
+	require.NoError(err)
+
+	require.NoError(client.Start(forged))
+	require.Equal(common.StateSyncDone, <-toEngine)
+	require.ErrorIs(client.Error(), errRootMismatch)
+
+	// The sync can be resumed after a restart.
+	ongoing, err := client.GetOngoingSummary()
+	require.NoError(err)
+	require.Equal(forged.ID(), ongoing.ID())
+}
+
+func TestServerDisabled(t *testing.T) {
+	require := require.New(t)
+
+	server, err := NewServer(logging.NoLog{}, memdb.New(), &common.SenderTest{T: t}, 0)
+	require.NoError(err)
+	require.False(server.Enabled())
+	require.False(server.Due(0))
+	require.False(server.Due(4096))
+
+	require.NoError(server.Generate(context.Background(), 4096, []byte("block"), newTestRecords(3)))
+	_, err = server.GetLastSummary()
+	require.ErrorIs(err, database.ErrNotFound)
+}
@@ -17,20 +17,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
-	"github.com/gorilla/rpc/v2"
-
 	"github.com/prometheus/client_golang/prometheus"
 
 	"go.uber.org/zap"
 
+	"github.com/ava-labs/avalanchego/api/graphql"
+	"github.com/ava-labs/avalanchego/api/openrpc"
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/pubsub"
 	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
 	"github.com/ava-labs/avalanchego/snow/engine/common"
@@ -47,6 +50,7 @@ import (
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 	"github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/components/pruning"
 	"github.com/ava-labs/avalanchego/vms/platformvm/api"
 	"github.com/ava-labs/avalanchego/vms/platformvm/blocks"
 	"github.com/ava-labs/avalanchego/vms/platformvm/config"
@@ -54,6 +58,7 @@ import (
 	"github.com/ava-labs/avalanchego/vms/platformvm/metrics"
 	"github.com/ava-labs/avalanchego/vms/platformvm/reward"
 	"github.com/ava-labs/avalanchego/vms/platformvm/state"
+	"github.com/ava-labs/avalanchego/vms/platformvm/statesync"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs"
 	"github.com/ava-labs/avalanchego/vms/platformvm/txs/mempool"
 	"github.com/ava-labs/avalanchego/vms/platformvm/utxo"
@@ -115,6 +120,38 @@ type VM struct {
 
 	txBuilder txbuilder.CaminoBuilder
 	manager   blockexecutor.Manager
+
+	executionConfig config.ExecutionConfig
+	toEngine        chan<- common.Message
+	appSender       common.AppSender
+
+	stateSyncServer *statesync.Server
+	stateSyncClient *statesync.Client
+	// Height of the last state summary the VM synced to. The validator sets
+	// of lower heights can't be calculated.
+	stateSyncHeight uint64
+	// State summaries that are due, in the order of their heights. Summaries
+	// are generated in the background, so that accepting a block doesn't wait
+	// for the state export.
+	summaryLock      sync.Mutex
+	pendingSummaries []pendingSummary
+	// Signals that summaries are pending
+	summaryReady chan struct{}
+	// Stops the background summary generation. Nil if no summaries are
+	// generated.
+	stopSummaries context.CancelFunc
+
+	// Prunes old blocks in the background. Nil if pruning wasn't started.
+	pruner *pruning.Pruner
+
+	// Publishes accepted txs to websocket subscribers
+	pubsub *pubsub.Server
+	// Proposal ID + voter address --> nil, for the voters of unfinished
+	// proposals
+	proposalVotersDB database.Database
+	// Txs of the block, that is being accepted, which are published once the
+	// state of the block was committed
+	acceptedTxFilterers []*txFilterer
 }
 
 // Initialize this blockchain.
@@ -125,7 +162,7 @@ func (vm *VM) Initialize(
 	dbManager manager.Manager,
 	genesisBytes []byte,
 	_ []byte,
-	_ []byte,
+	configBytes []byte,
 	toEngine chan<- common.Message,
 	_ []*common.Fx,
 	appSender common.AppSender,
@@ -146,6 +183,15 @@ func (vm *VM) Initialize(
 
 	vm.ctx = chainCtx
 	vm.dbManager = dbManager
+	vm.toEngine = toEngine
+	vm.appSender = appSender
+	vm.pubsub = pubsub.New(vm.ctx.Log)
+	vm.proposalVotersDB = prefixdb.New(proposalVotersPrefix, vm.dbManager.Current().Database)
+
+	vm.executionConfig, err = config.GetExecutionConfig(configBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse execution config: %w", err)
+	}
 
 	vm.codecRegistry = linearcodec.NewCaminoDefault()
 	vm.fx = &secp256k1fx.CaminoFx{}
@@ -177,6 +223,19 @@ func (vm *VM) Initialize(
 		return err
 	}
 
+	if vm.executionConfig.ArchiveDrop {
+		if err := vm.state.DropArchive(); err != nil {
+			return fmt.Errorf("failed to drop archive: %w", err)
+		}
+	}
+	if err := vm.state.SetArchiveEnabled(vm.executionConfig.ArchiveEnabled); err != nil {
+		return fmt.Errorf("failed to initialize archive: %w", err)
+	}
+
+	if err := vm.registerCaminoHealthChecks(); err != nil {
+		return fmt.Errorf("failed to register camino health checks: %w", err)
+	}
+
 	vm.atomicUtxosManager = avax.NewAtomicUTXOManager(chainCtx.SharedMemory, txs.Codec)
 
 	camCfg, _ := vm.state.CaminoConfig()
@@ -224,6 +283,8 @@ func (vm *VM) Initialize(
 		vm.state,
 		txExecutorBackend,
 		vm.recentlyAccepted,
+		vm.onBlockAccepting,
+		vm.onBlockCommitted,
 	)
 	vm.Builder = blockbuilder.CaminoNew(
 		mempool,
@@ -234,6 +295,10 @@ func (vm *VM) Initialize(
 		appSender,
 	)
 
+	if err := vm.initStateSync(); err != nil {
+		return err
+	}
+
 	// Create all of the chains that the database says exist
 	if err := vm.initBlockchains(); err != nil {
 		return fmt.Errorf(
@@ -330,6 +395,8 @@ func (vm *VM) onNormalOperationsStarted() error {
 		return err
 	}
 
+	vm.startPruning()
+
 	// Start the block builder
 	vm.Builder.ResetBlockTimer()
 	return nil
@@ -337,7 +404,13 @@ func (vm *VM) onNormalOperationsStarted() error {
 
 func (vm *VM) SetState(_ context.Context, state snow.State) error {
 	switch state {
+	case snow.StateSyncing:
+		return nil
 	case snow.Bootstrapping:
+		// The node must not continue with a partially synced state.
+		if err := vm.stateSyncClient.Error(); err != nil {
+			return fmt.Errorf("state sync failed: %w", err)
+		}
 		return vm.onBootstrapStarted()
 	case snow.NormalOp:
 		return vm.onNormalOperationsStarted()
@@ -352,6 +425,13 @@ func (vm *VM) Shutdown(context.Context) error {
 		return nil
 	}
 
+	vm.stateSyncClient.Shutdown()
+	if vm.stopSummaries != nil {
+		vm.stopSummaries()
+	}
+	if vm.pruner != nil {
+		vm.pruner.Stop()
+	}
 	vm.Builder.Shutdown()
 
 	if vm.bootstrapped.Get() {
@@ -438,38 +518,57 @@ func (*VM) Version(context.Context) (string, error) {
 // * keys are API endpoint extensions
 // * values are API handlers
 func (vm *VM) CreateHandlers(context.Context) (map[string]*common.HTTPHandler, error) {
-	server := rpc.NewServer()
+	server := openrpc.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 	server.RegisterInterceptFunc(vm.metrics.InterceptRequest)
 	server.RegisterAfterFunc(vm.metrics.AfterRequest)
-	if err := server.RegisterService(
-		&CaminoService{
-			Service: Service{
-				vm:          vm,
-				addrManager: avax.NewAddressManager(vm.ctx),
-				stakerAttributesCache: &cache.LRU[ids.ID, *stakerAttributes]{
-					Size: stakerAttributesCacheSize,
-				},
+	service := &CaminoService{
+		Service: Service{
+			vm:          vm,
+			addrManager: avax.NewAddressManager(vm.ctx),
+			stakerAttributesCache: &cache.LRU[ids.ID, *stakerAttributes]{
+				Size: stakerAttributesCacheSize,
 			},
 		},
-		"platform",
-	); err != nil {
+	}
+	if err := server.RegisterService(service, "platform"); err != nil {
 		return nil, err
 	}
 
-	return map[string]*common.HTTPHandler{
+	handlers := map[string]*common.HTTPHandler{
 		"": {
 			Handler: server,
 		},
-	}, nil
+		"/events": {
+			LockOptions: common.NoLock,
+			Handler:     vm.pubsub,
+		},
+		// The service fills the caches of the state, so it needs the write lock
+		// like the JSON-RPC service.
+		common.GRPCExtension: {
+			Handler: newGRPCServer(service),
+		},
+	}
+	if vm.executionConfig.GraphQLEnabled {
+		schema, err := newGraphQLSchema(service)
+		if err != nil {
+			return nil, err
+		}
+		// The resolvers fill the caches of the state, so they need the write
+		// lock like the JSON-RPC service.
+		handlers["/graphql"] = &common.HTTPHandler{
+			Handler: graphql.NewHandler(schema),
+		}
+	}
+	return handlers, nil
 }
 
 // CreateStaticHandlers returns a map where:
 // * keys are API endpoint extensions
 // * values are API handlers
 func (*VM) CreateStaticHandlers(context.Context) (map[string]*common.HTTPHandler, error) {
-	server := rpc.NewServer()
+	server := openrpc.NewServer()
 	server.RegisterCodec(json.NewCodec(), "application/json")
 	server.RegisterCodec(json.NewCodec(), "application/json;charset=UTF-8")
 	if err := server.RegisterService(&api.StaticService{}, "platform"); err != nil {
@@ -485,6 +584,7 @@ func (*VM) CreateStaticHandlers(context.Context) (map[string]*common.HTTPHandler
 }
 
 func (vm *VM) Connected(_ context.Context, nodeID ids.NodeID, _ *version.Application) error {
+	vm.stateSyncClient.Connected(nodeID)
 	return vm.uptimeManager.Connect(nodeID, constants.PrimaryNetworkID)
 }
 
@@ -493,6 +593,7 @@ func (vm *VM) ConnectedSubnet(_ context.Context, nodeID ids.NodeID, subnetID ids
 }
 
 func (vm *VM) Disconnected(_ context.Context, nodeID ids.NodeID) error {
+	vm.stateSyncClient.Disconnected(nodeID)
 	if err := vm.uptimeManager.Disconnect(nodeID); err != nil {
 		return err
 	}
@@ -523,6 +624,9 @@ func (vm *VM) GetValidatorSet(ctx context.Context, height uint64, subnetID ids.I
 	if lastAcceptedHeight < height {
 		return nil, database.ErrNotFound
 	}
+	if height < vm.stateSyncHeight {
+		return nil, fmt.Errorf("%w: height %d, synced height %d", errValidatorSetNotSynced, height, vm.stateSyncHeight)
+	}
 
 	// get the start time to track metrics
 	startTime := vm.Clock().Time()
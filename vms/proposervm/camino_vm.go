@@ -0,0 +1,12 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package proposervm
+
+import "github.com/ava-labs/avalanchego/snow/engine/common"
+
+var _ common.WrappedVM = (*VM)(nil)
+
+func (vm *VM) Unwrap() common.VM {
+	return vm.ChainVM
+}
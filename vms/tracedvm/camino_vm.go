@@ -0,0 +1,19 @@
+// Copyright (C) 2022-2024, Chain4Travel AG. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tracedvm
+
+import "github.com/ava-labs/avalanchego/snow/engine/common"
+
+var (
+	_ common.WrappedVM = (*blockVM)(nil)
+	_ common.WrappedVM = (*vertexVM)(nil)
+)
+
+func (vm *blockVM) Unwrap() common.VM {
+	return vm.ChainVM
+}
+
+func (vm *vertexVM) Unwrap() common.VM {
+	return vm.LinearizableVMWithEngine
+}